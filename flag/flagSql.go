@@ -21,52 +21,79 @@ const (
 // SQL 表结构迁移，如果表不存在，它会创建新表；如果表已经存在，它会根据结构更新表
 // 迁移完成后自动初始化内置角色（幂等）
 func SQL() error {
+	// users.openid 改为唯一索引前，先把未绑定的空串统一为 NULL，避免空值互相冲突
+	if err := normalizeUserOpenids(global.DB.Session(&gorm.Session{NewDB: true})); err != nil {
+		return err
+	}
+
 	db := global.DB.Set("gorm:table_options", "ENGINE=InnoDB")
 
 	if err := db.AutoMigrate(
-		&entity.AIConversation{},          // AI 会话表
-		&entity.AIMessage{},               // AI 消息表
-		&entity.AIInterrupt{},             // AI 中断表
-		&entity.AIMemoryFact{},            // AI 结构化事实记忆表
-		&entity.AIMemoryDocument{},        // AI 长期记忆文档表
-		&entity.AIMemoryDocumentChunk{},   // AI 长期记忆文档切块表
-		&entity.AIConversationSummary{},   // AI 会话压缩摘要表
-		&entity.User{},                    // 用户表
-		&entity.Org{},                     // 组织表
-		&entity.OrgMember{},               // 组织成员状态表 - 身份上的
-		&entity.LeetcodeUserDetail{},      // 力扣用户详情表
-		&entity.LuoguUserDetail{},         // 洛谷用户详情表
-		&entity.LanqiaoUserDetail{},       // 蓝桥用户详情表
-		&entity.LeetcodeQuestionBank{},    // 力扣题库题目表
-		&entity.LuoguQuestionBank{},       // 洛谷题库题目表
-		&entity.LanqiaoQuestionBank{},     // 蓝桥题库题目表
-		&entity.LeetcodeUserQuestion{},    // 力扣用户以做题目表
-		&entity.LuoguUserQuestion{},       // 洛谷用户以做题目表
-		&entity.LanqiaoUserQuestion{},     // 蓝桥用户已通过题目表
-		&entity.OJUserDailyStat{},         // OJ 刷题曲线日聚合读模型
-		&entity.OJTask{},                  // OJ 任务版本表
-		&entity.OJTaskOrg{},               // OJ 任务组织关联表
-		&entity.OJTaskItem{},              // OJ 任务题单表
-		&entity.OJQuestionIntake{},        // OJ 任务待解析题目表
-		&entity.OJTaskExecution{},         // OJ 任务执行表
-		&entity.OJTaskExecutionUser{},     // OJ 任务执行用户快照表
-		&entity.OJTaskExecutionUserOrg{},  // OJ 任务执行用户组织快照表
-		&entity.OJTaskExecutionUserItem{}, // OJ 任务执行用户题目快照表
-		&entity.Login{},                   // 登录日志表
-		&entity.UserToken{},               // 用户Token记录表
-		&entity.TokenBlacklist{},          // Token黑名单表
-		&entity.JwtBlacklist{},            // JWT黑名单表（兼容现有代码）
-		&entity.Role{},                    // 角色表
-		&entity.Capability{},              // 业务能力表
-		&entity.Menu{},                    // 菜单表
-		&entity.UserOrgRole{},             // 用户组织角色关联表 - 权限上的（与 OrgMember 配合）
-		&entity.RoleCapability{},          // 角色与业务能力关联表
-		&entity.RoleAPI{},                 // 角色API直绑关联表
-		&entity.API{},                     // api表
-		&entity.OutboxEvent{},             // Outbox事件表
-		&entity.Image{},                   // 图片表
-		&entity.ObservabilityMetric{},     // 指标聚合表
-		&entity.ObservabilityTraceSpan{},  // 全链路追踪明细表
+		&entity.AIConversation{},             // AI 会话表
+		&entity.AIMessage{},                  // AI 消息表
+		&entity.AIInterrupt{},                // AI 中断表
+		&entity.AIConversationShare{},        // AI 会话只读分享表
+		&entity.AIMemoryFact{},               // AI 结构化事实记忆表
+		&entity.AIMemoryDocument{},           // AI 长期记忆文档表
+		&entity.AIMemoryDocumentChunk{},      // AI 长期记忆文档切块表
+		&entity.AIMemoryDocumentVersion{},    // AI 知识库文档版本表
+		&entity.AIMemoryDocumentRecallStat{}, // AI 长期记忆文档召回统计表
+		&entity.AIMemoryChunkVector{},        // AI 记忆本地向量表（未部署 Qdrant 时使用）
+		&entity.AIMemoryChunkTerm{},          // AI 记忆关键词倒排表（BM25 召回）
+		&entity.AIConversationSummary{},      // AI 会话压缩摘要表
+		&entity.User{},                       // 用户表
+		&entity.Org{},                        // 组织表
+		&entity.OrgMember{},                  // 组织成员状态表 - 身份上的
+		&entity.OrgInvitation{},              // 组织邀请表
+		&entity.OrgJoinRequest{},             // 组织加入申请表
+		&entity.LeetcodeUserDetail{},         // 力扣用户详情表
+		&entity.LuoguUserDetail{},            // 洛谷用户详情表
+		&entity.LanqiaoUserDetail{},          // 蓝桥用户详情表
+		&entity.LeetcodeQuestionBank{},       // 力扣题库题目表
+		&entity.LuoguQuestionBank{},          // 洛谷题库题目表
+		&entity.LanqiaoQuestionBank{},        // 蓝桥题库题目表
+		&entity.LeetcodeUserQuestion{},       // 力扣用户以做题目表
+		&entity.LuoguUserQuestion{},          // 洛谷用户以做题目表
+		&entity.LanqiaoUserQuestion{},        // 蓝桥用户已通过题目表
+		&entity.OJProviderUserDetail{},       // 可插拔 OJ 平台用户详情表（Codeforces/AtCoder）
+		&entity.OJProviderQuestionBank{},     // 可插拔 OJ 平台题库题目表
+		&entity.OJProviderUserQuestion{},     // 可插拔 OJ 平台用户已通过题目表
+		&entity.OJUserDailyStat{},            // OJ 刷题曲线日聚合读模型
+		&entity.OJTask{},                     // OJ 任务版本表
+		&entity.OJTaskOrg{},                  // OJ 任务组织关联表
+		&entity.OJTaskItem{},                 // OJ 任务题单表
+		&entity.OJQuestionIntake{},           // OJ 任务待解析题目表
+		&entity.OJTaskExecution{},            // OJ 任务执行表
+		&entity.OJTaskExecutionUser{},        // OJ 任务执行用户快照表
+		&entity.OJTaskExecutionUserOrg{},     // OJ 任务执行用户组织快照表
+		&entity.OJTaskExecutionUserItem{},    // OJ 任务执行用户题目快照表
+		&entity.OJContest{},                  // OJ 比赛表
+		&entity.OJContestProblem{},           // OJ 比赛题单表
+		&entity.OJContestStanding{},          // OJ 比赛最终排名快照表
+		&entity.OJContestStandingItem{},      // OJ 比赛最终逐题结果快照表
+		&entity.OJQuestionTag{},              // OJ 题库自定义标签表
+		&entity.OJQuestionTagBinding{},       // OJ 题库题目标签关联表
+		&entity.Login{},                      // 登录日志表
+		&entity.UserToken{},                  // 用户Token记录表
+		&entity.UserSession{},                // 登录会话表（设备管理与刷新令牌轮换）
+		&entity.TokenBlacklist{},             // Token黑名单表
+		&entity.JwtBlacklist{},               // JWT黑名单表（兼容现有代码）
+		&entity.PasswordResetCode{},          // 找回密码验证码表
+		&entity.UserTwoFactor{},              // 二次验证配置表
+		&entity.UserRecoveryCode{},           // 二次验证恢复码表
+		&entity.TwoFactorChallenge{},         // 二次验证登录挑战表
+		&entity.OAuthLoginState{},            // 第三方登录授权请求表
+		&entity.Role{},                       // 角色表
+		&entity.Capability{},                 // 业务能力表
+		&entity.Menu{},                       // 菜单表
+		&entity.UserOrgRole{},                // 用户组织角色关联表 - 权限上的（与 OrgMember 配合）
+		&entity.RoleCapability{},             // 角色与业务能力关联表
+		&entity.RoleAPI{},                    // 角色API直绑关联表
+		&entity.API{},                        // api表
+		&entity.OutboxEvent{},                // Outbox事件表
+		&entity.Image{},                      // 图片表
+		&entity.ObservabilityMetric{},        // 指标聚合表
+		&entity.ObservabilityTraceSpan{},     // 全链路追踪明细表
 	); err != nil {
 		return err
 	}
@@ -76,6 +103,11 @@ func SQL() error {
 	// 这里显式切换到全新 Session，避免后续辅助迁移误命中脏状态。
 	db = global.DB.Session(&gorm.Session{NewDB: true})
 
+	// users.openid 的普通索引已被唯一索引取代
+	if err := dropIndexIfExists(db, "users", "idx_users_openid"); err != nil {
+		return err
+	}
+
 	// menu_apis 加唯一索引前先按 api_id 清洗历史重复绑定，仅保留最小 menu_id。
 	// 清晰数据用的，后期可删
 	// if err := normalizeMenuAPISingleBinding(); err != nil {
@@ -808,7 +840,18 @@ func migrateOJTaskSchema(db *gorm.DB) error {
 	if err := syncOJQuestionIntakesFromTaskItems(db); err != nil {
 		return err
 	}
-	if err := ensureUniqueIndex(db, "oj_task_executions", "uk_oj_task_executions_task", "task_id"); err != nil {
+	// 周期任务一个版本对应多条执行记录，历史的 task_id 单列唯一索引（含 GORM 默认命名）需要移除，
+	// 改由 (task_id, occurrence_no) 保证同一周期只物化一次。
+	if err := dropIndexIfExists(db, "oj_task_executions", "uk_oj_task_executions_task"); err != nil {
+		return err
+	}
+	if err := dropIndexIfExists(db, "oj_task_executions", "idx_oj_task_executions_task_id"); err != nil {
+		return err
+	}
+	if err := ensureUniqueIndex(db, "oj_task_executions", "uk_oj_task_executions_task_occurrence", "task_id", "occurrence_no"); err != nil {
+		return err
+	}
+	if err := ensureIndex(db, "oj_tasks", "idx_oj_tasks_mode_next_occurrence", "mode", "next_occurrence_at"); err != nil {
 		return err
 	}
 	if err := ensureIndex(db, "oj_task_executions", "idx_oj_task_executions_status_planned", "status", "planned_at"); err != nil {
@@ -1455,3 +1498,11 @@ func migrateLegacyUserRoleToUserOrgRole(
 			AND u.deleted_at IS NULL
 	`, targetOrgID, targetRoleID, sourceRoleID).Error
 }
+
+// normalizeUserOpenids 把历史数据中未绑定第三方身份的空串 openid 置为 NULL。
+func normalizeUserOpenids(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&entity.User{}) {
+		return nil
+	}
+	return db.Model(&entity.User{}).Unscoped().Where("openid = ?", "").Update("openid", nil).Error
+}
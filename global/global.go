@@ -34,6 +34,13 @@ var (
 	OJBindLimiters      map[string]*ratelimit.SlidingWindowLimiter
 	SensitiveDataCodec  *sensitivedata.Codec
 
+	// 找回密码限流器（由 core.InitPasswordResetRateLimiters 初始化）
+	PasswordResetIPLimiter      *ratelimit.SlidingWindowLimiter // 找回密码 IP 级限流器
+	PasswordResetAccountLimiter *ratelimit.SlidingWindowLimiter // 找回密码账号级限流器
+
+	// 登录二次验证限流器（由 core.InitTwoFactorLoginRateLimiter 初始化）
+	TwoFactorLoginIPLimiter *ratelimit.SlidingWindowLimiter // 登录二次验证 IP 级限流器
+
 	// 观测基础设施后端
 	ObservabilityMetrics obsmetrics.MetricsBackend // 观测指标后端
 	ObservabilityTraces  obstrace.TraceBackend     // 全链路追踪后端
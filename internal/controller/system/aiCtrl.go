@@ -1,11 +1,17 @@
 package system
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"strings"
 
 	"personal_assistant/global"
 	streamsse "personal_assistant/internal/infrastructure/sse"
 	"personal_assistant/internal/model/dto/request"
+	resp "personal_assistant/internal/model/dto/response"
 	serviceContract "personal_assistant/internal/service/contract"
 	bizerrors "personal_assistant/pkg/errors"
 	"personal_assistant/pkg/jwt"
@@ -148,6 +154,227 @@ func (ctrl *AICtrl) StreamConversation(c *gin.Context) {
 	}
 }
 
+// DecideInterrupt 处理用户对待确认工具调用的批准 / 拒绝，并以 SSE 形式继续本轮生成。
+func (ctrl *AICtrl) DecideInterrupt(c *gin.Context) {
+	// 与 StreamConversation 保持一致，SSE 入口不接受 query token。
+	if strings.TrimSpace(c.Query("token")) != "" {
+		response.BizFailWithCodeMsg(bizerrors.CodeInvalidParams, "SSE 不接受 query token", c)
+		return
+	}
+
+	var req request.DecideAssistantInterruptReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("AI 中断决策参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	writer := streamsse.NewHTTPStreamWriter(c.Writer, resolveSSEPolicy())
+	err := ctrl.aiService.DecideInterrupt(
+		c.Request.Context(),
+		jwt.GetUserID(c),
+		c.Param("id"),
+		c.Param("interruptId"),
+		&req,
+		writer,
+	)
+	if err == nil {
+		return
+	}
+
+	global.Log.Error("AI 中断决策执行失败", zap.Error(err))
+	if !writer.Started() {
+		response.BizFailWithError(err, c)
+	}
+}
+
+// RegenerateMessage 重新生成指定 assistant 回复，并以 SSE 形式输出新分支。
+func (ctrl *AICtrl) RegenerateMessage(c *gin.Context) {
+	if strings.TrimSpace(c.Query("token")) != "" {
+		response.BizFailWithCodeMsg(bizerrors.CodeInvalidParams, "SSE 不接受 query token", c)
+		return
+	}
+
+	writer := streamsse.NewHTTPStreamWriter(c.Writer, resolveSSEPolicy())
+	err := ctrl.aiService.RegenerateMessage(
+		c.Request.Context(),
+		jwt.GetUserID(c),
+		c.Param("id"),
+		c.Param("messageId"),
+		writer,
+	)
+	if err == nil {
+		return
+	}
+
+	global.Log.Error("AI 重新生成回复失败", zap.Error(err))
+	if !writer.Started() {
+		response.BizFailWithError(err, c)
+	}
+}
+
+// EditMessage 编辑指定用户消息并重新发送，以 SSE 形式输出新分支。
+func (ctrl *AICtrl) EditMessage(c *gin.Context) {
+	if strings.TrimSpace(c.Query("token")) != "" {
+		response.BizFailWithCodeMsg(bizerrors.CodeInvalidParams, "SSE 不接受 query token", c)
+		return
+	}
+
+	var req request.EditAssistantMessageReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("AI 编辑消息参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	writer := streamsse.NewHTTPStreamWriter(c.Writer, resolveSSEPolicy())
+	err := ctrl.aiService.EditMessage(
+		c.Request.Context(),
+		jwt.GetUserID(c),
+		c.Param("id"),
+		c.Param("messageId"),
+		&req,
+		writer,
+	)
+	if err == nil {
+		return
+	}
+
+	global.Log.Error("AI 编辑消息重新发送失败", zap.Error(err))
+	if !writer.Started() {
+		response.BizFailWithError(err, c)
+	}
+}
+
+// SwitchBranch 切换会话的活动分支，并返回切换后的消息列表。
+func (ctrl *AICtrl) SwitchBranch(c *gin.Context) {
+	var req request.SwitchAssistantBranchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("AI 切换分支参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	data, err := ctrl.aiService.SwitchBranch(c.Request.Context(), jwt.GetUserID(c), c.Param("id"), &req)
+	if err != nil {
+		global.Log.Error("AI 切换分支失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "切换成功", c)
+}
+
+// ExportConversation 以附件形式导出指定会话，format 支持 markdown（默认）与 json。
+func (ctrl *AICtrl) ExportConversation(c *gin.Context) {
+	file, err := ctrl.aiService.ExportConversation(c.Request.Context(), jwt.GetUserID(c), c.Param("id"), c.Query("format"))
+	if err != nil {
+		global.Log.Error("AI 导出会话失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	writeAIExportFile(c, file)
+}
+
+// ExportAllConversations 把当前用户全部会话打包成 zip 下载。
+func (ctrl *AICtrl) ExportAllConversations(c *gin.Context) {
+	file, err := ctrl.aiService.ExportAllConversations(c.Request.Context(), jwt.GetUserID(c))
+	if err != nil {
+		global.Log.Error("AI 导出全部会话失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	writeAIExportFile(c, file)
+}
+
+// ImportConversation 把 JSON 导出文件导入为当前用户的新会话。
+func (ctrl *AICtrl) ImportConversation(c *gin.Context) {
+	var req request.ImportAssistantConversationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("AI 导入会话参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	data, err := ctrl.aiService.ImportConversation(c.Request.Context(), jwt.GetUserID(c), &req)
+	if err != nil {
+		global.Log.Error("AI 导入会话失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "导入成功", c)
+}
+
+// CreateShare 为指定会话创建只读分享链接，请求体可省略，默认永不过期且公开可见。
+func (ctrl *AICtrl) CreateShare(c *gin.Context) {
+	var req request.CreateAssistantShareReq
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		global.Log.Error("AI 创建分享参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	data, err := ctrl.aiService.CreateShare(c.Request.Context(), jwt.GetUserID(c), c.Param("id"), &req)
+	if err != nil {
+		global.Log.Error("AI 创建分享失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "分享成功", c)
+}
+
+// ListShares 返回指定会话的全部分享链接。
+func (ctrl *AICtrl) ListShares(c *gin.Context) {
+	data, err := ctrl.aiService.ListShares(c.Request.Context(), jwt.GetUserID(c), c.Param("id"))
+	if err != nil {
+		global.Log.Error("AI 获取分享列表失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "获取成功", c)
+}
+
+// RevokeShare 撤销指定分享链接，撤销后链接立即失效。
+func (ctrl *AICtrl) RevokeShare(c *gin.Context) {
+	if err := ctrl.aiService.RevokeShare(c.Request.Context(), jwt.GetUserID(c), c.Param("id"), c.Param("shareId")); err != nil {
+		global.Log.Error("AI 撤销分享失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithMessage("撤销成功", c)
+}
+
+// ViewShare 通过分享 token 查看只读会话快照。
+// 注意事项：
+//   - 该接口挂在公共路由组，不强制登录；只有可选登录中间件确认会话有效时才有查看者身份。
+func (ctrl *AICtrl) ViewShare(c *gin.Context) {
+	data, err := ctrl.aiService.ViewShare(c.Request.Context(), jwt.GetAuthenticatedUserID(c), c.Param("token"))
+	if err != nil {
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "获取成功", c)
+}
+
+// writeAIExportFile 负责把导出文件作为附件写回，文件名同时给出 ASCII 与 UTF-8 两种形式。
+// 注意事项：
+//   - 流式写出开始后的错误只能记录日志，客户端会收到截断的文件。
+func writeAIExportFile(c *gin.Context, file *resp.AssistantExportFile) {
+	c.Header("Content-Disposition", fmt.Sprintf(
+		"attachment; filename=%q; filename*=UTF-8''%s",
+		file.FileName,
+		url.PathEscape(file.FileName),
+	))
+	if file.Write == nil {
+		c.Data(http.StatusOK, file.ContentType, file.Content)
+		return
+	}
+	c.Header("Content-Type", file.ContentType)
+	c.Status(http.StatusOK)
+	if err := file.Write(c.Writer); err != nil {
+		global.Log.Error("AI 写出导出文件失败", zap.String("file_name", file.FileName), zap.Error(err))
+	}
+}
+
 // resolveSSEPolicy 负责为当前请求解析可用的 SSE 连接策略。
 // 参数：无。
 // 返回值：
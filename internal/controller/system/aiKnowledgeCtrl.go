@@ -0,0 +1,207 @@
+package system
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	serviceContract "personal_assistant/internal/service/contract"
+	bizerrors "personal_assistant/pkg/errors"
+	"personal_assistant/pkg/jwt"
+	"personal_assistant/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// aiKnowledgeMaxUploadBytes 限制知识库上传文件大小，与 Service 侧正文上限保持一致。
+const aiKnowledgeMaxUploadBytes = 1 << 20
+
+// AIKnowledgeCtrl 封装组织知识库文档的 HTTP 入口。
+// 导入和重新导入同时支持 JSON 正文与 multipart 文件上传，文件解析在这里完成，Service 只接收纯文本。
+type AIKnowledgeCtrl struct {
+	aiKnowledgeService serviceContract.AIKnowledgeServiceContract
+}
+
+// ListDocuments 分页查询组织知识库文档。
+func (ctrl *AIKnowledgeCtrl) ListDocuments(c *gin.Context) {
+	var req request.AIKnowledgeDocumentListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		global.Log.Error("AI 知识库文档列表参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	data, err := ctrl.aiKnowledgeService.ListDocuments(c.Request.Context(), jwt.GetUserID(c), &req)
+	if err != nil {
+		global.Log.Error("AI 获取知识库文档列表失败", zap.Uint("org_id", req.OrgID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "获取成功", c)
+}
+
+// CreateDocument 导入一份组织知识库文档。
+func (ctrl *AIKnowledgeCtrl) CreateDocument(c *gin.Context) {
+	var req request.CreateAIKnowledgeDocumentReq
+	if err := c.ShouldBind(&req); err != nil {
+		global.Log.Error("AI 导入知识库文档参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+	content, format, err := readAIKnowledgeUpload(c)
+	if err != nil {
+		response.BizFailWithError(err, c)
+		return
+	}
+	if content != "" {
+		req.ContentText = content
+		if req.Format == "" {
+			req.Format = format
+		}
+	}
+
+	data, err := ctrl.aiKnowledgeService.CreateDocument(c.Request.Context(), jwt.GetUserID(c), &req)
+	if err != nil {
+		global.Log.Error("AI 导入知识库文档失败", zap.Uint("org_id", req.OrgID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "导入成功", c)
+}
+
+// GetDocument 获取知识库文档详情。
+func (ctrl *AIKnowledgeCtrl) GetDocument(c *gin.Context) {
+	data, err := ctrl.aiKnowledgeService.GetDocument(c.Request.Context(), jwt.GetUserID(c), c.Param("id"))
+	if err != nil {
+		global.Log.Error("AI 获取知识库文档详情失败", zap.String("document_id", c.Param("id")), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "获取成功", c)
+}
+
+// UpdateDocument 重新导入知识库文档，生成新版本并重建向量索引。
+func (ctrl *AIKnowledgeCtrl) UpdateDocument(c *gin.Context) {
+	var req request.UpdateAIKnowledgeDocumentReq
+	if err := c.ShouldBind(&req); err != nil {
+		global.Log.Error("AI 更新知识库文档参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+	content, format, err := readAIKnowledgeUpload(c)
+	if err != nil {
+		response.BizFailWithError(err, c)
+		return
+	}
+	if content != "" {
+		req.ContentText = content
+		if req.Format == "" {
+			req.Format = format
+		}
+	}
+
+	data, err := ctrl.aiKnowledgeService.UpdateDocument(c.Request.Context(), jwt.GetUserID(c), c.Param("id"), &req)
+	if err != nil {
+		global.Log.Error("AI 更新知识库文档失败", zap.String("document_id", c.Param("id")), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "更新成功", c)
+}
+
+// DeleteDocument 删除知识库文档及其向量、版本和召回统计。
+func (ctrl *AIKnowledgeCtrl) DeleteDocument(c *gin.Context) {
+	if err := ctrl.aiKnowledgeService.DeleteDocument(c.Request.Context(), jwt.GetUserID(c), c.Param("id")); err != nil {
+		global.Log.Error("AI 删除知识库文档失败", zap.String("document_id", c.Param("id")), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithMessage("删除成功", c)
+}
+
+// ListVersions 获取知识库文档的版本历史。
+func (ctrl *AIKnowledgeCtrl) ListVersions(c *gin.Context) {
+	data, err := ctrl.aiKnowledgeService.ListVersions(c.Request.Context(), jwt.GetUserID(c), c.Param("id"))
+	if err != nil {
+		global.Log.Error("AI 获取知识库文档版本失败", zap.String("document_id", c.Param("id")), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "获取成功", c)
+}
+
+// GetVersion 获取知识库文档指定版本的完整快照。
+func (ctrl *AIKnowledgeCtrl) GetVersion(c *gin.Context) {
+	versionNo, err := strconv.Atoi(c.Param("versionNo"))
+	if err != nil || versionNo <= 0 {
+		response.BizFailWithCode(bizerrors.CodeInvalidParams, c)
+		return
+	}
+
+	data, err := ctrl.aiKnowledgeService.GetVersion(c.Request.Context(), jwt.GetUserID(c), c.Param("id"), versionNo)
+	if err != nil {
+		global.Log.Error(
+			"AI 获取知识库文档版本详情失败",
+			zap.String("document_id", c.Param("id")),
+			zap.Int("version_no", versionNo),
+			zap.Error(err),
+		)
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "获取成功", c)
+}
+
+// readAIKnowledgeUpload 读取 multipart 请求中的可选 file 字段，返回正文和按扩展名推断的格式。
+// 非 multipart 请求或未携带文件时返回空正文，由调用方继续使用 JSON / 表单里的 content_text。
+func readAIKnowledgeUpload(c *gin.Context) (string, string, error) {
+	if !strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		return "", "", nil
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			return "", "", nil
+		}
+		return "", "", bizerrors.Wrap(bizerrors.CodeBindFailed, err)
+	}
+
+	var format consts.AIKnowledgeFormat
+	switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+	case ".md", ".markdown":
+		format = consts.AIKnowledgeFormatMarkdown
+	case ".txt":
+		format = consts.AIKnowledgeFormatText
+	default:
+		return "", "", bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "仅支持上传 .md、.markdown 或 .txt 文件")
+	}
+	if fileHeader.Size > aiKnowledgeMaxUploadBytes {
+		return "", "", bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "上传文件不能超过 1MB")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", "", bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+	defer file.Close()
+	raw, err := io.ReadAll(io.LimitReader(file, aiKnowledgeMaxUploadBytes+1))
+	if err != nil {
+		return "", "", bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+	if len(raw) > aiKnowledgeMaxUploadBytes {
+		return "", "", bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "上传文件不能超过 1MB")
+	}
+	// 去掉 UTF-8 BOM，避免 Windows 编辑器导出的文件首行标题无法被识别。
+	content := strings.TrimPrefix(string(raw), "\uFEFF")
+	if strings.TrimSpace(content) == "" {
+		return "", "", bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "上传文件内容为空")
+	}
+	return content, string(format), nil
+}
@@ -0,0 +1,184 @@
+package system
+
+import (
+	"personal_assistant/global"
+	"personal_assistant/internal/model/dto/request"
+	serviceContract "personal_assistant/internal/service/contract"
+	bizerrors "personal_assistant/pkg/errors"
+	"personal_assistant/pkg/jwt"
+	"personal_assistant/pkg/response"
+	"personal_assistant/pkg/util"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AIMemoryCtrl 封装用户侧 AI 记忆管理 HTTP 入口。
+// 作用域统一通过 query 参数 scope / org_id 传入，权限判定全部交给 Service。
+type AIMemoryCtrl struct {
+	aiMemoryService serviceContract.AIMemoryServiceContract
+}
+
+// ListFacts 分页检索结构化记忆事实。
+func (ctrl *AIMemoryCtrl) ListFacts(c *gin.Context) {
+	var req request.AIMemoryFactListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		global.Log.Error("AI 记忆事实列表参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	data, err := ctrl.aiMemoryService.ListFacts(c.Request.Context(), jwt.GetUserID(c), &req)
+	if err != nil {
+		global.Log.Error("AI 获取记忆事实列表失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "获取成功", c)
+}
+
+// UpdateFact 修正一条结构化记忆事实。
+func (ctrl *AIMemoryCtrl) UpdateFact(c *gin.Context) {
+	factID := util.ParseUint(c.Param("id"))
+	if factID == 0 {
+		response.BizFailWithCode(bizerrors.CodeInvalidParams, c)
+		return
+	}
+	var scopeReq request.AIMemoryScopeReq
+	if err := c.ShouldBindQuery(&scopeReq); err != nil {
+		global.Log.Error("AI 修正记忆事实参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+	var req request.UpdateAIMemoryFactReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("AI 修正记忆事实参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	data, err := ctrl.aiMemoryService.UpdateFact(c.Request.Context(), jwt.GetUserID(c), factID, &scopeReq, &req)
+	if err != nil {
+		global.Log.Error("AI 修正记忆事实失败", zap.Uint("fact_id", factID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "更新成功", c)
+}
+
+// DeleteFact 删除一条结构化记忆事实。
+func (ctrl *AIMemoryCtrl) DeleteFact(c *gin.Context) {
+	factID := util.ParseUint(c.Param("id"))
+	if factID == 0 {
+		response.BizFailWithCode(bizerrors.CodeInvalidParams, c)
+		return
+	}
+	var scopeReq request.AIMemoryScopeReq
+	if err := c.ShouldBindQuery(&scopeReq); err != nil {
+		global.Log.Error("AI 删除记忆事实参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	if err := ctrl.aiMemoryService.DeleteFact(c.Request.Context(), jwt.GetUserID(c), factID, &scopeReq); err != nil {
+		global.Log.Error("AI 删除记忆事实失败", zap.Uint("fact_id", factID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithMessage("删除成功", c)
+}
+
+// ListDocuments 分页检索长期记忆文档。
+func (ctrl *AIMemoryCtrl) ListDocuments(c *gin.Context) {
+	var req request.AIMemoryDocumentListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		global.Log.Error("AI 记忆文档列表参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	data, err := ctrl.aiMemoryService.ListDocuments(c.Request.Context(), jwt.GetUserID(c), &req)
+	if err != nil {
+		global.Log.Error("AI 获取记忆文档列表失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "获取成功", c)
+}
+
+// GetDocument 获取单份长期记忆文档详情。
+func (ctrl *AIMemoryCtrl) GetDocument(c *gin.Context) {
+	var scopeReq request.AIMemoryScopeReq
+	if err := c.ShouldBindQuery(&scopeReq); err != nil {
+		global.Log.Error("AI 记忆文档详情参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	data, err := ctrl.aiMemoryService.GetDocument(c.Request.Context(), jwt.GetUserID(c), c.Param("id"), &scopeReq)
+	if err != nil {
+		global.Log.Error("AI 获取记忆文档详情失败", zap.String("document_id", c.Param("id")), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "获取成功", c)
+}
+
+// UpdateDocument 修正一份长期记忆文档，正文变化会触发重建向量索引。
+func (ctrl *AIMemoryCtrl) UpdateDocument(c *gin.Context) {
+	var scopeReq request.AIMemoryScopeReq
+	if err := c.ShouldBindQuery(&scopeReq); err != nil {
+		global.Log.Error("AI 修正记忆文档参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+	var req request.UpdateAIMemoryDocumentReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("AI 修正记忆文档参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	data, err := ctrl.aiMemoryService.UpdateDocument(c.Request.Context(), jwt.GetUserID(c), c.Param("id"), &scopeReq, &req)
+	if err != nil {
+		global.Log.Error("AI 修正记忆文档失败", zap.String("document_id", c.Param("id")), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "更新成功", c)
+}
+
+// DeleteDocument 删除一份长期记忆文档，并级联删除向量索引。
+func (ctrl *AIMemoryCtrl) DeleteDocument(c *gin.Context) {
+	var scopeReq request.AIMemoryScopeReq
+	if err := c.ShouldBindQuery(&scopeReq); err != nil {
+		global.Log.Error("AI 删除记忆文档参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	if err := ctrl.aiMemoryService.DeleteDocument(c.Request.Context(), jwt.GetUserID(c), c.Param("id"), &scopeReq); err != nil {
+		global.Log.Error("AI 删除记忆文档失败", zap.String("document_id", c.Param("id")), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithMessage("删除成功", c)
+}
+
+// ClearScope 清空指定作用域下的全部记忆，用于数据主体删除请求。
+func (ctrl *AIMemoryCtrl) ClearScope(c *gin.Context) {
+	var scopeReq request.AIMemoryScopeReq
+	if err := c.ShouldBindQuery(&scopeReq); err != nil {
+		global.Log.Error("AI 清空记忆参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	data, err := ctrl.aiMemoryService.ClearScope(c.Request.Context(), jwt.GetUserID(c), &scopeReq)
+	if err != nil {
+		global.Log.Error("AI 清空记忆失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(data, "删除成功", c)
+}
@@ -0,0 +1,176 @@
+package system
+
+import (
+	"strings"
+
+	"personal_assistant/global"
+	streamsse "personal_assistant/internal/infrastructure/sse"
+	"personal_assistant/internal/model/dto/request"
+	serviceContract "personal_assistant/internal/service/contract"
+	bizerrors "personal_assistant/pkg/errors"
+	"personal_assistant/pkg/jwt"
+	"personal_assistant/pkg/response"
+	"personal_assistant/pkg/util"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type OJContestCtrl struct {
+	ojContestService serviceContract.OJContestServiceContract
+}
+
+// CreateContest 创建 OJ 比赛
+func (ctrl *OJContestCtrl) CreateContest(c *gin.Context) {
+	var req request.CreateOJContestReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("创建 OJ 比赛参数错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	out, err := ctrl.ojContestService.CreateContest(c.Request.Context(), userID, &req)
+	if err != nil {
+		global.Log.Error("创建 OJ 比赛失败", zap.Uint("user_id", userID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(out, c)
+}
+
+// UpdateContest 更新未开赛的 OJ 比赛
+func (ctrl *OJContestCtrl) UpdateContest(c *gin.Context) {
+	var req request.UpdateOJContestReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("更新 OJ 比赛参数错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+
+	contestID := util.ParseUint(c.Param("id"))
+	if contestID == 0 {
+		response.BizFailWithCode(bizerrors.CodeInvalidParams, c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	if err := ctrl.ojContestService.UpdateContest(c.Request.Context(), userID, contestID, &req); err != nil {
+		global.Log.Error("更新 OJ 比赛失败", zap.Uint("user_id", userID), zap.Uint("contest_id", contestID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOk(c)
+}
+
+// DeleteContest 删除未开赛的 OJ 比赛
+func (ctrl *OJContestCtrl) DeleteContest(c *gin.Context) {
+	contestID := util.ParseUint(c.Param("id"))
+	if contestID == 0 {
+		response.BizFailWithCode(bizerrors.CodeInvalidParams, c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	if err := ctrl.ojContestService.DeleteContest(c.Request.Context(), userID, contestID); err != nil {
+		global.Log.Error("删除 OJ 比赛失败", zap.Uint("user_id", userID), zap.Uint("contest_id", contestID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOk(c)
+}
+
+// GetVisibleContestList 获取可见的 OJ 比赛列表
+func (ctrl *OJContestCtrl) GetVisibleContestList(c *gin.Context) {
+	var req request.OJContestListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		global.Log.Error("查询 OJ 比赛列表参数错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	list, total, err := ctrl.ojContestService.GetVisibleContestList(c.Request.Context(), userID, &req)
+	if err != nil {
+		global.Log.Error("查询 OJ 比赛列表失败", zap.Uint("user_id", userID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	response.BizOkWithPage(list, total, page, pageSize, c)
+}
+
+// GetContestDetail 获取 OJ 比赛详情
+func (ctrl *OJContestCtrl) GetContestDetail(c *gin.Context) {
+	contestID := util.ParseUint(c.Param("id"))
+	if contestID == 0 {
+		response.BizFailWithCode(bizerrors.CodeInvalidParams, c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	out, err := ctrl.ojContestService.GetContestDetail(c.Request.Context(), userID, contestID)
+	if err != nil {
+		global.Log.Error("查询 OJ 比赛详情失败", zap.Uint("user_id", userID), zap.Uint("contest_id", contestID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(out, c)
+}
+
+// GetScoreboard 获取 OJ 比赛榜单
+func (ctrl *OJContestCtrl) GetScoreboard(c *gin.Context) {
+	contestID := util.ParseUint(c.Param("id"))
+	if contestID == 0 {
+		response.BizFailWithCode(bizerrors.CodeInvalidParams, c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	out, err := ctrl.ojContestService.GetScoreboard(c.Request.Context(), userID, contestID)
+	if err != nil {
+		global.Log.Error("查询 OJ 比赛榜单失败", zap.Uint("user_id", userID), zap.Uint("contest_id", contestID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(out, c)
+}
+
+// StreamScoreboard 以 SSE 订阅 OJ 比赛实时榜单，支持 Last-Event-ID 断线续传。
+func (ctrl *OJContestCtrl) StreamScoreboard(c *gin.Context) {
+	// 与 AI 流式接口保持一致，SSE 入口不接受 query token。
+	if strings.TrimSpace(c.Query("token")) != "" {
+		response.BizFailWithCodeMsg(bizerrors.CodeInvalidParams, "SSE 不接受 query token", c)
+		return
+	}
+	contestID := util.ParseUint(c.Param("id"))
+	if contestID == 0 {
+		response.BizFailWithCode(bizerrors.CodeInvalidParams, c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	writer := streamsse.NewHTTPStreamWriter(c.Writer, resolveSSEPolicy())
+	err := ctrl.ojContestService.StreamScoreboard(
+		c.Request.Context(),
+		userID,
+		contestID,
+		streamsse.LastEventIDFromRequest(c.Request),
+		writer,
+	)
+	if err == nil {
+		return
+	}
+
+	global.Log.Error("OJ 比赛榜单 SSE 执行失败", zap.Uint("user_id", userID), zap.Uint("contest_id", contestID), zap.Error(err))
+	if !writer.Started() {
+		response.BizFailWithError(err, c)
+	}
+}
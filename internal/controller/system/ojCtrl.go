@@ -99,6 +99,34 @@ func (ctrl *OJCtrl) BindOJAccount(c *gin.Context) {
 		Success("绑定成功", out)
 }
 
+// UnbindOJAccount 解绑当前用户在指定平台的 OJ 账号
+func (ctrl *OJCtrl) UnbindOJAccount(c *gin.Context) {
+	var req request.UnbindOJAccountReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("解绑参数错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, fmt.Sprintf("参数错误: %v", err), c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	if userID == 0 {
+		response.BizResult(bizerrors.CodeLoginRequired, gin.H{"reload": true}, "用户未登录", c)
+		return
+	}
+
+	out, err := ctrl.ojService.UnbindOJAccount(c.Request.Context(), userID, &req)
+	if err != nil {
+		global.Log.Error("解绑OJ账号失败",
+			zap.Uint("user_id", userID),
+			zap.String("platform", req.Platform),
+			zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+
+	response.BizOkWithData(out, c)
+}
+
 func (ctrl *OJCtrl) GetRankingList(c *gin.Context) {
 	var req request.OJRankingListReq
 	if err := c.ShouldBindJSON(&req); err != nil {
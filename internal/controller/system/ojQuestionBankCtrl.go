@@ -0,0 +1,131 @@
+package system
+
+import (
+	"personal_assistant/global"
+	"personal_assistant/internal/model/dto/request"
+	serviceContract "personal_assistant/internal/service/contract"
+	bizerrors "personal_assistant/pkg/errors"
+	"personal_assistant/pkg/jwt"
+	"personal_assistant/pkg/response"
+	"personal_assistant/pkg/util"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type OJQuestionBankCtrl struct {
+	ojQuestionBankService serviceContract.OJQuestionBankServiceContract
+}
+
+// SearchQuestions 检索跨平台题库目录
+func (ctrl *OJQuestionBankCtrl) SearchQuestions(c *gin.Context) {
+	var req request.OJQuestionBankListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		global.Log.Error("检索题库参数错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+
+	list, total, err := ctrl.ojQuestionBankService.SearchQuestions(c.Request.Context(), &req)
+	if err != nil {
+		global.Log.Error("检索题库失败", zap.String("keyword", req.Keyword), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithPage(list, total, req.Page, req.PageSize, c)
+}
+
+// ListTags 获取题库标签列表
+func (ctrl *OJQuestionBankCtrl) ListTags(c *gin.Context) {
+	list, err := ctrl.ojQuestionBankService.ListTags(c.Request.Context())
+	if err != nil {
+		global.Log.Error("查询题库标签失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(list, c)
+}
+
+// CreateTag 创建题库标签
+func (ctrl *OJQuestionBankCtrl) CreateTag(c *gin.Context) {
+	var req request.CreateOJQuestionTagReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("创建题库标签参数错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	out, err := ctrl.ojQuestionBankService.CreateTag(c.Request.Context(), userID, &req)
+	if err != nil {
+		global.Log.Error("创建题库标签失败", zap.Uint("user_id", userID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(out, c)
+}
+
+// UpdateTag 修改题库标签
+func (ctrl *OJQuestionBankCtrl) UpdateTag(c *gin.Context) {
+	var req request.UpdateOJQuestionTagReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("修改题库标签参数错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+
+	tagID := util.ParseUint(c.Param("id"))
+	if tagID == 0 {
+		response.BizFailWithCode(bizerrors.CodeInvalidParams, c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	if err := ctrl.ojQuestionBankService.UpdateTag(c.Request.Context(), userID, tagID, &req); err != nil {
+		global.Log.Error("修改题库标签失败", zap.Uint("user_id", userID), zap.Uint("tag_id", tagID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOk(c)
+}
+
+// DeleteTag 删除题库标签
+func (ctrl *OJQuestionBankCtrl) DeleteTag(c *gin.Context) {
+	tagID := util.ParseUint(c.Param("id"))
+	if tagID == 0 {
+		response.BizFailWithCode(bizerrors.CodeInvalidParams, c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	if err := ctrl.ojQuestionBankService.DeleteTag(c.Request.Context(), userID, tagID); err != nil {
+		global.Log.Error("删除题库标签失败", zap.Uint("user_id", userID), zap.Uint("tag_id", tagID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOk(c)
+}
+
+// SetQuestionTags 设置题目的标签
+func (ctrl *OJQuestionBankCtrl) SetQuestionTags(c *gin.Context) {
+	var req request.SetOJQuestionTagsReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("设置题目标签参数错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	if err := ctrl.ojQuestionBankService.SetQuestionTags(c.Request.Context(), userID, &req); err != nil {
+		global.Log.Error(
+			"设置题目标签失败",
+			zap.Uint("user_id", userID),
+			zap.String("platform", req.Platform),
+			zap.Uint("question_id", req.QuestionID),
+			zap.Error(err),
+		)
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOk(c)
+}
@@ -1,7 +1,13 @@
 package system
 
 import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
 	"personal_assistant/global"
+	streamsse "personal_assistant/internal/infrastructure/sse"
 	"personal_assistant/internal/model/dto/request"
 	serviceContract "personal_assistant/internal/service/contract"
 	bizerrors "personal_assistant/pkg/errors"
@@ -220,6 +226,31 @@ func (ctrl *OJTaskCtrl) GetTaskVersions(c *gin.Context) {
 	response.BizOkWithData(out, c)
 }
 
+// GetTaskOccurrences 获取 OJTask 跨周期执行对比
+func (ctrl *OJTaskCtrl) GetTaskOccurrences(c *gin.Context) {
+	var req request.OJTaskOccurrenceListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		global.Log.Error("查询 OJTask 周期对比参数错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+
+	taskID := util.ParseUint(c.Param("id"))
+	if taskID == 0 {
+		response.BizFailWithCode(bizerrors.CodeInvalidParams, c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	out, err := ctrl.ojTaskService.GetTaskOccurrences(c.Request.Context(), userID, taskID, &req)
+	if err != nil {
+		global.Log.Error("查询 OJTask 周期对比失败", zap.Uint("user_id", userID), zap.Uint("task_id", taskID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(out, c)
+}
+
 // GetTaskExecutionDetail 获取 OJTask 执行详情
 func (ctrl *OJTaskCtrl) GetTaskExecutionDetail(c *gin.Context) {
 	taskID := util.ParseUint(c.Param("id"))
@@ -277,6 +308,56 @@ func (ctrl *OJTaskCtrl) GetTaskExecutionUsers(c *gin.Context) {
 	response.BizOkWithPage(out.List, out.Total, out.Page, out.PageSize, c)
 }
 
+// ExportTaskExecution 以 CSV 或 XLSX 附件导出 OJTask 执行结果矩阵
+// 注意事项：
+//   - 权限与参数错误在写出前以统一 JSON 返回；开始写出后的错误只能记录日志，客户端会收到截断的文件。
+func (ctrl *OJTaskCtrl) ExportTaskExecution(c *gin.Context) {
+	var req request.OJTaskExecutionExportReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		global.Log.Error("导出 OJTask 执行结果参数错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+
+	taskID := util.ParseUint(c.Param("id"))
+	executionID := util.ParseUint(c.Param("executionId"))
+	if taskID == 0 || executionID == 0 {
+		response.BizFailWithCode(bizerrors.CodeInvalidParams, c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	file, err := ctrl.ojTaskService.ExportTaskExecution(c.Request.Context(), userID, taskID, executionID, &req)
+	if err != nil {
+		global.Log.Error(
+			"导出 OJTask 执行结果失败",
+			zap.Uint("user_id", userID),
+			zap.Uint("task_id", taskID),
+			zap.Uint("execution_id", executionID),
+			zap.Error(err),
+		)
+		response.BizFailWithError(err, c)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(
+		"attachment; filename=%q; filename*=UTF-8''%s",
+		file.FileName,
+		url.PathEscape(file.FileName),
+	))
+	c.Header("Content-Type", file.ContentType)
+	c.Status(http.StatusOK)
+	if err := file.Write(c.Writer); err != nil {
+		global.Log.Error(
+			"写出 OJTask 执行结果导出文件失败",
+			zap.Uint("user_id", userID),
+			zap.Uint("task_id", taskID),
+			zap.Uint("execution_id", executionID),
+			zap.Error(err),
+		)
+	}
+}
+
 // GetTaskExecutionUserDetail 获取 OJTask 执行用户详情
 func (ctrl *OJTaskCtrl) GetTaskExecutionUserDetail(c *gin.Context) {
 	taskID := util.ParseUint(c.Param("id"))
@@ -309,3 +390,117 @@ func (ctrl *OJTaskCtrl) GetTaskExecutionUserDetail(c *gin.Context) {
 	}
 	response.BizOkWithData(out, c)
 }
+
+// StreamTaskNotices 以 SSE 订阅当前用户的 OJ 任务截止提醒与结果冻结通知，支持 Last-Event-ID 断线续传。
+func (ctrl *OJTaskCtrl) StreamTaskNotices(c *gin.Context) {
+	// 与 AI 流式接口保持一致，SSE 入口不接受 query token。
+	if strings.TrimSpace(c.Query("token")) != "" {
+		response.BizFailWithCodeMsg(bizerrors.CodeInvalidParams, "SSE 不接受 query token", c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	writer := streamsse.NewHTTPStreamWriter(c.Writer, resolveSSEPolicy())
+	err := ctrl.ojTaskService.StreamTaskNotices(
+		c.Request.Context(),
+		userID,
+		streamsse.LastEventIDFromRequest(c.Request),
+		writer,
+	)
+	if err == nil {
+		return
+	}
+
+	global.Log.Error("OJ 任务通知 SSE 执行失败", zap.Uint("user_id", userID), zap.Error(err))
+	if !writer.Started() {
+		response.BizFailWithError(err, c)
+	}
+}
+
+// ListPendingIntakes 跨任务查询待解析题目
+func (ctrl *OJTaskCtrl) ListPendingIntakes(c *gin.Context) {
+	var req request.OJQuestionIntakeListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		global.Log.Error("查询待解析题目参数错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	list, total, err := ctrl.ojTaskService.ListPendingIntakes(c.Request.Context(), userID, &req)
+	if err != nil {
+		global.Log.Error("查询待解析题目失败", zap.Uint("user_id", userID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithPage(list, total, req.Page, req.PageSize, c)
+}
+
+// SuggestIntakeCandidates 为待解析标题推荐题库候选
+func (ctrl *OJTaskCtrl) SuggestIntakeCandidates(c *gin.Context) {
+	var req request.OJQuestionIntakeCandidateReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		global.Log.Error("查询待解析题目候选参数错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	list, err := ctrl.ojTaskService.SuggestIntakeCandidates(c.Request.Context(), userID, &req)
+	if err != nil {
+		global.Log.Error("查询待解析题目候选失败", zap.Uint("user_id", userID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(list, c)
+}
+
+// ConfirmIntake 确认待解析标题对应的题库题目
+func (ctrl *OJTaskCtrl) ConfirmIntake(c *gin.Context) {
+	var req request.ConfirmOJQuestionIntakeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("确认待解析题目参数错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	out, err := ctrl.ojTaskService.ConfirmIntake(c.Request.Context(), userID, &req)
+	if err != nil {
+		global.Log.Error(
+			"确认待解析题目失败",
+			zap.Uint("user_id", userID),
+			zap.String("platform", req.Platform),
+			zap.Uint("question_id", req.QuestionID),
+			zap.Error(err),
+		)
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(out, c)
+}
+
+// CreateManualQuestionForIntake 为待解析标题手工录入题库题目
+func (ctrl *OJTaskCtrl) CreateManualQuestionForIntake(c *gin.Context) {
+	var req request.CreateManualOJQuestionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("手工录入题目参数错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	out, err := ctrl.ojTaskService.CreateManualQuestionForIntake(c.Request.Context(), userID, &req)
+	if err != nil {
+		global.Log.Error(
+			"手工录入题目失败",
+			zap.Uint("user_id", userID),
+			zap.String("platform", req.Platform),
+			zap.String("question_code", req.QuestionCode),
+			zap.Error(err),
+		)
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(out, c)
+}
@@ -268,6 +268,163 @@ func (ctrl *OrgCtrl) RecoverMember(c *gin.Context) {
 	response.BizOkWithMessage("成员已恢复", c)
 }
 
+// CreateOrgInvitation 创建组织邀请
+func (ctrl *OrgCtrl) CreateOrgInvitation(c *gin.Context) {
+	orgID := util.ParseUint(c.Param("id"))
+	if orgID == 0 {
+		response.BizFailWithMessage("参数错误", c)
+		return
+	}
+
+	var req request.CreateOrgInvitationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("创建邀请参数绑定失败", zap.Error(err))
+		response.BizFailWithMessage("参数错误", c)
+		return
+	}
+
+	operatorID := jwt.GetUserID(c)
+	result, err := ctrl.orgService.CreateOrgInvitation(c.Request.Context(), operatorID, orgID, &req)
+	if err != nil {
+		global.Log.Error("创建邀请失败", zap.Uint("org_id", orgID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(result, "邀请已创建，链接仅显示一次", c)
+}
+
+// ListOrgInvitations 组织邀请列表
+func (ctrl *OrgCtrl) ListOrgInvitations(c *gin.Context) {
+	orgID := util.ParseUint(c.Param("id"))
+	if orgID == 0 {
+		response.BizFailWithMessage("参数错误", c)
+		return
+	}
+
+	operatorID := jwt.GetUserID(c)
+	items, err := ctrl.orgService.ListOrgInvitations(c.Request.Context(), operatorID, orgID)
+	if err != nil {
+		global.Log.Error("获取邀请列表失败", zap.Uint("org_id", orgID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(items, c)
+}
+
+// RevokeOrgInvitation 撤销组织邀请
+func (ctrl *OrgCtrl) RevokeOrgInvitation(c *gin.Context) {
+	orgID := util.ParseUint(c.Param("id"))
+	invitationID := util.ParseUint(c.Param("invitationId"))
+	if orgID == 0 || invitationID == 0 {
+		response.BizFailWithMessage("参数错误", c)
+		return
+	}
+
+	operatorID := jwt.GetUserID(c)
+	if err := ctrl.orgService.RevokeOrgInvitation(c.Request.Context(), operatorID, orgID, invitationID); err != nil {
+		global.Log.Error(
+			"撤销邀请失败",
+			zap.Uint("org_id", orgID),
+			zap.Uint("invitation_id", invitationID),
+			zap.Error(err),
+		)
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithMessage("邀请已撤销", c)
+}
+
+// AcceptOrgInvitation 接受组织邀请
+func (ctrl *OrgCtrl) AcceptOrgInvitation(c *gin.Context) {
+	var req request.AcceptOrgInvitationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("接受邀请参数绑定失败", zap.Error(err))
+		response.BizFailWithMessage("参数错误", c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	result, err := ctrl.orgService.AcceptOrgInvitation(c.Request.Context(), userID, req.Token)
+	if err != nil {
+		global.Log.Error("接受邀请失败", zap.Uint("user_id", userID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	message := "加入成功"
+	if result.Status == "pending" {
+		message = "已提交加入申请，等待管理员审批"
+	}
+	response.BizOkWithDetailed(result, message, c)
+}
+
+// ListOrgJoinRequests 组织加入申请列表
+func (ctrl *OrgCtrl) ListOrgJoinRequests(c *gin.Context) {
+	orgID := util.ParseUint(c.Param("id"))
+	if orgID == 0 {
+		response.BizFailWithMessage("参数错误", c)
+		return
+	}
+
+	var req request.OrgJoinRequestListReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		global.Log.Error("加入申请列表参数绑定失败", zap.Error(err))
+		response.BizFailWithMessage("参数错误", c)
+		return
+	}
+
+	operatorID := jwt.GetUserID(c)
+	items, err := ctrl.orgService.ListOrgJoinRequests(c.Request.Context(), operatorID, orgID, req.Status)
+	if err != nil {
+		global.Log.Error("获取加入申请列表失败", zap.Uint("org_id", orgID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(items, c)
+}
+
+// ApproveOrgJoinRequest 通过加入申请
+func (ctrl *OrgCtrl) ApproveOrgJoinRequest(c *gin.Context) {
+	ctrl.reviewOrgJoinRequest(c, true)
+}
+
+// RejectOrgJoinRequest 拒绝加入申请
+func (ctrl *OrgCtrl) RejectOrgJoinRequest(c *gin.Context) {
+	ctrl.reviewOrgJoinRequest(c, false)
+}
+
+func (ctrl *OrgCtrl) reviewOrgJoinRequest(c *gin.Context, approve bool) {
+	orgID := util.ParseUint(c.Param("id"))
+	requestID := util.ParseUint(c.Param("requestId"))
+	if orgID == 0 || requestID == 0 {
+		response.BizFailWithMessage("参数错误", c)
+		return
+	}
+
+	var req request.ReviewOrgJoinRequestReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		// 允许无 body
+		req.Note = ""
+	}
+
+	operatorID := jwt.GetUserID(c)
+	review, message := ctrl.orgService.RejectOrgJoinRequest, "已拒绝加入申请"
+	if approve {
+		review, message = ctrl.orgService.ApproveOrgJoinRequest, "已通过加入申请"
+	}
+	if err := review(c.Request.Context(), operatorID, orgID, requestID, req.Note); err != nil {
+		global.Log.Error(
+			"审批加入申请失败",
+			zap.Uint("org_id", orgID),
+			zap.Uint("request_id", requestID),
+			zap.Bool("approve", approve),
+			zap.Error(err),
+		)
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithMessage(message, c)
+}
+
 // ==================== 辅助函数 ====================
 
 // readModelToOrgItem 将组织读模型转换为响应DTO。
@@ -45,20 +45,17 @@ func (r *RefreshTokenCtrl) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// 检查refresh token是否在黑名单中
-	if r.jwtService.IsInBlacklist(refreshToken) {
-		helper.CommonError("token is blacklist", bizerrors.CodeTokenBlacklisted, nil)
-		response.NewResponse[resp.AuthResponse, resp.AuthResponse](c).SetCode(bizerrors.CodeTokenBlacklisted).
-			Failed("token is blacklist", &resp.AuthResponse{
-				Message: "token is blacklist",
-				Reload:  true})
-		return
-	}
-
-	// 获取用户所有信息
-	refreshReq, jwtErr := r.jwtService.GetAccessToken(c.Request.Context(), refreshToken)
+	// 轮换刷新令牌：会话已吊销或旧令牌被重放时返回错误，重放会同时注销整个会话
+	refreshReq, refreshExpiresAt, jwtErr := r.jwtService.RefreshTokens(
+		c.Request.Context(),
+		refreshToken,
+		loginDeviceFromRequest(c),
+	)
 	if jwtErr != nil {
 		helper.HandleJWTError(jwtErr)
+		if jwtErr.Code == bizerrors.CodeTokenBlacklisted {
+			jwt.ClearRefreshToken(c)
+		}
 		response.NewResponse[resp.AuthResponse, resp.AuthResponse](c).SetCode(jwtErr.Code).
 			Failed(jwtErr.Message, &resp.AuthResponse{
 				Message: jwtErr.Message,
@@ -67,10 +64,8 @@ func (r *RefreshTokenCtrl) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// 创建令牌
-	if refreshReq != nil {
-		refreshReq.RefreshToken = refreshToken
-	}
+	// 新刷新令牌覆盖 Cookie，旧令牌此后不可再用
+	setRefreshTokenCookie(c, refreshReq.RefreshToken, refreshExpiresAt)
 	response.NewResponse[resp.RefreshTokenResponse, resp.RefreshTokenResponse](c).
 		SetTrans(&resp.RefreshTokenResponse{}).
 		Success("刷新成功", refreshReq)
@@ -7,6 +7,8 @@ import (
 // Supplier 用于集中提供当前模块依赖对象。
 type Supplier interface {
 	GetAICtrl() *AICtrl
+	GetAIMemoryCtrl() *AIMemoryCtrl
+	GetAIKnowledgeCtrl() *AIKnowledgeCtrl
 	GetRefreshTokenCtrl() *RefreshTokenCtrl
 	GetBaseCtrl() *BaseCtrl
 	GetHealthCtrl() *HealthCtrl
@@ -14,6 +16,8 @@ type Supplier interface {
 	GetOrgCtrl() *OrgCtrl
 	GetOJCtrl() *OJCtrl
 	GetOJTaskCtrl() *OJTaskCtrl
+	GetOJContestCtrl() *OJContestCtrl
+	GetOJQuestionBankCtrl() *OJQuestionBankCtrl
 	GetApiCtrl() *ApiCtrl
 	GetMenuCtrl() *MenuCtrl
 	GetRoleCtrl() *RoleCtrl
@@ -32,6 +36,12 @@ func SetUp(service *service.Group) Supplier {
 	cs.aiCtrl = &AICtrl{
 		aiService: service.SystemServiceSupplier.GetAISvc(),
 	}
+	cs.aiMemoryCtrl = &AIMemoryCtrl{
+		aiMemoryService: service.SystemServiceSupplier.GetAIMemorySvc(),
+	}
+	cs.aiKnowledgeCtrl = &AIKnowledgeCtrl{
+		aiKnowledgeService: service.SystemServiceSupplier.GetAIKnowledgeSvc(),
+	}
 	cs.baseCtrl = &BaseCtrl{
 		baseService: service.SystemServiceSupplier.GetBaseSvc(),
 	}
@@ -41,6 +51,7 @@ func SetUp(service *service.Group) Supplier {
 	cs.userCtrl = &UserCtrl{
 		userService: service.SystemServiceSupplier.GetUserSvc(),
 		jwtService:  service.SystemServiceSupplier.GetJWTSvc(),
+		baseService: service.SystemServiceSupplier.GetBaseSvc(),
 	}
 	cs.orgCtrl = &OrgCtrl{
 		orgService: service.SystemServiceSupplier.GetOrgSvc(),
@@ -53,6 +64,12 @@ func SetUp(service *service.Group) Supplier {
 	cs.ojTaskCtrl = &OJTaskCtrl{
 		ojTaskService: service.SystemServiceSupplier.GetOJTaskSvc(),
 	}
+	cs.ojContestCtrl = &OJContestCtrl{
+		ojContestService: service.SystemServiceSupplier.GetOJContestSvc(),
+	}
+	cs.ojQuestionBankCtrl = &OJQuestionBankCtrl{
+		ojQuestionBankService: service.SystemServiceSupplier.GetOJQuestionBankSvc(),
+	}
 	cs.apiCtrl = &ApiCtrl{
 		apiService: service.SystemServiceSupplier.GetApiSvc(),
 	}
@@ -2,19 +2,23 @@ package system
 
 // controllerSupplier 用于集中提供当前模块依赖对象。
 type controllerSupplier struct {
-	aiCtrl            *AICtrl
-	refreshTokenCtrl  *RefreshTokenCtrl
-	baseCtrl          *BaseCtrl
-	healthCtrl        *HealthCtrl
-	userCtrl          *UserCtrl
-	orgCtrl           *OrgCtrl
-	ojCtrl            *OJCtrl
-	ojTaskCtrl        *OJTaskCtrl
-	apiCtrl           *ApiCtrl
-	menuCtrl          *MenuCtrl
-	roleCtrl          *RoleCtrl
-	imageCtrl         *ImageCtrl
-	observabilityCtrl *ObservabilityCtrl
+	aiCtrl             *AICtrl
+	aiMemoryCtrl       *AIMemoryCtrl
+	aiKnowledgeCtrl    *AIKnowledgeCtrl
+	refreshTokenCtrl   *RefreshTokenCtrl
+	baseCtrl           *BaseCtrl
+	healthCtrl         *HealthCtrl
+	userCtrl           *UserCtrl
+	orgCtrl            *OrgCtrl
+	ojCtrl             *OJCtrl
+	ojTaskCtrl         *OJTaskCtrl
+	ojContestCtrl      *OJContestCtrl
+	ojQuestionBankCtrl *OJQuestionBankCtrl
+	apiCtrl            *ApiCtrl
+	menuCtrl           *MenuCtrl
+	roleCtrl           *RoleCtrl
+	imageCtrl          *ImageCtrl
+	observabilityCtrl  *ObservabilityCtrl
 }
 
 // GetAICtrl 用于获取当前场景需要的对象或数据。
@@ -153,6 +157,16 @@ func (c *controllerSupplier) GetOJTaskCtrl() *OJTaskCtrl {
 	return c.ojTaskCtrl
 }
 
+// GetOJContestCtrl 返回 OJ 比赛控制器。
+func (c *controllerSupplier) GetOJContestCtrl() *OJContestCtrl {
+	return c.ojContestCtrl
+}
+
+// GetOJQuestionBankCtrl 返回 OJ 题库目录控制器。
+func (c *controllerSupplier) GetOJQuestionBankCtrl() *OJQuestionBankCtrl {
+	return c.ojQuestionBankCtrl
+}
+
 // GetApiCtrl 用于获取当前场景需要的对象或数据。
 // 参数：
 //   - 无。
@@ -237,3 +251,17 @@ func (c *controllerSupplier) GetImageCtrl() *ImageCtrl {
 func (c *controllerSupplier) GetObservabilityCtrl() *ObservabilityCtrl {
 	return c.observabilityCtrl
 }
+
+// GetAIMemoryCtrl 用于获取 AI 记忆管理控制器。
+// 返回值：
+//   - *AIMemoryCtrl：记忆管理控制器实例。
+func (c *controllerSupplier) GetAIMemoryCtrl() *AIMemoryCtrl {
+	return c.aiMemoryCtrl
+}
+
+// GetAIKnowledgeCtrl 用于获取组织知识库控制器。
+// 返回值：
+//   - *AIKnowledgeCtrl：知识库控制器实例。
+func (c *controllerSupplier) GetAIKnowledgeCtrl() *AIKnowledgeCtrl {
+	return c.aiKnowledgeCtrl
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"personal_assistant/global"
+	"personal_assistant/internal/model/consts"
 	"personal_assistant/internal/model/dto/request"
 	resp "personal_assistant/internal/model/dto/response"
 	"personal_assistant/internal/model/entity"
@@ -22,6 +23,7 @@ import (
 type UserCtrl struct {
 	userService serviceContract.UserServiceContract
 	jwtService  serviceContract.JWTServiceContract
+	baseService serviceContract.BaseServiceContract // 校验会话中的邮箱验证码
 }
 
 // Register 注册
@@ -87,11 +89,59 @@ func (u *UserCtrl) Login(ctx *gin.Context) {
 		return
 	}
 
+	// 开启二次验证或被策略要求绑定的账号，先返回登录挑战，完成第二步后再签发令牌
+	challenge, err := u.userService.BeginTwoFactorLogin(ctx, user)
+	if err != nil {
+		global.Log.Error("创建二次验证挑战失败", zap.Uint("userID", user.ID), zap.Error(err))
+		response.BizFailWithError(err, ctx)
+		return
+	}
+	if challenge != nil {
+		response.BizOkWithDetailed(challenge, "请完成二次验证", ctx)
+		return
+	}
+
 	// 第三阶段：统一收口结果、状态更新或返回动作，保证对外行为一致。
 	// 把收尾逻辑显式标出来，可以降低后续维护时遗漏边界处理的风险。
 	u.TokenNext(ctx, *user)
 }
 
+// LoginTwoFactor 登录第二步：提交动态码或恢复码换取令牌
+func (u *UserCtrl) LoginTwoFactor(c *gin.Context) {
+	var req request.TwoFactorLoginReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("绑定数据错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	user, recoveryCodes, err := u.userService.CompleteTwoFactorLogin(c.Request.Context(), &req)
+	if err != nil {
+		global.Log.Warn("二次验证登录失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	u.issueLoginTokens(c, *user, recoveryCodes)
+}
+
+// LoginTwoFactorSetup 登录时被要求强制绑定二次验证：获取绑定密钥
+func (u *UserCtrl) LoginTwoFactorSetup(c *gin.Context) {
+	var req request.TwoFactorChallengeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("绑定数据错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	setup, err := u.userService.SetupTwoFactorByChallenge(c.Request.Context(), &req)
+	if err != nil {
+		global.Log.Warn("获取二次验证绑定密钥失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(setup, c)
+}
+
 // TokenNext 负责执行当前函数对应的核心逻辑。
 // 参数：
 //   - c：调用方传入的目标对象或配置实例。
@@ -107,10 +157,19 @@ func (u *UserCtrl) Login(ctx *gin.Context) {
 // 注意事项：
 //   - 具体细节需结合函数体与调用方一起理解；当前注释基于函数命名和上下文整理。
 func (u *UserCtrl) TokenNext(c *gin.Context, user entity.User) {
+	u.issueLoginTokens(c, user, nil)
+}
+
+// issueLoginTokens 签发登录令牌；recoveryCodes 非空时（登录时强制绑定二次验证）随登录响应一并返回
+func (u *UserCtrl) issueLoginTokens(c *gin.Context, user entity.User, recoveryCodes []string) {
 	// 第一阶段：先处理入口参数、依赖或前置状态，尽早挡住不能继续推进的情况。
 	// 把前置判断集中在这里，是为了避免后续主逻辑夹杂过多防御性分支。
 	helper := response.NewAPIHelper(c, "LoginTokenNext")
-	loginResp, refreshToken, refreshExpiresAt, jwtErr := u.jwtService.IssueLoginTokens(c.Request.Context(), user)
+	loginResp, refreshToken, refreshExpiresAt, jwtErr := u.jwtService.IssueLoginTokens(
+		c.Request.Context(),
+		user,
+		loginDeviceFromRequest(c),
+	)
 	// 第二阶段：进入当前函数的主体逻辑，逐步组装中间结果或推进状态。
 	// 这里单独分段，是为了让阅读者更容易看清主要业务动作发生的位置。
 	if jwtErr != nil {
@@ -122,15 +181,8 @@ func (u *UserCtrl) TokenNext(c *gin.Context, user entity.User) {
 	}
 
 	// 将刷新令牌写入HttpOnly Cookie（统一使用 jwt 包的辅助函数）
-	if refreshToken != "" {
-		nowMs := time.Now().UnixMilli()
-		ttlMs := refreshExpiresAt - nowMs
-		maxAge := 0
-		if ttlMs > 0 {
-			maxAge = int(ttlMs / 1000)
-		}
-		jwt.SetRefreshToken(c, refreshToken, maxAge)
-	}
+	setRefreshTokenCookie(c, refreshToken, refreshExpiresAt)
+	loginResp.RecoveryCodes = recoveryCodes
 
 	// 第三阶段：统一收口结果、状态更新或返回动作，保证对外行为一致。
 	// 把收尾逻辑显式标出来，可以降低后续维护时遗漏边界处理的风险。
@@ -139,28 +191,23 @@ func (u *UserCtrl) TokenNext(c *gin.Context, user entity.User) {
 		Success("登录成功", loginResp)
 }
 
-// Logout 登出：清除刷新令牌 Cookie
+// Logout 登出：吊销当前会话并清除刷新令牌 Cookie
 func (u *UserCtrl) Logout(c *gin.Context) {
 	// 读取必要信息（尽量复用已有的工具函数）
-	uid := jwt.GetUUID(c)
-	jwtStr := jwt.GetRefreshToken(c)
+	userID := jwt.GetUserID(c)
+	sessionID := jwt.GetSessionID(c)
 
 	// 清除刷新令牌 Cookie（HttpOnly）
 	jwt.ClearRefreshToken(c)
 
-	// 移除Redis中的登录状态（多点登录与单点是同一个场景）
-	if err := global.Redis.Del(c.Request.Context(), uid.String()).Err(); err != nil {
-		global.Log.Warn("Redis 删除登录状态失败",
-			zap.String("uuid", uid.String()),
-			zap.Error(err))
-	}
-
-	// 将当前刷新令牌加入黑名单（防止后续再使用）
-	if jwtStr != "" {
-		if err := u.jwtService.JoinInBlacklist(
+	// 吊销当前会话，会话内的访问令牌与刷新令牌一并失效
+	if sessionID != "" {
+		if err := u.jwtService.RevokeUserSession(
 			c.Request.Context(),
-			entity.JwtBlacklist{JWT: jwtStr}); err != nil {
-			global.Log.Warn("加入刷新令牌黑名单失败", zap.Error(err))
+			userID,
+			sessionID,
+			consts.UserSessionRevokeReasonLogout); err != nil {
+			global.Log.Warn("吊销登录会话失败", zap.String("sessionID", sessionID), zap.Error(err))
 		}
 	}
 	response.NewResponse[any, any](c).
@@ -169,6 +216,241 @@ func (u *UserCtrl) Logout(c *gin.Context) {
 			map[string]any{"message": "已成功退出登录"})
 }
 
+// ListSessions 查看当前账号已登录的设备会话
+func (u *UserCtrl) ListSessions(c *gin.Context) {
+	userID := jwt.GetUserID(c)
+	items, err := u.jwtService.ListUserSessions(c.Request.Context(), userID, jwt.GetSessionID(c))
+	if err != nil {
+		global.Log.Error("查询登录会话失败", zap.Uint("userID", userID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+
+	response.BizOkWithData(items, c)
+}
+
+// RevokeSession 下线指定设备会话
+func (u *UserCtrl) RevokeSession(c *gin.Context) {
+	userID := jwt.GetUserID(c)
+	sessionID := c.Param("session_id")
+	if err := u.jwtService.RevokeUserSession(
+		c.Request.Context(),
+		userID,
+		sessionID,
+		consts.UserSessionRevokeReasonUserRevoke,
+	); err != nil {
+		global.Log.Error("下线登录会话失败", zap.Uint("userID", userID), zap.String("sessionID", sessionID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	if sessionID == jwt.GetSessionID(c) {
+		jwt.ClearRefreshToken(c)
+	}
+
+	response.BizOkWithMessage("设备已下线", c)
+}
+
+// RevokeOtherSessions 下线除当前设备外的全部会话
+func (u *UserCtrl) RevokeOtherSessions(c *gin.Context) {
+	userID := jwt.GetUserID(c)
+	count, err := u.jwtService.RevokeOtherUserSessions(c.Request.Context(), userID, jwt.GetSessionID(c))
+	if err != nil {
+		global.Log.Error("下线其他登录会话失败", zap.Uint("userID", userID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+
+	response.BizOkWithData(map[string]int{"revoked": count}, c)
+}
+
+// GetTwoFactorStatus 查询当前账号的二次验证状态
+func (u *UserCtrl) GetTwoFactorStatus(c *gin.Context) {
+	userID := jwt.GetUserID(c)
+	status, err := u.userService.GetTwoFactorStatus(c.Request.Context(), userID)
+	if err != nil {
+		global.Log.Error("查询二次验证状态失败", zap.Uint("userID", userID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(status, c)
+}
+
+// SetupTwoFactor 生成待确认的二次验证密钥
+func (u *UserCtrl) SetupTwoFactor(c *gin.Context) {
+	userID := jwt.GetUserID(c)
+	setup, err := u.userService.SetupTwoFactor(c.Request.Context(), userID)
+	if err != nil {
+		global.Log.Error("生成二次验证密钥失败", zap.Uint("userID", userID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(setup, c)
+}
+
+// EnableTwoFactor 校验动态码并启用二次验证
+func (u *UserCtrl) EnableTwoFactor(c *gin.Context) {
+	var req request.TwoFactorCodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("绑定数据错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	codes, err := u.userService.EnableTwoFactor(c.Request.Context(), userID, &req)
+	if err != nil {
+		global.Log.Warn("启用二次验证失败", zap.Uint("userID", userID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(codes, "二次验证已启用，请妥善保存恢复码", c)
+}
+
+// DisableTwoFactor 校验动态码或恢复码后关闭二次验证
+func (u *UserCtrl) DisableTwoFactor(c *gin.Context) {
+	var req request.TwoFactorCodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("绑定数据错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	if err := u.userService.DisableTwoFactor(c.Request.Context(), userID, &req); err != nil {
+		global.Log.Warn("关闭二次验证失败", zap.Uint("userID", userID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithMessage("二次验证已关闭", c)
+}
+
+// RegenerateTwoFactorRecoveryCodes 重新生成恢复码
+func (u *UserCtrl) RegenerateTwoFactorRecoveryCodes(c *gin.Context) {
+	var req request.TwoFactorCodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("绑定数据错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	codes, err := u.userService.RegenerateTwoFactorRecoveryCodes(c.Request.Context(), userID, &req)
+	if err != nil {
+		global.Log.Warn("重新生成恢复码失败", zap.Uint("userID", userID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(codes, "恢复码已重新生成，旧恢复码全部失效", c)
+}
+
+// ListOAuthProviders 登录页可用的第三方登录方式
+func (u *UserCtrl) ListOAuthProviders(c *gin.Context) {
+	providers, err := u.userService.ListOAuthProviders(c.Request.Context())
+	if err != nil {
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(providers, c)
+}
+
+// OAuthAuthorize 发起第三方登录，返回授权地址
+func (u *UserCtrl) OAuthAuthorize(c *gin.Context) {
+	provider := c.Param("provider")
+	authorize, err := u.userService.BeginOAuthLogin(c.Request.Context(), provider)
+	if err != nil {
+		global.Log.Warn("发起第三方登录失败", zap.String("provider", provider), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(authorize, c)
+}
+
+// OAuthCallback 第三方登录回调：校验通过后与密码登录一样进入二次验证判断，再签发令牌
+func (u *UserCtrl) OAuthCallback(c *gin.Context) {
+	var req request.OAuthCallbackReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("绑定数据错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	provider := c.Param("provider")
+	user, err := u.userService.CompleteOAuthLogin(c.Request.Context(), provider, &req)
+	if err != nil {
+		global.Log.Warn("第三方登录失败", zap.String("provider", provider), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+
+	challenge, err := u.userService.BeginTwoFactorLogin(c.Request.Context(), user)
+	if err != nil {
+		global.Log.Error("创建二次验证挑战失败", zap.Uint("userID", user.ID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	if challenge != nil {
+		response.BizOkWithDetailed(challenge, "请完成二次验证", c)
+		return
+	}
+	u.TokenNext(c, *user)
+}
+
+// GetOAuthBinding 查询当前账号的第三方账号绑定状态
+func (u *UserCtrl) GetOAuthBinding(c *gin.Context) {
+	userID := jwt.GetUserID(c)
+	binding, err := u.userService.GetOAuthBinding(c.Request.Context(), userID)
+	if err != nil {
+		global.Log.Error("查询第三方账号绑定失败", zap.Uint("userID", userID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(binding, c)
+}
+
+// OAuthLinkAuthorize 已登录用户发起第三方账号绑定，返回授权地址
+func (u *UserCtrl) OAuthLinkAuthorize(c *gin.Context) {
+	userID := jwt.GetUserID(c)
+	provider := c.Param("provider")
+	authorize, err := u.userService.BeginOAuthLink(c.Request.Context(), userID, provider)
+	if err != nil {
+		global.Log.Warn("发起第三方账号绑定失败", zap.Uint("userID", userID), zap.String("provider", provider), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithData(authorize, c)
+}
+
+// OAuthLinkCallback 第三方账号绑定回调
+func (u *UserCtrl) OAuthLinkCallback(c *gin.Context) {
+	var req request.OAuthCallbackReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("绑定数据错误", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, "参数绑定失败", c)
+		return
+	}
+
+	userID := jwt.GetUserID(c)
+	provider := c.Param("provider")
+	binding, err := u.userService.CompleteOAuthLink(c.Request.Context(), userID, provider, &req)
+	if err != nil {
+		global.Log.Warn("绑定第三方账号失败", zap.Uint("userID", userID), zap.String("provider", provider), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithDetailed(binding, "第三方账号绑定成功", c)
+}
+
+// UnlinkOAuth 解绑第三方账号
+func (u *UserCtrl) UnlinkOAuth(c *gin.Context) {
+	userID := jwt.GetUserID(c)
+	if err := u.userService.UnlinkOAuth(c.Request.Context(), userID); err != nil {
+		global.Log.Warn("解绑第三方账号失败", zap.Uint("userID", userID), zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+	response.BizOkWithMessage("第三方账号已解绑", c)
+}
+
 // UpdateProfile 更新个人资料
 func (u *UserCtrl) UpdateProfile(c *gin.Context) {
 	var req request.UpdateProfileReq
@@ -209,6 +491,29 @@ func (u *UserCtrl) ChangePhone(c *gin.Context) {
 	response.BizOkWithData(entityToUserDetail(user), c)
 }
 
+// BindEmail 绑定或换绑邮箱：校验发往新邮箱的验证码后写入账号
+func (u *UserCtrl) BindEmail(c *gin.Context) {
+	var req request.BindEmailReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+	if err := u.baseService.ConsumeEmailVerificationCode(c, req.Email, req.VerificationCode); err != nil {
+		response.BizFailWithError(err, c)
+		return
+	}
+
+	user, err := u.userService.BindEmail(c.Request.Context(), jwt.GetUserID(c), req.Email)
+	if err != nil {
+		global.Log.Error("绑定邮箱失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+
+	response.BizOkWithData(entityToUserDetail(user), c)
+}
+
 // ChangePassword 修改密码
 func (u *UserCtrl) ChangePassword(c *gin.Context) {
 	var req request.ChangePasswordReq
@@ -228,6 +533,43 @@ func (u *UserCtrl) ChangePassword(c *gin.Context) {
 	response.BizOkWithMessage("密码修改成功，请重新登录", c)
 }
 
+// SendPasswordResetCode 找回密码：发送邮箱验证码
+func (u *UserCtrl) SendPasswordResetCode(c *gin.Context) {
+	var req request.SendPasswordResetCodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+
+	if err := u.userService.SendPasswordResetCode(c.Request.Context(), store, c.ClientIP(), &req); err != nil {
+		global.Log.Error("发送找回密码验证码失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+
+	response.BizOkWithMessage("若账号存在且已绑定邮箱，验证码已发送", c)
+}
+
+// ResetPassword 找回密码：校验验证码并设置新密码
+func (u *UserCtrl) ResetPassword(c *gin.Context) {
+	var req request.ResetPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		global.Log.Error("参数绑定失败", zap.Error(err))
+		response.BizFailWithCodeMsg(bizerrors.CodeBindFailed, err.Error(), c)
+		return
+	}
+
+	if err := u.userService.ResetPassword(c.Request.Context(), &req); err != nil {
+		global.Log.Error("重置密码失败", zap.Error(err))
+		response.BizFailWithError(err, c)
+		return
+	}
+
+	jwt.ClearRefreshToken(c)
+	response.BizOkWithMessage("密码已重置，请重新登录", c)
+}
+
 // DeactivateAccount 主动注销账号（等同禁用）
 func (u *UserCtrl) DeactivateAccount(c *gin.Context) {
 	var req request.DeactivateAccountReq
@@ -464,3 +806,25 @@ func entityToUserDetail(user *entity.User) *resp.UserDetailItem {
 	// 把收尾逻辑显式标出来，可以降低后续维护时遗漏边界处理的风险。
 	return item
 }
+
+// loginDeviceFromRequest 从请求中提取会话设备信息，设备名由客户端通过 X-Device-Label 头上报
+func loginDeviceFromRequest(c *gin.Context) request.LoginDevice {
+	return request.LoginDevice{
+		Label:     c.GetHeader("X-Device-Label"),
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// setRefreshTokenCookie 按刷新令牌过期时间（毫秒）写入 HttpOnly Cookie
+func setRefreshTokenCookie(c *gin.Context, refreshToken string, refreshExpiresAt int64) {
+	if refreshToken == "" {
+		return
+	}
+	ttlMs := refreshExpiresAt - time.Now().UnixMilli()
+	maxAge := 0
+	if ttlMs > 0 {
+		maxAge = int(ttlMs / 1000)
+	}
+	jwt.SetRefreshToken(c, refreshToken, maxAge)
+}
@@ -37,6 +37,7 @@ func InitConfig(path string) {
 	viper.SetDefault("task.disabled_user_cleanup_enabled", true)
 	viper.SetDefault("task.disabled_user_retention_days", 30)
 	viper.SetDefault("task.disabled_user_cleanup_cron", "@daily")
+	viper.SetDefault("task.ai_memory_reindex_interval_seconds", 60)
 	viper.SetDefault("messaging.outbox_relay_lock_enabled", true)
 	viper.SetDefault("messaging.outbox_relay_lock_ttl_seconds", 15)
 	viper.SetDefault("messaging.oj_question_upsert_topic", "oj_question_upsert")
@@ -81,13 +82,19 @@ func InitConfig(path string) {
 	viper.SetDefault("ai.memory.extract_timeout_seconds", 20)
 	viper.SetDefault("ai.memory.extract_max_chars", 6000)
 	viper.SetDefault("ai.memory.tool_output_token_budget", 800)
-	viper.SetDefault("ai.memory.embed_model", "qwen3-vl-embedding")
+	viper.SetDefault("ai.memory.embed_provider", "dashscope")
 	viper.SetDefault("ai.memory.embed_endpoint", "https://dashscope.aliyuncs.com/api/v1/services/embeddings/multimodal-embedding/multimodal-embedding")
 	viper.SetDefault("ai.memory.embed_dimension", 1024)
+	viper.SetDefault("ai.memory.vector_store", "auto")
 	viper.SetDefault("ai.memory.chunk_max_chars", 1200)
 	viper.SetDefault("ai.memory.chunk_overlap_chars", 150)
 	viper.SetDefault("ai.memory.index_batch_size", 20)
 	viper.SetDefault("ai.memory.index_timeout_seconds", 30)
+	viper.SetDefault("ai.memory.lexical_recall_enabled", true)
+	viper.SetDefault("ai.memory.rerank_provider", "none")
+	viper.SetDefault("ai.memory.rerank_top_n", 20)
+	viper.SetDefault("ai.memory.rerank_timeout_seconds", 10)
+	viper.SetDefault("ai.memory.recall_trace", false)
 	viper.SetDefault("qdrant.enabled", true)
 	viper.SetDefault("qdrant.endpoint", "")
 	viper.SetDefault("qdrant.grpc_host", "")
@@ -172,6 +179,14 @@ func InitConfig(path string) {
 	_ = viper.BindEnv("crawler.lanqiao.retry_wait_ms", "LANQIAO_RETRY_WAIT_MS")
 	_ = viper.BindEnv("crawler.lanqiao.retry_max_wait_ms", "LANQIAO_RETRY_MAX_WAIT_MS")
 	_ = viper.BindEnv("crawler.lanqiao.response_body_limit_bytes", "LANQIAO_RESPONSE_BODY_LIMIT_BYTES")
+	_ = viper.BindEnv("crawler.codeforces.base_url", "CRAWLER_CODEFORCES_BASE_URL", "CODEFORCES_BASE_URL")
+	_ = viper.BindEnv("crawler.codeforces.api_prefix", "CRAWLER_CODEFORCES_API_PREFIX")
+	_ = viper.BindEnv("crawler.codeforces.timeout_ms", "CODEFORCES_TIMEOUT_MS")
+	_ = viper.BindEnv("crawler.codeforces.retry_count", "CODEFORCES_RETRY_COUNT")
+	_ = viper.BindEnv("crawler.atcoder.base_url", "CRAWLER_ATCODER_BASE_URL", "ATCODER_BASE_URL")
+	_ = viper.BindEnv("crawler.atcoder.api_prefix", "CRAWLER_ATCODER_API_PREFIX")
+	_ = viper.BindEnv("crawler.atcoder.timeout_ms", "ATCODER_TIMEOUT_MS")
+	_ = viper.BindEnv("crawler.atcoder.retry_count", "ATCODER_RETRY_COUNT")
 	_ = viper.BindEnv("storage.qiniu.bucket", "STORAGE_QINIU_BUCKET")
 	_ = viper.BindEnv("storage.qiniu.domain", "STORAGE_QINIU_DOMAIN")
 	_ = viper.BindEnv("storage.qiniu.key_prefix", "STORAGE_QINIU_KEY_PREFIX")
@@ -252,6 +267,7 @@ func InitConfig(path string) {
 	_ = viper.BindEnv("task.disabled_user_cleanup_enabled", "TASK_DISABLED_USER_CLEANUP_ENABLED")
 	_ = viper.BindEnv("task.disabled_user_retention_days", "TASK_DISABLED_USER_RETENTION_DAYS")
 	_ = viper.BindEnv("task.disabled_user_cleanup_cron", "TASK_DISABLED_USER_CLEANUP_CRON")
+	_ = viper.BindEnv("task.ai_memory_reindex_interval_seconds", "TASK_AI_MEMORY_REINDEX_INTERVAL_SECONDS")
 	_ = viper.BindEnv("messaging.redis_stream_read_count", "MESSAGING_REDIS_STREAM_READ_COUNT")
 	_ = viper.BindEnv("messaging.redis_stream_block_ms", "MESSAGING_REDIS_STREAM_BLOCK_MS")
 	_ = viper.BindEnv("messaging.outbox_relay_lock_enabled", "MESSAGING_OUTBOX_RELAY_LOCK_ENABLED")
@@ -309,13 +325,25 @@ func InitConfig(path string) {
 	_ = viper.BindEnv("ai.memory.extract_timeout_seconds", "AI_MEMORY_EXTRACT_TIMEOUT_SECONDS")
 	_ = viper.BindEnv("ai.memory.extract_max_chars", "AI_MEMORY_EXTRACT_MAX_CHARS")
 	_ = viper.BindEnv("ai.memory.tool_output_token_budget", "AI_MEMORY_TOOL_OUTPUT_TOKEN_BUDGET")
+	_ = viper.BindEnv("ai.memory.embed_provider", "AI_MEMORY_EMBED_PROVIDER")
+	_ = viper.BindEnv("ai.memory.embed_api_key", "AI_MEMORY_EMBED_API_KEY")
+	_ = viper.BindEnv("ai.memory.embed_base_url", "AI_MEMORY_EMBED_BASE_URL")
 	_ = viper.BindEnv("ai.memory.embed_model", "AI_MEMORY_EMBED_MODEL")
 	_ = viper.BindEnv("ai.memory.embed_endpoint", "AI_MEMORY_EMBED_ENDPOINT")
 	_ = viper.BindEnv("ai.memory.embed_dimension", "AI_MEMORY_EMBED_DIMENSION")
+	_ = viper.BindEnv("ai.memory.vector_store", "AI_MEMORY_VECTOR_STORE")
 	_ = viper.BindEnv("ai.memory.chunk_max_chars", "AI_MEMORY_CHUNK_MAX_CHARS")
 	_ = viper.BindEnv("ai.memory.chunk_overlap_chars", "AI_MEMORY_CHUNK_OVERLAP_CHARS")
 	_ = viper.BindEnv("ai.memory.index_batch_size", "AI_MEMORY_INDEX_BATCH_SIZE")
 	_ = viper.BindEnv("ai.memory.index_timeout_seconds", "AI_MEMORY_INDEX_TIMEOUT_SECONDS")
+	_ = viper.BindEnv("ai.memory.lexical_recall_enabled", "AI_MEMORY_LEXICAL_RECALL_ENABLED")
+	_ = viper.BindEnv("ai.memory.rerank_provider", "AI_MEMORY_RERANK_PROVIDER")
+	_ = viper.BindEnv("ai.memory.rerank_model", "AI_MEMORY_RERANK_MODEL")
+	_ = viper.BindEnv("ai.memory.rerank_base_url", "AI_MEMORY_RERANK_BASE_URL")
+	_ = viper.BindEnv("ai.memory.rerank_api_key", "AI_MEMORY_RERANK_API_KEY")
+	_ = viper.BindEnv("ai.memory.rerank_top_n", "AI_MEMORY_RERANK_TOP_N")
+	_ = viper.BindEnv("ai.memory.rerank_timeout_seconds", "AI_MEMORY_RERANK_TIMEOUT_SECONDS")
+	_ = viper.BindEnv("ai.memory.recall_trace", "AI_MEMORY_RECALL_TRACE")
 	_ = viper.BindEnv("qdrant.enabled", "QDRANT_ENABLED")
 	_ = viper.BindEnv("qdrant.endpoint", "QDRANT_ENDPOINT")
 	_ = viper.BindEnv("qdrant.grpc_host", "QDRANT_GRPC_HOST")
@@ -446,4 +474,7 @@ func InitConfig(path string) {
 	global.Log.Info("-----------------------------\n")
 	// 传递到全局
 	global.Config = config.NewConfig()
+	if err := global.Config.OAuth.Validate(); err != nil {
+		global.Log.Fatal("第三方登录配置无效", zap.Error(err))
+	}
 }
@@ -7,6 +7,7 @@ import (
 	"go.uber.org/zap"
 
 	"personal_assistant/global"
+	aimemory "personal_assistant/internal/infrastructure/ai/memory"
 )
 
 func TestInitConfigBindsAIMemoryAndQdrantCompatibility(t *testing.T) {
@@ -32,6 +33,8 @@ func TestInitConfigBindsAIMemoryAndQdrantCompatibility(t *testing.T) {
 	t.Setenv("AI_MEMORY_TOOL_OUTPUT_TOKEN_BUDGET", "256")
 	t.Setenv("AI_MEMORY_EMBED_DIMENSION", "1024")
 	t.Setenv("AI_MEMORY_INDEX_BATCH_SIZE", "11")
+	t.Setenv("AI_MEMORY_RERANK_PROVIDER", "http")
+	t.Setenv("AI_MEMORY_RERANK_TOP_N", "12")
 	t.Setenv("QDRANT_COLLECTION_NAME", "legacy-knowledge")
 	t.Setenv("QDRANT_MEMORY_COLLECTION_NAME", "memory-chunks")
 
@@ -85,8 +88,16 @@ func TestInitConfigBindsAIMemoryAndQdrantCompatibility(t *testing.T) {
 			global.Config.AI.Memory.SummaryRefreshEveryTurns,
 		)
 	}
-	if global.Config.AI.Memory.EmbedModel != "qwen3-vl-embedding" {
-		t.Fatalf("AI.Memory.EmbedModel = %q, want qwen3-vl-embedding", global.Config.AI.Memory.EmbedModel)
+	if global.Config.AI.Memory.EmbedProvider != "dashscope" {
+		t.Fatalf("AI.Memory.EmbedProvider = %q, want dashscope", global.Config.AI.Memory.EmbedProvider)
+	}
+	space := aimemory.NormalizeEmbeddingSpace(
+		global.Config.AI.Memory.EmbedProvider,
+		global.Config.AI.Memory.EmbedModel,
+		global.Config.AI.Memory.EmbedDimension,
+	)
+	if space.Model != "qwen3-vl-embedding" {
+		t.Fatalf("resolved embed model = %q, want qwen3-vl-embedding", space.Model)
 	}
 	if global.Config.AI.Memory.EmbedDimension != 1024 {
 		t.Fatalf("AI.Memory.EmbedDimension = %d, want 1024", global.Config.AI.Memory.EmbedDimension)
@@ -94,6 +105,18 @@ func TestInitConfigBindsAIMemoryAndQdrantCompatibility(t *testing.T) {
 	if global.Config.AI.Memory.IndexBatchSize != 11 {
 		t.Fatalf("AI.Memory.IndexBatchSize = %d, want 11", global.Config.AI.Memory.IndexBatchSize)
 	}
+	if !global.Config.AI.Memory.LexicalRecallEnabled {
+		t.Fatal("AI.Memory.LexicalRecallEnabled = false, want default true")
+	}
+	if global.Config.AI.Memory.RerankProvider != "http" {
+		t.Fatalf("AI.Memory.RerankProvider = %q, want http", global.Config.AI.Memory.RerankProvider)
+	}
+	if global.Config.AI.Memory.RerankTopN != 12 {
+		t.Fatalf("AI.Memory.RerankTopN = %d, want 12", global.Config.AI.Memory.RerankTopN)
+	}
+	if global.Config.AI.Memory.RecallTrace {
+		t.Fatal("AI.Memory.RecallTrace = true, want default false")
+	}
 	if global.Config.Qdrant.CollectionName != "legacy-knowledge" {
 		t.Fatalf("Qdrant.CollectionName = %q, want %q", global.Config.Qdrant.CollectionName, "legacy-knowledge")
 	}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"time"
 
 	"personal_assistant/global"
 	"personal_assistant/internal/repository"
@@ -9,7 +10,7 @@ import (
 	"go.uber.org/zap"
 )
 
-// LoadJWTBlacklistWithRepository 启动阶段预热 JWT 黑名单到本地缓存。
+// LoadJWTBlacklistWithRepository 启动阶段把仍未过期的被吊销会话预热到本地缓存，缓存键为会话标识。
 func LoadJWTBlacklistWithRepository(ctx context.Context, repositoryGroup *repository.Group) {
 	if repositoryGroup == nil || repositoryGroup.SystemRepositorySupplier == nil {
 		return
@@ -18,7 +19,7 @@ func LoadJWTBlacklistWithRepository(ctx context.Context, repositoryGroup *reposi
 	if jwtRepo == nil {
 		return
 	}
-	data, err := jwtRepo.GetAllJwtBlacklist(ctx)
+	data, err := jwtRepo.ListBlacklistedSessionIDs(ctx, time.Now())
 	if err != nil {
 		global.Log.Error("failed to load jwt blacklist from repository", zap.Error(err))
 		return
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"personal_assistant/global"
+	aimemory "personal_assistant/internal/infrastructure/ai/memory"
 	"personal_assistant/internal/model/config"
 
 	"github.com/qdrant/go-client/qdrant"
@@ -92,55 +93,68 @@ func newQdrantClient(qdrantCfg config.Qdrant) (*qdrant.Client, error) {
 	return client, nil
 }
 
-// ensureQdrantCollection 确保配置指定的单向量 collection 已存在且 schema 匹配。
+// ensureQdrantCollections 确保知识库 collection 与当前 embedding 空间对应的 memory collection 均可用。
 //
 // 核心流程：
-//  1. 校验 collection 名称、向量维度和距离算法配置。
-//  2. 调用 CollectionExists 判断是否已存在。
-//  3. 不存在则按配置创建；已存在则读取 collection 信息并校验向量参数。
+//  1. 知识库 collection 使用 qdrant.vector_size。
+//  2. memory collection 名称由 qdrant.memory_collection_name 与 embedding 空间共同决定，
+//     向量维度取 ai.memory.embed_dimension，而不是 qdrant.vector_size。
+//  3. memory collection 与知识库 collection 同名时，两侧维度必须一致。
 //
 // 生产约束：
-//   - 已存在 collection 的维度或距离算法不匹配时必须返回错误。
-//   - 这里不自动删除或重建 collection，避免误删线上已有向量数据。
+//   - 切换 embedding provider / model / dimension 会得到新的 collection 名称，旧 collection 原样保留。
+//   - 已存在 collection 的维度或距离算法不匹配时必须返回错误，不自动删除或重建。
 func ensureQdrantCollections(
 	ctx context.Context,
 	client *qdrant.Client,
 	qdrantCfg config.Qdrant,
 ) error {
-	if err := ensureQdrantCollection(ctx, client, qdrantCfg, qdrantCfg.CollectionName); err != nil {
+	knowledgeCollectionName := strings.TrimSpace(qdrantCfg.CollectionName)
+	if err := ensureQdrantCollection(ctx, client, qdrantCfg, knowledgeCollectionName, qdrantCfg.VectorSize); err != nil {
 		return err
 	}
 	memoryCollectionName := strings.TrimSpace(qdrantCfg.MemoryCollectionName)
 	if memoryCollectionName == "" {
-		memoryCollectionName = strings.TrimSpace(qdrantCfg.CollectionName)
+		memoryCollectionName = knowledgeCollectionName
 	}
-	if memoryCollectionName == "" || memoryCollectionName == strings.TrimSpace(qdrantCfg.CollectionName) {
+	if memoryCollectionName == "" || global.Config == nil {
 		return nil
 	}
-	if global.Config != nil &&
-		global.Config.AI.Memory.EmbedDimension > 0 &&
-		global.Config.AI.Memory.EmbedDimension != qdrantCfg.VectorSize {
-		return fmt.Errorf(
-			"qdrant memory collection vector size mismatch: qdrant.vector_size=%d ai.memory.embed_dimension=%d",
-			qdrantCfg.VectorSize,
-			global.Config.AI.Memory.EmbedDimension,
-		)
+	memoryCfg := global.Config.AI.Memory
+	space := aimemory.NormalizeEmbeddingSpace(memoryCfg.EmbedProvider, memoryCfg.EmbedModel, memoryCfg.EmbedDimension)
+	memoryCollectionName = aimemory.ResolveMemoryCollectionName(memoryCollectionName, space)
+	if memoryCollectionName == knowledgeCollectionName {
+		if space.Dimension != qdrantCfg.VectorSize {
+			return fmt.Errorf(
+				"qdrant memory collection vector size mismatch: qdrant.vector_size=%d ai.memory.embed_dimension=%d",
+				qdrantCfg.VectorSize,
+				space.Dimension,
+			)
+		}
+		return nil
 	}
-	return ensureQdrantCollection(ctx, client, qdrantCfg, memoryCollectionName)
+	return ensureQdrantCollection(ctx, client, qdrantCfg, memoryCollectionName, space.Dimension)
 }
 
+// ensureQdrantCollection 确保指定单向量 collection 已存在且 schema 匹配。
+//
+// 核心流程：
+//  1. 校验 collection 名称、向量维度和距离算法配置。
+//  2. 调用 CollectionExists 判断是否已存在。
+//  3. 不存在则按配置创建；已存在则读取 collection 信息并校验向量参数。
 func ensureQdrantCollection(
 	ctx context.Context,
 	client *qdrant.Client,
 	qdrantCfg config.Qdrant,
 	collectionName string,
+	vectorSize int,
 ) error {
 	collectionName = strings.TrimSpace(collectionName)
 	if collectionName == "" {
 		return errors.New("qdrant collection name is empty")
 	}
-	if qdrantCfg.VectorSize <= 0 {
-		return fmt.Errorf("qdrant vector size must be positive: %d", qdrantCfg.VectorSize)
+	if vectorSize <= 0 {
+		return fmt.Errorf("qdrant vector size must be positive: %d", vectorSize)
 	}
 	distance, err := parseQdrantDistance(qdrantCfg.Distance)
 	if err != nil {
@@ -155,7 +169,7 @@ func ensureQdrantCollection(
 		if err := client.CreateCollection(ctx, &qdrant.CreateCollection{
 			CollectionName: collectionName,
 			VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
-				Size:     uint64(qdrantCfg.VectorSize),
+				Size:     uint64(vectorSize),
 				Distance: distance,
 			}),
 		}); err != nil {
@@ -174,13 +188,13 @@ func ensureQdrantCollection(
 	if params == nil {
 		return fmt.Errorf("qdrant collection %q is not a single-vector collection", collectionName)
 	}
-	if params.GetSize() != uint64(qdrantCfg.VectorSize) || params.GetDistance() != distance {
+	if params.GetSize() != uint64(vectorSize) || params.GetDistance() != distance {
 		return fmt.Errorf(
 			"qdrant collection %q config mismatch: got size=%d distance=%s, want size=%d distance=%s",
 			collectionName,
 			params.GetSize(),
 			params.GetDistance().String(),
-			qdrantCfg.VectorSize,
+			vectorSize,
 			distance.String(),
 		)
 	}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"personal_assistant/global"
+	"personal_assistant/internal/model/consts"
 	"personal_assistant/pkg/ratelimit"
 
 	"go.uber.org/zap"
@@ -70,13 +71,81 @@ func InitOJBindRateLimiters() {
 	}
 
 	window := time.Duration(windowSec) * time.Second
-	global.OJBindLimiters = map[string]*ratelimit.SlidingWindowLimiter{
-		"leetcode": ratelimit.NewSlidingWindowLimiter(global.Redis, "ratelimit:oj_bind:leetcode", limit, window),
-		"luogu":    ratelimit.NewSlidingWindowLimiter(global.Redis, "ratelimit:oj_bind:luogu", limit, window),
-		"lanqiao":  ratelimit.NewSlidingWindowLimiter(global.Redis, "ratelimit:oj_bind:lanqiao", limit, window),
+	platforms := consts.SupportedOJPlatforms()
+	limiters := make(map[string]*ratelimit.SlidingWindowLimiter, len(platforms))
+	for _, platform := range platforms {
+		limiters[platform] = ratelimit.NewSlidingWindowLimiter(global.Redis, "ratelimit:oj_bind:"+platform, limit, window)
 	}
+	global.OJBindLimiters = limiters
 
 	global.Log.Info("OJ 绑定限流器初始化完成",
 		zap.Int("limit", limit),
 		zap.Int("window_sec", windowSec))
 }
+
+// InitPasswordResetRateLimiters 初始化找回密码接口的 IP 级与账号级滑动窗口限流器。
+func InitPasswordResetRateLimiters() {
+	cfg := global.Config.RateLimit.PasswordReset
+
+	ipLimit := cfg.IPLimit
+	if ipLimit <= 0 {
+		ipLimit = 20
+	}
+	ipWindowSec := cfg.IPWindowSec
+	if ipWindowSec <= 0 {
+		ipWindowSec = 1800
+	}
+	accountLimit := cfg.AccountLimit
+	if accountLimit <= 0 {
+		accountLimit = 6
+	}
+	accountWindowSec := cfg.AccountWindowSec
+	if accountWindowSec <= 0 {
+		accountWindowSec = 1800
+	}
+
+	global.PasswordResetIPLimiter = ratelimit.NewSlidingWindowLimiter(
+		global.Redis,
+		"ratelimit:password_reset:ip",
+		ipLimit,
+		time.Duration(ipWindowSec)*time.Second,
+	)
+	global.PasswordResetAccountLimiter = ratelimit.NewSlidingWindowLimiter(
+		global.Redis,
+		"ratelimit:password_reset:account",
+		accountLimit,
+		time.Duration(accountWindowSec)*time.Second,
+	)
+
+	global.Log.Info("找回密码限流器初始化完成",
+		zap.Int("ip_limit", ipLimit),
+		zap.Int("ip_window_sec", ipWindowSec),
+		zap.Int("account_limit", accountLimit),
+		zap.Int("account_window_sec", accountWindowSec))
+}
+
+// InitTwoFactorLoginRateLimiter 初始化登录二次验证接口的 IP 级滑动窗口限流器。
+// 单个挑战的校验次数已由挑战本身限制，这里限制同一来源反复换挑战猜测动态码。
+func InitTwoFactorLoginRateLimiter() {
+	cfg := global.Config.RateLimit.TwoFactorLogin
+
+	ipLimit := cfg.IPLimit
+	if ipLimit <= 0 {
+		ipLimit = 30
+	}
+	ipWindowSec := cfg.IPWindowSec
+	if ipWindowSec <= 0 {
+		ipWindowSec = 600
+	}
+
+	global.TwoFactorLoginIPLimiter = ratelimit.NewSlidingWindowLimiter(
+		global.Redis,
+		"ratelimit:two_factor_login:ip",
+		ipLimit,
+		time.Duration(ipWindowSec)*time.Second,
+	)
+
+	global.Log.Info("登录二次验证限流器初始化完成",
+		zap.Int("ip_limit", ipLimit),
+		zap.Int("ip_window_sec", ipWindowSec))
+}
@@ -1,12 +1,19 @@
 package core
 
 import (
+	"context"
+	"errors"
+	"sync"
 	"time"
 
 	"personal_assistant/global"
 	"personal_assistant/internal/infrastructure/sse"
+
+	"go.uber.org/zap"
 )
 
+var sseBackplaneRelayOnce sync.Once
+
 // InitSSEInfrastructure 负责根据全局配置初始化 SSE 运行时基础设施。
 // 参数：无。
 // 返回值：无。
@@ -47,3 +54,32 @@ func InitSSEInfrastructure() {
 		cfg.PubSubChannelPrefix,
 	)
 }
+
+// StartSSEBackplaneRelay 订阅跨实例背板，把频道级事件转投到本机 Broker。
+// 频道广播（如比赛实时榜单）由任意实例的后台任务产生，连接却分散在各个实例上；
+// 发布方只写背板，各实例（含自身）通过这里统一投递本地连接，避免重复推送。
+func StartSSEBackplaneRelay(ctx context.Context) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	infra := global.StreamInfra
+	if infra == nil || infra.Backplane == nil || infra.Broker == nil {
+		return
+	}
+
+	sseBackplaneRelayOnce.Do(func() {
+		go func() {
+			err := infra.Backplane.Subscribe(ctx, func(_ context.Context, evt *sse.StreamEvent) error {
+				// 会话级事件仍由产生它的实例直接写出，这里只转投频道事件。
+				if evt == nil || evt.StreamKind != sse.StreamKindChannel || evt.Channel == "" {
+					return nil
+				}
+				infra.Broker.PublishToChannel(evt.Channel, evt)
+				return nil
+			})
+			if err != nil && !errors.Is(err, context.Canceled) {
+				global.Log.Error("SSE backplane relay 运行失败", zap.Error(err))
+			}
+		}()
+	})
+}
@@ -0,0 +1,19 @@
+package core
+
+import (
+	"fmt"
+
+	"personal_assistant/internal/model/dto/request"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// InitValidators 向 gin 的默认校验引擎注册请求 DTO 使用的自定义校验标签，必须在路由初始化之前调用。
+func InitValidators() error {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("unexpected gin validator engine %T", binding.Validator.Engine())
+	}
+	return request.RegisterValidators(engine)
+}
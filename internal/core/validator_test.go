@@ -0,0 +1,36 @@
+package core
+
+import (
+	"testing"
+
+	"personal_assistant/internal/model/dto/request"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestInitValidatorsRegistersOJPlatformTags(t *testing.T) {
+	if err := InitValidators(); err != nil {
+		t.Fatalf("InitValidators() error = %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		value any
+		ok    bool
+	}{
+		{"ranking all", &request.OJRankingListReq{Platform: "all"}, true},
+		{"ranking provider", &request.OJRankingListReq{Platform: "atcoder"}, true},
+		{"ranking unknown", &request.OJRankingListReq{Platform: "poj"}, false},
+		{"bind builtin", &request.BindOJAccountReq{Platform: "luogu", Identifier: "x"}, true},
+		{"bind provider", &request.BindOJAccountReq{Platform: "codeforces", Identifier: "x"}, true},
+		{"bind lanqiao uses dedicated route", &request.BindOJAccountReq{Platform: "lanqiao", Identifier: "x"}, false},
+		{"unbind lanqiao", &request.UnbindOJAccountReq{Platform: "lanqiao"}, true},
+		{"unbind uppercase", &request.UnbindOJAccountReq{Platform: "LUOGU"}, false},
+	}
+	for _, tc := range cases {
+		err := binding.Validator.ValidateStruct(tc.value)
+		if (err == nil) != tc.ok {
+			t.Fatalf("%s: ValidateStruct() error = %v, want ok=%v", tc.name, err, tc.ok)
+		}
+	}
+}
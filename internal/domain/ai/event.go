@@ -20,6 +20,12 @@ const (
 	// EventToolCallFinished 表示一次工具调用已经结束。
 	EventToolCallFinished EventName = "tool_call_finished"
 
+	// EventToolCallWaitingConfirmation 表示工具调用已被拦截，等待用户确认。
+	EventToolCallWaitingConfirmation EventName = "tool_call_waiting_confirmation"
+
+	// EventToolCallConfirmationResult 表示用户已对等待确认的工具调用做出决策。
+	EventToolCallConfirmationResult EventName = "tool_call_confirmation_result"
+
 	// EventError 表示 runtime 执行过程中出现可下发给前端的错误。
 	EventError EventName = "error"
 
@@ -84,6 +90,34 @@ type ToolCallFinishedPayload struct {
 	DetailMarkdown string `json:"detail_markdown,omitempty"`
 }
 
+// ToolCallWaitingConfirmationPayload 表示工具调用等待确认事件的载荷。
+type ToolCallWaitingConfirmationPayload struct {
+	// Key 是被拦截工具调用在 trace_items 中的稳定主键。
+	Key string `json:"key"`
+	// InterruptID 是持久化中断记录的主键，前端用它调用决策接口。
+	InterruptID string `json:"interrupt_id"`
+	// ToolName 是等待确认的工具名。
+	ToolName string `json:"tool_name,omitempty"`
+	// Title 是确认卡片标题。
+	Title string `json:"title"`
+	// Description 是确认卡片说明。
+	Description string `json:"description"`
+	// DetailMarkdown 是执行预览。
+	DetailMarkdown string `json:"detail_markdown,omitempty"`
+}
+
+// ToolCallConfirmationResultPayload 表示工具确认结果事件的载荷。
+type ToolCallConfirmationResultPayload struct {
+	// Key 是被确认工具调用在 trace_items 中的稳定主键。
+	Key string `json:"key"`
+	// InterruptID 是本次决策对应的中断记录主键。
+	InterruptID string `json:"interrupt_id"`
+	// Decision 是用户决策，取值 approve 或 reject。
+	Decision string `json:"decision"`
+	// Reason 是用户填写的可选理由。
+	Reason string `json:"reason,omitempty"`
+}
+
 // ErrorPayload 表示 AI 流式执行失败时的事件载荷。
 type ErrorPayload struct {
 	Message string `json:"message"`
@@ -0,0 +1,88 @@
+package ai
+
+import "context"
+
+const (
+	// InterruptStatusPending 表示中断仍在等待用户决策。
+	InterruptStatusPending = "pending"
+	// InterruptStatusApproved 表示用户已批准继续执行被拦截的工具调用。
+	InterruptStatusApproved = "approved"
+	// InterruptStatusRejected 表示用户已拒绝执行被拦截的工具调用。
+	InterruptStatusRejected = "rejected"
+	// InterruptStatusCancelled 表示中断因新一轮对话或会话删除而失效。
+	InterruptStatusCancelled = "cancelled"
+
+	// InterruptDecisionApprove 表示用户批准本次工具调用。
+	InterruptDecisionApprove = "approve"
+	// InterruptDecisionReject 表示用户拒绝本次工具调用。
+	InterruptDecisionReject = "reject"
+
+	// FinishReasonInterrupted 表示 runtime 因等待人工确认而暂停。
+	FinishReasonInterrupted = "interrupted"
+)
+
+// ToolConfirmation 表示工具在真正执行前展示给用户的确认信息。
+type ToolConfirmation struct {
+	// Title 是确认卡片标题。
+	Title string `json:"title"`
+	// Description 是确认卡片的简短说明。
+	Description string `json:"description"`
+	// DetailMarkdown 是执行预览，例如将要写入的参数或变更 diff。
+	DetailMarkdown string `json:"detail_markdown,omitempty"`
+}
+
+// ConfirmableTool 表示执行前需要人工确认的工具。
+// 注意事项：
+//   - RequiresConfirmation 返回 false 时 runtime 按普通工具处理。
+//   - PrepareConfirmation 只能做只读预览，不允许产生任何写副作用。
+type ConfirmableTool interface {
+	Tool
+	RequiresConfirmation() bool
+	PrepareConfirmation(ctx context.Context, call ToolCall, callCtx ToolCallContext) (ToolConfirmation, error)
+}
+
+// RuntimeInterrupt 表示 runtime 因工具确认而暂停时返回给 Service 的中断信息。
+type RuntimeInterrupt struct {
+	// ToolCallKey 是被拦截工具调用在 trace_items 中的稳定主键。
+	ToolCallKey string
+	// ToolName 是被拦截的工具名。
+	ToolName string
+	// ArgumentsJSON 是已经通过校验的归一化参数。
+	ArgumentsJSON string
+	// Confirmation 是展示给用户的确认信息。
+	Confirmation ToolConfirmation
+	// StateJSON 是 runtime 私有的恢复快照，Service 只负责原样持久化。
+	StateJSON string
+}
+
+// ResumeInput 表示从人工确认中断恢复执行时的输入。
+type ResumeInput struct {
+	UserID uint
+
+	ConversationID string
+
+	AssistantMessageID string
+
+	// Tools 是恢复时重新按权限过滤后的工具集合。
+	Tools []Tool
+
+	// ToolCallContext 是恢复后工具调用共享的执行上下文。
+	ToolCallContext ToolCallContext
+
+	// StateJSON 是中断时 runtime 返回的恢复快照。
+	StateJSON string
+
+	// Decision 是用户决策，取值 approve 或 reject。
+	Decision string
+
+	// Reason 是用户填写的可选决策理由，拒绝时会回填给模型。
+	Reason string
+}
+
+// ResumableRuntime 表示支持人工确认中断恢复的 runtime。
+// 注意事项：
+//   - 不支持恢复的 runtime 只实现 Runtime，Service 会在决策接口返回不可用错误。
+type ResumableRuntime interface {
+	Runtime
+	Resume(ctx context.Context, input ResumeInput, sink Sink) (StreamResult, error)
+}
@@ -122,6 +122,26 @@ type MemoryDocumentQuery struct {
 	Limit int
 }
 
+// MemoryManageQuery 描述用户侧记忆管理的分页检索条件。
+// 与召回查询不同，管理查询只面向单个 scope，并且不过滤过期记录，方便用户核对和清理。
+type MemoryManageQuery struct {
+	// ScopeKey 是本次管理的记忆归属，调用方必须先完成授权。
+	ScopeKey string
+	// Visibility 是该 scope 对应的访问等级，查询时与 ScopeKey 同时生效。
+	Visibility MemoryVisibility
+	// Keyword 对摘要、标题、正文等可读字段做模糊匹配；为空时不过滤。
+	Keyword string
+	// Namespace 仅对 facts 生效，按业务命名空间过滤。
+	Namespace string
+	// MemoryType 仅对 documents 生效，按长期记忆类型过滤。
+	MemoryType MemoryType
+	// SourceKind 仅对 documents 生效，按来源类型过滤，例如只列出知识库导入的文档。
+	SourceKind MemorySourceKind
+	// Offset 和 Limit 控制分页窗口；Limit 小于等于 0 表示不主动限制。
+	Offset int
+	Limit  int
+}
+
 // MemoryDocumentChunkRef 描述指定 document 内某个 chunk 的精确定位。
 type MemoryDocumentChunkRef struct {
 	DocumentID string
@@ -18,6 +18,7 @@ const (
 	MemorySourceModelInferred         MemorySourceKind = "model_inferred"
 	MemorySourceRawTracePayload       MemorySourceKind = "raw_trace_payload"
 	MemorySourceFullToolOutput        MemorySourceKind = "full_tool_output"
+	MemorySourceKnowledgeBase         MemorySourceKind = "knowledge_base"
 )
 
 // MemoryTTLHintKind 表示 LLM 可提议的受控 TTL 语义类型。
@@ -63,8 +63,21 @@ type MemoryVectorSearchInput struct {
 	ScopeKey   string
 	Visibility string
 	UserID     uint
-	Limit      int
-	MinScore   float64
+	// MemoryTypes 非空时只召回这些长期记忆类型的 chunk。
+	MemoryTypes []string
+	Limit       int
+	MinScore    float64
+}
+
+// MemoryVectorRecordFilter 描述本地向量库按 payload 预过滤候选的条件。
+type MemoryVectorRecordFilter struct {
+	CollectionName string
+	ScopeKey       string
+	Visibility     string
+	UserID         uint
+	MemoryTypes    []string
+	Dimension      int
+	Limit          int
 }
 
 // MemoryVectorSearchResult 描述 Qdrant 返回的候选 chunk。
@@ -75,6 +88,44 @@ type MemoryVectorSearchResult struct {
 	Score         float64
 }
 
+// MemoryLexicalQuery 描述一次关键词倒排检索在单个作用域内的过滤条件。
+type MemoryLexicalQuery struct {
+	ScopeKey   string
+	Visibility string
+	UserID     uint
+	Terms      []string
+	// PostingLimit 是单次检索最多读取的倒排记录数，按词频降序截断。
+	PostingLimit int
+}
+
+// MemoryLexicalPosting 是一条倒排记录：某个 term 在某个 chunk 中出现的次数。
+type MemoryLexicalPosting struct {
+	ChunkID     string
+	Term        string
+	TermFreq    int
+	ChunkLength int
+}
+
+// MemoryLexicalStats 是 BM25 打分所需的作用域级统计与命中倒排记录。
+type MemoryLexicalStats struct {
+	TotalChunks    int64
+	AvgChunkLength float64
+	DocFreq        map[string]int64
+	Postings       []MemoryLexicalPosting
+}
+
+// MemoryRerankInput 描述一次召回重排请求。
+type MemoryRerankInput struct {
+	Query     string
+	Documents []string
+}
+
+// MemoryRerankResult 是重排后单个候选的相关度，Index 指向 MemoryRerankInput.Documents 下标。
+type MemoryRerankResult struct {
+	Index int
+	Score float64
+}
+
 // MemoryChunker 负责把长期记忆文档切分成可 embedding 的 chunks。
 type MemoryChunker interface {
 	Chunk(ctx context.Context, doc MemoryDocumentForIndex) ([]MemoryDocumentChunk, error)
@@ -96,6 +147,12 @@ type MemoryVectorSearcher interface {
 	SearchChunks(ctx context.Context, input MemoryVectorSearchInput) ([]MemoryVectorSearchResult, error)
 }
 
+// MemoryReranker 负责对融合后的召回候选按 query 相关度重新打分。
+// 返回结果可以只覆盖部分候选，未返回的候选由调用方保持原有顺序排在后面。
+type MemoryReranker interface {
+	Rerank(ctx context.Context, input MemoryRerankInput) ([]MemoryRerankResult, error)
+}
+
 // MemoryDocumentIndexer 定义 memory documents 的索引建设能力。
 type MemoryDocumentIndexer interface {
 	IndexDocuments(ctx context.Context, documentIDs []string) error
@@ -14,6 +14,8 @@ const (
 	MessageStatusError = "error"
 	// MessageStatusStopped 表示 assistant 消息因取消或超时停止。
 	MessageStatusStopped = "stopped"
+	// MessageStatusWaitingConfirmation 表示 assistant 消息因工具需要人工确认而暂停。
+	MessageStatusWaitingConfirmation = "waiting_confirmation"
 )
 
 // Message 表示 runtime 可读取的最小历史消息结构。
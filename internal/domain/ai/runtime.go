@@ -54,4 +54,7 @@ type StreamInput struct {
 type StreamResult struct {
 	Content      string
 	FinishReason string
+
+	// Interrupt 非空表示 runtime 因工具需要人工确认而暂停，FinishReason 为 interrupted。
+	Interrupt *RuntimeInterrupt
 }
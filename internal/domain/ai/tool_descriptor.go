@@ -6,6 +6,8 @@ type ToolDescriptor struct {
 	Spec    ToolSpec
 	GroupID ToolGroupID
 	Brief   ToolBrief
+	// RequiresConfirmation 表示该工具执行前必须经过用户人工确认。
+	RequiresConfirmation bool
 }
 
 // ToolGroupProfile 描述单个工具组的固定语义和使用边界。
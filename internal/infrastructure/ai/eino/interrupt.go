@@ -0,0 +1,211 @@
+package eino
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+
+	aidomain "personal_assistant/internal/domain/ai"
+)
+
+var _ aidomain.ResumableRuntime = (*Runtime)(nil)
+
+// pendingToolConfirmation 表示 executeToolCalls 遇到的待确认工具调用。
+type pendingToolConfirmation struct {
+	// index 是该调用在本轮原始 tool call 列表中的绝对下标。
+	index int
+	// call 是已通过校验的归一化调用。
+	call aidomain.ToolCall
+	// confirmation 是工具给出的确认预览。
+	confirmation aidomain.ToolConfirmation
+}
+
+// interruptState 是 runtime 中断时序列化的恢复快照。
+// 它只在 eino 包内解析，Service 把它当作不透明字符串持久化。
+type interruptState struct {
+	// Messages 是中断时刻完整的模型上下文，包含已执行完成的 tool message。
+	Messages []*schema.Message `json:"messages"`
+	// PendingToolCalls 是从被拦截调用开始、本轮尚未执行的 tool call。
+	PendingToolCalls []schema.ToolCall `json:"pending_tool_calls"`
+	// PendingOffset 是 PendingToolCalls[0] 在本轮原始列表中的下标。
+	PendingOffset int `json:"pending_offset"`
+	// PendingKey 是被拦截调用的 trace key。
+	PendingKey string `json:"pending_key"`
+	// Turn 是中断发生时的 tool loop 轮次。
+	Turn int `json:"turn"`
+	// RepairAttempts 保留本轮已消耗的参数修复次数。
+	RepairAttempts int `json:"repair_attempts"`
+	// RepairSeen 保留本轮已尝试修复的调用签名。
+	RepairSeen map[string]int `json:"repair_seen,omitempty"`
+}
+
+// buildInterruptResult 负责把当前 tool loop 状态打包成中断结果。
+// 参数：
+//   - toolCalls：本轮尚未执行的调用，toolCalls[0] 的绝对下标为 offset。
+func buildInterruptResult(
+	messages []*schema.Message,
+	toolCalls []schema.ToolCall,
+	offset int,
+	pending *pendingToolConfirmation,
+	turn int,
+	repairState *toolRepairState,
+) (aidomain.StreamResult, error) {
+	state := interruptState{
+		Messages:         messages,
+		PendingToolCalls: toolCalls[pending.index-offset:],
+		PendingOffset:    pending.index,
+		PendingKey:       pending.call.ID,
+		Turn:             turn,
+	}
+	if repairState != nil {
+		state.RepairAttempts = repairState.attempts
+		state.RepairSeen = repairState.seen
+	}
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return aidomain.StreamResult{}, err
+	}
+	return aidomain.StreamResult{
+		FinishReason: aidomain.FinishReasonInterrupted,
+		Interrupt: &aidomain.RuntimeInterrupt{
+			ToolCallKey:   pending.call.ID,
+			ToolName:      pending.call.Name,
+			ArgumentsJSON: pending.call.ArgumentsJSON,
+			Confirmation:  pending.confirmation,
+			StateJSON:     string(raw),
+		},
+	}, nil
+}
+
+// Resume 根据用户决策从人工确认中断处恢复 tool loop。
+// 参数：
+//   - ctx：请求上下文。
+//   - input：恢复快照、用户决策以及重新过滤后的工具集合。
+//   - sink：事件输出端。
+//
+// 返回值：
+//   - aidomain.StreamResult：恢复后的最终结果，可能再次中断。
+//   - error：快照损坏、模型调用或工具执行失败时返回。
+//
+// 核心流程：
+//  1. 解析快照并重新绑定工具。
+//  2. approve 时直接执行被拦截调用；reject 时写入拒绝 observation，让模型改为解释或换方案。
+//  3. 继续执行本轮剩余调用，然后从下一轮继续模型循环。
+func (r *Runtime) Resume(
+	ctx context.Context,
+	input aidomain.ResumeInput,
+	sink aidomain.Sink,
+) (aidomain.StreamResult, error) {
+	if r == nil || r.model == nil {
+		return aidomain.StreamResult{}, errors.New("eino runtime model is nil")
+	}
+	if sink == nil {
+		return aidomain.StreamResult{}, errors.New("ai runtime sink is nil")
+	}
+
+	var state interruptState
+	if err := json.Unmarshal([]byte(input.StateJSON), &state); err != nil {
+		return aidomain.StreamResult{}, err
+	}
+	if len(state.PendingToolCalls) == 0 || len(state.Messages) == 0 {
+		return aidomain.StreamResult{}, errors.New("eino runtime interrupt state is empty")
+	}
+
+	modelWithTools, unlock, err := r.bindToolModel(input.Tools)
+	if err != nil {
+		return aidomain.StreamResult{}, err
+	}
+	defer unlock()
+
+	toolMap := buildToolMap(input.Tools)
+	repairState := newToolRepairState()
+	repairState.attempts = state.RepairAttempts
+	for key, count := range state.RepairSeen {
+		repairState.seen[key] = count
+	}
+
+	messages := state.Messages
+	remaining := state.PendingToolCalls
+	offset := state.PendingOffset
+	approvedKey := ""
+	switch input.Decision {
+	case aidomain.InterruptDecisionApprove:
+		approvedKey = state.PendingKey
+	case aidomain.InterruptDecisionReject:
+		rejected := remaining[0]
+		toolName := strings.TrimSpace(rejected.Function.Name)
+		if err := sink.Emit(ctx, aidomain.Event{
+			Name: aidomain.EventToolCallFinished,
+			Payload: aidomain.ToolCallFinishedPayload{
+				Key:         state.PendingKey,
+				ToolName:    toolName,
+				Description: "用户已拒绝执行该工具调用。",
+				Status:      "rejected",
+				Content:     summarizeToolOutput(input.Reason),
+			},
+		}); err != nil {
+			return aidomain.StreamResult{}, err
+		}
+		messages = append(messages, schema.ToolMessage(
+			buildRejectedObservation(toolName, input.Reason),
+			state.PendingKey,
+			schema.WithToolName(toolName),
+		))
+		remaining = remaining[1:]
+		offset++
+	default:
+		return aidomain.StreamResult{}, errors.New("eino runtime unsupported interrupt decision: " + input.Decision)
+	}
+
+	if len(remaining) > 0 {
+		toolMessages, pending, err := r.executeToolCalls(
+			ctx,
+			toolMap,
+			remaining,
+			offset,
+			approvedKey,
+			input.ToolCallContext,
+			sink,
+			repairState,
+		)
+		if err != nil {
+			return aidomain.StreamResult{}, err
+		}
+		messages = append(messages, toolMessages...)
+		if pending != nil {
+			// 同一轮后续调用再次需要确认时，基于剩余调用重新打包快照。
+			return buildInterruptResult(messages, remaining, offset, pending, state.Turn, repairState)
+		}
+	}
+
+	return r.runToolLoop(
+		ctx,
+		modelWithTools,
+		toolMap,
+		messages,
+		state.Turn+1,
+		input.ToolCallContext,
+		sink,
+		repairState,
+	)
+}
+
+// buildRejectedObservation 负责生成用户拒绝工具调用后回填给模型的 observation。
+func buildRejectedObservation(toolName string, reason string) string {
+	payload := map[string]any{
+		"tool_name": toolName,
+		"status":    "rejected_by_user",
+		"message":   "用户拒绝执行该工具调用，请不要重试同一操作，向用户说明情况或询问下一步。",
+	}
+	if strings.TrimSpace(reason) != "" {
+		payload["reason"] = strings.TrimSpace(reason)
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "用户拒绝执行该工具调用。"
+	}
+	return string(raw)
+}
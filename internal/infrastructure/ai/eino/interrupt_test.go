@@ -0,0 +1,168 @@
+package eino
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+
+	aidomain "personal_assistant/internal/domain/ai"
+)
+
+type fakeConfirmableTool struct {
+	fakeRuntimeTool
+	prepared []aidomain.ToolCall
+}
+
+func (t *fakeConfirmableTool) RequiresConfirmation() bool {
+	return true
+}
+
+func (t *fakeConfirmableTool) PrepareConfirmation(
+	_ context.Context,
+	call aidomain.ToolCall,
+	_ aidomain.ToolCallContext,
+) (aidomain.ToolConfirmation, error) {
+	t.prepared = append(t.prepared, call)
+	return aidomain.ToolConfirmation{
+		Title:          "确认创建任务",
+		Description:    "将创建一个新的 OJ 任务。",
+		DetailMarkdown: call.ArgumentsJSON,
+	}, nil
+}
+
+func newConfirmableTaskModel(finalContent string) *fakeToolCallingChatModel {
+	return &fakeToolCallingChatModel{
+		streams: [][]*schema.Message{
+			{
+				schema.AssistantMessage("", []schema.ToolCall{
+					{
+						ID:   "call_create",
+						Type: "function",
+						Function: schema.FunctionCall{
+							Name:      "create_oj_task",
+							Arguments: `{"title":"周赛"}`,
+						},
+					},
+				}),
+			},
+			{
+				schema.AssistantMessage(finalContent, nil),
+			},
+		},
+	}
+}
+
+func newConfirmableTaskTool() *fakeConfirmableTool {
+	return &fakeConfirmableTool{
+		fakeRuntimeTool: fakeRuntimeTool{
+			spec: aidomain.ToolSpec{
+				Name: "create_oj_task",
+				Parameters: []aidomain.ToolParameter{
+					{Name: "title", Type: aidomain.ToolParameterTypeString, Required: true},
+				},
+			},
+			result: aidomain.ToolResult{
+				Output:  `{"task_id":9}`,
+				Summary: "已创建任务",
+			},
+		},
+	}
+}
+
+func TestRuntimeStreamInterruptsBeforeConfirmableToolAndResumesOnApprove(t *testing.T) {
+	model := newConfirmableTaskModel("任务已创建。")
+	runtime := &Runtime{model: model, systemPrompt: "base system prompt"}
+	tool := newConfirmableTaskTool()
+	sink := &runtimeEventSinkStub{}
+
+	result, err := runtime.Stream(context.Background(), aidomain.StreamInput{
+		Content: "帮我创建周赛任务",
+		Tools:   []aidomain.Tool{tool},
+	}, sink)
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if result.FinishReason != aidomain.FinishReasonInterrupted || result.Interrupt == nil {
+		t.Fatalf("result = %+v, want interrupt", result)
+	}
+	if result.Interrupt.ToolCallKey != "call_create" || result.Interrupt.ToolName != "create_oj_task" {
+		t.Fatalf("interrupt = %+v", result.Interrupt)
+	}
+	if result.Interrupt.Confirmation.Title != "确认创建任务" {
+		t.Fatalf("confirmation = %+v", result.Interrupt.Confirmation)
+	}
+	if len(tool.calls) != 0 {
+		t.Fatalf("tool should not be called before approval, calls = %d", len(tool.calls))
+	}
+	for _, event := range sink.events {
+		if event.Name == aidomain.EventDone {
+			t.Fatalf("interrupted stream should not emit done")
+		}
+	}
+
+	resumeSink := &runtimeEventSinkStub{}
+	resumed, err := runtime.Resume(context.Background(), aidomain.ResumeInput{
+		Tools:     []aidomain.Tool{tool},
+		StateJSON: result.Interrupt.StateJSON,
+		Decision:  aidomain.InterruptDecisionApprove,
+	}, resumeSink)
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if resumed.Content != "任务已创建。" || resumed.Interrupt != nil {
+		t.Fatalf("resumed = %+v", resumed)
+	}
+	if len(tool.calls) != 1 || tool.calls[0].ArgumentsJSON != `{"title":"周赛"}` {
+		t.Fatalf("tool calls = %+v", tool.calls)
+	}
+	last := resumeSink.events[len(resumeSink.events)-1]
+	if last.Name != aidomain.EventDone {
+		t.Fatalf("last resume event = %q, want done", last.Name)
+	}
+}
+
+func TestRuntimeResumeOnRejectFeedsObservationWithoutCallingTool(t *testing.T) {
+	model := newConfirmableTaskModel("好的，已取消创建。")
+	runtime := &Runtime{model: model, systemPrompt: "base system prompt"}
+	tool := newConfirmableTaskTool()
+
+	result, err := runtime.Stream(context.Background(), aidomain.StreamInput{
+		Content: "帮我创建周赛任务",
+		Tools:   []aidomain.Tool{tool},
+	}, &runtimeEventSinkStub{})
+	if err != nil || result.Interrupt == nil {
+		t.Fatalf("Stream() result = %+v, err = %v", result, err)
+	}
+
+	sink := &runtimeEventSinkStub{}
+	resumed, err := runtime.Resume(context.Background(), aidomain.ResumeInput{
+		Tools:     []aidomain.Tool{tool},
+		StateJSON: result.Interrupt.StateJSON,
+		Decision:  aidomain.InterruptDecisionReject,
+		Reason:    "时间不对",
+	}, sink)
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if resumed.Content != "好的，已取消创建。" {
+		t.Fatalf("resumed.Content = %q", resumed.Content)
+	}
+	if len(tool.calls) != 0 {
+		t.Fatalf("rejected tool should not be called, calls = %d", len(tool.calls))
+	}
+	finished, ok := sink.events[0].Payload.(aidomain.ToolCallFinishedPayload)
+	if !ok || finished.Status != "rejected" || finished.Key != "call_create" {
+		t.Fatalf("first resume event = %+v", sink.events[0])
+	}
+
+	secondTurn := model.inputs[len(model.inputs)-1]
+	observation := secondTurn[len(secondTurn)-1]
+	if observation.Role != schema.Tool || !strings.Contains(observation.Content, "rejected_by_user") {
+		t.Fatalf("last model input = %+v", observation)
+	}
+	if !strings.Contains(observation.Content, "时间不对") {
+		t.Fatalf("rejection reason missing: %s", observation.Content)
+	}
+}
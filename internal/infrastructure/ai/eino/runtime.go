@@ -47,7 +47,7 @@ type legacyBindToolsChatModel interface {
 //  3. 返回只负责基础流式对话的 runtime。
 //
 // 注意事项：
-//   - 当前阶段不注册 Tool，不启用 ApprovalMiddleware，也不使用 checkpoint/resume。
+//   - 人工确认不依赖 Eino checkpoint，而是由 runtime 自行序列化消息快照，见 interrupt.go。
 func NewRuntime(ctx context.Context, opt Options) (*Runtime, error) {
 	model, err := NewChatModel(ctx, opt)
 	if err != nil {
@@ -83,7 +83,7 @@ func (r *Runtime) Name() string {
 //  5. 输出 message_completed 和 done 终态事件。
 //
 // 注意事项：
-//   - 本实现不允许模型调用工具，也不会进入人工确认或恢复流程。
+//   - 命中需要人工确认的工具时不发送 done，而是返回带 Interrupt 的结果，由 Service 收尾。
 func (r *Runtime) Stream(
 	ctx context.Context,
 	input aidomain.StreamInput,
@@ -183,22 +183,32 @@ func (r *Runtime) streamWithTools(
 
 	// messages 保存 assistant 和 tool 的完整往返上下文。
 	messages := r.buildMessages(input)
-	// toolMap 用于把模型返回的 tool name 映射回真实实现。
-	toolMap := make(map[string]aidomain.Tool, len(input.Tools))
-	for _, tool := range input.Tools {
-		if tool == nil {
-			continue
-		}
-
-		// tool 名做 trim，避免模型或注册表中的空白差异导致找不到实现。
-		spec := tool.Spec()
-		toolMap[strings.TrimSpace(spec.Name)] = tool
-	}
+	return r.runToolLoop(
+		ctx,
+		modelWithTools,
+		buildToolMap(input.Tools),
+		messages,
+		0,
+		input.ToolCallContext,
+		sink,
+		newToolRepairState(),
+	)
+}
 
+// runToolLoop 负责从指定轮次开始执行 assistant/tool 循环，Stream 与 Resume 共用。
+func (r *Runtime) runToolLoop(
+	ctx context.Context,
+	modelWithTools einomodel.BaseChatModel,
+	toolMap map[string]aidomain.Tool,
+	messages []*schema.Message,
+	startTurn int,
+	callCtx aidomain.ToolCallContext,
+	sink aidomain.Sink,
+	repairState *toolRepairState,
+) (aidomain.StreamResult, error) {
 	// maxToolTurns 防止模型持续循环调用工具导致请求无限悬挂。
 	const maxToolTurns = 8
-	repairState := newToolRepairState()
-	for turn := 0; turn < maxToolTurns; turn++ {
+	for turn := startTurn; turn < maxToolTurns; turn++ {
 		// 每一轮都让模型基于最新 messages 再生成一次 assistant 响应。
 		reader, err := modelWithTools.Stream(ctx, messages)
 		if err != nil {
@@ -258,11 +268,13 @@ func (r *Runtime) streamWithTools(
 		}
 
 		// assistant 返回 tool call 后，顺序执行工具并把 tool message 追加回上下文。
-		toolMessages, err := r.executeToolCalls(
+		toolMessages, pending, err := r.executeToolCalls(
 			ctx,
 			toolMap,
 			assistantMessage.ToolCalls,
-			input.ToolCallContext,
+			0,
+			"",
+			callCtx,
 			sink,
 			repairState,
 		)
@@ -270,12 +282,32 @@ func (r *Runtime) streamWithTools(
 			return aidomain.StreamResult{}, err
 		}
 		messages = append(messages, toolMessages...)
+		if pending != nil {
+			// 命中需要人工确认的工具时，保存快照并把中断交给 Service 持久化。
+			return buildInterruptResult(messages, assistantMessage.ToolCalls, 0, pending, turn, repairState)
+		}
 	}
 
 	// 超过保护阈值仍未收敛时，直接终止本轮请求。
 	return aidomain.StreamResult{}, errors.New("eino runtime exceeded max tool turns")
 }
 
+// buildToolMap 负责把工具列表按 trim 后的名称建立索引。
+func buildToolMap(tools []aidomain.Tool) map[string]aidomain.Tool {
+	// toolMap 用于把模型返回的 tool name 映射回真实实现。
+	toolMap := make(map[string]aidomain.Tool, len(tools))
+	for _, tool := range tools {
+		if tool == nil {
+			continue
+		}
+
+		// tool 名做 trim，避免模型或注册表中的空白差异导致找不到实现。
+		spec := tool.Spec()
+		toolMap[strings.TrimSpace(spec.Name)] = tool
+	}
+	return toolMap
+}
+
 // bindToolModel 负责把 domain tool spec 转成 Eino tool schema 并绑定到模型。
 func (r *Runtime) bindToolModel(tools []aidomain.Tool) (einomodel.BaseChatModel, func(), error) {
 	// 先构建所有工具的 schema 定义。
@@ -353,23 +385,33 @@ func drainAssistantTurn(
 }
 
 // executeToolCalls 负责顺序执行本轮 assistant 产出的所有工具调用。
+// 参数：
+//   - offset：toolCalls 在本轮原始 tool call 列表中的起始下标，用于生成稳定的兜底 ID。
+//   - approvedKey：已获用户批准的调用 key，命中时跳过人工确认直接执行。
+//
+// 返回值：
+//   - []*schema.Message：已执行完成的 tool message。
+//   - *pendingToolConfirmation：非空表示遇到需要人工确认的调用，后续调用未执行。
+//   - error：不可恢复的工具错误或事件输出失败。
 func (r *Runtime) executeToolCalls(
 	ctx context.Context,
 	toolMap map[string]aidomain.Tool,
 	toolCalls []schema.ToolCall,
+	offset int,
+	approvedKey string,
 	callCtx aidomain.ToolCallContext,
 	sink aidomain.Sink,
 	repairState *toolRepairState,
-) ([]*schema.Message, error) {
+) ([]*schema.Message, *pendingToolConfirmation, error) {
 	// 每个工具执行完成后都会生成一条 tool message 回填给模型。
 	messages := make([]*schema.Message, 0, len(toolCalls))
 	for idx, toolCall := range toolCalls {
 		// 先归一化调用 ID 和工具名，用于 trace 以及 tool message 关联。
-		callID := deriveToolCallID(toolCall, idx)
+		callID := deriveToolCallID(toolCall, offset+idx)
 		toolName := strings.TrimSpace(toolCall.Function.Name)
 		toolImpl, ok := toolMap[toolName]
 		if !ok {
-			return nil, fmt.Errorf("ai tool not found: %s", toolName)
+			return nil, nil, fmt.Errorf("ai tool not found: %s", toolName)
 		}
 
 		// 工具开始前先发 started 事件，让 projector 建立 running 态 trace 项。
@@ -382,7 +424,7 @@ func (r *Runtime) executeToolCalls(
 				Description: "正在执行工具调用。",
 			},
 		}); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		// 记录耗时，并在执行前先做 schema/补充校验。
@@ -400,6 +442,21 @@ func (r *Runtime) executeToolCalls(
 			}
 		}
 
+		if validationErr == nil && callID != approvedKey {
+			// 写操作类工具在参数校验通过后先暂停，等待用户确认后再真正执行。
+			if confirmable, ok := toolImpl.(aidomain.ConfirmableTool); ok && confirmable.RequiresConfirmation() {
+				confirmation, prepareErr := confirmable.PrepareConfirmation(ctx, runtimeCall, callCtx)
+				if prepareErr == nil {
+					return messages, &pendingToolConfirmation{
+						index:        offset + idx,
+						call:         runtimeCall,
+						confirmation: confirmation,
+					}, nil
+				}
+				validationErr = prepareErr
+			}
+		}
+
 		var (
 			result aidomain.ToolResult
 			err    error
@@ -435,18 +492,18 @@ func (r *Runtime) executeToolCalls(
 					DetailMarkdown: detail,
 				},
 			}); emitErr != nil {
-				return nil, emitErr
+				return nil, nil, emitErr
 			}
 
 			if issue == nil || issue.Classification == aidomain.ToolObservationTerminalToolError {
-				return nil, err
+				return nil, nil, err
 			}
 
 			observation := issue.Observation(toolName)
 			if observation.Classification == aidomain.ToolObservationRepairableInvalidParam {
 				retryKey := toolName + ":" + runtimeCall.ArgumentsJSON
 				if !repairState.consume(retryKey) {
-					return nil, err
+					return nil, nil, err
 				}
 			}
 
@@ -468,7 +525,7 @@ func (r *Runtime) executeToolCalls(
 				DetailMarkdown: result.DetailMarkdown,
 			},
 		}); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		// ToolMessage 会作为下一轮模型输入，让模型基于工具输出继续生成回答。
@@ -477,7 +534,7 @@ func (r *Runtime) executeToolCalls(
 			schema.ToolMessage(compressToolMessageOutput(result), callID, schema.WithToolName(toolName)),
 		)
 	}
-	return messages, nil
+	return messages, nil, nil
 }
 
 // buildMessages 把 domain 层历史消息转换成 Eino schema 消息。
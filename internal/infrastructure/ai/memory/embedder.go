@@ -15,16 +15,34 @@ import (
 
 const defaultDashScopeEmbeddingEndpoint = "https://dashscope.aliyuncs.com/api/v1/services/embeddings/multimodal-embedding/multimodal-embedding"
 
-// EmbedderOptions 配置 DashScope multimodal embedding 客户端。
+// EmbedderOptions 配置 memory embedding 客户端。
+// Endpoint 只用于 DashScope 完整接口地址；OpenAI 兼容协议（openai / ark）使用 BaseURL 拼接 /embeddings。
 type EmbedderOptions struct {
+	Provider  string
 	APIKey    string
 	Endpoint  string
+	BaseURL   string
 	Model     string
 	Dimension int
 	Timeout   time.Duration
 	Client    *http.Client
 }
 
+// NewEmbedder 按 provider 创建 embedding 客户端。
+// provider 为空时回退 dashscope，保持旧配置行为不变；未知 provider 直接报错，避免静默写入错误向量空间。
+func NewEmbedder(opts EmbedderOptions) (aidomain.MemoryEmbedder, error) {
+	switch normalizeEmbedProvider(opts.Provider) {
+	case EmbedProviderDashScope:
+		return NewDashScopeEmbedder(opts), nil
+	case EmbedProviderOpenAI, EmbedProviderArk:
+		return NewOpenAICompatibleEmbedder(opts), nil
+	case EmbedProviderLocal:
+		return NewHashingEmbedder(opts), nil
+	default:
+		return nil, fmt.Errorf("unsupported memory embed provider: %q", opts.Provider)
+	}
+}
+
 // DashScopeEmbedder 调用阿里云百炼 multimodal embedding 接口。
 type DashScopeEmbedder struct {
 	apiKey    string
@@ -39,19 +57,12 @@ func NewDashScopeEmbedder(opts EmbedderOptions) *DashScopeEmbedder {
 	if strings.TrimSpace(opts.Endpoint) == "" {
 		opts.Endpoint = defaultDashScopeEmbeddingEndpoint
 	}
-	if opts.Client == nil {
-		timeout := opts.Timeout
-		if timeout <= 0 {
-			timeout = 30 * time.Second
-		}
-		opts.Client = &http.Client{Timeout: timeout}
-	}
 	return &DashScopeEmbedder{
 		apiKey:    strings.TrimSpace(opts.APIKey),
 		endpoint:  strings.TrimSpace(opts.Endpoint),
 		model:     strings.TrimSpace(opts.Model),
 		dimension: opts.Dimension,
-		client:    opts.Client,
+		client:    newEmbeddingHTTPClient(opts),
 	}
 }
 
@@ -169,6 +180,17 @@ type dashScopeEmbeddingItem struct {
 	Embedding []float64 `json:"embedding"`
 }
 
+func newEmbeddingHTTPClient(opts EmbedderOptions) *http.Client {
+	if opts.Client != nil {
+		return opts.Client
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &http.Client{Timeout: timeout}
+}
+
 func normalizeEmbeddingTexts(texts []string) []string {
 	items := make([]string, 0, len(texts))
 	for _, text := range texts {
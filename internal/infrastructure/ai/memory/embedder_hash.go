@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+
+	aidomain "personal_assistant/internal/domain/ai"
+)
+
+// HashingEmbedder 是不依赖外部服务的确定性 embedder。
+//
+// 实现方式是 feature hashing + 对数词频：英文/数字按词切分，中日韩文字按单字和相邻双字切分，
+// 每个特征经 FNV-1a 哈希映射到固定维度并带符号累加，最后做 L2 归一化。
+// 语义能力远弱于模型 embedding，只用于测试和无法访问外部模型的离线部署。
+type HashingEmbedder struct {
+	dimension int
+}
+
+// NewHashingEmbedder 创建本地哈希 embedder。
+func NewHashingEmbedder(opts EmbedderOptions) *HashingEmbedder {
+	return &HashingEmbedder{dimension: opts.Dimension}
+}
+
+// Embed 为输入文本生成向量，同一文本在任意进程中总是得到同一向量。
+func (e *HashingEmbedder) Embed(
+	_ context.Context,
+	input aidomain.MemoryEmbeddingInput,
+) (aidomain.MemoryEmbeddingResult, error) {
+	if e == nil {
+		return aidomain.MemoryEmbeddingResult{}, fmt.Errorf("hashing embedder is nil")
+	}
+	if e.dimension <= 0 {
+		return aidomain.MemoryEmbeddingResult{}, fmt.Errorf("local embedding dimension must be positive")
+	}
+	texts := normalizeEmbeddingTexts(input.Texts)
+	if len(texts) == 0 {
+		return aidomain.MemoryEmbeddingResult{}, nil
+	}
+	vectors := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		vectors = append(vectors, e.embedText(text))
+	}
+	return aidomain.MemoryEmbeddingResult{Vectors: vectors}, nil
+}
+
+func (e *HashingEmbedder) embedText(text string) []float32 {
+	termFreq := make(map[string]int)
+	for _, feature := range hashingEmbeddingFeatures(text) {
+		termFreq[feature]++
+	}
+	values := make([]float64, e.dimension)
+	for feature, freq := range termFreq {
+		hasher := fnv.New64a()
+		_, _ = hasher.Write([]byte(feature))
+		sum := hasher.Sum64()
+		weight := 1 + math.Log(float64(freq))
+		// 最高位决定符号，降低哈希碰撞带来的系统性偏移。
+		if sum>>63 == 1 {
+			weight = -weight
+		}
+		values[sum%uint64(e.dimension)] += weight
+	}
+
+	var norm float64
+	for _, value := range values {
+		norm += value * value
+	}
+	vector := make([]float32, e.dimension)
+	if norm == 0 {
+		return vector
+	}
+	norm = math.Sqrt(norm)
+	for i, value := range values {
+		vector[i] = float32(value / norm)
+	}
+	return vector
+}
+
+// hashingEmbeddingFeatures 把文本切成哈希特征；没有可识别字符时退化为整段文本，保证向量非零。
+func hashingEmbeddingFeatures(text string) []string {
+	text = strings.ToLower(text)
+	features := make([]string, 0, len(text))
+	var word []rune
+	var prevCJK rune
+	flushWord := func() {
+		if len(word) > 0 {
+			features = append(features, "w:"+string(word))
+			word = word[:0]
+		}
+	}
+	for _, r := range text {
+		switch {
+		case isHashingCJK(r):
+			flushWord()
+			features = append(features, "c:"+string(r))
+			if prevCJK != 0 {
+				features = append(features, "b:"+string([]rune{prevCJK, r}))
+			}
+			prevCJK = r
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word = append(word, r)
+		default:
+			flushWord()
+		}
+		prevCJK = 0
+	}
+	flushWord()
+	if len(features) == 0 {
+		features = append(features, "t:"+text)
+	}
+	return features
+}
+
+func isHashingCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	aidomain "personal_assistant/internal/domain/ai"
+)
+
+const (
+	defaultOpenAIEmbeddingBaseURL = "https://api.openai.com/v1"
+	defaultArkEmbeddingBaseURL    = "https://ark.cn-beijing.volces.com/api/v3"
+)
+
+// OpenAICompatibleEmbedder 调用 OpenAI 兼容的 /embeddings 接口，openai 与 ark 共用同一套协议。
+type OpenAICompatibleEmbedder struct {
+	provider  string
+	apiKey    string
+	endpoint  string
+	model     string
+	dimension int
+	client    *http.Client
+}
+
+// NewOpenAICompatibleEmbedder 创建 OpenAI 兼容 embedding 客户端。
+func NewOpenAICompatibleEmbedder(opts EmbedderOptions) *OpenAICompatibleEmbedder {
+	provider := normalizeEmbedProvider(opts.Provider)
+	baseURL := strings.TrimSpace(opts.BaseURL)
+	if baseURL == "" {
+		baseURL = defaultOpenAIEmbeddingBaseURL
+		if provider == EmbedProviderArk {
+			baseURL = defaultArkEmbeddingBaseURL
+		}
+	}
+	return &OpenAICompatibleEmbedder{
+		provider:  provider,
+		apiKey:    strings.TrimSpace(opts.APIKey),
+		endpoint:  strings.TrimRight(baseURL, "/") + "/embeddings",
+		model:     strings.TrimSpace(opts.Model),
+		dimension: opts.Dimension,
+		client:    newEmbeddingHTTPClient(opts),
+	}
+}
+
+// Embed 为输入文本生成向量。
+func (e *OpenAICompatibleEmbedder) Embed(
+	ctx context.Context,
+	input aidomain.MemoryEmbeddingInput,
+) (aidomain.MemoryEmbeddingResult, error) {
+	if e == nil {
+		return aidomain.MemoryEmbeddingResult{}, fmt.Errorf("openai compatible embedder is nil")
+	}
+	if e.apiKey == "" {
+		return aidomain.MemoryEmbeddingResult{}, fmt.Errorf("%s embedding api key is required", e.provider)
+	}
+	if e.model == "" {
+		return aidomain.MemoryEmbeddingResult{}, fmt.Errorf("%s embedding model is required", e.provider)
+	}
+	if e.dimension <= 0 {
+		return aidomain.MemoryEmbeddingResult{}, fmt.Errorf("%s embedding dimension must be positive", e.provider)
+	}
+	texts := normalizeEmbeddingTexts(input.Texts)
+	if len(texts) == 0 {
+		return aidomain.MemoryEmbeddingResult{}, nil
+	}
+
+	reqBody := openAIEmbeddingRequest{
+		Model:          e.model,
+		Input:          texts,
+		EncodingFormat: "float",
+	}
+	// ark 的 embedding 模型维度由接入点固定，只有 openai 协议支持 dimensions 裁剪。
+	if e.provider == EmbedProviderOpenAI {
+		reqBody.Dimensions = e.dimension
+	}
+	payload, err := json.Marshal(reqBody)
+	if err != nil {
+		return aidomain.MemoryEmbeddingResult{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return aidomain.MemoryEmbeddingResult{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return aidomain.MemoryEmbeddingResult{}, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return aidomain.MemoryEmbeddingResult{}, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return aidomain.MemoryEmbeddingResult{}, fmt.Errorf("%s embedding failed: status=%d body=%s", e.provider, resp.StatusCode, truncateEmbeddingErrorBody(string(body)))
+	}
+
+	var parsed openAIEmbeddingResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return aidomain.MemoryEmbeddingResult{}, err
+	}
+	if len(parsed.Data) != len(texts) {
+		return aidomain.MemoryEmbeddingResult{}, fmt.Errorf("%s embedding count = %d, want %d", e.provider, len(parsed.Data), len(texts))
+	}
+	vectors := make([][]float32, len(texts))
+	for _, item := range parsed.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return aidomain.MemoryEmbeddingResult{}, fmt.Errorf("%s embedding index out of range: %d", e.provider, item.Index)
+		}
+		if len(item.Embedding) != e.dimension {
+			return aidomain.MemoryEmbeddingResult{}, fmt.Errorf("%s embedding dimension = %d, want %d", e.provider, len(item.Embedding), e.dimension)
+		}
+		vector := make([]float32, len(item.Embedding))
+		for i, value := range item.Embedding {
+			vector[i] = float32(value)
+		}
+		vectors[item.Index] = vector
+	}
+	return aidomain.MemoryEmbeddingResult{Vectors: vectors}, nil
+}
+
+type openAIEmbeddingRequest struct {
+	Model          string   `json:"model"`
+	Input          []string `json:"input"`
+	EncodingFormat string   `json:"encoding_format,omitempty"`
+	Dimensions     int      `json:"dimensions,omitempty"`
+}
+
+type openAIEmbeddingResponse struct {
+	Data []openAIEmbeddingItem `json:"data"`
+}
+
+type openAIEmbeddingItem struct {
+	Index     int       `json:"index"`
+	Embedding []float64 `json:"embedding"`
+}
@@ -72,3 +72,140 @@ func TestDashScopeEmbedderRequiresAPIKeyAndModel(t *testing.T) {
 		t.Fatal("Embed() error = nil, want missing model")
 	}
 }
+
+func TestOpenAICompatibleEmbedderSendsDimensionsOnlyForOpenAI(t *testing.T) {
+	var captured map[string]any
+	var capturedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedPath = r.URL.Path
+		captured = map[string]any{}
+		if err := json.NewDecoder(r.Body).Decode(&captured); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"data":[{"index":1,"embedding":[0.4,0.5,0.6]},{"index":0,"embedding":[0.1,0.2,0.3]}]}`))
+	}))
+	defer server.Close()
+
+	for _, provider := range []string{EmbedProviderOpenAI, EmbedProviderArk} {
+		embedder, err := NewEmbedder(EmbedderOptions{
+			Provider:  provider,
+			APIKey:    "test-key",
+			BaseURL:   server.URL + "/v1/",
+			Model:     "embed-model",
+			Dimension: 3,
+		})
+		if err != nil {
+			t.Fatalf("NewEmbedder(%s) error = %v", provider, err)
+		}
+		result, err := embedder.Embed(context.Background(), aidomain.MemoryEmbeddingInput{Texts: []string{"a", "b"}})
+		if err != nil {
+			t.Fatalf("%s Embed() error = %v", provider, err)
+		}
+		if capturedPath != "/v1/embeddings" || captured["model"] != "embed-model" {
+			t.Fatalf("%s request path = %q body = %+v", provider, capturedPath, captured)
+		}
+		_, hasDimensions := captured["dimensions"]
+		if hasDimensions != (provider == EmbedProviderOpenAI) {
+			t.Fatalf("%s request dimensions present = %v", provider, hasDimensions)
+		}
+		if len(result.Vectors) != 2 || result.Vectors[0][0] != 0.1 || result.Vectors[1][0] != 0.4 {
+			t.Fatalf("%s vectors = %+v", provider, result.Vectors)
+		}
+	}
+}
+
+func TestOpenAICompatibleEmbedderRejectsDimensionMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"index":0,"embedding":[0.1,0.2]}]}`))
+	}))
+	defer server.Close()
+
+	embedder := NewOpenAICompatibleEmbedder(EmbedderOptions{
+		Provider:  EmbedProviderArk,
+		APIKey:    "test-key",
+		BaseURL:   server.URL,
+		Model:     "ep-test",
+		Dimension: 3,
+	})
+	if _, err := embedder.Embed(context.Background(), aidomain.MemoryEmbeddingInput{Texts: []string{"hello"}}); err == nil {
+		t.Fatal("Embed() error = nil, want dimension mismatch")
+	}
+}
+
+func TestHashingEmbedderIsDeterministicAndNormalized(t *testing.T) {
+	embedder, err := NewEmbedder(EmbedderOptions{Provider: EmbedProviderLocal, Dimension: 64})
+	if err != nil {
+		t.Fatalf("NewEmbedder(local) error = %v", err)
+	}
+	texts := []string{"动态规划 背包问题 DP", "动态规划 背包问题", "Redis cluster failover"}
+	first, err := embedder.Embed(context.Background(), aidomain.MemoryEmbeddingInput{Texts: texts})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	second, _ := embedder.Embed(context.Background(), aidomain.MemoryEmbeddingInput{Texts: texts})
+	if len(first.Vectors) != 3 {
+		t.Fatalf("vectors len = %d, want 3", len(first.Vectors))
+	}
+	for i := range first.Vectors {
+		if len(first.Vectors[i]) != 64 {
+			t.Fatalf("vector %d dimension = %d, want 64", i, len(first.Vectors[i]))
+		}
+		var norm float64
+		for j, value := range first.Vectors[i] {
+			if value != second.Vectors[i][j] {
+				t.Fatalf("vector %d is not deterministic", i)
+			}
+			norm += float64(value) * float64(value)
+		}
+		if norm < 0.999 || norm > 1.001 {
+			t.Fatalf("vector %d norm = %f, want 1", i, norm)
+		}
+	}
+	if cosine(first.Vectors[0], first.Vectors[1]) <= cosine(first.Vectors[0], first.Vectors[2]) {
+		t.Fatal("overlapping texts should be closer than unrelated texts")
+	}
+}
+
+func TestNewEmbedderRejectsUnknownProvider(t *testing.T) {
+	if _, err := NewEmbedder(EmbedderOptions{Provider: "unknown", Dimension: 3}); err == nil {
+		t.Fatal("NewEmbedder() error = nil, want unsupported provider")
+	}
+	embedder, err := NewEmbedder(EmbedderOptions{Dimension: 3})
+	if err != nil {
+		t.Fatalf("NewEmbedder(default) error = %v", err)
+	}
+	if _, ok := embedder.(*DashScopeEmbedder); !ok {
+		t.Fatalf("NewEmbedder(default) = %T, want *DashScopeEmbedder", embedder)
+	}
+}
+
+func TestResolveMemoryCollectionNameSeparatesEmbeddingSpaces(t *testing.T) {
+	legacy := NormalizeEmbeddingSpace("", "", 0)
+	if got := ResolveMemoryCollectionName("ai_memory_chunks", legacy); got != "ai_memory_chunks" {
+		t.Fatalf("legacy collection = %q, want ai_memory_chunks", got)
+	}
+	if legacy.ModelKey() != "qwen3-vl-embedding" {
+		t.Fatalf("legacy model key = %q", legacy.ModelKey())
+	}
+
+	local := NormalizeEmbeddingSpace("LOCAL", "", 256)
+	if got := ResolveMemoryCollectionName("ai_memory_chunks", local); got != "ai_memory_chunks__local_local_hash_v1_256" {
+		t.Fatalf("local collection = %q", got)
+	}
+	if local.ModelKey() != "local/local-hash-v1" {
+		t.Fatalf("local model key = %q", local.ModelKey())
+	}
+
+	resized := NormalizeEmbeddingSpace("dashscope", "qwen3-vl-embedding", 768)
+	if got := ResolveMemoryCollectionName("ai_memory_chunks", resized); got == "ai_memory_chunks" {
+		t.Fatal("dimension change must use a new collection")
+	}
+}
+
+func cosine(left []float32, right []float32) float64 {
+	var dot float64
+	for i := range left {
+		dot += float64(left[i]) * float64(right[i])
+	}
+	return dot
+}
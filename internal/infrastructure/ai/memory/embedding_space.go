@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"strconv"
+	"strings"
+)
+
+// 支持的 memory embedding provider。
+const (
+	EmbedProviderDashScope = "dashscope"
+	EmbedProviderOpenAI    = "openai"
+	EmbedProviderArk       = "ark"
+	EmbedProviderLocal     = "local"
+)
+
+const (
+	defaultDashScopeEmbeddingModel = "qwen3-vl-embedding"
+	defaultOpenAIEmbeddingModel    = "text-embedding-3-small"
+	defaultLocalEmbeddingModel     = "local-hash-v1"
+	defaultEmbeddingDimension      = 1024
+)
+
+// EmbeddingSpace 标识一组可以互相比较相似度的向量。
+// provider、model、dimension 任意一项变化都意味着向量空间变化，旧向量不能与新 query vector 混用。
+type EmbeddingSpace struct {
+	Provider  string
+	Model     string
+	Dimension int
+}
+
+// NormalizeEmbeddingSpace 补齐 provider / model / dimension 的默认值。
+// ark 的 model 是推理接入点 ID，没有通用默认值，保持空串交给 embedder 报错。
+func NormalizeEmbeddingSpace(provider string, model string, dimension int) EmbeddingSpace {
+	space := EmbeddingSpace{
+		Provider:  normalizeEmbedProvider(provider),
+		Model:     strings.TrimSpace(model),
+		Dimension: dimension,
+	}
+	if space.Model == "" {
+		switch space.Provider {
+		case EmbedProviderDashScope:
+			space.Model = defaultDashScopeEmbeddingModel
+		case EmbedProviderOpenAI:
+			space.Model = defaultOpenAIEmbeddingModel
+		case EmbedProviderLocal:
+			space.Model = defaultLocalEmbeddingModel
+		}
+	}
+	if space.Dimension <= 0 {
+		space.Dimension = defaultEmbeddingDimension
+	}
+	return space
+}
+
+// ModelKey 返回写入 chunk.embedding_model 的向量空间标识。
+// dashscope 沿用裸模型名以兼容存量 chunks；其他 provider 带上前缀，防止同名模型跨 provider 被误判为同一空间。
+func (s EmbeddingSpace) ModelKey() string {
+	if s.Provider == EmbedProviderDashScope {
+		return s.Model
+	}
+	return s.Provider + "/" + s.Model
+}
+
+// ResolveMemoryCollectionName 返回当前向量空间对应的 Qdrant memory collection 名称。
+//
+// 默认空间（dashscope + qwen3-vl-embedding + 1024）沿用 base 名称，兼容存量部署；
+// 其他空间追加后缀，切换 provider 后新向量写入新 collection，旧 collection 保留用于回滚，
+// 未重建完成的文档只会召回不到，而不会和旧空间向量混在一起返回错误结果。
+func ResolveMemoryCollectionName(base string, space EmbeddingSpace) string {
+	base = strings.TrimSpace(base)
+	if base == "" {
+		return ""
+	}
+	if space.Provider == EmbedProviderDashScope &&
+		space.Model == defaultDashScopeEmbeddingModel &&
+		space.Dimension == defaultEmbeddingDimension {
+		return base
+	}
+	return base + "__" + slugEmbeddingSpacePart(space.Provider) + "_" +
+		slugEmbeddingSpacePart(space.Model) + "_" + strconv.Itoa(space.Dimension)
+}
+
+func normalizeEmbedProvider(provider string) string {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if provider == "" {
+		return EmbedProviderDashScope
+	}
+	return provider
+}
+
+func slugEmbeddingSpacePart(value string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(value) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			builder.WriteRune(r)
+			continue
+		}
+		builder.WriteByte('_')
+	}
+	return builder.String()
+}
@@ -0,0 +1,270 @@
+package memory
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	aidomain "personal_assistant/internal/domain/ai"
+)
+
+const (
+	// MaxLexicalTermRunes 是倒排表单个 term 的最大长度，超长 token 多为 hash / base64，直接丢弃。
+	MaxLexicalTermRunes = 64
+
+	defaultBM25K1 = 1.2
+	defaultBM25B  = 0.75
+	// DefaultRRFK 是 reciprocal rank fusion 的平滑常数，取论文与业界常用的 60。
+	DefaultRRFK = 60
+)
+
+// LexicalTermFrequencies 把文本切成 BM25 倒排 term 并统计词频。
+//
+// 切分规则面向题号、slug 这类 embedding 不擅长的标识符：
+//   - 字母数字串整体作为一个 term，如 p1001、abc123；
+//   - 字母与数字交界处再拆出子 term，如 p1001 额外得到 1001，方便只输入题号数字时命中；
+//   - 由 - _ . 连接的 slug 既保留整体（two-sum），也保留各段（two、sum）；
+//   - 中日韩文字按相邻双字切分，孤立单字保留单字。
+func LexicalTermFrequencies(text string) map[string]int {
+	freq := make(map[string]int)
+	for _, term := range lexicalTerms(text) {
+		freq[term]++
+	}
+	return freq
+}
+
+// LexicalQueryTerms 返回 query 去重后的 term 列表，切分规则与 LexicalTermFrequencies 保持一致。
+func LexicalQueryTerms(query string) []string {
+	terms := lexicalTerms(query)
+	seen := make(map[string]struct{}, len(terms))
+	result := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if _, ok := seen[term]; ok {
+			continue
+		}
+		seen[term] = struct{}{}
+		result = append(result, term)
+	}
+	return result
+}
+
+// LexicalLength 返回文本的 term 总数，作为 BM25 的文档长度。
+func LexicalLength(freq map[string]int) int {
+	total := 0
+	for _, count := range freq {
+		total += count
+	}
+	return total
+}
+
+func lexicalTerms(text string) []string {
+	text = strings.ToLower(text)
+	terms := make([]string, 0, len(text)/2)
+	appendTerm := func(term string) {
+		if term == "" || utf8.RuneCountInString(term) > MaxLexicalTermRunes {
+			return
+		}
+		terms = append(terms, term)
+	}
+
+	var (
+		segments []string
+		word     []rune
+		cjkRun   []rune
+	)
+	flushWord := func() {
+		if len(word) > 0 {
+			segments = append(segments, string(word))
+			word = word[:0]
+		}
+	}
+	flushCompound := func() {
+		flushWord()
+		if len(segments) == 0 {
+			return
+		}
+		if len(segments) > 1 {
+			appendTerm(strings.Join(segments, "-"))
+		}
+		for _, segment := range segments {
+			appendTerm(segment)
+			for _, part := range splitLexicalLetterDigit(segment) {
+				appendTerm(part)
+			}
+		}
+		segments = segments[:0]
+	}
+	flushCJK := func() {
+		switch len(cjkRun) {
+		case 0:
+			return
+		case 1:
+			appendTerm(string(cjkRun))
+		default:
+			for i := 1; i < len(cjkRun); i++ {
+				appendTerm(string(cjkRun[i-1 : i+1]))
+			}
+		}
+		cjkRun = cjkRun[:0]
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case isHashingCJK(r):
+			flushCompound()
+			cjkRun = append(cjkRun, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		case (r == '-' || r == '_' || r == '.') && len(word) > 0 &&
+			i+1 < len(runes) && (unicode.IsLetter(runes[i+1]) || unicode.IsDigit(runes[i+1])) && !isHashingCJK(runes[i+1]):
+			// 连接符两侧都是字母数字时视为 slug 内部分隔，继续累积同一个复合 term。
+			flushWord()
+		default:
+			flushCompound()
+			flushCJK()
+		}
+	}
+	flushCompound()
+	flushCJK()
+	return terms
+}
+
+// splitLexicalLetterDigit 在字母与数字交界处拆分，只有真正混排时才返回子 term；单个字母不单独成 term。
+func splitLexicalLetterDigit(segment string) []string {
+	runes := []rune(segment)
+	parts := make([]string, 0, 2)
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		if i < len(runes) && unicode.IsDigit(runes[i]) == unicode.IsDigit(runes[i-1]) {
+			continue
+		}
+		part := runes[start:i]
+		if len(part) > 1 || unicode.IsDigit(part[0]) {
+			parts = append(parts, string(part))
+		}
+		start = i
+	}
+	if len(parts) == 1 && parts[0] == segment {
+		return nil
+	}
+	return parts
+}
+
+// BM25Options 配置 BM25 打分参数，非法值回退到 k1=1.2、b=0.75。
+type BM25Options struct {
+	K1 float64
+	B  float64
+}
+
+// LexicalScore 是单个 chunk 的 BM25 得分。
+type LexicalScore struct {
+	ChunkID      string
+	Score        float64
+	MatchedTerms []string
+}
+
+// ScoreBM25 按仓储返回的倒排统计为候选 chunk 打分，结果按得分降序、chunk id 升序返回。
+func ScoreBM25(queryTerms []string, stats aidomain.MemoryLexicalStats, opts BM25Options) []LexicalScore {
+	if len(queryTerms) == 0 || stats.TotalChunks <= 0 || len(stats.Postings) == 0 {
+		return nil
+	}
+	k1, b := opts.K1, opts.B
+	if k1 <= 0 {
+		k1 = defaultBM25K1
+	}
+	if b <= 0 || b > 1 {
+		b = defaultBM25B
+	}
+	avgLength := stats.AvgChunkLength
+	if avgLength <= 0 {
+		avgLength = 1
+	}
+	wanted := make(map[string]struct{}, len(queryTerms))
+	for _, term := range queryTerms {
+		wanted[term] = struct{}{}
+	}
+
+	total := float64(stats.TotalChunks)
+	scores := make(map[string]*LexicalScore)
+	for _, posting := range stats.Postings {
+		if _, ok := wanted[posting.Term]; !ok || posting.TermFreq <= 0 {
+			continue
+		}
+		df := float64(stats.DocFreq[posting.Term])
+		if df <= 0 {
+			df = 1
+		}
+		// 使用 Lucene 的非负 idf 形式，避免高频 term 产生负分把其他命中抵消掉。
+		idf := math.Log(1 + (total-df+0.5)/(df+0.5))
+		length := float64(posting.ChunkLength)
+		if length <= 0 {
+			length = avgLength
+		}
+		tf := float64(posting.TermFreq)
+		score := idf * tf * (k1 + 1) / (tf + k1*(1-b+b*length/avgLength))
+
+		item := scores[posting.ChunkID]
+		if item == nil {
+			item = &LexicalScore{ChunkID: posting.ChunkID}
+			scores[posting.ChunkID] = item
+		}
+		item.Score += score
+		item.MatchedTerms = append(item.MatchedTerms, posting.Term)
+	}
+
+	results := make([]LexicalScore, 0, len(scores))
+	for _, item := range scores {
+		sort.Strings(item.MatchedTerms)
+		results = append(results, *item)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].ChunkID < results[j].ChunkID
+		}
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// ReciprocalRankFusion 按 RRF 融合多路有序结果：score = Σ 1/(k + rank)，rank 从 1 开始。
+// 返回的 key 按融合得分降序、首次出现的名次升序排列，保证同分时结果稳定。
+func ReciprocalRankFusion(k int, rankings ...[]string) ([]string, map[string]float64) {
+	if k <= 0 {
+		k = DefaultRRFK
+	}
+	scores := make(map[string]float64)
+	bestRank := make(map[string]int)
+	order := make([]string, 0)
+	for _, ranking := range rankings {
+		seen := make(map[string]struct{}, len(ranking))
+		for index, key := range ranking {
+			if key == "" {
+				continue
+			}
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			rank := index + 1
+			if _, ok := scores[key]; !ok {
+				order = append(order, key)
+				bestRank[key] = rank
+			} else if rank < bestRank[key] {
+				bestRank[key] = rank
+			}
+			scores[key] += 1 / float64(k+rank)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		left, right := order[i], order[j]
+		if scores[left] != scores[right] {
+			return scores[left] > scores[right]
+		}
+		return bestRank[left] < bestRank[right]
+	})
+	return order, scores
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	aidomain "personal_assistant/internal/domain/ai"
+)
+
+func TestLexicalTermFrequenciesHandlesProblemIDsAndSlugs(t *testing.T) {
+	freq := LexicalTermFrequencies("P1001 题解；LeetCode two-sum 与 abc300_a，再看一次 P1001。")
+
+	for term, want := range map[string]int{
+		"p1001":    2,
+		"1001":     2,
+		"leetcode": 1,
+		"two-sum":  1,
+		"two":      1,
+		"sum":      1,
+		"abc300-a": 1,
+		"abc300":   1,
+		"abc":      1,
+		"300":      1,
+		"题解":       1,
+		"再看":       1,
+	} {
+		if freq[term] != want {
+			t.Fatalf("freq[%q] = %d, want %d (all = %v)", term, freq[term], want, freq)
+		}
+	}
+	if _, ok := freq["p"]; ok {
+		t.Fatalf("single letter should not be indexed: %v", freq)
+	}
+	if _, ok := freq["a"]; !ok {
+		t.Fatalf("slug segment should be kept as written: %v", freq)
+	}
+	if LexicalLength(freq) <= len(freq) {
+		t.Fatalf("LexicalLength() = %d should count repeated terms", LexicalLength(freq))
+	}
+}
+
+func TestLexicalQueryTermsDedupesAndDropsOversizedTokens(t *testing.T) {
+	terms := LexicalQueryTerms("two-sum two-sum " + strings.Repeat("x", MaxLexicalTermRunes+1))
+	want := []string{"two-sum", "two", "sum"}
+	if !reflect.DeepEqual(terms, want) {
+		t.Fatalf("LexicalQueryTerms() = %v, want %v", terms, want)
+	}
+	if got := LexicalQueryTerms("  ，。"); len(got) != 0 {
+		t.Fatalf("punctuation-only query terms = %v", got)
+	}
+}
+
+func TestScoreBM25PrefersRareTermsAndShorterChunks(t *testing.T) {
+	stats := aidomain.MemoryLexicalStats{
+		TotalChunks:    10,
+		AvgChunkLength: 20,
+		DocFreq:        map[string]int64{"p1001": 1, "dp": 8},
+		Postings: []aidomain.MemoryLexicalPosting{
+			{ChunkID: "chunk-pid", Term: "p1001", TermFreq: 1, ChunkLength: 20},
+			{ChunkID: "chunk-dp-long", Term: "dp", TermFreq: 1, ChunkLength: 80},
+			{ChunkID: "chunk-dp-short", Term: "dp", TermFreq: 1, ChunkLength: 10},
+			{ChunkID: "chunk-ignored", Term: "unrelated", TermFreq: 5, ChunkLength: 10},
+		},
+	}
+
+	scores := ScoreBM25([]string{"p1001", "dp"}, stats, BM25Options{})
+	if len(scores) != 3 {
+		t.Fatalf("ScoreBM25() len = %d, want 3: %+v", len(scores), scores)
+	}
+	order := []string{scores[0].ChunkID, scores[1].ChunkID, scores[2].ChunkID}
+	if !reflect.DeepEqual(order, []string{"chunk-pid", "chunk-dp-short", "chunk-dp-long"}) {
+		t.Fatalf("ScoreBM25() order = %v", order)
+	}
+	if !reflect.DeepEqual(scores[0].MatchedTerms, []string{"p1001"}) {
+		t.Fatalf("matched terms = %v", scores[0].MatchedTerms)
+	}
+	for _, score := range scores {
+		if score.Score <= 0 {
+			t.Fatalf("score should stay positive even for frequent terms: %+v", score)
+		}
+	}
+	if got := ScoreBM25([]string{"p1001"}, aidomain.MemoryLexicalStats{}, BM25Options{}); got != nil {
+		t.Fatalf("empty stats scores = %+v", got)
+	}
+}
+
+func TestReciprocalRankFusionRewardsAgreementAcrossRankings(t *testing.T) {
+	order, scores := ReciprocalRankFusion(60,
+		[]string{"vector-only", "both", "tail"},
+		[]string{"both", "lexical-only", "both"},
+	)
+	want := []string{"both", "vector-only", "lexical-only", "tail"}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("ReciprocalRankFusion() order = %v, want %v", order, want)
+	}
+	// 同一路里重复出现的 key 只按最靠前的名次计一次。
+	if got, want := scores["both"], 1.0/61+1.0/62; math.Abs(got-want) > 1e-12 {
+		t.Fatalf("both score = %v, want %v", got, want)
+	}
+}
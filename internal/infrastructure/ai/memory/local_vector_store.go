@@ -0,0 +1,198 @@
+package memory
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	aidomain "personal_assistant/internal/domain/ai"
+	"personal_assistant/internal/model/entity"
+)
+
+const defaultLocalVectorScanLimit = 5000
+
+// LocalVectorRecordStore 是本地向量库依赖的持久化能力，由 MySQL / SQLite 仓储实现。
+type LocalVectorRecordStore interface {
+	UpsertVectors(ctx context.Context, rows []*entity.AIMemoryChunkVector) error
+	DeleteDocumentVectors(ctx context.Context, collectionName string, documentID string) error
+	ListVectors(ctx context.Context, filter aidomain.MemoryVectorRecordFilter) ([]*entity.AIMemoryChunkVector, error)
+}
+
+// LocalVectorStoreOptions 配置本地向量库。
+type LocalVectorStoreOptions struct {
+	CollectionName string
+	// ScanLimit 是单次检索最多参与打分的候选数，防止单个作用域数据过多时拖垮请求。
+	ScanLimit int
+}
+
+// LocalVectorStore 在数据库中保存向量，检索时按 payload 预过滤后做暴力余弦相似度计算。
+// 适用于开发机、小规模部署和测试；数据量上来后应切回 Qdrant。
+type LocalVectorStore struct {
+	records        LocalVectorRecordStore
+	collectionName string
+	scanLimit      int
+}
+
+// NewLocalVectorStore 创建本地向量库。
+func NewLocalVectorStore(records LocalVectorRecordStore, opts LocalVectorStoreOptions) *LocalVectorStore {
+	scanLimit := opts.ScanLimit
+	if scanLimit <= 0 {
+		scanLimit = defaultLocalVectorScanLimit
+	}
+	return &LocalVectorStore{
+		records:        records,
+		collectionName: strings.TrimSpace(opts.CollectionName),
+		scanLimit:      scanLimit,
+	}
+}
+
+// DeleteDocumentChunks 删除指定 document 已存在的旧向量。
+func (s *LocalVectorStore) DeleteDocumentChunks(ctx context.Context, documentID string) error {
+	if s == nil || s.records == nil {
+		return nil
+	}
+	documentID = strings.TrimSpace(documentID)
+	if documentID == "" {
+		return nil
+	}
+	if s.collectionName == "" {
+		return fmt.Errorf("local memory collection name is required")
+	}
+	return s.records.DeleteDocumentVectors(ctx, s.collectionName, documentID)
+}
+
+// UpsertChunks 写入最新 memory chunk vectors。
+func (s *LocalVectorStore) UpsertChunks(ctx context.Context, chunks []aidomain.MemoryVectorChunk) error {
+	if s == nil || s.records == nil || len(chunks) == 0 {
+		return nil
+	}
+	if s.collectionName == "" {
+		return fmt.Errorf("local memory collection name is required")
+	}
+	now := time.Now()
+	rows := make([]*entity.AIMemoryChunkVector, 0, len(chunks))
+	for _, item := range chunks {
+		if len(item.Vector) == 0 || strings.TrimSpace(item.Chunk.QdrantPointID) == "" {
+			continue
+		}
+		rows = append(rows, &entity.AIMemoryChunkVector{
+			PointID:        item.Chunk.QdrantPointID,
+			CollectionName: s.collectionName,
+			DocumentID:     item.Chunk.DocumentID,
+			ChunkID:        item.Chunk.ID,
+			ScopeKey:       item.Chunk.ScopeKey,
+			Visibility:     item.Chunk.Visibility,
+			UserID:         item.Chunk.UserID,
+			OrgID:          item.Chunk.OrgID,
+			MemoryType:     item.Chunk.MemoryType,
+			Dimension:      len(item.Vector),
+			Vector:         EncodeVector(item.Vector),
+			CreatedAt:      now,
+		})
+	}
+	return s.records.UpsertVectors(ctx, rows)
+}
+
+// SearchChunks 按 query vector 检索 memory chunk 候选，过滤语义与 QdrantVectorStore 保持一致。
+func (s *LocalVectorStore) SearchChunks(
+	ctx context.Context,
+	input aidomain.MemoryVectorSearchInput,
+) ([]aidomain.MemoryVectorSearchResult, error) {
+	if s == nil || s.records == nil || len(input.Vector) == 0 || input.Limit <= 0 {
+		return nil, nil
+	}
+	if s.collectionName == "" {
+		return nil, fmt.Errorf("local memory collection name is required")
+	}
+	rows, err := s.records.ListVectors(ctx, aidomain.MemoryVectorRecordFilter{
+		CollectionName: s.collectionName,
+		ScopeKey:       input.ScopeKey,
+		Visibility:     input.Visibility,
+		UserID:         input.UserID,
+		MemoryTypes:    input.MemoryTypes,
+		Dimension:      len(input.Vector),
+		Limit:          s.scanLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	queryNorm := vectorNorm(input.Vector)
+	if queryNorm == 0 {
+		return nil, nil
+	}
+	results := make([]aidomain.MemoryVectorSearchResult, 0, len(rows))
+	for _, row := range rows {
+		if row == nil {
+			continue
+		}
+		vector, ok := DecodeVector(row.Vector)
+		if !ok || len(vector) != len(input.Vector) {
+			continue
+		}
+		score := cosineSimilarity(input.Vector, vector, queryNorm)
+		if input.MinScore > 0 && score < input.MinScore {
+			continue
+		}
+		results = append(results, aidomain.MemoryVectorSearchResult{
+			QdrantPointID: row.PointID,
+			ChunkID:       row.ChunkID,
+			DocumentID:    row.DocumentID,
+			Score:         score,
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].QdrantPointID < results[j].QdrantPointID
+		}
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > input.Limit {
+		results = results[:input.Limit]
+	}
+	return results, nil
+}
+
+// EncodeVector 把向量编码为小端序 float32 字节串。
+func EncodeVector(vector []float32) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, value := range vector {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(value))
+	}
+	return buf
+}
+
+// DecodeVector 解码 EncodeVector 生成的字节串；长度不是 4 的整数倍时返回 false。
+func DecodeVector(data []byte) ([]float32, bool) {
+	if len(data) == 0 || len(data)%4 != 0 {
+		return nil, false
+	}
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return vector, true
+}
+
+func vectorNorm(vector []float32) float64 {
+	var sum float64
+	for _, value := range vector {
+		sum += float64(value) * float64(value)
+	}
+	return math.Sqrt(sum)
+}
+
+func cosineSimilarity(query []float32, candidate []float32, queryNorm float64) float64 {
+	candidateNorm := vectorNorm(candidate)
+	if queryNorm == 0 || candidateNorm == 0 {
+		return 0
+	}
+	var dot float64
+	for i := range query {
+		dot += float64(query[i]) * float64(candidate[i])
+	}
+	return dot / (queryNorm * candidateNorm)
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	aidomain "personal_assistant/internal/domain/ai"
+	"personal_assistant/internal/model/entity"
+)
+
+type sliceVectorRecordStore struct {
+	rows       []*entity.AIMemoryChunkVector
+	lastFilter aidomain.MemoryVectorRecordFilter
+}
+
+func (s *sliceVectorRecordStore) UpsertVectors(_ context.Context, rows []*entity.AIMemoryChunkVector) error {
+	s.rows = append(s.rows, rows...)
+	return nil
+}
+
+func (s *sliceVectorRecordStore) DeleteDocumentVectors(_ context.Context, collectionName string, documentID string) error {
+	kept := s.rows[:0]
+	for _, row := range s.rows {
+		if row.CollectionName != collectionName || row.DocumentID != documentID {
+			kept = append(kept, row)
+		}
+	}
+	s.rows = kept
+	return nil
+}
+
+func (s *sliceVectorRecordStore) ListVectors(
+	_ context.Context,
+	filter aidomain.MemoryVectorRecordFilter,
+) ([]*entity.AIMemoryChunkVector, error) {
+	s.lastFilter = filter
+	return s.rows, nil
+}
+
+func TestLocalVectorStoreRanksByCosineAndAppliesThreshold(t *testing.T) {
+	records := &sliceVectorRecordStore{}
+	store := NewLocalVectorStore(records, LocalVectorStoreOptions{CollectionName: "memory"})
+	ctx := context.Background()
+	userID := uint(7)
+	chunk := func(pointID string, documentID string) aidomain.MemoryDocumentChunk {
+		return aidomain.MemoryDocumentChunk{
+			ID:            "chunk-" + pointID,
+			DocumentID:    documentID,
+			ScopeKey:      "self:7",
+			Visibility:    "self",
+			UserID:        &userID,
+			MemoryType:    "semantic",
+			QdrantPointID: pointID,
+		}
+	}
+	if err := store.UpsertChunks(ctx, []aidomain.MemoryVectorChunk{
+		{Chunk: chunk("p-near", "doc-a"), Vector: []float32{1, 0.1, 0}},
+		{Chunk: chunk("p-exact", "doc-a"), Vector: []float32{2, 0, 0}},
+		{Chunk: chunk("p-far", "doc-b"), Vector: []float32{0, 1, 0}},
+	}); err != nil {
+		t.Fatalf("UpsertChunks() error = %v", err)
+	}
+	if got := records.rows[0].Dimension; got != 3 {
+		t.Fatalf("stored dimension = %d, want 3", got)
+	}
+
+	results, err := store.SearchChunks(ctx, aidomain.MemoryVectorSearchInput{
+		Vector:      []float32{1, 0, 0},
+		ScopeKey:    "self:7",
+		MemoryTypes: []string{"semantic"},
+		Limit:       5,
+		MinScore:    0.5,
+	})
+	if err != nil {
+		t.Fatalf("SearchChunks() error = %v", err)
+	}
+	if len(results) != 2 || results[0].QdrantPointID != "p-exact" || results[1].QdrantPointID != "p-near" {
+		t.Fatalf("results = %+v, want p-exact then p-near", results)
+	}
+	if results[0].Score < 0.999 || results[0].ChunkID != "chunk-p-exact" || results[0].DocumentID != "doc-a" {
+		t.Fatalf("top result = %+v", results[0])
+	}
+	if records.lastFilter.CollectionName != "memory" || records.lastFilter.Dimension != 3 ||
+		records.lastFilter.ScopeKey != "self:7" || len(records.lastFilter.MemoryTypes) != 1 {
+		t.Fatalf("filter = %+v", records.lastFilter)
+	}
+
+	if err := store.DeleteDocumentChunks(ctx, "doc-a"); err != nil {
+		t.Fatalf("DeleteDocumentChunks() error = %v", err)
+	}
+	if len(records.rows) != 1 || records.rows[0].PointID != "p-far" {
+		t.Fatalf("rows after delete = %+v", records.rows)
+	}
+}
+
+func TestVectorEncodingRoundTrip(t *testing.T) {
+	vector := []float32{0.25, -1.5, 3}
+	decoded, ok := DecodeVector(EncodeVector(vector))
+	if !ok || len(decoded) != len(vector) {
+		t.Fatalf("DecodeVector() = %v, %v", decoded, ok)
+	}
+	for i := range vector {
+		if decoded[i] != vector[i] {
+			t.Fatalf("decoded[%d] = %f, want %f", i, decoded[i], vector[i])
+		}
+	}
+	if _, ok := DecodeVector([]byte{1, 2, 3}); ok {
+		t.Fatal("DecodeVector() ok = true for truncated data")
+	}
+}
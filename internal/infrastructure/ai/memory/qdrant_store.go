@@ -166,7 +166,7 @@ func buildMemoryVectorPayload(chunk aidomain.MemoryDocumentChunk) map[string]any
 }
 
 func buildMemoryVectorSearchFilter(input aidomain.MemoryVectorSearchInput) *qdrant.Filter {
-	must := make([]*qdrant.Condition, 0, 3)
+	must := make([]*qdrant.Condition, 0, 4)
 	if scopeKey := strings.TrimSpace(input.ScopeKey); scopeKey != "" {
 		must = append(must, qdrant.NewMatchKeyword("scope_key", scopeKey))
 	}
@@ -176,6 +176,9 @@ func buildMemoryVectorSearchFilter(input aidomain.MemoryVectorSearchInput) *qdra
 	if input.UserID > 0 {
 		must = append(must, qdrant.NewMatchInt("user_id", int64(input.UserID)))
 	}
+	if len(input.MemoryTypes) > 0 {
+		must = append(must, qdrant.NewMatchKeywords("memory_type", input.MemoryTypes...))
+	}
 	if len(must) == 0 {
 		return nil
 	}
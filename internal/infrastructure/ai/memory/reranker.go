@@ -0,0 +1,298 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	einomodel "github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	aidomain "personal_assistant/internal/domain/ai"
+	infraeino "personal_assistant/internal/infrastructure/ai/eino"
+)
+
+// 支持的召回重排 provider。
+const (
+	RerankProviderNone = "none"
+	RerankProviderLLM  = "llm"
+	RerankProviderHTTP = "http"
+)
+
+const (
+	defaultRerankTimeout          = 10 * time.Second
+	defaultRerankDocumentMaxRunes = 600
+)
+
+// RerankerOptions 配置召回重排器，不直接读取全局配置。
+//
+// llm 复用对话模型按 prompt 打分；http 调用 Jina / Cohere / Xinference 等 cross-encoder 服务通用的
+// {base_url}/rerank 协议。两者都只影响候选排序，不会引入新的候选。
+type RerankerOptions struct {
+	Provider string
+	// LLM 重排使用的对话模型配置。
+	ChatProvider        string
+	APIKey              string
+	BaseURL             string
+	Model               string
+	ByAzure             bool
+	APIVersion          string
+	MaxCompletionTokens int
+	ChatModel           einomodel.BaseChatModel
+	// Timeout 是单次重排调用的超时时间。
+	Timeout time.Duration
+	// DocumentMaxChars 控制单个候选送入重排器的最大字符数。
+	DocumentMaxChars int
+	Client           *http.Client
+}
+
+// NewReranker 按 provider 创建重排器；none 或空串返回 nil 表示关闭重排，未知 provider 直接报错。
+func NewReranker(ctx context.Context, opts RerankerOptions) (aidomain.MemoryReranker, error) {
+	switch strings.ToLower(strings.TrimSpace(opts.Provider)) {
+	case "", RerankProviderNone:
+		return nil, nil
+	case RerankProviderLLM:
+		return NewLLMReranker(ctx, opts)
+	case RerankProviderHTTP:
+		return NewHTTPReranker(opts), nil
+	default:
+		return nil, fmt.Errorf("unsupported memory rerank provider: %q", opts.Provider)
+	}
+}
+
+// LLMReranker 让对话模型为每个候选给出 0~1 的相关度分数。
+type LLMReranker struct {
+	model         einomodel.BaseChatModel
+	timeout       time.Duration
+	maxDocRunes   int
+	systemMessage string
+}
+
+// NewLLMReranker 创建基于对话模型的重排器。
+func NewLLMReranker(ctx context.Context, opts RerankerOptions) (*LLMReranker, error) {
+	model := opts.ChatModel
+	if model == nil {
+		created, err := infraeino.NewChatModel(ctx, infraeino.Options{
+			Provider:            opts.ChatProvider,
+			APIKey:              opts.APIKey,
+			BaseURL:             opts.BaseURL,
+			Model:               opts.Model,
+			ByAzure:             opts.ByAzure,
+			APIVersion:          opts.APIVersion,
+			MaxCompletionTokens: opts.MaxCompletionTokens,
+		})
+		if err != nil {
+			return nil, err
+		}
+		model = created
+	}
+	return &LLMReranker{
+		model:         model,
+		timeout:       normalizeRerankTimeout(opts.Timeout),
+		maxDocRunes:   normalizeRerankDocumentMaxChars(opts.DocumentMaxChars),
+		systemMessage: "你是 personal_assistant 的记忆召回重排器。你只输出 JSON，不输出解释。",
+	}, nil
+}
+
+// Rerank 按 query 相关度为候选打分。
+func (r *LLMReranker) Rerank(
+	ctx context.Context,
+	input aidomain.MemoryRerankInput,
+) ([]aidomain.MemoryRerankResult, error) {
+	if r == nil || r.model == nil {
+		return nil, fmt.Errorf("llm memory reranker model is nil")
+	}
+	if strings.TrimSpace(input.Query) == "" || len(input.Documents) == 0 {
+		return nil, nil
+	}
+	callCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	msg, err := r.model.Generate(callCtx, []*schema.Message{
+		schema.SystemMessage(r.systemMessage),
+		schema.UserMessage(r.buildPrompt(input)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil || strings.TrimSpace(msg.Content) == "" {
+		return nil, fmt.Errorf("llm memory reranker returned empty content")
+	}
+	var output struct {
+		Scores []struct {
+			Index int     `json:"index"`
+			Score float64 `json:"score"`
+		} `json:"scores"`
+	}
+	if err := unmarshalLLMExtractorJSON(msg.Content, &output); err != nil {
+		return nil, fmt.Errorf("llm memory reranker: %w", err)
+	}
+	results := make([]aidomain.MemoryRerankResult, 0, len(output.Scores))
+	seen := make(map[int]struct{}, len(output.Scores))
+	for _, item := range output.Scores {
+		if item.Index < 0 || item.Index >= len(input.Documents) {
+			continue
+		}
+		if _, ok := seen[item.Index]; ok {
+			continue
+		}
+		seen[item.Index] = struct{}{}
+		results = append(results, aidomain.MemoryRerankResult{Index: item.Index, Score: clampRerankScore(item.Score)})
+	}
+	return results, nil
+}
+
+func (r *LLMReranker) buildPrompt(input aidomain.MemoryRerankInput) string {
+	var builder strings.Builder
+	builder.WriteString("请判断每个候选片段对回答用户问题的帮助程度，给出 0 到 1 之间的分数：\n")
+	builder.WriteString("1 表示直接包含答案或题目本身，0.5 表示相关背景，0 表示无关。\n")
+	builder.WriteString("题号、题目 slug、专有名词完全一致时应给高分。\n")
+	builder.WriteString("只输出 JSON：{\"scores\":[{\"index\":0,\"score\":0.0}]}，每个候选都要给分。\n\n")
+	builder.WriteString("用户问题：\n")
+	builder.WriteString(truncateRunes(input.Query, r.maxDocRunes))
+	builder.WriteString("\n\n候选片段：\n")
+	for index, document := range input.Documents {
+		builder.WriteString(fmt.Sprintf("[%d] %s\n", index, strings.Join(strings.Fields(truncateRunes(document, r.maxDocRunes)), " ")))
+	}
+	return builder.String()
+}
+
+// HTTPReranker 调用 cross-encoder 重排服务的 {base_url}/rerank 接口。
+type HTTPReranker struct {
+	apiKey      string
+	endpoint    string
+	model       string
+	maxDocRunes int
+	client      *http.Client
+}
+
+// NewHTTPReranker 创建 cross-encoder HTTP 重排器。
+func NewHTTPReranker(opts RerankerOptions) *HTTPReranker {
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: normalizeRerankTimeout(opts.Timeout)}
+	}
+	endpoint := ""
+	if baseURL := strings.TrimRight(strings.TrimSpace(opts.BaseURL), "/"); baseURL != "" {
+		endpoint = baseURL + "/rerank"
+	}
+	return &HTTPReranker{
+		apiKey:      strings.TrimSpace(opts.APIKey),
+		endpoint:    endpoint,
+		model:       strings.TrimSpace(opts.Model),
+		maxDocRunes: normalizeRerankDocumentMaxChars(opts.DocumentMaxChars),
+		client:      client,
+	}
+}
+
+// Rerank 按 query 相关度为候选打分。
+func (r *HTTPReranker) Rerank(
+	ctx context.Context,
+	input aidomain.MemoryRerankInput,
+) ([]aidomain.MemoryRerankResult, error) {
+	if r == nil {
+		return nil, fmt.Errorf("http memory reranker is nil")
+	}
+	if r.endpoint == "" {
+		return nil, fmt.Errorf("http memory reranker base url is required")
+	}
+	if r.model == "" {
+		return nil, fmt.Errorf("http memory reranker model is required")
+	}
+	if strings.TrimSpace(input.Query) == "" || len(input.Documents) == 0 {
+		return nil, nil
+	}
+	documents := make([]string, 0, len(input.Documents))
+	for _, document := range input.Documents {
+		documents = append(documents, truncateRunes(document, r.maxDocRunes))
+	}
+	payload, err := json.Marshal(httpRerankRequest{
+		Model:     r.model,
+		Query:     input.Query,
+		Documents: documents,
+		TopN:      len(documents),
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	if r.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("memory rerank failed: status=%d body=%s", resp.StatusCode, truncateEmbeddingErrorBody(string(body)))
+	}
+	var parsed httpRerankResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, err
+	}
+	results := make([]aidomain.MemoryRerankResult, 0, len(parsed.Results))
+	for _, item := range parsed.Results {
+		if item.Index < 0 || item.Index >= len(input.Documents) {
+			return nil, fmt.Errorf("memory rerank index out of range: %d", item.Index)
+		}
+		results = append(results, aidomain.MemoryRerankResult{Index: item.Index, Score: item.RelevanceScore})
+	}
+	return results, nil
+}
+
+type httpRerankRequest struct {
+	Model     string   `json:"model"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	TopN      int      `json:"top_n,omitempty"`
+}
+
+type httpRerankResponse struct {
+	Results []httpRerankItem `json:"results"`
+}
+
+type httpRerankItem struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+func normalizeRerankTimeout(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return defaultRerankTimeout
+	}
+	return timeout
+}
+
+func normalizeRerankDocumentMaxChars(limit int) int {
+	if limit <= 0 {
+		return defaultRerankDocumentMaxRunes
+	}
+	return limit
+}
+
+func clampRerankScore(score float64) float64 {
+	if score < 0 {
+		return 0
+	}
+	if score > 1 {
+		return 1
+	}
+	return score
+}
@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+
+	aidomain "personal_assistant/internal/domain/ai"
+)
+
+func TestNewRerankerReturnsNilWhenDisabled(t *testing.T) {
+	for _, provider := range []string{"", "none", " NONE "} {
+		reranker, err := NewReranker(context.Background(), RerankerOptions{Provider: provider})
+		if err != nil || reranker != nil {
+			t.Fatalf("NewReranker(%q) = %v, %v", provider, reranker, err)
+		}
+	}
+	if _, err := NewReranker(context.Background(), RerankerOptions{Provider: "bogus"}); err == nil {
+		t.Fatal("NewReranker(bogus) error = nil, want error")
+	}
+}
+
+func TestHTTPRerankerSendsExpectedRequest(t *testing.T) {
+	var captured httpRerankRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/rerank" {
+			t.Fatalf("path = %q", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer rerank-key" {
+			t.Fatalf("Authorization = %q", got)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&captured); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"results": []map[string]any{
+				{"index": 1, "relevance_score": 0.92},
+				{"index": 0, "relevance_score": 0.08},
+			},
+		})
+	}))
+	defer server.Close()
+
+	reranker := NewHTTPReranker(RerankerOptions{
+		APIKey:           "rerank-key",
+		BaseURL:          server.URL + "/v1/",
+		Model:            "bge-reranker-v2-m3",
+		DocumentMaxChars: 4,
+	})
+	results, err := reranker.Rerank(context.Background(), aidomain.MemoryRerankInput{
+		Query:     "P1001",
+		Documents: []string{"背包问题笔记", "P1001 题解"},
+	})
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+	if captured.Model != "bge-reranker-v2-m3" || captured.Query != "P1001" || captured.TopN != 2 {
+		t.Fatalf("captured request = %+v", captured)
+	}
+	if captured.Documents[1] != "P100" {
+		t.Fatalf("documents should be truncated, got %v", captured.Documents)
+	}
+	if len(results) != 2 || results[0].Index != 1 || results[0].Score != 0.92 {
+		t.Fatalf("results = %+v", results)
+	}
+}
+
+func TestHTTPRerankerRejectsOutOfRangeIndexAndMissingConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"results":[{"index":3,"relevance_score":0.5}]}`))
+	}))
+	defer server.Close()
+
+	input := aidomain.MemoryRerankInput{Query: "q", Documents: []string{"a"}}
+	if _, err := NewHTTPReranker(RerankerOptions{BaseURL: server.URL, Model: "m"}).Rerank(context.Background(), input); err == nil {
+		t.Fatal("Rerank() out of range error = nil")
+	}
+	if _, err := NewHTTPReranker(RerankerOptions{Model: "m"}).Rerank(context.Background(), input); err == nil {
+		t.Fatal("Rerank() without base url error = nil")
+	}
+	if _, err := NewHTTPReranker(RerankerOptions{BaseURL: server.URL}).Rerank(context.Background(), input); err == nil {
+		t.Fatal("Rerank() without model error = nil")
+	}
+}
+
+func TestLLMRerankerParsesScoresAndClamps(t *testing.T) {
+	model := &fakeLLMExtractorChatModel{
+		generateMsg: schema.AssistantMessage("```json\n{\"scores\":[{\"index\":1,\"score\":1.4},{\"index\":0,\"score\":-0.2},{\"index\":1,\"score\":0.1},{\"index\":9,\"score\":0.5}]}\n```", nil),
+	}
+	reranker, err := NewLLMReranker(context.Background(), RerankerOptions{ChatModel: model})
+	if err != nil {
+		t.Fatalf("NewLLMReranker() error = %v", err)
+	}
+	results, err := reranker.Rerank(context.Background(), aidomain.MemoryRerankInput{
+		Query:     "two-sum 怎么做",
+		Documents: []string{"背包问题", "LeetCode two-sum 哈希表"},
+	})
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+	if len(results) != 2 ||
+		results[0] != (aidomain.MemoryRerankResult{Index: 1, Score: 1}) ||
+		results[1] != (aidomain.MemoryRerankResult{Index: 0, Score: 0}) {
+		t.Fatalf("results = %+v", results)
+	}
+	prompt := model.generateInputs[0][1].Content
+	if !strings.Contains(prompt, "[1] LeetCode two-sum 哈希表") || !strings.Contains(prompt, "two-sum 怎么做") {
+		t.Fatalf("prompt = %s", prompt)
+	}
+}
+
+func TestLLMRerankerReturnsModelErrors(t *testing.T) {
+	reranker, err := NewLLMReranker(context.Background(), RerankerOptions{
+		ChatModel: &fakeLLMExtractorChatModel{generateErr: errors.New("model down")},
+	})
+	if err != nil {
+		t.Fatalf("NewLLMReranker() error = %v", err)
+	}
+	_, err = reranker.Rerank(context.Background(), aidomain.MemoryRerankInput{Query: "q", Documents: []string{"a"}})
+	if err == nil || !strings.Contains(err.Error(), "model down") {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+}
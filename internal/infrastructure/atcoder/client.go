@@ -0,0 +1,362 @@
+package atcoder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/config"
+	obsprophttp "personal_assistant/pkg/observability/propagation/http"
+	obstrace "personal_assistant/pkg/observability/trace"
+
+	"github.com/go-resty/resty/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultBaseURL     = "https://kenkoooo.com"
+	defaultAPIPrefix   = "/atcoder"
+	defaultTimeout     = 15 * time.Second
+	defaultMaxBodySize = 32 << 20 // 32MB，problems.json 是全量题目列表
+)
+
+// Client 是 AtCoder Problems（kenkoooo.com/atcoder）API 客户端。
+// AtCoder 官方没有公开 API，提交记录与题目元信息都来自该社区镜像，调用方需自行控制请求频率。
+type Client struct {
+	baseURL           *url.URL
+	restyClient       *resty.Client
+	logger            *zap.Logger
+	responseBodyLimit int64
+}
+
+type Option func(*Client)
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// NewFromConfig 根据 crawler.atcoder 配置创建客户端；未配置 base_url 时直连 AtCoder Problems。
+func NewFromConfig(cfg config.AtCoderCrawler, opts ...Option) (*Client, error) {
+	if strings.TrimSpace(cfg.BaseURL) == "" {
+		cfg.BaseURL = defaultBaseURL
+		if strings.TrimSpace(cfg.APIPrefix) == "" {
+			cfg.APIPrefix = defaultAPIPrefix
+		}
+	}
+	if cfg.TimeoutMs <= 0 {
+		cfg.TimeoutMs = int(defaultTimeout / time.Millisecond)
+	}
+	if cfg.MaxIdleConns <= 0 {
+		cfg.MaxIdleConns = 100
+	}
+	if cfg.MaxIdleConnsPerHost <= 0 {
+		cfg.MaxIdleConnsPerHost = 100
+	}
+	if cfg.ResponseBodyLimitBytes <= 0 {
+		cfg.ResponseBodyLimitBytes = defaultMaxBodySize
+	}
+
+	c, err := NewClient(joinBaseURLWithPrefix(cfg.BaseURL, cfg.APIPrefix), opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.responseBodyLimit = cfg.ResponseBodyLimitBytes
+
+	r := c.restyClient
+	r.SetTimeout(time.Duration(cfg.TimeoutMs) * time.Millisecond)
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:       time.Duration(cfg.IdleConnTimeoutSec) * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	r.SetTransport(transport)
+
+	if cfg.RetryCount > 0 {
+		r.SetRetryCount(cfg.RetryCount)
+		r.SetRetryWaitTime(time.Duration(cfg.RetryWaitMs) * time.Millisecond)
+		r.SetRetryMaxWaitTime(time.Duration(cfg.RetryMaxWaitMs) * time.Millisecond)
+		r.AddRetryCondition(func(r *resty.Response, err error) bool {
+			return err != nil || r.StatusCode() >= 500
+		})
+	}
+
+	return c, nil
+}
+
+func NewClient(baseURL string, opts ...Option) (*Client, error) {
+	baseURL = strings.TrimSpace(baseURL)
+	if baseURL == "" {
+		return nil, errors.New("baseURL is required")
+	}
+
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse baseURL failed: %w", err)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid baseURL: %s", baseURL)
+	}
+	parsed.Path = strings.TrimRight(parsed.Path, "/")
+
+	c := &Client{
+		baseURL:           parsed,
+		restyClient:       resty.New(),
+		logger:            zap.NewNop(),
+		responseBodyLimit: defaultMaxBodySize,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+	c.restyClient.SetBaseURL(c.baseURL.String())
+
+	return c, nil
+}
+
+// UserSubmissions 查询 fromSecond（含）之后的提交记录，按提交时间升序，单次最多 SubmissionPageSize 条。
+// 对应接口: GET /atcoder-api/v3/user/submissions?user={user}&from_second={fromSecond}
+func (c *Client) UserSubmissions(ctx context.Context, user string, fromSecond int64) ([]Submission, error) {
+	if fromSecond < 0 {
+		fromSecond = 0
+	}
+	query := url.Values{}
+	query.Set("user", user)
+	query.Set("from_second", strconv.FormatInt(fromSecond, 10))
+	var out []Submission
+	if err := c.get(ctx, "/atcoder-api/v3/user/submissions", query, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserACRank 查询用户的通过题数与排名，同时用于校验用户是否存在。
+// 对应接口: GET /atcoder-api/v3/user/ac_rank?user={user}
+func (c *Client) UserACRank(ctx context.Context, user string) (*RankCount, error) {
+	query := url.Values{}
+	query.Set("user", user)
+	var out RankCount
+	if err := c.get(ctx, "/atcoder-api/v3/user/ac_rank", query, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Problems 拉取全量题目元信息，用于把提交记录中的 problem_id 映射成标题。
+// 对应接口: GET /resources/problems.json
+func (c *Client) Problems(ctx context.Context) ([]Problem, error) {
+	var out []Problem
+	if err := c.get(ctx, "/resources/problems.json", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// get 发起 GET 请求并把 JSON 响应解码到 out。
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	if c == nil || c.baseURL == nil || c.restyClient == nil {
+		return errors.New("atcoder client is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	spanCtx, spanEvent := obstrace.StartSpan(ctx, obstrace.StartOptions{
+		Service: traceServiceName(),
+		Stage:   "outbound.http",
+		Name:    "atcoder" + path,
+		Kind:    "client",
+		Tags: map[string]string{
+			"provider": "atcoder",
+			"path":     path,
+		},
+	})
+	ctx = spanCtx
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: c.baseURL.Path + path}).String()
+
+	r := c.restyClient.R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		SetQueryParamsFromValues(query)
+
+	obsprophttp.InjectHeaders(ctx, func(key, value string) {
+		r.SetHeader(key, value)
+	}, outboundInjectOptions())
+
+	resp, err := r.Get(path)
+	if err != nil {
+		c.logError("atcoder http request failed", err, endpoint, 0)
+		if spanEvent != nil && global.ObservabilityTraces != nil {
+			spanEvent.WithErrorPayload(cutTracePayload(query.Encode()), cutTracePayload(err.Error()))
+			spanEvent.WithErrorDetail(buildOutboundErrorDetail(path, endpoint, 0, query, "", err.Error()))
+			span := spanEvent.End(obstrace.SpanStatusError, "outbound_http_error", err.Error(), map[string]string{
+				"endpoint": endpoint,
+				"path":     path,
+			})
+			_ = global.ObservabilityTraces.RecordSpan(ctx, span)
+		}
+		return fmt.Errorf("atcoder http request failed: %w", err)
+	}
+	if resp == nil {
+		if spanEvent != nil && global.ObservabilityTraces != nil {
+			spanEvent.WithErrorDetail(buildOutboundErrorDetail(path, endpoint, 0, query, "", "atcoder empty response"))
+			span := spanEvent.End(obstrace.SpanStatusError, "outbound_empty_response", "atcoder empty response", map[string]string{
+				"endpoint": endpoint,
+				"path":     path,
+			})
+			_ = global.ObservabilityTraces.RecordSpan(ctx, span)
+		}
+		return errors.New("atcoder empty response")
+	}
+	if resp.IsError() {
+		httpErr := &RemoteHTTPError{
+			URL:        endpoint,
+			Path:       path,
+			StatusCode: resp.StatusCode(),
+			Body:       resp.String(),
+		}
+
+		c.logError("atcoder remote http error", httpErr, endpoint, resp.StatusCode())
+		if spanEvent != nil && global.ObservabilityTraces != nil {
+			spanEvent.WithErrorPayload(cutTracePayload(query.Encode()), cutTracePayload(resp.String()))
+			spanEvent.WithErrorDetail(buildOutboundErrorDetail(path, endpoint, resp.StatusCode(), query, resp.String(), httpErr.Error()))
+			span := spanEvent.End(obstrace.SpanStatusError, "outbound_http_status_error", httpErr.Error(), map[string]string{
+				"endpoint":    endpoint,
+				"path":        path,
+				"status_code": fmt.Sprintf("%d", resp.StatusCode()),
+			})
+			_ = global.ObservabilityTraces.RecordSpan(ctx, span)
+		}
+		return httpErr
+	}
+	if out != nil {
+		if err := json.Unmarshal(resp.Body(), out); err != nil {
+			return fmt.Errorf("atcoder decode response failed: %w", err)
+		}
+	}
+	if spanEvent != nil && global.ObservabilityTraces != nil {
+		span := spanEvent.End(obstrace.SpanStatusOK, "", "", map[string]string{
+			"endpoint":    endpoint,
+			"path":        path,
+			"status_code": fmt.Sprintf("%d", resp.StatusCode()),
+		})
+		_ = global.ObservabilityTraces.RecordSpan(ctx, span)
+	}
+	return nil
+}
+
+func cutTracePayload(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	maxBytes := 4096
+	if global.Config != nil && global.Config.Observability.Traces.MaxPayloadBytes > 0 {
+		maxBytes = global.Config.Observability.Traces.MaxPayloadBytes
+	}
+	if len(raw) <= maxBytes {
+		return raw
+	}
+	return raw[:maxBytes]
+}
+
+func buildOutboundErrorDetail(
+	path string,
+	endpoint string,
+	statusCode int,
+	query url.Values,
+	responseBody string,
+	errMsg string,
+) string {
+	payload := map[string]interface{}{
+		"provider":       "atcoder",
+		"path":           strings.TrimSpace(path),
+		"endpoint":       strings.TrimSpace(endpoint),
+		"status_code":    statusCode,
+		"error":          strings.TrimSpace(errMsg),
+		"request":        cutTracePayload(query.Encode()),
+		"response":       cutTracePayload(responseBody),
+		"occurred_stage": "outbound.http",
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func traceServiceName() string {
+	if global.Config == nil {
+		return "personal_assistant"
+	}
+	if v := strings.TrimSpace(global.Config.Observability.ServiceName); v != "" {
+		return v
+	}
+	return "personal_assistant"
+}
+
+func outboundInjectOptions() obsprophttp.InjectOptions {
+	opt := obsprophttp.InjectOptions{
+		InjectW3C: true,
+	}
+	if global.Config == nil {
+		return opt
+	}
+	opt.RequestIDHeader = strings.TrimSpace(global.Config.Observability.Propagation.RequestIDHeader)
+	opt.InjectW3C = global.Config.Observability.Propagation.Enabled &&
+		global.Config.Observability.Propagation.InjectW3C
+	return opt
+}
+
+func joinBaseURLWithPrefix(baseURL, apiPrefix string) string {
+	baseURL = strings.TrimSpace(baseURL)
+	apiPrefix = strings.TrimSpace(apiPrefix)
+	if apiPrefix == "" {
+		return baseURL
+	}
+	if !strings.HasPrefix(apiPrefix, "/") {
+		apiPrefix = "/" + apiPrefix
+	}
+	apiPrefix = strings.TrimRight(apiPrefix, "/")
+	if apiPrefix == "" {
+		return baseURL
+	}
+	return strings.TrimRight(baseURL, "/") + apiPrefix
+}
+
+func (c *Client) logError(msg string, err error, endpoint string, statusCode int) {
+	if c.logger == nil {
+		return
+	}
+	fields := []zap.Field{
+		zap.String("endpoint", endpoint),
+	}
+	if statusCode != 0 {
+		fields = append(fields, zap.Int("status_code", statusCode))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	c.logger.Error(msg, fields...)
+}
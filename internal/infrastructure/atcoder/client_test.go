@@ -0,0 +1,106 @@
+package atcoder
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"personal_assistant/internal/model/config"
+)
+
+func TestClient_UserSubmissions_OK(t *testing.T) {
+	t.Parallel()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		q := r.URL.Query()
+		if r.URL.Path != "/atcoder/atcoder-api/v3/user/submissions" || q.Get("user") != "chokudai" || q.Get("from_second") != "1700000000" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"id": 10, "epoch_second": 1700000001, "problem_id": "abc300_a", "contest_id": "abc300", "user_id": "chokudai", "result": "AC"},
+			{"id": 11, "epoch_second": 1700000002, "problem_id": "abc300_b", "contest_id": "abc300", "user_id": "chokudai", "result": "WA"},
+		})
+	}))
+	defer s.Close()
+
+	c, err := NewFromConfig(config.AtCoderCrawler{BaseURL: s.URL, APIPrefix: "/atcoder"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	submissions, err := c.UserSubmissions(ctx, "chokudai", 1700000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(submissions) != 2 {
+		t.Fatalf("len(submissions) = %d, want 2", len(submissions))
+	}
+	if submissions[0].Result != ResultAccepted || submissions[0].ProblemID != "abc300_a" {
+		t.Fatalf("unexpected first submission: %+v", submissions[0])
+	}
+}
+
+func TestClient_UserACRank_NotFound(t *testing.T) {
+	t.Parallel()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/atcoder/atcoder-api/v3/user/ac_rank" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer s.Close()
+
+	c, err := NewFromConfig(config.AtCoderCrawler{BaseURL: s.URL, APIPrefix: "/atcoder"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.UserACRank(context.Background(), "nobody_zz")
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !IsNotFound(err) {
+		t.Fatalf("IsNotFound(%v) = false, want true", err)
+	}
+}
+
+func TestClient_Problems_OK(t *testing.T) {
+	t.Parallel()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/atcoder/resources/problems.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":"abc300_a","contest_id":"abc300","problem_index":"A","name":"N-choice question","title":"A. N-choice question"}]`))
+	}))
+	defer s.Close()
+
+	c, err := NewFromConfig(config.AtCoderCrawler{BaseURL: s.URL, APIPrefix: "/atcoder"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := c.Problems(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 1 || problems[0].Name != "N-choice question" {
+		t.Fatalf("unexpected problems: %+v", problems)
+	}
+}
@@ -0,0 +1,66 @@
+package atcoder
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// ResultAccepted 是 AtCoder 提交记录中“通过”的判题结果。
+	ResultAccepted = "AC"
+	// SubmissionPageSize 是 user/submissions 单次最多返回的条数，返回满页时需要继续翻页。
+	SubmissionPageSize = 500
+)
+
+// Submission 对应 AtCoder Problems user/submissions 返回的单条提交记录。
+type Submission struct {
+	ID            int64   `json:"id"`
+	EpochSecond   int64   `json:"epoch_second"`
+	ProblemID     string  `json:"problem_id"`
+	ContestID     string  `json:"contest_id"`
+	UserID        string  `json:"user_id"`
+	Language      string  `json:"language"`
+	Point         float64 `json:"point"`
+	Result        string  `json:"result"`
+	ExecutionTime *int    `json:"execution_time"`
+}
+
+// Problem 对应 resources/problems.json 中的题目元信息。
+type Problem struct {
+	ID           string `json:"id"`
+	ContestID    string `json:"contest_id"`
+	ProblemIndex string `json:"problem_index"`
+	Name         string `json:"name"`
+	Title        string `json:"title"`
+}
+
+// RankCount 对应 user/ac_rank 返回的通过题数与排名。
+type RankCount struct {
+	Count int `json:"count"`
+	Rank  int `json:"rank"`
+}
+
+type RemoteHTTPError struct {
+	URL        string
+	Path       string
+	StatusCode int
+	Body       string
+	Message    string
+}
+
+func (e *RemoteHTTPError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("atcoder remote error: %s (status=%d)", e.Message, e.StatusCode)
+	}
+	return fmt.Sprintf("atcoder remote error: status=%d url=%s", e.StatusCode, e.URL)
+}
+
+// IsNotFound 判断错误是否表示用户不存在；AtCoder Problems 对未收录的用户返回 404。
+func IsNotFound(err error) bool {
+	var httpErr *RemoteHTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	return httpErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,367 @@
+package codeforces
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/config"
+	obsprophttp "personal_assistant/pkg/observability/propagation/http"
+	obstrace "personal_assistant/pkg/observability/trace"
+
+	"github.com/go-resty/resty/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultBaseURL     = "https://codeforces.com"
+	defaultAPIPrefix   = "/api"
+	defaultTimeout     = 10 * time.Second
+	defaultMaxBodySize = 8 << 20 // 8MB，user.status 全量分页时单页可能较大
+)
+
+// Client 是 Codeforces 官方 API 客户端，只使用无需鉴权的公开方法。
+type Client struct {
+	baseURL           *url.URL
+	restyClient       *resty.Client
+	logger            *zap.Logger
+	responseBodyLimit int64
+}
+
+type Option func(*Client)
+
+func WithLogger(logger *zap.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// NewFromConfig 根据 crawler.codeforces 配置创建客户端；未配置 base_url 时直连官方 API。
+func NewFromConfig(cfg config.CodeforcesCrawler, opts ...Option) (*Client, error) {
+	if strings.TrimSpace(cfg.BaseURL) == "" {
+		cfg.BaseURL = defaultBaseURL
+		if strings.TrimSpace(cfg.APIPrefix) == "" {
+			cfg.APIPrefix = defaultAPIPrefix
+		}
+	}
+	if cfg.TimeoutMs <= 0 {
+		cfg.TimeoutMs = int(defaultTimeout / time.Millisecond)
+	}
+	if cfg.MaxIdleConns <= 0 {
+		cfg.MaxIdleConns = 100
+	}
+	if cfg.MaxIdleConnsPerHost <= 0 {
+		cfg.MaxIdleConnsPerHost = 100
+	}
+	if cfg.ResponseBodyLimitBytes <= 0 {
+		cfg.ResponseBodyLimitBytes = defaultMaxBodySize
+	}
+
+	c, err := NewClient(joinBaseURLWithPrefix(cfg.BaseURL, cfg.APIPrefix), opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.responseBodyLimit = cfg.ResponseBodyLimitBytes
+
+	r := c.restyClient
+	r.SetTimeout(time.Duration(cfg.TimeoutMs) * time.Millisecond)
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:       time.Duration(cfg.IdleConnTimeoutSec) * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	r.SetTransport(transport)
+
+	if cfg.RetryCount > 0 {
+		r.SetRetryCount(cfg.RetryCount)
+		r.SetRetryWaitTime(time.Duration(cfg.RetryWaitMs) * time.Millisecond)
+		r.SetRetryMaxWaitTime(time.Duration(cfg.RetryMaxWaitMs) * time.Millisecond)
+		r.AddRetryCondition(func(r *resty.Response, err error) bool {
+			return err != nil || r.StatusCode() >= 500
+		})
+	}
+
+	return c, nil
+}
+
+func NewClient(baseURL string, opts ...Option) (*Client, error) {
+	baseURL = strings.TrimSpace(baseURL)
+	if baseURL == "" {
+		return nil, errors.New("baseURL is required")
+	}
+
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse baseURL failed: %w", err)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid baseURL: %s", baseURL)
+	}
+	parsed.Path = strings.TrimRight(parsed.Path, "/")
+
+	c := &Client{
+		baseURL:           parsed,
+		restyClient:       resty.New(),
+		logger:            zap.NewNop(),
+		responseBodyLimit: defaultMaxBodySize,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+	c.restyClient.SetBaseURL(c.baseURL.String())
+
+	return c, nil
+}
+
+// UserInfo 查询单个 handle 的公开资料。
+// 对应接口: GET /user.info?handles={handle}
+func (c *Client) UserInfo(ctx context.Context, handle string) (*User, error) {
+	var out []User
+	query := url.Values{}
+	query.Set("handles", handle)
+	if err := c.get(ctx, "/user.info", query, &out); err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, &RemoteHTTPError{
+			Path:       "/user.info",
+			StatusCode: http.StatusOK,
+			Message:    "handles: user not found",
+		}
+	}
+	return &out[0], nil
+}
+
+// UserStatus 按提交时间倒序分页查询用户提交记录。
+// 对应接口: GET /user.status?handle={handle}&from={from}&count={count}，from 从 1 开始。
+func (c *Client) UserStatus(ctx context.Context, handle string, from int, count int) ([]Submission, error) {
+	if from <= 0 {
+		from = 1
+	}
+	query := url.Values{}
+	query.Set("handle", handle)
+	query.Set("from", strconv.Itoa(from))
+	if count > 0 {
+		query.Set("count", strconv.Itoa(count))
+	}
+	var out []Submission
+	if err := c.get(ctx, "/user.status", query, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// get 发起 GET 请求并拆开 Codeforces 的 status/result 响应包。
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	if c == nil || c.baseURL == nil || c.restyClient == nil {
+		return errors.New("codeforces client is not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	spanCtx, spanEvent := obstrace.StartSpan(ctx, obstrace.StartOptions{
+		Service: traceServiceName(),
+		Stage:   "outbound.http",
+		Name:    "codeforces" + path,
+		Kind:    "client",
+		Tags: map[string]string{
+			"provider": "codeforces",
+			"path":     path,
+		},
+	})
+	ctx = spanCtx
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: c.baseURL.Path + path}).String()
+
+	r := c.restyClient.R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		SetQueryParamsFromValues(query)
+
+	obsprophttp.InjectHeaders(ctx, func(key, value string) {
+		r.SetHeader(key, value)
+	}, outboundInjectOptions())
+
+	resp, err := r.Get(path)
+	if err != nil {
+		c.logError("codeforces http request failed", err, endpoint, 0)
+		if spanEvent != nil && global.ObservabilityTraces != nil {
+			spanEvent.WithErrorPayload(cutTracePayload(query.Encode()), cutTracePayload(err.Error()))
+			spanEvent.WithErrorDetail(buildOutboundErrorDetail(path, endpoint, 0, query, "", err.Error()))
+			span := spanEvent.End(obstrace.SpanStatusError, "outbound_http_error", err.Error(), map[string]string{
+				"endpoint": endpoint,
+				"path":     path,
+			})
+			_ = global.ObservabilityTraces.RecordSpan(ctx, span)
+		}
+		return fmt.Errorf("codeforces http request failed: %w", err)
+	}
+	if resp == nil {
+		if spanEvent != nil && global.ObservabilityTraces != nil {
+			spanEvent.WithErrorDetail(buildOutboundErrorDetail(path, endpoint, 0, query, "", "codeforces empty response"))
+			span := spanEvent.End(obstrace.SpanStatusError, "outbound_empty_response", "codeforces empty response", map[string]string{
+				"endpoint": endpoint,
+				"path":     path,
+			})
+			_ = global.ObservabilityTraces.RecordSpan(ctx, span)
+		}
+		return errors.New("codeforces empty response")
+	}
+	var envelope apiResponse
+	decodeErr := json.Unmarshal(resp.Body(), &envelope)
+	if resp.IsError() || decodeErr != nil || envelope.Status != StatusOK {
+		httpErr := &RemoteHTTPError{
+			URL:        endpoint,
+			Path:       path,
+			StatusCode: resp.StatusCode(),
+			Body:       resp.String(),
+			Message:    strings.TrimSpace(envelope.Comment),
+		}
+		if httpErr.Message == "" && decodeErr != nil {
+			httpErr.Message = "decode response failed: " + decodeErr.Error()
+		}
+
+		c.logError("codeforces remote http error", httpErr, endpoint, resp.StatusCode())
+		if spanEvent != nil && global.ObservabilityTraces != nil {
+			spanEvent.WithErrorPayload(cutTracePayload(query.Encode()), cutTracePayload(resp.String()))
+			spanEvent.WithErrorDetail(buildOutboundErrorDetail(path, endpoint, resp.StatusCode(), query, resp.String(), httpErr.Error()))
+			span := spanEvent.End(obstrace.SpanStatusError, "outbound_http_status_error", httpErr.Error(), map[string]string{
+				"endpoint":    endpoint,
+				"path":        path,
+				"status_code": fmt.Sprintf("%d", resp.StatusCode()),
+			})
+			_ = global.ObservabilityTraces.RecordSpan(ctx, span)
+		}
+		return httpErr
+	}
+	if out != nil && len(envelope.Result) > 0 {
+		if err := json.Unmarshal(envelope.Result, out); err != nil {
+			return fmt.Errorf("codeforces decode result failed: %w", err)
+		}
+	}
+	if spanEvent != nil && global.ObservabilityTraces != nil {
+		span := spanEvent.End(obstrace.SpanStatusOK, "", "", map[string]string{
+			"endpoint":    endpoint,
+			"path":        path,
+			"status_code": fmt.Sprintf("%d", resp.StatusCode()),
+		})
+		_ = global.ObservabilityTraces.RecordSpan(ctx, span)
+	}
+	return nil
+}
+
+func cutTracePayload(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	maxBytes := 4096
+	if global.Config != nil && global.Config.Observability.Traces.MaxPayloadBytes > 0 {
+		maxBytes = global.Config.Observability.Traces.MaxPayloadBytes
+	}
+	if len(raw) <= maxBytes {
+		return raw
+	}
+	return raw[:maxBytes]
+}
+
+func buildOutboundErrorDetail(
+	path string,
+	endpoint string,
+	statusCode int,
+	query url.Values,
+	responseBody string,
+	errMsg string,
+) string {
+	payload := map[string]interface{}{
+		"provider":       "codeforces",
+		"path":           strings.TrimSpace(path),
+		"endpoint":       strings.TrimSpace(endpoint),
+		"status_code":    statusCode,
+		"error":          strings.TrimSpace(errMsg),
+		"request":        cutTracePayload(query.Encode()),
+		"response":       cutTracePayload(responseBody),
+		"occurred_stage": "outbound.http",
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func traceServiceName() string {
+	if global.Config == nil {
+		return "personal_assistant"
+	}
+	if v := strings.TrimSpace(global.Config.Observability.ServiceName); v != "" {
+		return v
+	}
+	return "personal_assistant"
+}
+
+func outboundInjectOptions() obsprophttp.InjectOptions {
+	opt := obsprophttp.InjectOptions{
+		InjectW3C: true,
+	}
+	if global.Config == nil {
+		return opt
+	}
+	opt.RequestIDHeader = strings.TrimSpace(global.Config.Observability.Propagation.RequestIDHeader)
+	opt.InjectW3C = global.Config.Observability.Propagation.Enabled &&
+		global.Config.Observability.Propagation.InjectW3C
+	return opt
+}
+
+func joinBaseURLWithPrefix(baseURL, apiPrefix string) string {
+	baseURL = strings.TrimSpace(baseURL)
+	apiPrefix = strings.TrimSpace(apiPrefix)
+	if apiPrefix == "" {
+		return baseURL
+	}
+	if !strings.HasPrefix(apiPrefix, "/") {
+		apiPrefix = "/" + apiPrefix
+	}
+	apiPrefix = strings.TrimRight(apiPrefix, "/")
+	if apiPrefix == "" {
+		return baseURL
+	}
+	return strings.TrimRight(baseURL, "/") + apiPrefix
+}
+
+func (c *Client) logError(msg string, err error, endpoint string, statusCode int) {
+	if c.logger == nil {
+		return
+	}
+	fields := []zap.Field{
+		zap.String("endpoint", endpoint),
+	}
+	if statusCode != 0 {
+		fields = append(fields, zap.Int("status_code", statusCode))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	c.logger.Error(msg, fields...)
+}
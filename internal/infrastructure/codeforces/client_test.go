@@ -0,0 +1,128 @@
+package codeforces
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"personal_assistant/internal/model/config"
+)
+
+func TestClient_UserInfo_OK(t *testing.T) {
+	t.Parallel()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.URL.Path != "/api/user.info" || r.URL.Query().Get("handles") != "tourist" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status": "OK",
+			"result": []map[string]any{{
+				"handle":     "tourist",
+				"rating":     3800,
+				"maxRating":  3979,
+				"rank":       "legendary grandmaster",
+				"titlePhoto": "https://userpic.codeforces.org/422/title/50a270ed4a722867.jpg",
+			}},
+		})
+	}))
+	defer s.Close()
+
+	c, err := NewFromConfig(config.CodeforcesCrawler{BaseURL: s.URL, APIPrefix: "/api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	user, err := c.UserInfo(ctx, "tourist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Handle != "tourist" || user.Rating != 3800 || user.TitlePhoto == "" {
+		t.Fatalf("unexpected user: %+v", user)
+	}
+}
+
+func TestClient_UserInfo_NotFound(t *testing.T) {
+	t.Parallel()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":"FAILED","comment":"handles: User with handle nobody_zz not found"}`))
+	}))
+	defer s.Close()
+
+	c, err := NewFromConfig(config.CodeforcesCrawler{BaseURL: s.URL, APIPrefix: "/api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.UserInfo(context.Background(), "nobody_zz")
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !IsNotFound(err) {
+		t.Fatalf("IsNotFound(%v) = false, want true", err)
+	}
+}
+
+func TestClient_UserStatus_PassesPagingAndDecodesProblems(t *testing.T) {
+	t.Parallel()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/api/user.status" || q.Get("handle") != "tourist" || q.Get("from") != "1" || q.Get("count") != "2" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"OK","result":[
+			{"id":2,"contestId":1,"creationTimeSeconds":1700000100,"verdict":"OK","problem":{"contestId":1,"index":"a","name":"Theatre Square","rating":1000}},
+			{"id":1,"contestId":4,"creationTimeSeconds":1700000000,"verdict":"WRONG_ANSWER","problem":{"contestId":4,"index":"A","name":"Watermelon","rating":800}}
+		]}`))
+	}))
+	defer s.Close()
+
+	c, err := NewFromConfig(config.CodeforcesCrawler{BaseURL: s.URL, APIPrefix: "/api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	submissions, err := c.UserStatus(context.Background(), "tourist", 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(submissions) != 2 {
+		t.Fatalf("len(submissions) = %d, want 2", len(submissions))
+	}
+	if submissions[0].Verdict != VerdictOK || submissions[0].Problem.Code() != "1A" {
+		t.Fatalf("unexpected first submission: %+v", submissions[0])
+	}
+	if submissions[1].Problem.Name != "Watermelon" || submissions[1].CreationTimeSeconds != 1700000000 {
+		t.Fatalf("unexpected second submission: %+v", submissions[1])
+	}
+}
+
+func TestNewFromConfig_DefaultsToOfficialAPI(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewFromConfig(config.CodeforcesCrawler{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.baseURL.String(); got != "https://codeforces.com/api" {
+		t.Fatalf("baseURL = %q, want %q", got, "https://codeforces.com/api")
+	}
+}
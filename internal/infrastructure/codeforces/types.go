@@ -0,0 +1,90 @@
+package codeforces
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// StatusOK 是 Codeforces API 成功响应的 status 取值。
+	StatusOK = "OK"
+	// VerdictOK 是 Codeforces 提交记录中“通过”的判题结果。
+	VerdictOK = "OK"
+)
+
+// apiResponse 是 Codeforces 官方 API 的统一响应包：status=OK 时读取 result，FAILED 时读取 comment。
+type apiResponse struct {
+	Status  string          `json:"status"`
+	Comment string          `json:"comment"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// User 对应 user.info 返回的用户资料，仅保留同步需要的字段。
+type User struct {
+	Handle     string `json:"handle"`
+	FirstName  string `json:"firstName"`
+	LastName   string `json:"lastName"`
+	Rating     int    `json:"rating"`
+	MaxRating  int    `json:"maxRating"`
+	Rank       string `json:"rank"`
+	Avatar     string `json:"avatar"`
+	TitlePhoto string `json:"titlePhoto"`
+}
+
+// Problem 对应提交记录中的题目信息；题目唯一标识由 contestId + index 组成，例如 1A。
+type Problem struct {
+	ContestID int      `json:"contestId"`
+	Index     string   `json:"index"`
+	Name      string   `json:"name"`
+	Rating    int      `json:"rating"`
+	Tags      []string `json:"tags"`
+}
+
+// Code 返回题目在题库中的唯一编码；gym/题集题目没有 contestId 时返回空串。
+func (p Problem) Code() string {
+	index := strings.TrimSpace(p.Index)
+	if p.ContestID <= 0 || index == "" {
+		return ""
+	}
+	return fmt.Sprintf("%d%s", p.ContestID, strings.ToUpper(index))
+}
+
+// Submission 对应 user.status 返回的单条提交记录。
+type Submission struct {
+	ID                  int64   `json:"id"`
+	ContestID           int     `json:"contestId"`
+	CreationTimeSeconds int64   `json:"creationTimeSeconds"`
+	Problem             Problem `json:"problem"`
+	Verdict             string  `json:"verdict"`
+}
+
+type RemoteHTTPError struct {
+	URL        string
+	Path       string
+	StatusCode int
+	Body       string
+	Message    string
+}
+
+func (e *RemoteHTTPError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("codeforces remote error: %s (status=%d)", e.Message, e.StatusCode)
+	}
+	return fmt.Sprintf("codeforces remote error: status=%d url=%s", e.StatusCode, e.URL)
+}
+
+// IsNotFound 判断错误是否表示 handle 不存在。
+// Codeforces 对不存在的 handle 返回 400 + "handles: User with handle xxx not found"。
+func IsNotFound(err error) bool {
+	var httpErr *RemoteHTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	if httpErr.StatusCode != http.StatusBadRequest && httpErr.StatusCode != http.StatusOK {
+		return false
+	}
+	return strings.Contains(strings.ToLower(httpErr.Message), "not found")
+}
@@ -2,6 +2,8 @@ package infrastructure
 
 import (
 	"personal_assistant/global"
+	"personal_assistant/internal/infrastructure/atcoder"
+	"personal_assistant/internal/infrastructure/codeforces"
 	"personal_assistant/internal/infrastructure/lanqiao"
 	"personal_assistant/internal/infrastructure/leetcode"
 	"personal_assistant/internal/infrastructure/luogu"
@@ -17,6 +19,12 @@ var (
 
 	// lanqiaoClient 全局单例 Lanqiao 客户端
 	lanqiaoClient *lanqiao.Client
+
+	// codeforcesClient 全局单例 Codeforces 客户端
+	codeforcesClient *codeforces.Client
+
+	// atcoderClient 全局单例 AtCoder 客户端
+	atcoderClient *atcoder.Client
 )
 
 // Init 初始化基础设施层的所有外部服务客户端
@@ -50,6 +58,19 @@ func Init() {
 		global.Log.Panic("failed to init lanqiao client: " + err.Error())
 	}
 	lanqiaoClient = lq
+
+	// 4. 初始化 Codeforces / AtCoder 客户端（未配置 base_url 时直连公开 API）
+	cf, err := codeforces.NewFromConfig(global.Config.Crawler.Codeforces, codeforces.WithLogger(global.Log))
+	if err != nil {
+		global.Log.Panic("failed to init codeforces client: " + err.Error())
+	}
+	codeforcesClient = cf
+
+	ac, err := atcoder.NewFromConfig(global.Config.Crawler.AtCoder, atcoder.WithLogger(global.Log))
+	if err != nil {
+		global.Log.Panic("failed to init atcoder client: " + err.Error())
+	}
+	atcoderClient = ac
 }
 
 // LeetCode 获取全局单例的 LeetCode 客户端
@@ -68,3 +89,13 @@ func Luogu() *luogu.Client {
 func Lanqiao() *lanqiao.Client {
 	return lanqiaoClient
 }
+
+// Codeforces 获取全局单例的 Codeforces 客户端
+func Codeforces() *codeforces.Client {
+	return codeforcesClient
+}
+
+// AtCoder 获取全局单例的 AtCoder 客户端
+func AtCoder() *atcoder.Client {
+	return atcoderClient
+}
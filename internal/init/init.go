@@ -46,6 +46,12 @@ func Init() {
 		os.Exit(1)
 	}
 
+	// 注册请求 DTO 的自定义校验标签（依赖平台注册表，需在路由初始化之前完成）
+	if err := core.InitValidators(); err != nil {
+		global.Log.Error("init validators failed", zap.Error(err))
+		os.Exit(1)
+	}
+
 	infrastructure.Init()
 
 	// 为jwt黑名单开启本地存储
@@ -74,6 +80,10 @@ func Init() {
 	core.InitUploadRateLimiters()
 	// 初始化 OJ 绑定限流器（依赖 Redis）
 	core.InitOJBindRateLimiters()
+	// 初始化找回密码限流器（依赖 Redis）
+	core.InitPasswordResetRateLimiters()
+	// 初始化登录二次验证限流器（依赖 Redis）
+	core.InitTwoFactorLoginRateLimiter()
 	// 初始化flag
 	flag.InitFlag()
 	// 初始化Repository层
@@ -107,6 +117,9 @@ func Init() {
 		global.Redis,
 		global.Log)
 
+	// 开启 SSE 背板转投，多实例下频道事件（如比赛榜单）才能到达本机连接
+	core.StartSSEBackplaneRelay(context.Background())
+
 	// 加载jwt黑名单（使用Repository层）
 	// 为初始化操作设置30秒超时，避免启动时卡死
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -3,6 +3,8 @@ package middleware
 import (
 	"errors"
 
+	"personal_assistant/global"
+	"personal_assistant/internal/model/dto/request"
 	resp "personal_assistant/internal/model/dto/response"
 	bizerrors "personal_assistant/pkg/errors"
 	"personal_assistant/pkg/jwt"
@@ -26,20 +28,9 @@ func JWTAuth() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
-		j := jwt.NewJWT()
-
-		// 解析Access Token
-		claims, err := j.ParseAccessToken(accessToken)
-		if err != nil {
-			// 根据错误类型返回不同的响应
-			var errName string
-			code := bizerrors.CodeTokenInvalid
-			if errors.Is(err, jwt.TokenExpired) {
-				errName = "Access token expired, please refresh"
-				code = bizerrors.CodeTokenExpired
-			} else {
-				errName = "Invalid access token"
-			}
+
+		claims, code, errName := validateAccessToken(accessToken)
+		if claims == nil {
 			response.NewResponse[resp.AuthResponse, resp.AuthResponse](c).
 				SetCode(code).
 				Failed(errName, &resp.AuthResponse{
@@ -55,3 +46,39 @@ func JWTAuth() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// OptionalJWTAuth 可选登录：用于公共路由上"登录后可看到更多内容"的接口。
+// 与 JWTAuth 使用同一套校验，只有会话有效时才写入 claims；未携带、过期或已吊销的令牌一律按匿名处理，不拦截请求。
+func OptionalJWTAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if accessToken := jwt.GetAccessToken(c); accessToken != "" {
+			if claims, _, _ := validateAccessToken(accessToken); claims != nil {
+				c.Set("claims", claims)
+			}
+		}
+		c.Next()
+	}
+}
+
+// validateAccessToken 解析 Access Token 并校验会话未被吊销，失败时返回业务码与提示。
+func validateAccessToken(accessToken string) (*request.JwtCustomClaims, bizerrors.BizCode, string) {
+	j := jwt.NewJWT()
+
+	// 解析Access Token
+	claims, err := j.ParseAccessToken(accessToken)
+	if err != nil {
+		// 根据错误类型返回不同的响应
+		if errors.Is(err, jwt.TokenExpired) {
+			return nil, bizerrors.CodeTokenExpired, "Access token expired, please refresh"
+		}
+		return nil, bizerrors.CodeTokenInvalid, "Invalid access token"
+	}
+
+	// 会话已被吊销（登出、下线设备、刷新令牌重放等）时，未过期的访问令牌同样失效
+	if claims.SessionID != "" {
+		if _, revoked := global.BlackCache.Get(claims.SessionID); revoked {
+			return nil, bizerrors.CodeTokenBlacklisted, "Session has been revoked"
+		}
+	}
+	return claims, 0, ""
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/songzhibin97/gkit/cache/local_cache"
+	"go.uber.org/zap"
+)
+
+func TestOptionalJWTAuthOnlyTrustsValidSessions(t *testing.T) {
+	oldConfig := global.Config
+	oldLog := global.Log
+	oldBlackCache := global.BlackCache
+	global.Config = &config.Config{JWT: config.JWT{
+		AccessTokenSecret:     "test-access-secret",
+		AccessTokenExpiryTime: "15m",
+		Issuer:                "test",
+	}}
+	global.Log = zap.NewNop()
+	global.BlackCache = local_cache.NewCache()
+	t.Cleanup(func() {
+		global.Config = oldConfig
+		global.Log = oldLog
+		global.BlackCache = oldBlackCache
+	})
+
+	j := jwt.NewJWT()
+	validToken, err := j.CreateAccessToken(j.CreateAccessClaims(request.BaseClaims{UserID: 7, SessionID: "sess-valid"}))
+	if err != nil {
+		t.Fatalf("CreateAccessToken() error = %v", err)
+	}
+	revokedToken, err := j.CreateAccessToken(j.CreateAccessClaims(request.BaseClaims{UserID: 8, SessionID: "sess-revoked"}))
+	if err != nil {
+		t.Fatalf("CreateAccessToken() error = %v", err)
+	}
+	global.BlackCache.SetDefault("sess-revoked", struct{}{})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/share", OptionalJWTAuth(), func(c *gin.Context) {
+		c.String(http.StatusOK, strconv.FormatUint(uint64(jwt.GetAuthenticatedUserID(c)), 10))
+	})
+
+	for name, tc := range map[string]struct {
+		token string
+		want  string
+	}{
+		"anonymous": {token: "", want: "0"},
+		"malformed": {token: "not-a-token", want: "0"},
+		"revoked":   {token: revokedToken, want: "0"},
+		"valid":     {token: validToken, want: "7"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/share", nil)
+		if tc.token != "" {
+			req.Header.Set("x-access-token", tc.token)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusOK || recorder.Body.String() != tc.want {
+			t.Fatalf("%s: status=%d body=%q, want 200 %q", name, recorder.Code, recorder.Body.String(), tc.want)
+		}
+	}
+}
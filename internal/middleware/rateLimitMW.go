@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"personal_assistant/global"
+	"personal_assistant/internal/model/consts"
 	"personal_assistant/pkg/errors"
 	"personal_assistant/pkg/jwt"
 	"personal_assistant/pkg/ratelimit"
@@ -142,6 +143,107 @@ func OJBindRateLimitMiddleware(
 	}
 }
 
+// PasswordResetRateLimitMiddleware 找回密码接口限流中间件。
+// 先按客户端 IP 限流，再按请求体中的 account（手机号或邮箱）限流；限流器异常时降级放行。
+func PasswordResetRateLimitMiddleware(
+	ipLimiter, accountLimiter *ratelimit.SlidingWindowLimiter,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		if ipLimiter != nil {
+			result, err := ipLimiter.Allow(ctx, c.ClientIP())
+			if err != nil {
+				global.Log.Warn("找回密码 IP 限流器异常，降级放行", zap.Error(err))
+			} else if !result.Allowed {
+				abortPasswordResetRateLimited(c, result, zap.String("ip", c.ClientIP()))
+				return
+			}
+		}
+
+		account := resolvePasswordResetAccount(c)
+		if accountLimiter != nil && account != "" {
+			result, err := accountLimiter.Allow(ctx, account)
+			if err != nil {
+				global.Log.Warn("找回密码账号限流器异常，降级放行", zap.Error(err))
+			} else if !result.Allowed {
+				abortPasswordResetRateLimited(c, result, zap.String("account", account))
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+func abortPasswordResetRateLimited(c *gin.Context, result *ratelimit.Result, field zap.Field) {
+	setRateLimitHeaders(c, result)
+	global.Log.Warn("找回密码限流触发",
+		field,
+		zap.Int64("current", result.Current),
+		zap.Int("limit", result.Limit),
+		zap.Duration("retry_after", result.RetryAfter))
+	response.BizResultWithStatus(
+		http.StatusTooManyRequests,
+		errors.CodeTooManyRequests,
+		nil,
+		"操作过于频繁，请稍后再试",
+		c,
+	)
+	c.Abort()
+}
+
+// TwoFactorLoginRateLimitMiddleware 登录二次验证接口限流中间件。
+// 按客户端 IP 限流，防止同一来源反复申请挑战猜测动态码；限流器异常时降级放行。
+func TwoFactorLoginRateLimitMiddleware(ipLimiter *ratelimit.SlidingWindowLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ipLimiter == nil {
+			c.Next()
+			return
+		}
+		result, err := ipLimiter.Allow(c.Request.Context(), c.ClientIP())
+		if err != nil {
+			global.Log.Warn("登录二次验证限流器异常，降级放行", zap.Error(err))
+			c.Next()
+			return
+		}
+		setRateLimitHeaders(c, result)
+		if result.Allowed {
+			c.Next()
+			return
+		}
+
+		global.Log.Warn("登录二次验证限流触发",
+			zap.String("ip", c.ClientIP()),
+			zap.Int64("current", result.Current),
+			zap.Int("limit", result.Limit),
+			zap.Duration("retry_after", result.RetryAfter))
+		response.BizResultWithStatus(
+			http.StatusTooManyRequests,
+			errors.CodeTooManyRequests,
+			nil,
+			"操作过于频繁，请稍后再试",
+			c,
+		)
+		c.Abort()
+	}
+}
+
+// resolvePasswordResetAccount 从请求体读取 account 作为账号级限流维度，邮箱统一小写。
+func resolvePasswordResetAccount(c *gin.Context) string {
+	body, err := snapshotRequestBody(c)
+	if err != nil || len(body) == 0 {
+		return ""
+	}
+	var payload struct {
+		Account string `json:"account"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(payload.Account))
+}
+
 // setRateLimitHeaders 设置限流相关响应头，便于客户端感知
 func setRateLimitHeaders(c *gin.Context, result *ratelimit.Result) {
 	c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
@@ -177,7 +279,7 @@ func resolveOJBindPlatform(c *gin.Context) (string, bool) {
 		if len(body) == 0 || !json.Valid(body) {
 			return "", false
 		}
-		return "lanqiao", true
+		return consts.OJPlatformLanqiao, true
 	}
 	if !(strings.HasSuffix(routePath, "/bind") || strings.HasSuffix(requestPath, "/bind")) {
 		return "", false
@@ -194,12 +296,10 @@ func resolveOJBindPlatform(c *gin.Context) (string, bool) {
 	}
 
 	platform := strings.ToLower(strings.TrimSpace(payload.Platform))
-	switch platform {
-	case "leetcode", "luogu":
-		return platform, true
-	default:
+	if !consts.IsOJBindPlatform(platform) {
 		return "", false
 	}
+	return platform, true
 }
 
 func snapshotRequestBody(c *gin.Context) ([]byte, error) {
@@ -68,13 +68,24 @@ type AIMemory struct {
 	// ToolOutputTokenBudget 控制单次工具输出回喂模型前允许占用的最大 token 预算。
 	// 超预算时 runtime 会改成压缩版 feedback envelope，而不是把原始工具输出整段塞回上下文。
 	ToolOutputTokenBudget int `json:"tool_output_token_budget" yaml:"tool_output_token_budget"`
+	// EmbedProvider 指定 embedding 提供方：dashscope（默认）/ openai / ark / local。
+	// local 为本地确定性哈希 embedder，不访问外部服务，适用于测试和离线部署。
+	EmbedProvider string `json:"embed_provider" yaml:"embed_provider"`
+	// EmbedAPIKey 指定 embedding 专用 API Key，为空时复用 ai.api_key。
+	EmbedAPIKey string `json:"embed_api_key" yaml:"embed_api_key"`
+	// EmbedBaseURL 指定 openai / ark 的 OpenAI 兼容接口前缀，请求地址为 {base_url}/embeddings。
+	EmbedBaseURL string `json:"embed_base_url" yaml:"embed_base_url"`
 	// EmbedModel 指定记忆文档使用的 embedding 模型名称。
-	// 默认使用阿里云百炼 qwen3-vl-embedding。
+	// 为空时按 provider 取默认值：dashscope 为 qwen3-vl-embedding，openai 为 text-embedding-3-small，local 为 local-hash-v1。
 	EmbedModel string `json:"embed_model" yaml:"embed_model"`
-	// EmbedEndpoint 指定记忆文档 embedding 的 HTTP 接口地址。
+	// EmbedEndpoint 指定 DashScope embedding 的完整 HTTP 接口地址。
 	EmbedEndpoint string `json:"embed_endpoint" yaml:"embed_endpoint"`
-	// EmbedDimension 指定 embedding 输出维度，必须和 Qdrant memory collection 维度一致。
+	// EmbedDimension 指定 embedding 输出维度，同时决定 Qdrant memory collection 的向量维度。
+	// provider / model / dimension 变化后会切换到新的 memory collection 并由补偿任务重建向量。
 	EmbedDimension int `json:"embed_dimension" yaml:"embed_dimension"`
+	// VectorStore 选择 memory 向量后端：auto（默认，Qdrant 可用时用 Qdrant，否则回退本地）/ qdrant / local。
+	// local 把向量存进 MySQL 并做暴力余弦检索，适合开发机和小规模部署。
+	VectorStore string `json:"vector_store" yaml:"vector_store"`
 	// ChunkMaxChars 控制单个记忆 chunk 的最大字符数。
 	ChunkMaxChars int `json:"chunk_max_chars" yaml:"chunk_max_chars"`
 	// ChunkOverlapChars 控制相邻 chunk 的尾部重叠字符数。
@@ -83,4 +94,23 @@ type AIMemory struct {
 	IndexBatchSize int `json:"index_batch_size" yaml:"index_batch_size"`
 	// IndexTimeoutSeconds 控制单次异步索引任务超时时间。
 	IndexTimeoutSeconds int `json:"index_timeout_seconds" yaml:"index_timeout_seconds"`
+	// LexicalRecallEnabled 控制是否启用 BM25 关键词召回。
+	// 开启后关键词命中与向量命中按 reciprocal rank fusion 融合，补齐题号、slug 等 embedding 难以区分的查询。
+	LexicalRecallEnabled bool `json:"lexical_recall_enabled" yaml:"lexical_recall_enabled"`
+	// RerankProvider 指定融合后的重排方式：none（默认，不重排）/ llm（复用对话模型打分）/ http（cross-encoder 服务）。
+	// 重排失败时保持融合顺序，不影响本轮回答。
+	RerankProvider string `json:"rerank_provider" yaml:"rerank_provider"`
+	// RerankModel 指定重排模型；llm 为空时复用 ai.model，http 必填。
+	RerankModel string `json:"rerank_model" yaml:"rerank_model"`
+	// RerankBaseURL 指定 http 重排服务前缀，请求地址为 {base_url}/rerank。
+	RerankBaseURL string `json:"rerank_base_url" yaml:"rerank_base_url"`
+	// RerankAPIKey 指定 http 重排服务的 API Key，为空时不带鉴权头。
+	RerankAPIKey string `json:"rerank_api_key" yaml:"rerank_api_key"`
+	// RerankTopN 控制融合结果中最多取前多少条送去重排。
+	RerankTopN int `json:"rerank_top_n" yaml:"rerank_top_n"`
+	// RerankTimeoutSeconds 控制单次重排调用的超时时间。
+	RerankTimeoutSeconds int `json:"rerank_timeout_seconds" yaml:"rerank_timeout_seconds"`
+	// RecallTrace 控制是否把长期文档召回明细（向量 / 关键词名次、融合分、重排分）写入 assistant 消息的 trace_items。
+	// 用于排查“为什么召回了这个片段”，默认关闭，避免向普通用户暴露内部打分。
+	RecallTrace bool `json:"recall_trace" yaml:"recall_trace"`
 }
@@ -29,6 +29,7 @@ type Config struct {
 	Qdrant        Qdrant        `json:"qdrant" yaml:"qdrant"`               // Qdrant 向量数据库配置
 	RateLimit     RateLimit     `json:"rate_limit" yaml:"rate_limit"`       // 限流配置
 	Observability Observability `json:"observability" yaml:"observability"` // 观测基础设施配置
+	OAuth         OAuth         `json:"oauth" yaml:"oauth"`                 // 第三方登录配置
 }
 
 // NewConfig 负责创建并返回当前对象所需的实例。
@@ -81,6 +82,11 @@ func NewConfig() *Config {
 
 		// 业务逻辑配置
 		BindCoolDownHours: viper.GetInt("system.bind_cool_down_hours"),
+
+		// 综合排行榜权重
+		RankingWeightLeetcode: viper.GetFloat64("system.ranking_weight_leetcode"),
+		RankingWeightLuogu:    viper.GetFloat64("system.ranking_weight_luogu"),
+		RankingWeightLanqiao:  viper.GetFloat64("system.ranking_weight_lanqiao"),
 	}
 	_security := &Security{
 		SensitiveData: SensitiveData{
@@ -89,6 +95,10 @@ func NewConfig() *Config {
 			AESKeyBase64:  viper.GetString("security.sensitive_data.aes_key_base64"),
 			HashKeyBase64: viper.GetString("security.sensitive_data.hash_key_base64"),
 		},
+		TwoFactor: TwoFactor{
+			Issuer:       viper.GetString("security.two_factor.issuer"),
+			EnforceAdmin: viper.GetBool("security.two_factor.enforce_admin"),
+		},
 	}
 	// 日志配置初始化
 	_zap := &Zap{
@@ -220,6 +230,30 @@ func NewConfig() *Config {
 			RetryMaxWaitMs:         viper.GetInt("crawler.lanqiao.retry_max_wait_ms"),
 			ResponseBodyLimitBytes: viper.GetInt64("crawler.lanqiao.response_body_limit_bytes"),
 		},
+		Codeforces: CodeforcesCrawler{
+			BaseURL:                viper.GetString("crawler.codeforces.base_url"),
+			APIPrefix:              getCrawlerAPIPrefixOr("crawler.codeforces.api_prefix", "/api"),
+			TimeoutMs:              viper.GetInt("crawler.codeforces.timeout_ms"),
+			MaxIdleConns:           viper.GetInt("crawler.codeforces.max_idle_conns"),
+			MaxIdleConnsPerHost:    viper.GetInt("crawler.codeforces.max_idle_conns_per_host"),
+			IdleConnTimeoutSec:     viper.GetInt("crawler.codeforces.idle_conn_timeout_sec"),
+			RetryCount:             viper.GetInt("crawler.codeforces.retry_count"),
+			RetryWaitMs:            viper.GetInt("crawler.codeforces.retry_wait_ms"),
+			RetryMaxWaitMs:         viper.GetInt("crawler.codeforces.retry_max_wait_ms"),
+			ResponseBodyLimitBytes: viper.GetInt64("crawler.codeforces.response_body_limit_bytes"),
+		},
+		AtCoder: AtCoderCrawler{
+			BaseURL:                viper.GetString("crawler.atcoder.base_url"),
+			APIPrefix:              getCrawlerAPIPrefixOr("crawler.atcoder.api_prefix", "/atcoder"),
+			TimeoutMs:              viper.GetInt("crawler.atcoder.timeout_ms"),
+			MaxIdleConns:           viper.GetInt("crawler.atcoder.max_idle_conns"),
+			MaxIdleConnsPerHost:    viper.GetInt("crawler.atcoder.max_idle_conns_per_host"),
+			IdleConnTimeoutSec:     viper.GetInt("crawler.atcoder.idle_conn_timeout_sec"),
+			RetryCount:             viper.GetInt("crawler.atcoder.retry_count"),
+			RetryWaitMs:            viper.GetInt("crawler.atcoder.retry_wait_ms"),
+			RetryMaxWaitMs:         viper.GetInt("crawler.atcoder.retry_max_wait_ms"),
+			ResponseBodyLimitBytes: viper.GetInt64("crawler.atcoder.response_body_limit_bytes"),
+		},
 	}
 
 	_task := &Task{
@@ -239,19 +273,38 @@ func NewConfig() *Config {
 		LeetcodeSyncUserIntervalSeconds: viper.GetInt("task.leetcode_sync_user_interval_seconds"), // 读取力扣用户间隔
 		LeetcodeSyncIntervalSeconds:     viper.GetInt("task.leetcode_sync_interval_seconds"),      // 读取力扣全量间隔
 		RankingSyncIntervalSeconds:      viper.GetInt("task.ranking_sync_interval_seconds"),       // 读取排行榜间隔
-		OJDailyStatsRepairCron:          viper.GetString("task.oj_daily_stats_repair_cron"),
-		OJDailyStatsRepairBatchSize:     viper.GetInt("task.oj_daily_stats_repair_batch_size"),
-		OJDailyStatsRepairWindowDays:    viper.GetInt("task.oj_daily_stats_repair_window_days"),
-		OJTaskDispatchEnabled:           viper.GetBool("task.oj_task_dispatch_enabled"),
-		OJTaskDispatchIntervalSeconds:   viper.GetInt("task.oj_task_dispatch_interval_seconds"),
-		OJTaskDispatchBatchSize:         viper.GetInt("task.oj_task_dispatch_batch_size"),
-		OJTaskDispatchWorkerCount:       viper.GetInt("task.oj_task_dispatch_worker_count"),
-		OJTaskSnapshotInsertBatchSize:   viper.GetInt("task.oj_task_snapshot_insert_batch_size"),
-		OJTaskExecutionLockTTLSeconds:   viper.GetInt("task.oj_task_execution_lock_ttl_seconds"),
-		ImageOrphanCleanupCron:          viper.GetString("task.image_orphan_cleanup_cron"),
-		DisabledUserCleanupEnabled:      viper.GetBool("task.disabled_user_cleanup_enabled"),
-		DisabledUserRetentionDays:       viper.GetInt("task.disabled_user_retention_days"),
-		DisabledUserCleanupCron:         viper.GetString("task.disabled_user_cleanup_cron"),
+		OJProviderSyncIntervalSeconds:   viper.GetInt("task.oj_provider_sync_interval_seconds"),
+		OJProviderSyncUserIntervalSeconds: viper.GetInt(
+			"task.oj_provider_sync_user_interval_seconds",
+		),
+		OJDailyStatsRepairCron:        viper.GetString("task.oj_daily_stats_repair_cron"),
+		OJDailyStatsRepairBatchSize:   viper.GetInt("task.oj_daily_stats_repair_batch_size"),
+		OJDailyStatsRepairWindowDays:  viper.GetInt("task.oj_daily_stats_repair_window_days"),
+		OJTaskDispatchEnabled:         viper.GetBool("task.oj_task_dispatch_enabled"),
+		OJTaskDispatchIntervalSeconds: viper.GetInt("task.oj_task_dispatch_interval_seconds"),
+		OJTaskDispatchBatchSize:       viper.GetInt("task.oj_task_dispatch_batch_size"),
+		OJTaskDispatchWorkerCount:     viper.GetInt("task.oj_task_dispatch_worker_count"),
+		OJTaskSnapshotInsertBatchSize: viper.GetInt("task.oj_task_snapshot_insert_batch_size"),
+		OJTaskExecutionLockTTLSeconds: viper.GetInt("task.oj_task_execution_lock_ttl_seconds"),
+		OJTaskDeadlineEnabled:         viper.GetBool("task.oj_task_deadline_enabled"),
+		OJTaskDeadlineIntervalSeconds: viper.GetInt("task.oj_task_deadline_interval_seconds"),
+		OJTaskDeadlineReminderLeadSeconds: viper.GetInt(
+			"task.oj_task_deadline_reminder_lead_seconds",
+		),
+		OJTaskDeadlineReminderIntervalSeconds: viper.GetInt(
+			"task.oj_task_deadline_reminder_interval_seconds",
+		),
+		OJTaskLateTrackingDays:       viper.GetInt("task.oj_task_late_tracking_days"),
+		OJContestPollEnabled:         viper.GetBool("task.oj_contest_poll_enabled"),
+		OJContestPollIntervalSeconds: viper.GetInt("task.oj_contest_poll_interval_seconds"),
+		OJContestSyncUserIntervalSeconds: viper.GetInt(
+			"task.oj_contest_sync_user_interval_seconds",
+		),
+		ImageOrphanCleanupCron:         viper.GetString("task.image_orphan_cleanup_cron"),
+		DisabledUserCleanupEnabled:     viper.GetBool("task.disabled_user_cleanup_enabled"),
+		DisabledUserRetentionDays:      viper.GetInt("task.disabled_user_retention_days"),
+		DisabledUserCleanupCron:        viper.GetString("task.disabled_user_cleanup_cron"),
+		AIMemoryReindexIntervalSeconds: viper.GetInt("task.ai_memory_reindex_interval_seconds"),
 	}
 
 	// 限流配置初始化
@@ -266,6 +319,16 @@ func NewConfig() *Config {
 			Limit:     viper.GetInt("rate_limit.oj_bind.limit"),
 			WindowSec: viper.GetInt("rate_limit.oj_bind.window_sec"),
 		},
+		PasswordReset: PasswordResetRateLimit{
+			IPLimit:          viper.GetInt("rate_limit.password_reset.ip_limit"),
+			IPWindowSec:      viper.GetInt("rate_limit.password_reset.ip_window_sec"),
+			AccountLimit:     viper.GetInt("rate_limit.password_reset.account_limit"),
+			AccountWindowSec: viper.GetInt("rate_limit.password_reset.account_window_sec"),
+		},
+		TwoFactorLogin: TwoFactorLoginRateLimit{
+			IPLimit:     viper.GetInt("rate_limit.two_factor_login.ip_limit"),
+			IPWindowSec: viper.GetInt("rate_limit.two_factor_login.ip_window_sec"),
+		},
 	}
 
 	_messaging := &Messaging{
@@ -346,13 +409,25 @@ func NewConfig() *Config {
 			ExtractTimeoutSeconds:    viper.GetInt("ai.memory.extract_timeout_seconds"),
 			ExtractMaxChars:          viper.GetInt("ai.memory.extract_max_chars"),
 			ToolOutputTokenBudget:    viper.GetInt("ai.memory.tool_output_token_budget"),
+			EmbedProvider:            viper.GetString("ai.memory.embed_provider"),
+			EmbedAPIKey:              viper.GetString("ai.memory.embed_api_key"),
+			EmbedBaseURL:             viper.GetString("ai.memory.embed_base_url"),
 			EmbedModel:               viper.GetString("ai.memory.embed_model"),
 			EmbedEndpoint:            viper.GetString("ai.memory.embed_endpoint"),
 			EmbedDimension:           viper.GetInt("ai.memory.embed_dimension"),
+			VectorStore:              viper.GetString("ai.memory.vector_store"),
 			ChunkMaxChars:            viper.GetInt("ai.memory.chunk_max_chars"),
 			ChunkOverlapChars:        viper.GetInt("ai.memory.chunk_overlap_chars"),
 			IndexBatchSize:           viper.GetInt("ai.memory.index_batch_size"),
 			IndexTimeoutSeconds:      viper.GetInt("ai.memory.index_timeout_seconds"),
+			LexicalRecallEnabled:     viper.GetBool("ai.memory.lexical_recall_enabled"),
+			RerankProvider:           viper.GetString("ai.memory.rerank_provider"),
+			RerankModel:              viper.GetString("ai.memory.rerank_model"),
+			RerankBaseURL:            viper.GetString("ai.memory.rerank_base_url"),
+			RerankAPIKey:             viper.GetString("ai.memory.rerank_api_key"),
+			RerankTopN:               viper.GetInt("ai.memory.rerank_top_n"),
+			RerankTimeoutSeconds:     viper.GetInt("ai.memory.rerank_timeout_seconds"),
+			RecallTrace:              viper.GetBool("ai.memory.recall_trace"),
 		},
 	}
 
@@ -454,6 +529,7 @@ func NewConfig() *Config {
 		Qdrant:        *_qdrant,
 		RateLimit:     *_rateLimit,
 		Observability: *_observability,
+		OAuth:         OAuth{Providers: readOAuthProviders()},
 	}
 }
 
@@ -471,8 +547,13 @@ func NewConfig() *Config {
 // 注意事项：
 //   - 具体细节需结合函数体与调用方一起理解；当前注释基于函数命名和上下文整理。
 func getCrawlerAPIPrefix(key string) string {
+	return getCrawlerAPIPrefixOr(key, "/v2")
+}
+
+// getCrawlerAPIPrefixOr 读取爬虫 API 前缀，未配置时回退到 fallback；显式配置为空串表示不加前缀。
+func getCrawlerAPIPrefixOr(key string, fallback string) string {
 	if !viper.IsSet(key) {
-		return "/v2"
+		return fallback
 	}
 	return viper.GetString(key)
 }
@@ -3,9 +3,11 @@ package config
 // Crawler 爬虫服务聚合配置
 // 包含所有外部数据源（如 LeetCode、Luogu 等）的客户端配置
 type Crawler struct {
-	LeetCode LeetCodeCrawler `json:"leetcode" yaml:"leetcode"` // 力扣服务配置
-	Luogu    LuoguCrawler    `json:"luogu" yaml:"luogu"`       // 洛谷服务配置
-	Lanqiao  LanqiaoCrawler  `json:"lanqiao" yaml:"lanqiao"`   // 蓝桥服务配置
+	LeetCode   LeetCodeCrawler   `json:"leetcode" yaml:"leetcode"`     // 力扣服务配置
+	Luogu      LuoguCrawler      `json:"luogu" yaml:"luogu"`           // 洛谷服务配置
+	Lanqiao    LanqiaoCrawler    `json:"lanqiao" yaml:"lanqiao"`       // 蓝桥服务配置
+	Codeforces CodeforcesCrawler `json:"codeforces" yaml:"codeforces"` // Codeforces 官方 API 配置
+	AtCoder    AtCoderCrawler    `json:"atcoder" yaml:"atcoder"`       // AtCoder Problems API 配置
 }
 
 // LeetCodeCrawler 力扣客户端详细配置
@@ -53,3 +55,33 @@ type LanqiaoCrawler struct {
 	RetryMaxWaitMs         int    `json:"retry_max_wait_ms" yaml:"retry_max_wait_ms"`
 	ResponseBodyLimitBytes int64  `json:"response_body_limit_bytes" yaml:"response_body_limit_bytes"`
 }
+
+// CodeforcesCrawler Codeforces 客户端详细配置
+// 对应 configs.yaml 中的 crawler.codeforces 节点；直连官方公开 API，base_url 为空时使用 https://codeforces.com/api
+type CodeforcesCrawler struct {
+	BaseURL                string `json:"base_url" yaml:"base_url"`
+	APIPrefix              string `json:"api_prefix" yaml:"api_prefix"`
+	TimeoutMs              int    `json:"timeout_ms" yaml:"timeout_ms"`
+	MaxIdleConns           int    `json:"max_idle_conns" yaml:"max_idle_conns"`
+	MaxIdleConnsPerHost    int    `json:"max_idle_conns_per_host" yaml:"max_idle_conns_per_host"`
+	IdleConnTimeoutSec     int    `json:"idle_conn_timeout_sec" yaml:"idle_conn_timeout_sec"`
+	RetryCount             int    `json:"retry_count" yaml:"retry_count"`
+	RetryWaitMs            int    `json:"retry_wait_ms" yaml:"retry_wait_ms"`
+	RetryMaxWaitMs         int    `json:"retry_max_wait_ms" yaml:"retry_max_wait_ms"`
+	ResponseBodyLimitBytes int64  `json:"response_body_limit_bytes" yaml:"response_body_limit_bytes"`
+}
+
+// AtCoderCrawler AtCoder 客户端详细配置
+// 对应 configs.yaml 中的 crawler.atcoder 节点；AtCoder 官方没有公开 API，数据来自 AtCoder Problems（kenkoooo.com/atcoder）
+type AtCoderCrawler struct {
+	BaseURL                string `json:"base_url" yaml:"base_url"`
+	APIPrefix              string `json:"api_prefix" yaml:"api_prefix"`
+	TimeoutMs              int    `json:"timeout_ms" yaml:"timeout_ms"`
+	MaxIdleConns           int    `json:"max_idle_conns" yaml:"max_idle_conns"`
+	MaxIdleConnsPerHost    int    `json:"max_idle_conns_per_host" yaml:"max_idle_conns_per_host"`
+	IdleConnTimeoutSec     int    `json:"idle_conn_timeout_sec" yaml:"idle_conn_timeout_sec"`
+	RetryCount             int    `json:"retry_count" yaml:"retry_count"`
+	RetryWaitMs            int    `json:"retry_wait_ms" yaml:"retry_wait_ms"`
+	RetryMaxWaitMs         int    `json:"retry_max_wait_ms" yaml:"retry_max_wait_ms"`
+	ResponseBodyLimitBytes int64  `json:"response_body_limit_bytes" yaml:"response_body_limit_bytes"`
+}
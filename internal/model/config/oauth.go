@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// OAuthProviderNameMaxLen 提供方名称最大长度。
+// users.openid 列宽 100，subject 过长时存"提供方:SHA-256 摘要"（64 位十六进制），名称需为其留出空间。
+const OAuthProviderNameMaxLen = 35
+
+// OAuth 第三方登录（OAuth2 授权码 + PKCE / OIDC）配置。
+type OAuth struct {
+	Providers []OAuthProvider `json:"providers" yaml:"providers"`
+}
+
+// OAuthProvider 单个第三方登录提供方。Issuer 为空时按纯 OAuth2 处理，只通过 userinfo 获取身份。
+type OAuthProvider struct {
+	Name         string   `json:"name" yaml:"name" mapstructure:"name"`                            // 提供方标识，用于路由参数与账号绑定，如 github、keycloak
+	DisplayName  string   `json:"display_name" yaml:"display_name" mapstructure:"display_name"`    // 前端按钮展示名称
+	Enabled      bool     `json:"enabled" yaml:"enabled" mapstructure:"enabled"`                   // 是否启用
+	ClientID     string   `json:"client_id" yaml:"client_id" mapstructure:"client_id"`             // 客户端ID
+	ClientSecret string   `json:"client_secret" yaml:"client_secret" mapstructure:"client_secret"` // 客户端密钥，建议通过 OAUTH_<NAME>_CLIENT_SECRET 环境变量注入
+	AuthURL      string   `json:"auth_url" yaml:"auth_url" mapstructure:"auth_url"`                // 授权端点
+	TokenURL     string   `json:"token_url" yaml:"token_url" mapstructure:"token_url"`             // 令牌端点
+	UserInfoURL  string   `json:"userinfo_url" yaml:"userinfo_url" mapstructure:"userinfo_url"`    // 用户信息端点，可空
+	Issuer       string   `json:"issuer" yaml:"issuer" mapstructure:"issuer"`                      // OIDC 签发方，非空时校验 id_token
+	RedirectURL  string   `json:"redirect_url" yaml:"redirect_url" mapstructure:"redirect_url"`    // 前端回调页地址，需与提供方登记的一致
+	Scopes       []string `json:"scopes" yaml:"scopes" mapstructure:"scopes"`                      // 申请的 scope
+	LinkByEmail  bool     `json:"link_by_email" yaml:"link_by_email" mapstructure:"link_by_email"` // 首次登录时按已验证邮箱自动关联已有账号
+	InviteCode   string   `json:"invite_code" yaml:"invite_code" mapstructure:"invite_code"`       // 新用户加入的组织邀请码，为空时只加入全体成员组织
+}
+
+// readOAuthProviders 读取第三方登录提供方列表，客户端密钥允许由环境变量覆盖。
+func readOAuthProviders() []OAuthProvider {
+	var providers []OAuthProvider
+	if err := viper.UnmarshalKey("oauth.providers", &providers); err != nil {
+		return nil
+	}
+	for i := range providers {
+		envKey := "OAUTH_" + strings.ToUpper(strings.ReplaceAll(providers[i].Name, "-", "_")) + "_CLIENT_SECRET"
+		if secret, ok := os.LookupEnv(envKey); ok {
+			providers[i].ClientSecret = secret
+		}
+	}
+	return providers
+}
+
+// Validate 校验提供方配置：名称非空、不含冒号（用作 openid 前缀分隔符）且不超过 OAuthProviderNameMaxLen。
+func (o OAuth) Validate() error {
+	for _, provider := range o.Providers {
+		name := strings.TrimSpace(provider.Name)
+		if name == "" {
+			return fmt.Errorf("oauth provider name is required")
+		}
+		if strings.Contains(name, ":") {
+			return fmt.Errorf("oauth provider name %q must not contain ':'", name)
+		}
+		if len(name) > OAuthProviderNameMaxLen {
+			return fmt.Errorf("oauth provider name %q exceeds %d characters", name, OAuthProviderNameMaxLen)
+		}
+	}
+	return nil
+}
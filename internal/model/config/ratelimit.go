@@ -4,6 +4,9 @@ package config
 type RateLimit struct {
 	Upload UploadRateLimit `json:"upload" yaml:"upload"`   // 上传接口限流配置
 	OJBind OJBindRateLimit `json:"oj_bind" yaml:"oj_bind"` // OJ 绑定接口限流配置
+
+	PasswordReset  PasswordResetRateLimit  `json:"password_reset" yaml:"password_reset"`     // 找回密码接口限流配置
+	TwoFactorLogin TwoFactorLoginRateLimit `json:"two_factor_login" yaml:"two_factor_login"` // 登录二次验证接口限流配置
 }
 
 // UploadRateLimit 上传接口限流参数
@@ -19,3 +22,17 @@ type OJBindRateLimit struct {
 	Limit     int `json:"limit" yaml:"limit"`           // 每窗口最多请求数
 	WindowSec int `json:"window_sec" yaml:"window_sec"` // 窗口大小（秒）
 }
+
+// PasswordResetRateLimit 找回密码接口限流参数（发送验证码与重置共用计数）
+type PasswordResetRateLimit struct {
+	IPLimit          int `json:"ip_limit" yaml:"ip_limit"`                     // 单 IP 每窗口最多请求数
+	IPWindowSec      int `json:"ip_window_sec" yaml:"ip_window_sec"`           // IP 窗口大小（秒）
+	AccountLimit     int `json:"account_limit" yaml:"account_limit"`           // 单账号每窗口最多请求数
+	AccountWindowSec int `json:"account_window_sec" yaml:"account_window_sec"` // 账号窗口大小（秒）
+}
+
+// TwoFactorLoginRateLimit 登录二次验证接口限流参数（校验与强制绑定共用计数）
+type TwoFactorLoginRateLimit struct {
+	IPLimit     int `json:"ip_limit" yaml:"ip_limit"`           // 单 IP 每窗口最多请求数
+	IPWindowSec int `json:"ip_window_sec" yaml:"ip_window_sec"` // IP 窗口大小（秒）
+}
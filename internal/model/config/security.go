@@ -3,6 +3,7 @@ package config
 // Security 安全基础设施配置。
 type Security struct {
 	SensitiveData SensitiveData `json:"sensitive_data" yaml:"sensitive_data"`
+	TwoFactor     TwoFactor     `json:"two_factor" yaml:"two_factor"`
 }
 
 // SensitiveData 定义敏感数据编解码器配置。
@@ -12,3 +13,9 @@ type SensitiveData struct {
 	AESKeyBase64  string `json:"aes_key_base64" yaml:"aes_key_base64"`   // AES密钥，必须是base64编码后的32字节密钥
 	HashKeyBase64 string `json:"hash_key_base64" yaml:"hash_key_base64"` // 哈希密钥，必须是base64编码后的32字节密钥
 }
+
+// TwoFactor 定义二次验证（TOTP）配置，依赖敏感数据编解码器加密密钥。
+type TwoFactor struct {
+	Issuer       string `json:"issuer" yaml:"issuer"`               // 验证器 App 中显示的签发方名称，为空时使用 website.name
+	EnforceAdmin bool   `json:"enforce_admin" yaml:"enforce_admin"` // 是否强制超级管理员与组织管理员开启二次验证
+}
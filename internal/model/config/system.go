@@ -20,6 +20,11 @@ type System struct {
 
 	// 业务逻辑相关
 	BindCoolDownHours int `json:"bind_cool_down_hours" yaml:"bind_cool_down_hours"` // 换绑冷却时间（小时），防止频繁换绑
+
+	// 跨平台综合排行榜：每个平台一道题折算成的综合分，用于抹平各平台题量与难度口径差异；<=0 时按 1 处理
+	RankingWeightLeetcode float64 `json:"ranking_weight_leetcode" yaml:"ranking_weight_leetcode"`
+	RankingWeightLuogu    float64 `json:"ranking_weight_luogu" yaml:"ranking_weight_luogu"`
+	RankingWeightLanqiao  float64 `json:"ranking_weight_lanqiao" yaml:"ranking_weight_lanqiao"`
 }
 
 // Addr 服务器监听地址（主机:端口号）
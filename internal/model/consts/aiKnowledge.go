@@ -0,0 +1,51 @@
+package consts
+
+const (
+	// CapabilityDomainAIKnowledge 是 AI 知识库能力所属的权限域编码。
+	CapabilityDomainAIKnowledge = "ai_knowledge"
+	// CapabilityGroupCodeAIKnowledgeManagement 是 AI 知识库管理能力组编码。
+	CapabilityGroupCodeAIKnowledgeManagement = "ai_knowledge_management"
+	// CapabilityGroupNameAIKnowledgeManagement 是 AI 知识库管理能力组显示名。
+	CapabilityGroupNameAIKnowledgeManagement = "AI知识库管理"
+	// CapabilityCodeAIKnowledgeManage 是组织知识库文档管理能力编码。
+	CapabilityCodeAIKnowledgeManage = "ai.knowledge.manage"
+)
+
+// AIKnowledgeFormat 知识库文档的原始内容格式。
+type AIKnowledgeFormat string
+
+const (
+	// AIKnowledgeFormatMarkdown 表示 markdown 文档，切块时会按标题、列表、表格和代码块分段。
+	AIKnowledgeFormatMarkdown AIKnowledgeFormat = "markdown"
+	// AIKnowledgeFormatText 表示纯文本文档，按段落切块。
+	AIKnowledgeFormatText AIKnowledgeFormat = "text"
+)
+
+// AIKnowledgeCapabilitySeeds 返回 AI 知识库相关 capability 定义。
+func AIKnowledgeCapabilitySeeds() []CapabilitySeed {
+	return []CapabilitySeed{
+		{
+			Code:      CapabilityCodeAIKnowledgeManage,
+			Name:      "管理组织知识库",
+			Domain:    CapabilityDomainAIKnowledge,
+			GroupCode: CapabilityGroupCodeAIKnowledgeManagement,
+			GroupName: CapabilityGroupNameAIKnowledgeManagement,
+			Desc:      "允许上传、更新、删除组织知识库文档并查看版本与召回统计",
+		},
+	}
+}
+
+// AIKnowledgeCapabilityCodes 返回 AI 知识库 capability code 列表副本，避免调用方持有共享底层切片。
+func AIKnowledgeCapabilityCodes() []string {
+	return []string{CapabilityCodeAIKnowledgeManage}
+}
+
+// IsValidAIKnowledgeFormat 判断知识库文档格式是否属于当前系统允许的枚举值。
+func IsValidAIKnowledgeFormat(format string) bool {
+	switch AIKnowledgeFormat(format) {
+	case AIKnowledgeFormatMarkdown, AIKnowledgeFormatText:
+		return true
+	default:
+		return false
+	}
+}
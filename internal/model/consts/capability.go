@@ -105,9 +105,11 @@ var builtinCapabilitySeeds = []CapabilitySeed{
 
 // BuiltinCapabilitySeeds 返回 capability 种子定义副本。
 func BuiltinCapabilitySeeds() []CapabilitySeed {
-	dst := make([]CapabilitySeed, 0, len(builtinCapabilitySeeds)+len(OJTaskCapabilitySeeds()))
+	dst := make([]CapabilitySeed, 0, len(builtinCapabilitySeeds)+len(OJTaskCapabilitySeeds())+len(OJContestCapabilitySeeds())+len(AIKnowledgeCapabilitySeeds()))
 	dst = append(dst, builtinCapabilitySeeds...)
 	dst = append(dst, OJTaskCapabilitySeeds()...)
+	dst = append(dst, OJContestCapabilitySeeds()...)
+	dst = append(dst, AIKnowledgeCapabilitySeeds()...)
 	return dst
 }
 
@@ -141,6 +143,8 @@ func OrgManageCapabilityCodes() []string {
 func BuiltinOrgAdminCapabilityCodes() []string {
 	codes := append(OrgMemberCapabilityCodes(), OrgManageCapabilityCodes()...)
 	codes = append(codes, OJTaskCapabilityCodes()...)
+	codes = append(codes, OJContestCapabilityCodes()...)
+	codes = append(codes, AIKnowledgeCapabilityCodes()...)
 	dst := make([]string, len(codes))
 	copy(dst, codes)
 	return dst
@@ -0,0 +1,84 @@
+package consts
+
+import "strconv"
+
+// OJContestScoringMode 比赛计分方式。
+type OJContestScoringMode string
+
+const (
+	// OJContestScoringICPC 表示 ICPC 赛制：先比通过题数，再比罚时（各题通过时距开赛的分钟数之和）。
+	OJContestScoringICPC OJContestScoringMode = "icpc"
+	// OJContestScoringIOI 表示 IOI 赛制：按通过题目的分值求和排名。
+	OJContestScoringIOI OJContestScoringMode = "ioi"
+)
+
+// OJContestStatus 比赛状态。
+type OJContestStatus string
+
+const (
+	// OJContestStatusScheduled 表示比赛已创建但尚未开始，此时仍允许修改与删除。
+	OJContestStatusScheduled OJContestStatus = "scheduled"
+	// OJContestStatusRunning 表示比赛进行中，轮询器会持续同步参赛者并推送实时榜单。
+	OJContestStatusRunning OJContestStatus = "running"
+	// OJContestStatusFrozen 表示比赛已结束且最终榜单已冻结落库，不再随刷题事实变化。
+	OJContestStatusFrozen OJContestStatus = "frozen"
+)
+
+const (
+	// OJContestDefaultProblemPoints 是 IOI 赛制下未指定分值时的默认题目分值。
+	OJContestDefaultProblemPoints = 100
+	// OJContestMaxProblems 是单场比赛允许的最大题目数。
+	OJContestMaxProblems = 26
+)
+
+const (
+	// OJContestEventScoreboard 是比赛进行中实时榜单的 SSE 事件名。
+	OJContestEventScoreboard = "scoreboard"
+	// OJContestEventFinal 是比赛结束、榜单冻结后的 SSE 事件名。
+	OJContestEventFinal = "scoreboard_final"
+)
+
+// OJContestScoreboardChannel 返回比赛实时榜单的 SSE 频道名。
+func OJContestScoreboardChannel(contestID uint) string {
+	return "oj_contest:" + strconv.FormatUint(uint64(contestID), 10) + ":scoreboard"
+}
+
+const (
+	// CapabilityDomainOJContest 是 OJ 比赛能力所属的权限域编码。
+	CapabilityDomainOJContest = "oj_contest"
+	// CapabilityGroupCodeOJContestManagement 是 OJ 比赛管理能力组编码。
+	CapabilityGroupCodeOJContestManagement = "oj_contest_management"
+	// CapabilityGroupNameOJContestManagement 是 OJ 比赛管理能力组显示名。
+	CapabilityGroupNameOJContestManagement = "OJ比赛管理"
+	// CapabilityCodeOJContestManage 是 OJ 比赛管理能力编码。
+	CapabilityCodeOJContestManage = "oj.contest.manage"
+)
+
+// OJContestCapabilitySeeds 返回 OJ 比赛相关 capability 定义。
+func OJContestCapabilitySeeds() []CapabilitySeed {
+	return []CapabilitySeed{
+		{
+			Code:      CapabilityCodeOJContestManage,
+			Name:      "管理 OJ 比赛",
+			Domain:    CapabilityDomainOJContest,
+			GroupCode: CapabilityGroupCodeOJContestManagement,
+			GroupName: CapabilityGroupNameOJContestManagement,
+			Desc:      "允许创建、修改、删除组织内的限时 OJ 比赛",
+		},
+	}
+}
+
+// OJContestCapabilityCodes 返回 OJ 比赛 capability code 列表副本，避免调用方持有共享底层切片。
+func OJContestCapabilityCodes() []string {
+	return []string{CapabilityCodeOJContestManage}
+}
+
+// IsValidOJContestScoringMode 判断计分方式是否属于当前系统允许的枚举值。
+func IsValidOJContestScoringMode(mode string) bool {
+	switch OJContestScoringMode(mode) {
+	case OJContestScoringICPC, OJContestScoringIOI:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,46 @@
+package consts
+
+// OJBuiltinPlatforms 是使用独立表结构（*_user_details / *_question_banks）的内置平台，按展示顺序排列。
+var OJBuiltinPlatforms = []string{OJPlatformLuogu, OJPlatformLeetcode, OJPlatformLanqiao}
+
+// OJProviderPlatforms 是数据存放在通用 oj_provider_* 表中的扩展平台，须与服务层注册的 provider 一一对应。
+// 请求校验、绑定限流、排行榜投影与 AI 工具枚举都从这里派生，新增扩展平台只需在此登记并注册 provider。
+var OJProviderPlatforms = []string{OJPlatformCodeforces, OJPlatformAtcoder}
+
+// SupportedOJPlatforms 返回全部受支持的平台标识：内置平台在前，扩展平台在后。
+func SupportedOJPlatforms() []string {
+	out := make([]string, 0, len(OJBuiltinPlatforms)+len(OJProviderPlatforms))
+	out = append(out, OJBuiltinPlatforms...)
+	return append(out, OJProviderPlatforms...)
+}
+
+// OJBindPlatforms 返回可通过通用绑定接口（平台 + 账号标识）绑定的平台；蓝桥杯走独立的手机号密码绑定接口。
+func OJBindPlatforms() []string {
+	out := make([]string, 0, len(OJProviderPlatforms)+2)
+	out = append(out, OJPlatformLuogu, OJPlatformLeetcode)
+	return append(out, OJProviderPlatforms...)
+}
+
+// IsSupportedOJPlatform 判断平台标识是否受支持；按原样比较，调用方需先转小写并去除空白。
+func IsSupportedOJPlatform(platform string) bool {
+	return containsOJPlatform(SupportedOJPlatforms(), platform)
+}
+
+// IsOJBindPlatform 判断平台是否可通过通用绑定接口绑定。
+func IsOJBindPlatform(platform string) bool {
+	return containsOJPlatform(OJBindPlatforms(), platform)
+}
+
+// IsOJProviderPlatform 判断平台是否为扩展平台。
+func IsOJProviderPlatform(platform string) bool {
+	return containsOJPlatform(OJProviderPlatforms, platform)
+}
+
+func containsOJPlatform(platforms []string, platform string) bool {
+	for _, item := range platforms {
+		if item == platform {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package consts
+
+const (
+	// OJRankingModeTotal 表示按累计通过题数排名，为默认模式。
+	OJRankingModeTotal = "total"
+	// OJRankingModeWeighted 表示按题目难度加权后的分数排名，目前只支持力扣与洛谷。
+	OJRankingModeWeighted = "weighted"
+	// OJRankingModeWindow 表示按时间窗口内新增通过题数排名，数据来自 oj_user_daily_stats。
+	OJRankingModeWindow = "window"
+)
+
+const (
+	// OJRankingWindowWeek 表示自然周（周一至今天）。
+	OJRankingWindowWeek = "week"
+	// OJRankingWindowMonth 表示自然月（1 号至今天）。
+	OJRankingWindowMonth = "month"
+	// OJRankingWindowCustom 表示由 start_date / end_date 指定的自定义日期区间。
+	OJRankingWindowCustom = "custom"
+)
+
+const (
+	// OJRankingWeightLeetcodeEasy 是力扣简单题的加权分。
+	OJRankingWeightLeetcodeEasy = 1
+	// OJRankingWeightLeetcodeMedium 是力扣中等题的加权分。
+	OJRankingWeightLeetcodeMedium = 2
+	// OJRankingWeightLeetcodeHard 是力扣困难题的加权分。
+	OJRankingWeightLeetcodeHard = 3
+	// OJRankingWeightLuoguUnrated 是洛谷暂无评定（难度 0）题目的加权分；其余题目按难度等级 1~7 计分。
+	OJRankingWeightLuoguUnrated = 1
+)
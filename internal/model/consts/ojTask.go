@@ -1,5 +1,7 @@
 package consts
 
+import "strconv"
+
 // OJTaskMode 任务创建模式。
 type OJTaskMode string
 
@@ -8,6 +10,18 @@ const (
 	OJTaskModeImmediate OJTaskMode = "immediate"
 	// OJTaskModeScheduled 表示任务按指定时间调度执行，要求提供未来时间的 execute_at。
 	OJTaskModeScheduled OJTaskMode = "scheduled"
+	// OJTaskModeRecurring 表示任务按 recurrence_rule 周期执行，直到 recurrence_end_at，每个周期生成一条独立执行记录。
+	OJTaskModeRecurring OJTaskMode = "recurring"
+)
+
+// OJTaskExportFormat 执行结果导出格式。
+type OJTaskExportFormat string
+
+const (
+	// OJTaskExportFormatCSV 表示导出为带 UTF-8 BOM 的 CSV，便于 Excel 直接打开中文。
+	OJTaskExportFormatCSV OJTaskExportFormat = "csv"
+	// OJTaskExportFormatXLSX 表示导出为单工作表的 XLSX。
+	OJTaskExportFormatXLSX OJTaskExportFormat = "xlsx"
 )
 
 // OJTaskStatus 任务版本状态。
@@ -58,6 +72,8 @@ const (
 	OJTaskExecutionTriggerExecuteNow OJTaskExecutionTriggerType = "execute_now"
 	// OJTaskExecutionTriggerRetry 表示基于历史执行结果重新发起一次执行。
 	OJTaskExecutionTriggerRetry OJTaskExecutionTriggerType = "retry"
+	// OJTaskExecutionTriggerRecurrenceDue 表示周期任务的后续周期到点后由调度器物化并触发执行。
+	OJTaskExecutionTriggerRecurrenceDue OJTaskExecutionTriggerType = "recurrence_due"
 )
 
 // OJTaskExecutionUserItemResultStatus 执行用户题目结果状态。
@@ -68,6 +84,8 @@ const (
 	OJTaskExecutionUserItemResultCompleted OJTaskExecutionUserItemResultStatus = "completed"
 	// OJTaskExecutionUserItemResultPending 表示用户在该题目上尚未满足完成条件。
 	OJTaskExecutionUserItemResultPending OJTaskExecutionUserItemResultStatus = "pending"
+	// OJTaskExecutionUserItemResultCompletedLate 表示用户在截止时间之后才完成该题目，不计入按时完成统计。
+	OJTaskExecutionUserItemResultCompletedLate OJTaskExecutionUserItemResultStatus = "completed_late"
 )
 
 // OJTaskExecutionUserItemPendingReason pending 原因。
@@ -82,6 +100,18 @@ const (
 	OJTaskExecutionUserItemReasonQuestionNotFound OJTaskExecutionUserItemPendingReason = "question_not_found"
 )
 
+const (
+	// OJTaskNoticeEventDeadlineReminder 是截止前提醒未完成用户的 SSE 事件名。
+	OJTaskNoticeEventDeadlineReminder = "deadline_reminder"
+	// OJTaskNoticeEventResultFrozen 是截止后按时结果冻结的 SSE 事件名。
+	OJTaskNoticeEventResultFrozen = "result_frozen"
+)
+
+// OJTaskNoticeChannel 返回用户 OJ 任务通知的 SSE 频道名。
+func OJTaskNoticeChannel(userID uint) string {
+	return "oj_task_notice:user:" + strconv.FormatUint(uint64(userID), 10)
+}
+
 // OJQuestionSourceStatus 题库来源状态。
 type OJQuestionSourceStatus int8
 
@@ -131,6 +161,10 @@ const (
 	OJPlatformLeetcode = "leetcode"
 	// OJPlatformLanqiao 表示蓝桥杯平台标识。
 	OJPlatformLanqiao = "lanqiao"
+	// OJPlatformCodeforces 表示 Codeforces 平台标识，数据存放在通用的 oj_provider_* 表中。
+	OJPlatformCodeforces = "codeforces"
+	// OJPlatformAtcoder 表示 AtCoder 平台标识，数据存放在通用的 oj_provider_* 表中。
+	OJPlatformAtcoder = "atcoder"
 )
 
 const (
@@ -166,7 +200,7 @@ func OJTaskCapabilityCodes() []string {
 // IsValidOJTaskMode 判断任务创建模式是否属于当前系统允许的枚举值。
 func IsValidOJTaskMode(mode string) bool {
 	switch OJTaskMode(mode) {
-	case OJTaskModeImmediate, OJTaskModeScheduled:
+	case OJTaskModeImmediate, OJTaskModeScheduled, OJTaskModeRecurring:
 		return true
 	default:
 		return false
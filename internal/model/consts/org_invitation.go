@@ -0,0 +1,24 @@
+package consts
+
+import "strconv"
+
+// 组织邀请参数
+const (
+	OrgInvitationDefaultTTLHours = 7 * 24  // 未指定有效期时的默认有效期（小时）
+	OrgInvitationMaxTTLHours     = 30 * 24 // 有效期上限（小时）
+	OrgInvitationMaxUses         = 1000    // 单个邀请允许的最大使用次数
+)
+
+// OrgJoinRequestStatus 加入申请状态
+type OrgJoinRequestStatus string
+
+const (
+	OrgJoinRequestStatusPending  OrgJoinRequestStatus = "pending"  // 待审批
+	OrgJoinRequestStatusApproved OrgJoinRequestStatus = "approved" // 已通过
+	OrgJoinRequestStatusRejected OrgJoinRequestStatus = "rejected" // 已拒绝
+)
+
+// OrgInvitationJoinSource 通过邀请加入时写入 OrgMember.JoinSource 的值，记录具体使用的邀请。
+func OrgInvitationJoinSource(invitationID uint) string {
+	return string(OrgMemberJoinSourceInvite) + ":" + strconv.FormatUint(uint64(invitationID), 10)
+}
@@ -0,0 +1,16 @@
+package consts
+
+// 第三方登录参数
+const (
+	OAuthStateTTLMinutes = 10 // 授权请求 state 有效期（分钟）
+
+	// OAuthPlaceholderPhonePrefix 第三方注册用户未绑定手机号时的占位前缀，
+	// 手机号列唯一且非空，占位值不满足 11 位手机号格式，不会与真实手机号冲突。
+	OAuthPlaceholderPhonePrefix = "oauth_"
+)
+
+// OAuthLoginState 用途
+const (
+	OAuthStatePurposeLogin = "login" // 第三方登录 / 注册
+	OAuthStatePurposeLink  = "link"  // 已登录用户绑定第三方账号
+)
@@ -0,0 +1,8 @@
+package consts
+
+// 找回密码验证码参数
+const (
+	PasswordResetCodeLength      = 6  // 验证码位数
+	PasswordResetCodeTTLMinutes  = 10 // 验证码有效期（分钟）
+	PasswordResetCodeMaxAttempts = 5  // 单个验证码允许的最大失败次数
+)
@@ -6,4 +6,5 @@ type Register int
 const (
 	Email Register = iota // 邮箱验证码注册
 	QQ                    // QQ登录注册
+	OIDC                  // OAuth2/OIDC 第三方登录注册
 )
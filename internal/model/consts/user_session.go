@@ -0,0 +1,11 @@
+package consts
+
+// 会话吊销原因
+const (
+	UserSessionRevokeReasonLogout        = "logout"         // 用户主动登出
+	UserSessionRevokeReasonUserRevoke    = "user_revoke"    // 用户在设备管理中移除
+	UserSessionRevokeReasonTokenReused   = "token_reused"   // 检测到刷新令牌重放，整个会话作废
+	UserSessionRevokeReasonPasswordReset = "password_reset" // 找回密码后吊销全部会话
+	UserSessionRevokeReasonSingleLogin   = "single_login"   // 开启多点登录拦截时被新登录挤下线
+	UserSessionRevokeReasonDisabled      = "disabled"       // 账号被管理员禁用后吊销全部会话
+)
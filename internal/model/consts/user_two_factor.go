@@ -0,0 +1,15 @@
+package consts
+
+// 二次验证参数
+const (
+	TwoFactorChallengeTTLMinutes  = 5  // 登录挑战令牌有效期（分钟）
+	TwoFactorChallengeMaxAttempts = 5  // 单个登录挑战允许的最大失败次数
+	TwoFactorRecoveryCodeCount    = 10 // 每次生成的恢复码数量
+	TwoFactorTOTPSkew             = 1  // 允许前后各 1 个时间步的时钟偏差
+)
+
+// TwoFactorChallenge 用途
+const (
+	TwoFactorChallengePurposeVerify = "verify" // 已启用二次验证，校验动态码或恢复码
+	TwoFactorChallengePurposeEnroll = "enroll" // 策略要求但尚未绑定，先绑定再登录
+)
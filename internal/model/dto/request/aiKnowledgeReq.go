@@ -0,0 +1,34 @@
+package request
+
+// AIKnowledgeDocumentListReq 定义组织知识库文档列表查询参数。
+type AIKnowledgeDocumentListReq struct {
+	OrgID      uint   `form:"org_id" binding:"required,gt=0"`                       // 组织 ID
+	Page       int    `form:"page" binding:"omitempty,min=1"`                       // 页码
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=100"`          // 每页条数
+	Keyword    string `form:"keyword" binding:"omitempty,max=100"`                  // 按标题、摘要、主题和正文模糊搜索
+	MemoryType string `form:"memory_type" binding:"omitempty,oneof=faq procedural"` // 按文档类型过滤
+}
+
+// CreateAIKnowledgeDocumentReq 定义导入组织知识库文档的请求体。
+// 同时支持 JSON 和 multipart/form-data；multipart 上传文件时正文取自文件内容。
+type CreateAIKnowledgeDocumentReq struct {
+	OrgID       uint   `json:"org_id" form:"org_id" binding:"required,gt=0"`                           // 组织 ID
+	MemoryType  string `json:"memory_type" form:"memory_type" binding:"required,oneof=faq procedural"` // 文档类型：faq / procedural
+	Topic       string `json:"topic" form:"topic" binding:"required,max=128"`                          // 主题标签，例如 contest_rules
+	Title       string `json:"title" form:"title" binding:"required,max=255"`                          // 标题
+	Summary     string `json:"summary" form:"summary" binding:"omitempty,max=1000"`                    // 摘要，为空时截取正文开头
+	Format      string `json:"format" form:"format" binding:"omitempty,oneof=markdown text"`           // 内容格式，默认 markdown
+	ContentText string `json:"content_text" form:"content_text"`                                       // 正文，上传文件时忽略
+	ChangeNote  string `json:"change_note" form:"change_note" binding:"omitempty,max=255"`             // 版本说明
+}
+
+// UpdateAIKnowledgeDocumentReq 定义重新导入组织知识库文档的请求体，字段为空表示沿用当前版本。
+type UpdateAIKnowledgeDocumentReq struct {
+	MemoryType  string `json:"memory_type" form:"memory_type" binding:"omitempty,oneof=faq procedural"` // 文档类型
+	Topic       string `json:"topic" form:"topic" binding:"omitempty,max=128"`                          // 主题标签
+	Title       string `json:"title" form:"title" binding:"omitempty,max=255"`                          // 标题
+	Summary     string `json:"summary" form:"summary" binding:"omitempty,max=1000"`                     // 摘要
+	Format      string `json:"format" form:"format" binding:"omitempty,oneof=markdown text"`            // 内容格式
+	ContentText string `json:"content_text" form:"content_text"`                                        // 新正文，变化时重建向量索引
+	ChangeNote  string `json:"change_note" form:"change_note" binding:"omitempty,max=255"`              // 版本说明
+}
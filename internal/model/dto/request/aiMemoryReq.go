@@ -0,0 +1,42 @@
+package request
+
+import "time"
+
+// AIMemoryScopeReq 定义记忆管理接口共用的作用域参数，统一从 query 读取。
+type AIMemoryScopeReq struct {
+	Scope string `form:"scope" binding:"omitempty,oneof=self org platform_ops"` // 记忆作用域，默认 self
+	OrgID *uint  `form:"org_id" binding:"omitempty,gt=0"`                       // 组织 ID，scope=org 时必填
+}
+
+// AIMemoryFactListReq 定义结构化记忆事实列表查询参数。
+type AIMemoryFactListReq struct {
+	AIMemoryScopeReq
+	Page      int    `form:"page" binding:"omitempty,min=1"`
+	PageSize  int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Keyword   string `form:"keyword" binding:"omitempty,max=100"`  // 按摘要、键名和值模糊搜索
+	Namespace string `form:"namespace" binding:"omitempty,max=64"` // 按命名空间过滤
+}
+
+// AIMemoryDocumentListReq 定义长期记忆文档列表查询参数。
+type AIMemoryDocumentListReq struct {
+	AIMemoryScopeReq
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Keyword    string `form:"keyword" binding:"omitempty,max=100"`    // 按标题、摘要、主题和正文模糊搜索
+	MemoryType string `form:"memory_type" binding:"omitempty,max=32"` // 按长期记忆类型过滤
+}
+
+// UpdateAIMemoryFactReq 定义修正结构化记忆事实的请求体，字段为空表示不修改。
+type UpdateAIMemoryFactReq struct {
+	Summary        *string    `json:"summary" binding:"omitempty,max=500"` // 可读摘要
+	FactValueJSON  *string    `json:"fact_value_json"`                     // 事实值，必须是合法 JSON
+	ExpiresAt      *time.Time `json:"expires_at"`                          // 新的过期时间
+	ClearExpiresAt bool       `json:"clear_expires_at"`                    // 为 true 时改为永不过期，优先于 expires_at
+}
+
+// UpdateAIMemoryDocumentReq 定义修正长期记忆文档的请求体，字段为空表示不修改。
+type UpdateAIMemoryDocumentReq struct {
+	Title       *string `json:"title" binding:"omitempty,max=255"`    // 标题
+	Summary     *string `json:"summary" binding:"omitempty,max=1000"` // 摘要
+	ContentText *string `json:"content_text"`                         // 正文，修改后会重建向量索引
+}
@@ -12,3 +12,73 @@ type StreamAssistantMessageReq struct {
 	ContextUserName string `json:"context_user_name" binding:"omitempty,max=100"` // 兼容字段；正式上下文由服务端推导
 	ContextOrgName  string `json:"context_org_name" binding:"omitempty,max=100"`  // 兼容字段；正式上下文由服务端推导
 }
+
+// DecideAssistantInterruptReq 定义工具人工确认决策接口的请求参数结构。
+type DecideAssistantInterruptReq struct {
+	Decision string `json:"decision" binding:"required,oneof=approve reject"` // 用户决策，approve 批准执行，reject 拒绝执行
+	Reason   string `json:"reason" binding:"omitempty,max=500"`               // 决策理由，拒绝时会回填给模型
+}
+
+// EditAssistantMessageReq 定义编辑用户消息并重新发送接口的请求参数结构。
+type EditAssistantMessageReq struct {
+	Content string `json:"content" binding:"required"` // 编辑后的消息内容
+}
+
+// SwitchAssistantBranchReq 定义切换会话活动分支接口的请求参数结构。
+type SwitchAssistantBranchReq struct {
+	MessageID string `json:"message_id" binding:"required,max=64"` // 目标分支上的消息 ID，通常是某个兄弟分支的消息
+}
+
+// ImportAssistantConversationReq 定义会话导入接口的请求参数结构，结构与 JSON 导出文件保持一致。
+type ImportAssistantConversationReq struct {
+	Format       string                            `json:"format" binding:"required,max=64"` // 导出文件格式标识，固定为 personal_assistant.conversation
+	Version      int                               `json:"version" binding:"required,min=1"` // 导出文件格式版本
+	Conversation ImportAssistantConversationMeta   `json:"conversation"`                     // 原会话元信息
+	Messages     []ImportAssistantConversationItem `json:"messages" binding:"required,dive"` // 原会话全部消息，按创建顺序排列
+}
+
+// ImportAssistantConversationMeta 定义导入文件中的会话元信息。
+type ImportAssistantConversationMeta struct {
+	ID           string `json:"id" binding:"omitempty,max=64"`             // 原会话 ID，仅用于追溯，导入后会重新生成
+	Title        string `json:"title"`                                     // 原会话标题，超长时截断
+	ActiveLeafID string `json:"active_leaf_id" binding:"omitempty,max=64"` // 原活动分支叶子消息 ID
+}
+
+// ImportAssistantConversationItem 定义导入文件中的单条消息。
+type ImportAssistantConversationItem struct {
+	ID         string                            `json:"id" binding:"required,max=64"`                 // 原消息 ID，导入后会重新生成
+	ParentID   string                            `json:"parent_id" binding:"omitempty,max=64"`         // 原父消息 ID，根消息为空
+	Role       string                            `json:"role" binding:"required,oneof=user assistant"` // 消息角色
+	Content    string                            `json:"content"`                                      // 消息正文
+	Status     string                            `json:"status" binding:"omitempty,max=32"`            // 原消息状态，未完成状态导入后记为 stopped
+	CreatedAt  string                            `json:"created_at"`                                   // 原消息创建时间，RFC3339 格式
+	TraceItems []ImportAssistantTraceItem        `json:"trace_items"`                                  // 工具调用轨迹，只保留展示字段
+	Scope      *ImportAssistantConversationScope `json:"scope"`                                        // 原消息业务上下文
+	ErrorText  string                            `json:"error_text"`                                   // 原错误文案
+}
+
+// ImportAssistantTraceItem 定义导入文件中的执行轨迹节点，确认类字段不会被导入。
+type ImportAssistantTraceItem struct {
+	Key            string `json:"key"`             // 轨迹节点唯一标识
+	Title          string `json:"title"`           // 轨迹节点标题
+	Description    string `json:"description"`     // 轨迹节点简述
+	Status         string `json:"status"`          // 轨迹状态
+	DurationMS     int64  `json:"duration_ms"`     // 当前步骤耗时，单位毫秒
+	Content        string `json:"content"`         // 当前步骤的简要结果内容
+	DetailMarkdown string `json:"detail_markdown"` // 当前步骤的详细说明
+}
+
+// ImportAssistantConversationScope 定义导入文件中的消息业务上下文。
+type ImportAssistantConversationScope struct {
+	UserName      string `json:"user_name"`       // 原上下文中的用户名
+	OrgName       string `json:"org_name"`        // 原上下文中的组织名
+	ScopeLabel    string `json:"scope_label"`     // 原作用域标签
+	TaskName      string `json:"task_name"`       // 原关联任务名称
+	DocScopeLabel string `json:"doc_scope_label"` // 原文档范围标签
+}
+
+// CreateAssistantShareReq 定义创建会话只读分享链接接口的请求参数结构。
+type CreateAssistantShareReq struct {
+	ExpiresInHours int  `json:"expires_in_hours" binding:"omitempty,min=0,max=8760"` // 有效期（小时），0 或不传表示永不过期
+	OrgOnly        bool `json:"org_only"`                                            // 是否仅限会话所属组织的成员查看
+}
@@ -14,8 +14,9 @@ import (
 
 // BaseClaims 结构体用于存储基本的用户信息，作为JWT的Claim部分
 type BaseClaims struct {
-	UserID uint      // 用户ID，标识用户唯一性
-	UUID   uuid.UUID // 用户的UUID，唯一标识用户
+	UserID    uint      // 用户ID，标识用户唯一性
+	UUID      uuid.UUID // 用户的UUID，唯一标识用户
+	SessionID string    // 登录会话标识，会话被吊销后该会话签发的令牌全部失效
 	// 注意：移除RoleID字段，现在可通过权限服务动态获取用户角色
 }
 
@@ -28,12 +29,21 @@ type JwtCustomClaims struct {
 
 // JwtCustomRefreshClaims 结构体用于存储刷新Token的自定义Claims，包含用户ID和标准的JWT注册信息
 // 仅用户ID + JWT标准，安全要求中（高有效期）
+// RegisteredClaims.ID 为本次签发的刷新令牌ID（jti），用于轮换与重放检测
 type JwtCustomRefreshClaims struct {
-	UserID               uint // 用户ID，用于与刷新Token相关的身份验证
-	jwt.RegisteredClaims      // 标准JWT声明
+	UserID               uint   // 用户ID，用于与刷新Token相关的身份验证
+	SessionID            string // 登录会话标识（令牌族ID）
+	jwt.RegisteredClaims        // 标准JWT声明
 }
 
 type RefreshTokenRequest struct {
 	RefreshToken  string `json:"refreshToken"`
 	XRefreshToken string `json:"x-refresh-token"`
 }
+
+// LoginDevice 签发或轮换令牌时记录的设备信息
+type LoginDevice struct {
+	Label     string // 客户端上报的设备名称，可为空
+	IP        string
+	UserAgent string
+}
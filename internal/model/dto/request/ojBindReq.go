@@ -1,6 +1,11 @@
 package request
 
 type BindOJAccountReq struct {
-	Platform   string `json:"platform" binding:"required,oneof=leetcode luogu"`
+	Platform   string `json:"platform" binding:"required,ojbindplatform"`
 	Identifier string `json:"identifier" binding:"required"`
 }
+
+// UnbindOJAccountReq 解绑 OJ 账号请求，蓝桥与其他平台共用同一入口。
+type UnbindOJAccountReq struct {
+	Platform string `json:"platform" binding:"required,ojplatform"`
+}
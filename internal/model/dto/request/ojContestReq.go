@@ -0,0 +1,39 @@
+package request
+
+import "time"
+
+// OJContestProblemReq 比赛题单中的单道题目，题目须已存在于本地题库。
+type OJContestProblemReq struct {
+	Platform   string `json:"platform" binding:"required,ojplatform"`
+	QuestionID uint   `json:"question_id" binding:"required,gt=0"`
+	Points     int    `json:"points" binding:"omitempty,min=1,max=10000"`
+}
+
+// CreateOJContestReq 创建比赛请求。
+type CreateOJContestReq struct {
+	OrgID       uint                  `json:"org_id" binding:"required,gt=0"`
+	Title       string                `json:"title" binding:"required,max=200"`
+	Description string                `json:"description" binding:"omitempty,max=2000"`
+	ScoringMode string                `json:"scoring_mode" binding:"required,oneof=icpc ioi"`
+	StartAt     *time.Time            `json:"start_at" binding:"required"`
+	EndAt       *time.Time            `json:"end_at" binding:"required"`
+	Problems    []OJContestProblemReq `json:"problems" binding:"required,min=1,max=26,dive"`
+}
+
+// UpdateOJContestReq 更新未开赛的比赛；组织不可变更。
+type UpdateOJContestReq struct {
+	Title       string                `json:"title" binding:"required,max=200"`
+	Description string                `json:"description" binding:"omitempty,max=2000"`
+	ScoringMode string                `json:"scoring_mode" binding:"required,oneof=icpc ioi"`
+	StartAt     *time.Time            `json:"start_at" binding:"required"`
+	EndAt       *time.Time            `json:"end_at" binding:"required"`
+	Problems    []OJContestProblemReq `json:"problems" binding:"required,min=1,max=26,dive"`
+}
+
+// OJContestListReq 比赛列表查询。
+type OJContestListReq struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=200"`
+	OrgID    *uint  `form:"org_id" binding:"omitempty,gt=0"`
+	Status   string `form:"status" binding:"omitempty,oneof=scheduled running frozen"`
+}
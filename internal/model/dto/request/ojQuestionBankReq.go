@@ -0,0 +1,31 @@
+package request
+
+// OJQuestionBankListReq 题库目录检索请求。
+type OJQuestionBankListReq struct {
+	Page         int    `form:"page" binding:"omitempty,min=1"`
+	PageSize     int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Keyword      string `form:"keyword" binding:"omitempty,max=100"`
+	Platform     string `form:"platform" binding:"omitempty,max=32"`
+	Difficulty   string `form:"difficulty" binding:"omitempty,max=32"`
+	SourceStatus *int8  `form:"source_status" binding:"omitempty,oneof=1 2 3"`
+	TagIDs       []uint `form:"tag_ids" binding:"omitempty,max=10,dive,gt=0"`
+}
+
+// CreateOJQuestionTagReq 创建题库标签请求。
+type CreateOJQuestionTagReq struct {
+	Name        string `json:"name" binding:"required,max=32"`
+	Description string `json:"description" binding:"omitempty,max=255"`
+}
+
+// UpdateOJQuestionTagReq 修改题库标签请求。
+type UpdateOJQuestionTagReq struct {
+	Name        string `json:"name" binding:"required,max=32"`
+	Description string `json:"description" binding:"omitempty,max=255"`
+}
+
+// SetOJQuestionTagsReq 整体设置一道题的标签，tag_ids 为空表示清空。
+type SetOJQuestionTagsReq struct {
+	Platform   string `json:"platform" binding:"required,max=32"`
+	QuestionID uint   `json:"question_id" binding:"required,gt=0"`
+	TagIDs     []uint `json:"tag_ids" binding:"omitempty,max=20,dive,gt=0"`
+}
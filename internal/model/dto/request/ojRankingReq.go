@@ -3,7 +3,13 @@ package request
 type OJRankingListReq struct {
 	Page     int    `json:"page" binding:"omitempty,min=1"`
 	PageSize int    `json:"page_size" binding:"omitempty,min=1,max=100"`
-	Platform string `json:"platform" binding:"omitempty,oneof=leetcode luogu lanqiao"`
+	Platform string `json:"platform" binding:"omitempty,eq=all|ojplatform"`
 	Scope    string `json:"scope" binding:"omitempty,oneof=current_org all_members org"`
 	OrgID    *uint  `json:"org_id" binding:"omitempty,min=1"`
+	// Mode 为空时按累计过题数（total）排名。
+	Mode string `json:"mode" binding:"omitempty,oneof=total weighted window"`
+	// Window 仅在 window 模式下生效，默认 week；custom 需要同时传 start_date 与 end_date（2006-01-02）。
+	Window    string `json:"window" binding:"omitempty,oneof=week month custom"`
+	StartDate string `json:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
 }
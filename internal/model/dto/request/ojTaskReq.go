@@ -4,14 +4,14 @@ import "time"
 
 // OJTaskItemReq 单个题目请求项。
 type OJTaskItemReq struct {
-	Platform      string `json:"platform" binding:"required,oneof=luogu leetcode lanqiao"`
+	Platform      string `json:"platform" binding:"required,ojplatform"`
 	Title         string `json:"title" binding:"required,max=255"`
 	AnalysisToken string `json:"analysis_token" binding:"omitempty,max=1024"`
 }
 
 // AnalyzeOJTaskTitleItemReq 单个题目分析请求项。
 type AnalyzeOJTaskTitleItemReq struct {
-	Platform string `json:"platform" binding:"required,oneof=luogu leetcode lanqiao"`
+	Platform string `json:"platform" binding:"required,ojplatform"`
 	Title    string `json:"title" binding:"required,max=255"`
 }
 
@@ -22,32 +22,44 @@ type AnalyzeOJTaskTitlesReq struct {
 
 // CreateOJTaskReq 创建任务请求。
 type CreateOJTaskReq struct {
-	Title       string          `json:"title" binding:"required,max=200"`
-	Description string          `json:"description" binding:"omitempty,max=2000"`
-	Mode        string          `json:"mode" binding:"required,oneof=immediate scheduled"`
-	ExecuteAt   *time.Time      `json:"execute_at"`
-	OrgIDs      []uint          `json:"org_ids" binding:"required,min=1,dive,gt=0"`
-	Items       []OJTaskItemReq `json:"items" binding:"required,min=1,dive"`
+	Title           string          `json:"title" binding:"required,max=200"`
+	Description     string          `json:"description" binding:"omitempty,max=2000"`
+	Mode            string          `json:"mode" binding:"required,oneof=immediate scheduled recurring"`
+	ExecuteAt       *time.Time      `json:"execute_at"`
+	RecurrenceRule  string          `json:"recurrence_rule" binding:"omitempty,max=64"`
+	RecurrenceEndAt *time.Time      `json:"recurrence_end_at"`
+	DeadlineAt      *time.Time      `json:"deadline_at"`
+	DeadlineMinutes int             `json:"deadline_minutes" binding:"omitempty,min=1,max=43200"`
+	OrgIDs          []uint          `json:"org_ids" binding:"required,min=1,dive,gt=0"`
+	Items           []OJTaskItemReq `json:"items" binding:"required,min=1,dive"`
 }
 
-// UpdateOJTaskReq 更新未执行的 scheduled 任务版本。
+// UpdateOJTaskReq 更新未执行的 scheduled / recurring 任务版本，模式不可变更。
 type UpdateOJTaskReq struct {
-	Title       string          `json:"title" binding:"required,max=200"`
-	Description string          `json:"description" binding:"omitempty,max=2000"`
-	Mode        string          `json:"mode" binding:"required,eq=scheduled"`
-	ExecuteAt   *time.Time      `json:"execute_at"`
-	OrgIDs      []uint          `json:"org_ids" binding:"required,min=1,dive,gt=0"`
-	Items       []OJTaskItemReq `json:"items" binding:"required,min=1,dive"`
+	Title           string          `json:"title" binding:"required,max=200"`
+	Description     string          `json:"description" binding:"omitempty,max=2000"`
+	Mode            string          `json:"mode" binding:"required,oneof=scheduled recurring"`
+	ExecuteAt       *time.Time      `json:"execute_at"`
+	RecurrenceRule  string          `json:"recurrence_rule" binding:"omitempty,max=64"`
+	RecurrenceEndAt *time.Time      `json:"recurrence_end_at"`
+	DeadlineAt      *time.Time      `json:"deadline_at"`
+	DeadlineMinutes int             `json:"deadline_minutes" binding:"omitempty,min=1,max=43200"`
+	OrgIDs          []uint          `json:"org_ids" binding:"required,min=1,dive,gt=0"`
+	Items           []OJTaskItemReq `json:"items" binding:"required,min=1,dive"`
 }
 
 // ReviseOJTaskReq 基于旧版本派生新版本。
 type ReviseOJTaskReq struct {
-	Title       string          `json:"title" binding:"required,max=200"`
-	Description string          `json:"description" binding:"omitempty,max=2000"`
-	Mode        string          `json:"mode" binding:"required,oneof=immediate scheduled"`
-	ExecuteAt   *time.Time      `json:"execute_at"`
-	OrgIDs      []uint          `json:"org_ids" binding:"required,min=1,dive,gt=0"`
-	Items       []OJTaskItemReq `json:"items" binding:"required,min=1,dive"`
+	Title           string          `json:"title" binding:"required,max=200"`
+	Description     string          `json:"description" binding:"omitempty,max=2000"`
+	Mode            string          `json:"mode" binding:"required,oneof=immediate scheduled recurring"`
+	ExecuteAt       *time.Time      `json:"execute_at"`
+	RecurrenceRule  string          `json:"recurrence_rule" binding:"omitempty,max=64"`
+	RecurrenceEndAt *time.Time      `json:"recurrence_end_at"`
+	DeadlineAt      *time.Time      `json:"deadline_at"`
+	DeadlineMinutes int             `json:"deadline_minutes" binding:"omitempty,min=1,max=43200"`
+	OrgIDs          []uint          `json:"org_ids" binding:"required,min=1,dive,gt=0"`
+	Items           []OJTaskItemReq `json:"items" binding:"required,min=1,dive"`
 }
 
 // OJTaskListReq 任务列表查询。
@@ -58,7 +70,7 @@ type OJTaskListReq struct {
 	OrgID      *uint  `form:"org_id" binding:"omitempty,gt=0"`
 	RootTaskID *uint  `form:"root_task_id" binding:"omitempty,gt=0"`
 	OnlyLatest *bool  `form:"only_latest"`
-	Mode       string `form:"mode" binding:"omitempty,oneof=immediate scheduled"`
+	Mode       string `form:"mode" binding:"omitempty,oneof=immediate scheduled recurring"`
 	Status     string `form:"status" binding:"omitempty,oneof=scheduled queued executing succeeded failed deleted"`
 }
 
@@ -72,3 +84,45 @@ type OJTaskExecutionUserListReq struct {
 	AllCompleted *bool  `form:"all_completed"`
 	Username     string `form:"username" binding:"omitempty,max=50"`
 }
+
+// OJTaskExecutionExportReq 执行结果导出，筛选条件与执行用户列表一致。
+type OJTaskExecutionExportReq struct {
+	Format       string `form:"format" binding:"omitempty,oneof=csv xlsx"`
+	AllCompleted *bool  `form:"all_completed"`
+	Username     string `form:"username" binding:"omitempty,max=50"`
+}
+
+// OJTaskOccurrenceListReq 周期执行对比查询。
+type OJTaskOccurrenceListReq struct {
+	// Limit 是返回最近多少个周期，默认 12。
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// OJQuestionIntakeListReq 跨任务待解析题目查询。
+type OJQuestionIntakeListReq struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=200"`
+	Platform string `form:"platform" binding:"omitempty,ojplatform"`
+}
+
+// OJQuestionIntakeCandidateReq 为待解析标题推荐题库候选。
+type OJQuestionIntakeCandidateReq struct {
+	Platform   string `form:"platform" binding:"required,ojplatform"`
+	InputTitle string `form:"input_title" binding:"required,max=255"`
+}
+
+// ConfirmOJQuestionIntakeReq 把待解析标题确认为题库中的已有题目。
+type ConfirmOJQuestionIntakeReq struct {
+	Platform   string `json:"platform" binding:"required,ojplatform"`
+	InputTitle string `json:"input_title" binding:"required,max=255"`
+	QuestionID uint   `json:"question_id" binding:"required,gt=0"`
+}
+
+// CreateManualOJQuestionReq 为待解析标题手工录入题库题目；title 为空时沿用输入标题。
+type CreateManualOJQuestionReq struct {
+	Platform     string `json:"platform" binding:"required,ojplatform"`
+	InputTitle   string `json:"input_title" binding:"required,max=255"`
+	QuestionCode string `json:"question_code" binding:"required,max=64"`
+	Title        string `json:"title" binding:"omitempty,max=255"`
+	Difficulty   string `json:"difficulty" binding:"omitempty,max=32"`
+}
@@ -50,3 +50,28 @@ type KickMemberReq struct {
 type RecoverMemberReq struct {
 	Reason string `json:"reason" binding:"omitempty,max=200"`
 }
+
+// CreateOrgInvitationReq 创建组织邀请
+type CreateOrgInvitationReq struct {
+	MaxUses         int    `json:"max_uses" binding:"omitempty,min=1,max=1000"`        // 最大使用次数，默认 1（单次邀请）
+	ExpiresInHours  int    `json:"expires_in_hours" binding:"omitempty,min=1,max=720"` // 有效期（小时），默认 7 天
+	TargetEmail     string `json:"target_email" binding:"omitempty,email,max=100"`     // 限定受邀邮箱，可选
+	TargetPhone     string `json:"target_phone" binding:"omitempty,len=11"`            // 限定受邀手机号，可选
+	RequireApproval bool   `json:"require_approval"`                                   // 接受邀请后是否需要管理员审批
+	Note            string `json:"note" binding:"omitempty,max=200"`                   // 备注
+}
+
+// AcceptOrgInvitationReq 接受组织邀请
+type AcceptOrgInvitationReq struct {
+	Token string `json:"token" binding:"required,max=128"`
+}
+
+// OrgJoinRequestListReq 加入申请列表过滤
+type OrgJoinRequestListReq struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending approved rejected"` // 为空时返回全部
+}
+
+// ReviewOrgJoinRequestReq 审批加入申请
+type ReviewOrgJoinRequestReq struct {
+	Note string `json:"note" binding:"omitempty,max=200"`
+}
@@ -26,6 +26,12 @@ type UpdateProfileReq struct {
 	AvatarID  *uint   `json:"avatar_id" binding:"omitempty"`      // 与 Avatar 成对更新；传 0 表示清空头像
 }
 
+// BindEmailReq 绑定或换绑邮箱，验证码通过 /base/sendEmailVerificationCode 发送到新邮箱
+type BindEmailReq struct {
+	Email            string `json:"email" binding:"required,email,max=100"`
+	VerificationCode string `json:"verification_code" binding:"required,len=6"`
+}
+
 // ChangePhoneReq 换绑手机号
 type ChangePhoneReq struct {
 	Password  string `json:"password" binding:"required,min=8,max=16"`
@@ -40,6 +46,43 @@ type ChangePasswordReq struct {
 	NewPassword string `json:"new_password" binding:"required,min=8,max=16"`
 }
 
+// SendPasswordResetCodeReq 找回密码：申请邮箱验证码
+// Account 可填写手机号或邮箱，验证码统一发送到账号绑定的邮箱
+type SendPasswordResetCodeReq struct {
+	Account   string `json:"account" binding:"required,max=100"`
+	Captcha   string `json:"captcha" binding:"required,len=6"`
+	CaptchaID string `json:"captcha_id" binding:"required"`
+}
+
+// ResetPasswordReq 找回密码：校验邮箱验证码并设置新密码
+type ResetPasswordReq struct {
+	Account     string `json:"account" binding:"required,max=100"`
+	Code        string `json:"code" binding:"required,len=6"`
+	NewPassword string `json:"new_password" binding:"required,min=8,max=16"`
+}
+
+// TwoFactorChallengeReq 登录第二步：凭挑战令牌获取强制绑定所需的密钥
+type TwoFactorChallengeReq struct {
+	ChallengeToken string `json:"challenge_token" binding:"required,max=128"`
+}
+
+// TwoFactorLoginReq 登录第二步：提交动态码或恢复码换取正式令牌
+type TwoFactorLoginReq struct {
+	ChallengeToken string `json:"challenge_token" binding:"required,max=128"`
+	Code           string `json:"code" binding:"required,max=32"` // 6 位动态码或恢复码
+}
+
+// TwoFactorCodeReq 已登录用户确认二次验证操作（启用、关闭、重置恢复码）
+type TwoFactorCodeReq struct {
+	Code string `json:"code" binding:"required,max=32"` // 启用时只接受动态码，其余操作也可使用恢复码
+}
+
+// OAuthCallbackReq 第三方授权回调：前端回调页把提供方返回的 code 与 state 原样提交
+type OAuthCallbackReq struct {
+	Code  string `json:"code" binding:"required,max=512"`
+	State string `json:"state" binding:"required,max=128"`
+}
+
 // UserListReq 用户列表请求，支持分页、组织过滤和关键词搜索
 // Page 和 PageSize 为可选参数，未提供时由 Service 层设置默认值（page=1, page_size=10）
 type UserListReq struct {
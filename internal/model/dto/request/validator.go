@@ -0,0 +1,26 @@
+package request
+
+import (
+	"personal_assistant/internal/model/consts"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// ValidatorTagOJPlatform 校验字段是否为受支持的 OJ 平台，取值来自 consts.SupportedOJPlatforms。
+	ValidatorTagOJPlatform = "ojplatform"
+	// ValidatorTagOJBindPlatform 校验字段是否为可走通用绑定接口的 OJ 平台，取值来自 consts.OJBindPlatforms。
+	ValidatorTagOJBindPlatform = "ojbindplatform"
+)
+
+// RegisterValidators 向校验引擎注册请求 DTO 使用的自定义校验标签。
+func RegisterValidators(v *validator.Validate) error {
+	if err := v.RegisterValidation(ValidatorTagOJPlatform, func(fl validator.FieldLevel) bool {
+		return consts.IsSupportedOJPlatform(fl.Field().String())
+	}); err != nil {
+		return err
+	}
+	return v.RegisterValidation(ValidatorTagOJBindPlatform, func(fl validator.FieldLevel) bool {
+		return consts.IsOJBindPlatform(fl.Field().String())
+	})
+}
@@ -0,0 +1,42 @@
+package response
+
+// AIKnowledgeDocumentResp 表示一份组织知识库文档。
+type AIKnowledgeDocumentResp struct {
+	ID              string  `json:"id"`
+	OrgID           uint    `json:"org_id"`                      // 所属组织 ID
+	MemoryType      string  `json:"memory_type"`                 // 文档类型：faq / procedural
+	Topic           string  `json:"topic"`                       // 主题标签
+	Title           string  `json:"title"`                       // 标题
+	Summary         string  `json:"summary"`                     // 摘要
+	Format          string  `json:"format,omitempty"`            // 当前版本的内容格式，仅详情接口返回
+	ContentText     string  `json:"content_text,omitempty"`      // 正文，仅详情接口返回
+	CurrentVersion  int     `json:"current_version"`             // 当前版本号
+	ChunkCount      int     `json:"chunk_count,omitempty"`       // 已建立索引的片段数，仅详情接口返回
+	RecallCount     int64   `json:"recall_count"`                // 累计被召回的轮次
+	LastRecallScore float64 `json:"last_recall_score,omitempty"` // 最近一次召回的最高相似度
+	LastRecalledAt  string  `json:"last_recalled_at,omitempty"`  // 最近一次召回时间
+	CreatedAt       string  `json:"created_at"`
+	UpdatedAt       string  `json:"updated_at"`
+}
+
+// AIKnowledgeDocumentListResp 组织知识库文档分页响应。
+type AIKnowledgeDocumentListResp struct {
+	List     []*AIKnowledgeDocumentResp `json:"list"`
+	Total    int64                      `json:"total"`
+	Page     int                        `json:"page"`
+	PageSize int                        `json:"page_size"`
+}
+
+// AIKnowledgeVersionResp 表示知识库文档的一个历史版本。
+type AIKnowledgeVersionResp struct {
+	VersionNo   int    `json:"version_no"`             // 版本号
+	MemoryType  string `json:"memory_type"`            // 该版本的文档类型
+	Topic       string `json:"topic"`                  // 该版本的主题标签
+	Title       string `json:"title"`                  // 该版本的标题
+	Format      string `json:"format"`                 // 该版本的内容格式
+	ContentHash string `json:"content_hash"`           // 正文哈希，可用于比较两个版本是否一致
+	ContentText string `json:"content_text,omitempty"` // 正文快照，仅版本详情接口返回
+	ChangeNote  string `json:"change_note,omitempty"`  // 版本说明
+	OperatorID  uint   `json:"operator_id"`            // 导入人
+	CreatedAt   string `json:"created_at"`
+}
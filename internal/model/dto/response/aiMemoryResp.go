@@ -0,0 +1,65 @@
+package response
+
+// AIMemoryFactResp 表示一条结构化记忆事实。
+type AIMemoryFactResp struct {
+	ID            uint    `json:"id"`
+	ScopeType     string  `json:"scope_type"`             // 作用域类型：self / org / platform_ops
+	Visibility    string  `json:"visibility"`             // 访问等级
+	OrgID         *uint   `json:"org_id,omitempty"`       // 组织 ID，仅组织记忆返回
+	Namespace     string  `json:"namespace"`              // 业务命名空间，如 user_preference
+	FactKey       string  `json:"fact_key"`               // 命名空间下的事实键
+	FactValueJSON string  `json:"fact_value_json"`        // 事实值 JSON
+	Summary       string  `json:"summary"`                // 可读摘要
+	Confidence    float64 `json:"confidence"`             // 置信度
+	SourceKind    string  `json:"source_kind"`            // 来源类型，用于解释“为什么会记住这条”
+	SourceID      string  `json:"source_id,omitempty"`    // 来源 ID，通常是触发写回的消息 ID
+	EffectiveAt   string  `json:"effective_at,omitempty"` // 生效时间
+	ExpiresAt     string  `json:"expires_at,omitempty"`   // 过期时间，为空表示永不过期
+	Expired       bool    `json:"expired"`                // 是否已过期；过期记录不再参与召回
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
+}
+
+// AIMemoryFactListResp 结构化记忆事实分页响应。
+type AIMemoryFactListResp struct {
+	List     []*AIMemoryFactResp `json:"list"`
+	Total    int64               `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+}
+
+// AIMemoryDocumentResp 表示一份长期记忆文档。
+type AIMemoryDocumentResp struct {
+	ID          string  `json:"id"`
+	ScopeType   string  `json:"scope_type"`             // 作用域类型：self / org / platform_ops
+	Visibility  string  `json:"visibility"`             // 访问等级
+	OrgID       *uint   `json:"org_id,omitempty"`       // 组织 ID，仅组织记忆返回
+	MemoryType  string  `json:"memory_type"`            // 长期记忆类型
+	Topic       string  `json:"topic"`                  // 主题标签
+	Title       string  `json:"title"`                  // 标题
+	Summary     string  `json:"summary"`                // 摘要
+	ContentText string  `json:"content_text,omitempty"` // 正文，仅详情接口返回
+	Importance  float64 `json:"importance"`             // 重要度
+	SourceKind  string  `json:"source_kind"`            // 来源类型
+	SourceID    string  `json:"source_id,omitempty"`    // 来源 ID
+	ChunkCount  int     `json:"chunk_count,omitempty"`  // 已建立索引的片段数，仅详情接口返回
+	EffectiveAt string  `json:"effective_at,omitempty"` // 生效时间
+	ExpiresAt   string  `json:"expires_at,omitempty"`   // 过期时间，为空表示永不过期
+	Expired     bool    `json:"expired"`                // 是否已过期
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+// AIMemoryDocumentListResp 长期记忆文档分页响应。
+type AIMemoryDocumentListResp struct {
+	List     []*AIMemoryDocumentResp `json:"list"`
+	Total    int64                   `json:"total"`
+	Page     int                     `json:"page"`
+	PageSize int                     `json:"page_size"`
+}
+
+// AIMemoryClearResp 表示清空某个记忆作用域的结果。
+type AIMemoryClearResp struct {
+	DeletedFacts     int64 `json:"deleted_facts"`     // 删除的事实条数
+	DeletedDocuments int   `json:"deleted_documents"` // 删除的文档份数
+}
@@ -1,5 +1,7 @@
 package response
 
+import "io"
+
 // AssistantConversationGroup 表示会话列表中的时间分组。
 type AssistantConversationGroup string
 
@@ -55,15 +57,90 @@ type AssistantScopeInfo struct {
 
 // AssistantMessageResp 表示单条消息的响应结构。
 type AssistantMessageResp struct {
-	ID             string               `json:"id"`                   // 消息唯一标识。
-	ConversationID string               `json:"conversation_id"`      // 所属会话 ID。
-	Role           string               `json:"role"`                 // 消息角色，如 user / assistant / system。
-	Content        string               `json:"content"`              // 消息最终正文内容。
-	CreatedAt      string               `json:"created_at"`           // 消息创建时间的格式化字符串。
-	Status         string               `json:"status"`               // 消息状态，如 loading / success / error / stopped。
-	TraceItems     []AssistantTraceItem `json:"trace_items"`          // 当前消息关联的执行轨迹列表，前端会把这些轨迹折叠进同一条消息显示。
-	Scope          *AssistantScopeInfo  `json:"scope,omitempty"`      // 当前消息关联的作用域信息，为空表示无额外上下文。
-	ErrorText      string               `json:"error_text,omitempty"` // 错误信息文本，通常在失败场景下返回。
+	ID             string               `json:"id"`                    // 消息唯一标识。
+	ConversationID string               `json:"conversation_id"`       // 所属会话 ID。
+	ParentID       string               `json:"parent_id,omitempty"`   // 父消息 ID，根消息为空。
+	SiblingIDs     []string             `json:"sibling_ids,omitempty"` // 同一父消息下的全部分支消息 ID（含自身，按创建时间排序），仅存在多个分支时返回。
+	Role           string               `json:"role"`                  // 消息角色，如 user / assistant / system。
+	Content        string               `json:"content"`               // 消息最终正文内容。
+	CreatedAt      string               `json:"created_at"`            // 消息创建时间的格式化字符串。
+	Status         string               `json:"status"`                // 消息状态，如 loading / success / error / stopped。
+	TraceItems     []AssistantTraceItem `json:"trace_items"`           // 当前消息关联的执行轨迹列表，前端会把这些轨迹折叠进同一条消息显示。
+	Scope          *AssistantScopeInfo  `json:"scope,omitempty"`       // 当前消息关联的作用域信息，为空表示无额外上下文。
+	ErrorText      string               `json:"error_text,omitempty"`  // 错误信息文本，通常在失败场景下返回。
+}
+
+// AssistantConversationExport 表示会话 JSON 导出文件的结构，也是导入接口接受的格式。
+type AssistantConversationExport struct {
+	Format       string                               `json:"format"`       // 文件格式标识，固定为 personal_assistant.conversation。
+	Version      int                                  `json:"version"`      // 文件格式版本，结构不兼容变更时递增。
+	ExportedAt   string                               `json:"exported_at"`  // 导出时间，RFC3339 格式。
+	Conversation AssistantConversationExportMeta      `json:"conversation"` // 会话元信息。
+	Messages     []AssistantConversationExportMessage `json:"messages"`     // 会话全部分支上的消息，按创建顺序排列。
+}
+
+// AssistantConversationExportMeta 表示导出文件中的会话元信息。
+type AssistantConversationExportMeta struct {
+	ID           string `json:"id"`                       // 原会话 ID。
+	Title        string `json:"title"`                    // 会话标题。
+	CreatedAt    string `json:"created_at"`               // 会话创建时间，RFC3339 格式。
+	UpdatedAt    string `json:"updated_at"`               // 会话最近更新时间，RFC3339 格式。
+	ActiveLeafID string `json:"active_leaf_id,omitempty"` // 活动分支叶子消息 ID。
+}
+
+// AssistantConversationExportMessage 表示导出文件中的单条消息。
+type AssistantConversationExportMessage struct {
+	ID         string               `json:"id"`                   // 原消息 ID。
+	ParentID   string               `json:"parent_id,omitempty"`  // 父消息 ID，根消息为空。
+	Role       string               `json:"role"`                 // 消息角色，user / assistant。
+	Content    string               `json:"content"`              // 消息正文。
+	Status     string               `json:"status"`               // 消息状态。
+	CreatedAt  string               `json:"created_at"`           // 消息创建时间，RFC3339 格式。
+	TraceItems []AssistantTraceItem `json:"trace_items"`          // 工具调用等执行轨迹。
+	Scope      *AssistantScopeInfo  `json:"scope,omitempty"`      // 消息关联的业务上下文。
+	ErrorText  string               `json:"error_text,omitempty"` // 错误文案。
+}
+
+// AssistantExportFile 表示需要以附件形式下载的导出文件，由 Controller 直接写出，不走统一 JSON 响应。
+// Write 非空时由 Controller 设置附件头后调用它流式写出，此时忽略 Content。
+type AssistantExportFile struct {
+	FileName    string                  `json:"file_name"`    // 下载文件名。
+	ContentType string                  `json:"content_type"` // 文件 MIME 类型。
+	Content     []byte                  `json:"-"`            // 文件内容。
+	Write       func(w io.Writer) error `json:"-"`            // 边读边写文件内容。
+}
+
+// AssistantShareResp 表示会话分享链接的管理视图。
+type AssistantShareResp struct {
+	ID             string `json:"id"`                       // 分享 ID，用于撤销。
+	ConversationID string `json:"conversation_id"`          // 被分享的会话 ID。
+	Token          string `json:"token,omitempty"`          // 分享 token，仅创建时返回一次。
+	Title          string `json:"title"`                    // 分享时的会话标题。
+	OrgOnly        bool   `json:"org_only"`                 // 是否仅限会话所属组织成员查看。
+	OrgID          *uint  `json:"org_id,omitempty"`         // 会话所属组织 ID。
+	ExpiresAt      string `json:"expires_at,omitempty"`     // 过期时间，为空表示不过期。
+	RevokedAt      string `json:"revoked_at,omitempty"`     // 撤销时间，为空表示仍有效。
+	ViewCount      int64  `json:"view_count"`               // 查看次数。
+	LastViewedAt   string `json:"last_viewed_at,omitempty"` // 最近查看时间。
+	CreatedAt      string `json:"created_at"`               // 创建时间。
+}
+
+// AssistantSharedConversationResp 表示通过分享链接看到的只读会话快照。
+type AssistantSharedConversationResp struct {
+	Title     string                       `json:"title"`                // 分享时的会话标题。
+	SharedAt  string                       `json:"shared_at"`            // 分享创建时间。
+	ExpiresAt string                       `json:"expires_at,omitempty"` // 过期时间，为空表示不过期。
+	OrgOnly   bool                         `json:"org_only"`             // 是否仅限组织成员查看。
+	Messages  []AssistantSharedMessageResp `json:"messages"`             // 活动分支上的消息快照。
+}
+
+// AssistantSharedMessageResp 表示分享快照中的单条消息，不暴露消息 ID 与业务上下文。
+type AssistantSharedMessageResp struct {
+	Role       string               `json:"role"`        // 消息角色，user / assistant。
+	Content    string               `json:"content"`     // 消息正文。
+	CreatedAt  string               `json:"created_at"`  // 消息创建时间。
+	Status     string               `json:"status"`      // 消息状态。
+	TraceItems []AssistantTraceItem `json:"trace_items"` // 脱敏后的执行轨迹。
 }
 
 // AssistantConversationStartedPayload 表示会话开始事件的载荷。
@@ -1,5 +1,7 @@
 package response
 
+import "time"
+
 // RefreshTokenResponse 刷新token响应结构体
 type RefreshTokenResponse struct {
 	AccessToken          string `json:"access_token"`            // 新的访问令牌
@@ -42,3 +44,15 @@ func (a *AuthResponse) ToResponse(input *AuthResponse) *AuthResponse {
 reload: true 告诉前端需要 完全重置这些状态 。
 
 */
+
+// UserSessionItem 登录设备会话
+type UserSessionItem struct {
+	SessionID   string    `json:"session_id"`
+	DeviceLabel string    `json:"device_label"`
+	IP          string    `json:"ip"`
+	UserAgent   string    `json:"user_agent"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Current     bool      `json:"current"` // 是否为发起请求的当前会话
+}
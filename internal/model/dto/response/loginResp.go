@@ -8,6 +8,7 @@ type LoginResponse struct {
 	AccessToken          string      `json:"access_token"`
 	AccessTokenExpiresAt int64       `json:"access_token_expires_at"`
 	RefreshToken         string      `json:"refresh_token"`
+	RecoveryCodes        []string    `json:"recovery_codes,omitempty"` // 登录时强制绑定二次验证，仅此一次返回恢复码
 }
 
 func (l LoginResponse) ToResponse(input *LoginResponse) *LoginResponse {
@@ -0,0 +1,19 @@
+package response
+
+// OAuthProviderItem 登录页可用的第三方登录方式
+type OAuthProviderItem struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// OAuthAuthorizeResp 发起第三方授权：前端跳转到 authorize_url，回调页再提交 code 与 state
+type OAuthAuthorizeResp struct {
+	AuthorizeURL string `json:"authorize_url"`
+	ExpiresAt    int64  `json:"expires_at"` // state 过期时间戳（毫秒）
+}
+
+// OAuthBindingResp 当前账号的第三方账号绑定状态
+type OAuthBindingResp struct {
+	Linked   bool   `json:"linked"`
+	Provider string `json:"provider,omitempty"`
+}
@@ -11,6 +11,9 @@ type BindOJAccountResp struct {
 	// Luogu: 对应 PassedNumber
 	PassedNumber int `json:"passed_number"`
 
+	// Rating 为平台积分，目前只有 Codeforces 等扩展平台会返回。
+	Rating int `json:"rating,omitempty"`
+
 	// 蓝桥特有字段：提交成功/失败次数，不代表通过题总数。
 	SubmitSuccessCount int `json:"submit_success_count"`
 	SubmitFailedCount  int `json:"submit_failed_count"`
@@ -19,3 +22,13 @@ type BindOJAccountResp struct {
 func (b BindOJAccountResp) ToResponse(input *BindOJAccountResp) *BindOJAccountResp {
 	return input
 }
+
+// UnbindOJAccountResp 解绑结果，Identifier 为被解除绑定的账号标识（蓝桥为脱敏手机号）。
+type UnbindOJAccountResp struct {
+	Platform   string `json:"platform"`
+	Identifier string `json:"identifier"`
+}
+
+func (u UnbindOJAccountResp) ToResponse(input *UnbindOJAccountResp) *UnbindOJAccountResp {
+	return input
+}
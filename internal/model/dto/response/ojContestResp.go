@@ -0,0 +1,76 @@
+package response
+
+// OJContestProblemResp 比赛题目响应项。
+type OJContestProblemResp struct {
+	ID           uint   `json:"id"`
+	SortNo       int    `json:"sort_no"`
+	Label        string `json:"label"`
+	Platform     string `json:"platform"`
+	QuestionID   uint   `json:"question_id"`
+	QuestionCode string `json:"question_code"`
+	Title        string `json:"title"`
+	Points       int    `json:"points"`
+}
+
+// OJContestListItemResp 比赛列表项。
+type OJContestListItemResp struct {
+	ContestID   uint   `json:"contest_id"`
+	OrgID       uint   `json:"org_id"`
+	Title       string `json:"title"`
+	ScoringMode string `json:"scoring_mode"`
+	Status      string `json:"status"`
+	StartAt     string `json:"start_at"`
+	EndAt       string `json:"end_at"`
+	FrozenAt    string `json:"frozen_at,omitempty"`
+	CreatedBy   uint   `json:"created_by"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// OJContestDetailResp 比赛详情。
+type OJContestDetailResp struct {
+	OJContestListItemResp
+	Description string                  `json:"description"`
+	Problems    []*OJContestProblemResp `json:"problems"`
+}
+
+// OJContestCreateResp 创建比赛返回。
+type OJContestCreateResp struct {
+	ContestID uint   `json:"contest_id"`
+	Status    string `json:"status"`
+}
+
+// OJContestScoreboardCellResp 参赛者在单道题目上的结果。
+type OJContestScoreboardCellResp struct {
+	ProblemID      uint   `json:"problem_id"`
+	Label          string `json:"label"`
+	Solved         bool   `json:"solved"`
+	SolvedAt       string `json:"solved_at,omitempty"`
+	ElapsedMinutes int    `json:"elapsed_minutes"`
+	Points         int    `json:"points"`
+}
+
+// OJContestScoreboardRowResp 榜单中的一行。
+type OJContestScoreboardRowResp struct {
+	Rank           int                            `json:"rank"`
+	UserID         uint                           `json:"user_id"`
+	Username       string                         `json:"username"`
+	Avatar         string                         `json:"avatar"`
+	SolvedCount    int                            `json:"solved_count"`
+	PenaltyMinutes int                            `json:"penalty_minutes"`
+	Score          int                            `json:"score"`
+	Items          []*OJContestScoreboardCellResp `json:"items"`
+}
+
+// OJContestScoreboardResp 比赛榜单；Frozen 为 true 时为结束后落库的最终榜单。
+type OJContestScoreboardResp struct {
+	ContestID   uint                          `json:"contest_id"`
+	Status      string                        `json:"status"`
+	ScoringMode string                        `json:"scoring_mode"`
+	Frozen      bool                          `json:"frozen"`
+	StartAt     string                        `json:"start_at"`
+	EndAt       string                        `json:"end_at"`
+	GeneratedAt string                        `json:"generated_at"`
+	Problems    []*OJContestProblemResp       `json:"problems"`
+	Rows        []*OJContestScoreboardRowResp `json:"rows"`
+}
@@ -0,0 +1,33 @@
+package response
+
+import "time"
+
+// OJQuestionTagBriefResp 题目上展示的标签摘要。
+type OJQuestionTagBriefResp struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// OJQuestionBankItemResp 题库目录中的一道题。
+// AnalysisToken 仅对已验证题目下发，可直接作为任务题目的 analysis_token 使用。
+type OJQuestionBankItemResp struct {
+	Platform          string                    `json:"platform"`
+	QuestionID        uint                      `json:"question_id"`
+	QuestionCode      string                    `json:"question_code"`
+	Title             string                    `json:"title"`
+	Difficulty        string                    `json:"difficulty"`
+	SourceStatus      int8                      `json:"source_status"`
+	SourceStatusLabel string                    `json:"source_status_label"`
+	SourceType        string                    `json:"source_type"`
+	LastVerifiedAt    *time.Time                `json:"last_verified_at,omitempty"`
+	Tags              []*OJQuestionTagBriefResp `json:"tags"`
+	AnalysisToken     string                    `json:"analysis_token,omitempty"`
+}
+
+// OJQuestionTagResp 题库标签。
+type OJQuestionTagResp struct {
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	QuestionCount int64  `json:"question_count"`
+}
@@ -4,22 +4,32 @@ type OJRankingListResp struct {
 	List   []*OJRankingListItem `json:"list"`
 	MyRank *OJRankingMyRank     `json:"my_rank,omitempty"`
 	Total  int64                `json:"total"`
+	// Mode 为本次排名模式；weighted 模式下 total_passed 为加权分，window 模式下为区间内新增过题数。
+	Mode      string `json:"mode"`
+	StartDate string `json:"start_date,omitempty"`
+	EndDate   string `json:"end_date,omitempty"`
 }
 
 type OJRankingListItem struct {
-	Rank            int                       `json:"rank"`
-	UserID          uint                      `json:"user_id"`
-	RealName        string                    `json:"real_name"`
-	Avatar          string                    `json:"avatar"`
-	TotalPassed     int                       `json:"total_passed"`
-	CurrentOrg      *OrgSimpleItem            `json:"current_org,omitempty"`
-	PlatformDetails *OJRankingPlatformDetails `json:"platform_details,omitempty"`
+	Rank            int                      `json:"rank"`
+	UserID          uint                     `json:"user_id"`
+	RealName        string                   `json:"real_name"`
+	Avatar          string                   `json:"avatar"`
+	TotalPassed     int                      `json:"total_passed"`
+	CurrentOrg      *OrgSimpleItem           `json:"current_org,omitempty"`
+	PlatformDetails OJRankingPlatformDetails `json:"platform_details,omitempty"`
+	// SolvedTotal 仅在 platform=all 时返回，为各平台过题数直接相加；total_passed 为按权重折算后的综合分。
+	SolvedTotal int `json:"solved_total,omitempty"`
 }
 
-type OJRankingPlatformDetails struct {
-	Luogu    int `json:"luogu,omitempty"`
-	Leetcode int `json:"leetcode,omitempty"`
-	Lanqiao  int `json:"lanqiao,omitempty"`
+// OJRankingPlatformDetails 是按平台标识展示的过题数，只包含非零项。
+type OJRankingPlatformDetails map[string]int
+
+// Set 记录平台过题数，零值不写入，保持与旧版 omitempty 字段一致的输出。
+func (d OJRankingPlatformDetails) Set(platform string, score int) {
+	if score != 0 {
+		d[platform] = score
+	}
 }
 
 type OJRankingMyRank struct {
@@ -18,6 +18,9 @@ type OJStatsResp struct {
 
 	PassedNumber int `json:"passed_number"`
 
+	// Rating 为平台积分，目前只有 Codeforces 等扩展平台会返回。
+	Rating int `json:"rating,omitempty"`
+
 	// 蓝桥特有字段：提交成功/失败次数，不代表通过题总数。
 	SubmitSuccessCount int `json:"submit_success_count"`
 	SubmitFailedCount  int `json:"submit_failed_count"`
@@ -1,5 +1,7 @@
 package response
 
+import "io"
+
 // OJTaskOrgItemResp 任务关联组织响应项。
 type OJTaskOrgItemResp struct {
 	OrgID   uint   `json:"org_id"`
@@ -31,11 +33,17 @@ type OJTaskListItemResp struct {
 	Mode               string `json:"mode"`
 	Status             string `json:"status"`
 	ExecuteAt          string `json:"execute_at,omitempty"`
+	RecurrenceRule     string `json:"recurrence_rule,omitempty"`
+	RecurrenceEndAt    string `json:"recurrence_end_at,omitempty"`
+	NextOccurrenceAt   string `json:"next_occurrence_at,omitempty"`
+	DeadlineAt         string `json:"deadline_at,omitempty"`
+	DeadlineMinutes    int    `json:"deadline_minutes,omitempty"`
 	CreatedBy          uint   `json:"created_by"`
 	UpdatedBy          uint   `json:"updated_by"`
 	CreatedAt          string `json:"created_at"`
 	UpdatedAt          string `json:"updated_at"`
 	ExecutionID        uint   `json:"execution_id"`
+	OccurrenceNo       int    `json:"occurrence_no"`
 	ExecutionStatus    string `json:"execution_status"`
 	TotalUserCount     int    `json:"total_user_count"`
 	CompletedUserCount int    `json:"completed_user_count"`
@@ -43,6 +51,7 @@ type OJTaskListItemResp struct {
 	TotalItemCount     int    `json:"total_item_count"`
 	CompletedItemCount int    `json:"completed_item_count"`
 	PendingItemCount   int    `json:"pending_item_count"`
+	LateItemCount      int    `json:"late_item_count"`
 	OrgCount           int    `json:"org_count"`
 	ItemCount          int    `json:"item_count"`
 }
@@ -99,6 +108,7 @@ type OJTaskAnalyzeResp struct {
 type OJTaskExecutionResp struct {
 	ExecutionID        uint   `json:"execution_id"`
 	TaskID             uint   `json:"task_id"`
+	OccurrenceNo       int    `json:"occurrence_no"`
 	TriggerType        string `json:"trigger_type"`
 	RequestedBy        uint   `json:"requested_by"`
 	Status             string `json:"status"`
@@ -112,6 +122,9 @@ type OJTaskExecutionResp struct {
 	TotalItemCount     int    `json:"total_item_count"`
 	CompletedItemCount int    `json:"completed_item_count"`
 	PendingItemCount   int    `json:"pending_item_count"`
+	LateItemCount      int    `json:"late_item_count"`
+	DeadlineAt         string `json:"deadline_at,omitempty"`
+	ResultFrozenAt     string `json:"result_frozen_at,omitempty"`
 }
 
 // OJTaskDetailResp 任务详情响应。
@@ -125,6 +138,11 @@ type OJTaskDetailResp struct {
 	Mode             string               `json:"mode"`
 	Status           string               `json:"status"`
 	ExecuteAt        string               `json:"execute_at,omitempty"`
+	RecurrenceRule   string               `json:"recurrence_rule,omitempty"`
+	RecurrenceEndAt  string               `json:"recurrence_end_at,omitempty"`
+	NextOccurrenceAt string               `json:"next_occurrence_at,omitempty"`
+	DeadlineAt       string               `json:"deadline_at,omitempty"`
+	DeadlineMinutes  int                  `json:"deadline_minutes,omitempty"`
 	CreatedBy        uint                 `json:"created_by"`
 	UpdatedBy        uint                 `json:"updated_by"`
 	CreatedAt        string               `json:"created_at"`
@@ -136,17 +154,20 @@ type OJTaskDetailResp struct {
 
 // OJTaskVersionItemResp 版本链项。
 type OJTaskVersionItemResp struct {
-	TaskID          uint   `json:"task_id"`
-	RootTaskID      uint   `json:"root_task_id"`
-	ParentTaskID    *uint  `json:"parent_task_id,omitempty"`
-	VersionNo       int    `json:"version_no"`
-	Title           string `json:"title"`
-	Mode            string `json:"mode"`
-	Status          string `json:"status"`
-	ExecuteAt       string `json:"execute_at,omitempty"`
-	CreatedAt       string `json:"created_at"`
-	ExecutionID     uint   `json:"execution_id"`
-	ExecutionStatus string `json:"execution_status"`
+	TaskID           uint   `json:"task_id"`
+	RootTaskID       uint   `json:"root_task_id"`
+	ParentTaskID     *uint  `json:"parent_task_id,omitempty"`
+	VersionNo        int    `json:"version_no"`
+	Title            string `json:"title"`
+	Mode             string `json:"mode"`
+	Status           string `json:"status"`
+	ExecuteAt        string `json:"execute_at,omitempty"`
+	RecurrenceRule   string `json:"recurrence_rule,omitempty"`
+	NextOccurrenceAt string `json:"next_occurrence_at,omitempty"`
+	CreatedAt        string `json:"created_at"`
+	ExecutionID      uint   `json:"execution_id"`
+	OccurrenceNo     int    `json:"occurrence_no"`
+	ExecutionStatus  string `json:"execution_status"`
 }
 
 // OJTaskVersionListResp 版本链响应。
@@ -174,6 +195,7 @@ type OJTaskExecutionUserItemResp struct {
 	ResolvedTitleSnapshot string `json:"resolved_title_snapshot,omitempty"`
 	ResultStatus          string `json:"result_status"`
 	Reason                string `json:"reason,omitempty"`
+	CompletedAt           string `json:"completed_at,omitempty"`
 }
 
 // OJTaskExecutionUserSummaryResp 执行用户分页项。
@@ -185,6 +207,7 @@ type OJTaskExecutionUserSummaryResp struct {
 	UserStatusSnapshot int8                          `json:"user_status_snapshot"`
 	CompletedItemCount int                           `json:"completed_item_count"`
 	PendingItemCount   int                           `json:"pending_item_count"`
+	LateItemCount      int                           `json:"late_item_count"`
 	AllCompleted       bool                          `json:"all_completed"`
 	Orgs               []*OJTaskExecutionUserOrgResp `json:"orgs"`
 }
@@ -206,8 +229,104 @@ type OJTaskExecutionUserDetailResp struct {
 	UserStatusSnapshot int8                           `json:"user_status_snapshot"`
 	CompletedItemCount int                            `json:"completed_item_count"`
 	PendingItemCount   int                            `json:"pending_item_count"`
+	LateItemCount      int                            `json:"late_item_count"`
 	AllCompleted       bool                           `json:"all_completed"`
 	Orgs               []*OJTaskExecutionUserOrgResp  `json:"orgs"`
 	CompletedItems     []*OJTaskExecutionUserItemResp `json:"completed_items"`
+	LateItems          []*OJTaskExecutionUserItemResp `json:"late_items"`
 	PendingItems       []*OJTaskExecutionUserItemResp `json:"pending_items"`
 }
+
+// OJTaskExecutionExportFile 执行结果导出文件，由 Controller 设置附件头后调用 Write 流式写出，不走统一 JSON 响应。
+type OJTaskExecutionExportFile struct {
+	FileName    string                  `json:"file_name"`    // 下载文件名。
+	ContentType string                  `json:"content_type"` // 文件 MIME 类型。
+	Write       func(w io.Writer) error `json:"-"`            // 按批读取并写出文件内容。
+}
+
+// OJTaskOccurrenceItemResp 周期对比中的单个周期汇总。
+type OJTaskOccurrenceItemResp struct {
+	ExecutionID        uint    `json:"execution_id"`
+	OccurrenceNo       int     `json:"occurrence_no"`
+	Status             string  `json:"status"`
+	TriggerType        string  `json:"trigger_type"`
+	PlannedAt          string  `json:"planned_at"`
+	FinishedAt         string  `json:"finished_at,omitempty"`
+	TotalUserCount     int     `json:"total_user_count"`
+	CompletedUserCount int     `json:"completed_user_count"`
+	PendingUserCount   int     `json:"pending_user_count"`
+	TotalItemCount     int     `json:"total_item_count"`
+	CompletedItemCount int     `json:"completed_item_count"`
+	CompletionRate     float64 `json:"completion_rate"`
+}
+
+// OJTaskOccurrenceUserResultResp 单个用户在某个周期中的结果。
+type OJTaskOccurrenceUserResultResp struct {
+	ExecutionID        uint `json:"execution_id"`
+	OccurrenceNo       int  `json:"occurrence_no"`
+	CompletedItemCount int  `json:"completed_item_count"`
+	PendingItemCount   int  `json:"pending_item_count"`
+	AllCompleted       bool `json:"all_completed"`
+}
+
+// OJTaskOccurrenceUserResp 单个用户跨周期的结果对比。
+type OJTaskOccurrenceUserResp struct {
+	UserID                   uint                              `json:"user_id"`
+	UsernameSnapshot         string                            `json:"username_snapshot"`
+	AvatarSnapshot           string                            `json:"avatar_snapshot"`
+	CompletedOccurrenceCount int                               `json:"completed_occurrence_count"`
+	Results                  []*OJTaskOccurrenceUserResultResp `json:"results"`
+}
+
+// OJTaskOccurrenceCompareResp 周期任务跨周期对比响应。
+type OJTaskOccurrenceCompareResp struct {
+	TaskID           uint                        `json:"task_id"`
+	Mode             string                      `json:"mode"`
+	RecurrenceRule   string                      `json:"recurrence_rule,omitempty"`
+	RecurrenceEndAt  string                      `json:"recurrence_end_at,omitempty"`
+	NextOccurrenceAt string                      `json:"next_occurrence_at,omitempty"`
+	Occurrences      []*OJTaskOccurrenceItemResp `json:"occurrences"`
+	Users            []*OJTaskOccurrenceUserResp `json:"users"`
+}
+
+// OJTaskNoticeResp 用户 OJ 任务通知的 SSE 负载，截止提醒与结果冻结共用。
+type OJTaskNoticeResp struct {
+	TaskID             uint   `json:"task_id"`
+	ExecutionID        uint   `json:"execution_id"`
+	OccurrenceNo       int    `json:"occurrence_no"`
+	Title              string `json:"title"`
+	DeadlineAt         string `json:"deadline_at"`
+	TotalItemCount     int    `json:"total_item_count"`
+	CompletedItemCount int    `json:"completed_item_count"`
+	PendingItemCount   int    `json:"pending_item_count"`
+	LateItemCount      int    `json:"late_item_count"`
+	Frozen             bool   `json:"frozen"`
+}
+
+// OJQuestionIntakeGroupResp 跨任务聚合后的待解析标题。
+type OJQuestionIntakeGroupResp struct {
+	Platform    string `json:"platform"`
+	InputTitle  string `json:"input_title"`
+	IntakeCount int64  `json:"intake_count"`
+	TaskCount   int64  `json:"task_count"`
+}
+
+// OJQuestionIntakeCandidateResp 待解析标题的题库候选，Score 为 0~1 的标题相似度。
+type OJQuestionIntakeCandidateResp struct {
+	Platform     string  `json:"platform"`
+	QuestionID   uint    `json:"question_id"`
+	QuestionCode string  `json:"question_code"`
+	Title        string  `json:"title"`
+	Score        float64 `json:"score"`
+}
+
+// OJQuestionIntakeResolveResp 人工确认或手工录入后的回填结果。
+type OJQuestionIntakeResolveResp struct {
+	Platform          string `json:"platform"`
+	InputTitle        string `json:"input_title"`
+	QuestionID        uint   `json:"question_id"`
+	QuestionCode      string `json:"question_code"`
+	Title             string `json:"title"`
+	ResolvedItemCount int    `json:"resolved_item_count"`
+	ResolvedTaskCount int    `json:"resolved_task_count"`
+}
@@ -28,3 +28,49 @@ type MyOrgItem struct {
 	Name    string `json:"name"`     // 组织名称
 	IsOwner bool   `json:"is_owner"` // 当前用户是否为组织所有者
 }
+
+// OrgInvitationItem 组织邀请信息项（不含令牌明文）
+type OrgInvitationItem struct {
+	ID              uint   `json:"id"`
+	OrgID           uint   `json:"org_id"`
+	TokenHint       string `json:"token_hint"` // 令牌前缀，便于辨认
+	MaxUses         int    `json:"max_uses"`
+	UsedCount       int    `json:"used_count"`
+	ExpiresAt       string `json:"expires_at"`
+	TargetEmail     string `json:"target_email"`
+	TargetPhone     string `json:"target_phone"`
+	RequireApproval bool   `json:"require_approval"`
+	Note            string `json:"note"`
+	Status          string `json:"status"` // active / expired / exhausted / revoked
+	CreatedBy       uint   `json:"created_by"`
+	CreatedAt       string `json:"created_at"`
+	RevokedAt       string `json:"revoked_at,omitempty"`
+}
+
+// OrgInvitationCreatedResp 新建的组织邀请，令牌明文只返回这一次
+type OrgInvitationCreatedResp struct {
+	OrgInvitationItem
+	Token string `json:"token"`
+}
+
+// OrgInvitationAcceptResp 接受邀请结果
+type OrgInvitationAcceptResp struct {
+	OrgID         uint   `json:"org_id"`
+	OrgName       string `json:"org_name"`
+	Status        string `json:"status"`                    // joined：已加入；pending：等待管理员审批
+	JoinRequestID uint   `json:"join_request_id,omitempty"` // 待审批时的申请ID
+}
+
+// OrgJoinRequestItem 加入申请信息项
+type OrgJoinRequestItem struct {
+	ID           uint   `json:"id"`
+	OrgID        uint   `json:"org_id"`
+	UserID       uint   `json:"user_id"`
+	Username     string `json:"username"`
+	InvitationID uint   `json:"invitation_id"`
+	Status       string `json:"status"`
+	ReviewedBy   *uint  `json:"reviewed_by,omitempty"`
+	ReviewedAt   string `json:"reviewed_at,omitempty"`
+	ReviewNote   string `json:"review_note"`
+	CreatedAt    string `json:"created_at"`
+}
@@ -0,0 +1,30 @@
+package response
+
+import "time"
+
+// TwoFactorLoginChallenge 密码校验通过但需要二次验证时的登录响应
+type TwoFactorLoginChallenge struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	Purpose           string `json:"purpose"`    // verify：输入动态码；enroll：先绑定验证器
+	ExpiresAt         int64  `json:"expires_at"` // 挑战令牌过期时间戳（毫秒）
+}
+
+// TwoFactorSetupResp 绑定验证器所需的密钥与 otpauth URI，前端据此渲染二维码
+type TwoFactorSetupResp struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// TwoFactorRecoveryCodesResp 新生成的恢复码，明文只返回这一次
+type TwoFactorRecoveryCodesResp struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorStatusResp 当前账号的二次验证状态
+type TwoFactorStatusResp struct {
+	Enabled                bool       `json:"enabled"`
+	Required               bool       `json:"required"` // 策略是否要求该账号开启
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
@@ -15,6 +15,7 @@ type AIConversation struct {
 	Preview       string         `json:"preview" gorm:"type:varchar(500);not null;default:'';comment:'会话预览'"`
 	IsGenerating  bool           `json:"is_generating" gorm:"not null;default:false;index;comment:'是否正在生成中'"`
 	LastMessageAt *time.Time     `json:"last_message_at,omitempty" gorm:"index;comment:'最后消息时间'"`
+	ActiveLeafID  string         `json:"active_leaf_id" gorm:"type:varchar(64);not null;default:'';comment:'活动分支叶子消息ID'"`
 	CreatedAt     time.Time      `json:"created_at" gorm:"type:datetime;not null;comment:'创建时间'"`
 	UpdatedAt     time.Time      `json:"updated_at" gorm:"type:datetime;not null;comment:'更新时间'"`
 	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index;comment:'软删除时间'"`
@@ -41,6 +42,7 @@ func (AIConversation) TableName() string {
 type AIMessage struct {
 	ID             string         `json:"id" gorm:"type:varchar(64);primaryKey;comment:'AI消息ID'"`
 	ConversationID string         `json:"conversation_id" gorm:"type:varchar(64);index;not null;comment:'会话ID'"`
+	ParentID       string         `json:"parent_id" gorm:"type:varchar(64);index;not null;default:'';comment:'父消息ID'"`
 	Role           string         `json:"role" gorm:"type:varchar(16);not null;comment:'消息角色'"`
 	Content        string         `json:"content" gorm:"type:longtext;not null;comment:'消息正文'"`
 	Status         string         `json:"status" gorm:"type:varchar(32);not null;default:'success';comment:'消息状态'"`
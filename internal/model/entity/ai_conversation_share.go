@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// AIConversationShare 是 AI 会话的只读分享链接。
+// 创建时把活动分支脱敏后固化成快照，之后会话继续对话或删改都不影响已分享的内容。
+// 链接 token 只在创建时返回一次，库里只保存其 sha256，撤销和过期后立即失效。
+type AIConversationShare struct {
+	ID             string `json:"id" gorm:"type:varchar(64);primaryKey;comment:'分享ID'"`
+	ConversationID string `json:"conversation_id" gorm:"type:varchar(64);not null;index;comment:'会话ID'"`
+	UserID         uint   `json:"user_id" gorm:"not null;index;comment:'分享者用户ID'"`
+	// OrgID 是分享时会话所属组织快照；OrgOnly 为 true 时只有该组织活跃成员可以查看。
+	OrgID   *uint `json:"org_id,omitempty" gorm:"comment:'会话组织ID'"`
+	OrgOnly bool  `json:"org_only" gorm:"not null;default:false;comment:'是否仅组织成员可见'"`
+	// TokenHash 是分享 token 的 sha256 十六进制串。
+	TokenHash    string     `json:"-" gorm:"type:char(64);not null;uniqueIndex;comment:'分享token哈希'"`
+	Title        string     `json:"title" gorm:"type:varchar(100);not null;default:'';comment:'会话标题快照'"`
+	SnapshotJSON string     `json:"-" gorm:"type:longtext;not null;comment:'脱敏消息快照JSON'"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty" gorm:"comment:'过期时间，为空表示不过期'"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty" gorm:"comment:'撤销时间'"`
+	ViewCount    int64      `json:"view_count" gorm:"not null;default:0;comment:'查看次数'"`
+	LastViewedAt *time.Time `json:"last_viewed_at,omitempty" gorm:"comment:'最近查看时间'"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"type:datetime;not null;comment:'创建时间'"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"type:datetime;not null;comment:'更新时间'"`
+}
+
+// TableName 返回会话分享表名。
+func (AIConversationShare) TableName() string {
+	return "ai_conversation_shares"
+}
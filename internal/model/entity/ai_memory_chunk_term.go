@@ -0,0 +1,26 @@
+package entity
+
+// AIMemoryChunkTerm 是长期记忆 chunk 的关键词倒排记录，供 BM25 召回使用。
+// 题号、slug 这类标识符在 embedding 空间里区分度很低，需要靠精确 term 命中补齐。
+// scope 相关字段冗余自 chunk，检索时直接在倒排表上按作用域过滤。
+type AIMemoryChunkTerm struct {
+	// ChunkID 关联 ai_memory_document_chunks.id。
+	ChunkID string `json:"chunk_id" gorm:"type:varchar(64);primaryKey;comment:'记忆文档chunk ID'"`
+	// Term 是规范化后的小写 term。
+	Term string `json:"term" gorm:"type:varchar(64);primaryKey;index:idx_ai_memory_chunk_terms_term_scope,priority:1;comment:'倒排term'"`
+	// DocumentID 关联 ai_memory_documents.id，便于按文档整体清理。
+	DocumentID string `json:"document_id" gorm:"type:varchar(64);not null;index;comment:'记忆文档ID'"`
+	// ScopeKey 是权限过滤所需的归属键。
+	ScopeKey string `json:"scope_key" gorm:"type:varchar(128);not null;index:idx_ai_memory_chunk_terms_term_scope,priority:2;comment:'记忆作用域键'"`
+	// Visibility 表示访问等级。
+	Visibility string `json:"visibility" gorm:"type:varchar(32);not null;comment:'记忆访问等级'"`
+	// UserID 是关联用户快照。
+	UserID *uint `json:"user_id,omitempty" gorm:"comment:'关联用户ID'"`
+	// TermFreq 是 term 在 chunk 内出现的次数。
+	TermFreq int `json:"term_freq" gorm:"not null;default:0;comment:'词频'"`
+}
+
+// TableName 返回记忆关键词倒排表名。
+func (AIMemoryChunkTerm) TableName() string {
+	return "ai_memory_chunk_terms"
+}
@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// AIMemoryChunkVector 是本地向量库保存的 chunk 向量，用于未部署 Qdrant 时的暴力余弦检索。
+// payload 字段与 Qdrant point payload 保持一致，检索时先用索引列预过滤，再在内存中计算相似度。
+type AIMemoryChunkVector struct {
+	// PointID 与 chunk 的 qdrant_point_id 一致，切换回 Qdrant 时可直接对照。
+	PointID string `json:"point_id" gorm:"type:varchar(128);primaryKey;comment:'向量point ID'"`
+	// CollectionName 区分不同 embedding 空间，语义等同 Qdrant collection。
+	CollectionName string `json:"collection_name" gorm:"type:varchar(191);not null;index:idx_ai_memory_chunk_vectors_scope,priority:1;index:idx_ai_memory_chunk_vectors_document,priority:1;comment:'向量集合名'"`
+	// DocumentID 关联 ai_memory_documents.id。
+	DocumentID string `json:"document_id" gorm:"type:varchar(64);not null;index:idx_ai_memory_chunk_vectors_document,priority:2;comment:'记忆文档ID'"`
+	// ChunkID 关联 ai_memory_document_chunks.id。
+	ChunkID string `json:"chunk_id" gorm:"type:varchar(64);not null;comment:'记忆文档chunk ID'"`
+	// ScopeKey 是权限过滤所需的归属键。
+	ScopeKey string `json:"scope_key" gorm:"type:varchar(128);not null;index:idx_ai_memory_chunk_vectors_scope,priority:2;comment:'记忆作用域键'"`
+	// Visibility 表示访问等级。
+	Visibility string `json:"visibility" gorm:"type:varchar(32);not null;comment:'记忆访问等级'"`
+	// UserID 是关联用户快照。
+	UserID *uint `json:"user_id,omitempty" gorm:"comment:'关联用户ID'"`
+	// OrgID 是关联组织快照。
+	OrgID *uint `json:"org_id,omitempty" gorm:"comment:'关联组织ID'"`
+	// MemoryType 标记长期记忆类型。
+	MemoryType string `json:"memory_type" gorm:"type:varchar(32);not null;comment:'长期记忆类型'"`
+	// Dimension 是向量维度，检索时与 query vector 维度不一致的记录直接跳过。
+	Dimension int `json:"dimension" gorm:"not null;comment:'向量维度'"`
+	// Vector 是小端序 float32 编码的向量。
+	Vector []byte `json:"-" gorm:"type:mediumblob;not null;comment:'向量数据'"`
+	// CreatedAt 表示写入时间。
+	CreatedAt time.Time `json:"created_at" gorm:"type:datetime;not null;comment:'创建时间'"`
+}
+
+// TableName 返回本地向量表名。
+func (AIMemoryChunkVector) TableName() string {
+	return "ai_memory_chunk_vectors"
+}
@@ -36,6 +36,8 @@ type AIMemoryDocumentChunk struct {
 	EmbeddingDimension int `json:"embedding_dimension" gorm:"not null;default:0;index:idx_ai_memory_document_chunks_embedding,priority:2;comment:'Embedding维度'"`
 	// QdrantPointID 是该 chunk 在 Qdrant 中的 point id。
 	QdrantPointID string `json:"qdrant_point_id" gorm:"type:varchar(128);not null;default:'';uniqueIndex:uk_ai_memory_document_chunks_qdrant_point_id;comment:'Qdrant point ID'"`
+	// LexicalLength 是 chunk 切出的关键词 term 总数，作为 BM25 文档长度；0 表示尚未建立关键词倒排。
+	LexicalLength int `json:"lexical_length" gorm:"not null;default:0;index;comment:'关键词term总数'"`
 	// IndexedAt 表示该 chunk 完成 Qdrant 写入的时间。
 	IndexedAt *time.Time `json:"indexed_at,omitempty" gorm:"type:datetime;index;comment:'向量索引时间'"`
 	// CreatedAt 表示首次写入时间。
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// AIMemoryDocumentRecallStat 记录长期记忆文档被 RAG 召回的统计。
+// 单独成表而不是挂在文档上，是为了避免召回计数刷新 updated_at 进而触发重建索引。
+type AIMemoryDocumentRecallStat struct {
+	// DocumentID 关联 ai_memory_documents.id。
+	DocumentID string `json:"document_id" gorm:"type:varchar(64);primaryKey;comment:'记忆文档ID'"`
+	// RecallCount 是文档进入召回结果的累计轮次，同一轮命中多个 chunk 只计一次。
+	RecallCount int64 `json:"recall_count" gorm:"not null;default:0;comment:'累计召回次数'"`
+	// LastScore 是最近一次召回时该文档命中 chunk 的最高相似度。
+	LastScore float64 `json:"last_score" gorm:"not null;default:0;comment:'最近一次召回得分'"`
+	// LastRecalledAt 是最近一次召回时间。
+	LastRecalledAt *time.Time `json:"last_recalled_at,omitempty" gorm:"type:datetime;comment:'最近一次召回时间'"`
+}
+
+// TableName 返回记忆文档召回统计表名。
+func (AIMemoryDocumentRecallStat) TableName() string {
+	return "ai_memory_document_recall_stats"
+}
@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+// AIMemoryDocumentVersion 保存管理员导入知识库文档时的历史版本快照。
+// 长期记忆文档本身只保留当前版本，历史版本只用于审计和回看，不参与召回。
+type AIMemoryDocumentVersion struct {
+	// ID 是版本记录主键。
+	ID uint `json:"id" gorm:"primaryKey;comment:'文档版本主键ID'"`
+	// DocumentID 关联 ai_memory_documents.id。
+	DocumentID string `json:"document_id" gorm:"type:varchar(64);not null;uniqueIndex:uk_ai_memory_document_versions_document_version,priority:1;comment:'记忆文档ID'"`
+	// VersionNo 是文档内递增的版本号，从 1 开始。
+	VersionNo int `json:"version_no" gorm:"not null;uniqueIndex:uk_ai_memory_document_versions_document_version,priority:2;comment:'版本号'"`
+	// OrgID 冗余保存归属组织，便于按组织清理和排查。
+	OrgID *uint `json:"org_id,omitempty" gorm:"index;comment:'关联组织ID'"`
+	// MemoryType 是该版本导入时的长期记忆类型。
+	MemoryType string `json:"memory_type" gorm:"type:varchar(32);not null;comment:'长期记忆类型'"`
+	// Topic 是该版本导入时的主题。
+	Topic string `json:"topic" gorm:"type:varchar(128);not null;default:'';comment:'主题'"`
+	// Title 是该版本导入时的标题。
+	Title string `json:"title" gorm:"type:varchar(255);not null;default:'';comment:'标题'"`
+	// Format 表示原始内容格式，例如 markdown、text。
+	Format string `json:"format" gorm:"type:varchar(16);not null;default:'';comment:'内容格式'"`
+	// ContentText 是该版本的完整正文快照。
+	ContentText string `json:"content_text" gorm:"type:longtext;not null;comment:'正文快照'"`
+	// ContentHash 是规范化正文后的哈希，便于判断两次导入内容是否一致。
+	ContentHash string `json:"content_hash" gorm:"type:char(64);not null;default:'';comment:'正文内容哈希'"`
+	// ChangeNote 是管理员填写的变更说明。
+	ChangeNote string `json:"change_note" gorm:"type:varchar(255);not null;default:'';comment:'变更说明'"`
+	// OperatorID 是导入该版本的用户。
+	OperatorID uint `json:"operator_id" gorm:"not null;default:0;comment:'操作人ID'"`
+	// CreatedAt 表示版本创建时间。
+	CreatedAt time.Time `json:"created_at" gorm:"type:datetime;not null;comment:'创建时间'"`
+}
+
+// TableName 返回记忆文档版本表名。
+func (AIMemoryDocumentVersion) TableName() string {
+	return "ai_memory_document_versions"
+}
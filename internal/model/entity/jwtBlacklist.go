@@ -1,8 +1,12 @@
 package entity
 
-// JwtBlacklist JWT黑名单表 - 存储被撤销的JWT令牌，用于令牌失效管理
+import "time"
+
+// JwtBlacklist JWT黑名单表 - 按会话记录被吊销的令牌族，会话内签发的访问令牌与刷新令牌一并失效
 type JwtBlacklist struct {
 	MODEL
-	JWT string `json:"jwt" gorm:"type:text;not null;comment:'被撤销的JWT令牌'"`
-	// 完整的JWT令牌字符串，用于验证时检查是否已被撤销
+	SessionID string     `json:"session_id" gorm:"type:char(36);index;comment:'被吊销的会话标识'"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" gorm:"type:datetime;index;comment:'会话原始过期时间'"` // 过期后无需再加载到本地缓存
+	// JWT 旧版按令牌字符串拉黑的记录，仅保留历史数据，不再写入
+	JWT string `json:"jwt" gorm:"type:text;comment:'被撤销的JWT令牌（历史数据）'"`
 }
@@ -0,0 +1,100 @@
+package entity
+
+import "time"
+
+// OJContest 表示组织内的一场限时 OJ 比赛。
+// 比赛期间轮询器基于参赛者的过题事实实时计算榜单，结束后把最终榜单冻结到 OJContestStanding。
+type OJContest struct {
+	// MODEL 提供主键、时间戳和软删除字段。
+	MODEL
+	// OrgID 是举办比赛的组织 ID；参赛者为该组织的 active 成员。
+	OrgID uint `json:"org_id" gorm:"not null;index;comment:'组织ID'"`
+	// Title 是比赛标题。
+	Title string `json:"title" gorm:"type:varchar(200);not null;comment:'比赛标题'"`
+	// Description 是比赛说明。
+	Description string `json:"description" gorm:"type:text;comment:'比赛说明'"`
+	// ScoringMode 是计分方式，取值来自 consts.OJContestScoringMode。
+	ScoringMode string `json:"scoring_mode" gorm:"type:varchar(16);not null;comment:'计分方式 icpc|ioi'"`
+	// StartAt 是比赛开始时间。
+	StartAt time.Time `json:"start_at" gorm:"type:datetime;not null;index;comment:'开始时间'"`
+	// EndAt 是比赛结束时间。
+	EndAt time.Time `json:"end_at" gorm:"type:datetime;not null;index;comment:'结束时间'"`
+	// Status 是比赛状态，取值来自 consts.OJContestStatus。
+	Status string `json:"status" gorm:"type:varchar(16);not null;index;comment:'比赛状态'"`
+	// FrozenAt 是最终榜单冻结时间；未冻结时为空。
+	FrozenAt *time.Time `json:"frozen_at,omitempty" gorm:"type:datetime;comment:'榜单冻结时间'"`
+	// CreatedBy 是创建比赛的用户 ID。
+	CreatedBy uint `json:"created_by" gorm:"not null;index;comment:'创建人ID'"`
+	// UpdatedBy 是最后一次修改比赛的用户 ID。
+	UpdatedBy uint `json:"updated_by" gorm:"not null;index;comment:'更新人ID'"`
+}
+
+// OJContestProblem 表示比赛题单中的一道题目。
+// 题目在创建时即解析为本地题库中的已验证题目，并冻结编码与标题快照。
+type OJContestProblem struct {
+	// MODEL 提供主键、时间戳和软删除字段。
+	MODEL
+	// ContestID 是所属比赛 ID。
+	ContestID uint `json:"contest_id" gorm:"not null;index;comment:'比赛ID'"`
+	// SortNo 是题目顺序，从 1 开始。
+	SortNo int `json:"sort_no" gorm:"not null;default:1;comment:'题目顺序'"`
+	// Label 是题目展示编号，如 A、B、C。
+	Label string `json:"label" gorm:"type:varchar(8);not null;default:'';comment:'题目编号'"`
+	// Platform 是 OJ 平台标识。
+	Platform string `json:"platform" gorm:"type:varchar(16);not null;index;comment:'OJ 平台'"`
+	// QuestionID 是本地题库题目 ID。
+	QuestionID uint `json:"question_id" gorm:"not null;index;comment:'本地题库ID'"`
+	// QuestionCode 是题目编码快照。
+	QuestionCode string `json:"question_code" gorm:"type:varchar(64);not null;default:'';comment:'题目编码快照'"`
+	// TitleSnapshot 是创建时冻结的题目标题。
+	TitleSnapshot string `json:"title_snapshot" gorm:"type:varchar(255);not null;default:'';comment:'题目标题快照'"`
+	// Points 是 IOI 赛制下该题分值；ICPC 赛制不使用。
+	Points int `json:"points" gorm:"not null;default:0;comment:'题目分值'"`
+}
+
+// OJContestStanding 表示比赛结束时冻结的参赛者最终排名快照。
+// 与任务执行快照一致，用户展示信息在冻结时固化，后续资料变更或解绑都不会影响历史榜单。
+type OJContestStanding struct {
+	// MODEL 提供主键、时间戳和软删除字段。
+	MODEL
+	// ContestID 是所属比赛 ID。
+	ContestID uint `json:"contest_id" gorm:"not null;index;uniqueIndex:idx_oj_contest_standing_user;comment:'比赛ID'"`
+	// UserID 是参赛用户 ID。
+	UserID uint `json:"user_id" gorm:"not null;index;uniqueIndex:idx_oj_contest_standing_user;comment:'用户ID'"`
+	// UserUUIDSnapshot 是冻结时的用户 UUID。
+	UserUUIDSnapshot string `json:"user_uuid_snapshot" gorm:"type:char(36);not null;default:'';comment:'用户UUID快照'"`
+	// UsernameSnapshot 是冻结时的用户名。
+	UsernameSnapshot string `json:"username_snapshot" gorm:"type:varchar(50);not null;default:'';comment:'用户名快照'"`
+	// AvatarSnapshot 是冻结时的头像地址。
+	AvatarSnapshot string `json:"avatar_snapshot" gorm:"type:varchar(255);not null;default:'';comment:'头像快照'"`
+	// Rank 是最终名次，并列时名次相同。
+	Rank int `json:"rank" gorm:"not null;default:0;index;comment:'名次'"`
+	// SolvedCount 是比赛期间通过的题目数。
+	SolvedCount int `json:"solved_count" gorm:"not null;default:0;comment:'通过题数'"`
+	// PenaltyMinutes 是 ICPC 罚时（分钟）。
+	PenaltyMinutes int `json:"penalty_minutes" gorm:"not null;default:0;comment:'罚时分钟'"`
+	// Score 是 IOI 总分。
+	Score int `json:"score" gorm:"not null;default:0;comment:'总分'"`
+}
+
+// OJContestStandingItem 表示参赛者在单道比赛题目上的冻结结果。
+type OJContestStandingItem struct {
+	// MODEL 提供主键、时间戳和软删除字段。
+	MODEL
+	// ContestID 是所属比赛 ID。
+	ContestID uint `json:"contest_id" gorm:"not null;index;comment:'比赛ID'"`
+	// StandingID 是所属排名快照 ID。
+	StandingID uint `json:"standing_id" gorm:"not null;index;comment:'排名快照ID'"`
+	// UserID 是参赛用户 ID。
+	UserID uint `json:"user_id" gorm:"not null;index;comment:'用户ID'"`
+	// ProblemID 是比赛题目 ID。
+	ProblemID uint `json:"problem_id" gorm:"not null;index;comment:'比赛题目ID'"`
+	// Solved 表示该题是否在比赛时间窗内通过。
+	Solved bool `json:"solved" gorm:"type:boolean;not null;default:false;comment:'是否通过'"`
+	// SolvedAt 是首次通过时间；未通过时为空。
+	SolvedAt *time.Time `json:"solved_at,omitempty" gorm:"type:datetime;comment:'首次通过时间'"`
+	// ElapsedMinutes 是通过时距开赛的分钟数。
+	ElapsedMinutes int `json:"elapsed_minutes" gorm:"not null;default:0;comment:'通过用时分钟'"`
+	// Points 是该题得分。
+	Points int `json:"points" gorm:"not null;default:0;comment:'得分'"`
+}
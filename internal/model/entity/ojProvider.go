@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+// 可插拔 OJ 平台（Codeforces、AtCoder 等）共用以下三张表，按 platform 区分数据，
+// 新接入的平台只需实现服务层 provider，不再为每个平台单独建表。
+// 删除账号详情或题目时，会级联删除 OJProviderUserQuestion。
+
+// OJProviderUserDetail 保存用户在可插拔 OJ 平台上的绑定账号与资料快照。
+// 注意：通过题总数以 OJProviderUserQuestion 的去重数量为准，PassedNumber 只是同步时回写的快照。
+type OJProviderUserDetail struct {
+	MODEL
+	Platform     string     `json:"platform" gorm:"type:varchar(32);not null;uniqueIndex:idx_oj_provider_user_platform;index:idx_oj_provider_platform_identifier;comment:'平台标识'"` // 平台标识，如 codeforces
+	Identifier   string     `json:"identifier" gorm:"type:varchar(64);not null;index:idx_oj_provider_platform_identifier;comment:'平台账号标识'"`                                       // 平台账号标识，如 Codeforces handle
+	RealName     string     `json:"real_name" gorm:"type:varchar(64);not null;default:'';comment:'平台展示名'"`
+	UserAvatar   string     `json:"user_avatar" gorm:"type:varchar(255);not null;default:'';comment:'平台头像'"`
+	Rating       int        `json:"rating" gorm:"not null;default:0;comment:'平台积分'"`
+	PassedNumber int        `json:"passed_number" gorm:"not null;default:0;comment:'通过题数快照'"`
+	SyncCursor   int64      `json:"-" gorm:"not null;default:0;comment:'增量同步游标(最近一次已处理提交的秒级时间戳)'"` // 增量同步从游标之后继续拉取
+	LastBindAt   *time.Time `json:"last_bind_at,omitempty" gorm:"type:datetime;comment:'上次绑定时间'"`
+	LastSyncAt   *time.Time `json:"last_sync_at,omitempty" gorm:"type:datetime;comment:'最近一次题目同步时间'"`
+
+	UserID uint `json:"user_id" gorm:"not null;uniqueIndex:idx_oj_provider_user_platform;comment:'所属用户ID(外键)'"` // 同一用户在同一平台只绑定一个账号
+	User   User `json:"-" gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// OJProviderQuestionBank 保存可插拔 OJ 平台的题目元信息，(platform, problem_code) 唯一。
+type OJProviderQuestionBank struct {
+	MODEL
+	Platform         string     `json:"platform" gorm:"type:varchar(32);not null;uniqueIndex:idx_oj_provider_question_code;index:idx_oj_provider_question_title;comment:'平台标识'"`
+	ProblemCode      string     `json:"problem_code" gorm:"type:varchar(64);not null;uniqueIndex:idx_oj_provider_question_code;comment:'平台题目编码(如 1A、abc300_a)'"`
+	Title            string     `json:"title" gorm:"type:varchar(255);not null;index:idx_oj_provider_question_title;comment:'题目标题'"`
+	Difficulty       string     `json:"difficulty" gorm:"type:varchar(32);not null;default:'';comment:'平台难度(如 Codeforces rating)'"`
+	SourceStatus     int8       `json:"source_status" gorm:"type:tinyint;not null;default:1;index;comment:'来源状态:1 verified,2 pending,3 invalid'"`
+	SourceType       string     `json:"source_type" gorm:"type:varchar(16);not null;default:'sync';comment:'来源类型 sync|manual'"`
+	LastVerifiedAt   *time.Time `json:"last_verified_at,omitempty" gorm:"type:datetime;comment:'最近一次校验时间'"`
+	VerifyFailReason string     `json:"verify_fail_reason" gorm:"type:varchar(255);not null;default:'';comment:'最近一次校验失败原因'"`
+}
+
+// OJProviderUserQuestion 保存用户在可插拔 OJ 平台上已经通过的题目事实，一道题只保留首次通过记录。
+type OJProviderUserQuestion struct {
+	MODEL
+	DetailID   uint      `json:"detail_id" gorm:"not null;index;uniqueIndex:idx_oj_provider_user_question;comment:'平台账号详情ID(外键)'"`
+	QuestionID uint      `json:"question_id" gorm:"not null;index;uniqueIndex:idx_oj_provider_user_question;comment:'平台题库题目ID(外键)'"`
+	SolvedAt   time.Time `json:"solved_at" gorm:"type:datetime;not null;index;comment:'首次通过时间'"`
+
+	Detail   OJProviderUserDetail   `json:"-" gorm:"foreignKey:DetailID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Question OJProviderQuestionBank `json:"-" gorm:"foreignKey:QuestionID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
@@ -0,0 +1,30 @@
+package entity
+
+// OJQuestionTag 表示管理员维护的题库自定义标签（如 “DP”、“图论”），跨平台共享。
+type OJQuestionTag struct {
+	// MODEL 提供主键、时间戳和软删除字段。
+	MODEL
+	// Name 是标签名称，全局唯一。
+	Name string `json:"name" gorm:"type:varchar(32);not null;uniqueIndex:uk_oj_question_tags_name;comment:'标签名称'"`
+	// Description 是标签说明。
+	Description string `json:"description" gorm:"type:varchar(255);not null;default:'';comment:'标签说明'"`
+	// CreatedBy 是创建标签的用户 ID。
+	CreatedBy uint `json:"created_by" gorm:"not null;comment:'创建人ID'"`
+	// UpdatedBy 是最后一次修改标签的用户 ID。
+	UpdatedBy uint `json:"updated_by" gorm:"not null;comment:'更新人ID'"`
+}
+
+// OJQuestionTagBinding 表示标签与某个平台题库题目的关联。
+// QuestionID 与 OJTaskItem.ResolvedQuestionID 同一口径，指向对应平台题库表的主键。
+type OJQuestionTagBinding struct {
+	// MODEL 提供主键、时间戳和软删除字段。
+	MODEL
+	// TagID 是标签 ID。
+	TagID uint `json:"tag_id" gorm:"not null;uniqueIndex:uk_oj_question_tag_bindings;comment:'标签ID'"`
+	// Platform 是题目所属 OJ 平台标识。
+	Platform string `json:"platform" gorm:"type:varchar(32);not null;uniqueIndex:uk_oj_question_tag_bindings;index:idx_oj_question_tag_bindings_question;comment:'OJ 平台'"`
+	// QuestionID 是平台题库表主键。
+	QuestionID uint `json:"question_id" gorm:"not null;uniqueIndex:uk_oj_question_tag_bindings;index:idx_oj_question_tag_bindings_question;comment:'题库题目ID'"`
+	// CreatedBy 是打标签的用户 ID。
+	CreatedBy uint `json:"created_by" gorm:"not null;comment:'创建人ID'"`
+}
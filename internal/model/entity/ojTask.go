@@ -18,11 +18,21 @@ type OJTask struct {
 	// Description 是任务描述。
 	Description string `json:"description" gorm:"type:text;comment:'任务描述'"`
 	// Mode 是任务创建模式，取值来自 consts.OJTaskMode。
-	Mode string `json:"mode" gorm:"type:varchar(16);not null;index;comment:'创建模式 immediate|scheduled'"`
+	Mode string `json:"mode" gorm:"type:varchar(16);not null;index;comment:'创建模式 immediate|scheduled|recurring'"`
 	// Status 是任务版本状态，取值来自 consts.OJTaskStatus。
 	Status string `json:"status" gorm:"type:varchar(16);not null;index;comment:'任务状态'"`
-	// ExecuteAt 是定时任务计划执行时间；立即任务通常为空。
+	// ExecuteAt 是定时任务计划执行时间；周期任务为首个周期时间；立即任务通常为空。
 	ExecuteAt *time.Time `json:"execute_at,omitempty" gorm:"type:datetime;index;comment:'计划执行时间'"`
+	// RecurrenceRule 是周期任务的标准 5 段 cron 表达式，按北京时间解释；非周期任务为空。
+	RecurrenceRule string `json:"recurrence_rule" gorm:"type:varchar(64);not null;default:'';comment:'周期规则(cron)'"`
+	// RecurrenceEndAt 是周期任务的截止时间，晚于该时间的周期不再物化；非周期任务为空。
+	RecurrenceEndAt *time.Time `json:"recurrence_end_at,omitempty" gorm:"type:datetime;comment:'周期截止时间'"`
+	// NextOccurrenceAt 是周期任务下一个待物化周期的计划时间；周期结束、删除或非周期任务为空。
+	NextOccurrenceAt *time.Time `json:"next_occurrence_at,omitempty" gorm:"type:datetime;index;comment:'下一周期时间'"`
+	// DeadlineAt 是立即与定时任务的完成截止时间；为空表示不设截止，周期任务固定为空。
+	DeadlineAt *time.Time `json:"deadline_at,omitempty" gorm:"type:datetime;comment:'完成截止时间'"`
+	// DeadlineMinutes 是周期任务每个周期从计划时间起的完成时限（分钟）；0 表示不设截止，非周期任务固定为 0。
+	DeadlineMinutes int `json:"deadline_minutes" gorm:"not null;default:0;comment:'周期完成时限(分钟)'"`
 	// CreatedBy 是创建该任务版本的用户 ID。
 	CreatedBy uint `json:"created_by" gorm:"not null;index;comment:'创建人ID'"`
 	// UpdatedBy 是最后一次修改该任务版本的用户 ID。
@@ -88,13 +98,15 @@ type OJQuestionIntake struct {
 	ResolutionNote string `json:"resolution_note" gorm:"type:varchar(255);not null;default:'';comment:'待解析备注'"`
 }
 
-// OJTaskExecution 表示任务版本对应的唯一执行记录实体。
-// 当前实现约束一个任务版本只对应一条执行记录，状态流转由调度器和 Service 共同维护。
+// OJTaskExecution 表示任务版本的一次执行记录实体。
+// 立即与定时任务只有一条执行记录；周期任务每个周期一条，通过 OccurrenceNo 区分，状态流转由调度器和 Service 共同维护。
 type OJTaskExecution struct {
 	// MODEL 提供主键、时间戳和软删除字段。
 	MODEL
-	// TaskID 是任务版本 ID，并通过唯一索引保证一对一执行关系。
-	TaskID uint `json:"task_id" gorm:"not null;uniqueIndex;comment:'任务ID'"`
+	// TaskID 是任务版本 ID，与 OccurrenceNo 组成唯一索引。
+	TaskID uint `json:"task_id" gorm:"not null;uniqueIndex:uk_oj_task_executions_task_occurrence,priority:1;comment:'任务ID'"`
+	// OccurrenceNo 是周期序号，从 1 开始；非周期任务固定为 1。
+	OccurrenceNo int `json:"occurrence_no" gorm:"not null;default:1;uniqueIndex:uk_oj_task_executions_task_occurrence,priority:2;comment:'周期序号'"`
 	// TriggerType 是本次执行的触发来源，取值来自 consts.OJTaskExecutionTriggerType。
 	TriggerType string `json:"trigger_type" gorm:"type:varchar(32);not null;comment:'触发类型'"`
 	// PlannedAt 是本次执行计划开始时间；立即任务通常为创建或触发当时。
@@ -121,6 +133,14 @@ type OJTaskExecution struct {
 	CompletedItemCount int `json:"completed_item_count" gorm:"not null;default:0;comment:'完成题目快照数'"`
 	// PendingItemCount 是本次执行中未完成的用户题目明细数。
 	PendingItemCount int `json:"pending_item_count" gorm:"not null;default:0;comment:'未完成题目快照数'"`
+	// LateItemCount 是截止后才完成的用户题目明细数，不计入 CompletedItemCount。
+	LateItemCount int `json:"late_item_count" gorm:"not null;default:0;comment:'逾期完成题目快照数'"`
+	// DeadlineAt 是本次执行的完成截止时间，执行成功时由任务截止配置推算；为空表示不做截止跟踪。
+	DeadlineAt *time.Time `json:"deadline_at,omitempty" gorm:"type:datetime;index;comment:'完成截止时间'"`
+	// LastRemindedAt 是最近一次向未完成用户发送截止提醒的时间。
+	LastRemindedAt *time.Time `json:"last_reminded_at,omitempty" gorm:"type:datetime;comment:'最近提醒时间'"`
+	// ResultFrozenAt 是截止后按时结果冻结的时间；冻结后完成统计不再变化，仅继续标记逾期完成。
+	ResultFrozenAt *time.Time `json:"result_frozen_at,omitempty" gorm:"type:datetime;comment:'结果冻结时间'"`
 }
 
 // OJTaskExecutionUser 表示执行过程中冻结的用户快照。
@@ -144,7 +164,9 @@ type OJTaskExecutionUser struct {
 	CompletedItemCount int `json:"completed_item_count" gorm:"not null;default:0;comment:'完成题数'"`
 	// PendingItemCount 是该用户待完成题目数。
 	PendingItemCount int `json:"pending_item_count" gorm:"not null;default:0;comment:'未完成题数'"`
-	// AllCompleted 表示该用户是否已完成本次任务全部题目。
+	// LateItemCount 是该用户截止后才完成的题目数。
+	LateItemCount int `json:"late_item_count" gorm:"not null;default:0;comment:'逾期完成题数'"`
+	// AllCompleted 表示该用户是否已在截止前完成本次任务全部题目。
 	AllCompleted bool `json:"all_completed" gorm:"type:boolean;not null;default:false;index;comment:'是否全部完成'"`
 }
 
@@ -178,4 +200,6 @@ type OJTaskExecutionUserItem struct {
 	ResultStatus string `json:"result_status" gorm:"type:varchar(16);not null;index;comment:'完成状态'"`
 	// Reason 是 pending 状态下的原因编码；completed 状态通常为空字符串。
 	Reason string `json:"reason" gorm:"type:varchar(32);not null;default:'';index;comment:'pending 原因'"`
+	// CompletedAt 是截止跟踪期间复评发现的完成时间；执行快照当时已完成的题目为空。
+	CompletedAt *time.Time `json:"completed_at,omitempty" gorm:"type:datetime;comment:'复评完成时间'"`
 }
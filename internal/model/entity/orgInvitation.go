@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"personal_assistant/internal/model/consts"
+)
+
+// OrgInvitation 组织邀请表 - 每个邀请对应一个独立令牌，可限制次数、有效期与受邀人，令牌只保存摘要
+type OrgInvitation struct {
+	MODEL
+	OrgID           uint       `json:"org_id" gorm:"not null;index;comment:'组织ID'"`
+	CreatedBy       uint       `json:"created_by" gorm:"not null;index;comment:'创建者ID'"`
+	TokenHash       string     `json:"-" gorm:"type:char(64);not null;uniqueIndex;comment:'邀请令牌SHA-256摘要'"` // 明文只在创建时返回一次
+	TokenHint       string     `json:"token_hint" gorm:"type:varchar(8);not null;default:'';comment:'令牌前缀，便于管理员辨认'"`
+	MaxUses         int        `json:"max_uses" gorm:"not null;default:1;comment:'最大使用次数'"`
+	UsedCount       int        `json:"used_count" gorm:"not null;default:0;comment:'已使用次数'"`
+	ExpiresAt       time.Time  `json:"expires_at" gorm:"type:datetime;not null;index;comment:'过期时间'"`
+	TargetEmail     string     `json:"target_email" gorm:"type:varchar(100);not null;default:'';comment:'限定受邀邮箱，空表示不限'"`
+	TargetPhone     string     `json:"target_phone" gorm:"type:varchar(20);not null;default:'';comment:'限定受邀手机号，空表示不限'"`
+	RequireApproval bool       `json:"require_approval" gorm:"not null;default:false;comment:'加入是否需要管理员审批'"`
+	Note            string     `json:"note" gorm:"type:varchar(200);not null;default:'';comment:'备注'"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty" gorm:"type:datetime;comment:'撤销时间'"`
+	RevokedBy       *uint      `json:"revoked_by,omitempty" gorm:"comment:'撤销操作者ID'"`
+}
+
+// OrgJoinRequest 组织加入申请表 - 需要审批的邀请被接受后写入，由持有 org.member.invite 的管理员审批
+type OrgJoinRequest struct {
+	MODEL
+	OrgID        uint                        `json:"org_id" gorm:"not null;index;comment:'组织ID'"`
+	UserID       uint                        `json:"user_id" gorm:"not null;index;comment:'申请人ID'"`
+	InvitationID uint                        `json:"invitation_id" gorm:"not null;index;comment:'使用的邀请ID'"`
+	Status       consts.OrgJoinRequestStatus `json:"status" gorm:"type:varchar(16);not null;index;comment:'pending/approved/rejected'"`
+	ReviewedBy   *uint                       `json:"reviewed_by,omitempty" gorm:"comment:'审批人ID'"`
+	ReviewedAt   *time.Time                  `json:"reviewed_at,omitempty" gorm:"type:datetime;comment:'审批时间'"`
+	ReviewNote   string                      `json:"review_note" gorm:"type:varchar(200);not null;default:'';comment:'审批备注'"`
+}
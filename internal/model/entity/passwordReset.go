@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// PasswordResetCode 找回密码验证码表 - 记录每次发出的重置验证码，只保存摘要，不保存明文
+type PasswordResetCode struct {
+	MODEL
+	UserID    uint       `json:"user_id" gorm:"not null;index;comment:'关联用户ID'"`                   // 申请重置密码的用户
+	Account   string     `json:"account" gorm:"type:varchar(100);not null;comment:'申请时填写的手机号或邮箱'"` // 用户申请时输入的账号标识
+	Email     string     `json:"email" gorm:"type:varchar(100);not null;comment:'验证码投递邮箱'"`        // 验证码实际投递的邮箱
+	CodeHash  string     `json:"-" gorm:"type:char(64);not null;comment:'验证码SHA-256摘要'"`           // 验证码摘要，明文只出现在邮件中
+	ExpiresAt time.Time  `json:"expires_at" gorm:"type:datetime;not null;index;comment:'过期时间'"`
+	Attempts  int        `json:"attempts" gorm:"not null;default:0;comment:'已校验失败次数'"` // 达到上限后验证码作废
+	UsedAt    *time.Time `json:"used_at,omitempty" gorm:"type:datetime;index;comment:'使用或作废时间'"`
+	RequestIP string     `json:"request_ip" gorm:"type:varchar(45);default:'';comment:'申请IP'"`
+}
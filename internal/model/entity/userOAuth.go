@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// OAuthLoginState 第三方登录授权请求表 - 发起授权时写入，回调时按 state 取回 PKCE 校验参数，只能使用一次
+type OAuthLoginState struct {
+	MODEL
+	Provider     string     `json:"provider" gorm:"type:varchar(50);not null;comment:'第三方提供方标识'"`
+	StateHash    string     `json:"-" gorm:"type:char(64);not null;uniqueIndex;comment:'state SHA-256摘要'"` // 明文只出现在授权地址中
+	CodeVerifier string     `json:"-" gorm:"type:varchar(128);not null;comment:'PKCE code_verifier'"`      // 仅在有效期内用于换取令牌
+	Nonce        string     `json:"-" gorm:"type:varchar(128);not null;default:'';comment:'OIDC nonce'"`
+	Purpose      string     `json:"purpose" gorm:"type:varchar(16);not null;comment:'login 登录 / link 绑定'"`
+	UserID       uint       `json:"user_id" gorm:"not null;default:0;index;comment:'发起绑定的用户ID，登录时为0'"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"type:datetime;not null;index;comment:'过期时间'"`
+	UsedAt       *time.Time `json:"used_at,omitempty" gorm:"type:datetime;comment:'使用时间'"`
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// UserSession 登录会话表 - 每次登录对应一个设备会话，刷新令牌在会话内轮换
+type UserSession struct {
+	MODEL
+	UserID       uint       `json:"user_id" gorm:"not null;index;comment:'关联用户ID'"`
+	SessionID    string     `json:"session_id" gorm:"type:char(36);uniqueIndex;not null;comment:'会话标识（令牌族ID）'"` // 写入访问令牌与刷新令牌的 sid
+	CurrentJTI   string     `json:"-" gorm:"type:char(36);not null;comment:'当前有效刷新令牌ID'"`                       // 仅最新一次轮换签发的刷新令牌可用
+	DeviceLabel  string     `json:"device_label" gorm:"type:varchar(100);default:'';comment:'设备名称'"`            // 客户端上报或由 User-Agent 推断
+	IP           string     `json:"ip" gorm:"type:varchar(45);default:'';comment:'最近一次活跃IP'"`                   // 登录或最近一次刷新时的客户端 IP
+	UserAgent    string     `json:"user_agent" gorm:"type:varchar(500);default:'';comment:'最近一次活跃User-Agent'"`  // 登录或最近一次刷新时的 User-Agent
+	LastSeenAt   time.Time  `json:"last_seen_at" gorm:"type:datetime;not null;comment:'最近活跃时间'"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"type:datetime;not null;index;comment:'会话过期时间'"` // 随刷新令牌轮换顺延
+	RevokedAt    *time.Time `json:"revoked_at,omitempty" gorm:"type:datetime;index;comment:'吊销时间'"`
+	RevokeReason string     `json:"revoke_reason" gorm:"type:varchar(50);default:'';comment:'吊销原因'"`
+}
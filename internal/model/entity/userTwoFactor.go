@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// UserTwoFactor 用户二次验证（TOTP）配置表 - 每个用户最多一条，密钥使用敏感数据编解码器加密保存
+type UserTwoFactor struct {
+	MODEL
+	UserID       uint       `json:"user_id" gorm:"not null;uniqueIndex;comment:'关联用户ID'"`
+	SecretCipher string     `json:"-" gorm:"type:text;not null;comment:'TOTP密钥密文'"`           // 绑定中与已启用共用，未启用时为待确认的新密钥
+	Enabled      bool       `json:"enabled" gorm:"not null;default:false;comment:'是否已启用'"`    // 首次校验动态码成功后启用
+	EnabledAt    *time.Time `json:"enabled_at,omitempty" gorm:"type:datetime;comment:'启用时间'"` // 未启用时为空
+	LastUsedStep int64      `json:"-" gorm:"not null;default:0;comment:'最近一次通过校验的TOTP时间步'"`   // 同一时间步的动态码只能使用一次
+}
+
+// UserRecoveryCode 二次验证恢复码表 - 只保存 HMAC 摘要，每个恢复码只能使用一次
+type UserRecoveryCode struct {
+	MODEL
+	UserID   uint       `json:"user_id" gorm:"not null;index;comment:'关联用户ID'"`
+	CodeHash string     `json:"-" gorm:"type:char(64);not null;index;comment:'恢复码HMAC摘要'"`
+	UsedAt   *time.Time `json:"used_at,omitempty" gorm:"type:datetime;comment:'使用时间'"`
+}
+
+// TwoFactorChallenge 二次验证登录挑战表 - 密码校验通过后签发，完成第二步后换取正式令牌
+type TwoFactorChallenge struct {
+	MODEL
+	UserID    uint       `json:"user_id" gorm:"not null;index;comment:'关联用户ID'"`
+	TokenHash string     `json:"-" gorm:"type:char(64);not null;uniqueIndex;comment:'挑战令牌SHA-256摘要'"` // 明文只返回给客户端一次
+	Purpose   string     `json:"purpose" gorm:"type:varchar(16);not null;comment:'verify 校验 / enroll 强制绑定'"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"type:datetime;not null;index;comment:'过期时间'"`
+	Attempts  int        `json:"attempts" gorm:"not null;default:0;comment:'已校验失败次数'"`
+	UsedAt    *time.Time `json:"used_at,omitempty" gorm:"type:datetime;comment:'使用或作废时间'"`
+}
@@ -0,0 +1,57 @@
+package readmodel
+
+import "time"
+
+// OJQuestionBankQuery 是题库目录的检索条件，由服务层归一化后传给仓储。
+type OJQuestionBankQuery struct {
+	// Platforms 是参与检索的平台；洛谷、力扣、蓝桥走各自题库表，其余平台走通用扩展题库表。
+	Platforms []string
+	// Terms 是拆分后的关键词，默认每个词都需命中标题或题目编码。
+	Terms []string
+	// MatchAnyTerm 为 true 时任一关键词命中即可，用于模糊召回候选题目。
+	MatchAnyTerm bool
+	// Keyword 是原始关键词，用于把标题或编码完全相同的题目排在前面。
+	Keyword string
+	// Difficulty 是平台难度，精确匹配；力扣与蓝桥题库没有难度字段。
+	Difficulty string
+	// SourceStatus 是题库来源状态，为空表示不过滤。
+	SourceStatus *int8
+	// TagIDs 是标签过滤，题目需同时带有全部标签。
+	TagIDs []uint
+	Offset int
+	Limit  int
+}
+
+// OJQuestionBankItem 是跨平台题库目录中的一道题，各平台题库表字段归一化到同一结构。
+type OJQuestionBankItem struct {
+	Platform       string     `gorm:"column:platform"`
+	QuestionID     uint       `gorm:"column:question_id"`
+	QuestionCode   string     `gorm:"column:question_code"`
+	Title          string     `gorm:"column:title"`
+	Difficulty     string     `gorm:"column:difficulty"`
+	SourceStatus   int8       `gorm:"column:source_status"`
+	SourceType     string     `gorm:"column:source_type"`
+	LastVerifiedAt *time.Time `gorm:"column:last_verified_at"`
+}
+
+// OJQuestionRef 标识某个平台题库中的一道题。
+type OJQuestionRef struct {
+	Platform   string
+	QuestionID uint
+}
+
+// OJQuestionTagBindingItem 是题目与标签的关联，附带标签名称。
+type OJQuestionTagBindingItem struct {
+	Platform   string `gorm:"column:platform"`
+	QuestionID uint   `gorm:"column:question_id"`
+	TagID      uint   `gorm:"column:tag_id"`
+	TagName    string `gorm:"column:tag_name"`
+}
+
+// OJQuestionTagItem 是标签及其关联题目数。
+type OJQuestionTagItem struct {
+	ID            uint   `gorm:"column:id"`
+	Name          string `gorm:"column:name"`
+	Description   string `gorm:"column:description"`
+	QuestionCount int64  `gorm:"column:question_count"`
+}
@@ -41,8 +41,18 @@ type OJTaskListItem struct {
 	Mode string `gorm:"column:mode"`
 	// Status 是任务版本当前状态，取值来自 consts.OJTaskStatus。
 	Status string `gorm:"column:status"`
-	// ExecuteAt 是定时任务计划执行时间；立即任务通常为空。
+	// ExecuteAt 是定时任务计划执行时间；周期任务为首个周期时间；立即任务通常为空。
 	ExecuteAt *time.Time `gorm:"column:execute_at"`
+	// RecurrenceRule 是周期任务的 cron 规则；非周期任务为空。
+	RecurrenceRule string `gorm:"column:recurrence_rule"`
+	// RecurrenceEndAt 是周期任务截止时间。
+	RecurrenceEndAt *time.Time `gorm:"column:recurrence_end_at"`
+	// NextOccurrenceAt 是周期任务下一个待物化周期时间；周期已结束时为空。
+	NextOccurrenceAt *time.Time `gorm:"column:next_occurrence_at"`
+	// DeadlineAt 是立即与定时任务的完成截止时间；未设置时为空。
+	DeadlineAt *time.Time `gorm:"column:deadline_at"`
+	// DeadlineMinutes 是周期任务每个周期的完成时限（分钟）；0 表示不设截止。
+	DeadlineMinutes int `gorm:"column:deadline_minutes"`
 	// CreatedBy 是任务版本创建人 ID。
 	CreatedBy uint `gorm:"column:created_by"`
 	// UpdatedBy 是任务版本最后更新人 ID。
@@ -51,8 +61,10 @@ type OJTaskListItem struct {
 	CreatedAt time.Time `gorm:"column:created_at"`
 	// UpdatedAt 是任务版本最后更新时间。
 	UpdatedAt time.Time `gorm:"column:updated_at"`
-	// ExecutionID 是当前任务版本绑定的唯一执行记录 ID。
+	// ExecutionID 是当前任务版本最近一个周期的执行记录 ID；非周期任务即唯一执行记录。
 	ExecutionID uint `gorm:"column:execution_id"`
+	// OccurrenceNo 是该执行记录的周期序号，非周期任务固定为 1。
+	OccurrenceNo int `gorm:"column:occurrence_no"`
 	// ExecutionStatus 是当前执行记录状态，取值来自 consts.OJTaskExecutionStatus。
 	ExecutionStatus string `gorm:"column:execution_status"`
 	// TotalUserCount 是执行快照覆盖的总用户数。
@@ -67,6 +79,12 @@ type OJTaskListItem struct {
 	CompletedItemCount int `gorm:"column:completed_item_count"`
 	// PendingItemCount 是未完成的用户题目明细条数。
 	PendingItemCount int `gorm:"column:pending_item_count"`
+	// LateItemCount 是截止后才完成的用户题目明细条数。
+	LateItemCount int `gorm:"column:late_item_count"`
+	// ExecutionDeadlineAt 是当前执行记录的完成截止时间；未设截止或尚未执行成功时为空。
+	ExecutionDeadlineAt *time.Time `gorm:"column:execution_deadline_at"`
+	// ResultFrozenAt 是当前执行记录按时结果冻结的时间；截止前为空。
+	ResultFrozenAt *time.Time `gorm:"column:result_frozen_at"`
 	// OrgCount 是任务当前关联的组织数量。
 	OrgCount int `gorm:"column:org_count"`
 	// ItemCount 是任务题单中的题目数量。
@@ -111,10 +129,16 @@ type OJTaskVersionItem struct {
 	Status string `gorm:"column:status"`
 	// ExecuteAt 是该版本的计划执行时间。
 	ExecuteAt *time.Time `gorm:"column:execute_at"`
+	// RecurrenceRule 是该版本的周期规则；非周期任务为空。
+	RecurrenceRule string `gorm:"column:recurrence_rule"`
+	// NextOccurrenceAt 是该版本下一个待物化周期时间。
+	NextOccurrenceAt *time.Time `gorm:"column:next_occurrence_at"`
 	// CreatedAt 是该版本创建时间。
 	CreatedAt time.Time `gorm:"column:created_at"`
-	// ExecutionID 是该版本关联的执行记录 ID。
+	// ExecutionID 是该版本最近一个周期的执行记录 ID。
 	ExecutionID uint `gorm:"column:execution_id"`
+	// OccurrenceNo 是该执行记录的周期序号。
+	OccurrenceNo int `gorm:"column:occurrence_no"`
 	// ExecutionStatus 是该版本关联执行记录的状态。
 	ExecutionStatus string `gorm:"column:execution_status"`
 }
@@ -153,6 +177,8 @@ type OJTaskExecutionUserListItem struct {
 	CompletedItemCount int `gorm:"column:completed_item_count"`
 	// PendingItemCount 是该用户在本次执行中待完成题目数。
 	PendingItemCount int `gorm:"column:pending_item_count"`
+	// LateItemCount 是该用户截止后才完成的题目数。
+	LateItemCount int `gorm:"column:late_item_count"`
 	// AllCompleted 表示该用户在本次执行中是否已完成全部题目。
 	AllCompleted bool `gorm:"column:all_completed"`
 }
@@ -199,4 +225,21 @@ type OJTaskExecutionUserItemDetail struct {
 	ResultStatus string `gorm:"column:result_status"`
 	// Reason 是 pending 状态下的原因编码；completed 状态通常为空字符串。
 	Reason string `gorm:"column:reason"`
+	// CompletedAt 是截止跟踪期间复评发现的完成时间；执行快照当时已完成的题目为空。
+	CompletedAt *time.Time `gorm:"column:completed_at"`
+}
+
+// OJPendingIntakeGroup 是跨任务按“平台 + 输入标题”聚合的待解析题目，用于题库人工整理。
+// 同一标题往往出现在多个任务或多个版本中，确认一次即可回填全部任务项。
+type OJPendingIntakeGroup struct {
+	// Platform 是 OJ 平台标识。
+	Platform string `gorm:"column:platform"`
+	// InputTitle 是任务创建时冻结的原始输入标题。
+	InputTitle string `gorm:"column:input_title"`
+	// IntakeCount 是该标题下仍处于待解析状态的任务项数量。
+	IntakeCount int64 `gorm:"column:intake_count"`
+	// TaskCount 是涉及的任务版本数量。
+	TaskCount int64 `gorm:"column:task_count"`
+	// FirstIntakeID 是该标题最早一条待解析记录的 ID，用于按出现先后排序。
+	FirstIntakeID uint `gorm:"column:first_intake_id"`
 }
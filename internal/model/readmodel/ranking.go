@@ -15,14 +15,28 @@ type Ranking struct {
 	LuoguIdentifier string `gorm:"column:luogu_identifier"`
 	LuoguAvatar     string `gorm:"column:luogu_avatar"`
 	LuoguScore      int    `gorm:"column:luogu_score"`
+	// LuoguWeightedScore 是按题目难度等级加权后的分数，仅统计已落库的过题关系。
+	LuoguWeightedScore int `gorm:"column:luogu_weighted_score"`
 
 	LeetcodeIdentifier string `gorm:"column:leetcode_identifier"`
 	LeetcodeAvatar     string `gorm:"column:leetcode_avatar"`
 	LeetcodeScore      int    `gorm:"column:leetcode_score"`
+	// LeetcodeWeightedScore 是按简单/中等/困难分档加权后的分数。
+	LeetcodeWeightedScore int `gorm:"column:leetcode_weighted_score"`
 
 	LanqiaoIdentifier string `gorm:"column:lanqiao_identifier"`
 	LanqiaoAvatar     string `gorm:"column:lanqiao_avatar"`
 	LanqiaoScore      int    `gorm:"column:lanqiao_score"`
+
+	// Providers 是扩展平台（consts.OJProviderPlatforms）的账号资料，键为平台标识，未绑定的平台不出现。
+	Providers map[string]RankingProviderProfile `gorm:"-"`
+}
+
+// RankingProviderProfile 是单个扩展平台在排行榜读模型中的账号资料。
+type RankingProviderProfile struct {
+	Identifier string
+	Avatar     string
+	Score      int
 }
 
 func (m *Ranking) IsActive() bool {
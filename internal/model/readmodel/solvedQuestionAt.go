@@ -0,0 +1,11 @@
+package readmodel
+
+import "time"
+
+// SolvedQuestionAt 承载单个平台账号在单道题目上的首次通过时间。
+// 力扣与洛谷未记录上游通过时间，以过题关系落库时间（created_at）近似。
+type SolvedQuestionAt struct {
+	DetailID   uint      `gorm:"column:detail_id"`
+	QuestionID uint      `gorm:"column:question_id"`
+	SolvedAt   time.Time `gorm:"column:solved_at"`
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	stdErrors "errors"
 
+	"personal_assistant/internal/model/dto/request"
 	resp "personal_assistant/internal/model/dto/response"
 	"personal_assistant/internal/model/entity"
 	"personal_assistant/internal/service/contract"
@@ -25,43 +26,29 @@ func WrapJWTService(next contract.JWTServiceContract) contract.JWTServiceContrac
 func (t *tracedJWTService) IssueLoginTokens(
 	ctx context.Context,
 	user entity.User,
+	device request.LoginDevice,
 ) (*resp.LoginResponse, string, int64, *erro.JWTError) {
 	opt := newTraceOptions("jwt", "IssueLoginTokens")
 	spanCtx, spanEvent := servicetrace.Start(ctx, opt)
-	out, refreshToken, refreshExpiresAt, jwtErr := t.next.IssueLoginTokens(spanCtx, user)
-	if jwtErr != nil {
-		err := jwtErr.Err
-		if err == nil {
-			err = stdErrors.New(jwtErr.Message)
-		}
-		servicetrace.Finish(spanCtx, opt, spanEvent, err)
-		return out, refreshToken, refreshExpiresAt, jwtErr
-	}
-	servicetrace.Finish(spanCtx, opt, spanEvent, nil)
-	return out, refreshToken, refreshExpiresAt, nil
+	out, refreshToken, refreshExpiresAt, jwtErr := t.next.IssueLoginTokens(spanCtx, user, device)
+	servicetrace.Finish(spanCtx, opt, spanEvent, jwtTraceErr(jwtErr))
+	return out, refreshToken, refreshExpiresAt, jwtErr
 }
 
-func (t *tracedJWTService) IsInBlacklist(jwt string) bool {
-	return t.next.IsInBlacklist(jwt)
+func (t *tracedJWTService) IsInBlacklist(sessionID string) bool {
+	return t.next.IsInBlacklist(sessionID)
 }
 
-func (t *tracedJWTService) GetAccessToken(
+func (t *tracedJWTService) RefreshTokens(
 	ctx context.Context,
 	token string,
-) (*resp.RefreshTokenResponse, *erro.JWTError) {
-	opt := newTraceOptions("jwt", "GetAccessToken")
+	device request.LoginDevice,
+) (*resp.RefreshTokenResponse, int64, *erro.JWTError) {
+	opt := newTraceOptions("jwt", "RefreshTokens")
 	spanCtx, spanEvent := servicetrace.Start(ctx, opt)
-	out, jwtErr := t.next.GetAccessToken(spanCtx, token)
-	if jwtErr != nil {
-		err := jwtErr.Err
-		if err == nil {
-			err = stdErrors.New(jwtErr.Message)
-		}
-		servicetrace.Finish(spanCtx, opt, spanEvent, err)
-		return out, jwtErr
-	}
-	servicetrace.Finish(spanCtx, opt, spanEvent, nil)
-	return out, nil
+	out, refreshExpiresAt, jwtErr := t.next.RefreshTokens(spanCtx, token, device)
+	servicetrace.Finish(spanCtx, opt, spanEvent, jwtTraceErr(jwtErr))
+	return out, refreshExpiresAt, jwtErr
 }
 
 func (t *tracedJWTService) JoinInBlacklist(ctx context.Context, jwtList entity.JwtBlacklist) error {
@@ -70,4 +57,43 @@ func (t *tracedJWTService) JoinInBlacklist(ctx context.Context, jwtList entity.J
 	})
 }
 
+func (t *tracedJWTService) RevokeUserRefreshTokens(ctx context.Context, user entity.User, reason string) error {
+	return runTracedErr(ctx, "jwt", "RevokeUserRefreshTokens", func(inner context.Context) error {
+		return t.next.RevokeUserRefreshTokens(inner, user, reason)
+	})
+}
+
+func (t *tracedJWTService) ListUserSessions(
+	ctx context.Context,
+	userID uint,
+	currentSessionID string,
+) ([]*resp.UserSessionItem, error) {
+	return runTraced(ctx, "jwt", "ListUserSessions", func(inner context.Context) ([]*resp.UserSessionItem, error) {
+		return t.next.ListUserSessions(inner, userID, currentSessionID)
+	})
+}
+
+func (t *tracedJWTService) RevokeUserSession(ctx context.Context, userID uint, sessionID, reason string) error {
+	return runTracedErr(ctx, "jwt", "RevokeUserSession", func(inner context.Context) error {
+		return t.next.RevokeUserSession(inner, userID, sessionID, reason)
+	})
+}
+
+func (t *tracedJWTService) RevokeOtherUserSessions(ctx context.Context, userID uint, currentSessionID string) (int, error) {
+	return runTraced(ctx, "jwt", "RevokeOtherUserSessions", func(inner context.Context) (int, error) {
+		return t.next.RevokeOtherUserSessions(inner, userID, currentSessionID)
+	})
+}
+
+// jwtTraceErr 把 JWTError 转为链路追踪使用的 error
+func jwtTraceErr(jwtErr *erro.JWTError) error {
+	if jwtErr == nil {
+		return nil
+	}
+	if jwtErr.Err != nil {
+		return jwtErr.Err
+	}
+	return stdErrors.New(jwtErr.Message)
+}
+
 var _ contract.JWTServiceContract = (*tracedJWTService)(nil)
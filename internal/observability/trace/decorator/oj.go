@@ -40,6 +40,16 @@ func (t *tracedOJService) BindLanqiaoAccount(
 	})
 }
 
+func (t *tracedOJService) UnbindOJAccount(
+	ctx context.Context,
+	userID uint,
+	req *request.UnbindOJAccountReq,
+) (*resp.UnbindOJAccountResp, error) {
+	return runTraced(ctx, "oj", "UnbindOJAccount", func(inner context.Context) (*resp.UnbindOJAccountResp, error) {
+		return t.next.UnbindOJAccount(inner, userID, req)
+	})
+}
+
 func (t *tracedOJService) GetRankingList(
 	ctx context.Context,
 	userID uint,
@@ -82,6 +92,10 @@ func (t *tracedOJService) SyncAllLanqiaoUsers(ctx context.Context) error {
 	return runTracedErr(ctx, "oj", "SyncAllLanqiaoUsers", t.next.SyncAllLanqiaoUsers)
 }
 
+func (t *tracedOJService) SyncAllProviderUsers(ctx context.Context) error {
+	return runTracedErr(ctx, "oj", "SyncAllProviderUsers", t.next.SyncAllProviderUsers)
+}
+
 func (t *tracedOJService) RefreshAllLanqiaoSubmissionStats(ctx context.Context) error {
 	return runTracedErr(ctx, "oj", "RefreshAllLanqiaoSubmissionStats", t.next.RefreshAllLanqiaoSubmissionStats)
 }
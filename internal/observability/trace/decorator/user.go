@@ -7,6 +7,8 @@ import (
 	resp "personal_assistant/internal/model/dto/response"
 	"personal_assistant/internal/model/entity"
 	"personal_assistant/internal/service/contract"
+
+	"github.com/mojocn/base64Captcha"
 )
 
 // tracedUserService 是 UserServiceContract 的装饰器实现，添加了分布式追踪功能
@@ -54,6 +56,16 @@ func (t *tracedUserService) ChangePhone(
 	})
 }
 
+func (t *tracedUserService) BindEmail(
+	ctx context.Context,
+	userID uint,
+	email string,
+) (*entity.User, error) {
+	return runTraced(ctx, "user", "BindEmail", func(inner context.Context) (*entity.User, error) {
+		return t.next.BindEmail(inner, userID, email)
+	})
+}
+
 func (t *tracedUserService) ChangePassword(
 	ctx context.Context,
 	userID uint,
@@ -64,6 +76,143 @@ func (t *tracedUserService) ChangePassword(
 	})
 }
 
+func (t *tracedUserService) SendPasswordResetCode(
+	ctx context.Context,
+	store base64Captcha.Store,
+	clientIP string,
+	req *request.SendPasswordResetCodeReq,
+) error {
+	return runTracedErr(ctx, "user", "SendPasswordResetCode", func(inner context.Context) error {
+		return t.next.SendPasswordResetCode(inner, store, clientIP, req)
+	})
+}
+
+func (t *tracedUserService) ResetPassword(ctx context.Context, req *request.ResetPasswordReq) error {
+	return runTracedErr(ctx, "user", "ResetPassword", func(inner context.Context) error {
+		return t.next.ResetPassword(inner, req)
+	})
+}
+
+func (t *tracedUserService) BeginTwoFactorLogin(
+	ctx context.Context,
+	user *entity.User,
+) (*resp.TwoFactorLoginChallenge, error) {
+	return runTraced(ctx, "user", "BeginTwoFactorLogin", func(inner context.Context) (*resp.TwoFactorLoginChallenge, error) {
+		return t.next.BeginTwoFactorLogin(inner, user)
+	})
+}
+
+func (t *tracedUserService) SetupTwoFactorByChallenge(
+	ctx context.Context,
+	req *request.TwoFactorChallengeReq,
+) (*resp.TwoFactorSetupResp, error) {
+	return runTraced(ctx, "user", "SetupTwoFactorByChallenge", func(inner context.Context) (*resp.TwoFactorSetupResp, error) {
+		return t.next.SetupTwoFactorByChallenge(inner, req)
+	})
+}
+
+func (t *tracedUserService) CompleteTwoFactorLogin(
+	ctx context.Context,
+	req *request.TwoFactorLoginReq,
+) (*entity.User, []string, error) {
+	var recoveryCodes []string
+	user, err := runTraced(ctx, "user", "CompleteTwoFactorLogin", func(inner context.Context) (*entity.User, error) {
+		user, codes, err := t.next.CompleteTwoFactorLogin(inner, req)
+		recoveryCodes = codes
+		return user, err
+	})
+	return user, recoveryCodes, err
+}
+
+func (t *tracedUserService) GetTwoFactorStatus(ctx context.Context, userID uint) (*resp.TwoFactorStatusResp, error) {
+	return runTraced(ctx, "user", "GetTwoFactorStatus", func(inner context.Context) (*resp.TwoFactorStatusResp, error) {
+		return t.next.GetTwoFactorStatus(inner, userID)
+	})
+}
+
+func (t *tracedUserService) SetupTwoFactor(ctx context.Context, userID uint) (*resp.TwoFactorSetupResp, error) {
+	return runTraced(ctx, "user", "SetupTwoFactor", func(inner context.Context) (*resp.TwoFactorSetupResp, error) {
+		return t.next.SetupTwoFactor(inner, userID)
+	})
+}
+
+func (t *tracedUserService) EnableTwoFactor(
+	ctx context.Context,
+	userID uint,
+	req *request.TwoFactorCodeReq,
+) (*resp.TwoFactorRecoveryCodesResp, error) {
+	return runTraced(ctx, "user", "EnableTwoFactor", func(inner context.Context) (*resp.TwoFactorRecoveryCodesResp, error) {
+		return t.next.EnableTwoFactor(inner, userID, req)
+	})
+}
+
+func (t *tracedUserService) DisableTwoFactor(ctx context.Context, userID uint, req *request.TwoFactorCodeReq) error {
+	return runTracedErr(ctx, "user", "DisableTwoFactor", func(inner context.Context) error {
+		return t.next.DisableTwoFactor(inner, userID, req)
+	})
+}
+
+func (t *tracedUserService) RegenerateTwoFactorRecoveryCodes(
+	ctx context.Context,
+	userID uint,
+	req *request.TwoFactorCodeReq,
+) (*resp.TwoFactorRecoveryCodesResp, error) {
+	return runTraced(ctx, "user", "RegenerateTwoFactorRecoveryCodes", func(inner context.Context) (*resp.TwoFactorRecoveryCodesResp, error) {
+		return t.next.RegenerateTwoFactorRecoveryCodes(inner, userID, req)
+	})
+}
+
+func (t *tracedUserService) ListOAuthProviders(ctx context.Context) ([]resp.OAuthProviderItem, error) {
+	return runTraced(ctx, "user", "ListOAuthProviders", func(inner context.Context) ([]resp.OAuthProviderItem, error) {
+		return t.next.ListOAuthProviders(inner)
+	})
+}
+
+func (t *tracedUserService) BeginOAuthLogin(ctx context.Context, provider string) (*resp.OAuthAuthorizeResp, error) {
+	return runTraced(ctx, "user", "BeginOAuthLogin", func(inner context.Context) (*resp.OAuthAuthorizeResp, error) {
+		return t.next.BeginOAuthLogin(inner, provider)
+	})
+}
+
+func (t *tracedUserService) CompleteOAuthLogin(
+	ctx context.Context,
+	provider string,
+	req *request.OAuthCallbackReq,
+) (*entity.User, error) {
+	return runTraced(ctx, "user", "CompleteOAuthLogin", func(inner context.Context) (*entity.User, error) {
+		return t.next.CompleteOAuthLogin(inner, provider, req)
+	})
+}
+
+func (t *tracedUserService) BeginOAuthLink(ctx context.Context, userID uint, provider string) (*resp.OAuthAuthorizeResp, error) {
+	return runTraced(ctx, "user", "BeginOAuthLink", func(inner context.Context) (*resp.OAuthAuthorizeResp, error) {
+		return t.next.BeginOAuthLink(inner, userID, provider)
+	})
+}
+
+func (t *tracedUserService) CompleteOAuthLink(
+	ctx context.Context,
+	userID uint,
+	provider string,
+	req *request.OAuthCallbackReq,
+) (*resp.OAuthBindingResp, error) {
+	return runTraced(ctx, "user", "CompleteOAuthLink", func(inner context.Context) (*resp.OAuthBindingResp, error) {
+		return t.next.CompleteOAuthLink(inner, userID, provider, req)
+	})
+}
+
+func (t *tracedUserService) GetOAuthBinding(ctx context.Context, userID uint) (*resp.OAuthBindingResp, error) {
+	return runTraced(ctx, "user", "GetOAuthBinding", func(inner context.Context) (*resp.OAuthBindingResp, error) {
+		return t.next.GetOAuthBinding(inner, userID)
+	})
+}
+
+func (t *tracedUserService) UnlinkOAuth(ctx context.Context, userID uint) error {
+	return runTracedErr(ctx, "user", "UnlinkOAuth", func(inner context.Context) error {
+		return t.next.UnlinkOAuth(inner, userID)
+	})
+}
+
 func (t *tracedUserService) GetUserList(
 	ctx context.Context,
 	req *request.UserListReq,
@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	aidomain "personal_assistant/internal/domain/ai"
 	"personal_assistant/internal/model/entity"
@@ -33,9 +34,56 @@ type AIMemoryRepository interface {
 	ListDocumentChunksByRefs(ctx context.Context, refs []aidomain.MemoryDocumentChunkRef) ([]*entity.AIMemoryDocumentChunk, error)
 	// ListDocumentChunksByPointIDs 按 Qdrant point ids 回查仍有效的 chunks。
 	ListDocumentChunksByPointIDs(ctx context.Context, pointIDs []string) ([]*entity.AIMemoryDocumentChunk, error)
+	// ListDocumentChunksByIDs 按 chunk id 回查仍有效的 chunks。
+	ListDocumentChunksByIDs(ctx context.Context, ids []string) ([]*entity.AIMemoryDocumentChunk, error)
+
+	// SaveChunkTerms 覆盖写入 chunks 的关键词倒排，并同步刷新 chunk 的 lexical_length。
+	SaveChunkTerms(ctx context.Context, chunks []*entity.AIMemoryDocumentChunk, terms []*entity.AIMemoryChunkTerm) error
+	// ListChunksMissingTerms 扫描仍有效但尚未建立关键词倒排的 chunks。
+	ListChunksMissingTerms(ctx context.Context, limit int) ([]*entity.AIMemoryDocumentChunk, error)
+	// SearchChunkTerms 在单个作用域内读取 BM25 所需的统计信息和命中倒排记录。
+	SearchChunkTerms(ctx context.Context, query aidomain.MemoryLexicalQuery) (aidomain.MemoryLexicalStats, error)
 
 	// GetConversationSummary 按 conversation_id + user_id + org_id + scope_key 读取当前有效摘要。
 	GetConversationSummary(ctx context.Context, query aidomain.MemoryConversationSummaryQuery) (*entity.AIConversationSummary, error)
 	// UpsertConversationSummary 按 conversation_id 覆盖更新会话摘要。
 	UpsertConversationSummary(ctx context.Context, summary *entity.AIConversationSummary) error
+
+	// PageFacts 按单个 scope 分页检索 facts，包含已过期记录，供用户侧管理使用。
+	PageFacts(ctx context.Context, query aidomain.MemoryManageQuery) ([]*entity.AIMemoryFact, int64, error)
+	// GetFactByID 按主键读取 fact，不存在时返回 nil, nil。
+	GetFactByID(ctx context.Context, id uint) (*entity.AIMemoryFact, error)
+	// UpdateFactContent 更新 fact 的值、摘要、来源和过期时间。
+	UpdateFactContent(ctx context.Context, fact *entity.AIMemoryFact) error
+	// DeleteFact 物理删除指定 fact。
+	DeleteFact(ctx context.Context, id uint) error
+	// DeleteFactsByScopeKey 物理删除指定 scope 下的全部 facts，返回删除条数。
+	DeleteFactsByScopeKey(ctx context.Context, scopeKey string) (int64, error)
+
+	// PageDocuments 按单个 scope 分页检索 documents，包含已过期记录，供用户侧管理使用。
+	PageDocuments(ctx context.Context, query aidomain.MemoryManageQuery) ([]*entity.AIMemoryDocument, int64, error)
+	// GetDocumentByID 按主键读取未删除的 document，不存在时返回 nil, nil。
+	GetDocumentByID(ctx context.Context, id string) (*entity.AIMemoryDocument, error)
+	// UpdateDocumentContent 更新 document 的类型、主题、标题、摘要、正文和对应哈希。
+	UpdateDocumentContent(ctx context.Context, doc *entity.AIMemoryDocument) error
+	// ListDocumentIDsByScopeKey 读取指定 scope 下的全部 document id，包含已软删除记录。
+	ListDocumentIDsByScopeKey(ctx context.Context, scopeKey string) ([]string, error)
+	// PurgeDocuments 物理删除 documents 及其 chunks、关键词倒排、版本和召回统计，用于用户主动删除和数据主体删除请求。
+	PurgeDocuments(ctx context.Context, ids []string) error
+
+	// CreateDocumentVersion 写入一条知识库文档版本快照。
+	CreateDocumentVersion(ctx context.Context, version *entity.AIMemoryDocumentVersion) error
+	// GetLatestDocumentVersionNo 读取文档当前最大版本号，没有版本时返回 0。
+	GetLatestDocumentVersionNo(ctx context.Context, documentID string) (int, error)
+	// ListLatestDocumentVersionNos 批量读取多个文档的当前最大版本号。
+	ListLatestDocumentVersionNos(ctx context.Context, documentIDs []string) (map[string]int, error)
+	// ListDocumentVersions 按版本号倒序读取文档版本列表，不含正文快照。
+	ListDocumentVersions(ctx context.Context, documentID string) ([]*entity.AIMemoryDocumentVersion, error)
+	// GetDocumentVersion 读取指定版本快照，不存在时返回 nil, nil。
+	GetDocumentVersion(ctx context.Context, documentID string, versionNo int) (*entity.AIMemoryDocumentVersion, error)
+
+	// RecordDocumentRecalls 按 document_id 累加召回次数并刷新最近得分。
+	RecordDocumentRecalls(ctx context.Context, scores map[string]float64, recalledAt time.Time) error
+	// ListDocumentRecallStats 批量读取文档召回统计。
+	ListDocumentRecallStats(ctx context.Context, documentIDs []string) (map[string]*entity.AIMemoryDocumentRecallStat, error)
 }
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	aidomain "personal_assistant/internal/domain/ai"
+	"personal_assistant/internal/model/entity"
+)
+
+// AIMemoryVectorRepository 定义本地向量库的持久化能力。
+// 只在未部署 Qdrant 时作为 memory vector store 的后端，不参与业务事务。
+type AIMemoryVectorRepository interface {
+	// UpsertVectors 按 point_id 覆盖写入向量。
+	UpsertVectors(ctx context.Context, rows []*entity.AIMemoryChunkVector) error
+	// DeleteDocumentVectors 删除指定集合下某个文档的全部向量。
+	DeleteDocumentVectors(ctx context.Context, collectionName string, documentID string) error
+	// ListVectors 按 payload 条件预过滤候选向量，供调用方计算相似度。
+	ListVectors(ctx context.Context, filter aidomain.MemoryVectorRecordFilter) ([]*entity.AIMemoryChunkVector, error)
+}
@@ -18,12 +18,18 @@ type AIRepository interface {
 
 	CreateMessage(ctx context.Context, message *entity.AIMessage) error
 	UpdateMessage(ctx context.Context, message *entity.AIMessage) error
+	GetMessageByID(ctx context.Context, messageID string) (*entity.AIMessage, error)
 	ListMessagesByConversation(ctx context.Context, conversationID string) ([]*entity.AIMessage, error)
 
 	CreateInterrupt(ctx context.Context, interrupt *entity.AIInterrupt) error
 	GetInterruptByID(ctx context.Context, interruptID string) (*entity.AIInterrupt, error)
 	GetInterruptByIDForUpdate(ctx context.Context, interruptID string) (*entity.AIInterrupt, error)
 	ListInterruptsByUserAndStatuses(ctx context.Context, userID uint, statuses []string) ([]*entity.AIInterrupt, error)
+	ListInterruptsByConversationAndStatuses(
+		ctx context.Context,
+		conversationID string,
+		statuses []string,
+	) ([]*entity.AIInterrupt, error)
 	ListInterruptsForRecovery(
 		ctx context.Context,
 		statuses []string,
@@ -32,5 +38,12 @@ type AIRepository interface {
 	) ([]*entity.AIInterrupt, error)
 	UpdateInterrupt(ctx context.Context, interrupt *entity.AIInterrupt) error
 
+	CreateShare(ctx context.Context, share *entity.AIConversationShare) error
+	GetShareByID(ctx context.Context, shareID string) (*entity.AIConversationShare, error)
+	GetShareByTokenHash(ctx context.Context, tokenHash string) (*entity.AIConversationShare, error)
+	ListSharesByConversation(ctx context.Context, conversationID string) ([]*entity.AIConversationShare, error)
+	UpdateShare(ctx context.Context, share *entity.AIConversationShare) error
+	IncrementShareView(ctx context.Context, shareID string, viewedAt time.Time) error
+
 	WithTx(tx any) AIRepository
 }
@@ -16,8 +16,8 @@ type JWTRepository interface {
 	// CleanExpiredTokens 清理过期Token
 	CleanExpiredTokens(ctx context.Context) error
 
-	// SaveUserToken 保存用户Token记录
-	SaveUserToken(ctx context.Context, userID uint, token string, expiry time.Time) error
+	// SaveUserToken 保存用户Token记录，tokenType 取 access / refresh
+	SaveUserToken(ctx context.Context, userID uint, token, tokenType string, expiry time.Time) error
 	// GetUserTokens 获取用户的Token记录列表
 	GetUserTokens(ctx context.Context, userID uint) ([]*entity.UserToken, error)
 	// RevokeUserToken 撤销用户的指定Token
@@ -36,8 +36,8 @@ type JWTRepository interface {
 	CreateJwtBlacklist(ctx context.Context, jwtList *entity.JwtBlacklist) error
 	// IsJwtInBlacklist 检查JWT是否在黑名单中（兼容旧接口）
 	IsJwtInBlacklist(ctx context.Context, jwt string) (bool, error)
-	// GetAllJwtBlacklist 获取所有黑名单JWT（兼容旧接口）
-	GetAllJwtBlacklist(ctx context.Context) ([]string, error)
+	// ListBlacklistedSessionIDs 获取仍未过期的被吊销会话标识，用于预热本地黑名单
+	ListBlacklistedSessionIDs(ctx context.Context, now time.Time) ([]string, error)
 	// GetUserByID 根据ID获取用户（兼容旧接口）
 	GetUserByID(ctx context.Context, id uint) (*entity.User, error)
 }
@@ -13,5 +13,7 @@ type LanqiaoUserDetailRepository interface {
 	GetByCredentialHash(ctx context.Context, credentialHash string) (*entity.LanqiaoUserDetail, error)
 	UpsertByUserID(ctx context.Context, detail *entity.LanqiaoUserDetail) (*entity.LanqiaoUserDetail, error)
 	DeleteByUserID(ctx context.Context, userID uint) error
+	// PurgeByUserID 物理删除用户的蓝桥详情（含历史软删除记录）及其过题关系，释放凭据哈希唯一索引。
+	PurgeByUserID(ctx context.Context, userID uint) error
 	GetAll(ctx context.Context) ([]*entity.LanqiaoUserDetail, error)
 }
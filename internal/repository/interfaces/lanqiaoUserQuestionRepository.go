@@ -12,6 +12,7 @@ type LanqiaoUserQuestionRepository interface {
 	WithTx(tx any) LanqiaoUserQuestionRepository
 	GetSolvedProblemIDs(ctx context.Context, lanqiaoUserDetailID uint) (map[uint]struct{}, error)
 	GetSolvedProblemIDsByDetailIDs(ctx context.Context, lanqiaoUserDetailIDs []uint) (map[uint]map[uint]struct{}, error)
+	ListSolvedAtInRange(ctx context.Context, detailIDs []uint, questionIDs []uint, start time.Time, end time.Time) ([]*readmodel.SolvedQuestionAt, error)
 	BatchCreate(ctx context.Context, records []*entity.LanqiaoUserQuestion) error
 	CountSolvedByDateRange(
 		ctx context.Context,
@@ -20,4 +20,6 @@ type LeetcodeUserDetailRepository interface {
 	UpsertByUserID(ctx context.Context, detail *entity.LeetcodeUserDetail) (*entity.LeetcodeUserDetail, error)
 	// DeleteByUserID 删除用户的力扣详情
 	DeleteByUserID(ctx context.Context, userID uint) error
+	// PurgeByUserID 物理删除用户的力扣详情（含历史软删除记录）及其过题关系，用于解绑
+	PurgeByUserID(ctx context.Context, userID uint) error
 }
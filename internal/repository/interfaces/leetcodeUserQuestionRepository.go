@@ -11,6 +11,7 @@ import (
 type LeetcodeUserQuestionRepository interface {
 	GetSolvedProblemIDs(ctx context.Context, leetcodeUserDetailID uint) (map[uint]struct{}, error)
 	GetSolvedProblemIDsByDetailIDs(ctx context.Context, leetcodeUserDetailIDs []uint) (map[uint]map[uint]struct{}, error)
+	ListSolvedAtInRange(ctx context.Context, detailIDs []uint, questionIDs []uint, start time.Time, end time.Time) ([]*readmodel.SolvedQuestionAt, error)
 	BatchCreate(ctx context.Context, records []*entity.LeetcodeUserQuestion) error
 	CountSolvedByDateRange(
 		ctx context.Context,
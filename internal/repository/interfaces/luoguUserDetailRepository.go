@@ -18,6 +18,8 @@ type LuoguUserDetailRepository interface {
 	UpsertByUserID(ctx context.Context, detail *entity.LuoguUserDetail) (*entity.LuoguUserDetail, error)
 	// DeleteByUserID 删除用户的洛谷详情
 	DeleteByUserID(ctx context.Context, userID uint) error
+	// PurgeByUserID 物理删除用户的洛谷详情（含历史软删除记录）及其过题关系，用于解绑
+	PurgeByUserID(ctx context.Context, userID uint) error
 	// GetAll 获取所有已绑定洛谷的用户
 	GetAll(ctx context.Context) ([]*entity.LuoguUserDetail, error)
 }
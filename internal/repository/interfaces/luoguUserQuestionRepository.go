@@ -14,6 +14,8 @@ type LuoguUserQuestionRepository interface {
 	GetSolvedProblemIDs(ctx context.Context, luoguUserDetailID uint) (map[uint]struct{}, error)
 	// GetSolvedProblemIDsByDetailIDs 批量获取多个详情ID对应的已做题集合
 	GetSolvedProblemIDsByDetailIDs(ctx context.Context, luoguUserDetailIDs []uint) (map[uint]map[uint]struct{}, error)
+	// ListSolvedAtInRange 批量获取指定题目在时间区间内的过题时间
+	ListSolvedAtInRange(ctx context.Context, detailIDs []uint, questionIDs []uint, start time.Time, end time.Time) ([]*readmodel.SolvedQuestionAt, error)
 	// BatchCreate 批量创建用户做题记录
 	BatchCreate(ctx context.Context, records []*entity.LuoguUserQuestion) error
 	// CountSolvedByDateRange 按时间范围统计每天新增做题数
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"personal_assistant/internal/model/entity"
+)
+
+// OAuthStateRepository 第三方登录授权请求仓储。
+type OAuthStateRepository interface {
+	Create(ctx context.Context, state *entity.OAuthLoginState) error
+	// GetByStateHash 按 state 摘要获取授权请求，不存在时返回 nil。
+	GetByStateHash(ctx context.Context, stateHash string) (*entity.OAuthLoginState, error)
+	// MarkUsed 仅当授权请求未使用时标记为已使用，返回是否标记成功，用于保证 state 只能回调一次。
+	MarkUsed(ctx context.Context, id uint, now time.Time) (bool, error)
+}
@@ -0,0 +1,29 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"personal_assistant/internal/model/entity"
+)
+
+// OJContestRepository OJ 比赛、题单与冻结榜单相关仓储。
+type OJContestRepository interface {
+	WithTx(tx any) OJContestRepository
+	Create(ctx context.Context, contest *entity.OJContest) error
+	Update(ctx context.Context, contest *entity.OJContest) error
+	GetByID(ctx context.Context, contestID uint) (*entity.OJContest, error)
+	Delete(ctx context.Context, contestID uint) error
+	// UpdateStatus 以 from 状态为条件推进比赛状态，返回是否抢占成功，用于多实例下的幂等状态迁移。
+	UpdateStatus(ctx context.Context, contestID uint, from, to string, frozenAt *time.Time) (bool, error)
+	ListByOrgIDs(ctx context.Context, orgIDs []uint, allOrgs bool, status string, page, pageSize int) ([]*entity.OJContest, int64, error)
+	// ListPollable 返回需要轮询的比赛：已到开赛时间的 scheduled 比赛，以及全部 running 比赛。
+	ListPollable(ctx context.Context, now time.Time, limit int) ([]*entity.OJContest, error)
+	CreateProblems(ctx context.Context, problems []*entity.OJContestProblem) error
+	ReplaceProblems(ctx context.Context, contestID uint, problems []*entity.OJContestProblem) error
+	ListProblemsByContestID(ctx context.Context, contestID uint) ([]*entity.OJContestProblem, error)
+	CreateStandings(ctx context.Context, standings []*entity.OJContestStanding) error
+	CreateStandingItems(ctx context.Context, items []*entity.OJContestStandingItem) error
+	ListStandingsByContestID(ctx context.Context, contestID uint) ([]*entity.OJContestStanding, error)
+	ListStandingItemsByContestID(ctx context.Context, contestID uint) ([]*entity.OJContestStandingItem, error)
+}
@@ -16,6 +16,14 @@ type OJDailyStatsRepository interface {
 		fromDate time.Time,
 		toDate time.Time,
 	) ([]*entity.OJUserDailyStat, error)
+	// SumSolvedByUsers 汇总一批用户在日期区间内（含首尾）的新增过题数，返回 user_id -> 过题数。
+	SumSolvedByUsers(
+		ctx context.Context,
+		platform string,
+		userIDs []uint,
+		fromDate time.Time,
+		toDate time.Time,
+	) (map[uint]int, error)
 	// DeleteByUserPlatform 删除指定用户和平台的所有统计数据，通常用于重建最近窗口数据时清理旧数据
 	DeleteByUserPlatform(ctx context.Context, userID uint, platform string) error
 }
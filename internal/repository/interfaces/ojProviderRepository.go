@@ -0,0 +1,46 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"personal_assistant/internal/model/entity"
+	readmodel "personal_assistant/internal/model/readmodel"
+)
+
+// OJProviderUserDetailRepository 负责可插拔 OJ 平台账号详情的读写，所有查询都按 platform 隔离。
+type OJProviderUserDetailRepository interface {
+	WithTx(tx any) OJProviderUserDetailRepository
+	GetByUserPlatform(ctx context.Context, userID uint, platform string) (*entity.OJProviderUserDetail, error)
+	GetByUserIDs(ctx context.Context, platform string, userIDs []uint) ([]*entity.OJProviderUserDetail, error)
+	ListByPlatform(ctx context.Context, platform string) ([]*entity.OJProviderUserDetail, error)
+	UpsertByUserPlatform(ctx context.Context, detail *entity.OJProviderUserDetail) (*entity.OJProviderUserDetail, error)
+	UpdateSyncState(ctx context.Context, detail *entity.OJProviderUserDetail) error
+	// DeleteByUserPlatform 在同一事务内物理删除账号详情及其过题关系，保证 (user_id, platform) 唯一索引可以被重新占用。
+	DeleteByUserPlatform(ctx context.Context, userID uint, platform string) error
+}
+
+// OJProviderQuestionBankRepository 负责可插拔 OJ 平台题库的读写，题目按 (platform, problem_code) 唯一。
+type OJProviderQuestionBankRepository interface {
+	WithTx(tx any) OJProviderQuestionBankRepository
+	GetByID(ctx context.Context, id uint) (*entity.OJProviderQuestionBank, error)
+	GetByCode(ctx context.Context, platform string, problemCode string) (*entity.OJProviderQuestionBank, error)
+	ListByExactTitle(ctx context.Context, platform string, title string) ([]*entity.OJProviderQuestionBank, error)
+	EnsureQuestionID(ctx context.Context, question *entity.OJProviderQuestionBank) (uint, bool, error)
+}
+
+// OJProviderUserQuestionRepository 负责可插拔 OJ 平台过题事实的读写。
+type OJProviderUserQuestionRepository interface {
+	WithTx(tx any) OJProviderUserQuestionRepository
+	GetSolvedProblemIDs(ctx context.Context, detailID uint) (map[uint]struct{}, error)
+	GetSolvedProblemIDsByDetailIDs(ctx context.Context, detailIDs []uint) (map[uint]map[uint]struct{}, error)
+	ListSolvedAtInRange(ctx context.Context, detailIDs []uint, questionIDs []uint, start time.Time, end time.Time) ([]*readmodel.SolvedQuestionAt, error)
+	BatchCreate(ctx context.Context, records []*entity.OJProviderUserQuestion) error
+	CountSolvedByDateRange(
+		ctx context.Context,
+		detailID uint,
+		start time.Time,
+		end time.Time,
+	) ([]*readmodel.DateSolvedCount, error)
+	CountPassed(ctx context.Context, detailID uint) (int64, error)
+}
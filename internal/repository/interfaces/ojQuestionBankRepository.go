@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"context"
+
+	"personal_assistant/internal/model/entity"
+	readmodel "personal_assistant/internal/model/readmodel"
+)
+
+// OJQuestionBankRepository 跨平台题库目录检索与自定义标签仓储。
+type OJQuestionBankRepository interface {
+	WithTx(tx any) OJQuestionBankRepository
+	// Search 在各平台题库表上做归一化联合检索，返回当前页与总数。
+	Search(ctx context.Context, query *readmodel.OJQuestionBankQuery) ([]*readmodel.OJQuestionBankItem, int64, error)
+	QuestionExists(ctx context.Context, platform string, questionID uint) (bool, error)
+	CreateTag(ctx context.Context, tag *entity.OJQuestionTag) error
+	UpdateTag(ctx context.Context, tag *entity.OJQuestionTag) error
+	GetTagByID(ctx context.Context, tagID uint) (*entity.OJQuestionTag, error)
+	GetTagByName(ctx context.Context, name string) (*entity.OJQuestionTag, error)
+	// DeleteTag 物理删除标签及其全部题目关联。
+	DeleteTag(ctx context.Context, tagID uint) error
+	ListTags(ctx context.Context) ([]*readmodel.OJQuestionTagItem, error)
+	CountTagsByIDs(ctx context.Context, tagIDs []uint) (int64, error)
+	// ReplaceQuestionTags 用给定标签整体替换一道题的标签。
+	ReplaceQuestionTags(ctx context.Context, platform string, questionID uint, tagIDs []uint, operatorID uint) error
+	ListTagBindings(ctx context.Context, refs []readmodel.OJQuestionRef) ([]*readmodel.OJQuestionTagBindingItem, error)
+}
@@ -18,16 +18,25 @@ type OJTaskExecutionRepository interface {
 	GetDispatchExecutionByID(ctx context.Context, executionID uint) (*readmodel.OJTaskExecutionDispatch, error)
 	GetByTaskID(ctx context.Context, taskID uint) (*entity.OJTaskExecution, error)
 	GetByTaskIDForUpdate(ctx context.Context, taskID uint) (*entity.OJTaskExecution, error)
+	ListByTaskID(ctx context.Context, taskID uint, limit int) ([]*entity.OJTaskExecution, error)
 	ListDueExecutions(ctx context.Context, statuses []string, before time.Time, limit int) ([]*readmodel.OJTaskExecutionDispatch, error)
 	ClaimExecution(ctx context.Context, executionID uint, fromStatuses []string, startedAt time.Time) (bool, error)
+	ListDeadlineTrackingExecutions(ctx context.Context, now, lateCutoff time.Time, limit int) ([]*entity.OJTaskExecution, error)
+	ClaimDeadlineReminder(ctx context.Context, executionID uint, remindedBefore, remindedAt time.Time) (bool, error)
 	BatchCreateExecutionUsers(ctx context.Context, rows []*entity.OJTaskExecutionUser, batchSize int) error
 	ListExecutionUsersByExecutionID(ctx context.Context, executionID uint) ([]*entity.OJTaskExecutionUser, error)
+	ListExecutionUsersByExecutionIDs(ctx context.Context, executionIDs []uint) ([]*entity.OJTaskExecutionUser, error)
 	BatchCreateExecutionUserOrgs(ctx context.Context, rows []*entity.OJTaskExecutionUserOrg, batchSize int) error
 	BatchCreateExecutionUserItems(ctx context.Context, rows []*entity.OJTaskExecutionUserItem, batchSize int) error
+	ListUserItemsByResultStatus(ctx context.Context, executionID uint, resultStatus string) ([]*entity.OJTaskExecutionUserItem, error)
+	UpdateUserItemResult(ctx context.Context, item *entity.OJTaskExecutionUserItem) error
+	UpdateExecutionUserProgress(ctx context.Context, row *entity.OJTaskExecutionUser) error
 	GetVisibleExecutionDetail(ctx context.Context, userID uint, isSuperAdmin bool, taskID, executionID uint) (*readmodel.OJTaskVisibleTask, error)
 	ListVisibleExecutionUsers(ctx context.Context, userID uint, isSuperAdmin bool, taskID, executionID uint, req *request.OJTaskExecutionUserListReq) ([]*readmodel.OJTaskExecutionUserListItem, int64, error)
+	ListVisibleExecutionUsersAfter(ctx context.Context, userID uint, isSuperAdmin bool, taskID, executionID uint, req *request.OJTaskExecutionUserListReq, afterExecutionUserID uint, limit int) ([]*readmodel.OJTaskExecutionUserListItem, error)
 	GetVisibleExecutionUser(ctx context.Context, userID uint, isSuperAdmin bool, taskID, executionID, targetUserID uint) (*readmodel.OJTaskExecutionUserListItem, error)
 	ListExecutionUserOrgs(ctx context.Context, executionUserID uint) ([]*readmodel.OJTaskExecutionUserOrgItem, error)
 	ListExecutionUserOrgsByExecutionUserIDs(ctx context.Context, executionUserIDs []uint) ([]*readmodel.OJTaskExecutionUserOrgItem, error)
 	ListExecutionUserItems(ctx context.Context, executionID, targetUserID uint) ([]*readmodel.OJTaskExecutionUserItemDetail, error)
+	ListExecutionUserItemsByExecutionUserIDs(ctx context.Context, executionID uint, executionUserIDs []uint) ([]*readmodel.OJTaskExecutionUserItemDetail, error)
 }
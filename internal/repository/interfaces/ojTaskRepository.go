@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"personal_assistant/internal/model/dto/request"
 	"personal_assistant/internal/model/entity"
@@ -14,6 +15,9 @@ type OJTaskRepository interface {
 	Create(ctx context.Context, task *entity.OJTask) error
 	Update(ctx context.Context, task *entity.OJTask) error
 	UpdateRootTaskID(ctx context.Context, taskID, rootTaskID uint) error
+	UpdateStatus(ctx context.Context, taskID uint, status string, updatedBy uint) error
+	ListDueRecurringTasks(ctx context.Context, before time.Time, limit int) ([]*entity.OJTask, error)
+	AdvanceRecurrence(ctx context.Context, taskID uint, expectedNext time.Time, next *time.Time, status string) (bool, error)
 	GetByID(ctx context.Context, taskID uint) (*entity.OJTask, error)
 	GetByIDForUpdate(ctx context.Context, taskID uint) (*entity.OJTask, error)
 	GetLatestVersionByRootIDForUpdate(ctx context.Context, rootTaskID uint) (*entity.OJTask, error)
@@ -31,6 +35,8 @@ type OJTaskRepository interface {
 	ReplaceIntakes(ctx context.Context, taskID uint, rows []*entity.OJQuestionIntake) error
 	ListIntakesByTaskID(ctx context.Context, taskID uint) ([]*entity.OJQuestionIntake, error)
 	ListPendingIntakesByTitle(ctx context.Context, platform, title string) ([]*entity.OJQuestionIntake, error)
+	// ListPendingIntakeGroups 跨任务按平台与输入标题聚合待解析题目，platform 为空表示全部平台。
+	ListPendingIntakeGroups(ctx context.Context, platform string, offset, limit int) ([]*readmodel.OJPendingIntakeGroup, int64, error)
 	ListVisibleTasks(ctx context.Context, userID uint, isSuperAdmin bool, req *request.OJTaskListReq) ([]*readmodel.OJTaskListItem, int64, error)
 	GetVisibleTask(ctx context.Context, userID uint, isSuperAdmin bool, taskID uint) (*readmodel.OJTaskVisibleTask, error)
 	ListVisibleVersions(ctx context.Context, userID uint, isSuperAdmin bool, rootTaskID uint) ([]*readmodel.OJTaskVersionItem, error)
@@ -0,0 +1,43 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/entity"
+)
+
+// OrgInvitationRepository 组织邀请与加入申请仓储。
+type OrgInvitationRepository interface {
+	WithTx(tx any) OrgInvitationRepository
+
+	Create(ctx context.Context, invitation *entity.OrgInvitation) error
+	// GetByID 获取邀请，不存在时返回 nil。
+	GetByID(ctx context.Context, id uint) (*entity.OrgInvitation, error)
+	// GetByTokenHash 按令牌摘要获取邀请，不存在时返回 nil。
+	GetByTokenHash(ctx context.Context, tokenHash string) (*entity.OrgInvitation, error)
+	// ListByOrg 按创建时间倒序列出组织的全部邀请（含已失效）。
+	ListByOrg(ctx context.Context, orgID uint) ([]*entity.OrgInvitation, error)
+	// Revoke 撤销未撤销的邀请，返回是否撤销成功。
+	Revoke(ctx context.Context, id, operatorID uint, now time.Time) (bool, error)
+	// ConsumeUse 仅当邀请未撤销、未过期且仍有剩余次数时使用次数加一，返回是否成功。
+	ConsumeUse(ctx context.Context, id uint, now time.Time) (bool, error)
+
+	CreateJoinRequest(ctx context.Context, joinRequest *entity.OrgJoinRequest) error
+	// GetJoinRequestByID 获取加入申请，不存在时返回 nil。
+	GetJoinRequestByID(ctx context.Context, id uint) (*entity.OrgJoinRequest, error)
+	// GetPendingJoinRequest 获取用户在组织下待审批的申请，不存在时返回 nil。
+	GetPendingJoinRequest(ctx context.Context, orgID, userID uint) (*entity.OrgJoinRequest, error)
+	// ListJoinRequests 按创建时间倒序列出组织的加入申请，status 为空时不过滤。
+	ListJoinRequests(ctx context.Context, orgID uint, status consts.OrgJoinRequestStatus) ([]*entity.OrgJoinRequest, error)
+	// ReviewJoinRequest 仅当申请仍待审批时写入审批结果，返回是否成功。
+	ReviewJoinRequest(
+		ctx context.Context,
+		id uint,
+		status consts.OrgJoinRequestStatus,
+		reviewerID uint,
+		note string,
+		now time.Time,
+	) (bool, error)
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"personal_assistant/internal/model/entity"
+)
+
+// PasswordResetRepository 找回密码验证码仓储。
+type PasswordResetRepository interface {
+	WithTx(tx any) PasswordResetRepository
+	Create(ctx context.Context, code *entity.PasswordResetCode) error
+	// GetLatestActiveByUserID 返回用户最近一条未使用且未过期的验证码，不存在时返回 nil。
+	GetLatestActiveByUserID(ctx context.Context, userID uint, now time.Time) (*entity.PasswordResetCode, error)
+	// ClaimAttempt 原子占用一次校验机会：仅当验证码未使用、未过期且失败次数未达上限时次数加一，返回是否占用成功。
+	ClaimAttempt(ctx context.Context, id uint, maxAttempts int, now time.Time) (bool, error)
+	// InvalidateByUserID 作废用户全部未使用的验证码。
+	InvalidateByUserID(ctx context.Context, userID uint, now time.Time) error
+}
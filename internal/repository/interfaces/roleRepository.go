@@ -72,6 +72,8 @@ type RoleRepository interface {
 	GetUserRolesByOrg(ctx context.Context, userID, orgID uint) ([]*entity.Role, error)
 	// GetUserGlobalRoles 获取用户的全局角色（org_id = 0，如超级管理员等不绑定具体组织的角色）
 	GetUserGlobalRoles(ctx context.Context, userID uint) ([]*entity.Role, error)
+	// HasRoleInAnyOrg 判断用户是否在任一组织中持有指定角色
+	HasRoleInAnyOrg(ctx context.Context, userID uint, roleCode string) (bool, error)
 	// ClearRoleUserRelations 清空角色的所有用户关联
 	ClearRoleUserRelations(ctx context.Context, roleID uint) error
 
@@ -0,0 +1,36 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"personal_assistant/internal/model/entity"
+)
+
+// TwoFactorRepository 二次验证仓储，管理 TOTP 配置、恢复码与登录挑战。
+type TwoFactorRepository interface {
+	WithTx(tx any) TwoFactorRepository
+	// GetByUserID 获取用户的二次验证配置，不存在时返回 nil。
+	GetByUserID(ctx context.Context, userID uint) (*entity.UserTwoFactor, error)
+	// Save 新建或更新二次验证配置。
+	Save(ctx context.Context, setting *entity.UserTwoFactor) error
+	// DeleteByUserID 删除用户的二次验证配置与全部恢复码。
+	DeleteByUserID(ctx context.Context, userID uint) error
+	// AdvanceLastUsedStep 仅当 step 大于已记录的时间步时更新，返回是否更新成功，用于拒绝动态码重放。
+	AdvanceLastUsedStep(ctx context.Context, userID uint, step int64) (bool, error)
+
+	// ReplaceRecoveryCodes 作废旧恢复码并写入新的恢复码摘要。
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error
+	// ConsumeRecoveryCode 标记未使用的恢复码为已使用，返回是否命中。
+	ConsumeRecoveryCode(ctx context.Context, userID uint, codeHash string, now time.Time) (bool, error)
+	// CountUnusedRecoveryCodes 统计用户剩余可用的恢复码数量。
+	CountUnusedRecoveryCodes(ctx context.Context, userID uint) (int64, error)
+
+	CreateChallenge(ctx context.Context, challenge *entity.TwoFactorChallenge) error
+	// GetChallengeByTokenHash 按令牌摘要获取登录挑战，不存在时返回 nil。
+	GetChallengeByTokenHash(ctx context.Context, tokenHash string) (*entity.TwoFactorChallenge, error)
+	// ClaimChallengeAttempt 条件自增挑战的校验次数，挑战已使用、过期或次数耗尽时返回 false。
+	ClaimChallengeAttempt(ctx context.Context, id uint, maxAttempts int, now time.Time) (bool, error)
+	// ConsumeChallenge 标记未使用的挑战为已使用，返回是否命中，保证挑战只能换取一次令牌。
+	ConsumeChallenge(ctx context.Context, id uint, now time.Time) (bool, error)
+}
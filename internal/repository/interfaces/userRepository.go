@@ -17,15 +17,17 @@ type UserRepository interface {
 	GetByUsername(ctx context.Context, username string) (*entity.User, error)
 	// GetByEmail 根据邮箱获取用户
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
+	// GetByOpenid 根据第三方登录标识获取用户
+	GetByOpenid(ctx context.Context, openid string) (*entity.User, error)
 	// GetByPhone 根据手机号获取用户
 	GetByPhone(ctx context.Context, phone string) (*entity.User, error)
 	// GetByIDs 批量获取用户
 	GetByIDs(ctx context.Context, ids []uint) ([]*entity.User, error)
 	// GetByIDsActive 批量获取活跃用户
 	GetByIDsActive(ctx context.Context, ids []uint) ([]*entity.User, error)
-	// Create 创建用户
+	// Create 创建用户，唯一索引冲突时返回 gorm.ErrDuplicatedKey
 	Create(ctx context.Context, user *entity.User) error
-	// Update 更新用户
+	// Update 更新用户，唯一索引冲突时返回 gorm.ErrDuplicatedKey
 	Update(ctx context.Context, user *entity.User) error
 	// Delete 删除用户
 	Delete(ctx context.Context, id uint) error
@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"personal_assistant/internal/model/entity"
+)
+
+// UserSessionRepository 登录会话仓储。
+type UserSessionRepository interface {
+	Create(ctx context.Context, session *entity.UserSession) error
+	// GetBySessionID 按会话标识查询，不存在时返回 nil。
+	GetBySessionID(ctx context.Context, sessionID string) (*entity.UserSession, error)
+	// ListActiveByUserID 返回用户未吊销且未过期的会话，按最近活跃时间倒序。
+	ListActiveByUserID(ctx context.Context, userID uint, now time.Time) ([]*entity.UserSession, error)
+	// Rotate 仅当会话未吊销且当前刷新令牌仍为 oldJTI 时切换到 newJTI，返回是否切换成功。
+	Rotate(ctx context.Context, sessionID, oldJTI string, rotated *entity.UserSession) (bool, error)
+	// Revoke 吊销会话，已吊销的会话保持原状。
+	Revoke(ctx context.Context, sessionID, reason string, now time.Time) error
+}
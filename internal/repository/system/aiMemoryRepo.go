@@ -260,6 +260,10 @@ func (r *AIMemoryGormRepository) ReplaceDocumentChunks(
 		if err := tx.Where("document_id = ?", documentID).Delete(&entity.AIMemoryDocumentChunk{}).Error; err != nil {
 			return err
 		}
+		// 旧 chunk 的倒排随 chunk 一起失效，新 chunk 的倒排由 SaveChunkTerms 另行写入。
+		if err := tx.Where("document_id = ?", documentID).Delete(&entity.AIMemoryChunkTerm{}).Error; err != nil {
+			return err
+		}
 		if len(chunks) == 0 {
 			return nil
 		}
@@ -356,6 +360,158 @@ func (r *AIMemoryGormRepository) ListDocumentChunksByPointIDs(
 	return rows, nil
 }
 
+// ListDocumentChunksByIDs 按 chunk id 回查仍有效的 chunks。
+func (r *AIMemoryGormRepository) ListDocumentChunksByIDs(
+	ctx context.Context,
+	ids []string,
+) ([]*entity.AIMemoryDocumentChunk, error) {
+	normalizedIDs := normalizeMemoryDocumentIDs(ids)
+	if len(normalizedIDs) == 0 {
+		return []*entity.AIMemoryDocumentChunk{}, nil
+	}
+	var rows []*entity.AIMemoryDocumentChunk
+	if err := r.db.WithContext(ctx).
+		Model(&entity.AIMemoryDocumentChunk{}).
+		Joins("JOIN ai_memory_documents d ON d.id = ai_memory_document_chunks.document_id AND d.deleted_at IS NULL").
+		Where("ai_memory_document_chunks.id IN ?", normalizedIDs).
+		Where("(d.expires_at IS NULL OR d.expires_at > ?)", time.Now()).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// SaveChunkTerms 覆盖写入 chunks 的关键词倒排，并同步刷新 chunk 的 lexical_length。
+func (r *AIMemoryGormRepository) SaveChunkTerms(
+	ctx context.Context,
+	chunks []*entity.AIMemoryDocumentChunk,
+	terms []*entity.AIMemoryChunkTerm,
+) error {
+	chunkIDs := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		if chunk != nil && strings.TrimSpace(chunk.ID) != "" {
+			chunkIDs = append(chunkIDs, chunk.ID)
+		}
+	}
+	if len(chunkIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("chunk_id IN ?", chunkIDs).Delete(&entity.AIMemoryChunkTerm{}).Error; err != nil {
+			return err
+		}
+		for _, chunk := range chunks {
+			if chunk == nil || strings.TrimSpace(chunk.ID) == "" {
+				continue
+			}
+			if err := tx.Model(&entity.AIMemoryDocumentChunk{}).
+				Where("id = ?", chunk.ID).
+				UpdateColumn("lexical_length", chunk.LexicalLength).Error; err != nil {
+				return err
+			}
+		}
+		if len(terms) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(terms, 500).Error
+	})
+}
+
+// ListChunksMissingTerms 扫描仍有效但尚未建立关键词倒排的 chunks，用于存量数据补建。
+func (r *AIMemoryGormRepository) ListChunksMissingTerms(
+	ctx context.Context,
+	limit int,
+) ([]*entity.AIMemoryDocumentChunk, error) {
+	var rows []*entity.AIMemoryDocumentChunk
+	db := r.db.WithContext(ctx).
+		Model(&entity.AIMemoryDocumentChunk{}).
+		Joins("JOIN ai_memory_documents d ON d.id = ai_memory_document_chunks.document_id AND d.deleted_at IS NULL").
+		Where("ai_memory_document_chunks.lexical_length = 0").
+		Where("(d.expires_at IS NULL OR d.expires_at > ?)", time.Now())
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	if err := db.Order("ai_memory_document_chunks.created_at ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// SearchChunkTerms 在单个作用域内读取 BM25 所需的统计信息和命中倒排记录。
+// chunk 总数和平均长度按作用域统计，因此个人记忆与组织知识库的 idf 互不影响。
+func (r *AIMemoryGormRepository) SearchChunkTerms(
+	ctx context.Context,
+	query aidomain.MemoryLexicalQuery,
+) (aidomain.MemoryLexicalStats, error) {
+	stats := aidomain.MemoryLexicalStats{DocFreq: map[string]int64{}}
+	terms := normalizeMemoryDocumentIDs(query.Terms)
+	scopeKey := strings.TrimSpace(query.ScopeKey)
+	if len(terms) == 0 || scopeKey == "" {
+		return stats, nil
+	}
+	now := time.Now()
+	scopedChunks := func() *gorm.DB {
+		db := r.db.WithContext(ctx).
+			Table("ai_memory_document_chunks c").
+			Joins("JOIN ai_memory_documents d ON d.id = c.document_id AND d.deleted_at IS NULL").
+			Where("(d.expires_at IS NULL OR d.expires_at > ?)", now).
+			Where("c.scope_key = ? AND c.visibility = ?", scopeKey, strings.TrimSpace(query.Visibility))
+		if query.UserID > 0 {
+			db = db.Where("c.user_id = ?", query.UserID)
+		}
+		return db
+	}
+
+	var summary struct {
+		Total     int64
+		AvgLength float64
+	}
+	if err := scopedChunks().
+		Where("c.lexical_length > 0").
+		Select("COUNT(*) AS total, COALESCE(AVG(c.lexical_length), 0) AS avg_length").
+		Scan(&summary).Error; err != nil {
+		return stats, err
+	}
+	if summary.Total == 0 {
+		return stats, nil
+	}
+	stats.TotalChunks = summary.Total
+	stats.AvgChunkLength = summary.AvgLength
+
+	var freqRows []struct {
+		Term  string
+		Count int64
+	}
+	if err := scopedChunks().
+		Joins("JOIN ai_memory_chunk_terms t ON t.chunk_id = c.id").
+		Where("t.term IN ?", terms).
+		Select("t.term AS term, COUNT(*) AS count").
+		Group("t.term").
+		Scan(&freqRows).Error; err != nil {
+		return stats, err
+	}
+	for _, row := range freqRows {
+		stats.DocFreq[row.Term] = row.Count
+	}
+	if len(stats.DocFreq) == 0 {
+		return stats, nil
+	}
+
+	postingQuery := scopedChunks().
+		Joins("JOIN ai_memory_chunk_terms t ON t.chunk_id = c.id").
+		Where("t.term IN ?", terms).
+		Select("t.chunk_id AS chunk_id, t.term AS term, t.term_freq AS term_freq, c.lexical_length AS chunk_length").
+		Order("t.term_freq DESC").
+		Order("t.chunk_id ASC")
+	if query.PostingLimit > 0 {
+		postingQuery = postingQuery.Limit(query.PostingLimit)
+	}
+	if err := postingQuery.Scan(&stats.Postings).Error; err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
 // GetConversationSummary 获取指定会话的压缩摘要。
 func (r *AIMemoryGormRepository) GetConversationSummary(
 	ctx context.Context,
@@ -414,6 +570,366 @@ func (r *AIMemoryGormRepository) UpsertConversationSummary(
 		Create(summary).Error
 }
 
+// PageFacts 按单个 scope 分页检索 facts，管理场景下不过滤过期记录。
+func (r *AIMemoryGormRepository) PageFacts(
+	ctx context.Context,
+	query aidomain.MemoryManageQuery,
+) ([]*entity.AIMemoryFact, int64, error) {
+	scopeKey := strings.TrimSpace(query.ScopeKey)
+	if scopeKey == "" || strings.TrimSpace(string(query.Visibility)) == "" {
+		// 与召回查询保持一致：授权边界不完整时直接返回空，避免误查全表。
+		return []*entity.AIMemoryFact{}, 0, nil
+	}
+	db := r.db.WithContext(ctx).
+		Model(&entity.AIMemoryFact{}).
+		Where("scope_key = ?", scopeKey).
+		Where("visibility = ?", string(query.Visibility))
+	if namespace := strings.TrimSpace(query.Namespace); namespace != "" {
+		db = db.Where("namespace = ?", namespace)
+	}
+	if keyword := strings.TrimSpace(query.Keyword); keyword != "" {
+		like := "%" + keyword + "%"
+		db = db.Where("(summary LIKE ? OR fact_key LIKE ? OR namespace LIKE ? OR fact_value_json LIKE ?)", like, like, like, like)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []*entity.AIMemoryFact
+	if query.Limit > 0 {
+		db = db.Offset(query.Offset).Limit(query.Limit)
+	}
+	if err := db.Order("updated_at DESC").Order("id DESC").Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// GetFactByID 按主键读取 fact。
+func (r *AIMemoryGormRepository) GetFactByID(ctx context.Context, id uint) (*entity.AIMemoryFact, error) {
+	if id == 0 {
+		return nil, nil
+	}
+	var fact entity.AIMemoryFact
+	if err := r.db.WithContext(ctx).First(&fact, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &fact, nil
+}
+
+// UpdateFactContent 只更新用户可修正的字段，scope 与唯一键保持不变。
+func (r *AIMemoryGormRepository) UpdateFactContent(ctx context.Context, fact *entity.AIMemoryFact) error {
+	if fact == nil || fact.ID == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Model(&entity.AIMemoryFact{}).
+		Where("id = ?", fact.ID).
+		Updates(map[string]any{
+			"fact_value_json": fact.FactValueJSON,
+			"summary":         fact.Summary,
+			"confidence":      fact.Confidence,
+			"source_kind":     fact.SourceKind,
+			"source_id":       fact.SourceID,
+			"expires_at":      fact.ExpiresAt,
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+// DeleteFact 物理删除指定 fact。
+func (r *AIMemoryGormRepository) DeleteFact(ctx context.Context, id uint) error {
+	if id == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Delete(&entity.AIMemoryFact{}, id).Error
+}
+
+// DeleteFactsByScopeKey 物理删除指定 scope 下的全部 facts。
+func (r *AIMemoryGormRepository) DeleteFactsByScopeKey(ctx context.Context, scopeKey string) (int64, error) {
+	scopeKey = strings.TrimSpace(scopeKey)
+	if scopeKey == "" {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).
+		Where("scope_key = ?", scopeKey).
+		Delete(&entity.AIMemoryFact{})
+	return result.RowsAffected, result.Error
+}
+
+// PageDocuments 按单个 scope 分页检索 documents，管理场景下不过滤过期记录。
+func (r *AIMemoryGormRepository) PageDocuments(
+	ctx context.Context,
+	query aidomain.MemoryManageQuery,
+) ([]*entity.AIMemoryDocument, int64, error) {
+	scopeKey := strings.TrimSpace(query.ScopeKey)
+	if scopeKey == "" || strings.TrimSpace(string(query.Visibility)) == "" {
+		return []*entity.AIMemoryDocument{}, 0, nil
+	}
+	db := r.db.WithContext(ctx).
+		Model(&entity.AIMemoryDocument{}).
+		Where("scope_key = ?", scopeKey).
+		Where("visibility = ?", string(query.Visibility))
+	if memoryType := strings.TrimSpace(string(query.MemoryType)); memoryType != "" {
+		db = db.Where("memory_type = ?", memoryType)
+	}
+	if sourceKind := strings.TrimSpace(string(query.SourceKind)); sourceKind != "" {
+		db = db.Where("source_kind = ?", sourceKind)
+	}
+	if keyword := strings.TrimSpace(query.Keyword); keyword != "" {
+		like := "%" + keyword + "%"
+		db = db.Where("(title LIKE ? OR summary LIKE ? OR topic LIKE ? OR content_text LIKE ?)", like, like, like, like)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []*entity.AIMemoryDocument
+	if query.Limit > 0 {
+		db = db.Offset(query.Offset).Limit(query.Limit)
+	}
+	if err := db.Order("updated_at DESC").Order("id DESC").Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// GetDocumentByID 按主键读取未删除的 document。
+func (r *AIMemoryGormRepository) GetDocumentByID(ctx context.Context, id string) (*entity.AIMemoryDocument, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, nil
+	}
+	var doc entity.AIMemoryDocument
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&doc).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// UpdateDocumentContent 更新 document 可读字段并同步内容哈希。
+// dedup_key 保持不变，避免用户修正后被 writeback 当作新文档重复写入。
+func (r *AIMemoryGormRepository) UpdateDocumentContent(ctx context.Context, doc *entity.AIMemoryDocument) error {
+	if doc == nil || strings.TrimSpace(doc.ID) == "" {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Model(&entity.AIMemoryDocument{}).
+		Where("id = ?", strings.TrimSpace(doc.ID)).
+		Updates(map[string]any{
+			"memory_type":  doc.MemoryType,
+			"topic":        doc.Topic,
+			"title":        doc.Title,
+			"summary":      doc.Summary,
+			"content_text": doc.ContentText,
+			"content_hash": aidomain.BuildMemoryDocumentContentHash(doc.ContentText),
+			"summary_hash": aidomain.BuildMemoryDocumentSummaryHash(doc.Summary),
+			"source_kind":  doc.SourceKind,
+			"source_id":    doc.SourceID,
+			"expires_at":   doc.ExpiresAt,
+			"updated_at":   time.Now(),
+		}).Error
+}
+
+// ListDocumentIDsByScopeKey 读取指定 scope 下的全部 document id，包含已软删除记录。
+func (r *AIMemoryGormRepository) ListDocumentIDsByScopeKey(ctx context.Context, scopeKey string) ([]string, error) {
+	scopeKey = strings.TrimSpace(scopeKey)
+	if scopeKey == "" {
+		return []string{}, nil
+	}
+	var ids []string
+	if err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&entity.AIMemoryDocument{}).
+		Where("scope_key = ?", scopeKey).
+		Order("id ASC").
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// PurgeDocuments 物理删除 documents 及其 chunks、关键词倒排、版本快照和召回统计。
+// 这里不走软删除：用户主动删除的记忆不应再以任何形式留在库里。
+func (r *AIMemoryGormRepository) PurgeDocuments(ctx context.Context, ids []string) error {
+	normalizedIDs := normalizeMemoryDocumentIDs(ids)
+	if len(normalizedIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("document_id IN ?", normalizedIDs).Delete(&entity.AIMemoryDocumentChunk{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("document_id IN ?", normalizedIDs).Delete(&entity.AIMemoryChunkTerm{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("document_id IN ?", normalizedIDs).Delete(&entity.AIMemoryDocumentVersion{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("document_id IN ?", normalizedIDs).Delete(&entity.AIMemoryDocumentRecallStat{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", normalizedIDs).Delete(&entity.AIMemoryDocument{}).Error
+	})
+}
+
+// CreateDocumentVersion 写入一条文档版本快照。
+func (r *AIMemoryGormRepository) CreateDocumentVersion(ctx context.Context, version *entity.AIMemoryDocumentVersion) error {
+	if version == nil {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(version).Error
+}
+
+// GetLatestDocumentVersionNo 读取文档当前最大版本号，没有任何版本时返回 0。
+func (r *AIMemoryGormRepository) GetLatestDocumentVersionNo(ctx context.Context, documentID string) (int, error) {
+	documentID = strings.TrimSpace(documentID)
+	if documentID == "" {
+		return 0, nil
+	}
+	var latest int
+	if err := r.db.WithContext(ctx).
+		Model(&entity.AIMemoryDocumentVersion{}).
+		Where("document_id = ?", documentID).
+		Select("COALESCE(MAX(version_no), 0)").
+		Scan(&latest).Error; err != nil {
+		return 0, err
+	}
+	return latest, nil
+}
+
+// ListLatestDocumentVersionNos 批量读取多个文档的当前最大版本号。
+func (r *AIMemoryGormRepository) ListLatestDocumentVersionNos(ctx context.Context, documentIDs []string) (map[string]int, error) {
+	normalizedIDs := normalizeMemoryDocumentIDs(documentIDs)
+	result := make(map[string]int, len(normalizedIDs))
+	if len(normalizedIDs) == 0 {
+		return result, nil
+	}
+	var rows []struct {
+		DocumentID string
+		VersionNo  int
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&entity.AIMemoryDocumentVersion{}).
+		Select("document_id, MAX(version_no) AS version_no").
+		Where("document_id IN ?", normalizedIDs).
+		Group("document_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.DocumentID] = row.VersionNo
+	}
+	return result, nil
+}
+
+// ListDocumentVersions 按版本号倒序读取文档的全部版本快照，不返回正文以控制列表体积。
+func (r *AIMemoryGormRepository) ListDocumentVersions(
+	ctx context.Context,
+	documentID string,
+) ([]*entity.AIMemoryDocumentVersion, error) {
+	documentID = strings.TrimSpace(documentID)
+	if documentID == "" {
+		return []*entity.AIMemoryDocumentVersion{}, nil
+	}
+	var rows []*entity.AIMemoryDocumentVersion
+	if err := r.db.WithContext(ctx).
+		Omit("content_text").
+		Where("document_id = ?", documentID).
+		Order("version_no DESC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// GetDocumentVersion 按 document_id + version_no 读取单个版本快照，不存在时返回 nil, nil。
+func (r *AIMemoryGormRepository) GetDocumentVersion(
+	ctx context.Context,
+	documentID string,
+	versionNo int,
+) (*entity.AIMemoryDocumentVersion, error) {
+	documentID = strings.TrimSpace(documentID)
+	if documentID == "" || versionNo <= 0 {
+		return nil, nil
+	}
+	var version entity.AIMemoryDocumentVersion
+	if err := r.db.WithContext(ctx).
+		Where("document_id = ? AND version_no = ?", documentID, versionNo).
+		First(&version).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &version, nil
+}
+
+// RecordDocumentRecalls 按文档累加召回次数，并刷新最近一次召回得分和时间。
+func (r *AIMemoryGormRepository) RecordDocumentRecalls(
+	ctx context.Context,
+	scores map[string]float64,
+	recalledAt time.Time,
+) error {
+	if len(scores) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for documentID, score := range scores {
+			documentID = strings.TrimSpace(documentID)
+			if documentID == "" {
+				continue
+			}
+			stat := &entity.AIMemoryDocumentRecallStat{
+				DocumentID:     documentID,
+				RecallCount:    1,
+				LastScore:      score,
+				LastRecalledAt: &recalledAt,
+			}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "document_id"}},
+				DoUpdates: clause.Assignments(map[string]any{
+					"recall_count":     gorm.Expr("recall_count + ?", 1),
+					"last_score":       score,
+					"last_recalled_at": recalledAt,
+				}),
+			}).Create(stat).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListDocumentRecallStats 批量读取文档召回统计，返回以 document_id 为键的映射。
+func (r *AIMemoryGormRepository) ListDocumentRecallStats(
+	ctx context.Context,
+	documentIDs []string,
+) (map[string]*entity.AIMemoryDocumentRecallStat, error) {
+	normalizedIDs := normalizeMemoryDocumentIDs(documentIDs)
+	result := make(map[string]*entity.AIMemoryDocumentRecallStat, len(normalizedIDs))
+	if len(normalizedIDs) == 0 {
+		return result, nil
+	}
+	var rows []*entity.AIMemoryDocumentRecallStat
+	if err := r.db.WithContext(ctx).
+		Where("document_id IN ?", normalizedIDs).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.DocumentID] = row
+	}
+	return result, nil
+}
+
 func (r *AIMemoryGormRepository) prepareDocumentUpserts(
 	ctx context.Context,
 	docs []*entity.AIMemoryDocument,
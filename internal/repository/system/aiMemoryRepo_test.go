@@ -40,6 +40,7 @@ func TestAIMemoryModelsAutoMigrateAddsExpectedTablesAndIndexes(t *testing.T) {
 	assertHasIndex(t, db, &entity.AIMemoryDocumentChunk{}, "idx_ai_memory_document_chunks_document_id")
 	assertHasIndex(t, db, &entity.AIMemoryDocumentChunk{}, "idx_ai_memory_document_chunks_document_index")
 	assertHasIndex(t, db, &entity.AIMemoryDocumentChunk{}, "uk_ai_memory_document_chunks_qdrant_point_id")
+	assertHasIndex(t, db, &entity.AIMemoryChunkTerm{}, "idx_ai_memory_chunk_terms_term_scope")
 	assertHasIndex(t, db, &entity.AIConversationSummary{}, "idx_ai_conversation_summaries_scope_key")
 	assertHasIndex(t, db, &entity.AIConversationSummary{}, "idx_ai_conversation_summaries_user_id")
 	assertHasIndex(t, db, &entity.AIConversationSummary{}, "idx_ai_conversation_summaries_org_id")
@@ -757,6 +758,128 @@ func TestAIMemoryRepositoryListDocumentsNeedingIndex(t *testing.T) {
 	}
 }
 
+func TestAIMemoryRepositoryChunkTermsSearchAndBackfill(t *testing.T) {
+	db := newAIMemoryRepositoryTestDB(t)
+	repo := NewAIMemoryRepository(db)
+	ctx := context.Background()
+	userID := uint(110)
+	scopeKey := aidomain.BuildSelfMemoryScopeKey(userID)
+	now := time.Now()
+
+	docs := []*entity.AIMemoryDocument{
+		{
+			ID:          "doc-term-a",
+			ScopeKey:    scopeKey,
+			ScopeType:   string(aidomain.MemoryScopeSelf),
+			Visibility:  string(aidomain.MemoryVisibilitySelf),
+			UserID:      &userID,
+			MemoryType:  string(aidomain.MemoryTypeSemantic),
+			Title:       "a",
+			ContentText: "a",
+		},
+		{
+			ID:          "doc-term-b",
+			ScopeKey:    scopeKey,
+			ScopeType:   string(aidomain.MemoryScopeSelf),
+			Visibility:  string(aidomain.MemoryVisibilitySelf),
+			UserID:      &userID,
+			MemoryType:  string(aidomain.MemoryTypeSemantic),
+			Title:       "b",
+			ContentText: "b",
+		},
+	}
+	if err := repo.BatchUpsertDocuments(ctx, docs); err != nil {
+		t.Fatalf("BatchUpsertDocuments() error = %v", err)
+	}
+	chunkA := buildAIMemoryRepositoryTestChunk("chunk-term-a", "doc-term-a", scopeKey, "44444444-4444-4444-4444-444444444444", now)
+	chunkB := buildAIMemoryRepositoryTestChunk("chunk-term-b", "doc-term-b", scopeKey, "55555555-5555-5555-5555-555555555555", now)
+	for _, chunk := range []*entity.AIMemoryDocumentChunk{chunkA, chunkB} {
+		chunk.UserID = &userID
+		if err := repo.ReplaceDocumentChunks(ctx, chunk.DocumentID, []*entity.AIMemoryDocumentChunk{chunk}); err != nil {
+			t.Fatalf("ReplaceDocumentChunks(%s) error = %v", chunk.DocumentID, err)
+		}
+	}
+	pending, err := repo.ListChunksMissingTerms(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListChunksMissingTerms() error = %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("pending chunks = %d, want 2", len(pending))
+	}
+
+	buildTerm := func(chunk *entity.AIMemoryDocumentChunk, term string, freq int) *entity.AIMemoryChunkTerm {
+		return &entity.AIMemoryChunkTerm{
+			ChunkID:    chunk.ID,
+			Term:       term,
+			DocumentID: chunk.DocumentID,
+			ScopeKey:   chunk.ScopeKey,
+			Visibility: chunk.Visibility,
+			UserID:     &userID,
+			TermFreq:   freq,
+		}
+	}
+	chunkA.LexicalLength = 4
+	chunkB.LexicalLength = 8
+	if err := repo.SaveChunkTerms(ctx, []*entity.AIMemoryDocumentChunk{chunkA, chunkB}, []*entity.AIMemoryChunkTerm{
+		buildTerm(chunkA, "p1001", 2),
+		buildTerm(chunkA, "dp", 1),
+		buildTerm(chunkB, "dp", 3),
+	}); err != nil {
+		t.Fatalf("SaveChunkTerms() error = %v", err)
+	}
+	pending, err = repo.ListChunksMissingTerms(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListChunksMissingTerms(after save) error = %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("pending chunks after save = %d, want 0", len(pending))
+	}
+
+	stats, err := repo.SearchChunkTerms(ctx, aidomain.MemoryLexicalQuery{
+		ScopeKey:   scopeKey,
+		Visibility: string(aidomain.MemoryVisibilitySelf),
+		UserID:     userID,
+		Terms:      []string{"dp", "p1001", "missing"},
+	})
+	if err != nil {
+		t.Fatalf("SearchChunkTerms() error = %v", err)
+	}
+	if stats.TotalChunks != 2 || stats.AvgChunkLength != 6 {
+		t.Fatalf("stats summary = %d / %v", stats.TotalChunks, stats.AvgChunkLength)
+	}
+	if stats.DocFreq["dp"] != 2 || stats.DocFreq["p1001"] != 1 || stats.DocFreq["missing"] != 0 {
+		t.Fatalf("doc freq = %v", stats.DocFreq)
+	}
+	if len(stats.Postings) != 3 || stats.Postings[0].ChunkID != "chunk-term-b" || stats.Postings[0].ChunkLength != 8 {
+		t.Fatalf("postings = %+v", stats.Postings)
+	}
+
+	otherUserStats, err := repo.SearchChunkTerms(ctx, aidomain.MemoryLexicalQuery{
+		ScopeKey:   scopeKey,
+		Visibility: string(aidomain.MemoryVisibilitySelf),
+		UserID:     userID + 1,
+		Terms:      []string{"dp"},
+	})
+	if err != nil {
+		t.Fatalf("SearchChunkTerms(other user) error = %v", err)
+	}
+	if otherUserStats.TotalChunks != 0 || len(otherUserStats.Postings) != 0 {
+		t.Fatalf("other user stats = %+v", otherUserStats)
+	}
+
+	// 重建 chunks 时旧倒排随之失效。
+	if err := repo.ReplaceDocumentChunks(ctx, "doc-term-a", nil); err != nil {
+		t.Fatalf("ReplaceDocumentChunks(clear) error = %v", err)
+	}
+	var remaining int64
+	if err := db.Model(&entity.AIMemoryChunkTerm{}).Where("document_id = ?", "doc-term-a").Count(&remaining).Error; err != nil {
+		t.Fatalf("count terms: %v", err)
+	}
+	if remaining != 0 {
+		t.Fatalf("remaining terms = %d, want 0", remaining)
+	}
+}
+
 func buildAIMemoryRepositoryTestChunk(
 	id string,
 	documentID string,
@@ -793,6 +916,7 @@ func newAIMemoryRepositoryTestDB(t *testing.T) *gorm.DB {
 		&entity.AIMemoryFact{},
 		&entity.AIMemoryDocument{},
 		&entity.AIMemoryDocumentChunk{},
+		&entity.AIMemoryChunkTerm{},
 		&entity.AIConversationSummary{},
 	); err != nil {
 		t.Fatalf("auto migrate ai memory models: %v", err)
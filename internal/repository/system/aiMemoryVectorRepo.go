@@ -0,0 +1,92 @@
+package system
+
+import (
+	"context"
+	"strings"
+
+	aidomain "personal_assistant/internal/domain/ai"
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository/interfaces"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AIMemoryVectorGormRepository 基于 GORM 实现本地向量库持久化。
+type AIMemoryVectorGormRepository struct {
+	db *gorm.DB
+}
+
+// NewAIMemoryVectorRepository 创建本地向量仓储实例。
+func NewAIMemoryVectorRepository(db *gorm.DB) interfaces.AIMemoryVectorRepository {
+	return &AIMemoryVectorGormRepository{db: db}
+}
+
+// UpsertVectors 按 point_id 覆盖写入向量。
+func (r *AIMemoryVectorGormRepository) UpsertVectors(ctx context.Context, rows []*entity.AIMemoryChunkVector) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "point_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"collection_name",
+				"document_id",
+				"chunk_id",
+				"scope_key",
+				"visibility",
+				"user_id",
+				"org_id",
+				"memory_type",
+				"dimension",
+				"vector",
+				"created_at",
+			}),
+		}).
+		Create(&rows).Error
+}
+
+// DeleteDocumentVectors 删除指定集合下某个文档的全部向量。
+func (r *AIMemoryVectorGormRepository) DeleteDocumentVectors(
+	ctx context.Context,
+	collectionName string,
+	documentID string,
+) error {
+	return r.db.WithContext(ctx).
+		Where("collection_name = ? AND document_id = ?", collectionName, documentID).
+		Delete(&entity.AIMemoryChunkVector{}).Error
+}
+
+// ListVectors 按 payload 条件预过滤候选向量。
+func (r *AIMemoryVectorGormRepository) ListVectors(
+	ctx context.Context,
+	filter aidomain.MemoryVectorRecordFilter,
+) ([]*entity.AIMemoryChunkVector, error) {
+	db := r.db.WithContext(ctx).
+		Model(&entity.AIMemoryChunkVector{}).
+		Where("collection_name = ?", filter.CollectionName)
+	if scopeKey := strings.TrimSpace(filter.ScopeKey); scopeKey != "" {
+		db = db.Where("scope_key = ?", scopeKey)
+	}
+	if visibility := strings.TrimSpace(filter.Visibility); visibility != "" {
+		db = db.Where("visibility = ?", visibility)
+	}
+	if filter.UserID > 0 {
+		db = db.Where("user_id = ?", filter.UserID)
+	}
+	if len(filter.MemoryTypes) > 0 {
+		db = db.Where("memory_type IN ?", filter.MemoryTypes)
+	}
+	if filter.Dimension > 0 {
+		db = db.Where("dimension = ?", filter.Dimension)
+	}
+	if filter.Limit > 0 {
+		db = db.Limit(filter.Limit)
+	}
+	var rows []*entity.AIMemoryChunkVector
+	if err := db.Order("point_id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -180,6 +180,10 @@ func (r *AIGormRepository) DeleteConversationCascade(ctx context.Context, conver
 		if err := tx.Where("conversation_id = ?", conversationID).Delete(&entity.AIInterrupt{}).Error; err != nil {
 			return err
 		}
+		// 分享快照随会话一起删除，已发出的链接随之失效。
+		if err := tx.Where("conversation_id = ?", conversationID).Delete(&entity.AIConversationShare{}).Error; err != nil {
+			return err
+		}
 		return tx.Where("id = ?", conversationID).Delete(&entity.AIConversation{}).Error
 	})
 }
@@ -220,6 +224,18 @@ func (r *AIGormRepository) UpdateMessage(ctx context.Context, message *entity.AI
 	return r.db.WithContext(ctx).Save(message).Error
 }
 
+// GetMessageByID 按消息 ID 查询单条消息，不存在时返回 nil, nil。
+func (r *AIGormRepository) GetMessageByID(ctx context.Context, messageID string) (*entity.AIMessage, error) {
+	var message entity.AIMessage
+	if err := r.db.WithContext(ctx).Where("id = ?", messageID).First(&message).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &message, nil
+}
+
 // ListMessagesByConversation 用于查询并返回一组结果。
 // 参数：
 //   - ctx：链路上下文，用于取消、超时控制和日志透传。
@@ -327,6 +343,23 @@ func (r *AIGormRepository) ListInterruptsByUserAndStatuses(
 	return interrupts, nil
 }
 
+// ListInterruptsByConversationAndStatuses 返回某个会话指定状态下的 interrupt。
+func (r *AIGormRepository) ListInterruptsByConversationAndStatuses(
+	ctx context.Context,
+	conversationID string,
+	statuses []string,
+) ([]*entity.AIInterrupt, error) {
+	var interrupts []*entity.AIInterrupt
+	query := r.db.WithContext(ctx).Where("conversation_id = ?", conversationID)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	if err := query.Order("updated_at ASC").Find(&interrupts).Error; err != nil {
+		return nil, err
+	}
+	return interrupts, nil
+}
+
 // ListInterruptsForRecovery 返回达到恢复扫描条件的一批 interrupt。
 func (r *AIGormRepository) ListInterruptsForRecovery(
 	ctx context.Context,
@@ -366,3 +399,64 @@ func (r *AIGormRepository) ListInterruptsForRecovery(
 func (r *AIGormRepository) UpdateInterrupt(ctx context.Context, interrupt *entity.AIInterrupt) error {
 	return r.db.WithContext(ctx).Save(interrupt).Error
 }
+
+// CreateShare 负责写入一条会话分享记录。
+func (r *AIGormRepository) CreateShare(ctx context.Context, share *entity.AIConversationShare) error {
+	return r.db.WithContext(ctx).Create(share).Error
+}
+
+// GetShareByID 按分享 ID 查询分享记录，不存在时返回 nil。
+func (r *AIGormRepository) GetShareByID(ctx context.Context, shareID string) (*entity.AIConversationShare, error) {
+	var share entity.AIConversationShare
+	if err := r.db.WithContext(ctx).Where("id = ?", shareID).First(&share).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &share, nil
+}
+
+// GetShareByTokenHash 按 token 哈希查询分享记录，不存在时返回 nil。
+func (r *AIGormRepository) GetShareByTokenHash(ctx context.Context, tokenHash string) (*entity.AIConversationShare, error) {
+	var share entity.AIConversationShare
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&share).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &share, nil
+}
+
+// ListSharesByConversation 返回会话下的全部分享记录，最新创建的排在前面。
+func (r *AIGormRepository) ListSharesByConversation(
+	ctx context.Context,
+	conversationID string,
+) ([]*entity.AIConversationShare, error) {
+	var shares []*entity.AIConversationShare
+	if err := r.db.WithContext(ctx).
+		Where("conversation_id = ?", conversationID).
+		Order("created_at DESC").
+		Order("id DESC").
+		Find(&shares).Error; err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// UpdateShare 负责整体更新分享记录。
+func (r *AIGormRepository) UpdateShare(ctx context.Context, share *entity.AIConversationShare) error {
+	return r.db.WithContext(ctx).Save(share).Error
+}
+
+// IncrementShareView 原子递增分享查看次数并刷新最近查看时间。
+func (r *AIGormRepository) IncrementShareView(ctx context.Context, shareID string, viewedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.AIConversationShare{}).
+		Where("id = ?", shareID).
+		Updates(map[string]any{
+			"view_count":     gorm.Expr("view_count + ?", 1),
+			"last_viewed_at": viewedAt,
+		}).Error
+}
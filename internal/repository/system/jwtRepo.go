@@ -57,12 +57,13 @@ func (r *JWTGormRepository) SaveUserToken(
 	ctx context.Context,
 	userID uint,
 	token string,
+	tokenType string,
 	expiry time.Time,
 ) error {
 	userToken := &entity.UserToken{
 		UserID:    userID,
 		Token:     token,
-		TokenType: "access",
+		TokenType: tokenType,
 		ExpiresAt: expiry,
 		IsRevoked: false,
 	}
@@ -187,10 +188,13 @@ func (r *JWTGormRepository) IsJwtInBlacklist(
 	return count > 0, err
 }
 
-// GetAllJwtBlacklist 获取所有JWT黑名单（兼容现有Service层）
-func (r *JWTGormRepository) GetAllJwtBlacklist(ctx context.Context) ([]string, error) {
+// ListBlacklistedSessionIDs 获取仍未过期的被吊销会话标识
+func (r *JWTGormRepository) ListBlacklistedSessionIDs(ctx context.Context, now time.Time) ([]string, error) {
 	var data []string
-	err := r.db.WithContext(ctx).Model(&entity.JwtBlacklist{}).Pluck("jwt", &data).Error
+	err := r.db.WithContext(ctx).Model(&entity.JwtBlacklist{}).
+		Where("session_id <> '' AND (expires_at IS NULL OR expires_at > ?)", now).
+		Distinct().
+		Pluck("session_id", &data).Error
 	return data, err
 }
 
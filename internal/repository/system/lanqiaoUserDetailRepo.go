@@ -111,6 +111,24 @@ func (r *lanqiaoUserDetailRepository) DeleteByUserID(ctx context.Context, userID
 	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.LanqiaoUserDetail{}).Error
 }
 
+// PurgeByUserID 物理删除用户的蓝桥详情及过题关系，避免软删除记录继续占用 credential_hash 唯一索引。
+func (r *lanqiaoUserDetailRepository) PurgeByUserID(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		detailIDs := tx.Unscoped().
+			Model(&entity.LanqiaoUserDetail{}).
+			Select("id").
+			Where("user_id = ?", userID)
+		if err := tx.Unscoped().
+			Where("lanqiao_user_detail_id IN (?)", detailIDs).
+			Delete(&entity.LanqiaoUserQuestion{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().
+			Where("user_id = ?", userID).
+			Delete(&entity.LanqiaoUserDetail{}).Error
+	})
+}
+
 func (r *lanqiaoUserDetailRepository) GetAll(ctx context.Context) ([]*entity.LanqiaoUserDetail, error) {
 	var details []*entity.LanqiaoUserDetail
 	err := r.db.WithContext(ctx).Find(&details).Error
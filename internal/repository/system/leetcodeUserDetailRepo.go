@@ -122,3 +122,22 @@ func (r *leetcodeUserDetailRepository) DeleteByUserID(ctx context.Context, userI
 		Where("user_id = ?", userID).
 		Delete(&entity.LeetcodeUserDetail{}).Error
 }
+
+// PurgeByUserID 物理删除用户的力扣详情及过题关系。
+// 软删除不会触发外键级联，这里显式先删关系再删详情，并一并清理历次换绑残留的软删除记录。
+func (r *leetcodeUserDetailRepository) PurgeByUserID(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		detailIDs := tx.Unscoped().
+			Model(&entity.LeetcodeUserDetail{}).
+			Select("id").
+			Where("user_id = ?", userID)
+		if err := tx.Unscoped().
+			Where("leetcode_user_detail_id IN (?)", detailIDs).
+			Delete(&entity.LeetcodeUserQuestion{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().
+			Where("user_id = ?", userID).
+			Delete(&entity.LeetcodeUserDetail{}).Error
+	})
+}
@@ -69,6 +69,35 @@ func (r *leetcodeUserQuestionRepository) GetSolvedProblemIDsByDetailIDs(
 	return result, nil
 }
 
+// ListSolvedAtInRange 批量读取指定账号在指定题目上、落在 [start, end] 内的首次通过时间。
+func (r *leetcodeUserQuestionRepository) ListSolvedAtInRange(
+	ctx context.Context,
+	detailIDs []uint,
+	questionIDs []uint,
+	start time.Time,
+	end time.Time,
+) ([]*readmodel.SolvedQuestionAt, error) {
+	if len(detailIDs) == 0 || len(questionIDs) == 0 || end.Before(start) {
+		return nil, nil
+	}
+	var rows []*readmodel.SolvedQuestionAt
+	err := r.db.WithContext(ctx).
+		Model(&entity.LeetcodeUserQuestion{}).
+		Select("leetcode_user_detail_id AS detail_id, leetcode_question_id AS question_id, created_at AS solved_at").
+		Where(
+			"leetcode_user_detail_id IN ? AND leetcode_question_id IN ? AND created_at >= ? AND created_at <= ?",
+			detailIDs,
+			questionIDs,
+			start,
+			end,
+		).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 func (r *leetcodeUserQuestionRepository) BatchCreate(
 	ctx context.Context,
 	records []*entity.LeetcodeUserQuestion,
@@ -112,6 +112,25 @@ func (r *luoguUserDetailRepository) DeleteByUserID(ctx context.Context, userID u
 		Delete(&entity.LuoguUserDetail{}).Error
 }
 
+// PurgeByUserID 物理删除用户的洛谷详情及过题关系。
+// 软删除不会触发外键级联，这里显式先删关系再删详情，并一并清理历次换绑残留的软删除记录。
+func (r *luoguUserDetailRepository) PurgeByUserID(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		detailIDs := tx.Unscoped().
+			Model(&entity.LuoguUserDetail{}).
+			Select("id").
+			Where("user_id = ?", userID)
+		if err := tx.Unscoped().
+			Where("luogu_user_detail_id IN (?)", detailIDs).
+			Delete(&entity.LuoguUserQuestion{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().
+			Where("user_id = ?", userID).
+			Delete(&entity.LuoguUserDetail{}).Error
+	})
+}
+
 func (r *luoguUserDetailRepository) GetAll(ctx context.Context) ([]*entity.LuoguUserDetail, error) {
 	var details []*entity.LuoguUserDetail
 	err := r.db.WithContext(ctx).Find(&details).Error
@@ -0,0 +1,51 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository/interfaces"
+
+	"gorm.io/gorm"
+)
+
+// OAuthStateGormRepository 第三方登录授权请求仓储GORM实现
+type OAuthStateGormRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthStateRepository 创建第三方登录授权请求仓储实例
+func NewOAuthStateRepository(db *gorm.DB) interfaces.OAuthStateRepository {
+	return &OAuthStateGormRepository{db: db}
+}
+
+// Create 写入授权请求
+func (r *OAuthStateGormRepository) Create(ctx context.Context, state *entity.OAuthLoginState) error {
+	return r.db.WithContext(ctx).Create(state).Error
+}
+
+// GetByStateHash 按 state 摘要获取授权请求
+func (r *OAuthStateGormRepository) GetByStateHash(ctx context.Context, stateHash string) (*entity.OAuthLoginState, error) {
+	var state entity.OAuthLoginState
+	err := r.db.WithContext(ctx).Where("state_hash = ?", stateHash).First(&state).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+// MarkUsed 条件更新使用时间
+func (r *OAuthStateGormRepository) MarkUsed(ctx context.Context, id uint, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.OAuthLoginState{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,211 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository/interfaces"
+
+	"gorm.io/gorm"
+)
+
+type ojContestRepository struct {
+	db *gorm.DB
+}
+
+func NewOJContestRepository(db *gorm.DB) interfaces.OJContestRepository {
+	return &ojContestRepository{db: db}
+}
+
+func (r *ojContestRepository) WithTx(tx any) interfaces.OJContestRepository {
+	if transaction, ok := tx.(*gorm.DB); ok {
+		return &ojContestRepository{db: transaction}
+	}
+	return r
+}
+
+func (r *ojContestRepository) Create(ctx context.Context, contest *entity.OJContest) error {
+	return r.db.WithContext(ctx).Create(contest).Error
+}
+
+func (r *ojContestRepository) Update(ctx context.Context, contest *entity.OJContest) error {
+	return r.db.WithContext(ctx).Save(contest).Error
+}
+
+// GetByID 获取指定 ID 的比赛；未找到时返回 nil。
+func (r *ojContestRepository) GetByID(ctx context.Context, contestID uint) (*entity.OJContest, error) {
+	var contest entity.OJContest
+	err := r.db.WithContext(ctx).First(&contest, contestID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &contest, nil
+}
+
+// Delete 软删除比赛并物理清理题单；只有未开赛的比赛会走到这里，因此不存在冻结榜单。
+func (r *ojContestRepository) Delete(ctx context.Context, contestID uint) error {
+	if err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("contest_id = ?", contestID).
+		Delete(&entity.OJContestProblem{}).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Delete(&entity.OJContest{}, contestID).Error
+}
+
+func (r *ojContestRepository) UpdateStatus(
+	ctx context.Context,
+	contestID uint,
+	from, to string,
+	frozenAt *time.Time,
+) (bool, error) {
+	updates := map[string]any{"status": to}
+	if frozenAt != nil {
+		updates["frozen_at"] = *frozenAt
+	}
+	result := r.db.WithContext(ctx).
+		Model(&entity.OJContest{}).
+		Where("id = ? AND status = ?", contestID, from).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *ojContestRepository) ListByOrgIDs(
+	ctx context.Context,
+	orgIDs []uint,
+	allOrgs bool,
+	status string,
+	page, pageSize int,
+) ([]*entity.OJContest, int64, error) {
+	if !allOrgs && len(orgIDs) == 0 {
+		return []*entity.OJContest{}, 0, nil
+	}
+	query := r.db.WithContext(ctx).Model(&entity.OJContest{})
+	if !allOrgs {
+		query = query.Where("org_id IN ?", orgIDs)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []*entity.OJContest
+	if err := query.
+		Order("start_at DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+func (r *ojContestRepository) ListPollable(ctx context.Context, now time.Time, limit int) ([]*entity.OJContest, error) {
+	var rows []*entity.OJContest
+	query := r.db.WithContext(ctx).
+		Where(
+			"(status = ? AND start_at <= ?) OR status = ?",
+			string(consts.OJContestStatusScheduled), now,
+			string(consts.OJContestStatusRunning),
+		).
+		Order("start_at ASC, id ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *ojContestRepository) CreateProblems(ctx context.Context, problems []*entity.OJContestProblem) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(problems, 100).Error
+}
+
+func (r *ojContestRepository) ReplaceProblems(
+	ctx context.Context,
+	contestID uint,
+	problems []*entity.OJContestProblem,
+) error {
+	if err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("contest_id = ?", contestID).
+		Delete(&entity.OJContestProblem{}).Error; err != nil {
+		return err
+	}
+	return r.CreateProblems(ctx, problems)
+}
+
+func (r *ojContestRepository) ListProblemsByContestID(
+	ctx context.Context,
+	contestID uint,
+) ([]*entity.OJContestProblem, error) {
+	var problems []*entity.OJContestProblem
+	err := r.db.WithContext(ctx).
+		Where("contest_id = ?", contestID).
+		Order("sort_no ASC, id ASC").
+		Find(&problems).Error
+	if err != nil {
+		return nil, err
+	}
+	return problems, nil
+}
+
+func (r *ojContestRepository) CreateStandings(ctx context.Context, standings []*entity.OJContestStanding) error {
+	if len(standings) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(standings, 200).Error
+}
+
+func (r *ojContestRepository) CreateStandingItems(ctx context.Context, items []*entity.OJContestStandingItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(items, 500).Error
+}
+
+func (r *ojContestRepository) ListStandingsByContestID(
+	ctx context.Context,
+	contestID uint,
+) ([]*entity.OJContestStanding, error) {
+	var rows []*entity.OJContestStanding
+	err := r.db.WithContext(ctx).
+		Where("contest_id = ?", contestID).
+		Order("`rank` ASC, user_id ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *ojContestRepository) ListStandingItemsByContestID(
+	ctx context.Context,
+	contestID uint,
+) ([]*entity.OJContestStandingItem, error) {
+	var rows []*entity.OJContestStandingItem
+	err := r.db.WithContext(ctx).
+		Where("contest_id = ?", contestID).
+		Order("standing_id ASC, problem_id ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
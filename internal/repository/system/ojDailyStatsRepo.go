@@ -67,6 +67,38 @@ func (r *ojDailyStatsRepository) ListRange(
 	return rows, nil
 }
 
+// SumSolvedByUsers 汇总一批用户在日期区间内的新增过题数，没有统计行的用户不会出现在结果中。
+func (r *ojDailyStatsRepository) SumSolvedByUsers(
+	ctx context.Context,
+	platform string,
+	userIDs []uint,
+	fromDate time.Time,
+	toDate time.Time,
+) (map[uint]int, error) {
+	result := make(map[uint]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		UserID uint
+		Solved int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&entity.OJUserDailyStat{}).
+		Select("user_id, COALESCE(SUM(solved_count), 0) AS solved").
+		Where("platform = ? AND user_id IN ? AND stat_date BETWEEN ? AND ?", strings.TrimSpace(platform), userIDs, fromDate, toDate).
+		Group("user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.UserID] = row.Solved
+	}
+	return result, nil
+}
+
 // DeleteByUserPlatform 删除指定用户和平台的所有统计数据，通常用于重建最近窗口数据时清理旧数据
 func (r *ojDailyStatsRepository) DeleteByUserPlatform(
 	ctx context.Context,
@@ -0,0 +1,118 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository/interfaces"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ojProviderQuestionBankRepository struct {
+	db *gorm.DB
+}
+
+func NewOJProviderQuestionBankRepository(db *gorm.DB) interfaces.OJProviderQuestionBankRepository {
+	return &ojProviderQuestionBankRepository{db: db}
+}
+
+func (r *ojProviderQuestionBankRepository) WithTx(tx any) interfaces.OJProviderQuestionBankRepository {
+	if transaction, ok := tx.(*gorm.DB); ok {
+		return &ojProviderQuestionBankRepository{db: transaction}
+	}
+	return r
+}
+
+func (r *ojProviderQuestionBankRepository) GetByID(
+	ctx context.Context,
+	id uint,
+) (*entity.OJProviderQuestionBank, error) {
+	var q entity.OJProviderQuestionBank
+	err := r.db.WithContext(ctx).First(&q, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &q, nil
+}
+
+func (r *ojProviderQuestionBankRepository) GetByCode(
+	ctx context.Context,
+	platform string,
+	problemCode string,
+) (*entity.OJProviderQuestionBank, error) {
+	var q entity.OJProviderQuestionBank
+	err := r.db.WithContext(ctx).
+		Where("platform = ? AND problem_code = ?", platform, problemCode).
+		First(&q).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &q, nil
+}
+
+func (r *ojProviderQuestionBankRepository) ListByExactTitle(
+	ctx context.Context,
+	platform string,
+	title string,
+) ([]*entity.OJProviderQuestionBank, error) {
+	var questions []*entity.OJProviderQuestionBank
+	err := r.db.WithContext(ctx).
+		Where("platform = ? AND title = ?", platform, title).
+		Order("source_status ASC, id ASC").
+		Find(&questions).Error
+	if err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+// EnsureQuestionID 保证题目存在并返回其 ID；created 表示本次调用新建了题目，调用方据此决定是否发布题目入库事件。
+func (r *ojProviderQuestionBankRepository) EnsureQuestionID(
+	ctx context.Context,
+	question *entity.OJProviderQuestionBank,
+) (uint, bool, error) {
+	if question == nil || strings.TrimSpace(question.Platform) == "" || strings.TrimSpace(question.ProblemCode) == "" {
+		return 0, false, errors.New("invalid oj provider question")
+	}
+
+	existing, err := r.GetByCode(ctx, question.Platform, question.ProblemCode)
+	if err != nil {
+		return 0, false, err
+	}
+	if existing != nil {
+		return existing.ID, false, nil
+	}
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "platform"}, {Name: "problem_code"}},
+			DoNothing: true,
+		}).
+		Create(question)
+	if result.Error != nil {
+		return 0, false, result.Error
+	}
+	if result.RowsAffected > 0 && question.ID > 0 {
+		return question.ID, true, nil
+	}
+
+	// 并发写入时另一方先插入，回查拿到已有 ID。
+	existing, err = r.GetByCode(ctx, question.Platform, question.ProblemCode)
+	if err != nil {
+		return 0, false, err
+	}
+	if existing == nil {
+		return 0, false, nil
+	}
+	return existing.ID, false, nil
+}
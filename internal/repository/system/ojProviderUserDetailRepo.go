@@ -0,0 +1,151 @@
+package system
+
+import (
+	"context"
+	"errors"
+
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository/interfaces"
+
+	"gorm.io/gorm"
+)
+
+type ojProviderUserDetailRepository struct {
+	db *gorm.DB
+}
+
+func NewOJProviderUserDetailRepository(db *gorm.DB) interfaces.OJProviderUserDetailRepository {
+	return &ojProviderUserDetailRepository{db: db}
+}
+
+func (r *ojProviderUserDetailRepository) WithTx(tx any) interfaces.OJProviderUserDetailRepository {
+	if transaction, ok := tx.(*gorm.DB); ok {
+		return &ojProviderUserDetailRepository{db: transaction}
+	}
+	return r
+}
+
+func (r *ojProviderUserDetailRepository) GetByUserPlatform(
+	ctx context.Context,
+	userID uint,
+	platform string,
+) (*entity.OJProviderUserDetail, error) {
+	var detail entity.OJProviderUserDetail
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND platform = ?", userID, platform).
+		First(&detail).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &detail, nil
+}
+
+func (r *ojProviderUserDetailRepository) GetByUserIDs(
+	ctx context.Context,
+	platform string,
+	userIDs []uint,
+) ([]*entity.OJProviderUserDetail, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	var details []*entity.OJProviderUserDetail
+	err := r.db.WithContext(ctx).
+		Where("platform = ? AND user_id IN ?", platform, userIDs).
+		Find(&details).Error
+	if err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+func (r *ojProviderUserDetailRepository) ListByPlatform(
+	ctx context.Context,
+	platform string,
+) ([]*entity.OJProviderUserDetail, error) {
+	var details []*entity.OJProviderUserDetail
+	err := r.db.WithContext(ctx).
+		Where("platform = ?", platform).
+		Order("id ASC").
+		Find(&details).Error
+	return details, err
+}
+
+// UpsertByUserPlatform 按 (user_id, platform) 写入账号详情；已存在时覆盖资料字段与同步游标。
+func (r *ojProviderUserDetailRepository) UpsertByUserPlatform(
+	ctx context.Context,
+	detail *entity.OJProviderUserDetail,
+) (*entity.OJProviderUserDetail, error) {
+	if detail == nil {
+		return nil, errors.New("nil oj provider detail")
+	}
+
+	existing, err := r.GetByUserPlatform(ctx, detail.UserID, detail.Platform)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		if err := r.db.WithContext(ctx).Create(detail).Error; err != nil {
+			return nil, err
+		}
+		return detail, nil
+	}
+
+	existing.Identifier = detail.Identifier
+	existing.RealName = detail.RealName
+	existing.UserAvatar = detail.UserAvatar
+	existing.Rating = detail.Rating
+	existing.PassedNumber = detail.PassedNumber
+	existing.SyncCursor = detail.SyncCursor
+	existing.LastBindAt = detail.LastBindAt
+	existing.LastSyncAt = detail.LastSyncAt
+
+	if err := r.db.WithContext(ctx).Save(existing).Error; err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// UpdateSyncState 只回写同步链路会变化的字段，避免覆盖并发绑定写入的 identifier。
+func (r *ojProviderUserDetailRepository) UpdateSyncState(
+	ctx context.Context,
+	detail *entity.OJProviderUserDetail,
+) error {
+	if detail == nil || detail.ID == 0 {
+		return errors.New("invalid oj provider detail")
+	}
+	return r.db.WithContext(ctx).
+		Model(&entity.OJProviderUserDetail{}).
+		Where("id = ?", detail.ID).
+		Updates(map[string]any{
+			"real_name":     detail.RealName,
+			"user_avatar":   detail.UserAvatar,
+			"rating":        detail.Rating,
+			"passed_number": detail.PassedNumber,
+			"sync_cursor":   detail.SyncCursor,
+			"last_sync_at":  detail.LastSyncAt,
+		}).Error
+}
+
+func (r *ojProviderUserDetailRepository) DeleteByUserPlatform(
+	ctx context.Context,
+	userID uint,
+	platform string,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		detailIDs := tx.Unscoped().
+			Model(&entity.OJProviderUserDetail{}).
+			Select("id").
+			Where("user_id = ? AND platform = ?", userID, platform)
+		if err := tx.Unscoped().
+			Where("detail_id IN (?)", detailIDs).
+			Delete(&entity.OJProviderUserQuestion{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().
+			Where("user_id = ? AND platform = ?", userID, platform).
+			Delete(&entity.OJProviderUserDetail{}).Error
+	})
+}
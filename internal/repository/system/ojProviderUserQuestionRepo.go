@@ -0,0 +1,166 @@
+package system
+
+import (
+	"context"
+	"time"
+
+	"personal_assistant/internal/model/entity"
+	readmodel "personal_assistant/internal/model/readmodel"
+	"personal_assistant/internal/repository/interfaces"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ojProviderUserQuestionRepository struct {
+	db *gorm.DB
+}
+
+func NewOJProviderUserQuestionRepository(db *gorm.DB) interfaces.OJProviderUserQuestionRepository {
+	return &ojProviderUserQuestionRepository{db: db}
+}
+
+func (r *ojProviderUserQuestionRepository) WithTx(tx any) interfaces.OJProviderUserQuestionRepository {
+	if transaction, ok := tx.(*gorm.DB); ok {
+		return &ojProviderUserQuestionRepository{db: transaction}
+	}
+	return r
+}
+
+func (r *ojProviderUserQuestionRepository) GetSolvedProblemIDs(
+	ctx context.Context,
+	detailID uint,
+) (map[uint]struct{}, error) {
+	var records []entity.OJProviderUserQuestion
+	err := r.db.WithContext(ctx).
+		Select("question_id").
+		Where("detail_id = ?", detailID).
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	idSet := make(map[uint]struct{}, len(records))
+	for _, record := range records {
+		idSet[record.QuestionID] = struct{}{}
+	}
+	return idSet, nil
+}
+
+func (r *ojProviderUserQuestionRepository) GetSolvedProblemIDsByDetailIDs(
+	ctx context.Context,
+	detailIDs []uint,
+) (map[uint]map[uint]struct{}, error) {
+	result := make(map[uint]map[uint]struct{}, len(detailIDs))
+	if len(detailIDs) == 0 {
+		return result, nil
+	}
+	type row struct {
+		DetailID   uint `gorm:"column:detail_id"`
+		QuestionID uint `gorm:"column:question_id"`
+	}
+	var rows []row
+	err := r.db.WithContext(ctx).
+		Model(&entity.OJProviderUserQuestion{}).
+		Select("detail_id, question_id").
+		Where("detail_id IN ?", detailIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if _, ok := result[row.DetailID]; !ok {
+			result[row.DetailID] = make(map[uint]struct{})
+		}
+		result[row.DetailID][row.QuestionID] = struct{}{}
+	}
+	return result, nil
+}
+
+// ListSolvedAtInRange 批量读取指定账号在指定题目上、落在 [start, end] 内的首次通过时间。
+func (r *ojProviderUserQuestionRepository) ListSolvedAtInRange(
+	ctx context.Context,
+	detailIDs []uint,
+	questionIDs []uint,
+	start time.Time,
+	end time.Time,
+) ([]*readmodel.SolvedQuestionAt, error) {
+	if len(detailIDs) == 0 || len(questionIDs) == 0 || end.Before(start) {
+		return nil, nil
+	}
+	var rows []*readmodel.SolvedQuestionAt
+	err := r.db.WithContext(ctx).
+		Model(&entity.OJProviderUserQuestion{}).
+		Select("detail_id AS detail_id, question_id AS question_id, solved_at AS solved_at").
+		Where(
+			"detail_id IN ? AND question_id IN ? AND solved_at >= ? AND solved_at <= ?",
+			detailIDs,
+			questionIDs,
+			start,
+			end,
+		).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *ojProviderUserQuestionRepository) BatchCreate(
+	ctx context.Context,
+	records []*entity.OJProviderUserQuestion,
+) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "detail_id"}, {Name: "question_id"}},
+			DoNothing: true,
+		}).
+		CreateInBatches(records, 100).Error
+}
+
+func (r *ojProviderUserQuestionRepository) CountSolvedByDateRange(
+	ctx context.Context,
+	detailID uint,
+	start time.Time,
+	end time.Time,
+) ([]*readmodel.DateSolvedCount, error) {
+	if detailID == 0 || !start.Before(end) {
+		return nil, nil
+	}
+
+	var rows []*readmodel.DateSolvedCount
+	err := r.db.WithContext(ctx).
+		Model(&entity.OJProviderUserQuestion{}).
+		Select("DATE(solved_at) AS stat_date, COUNT(1) AS solved_count").
+		Where(
+			"detail_id = ? AND solved_at >= ? AND solved_at < ?",
+			detailID,
+			start,
+			end,
+		).
+		Group("DATE(solved_at)").
+		Order("stat_date ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *ojProviderUserQuestionRepository) CountPassed(
+	ctx context.Context,
+	detailID uint,
+) (int64, error) {
+	if detailID == 0 {
+		return 0, nil
+	}
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entity.OJProviderUserQuestion{}).
+		Where("detail_id = ?", detailID).
+		Count(&count).Error
+	return count, err
+}
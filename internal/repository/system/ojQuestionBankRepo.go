@@ -0,0 +1,301 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/entity"
+	readmodel "personal_assistant/internal/model/readmodel"
+	"personal_assistant/internal/repository/interfaces"
+
+	"gorm.io/gorm"
+)
+
+type ojQuestionBankRepository struct {
+	db *gorm.DB
+}
+
+func NewOJQuestionBankRepository(db *gorm.DB) interfaces.OJQuestionBankRepository {
+	return &ojQuestionBankRepository{db: db}
+}
+
+func (r *ojQuestionBankRepository) WithTx(tx any) interfaces.OJQuestionBankRepository {
+	if transaction, ok := tx.(*gorm.DB); ok {
+		return &ojQuestionBankRepository{db: transaction}
+	}
+	return r
+}
+
+func (r *ojQuestionBankRepository) Search(
+	ctx context.Context,
+	query *readmodel.OJQuestionBankQuery,
+) ([]*readmodel.OJQuestionBankItem, int64, error) {
+	if query == nil || len(query.Platforms) == 0 {
+		return nil, 0, nil
+	}
+	base := r.catalogQuery(ctx, query)
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	listQuery := base.Select("q.*")
+	if query.Keyword != "" {
+		// 标题或编码与关键词完全一致的题目优先，便于按题号或完整标题直接定位。
+		listQuery = listQuery.Order(gorm.Expr(
+			"CASE WHEN q.title = ? OR q.question_code = ? THEN 0 ELSE 1 END",
+			query.Keyword,
+			query.Keyword,
+		))
+	}
+	listQuery = listQuery.Order("q.platform ASC").Order("q.question_id ASC")
+	if query.Limit > 0 {
+		listQuery = listQuery.Offset(query.Offset).Limit(query.Limit)
+	}
+
+	var rows []*readmodel.OJQuestionBankItem
+	if err := listQuery.Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+// ojBuiltinQuestionCatalog 描述内置平台题库表到统一列的映射；扩展平台共用 oj_provider_question_banks，无需登记。
+type ojBuiltinQuestionCatalog struct {
+	table      string
+	codeExpr   string
+	difficulty string
+}
+
+var ojBuiltinQuestionCatalogs = map[string]ojBuiltinQuestionCatalog{
+	consts.OJPlatformLuogu:    {table: "luogu_question_banks", codeExpr: "pid", difficulty: "difficulty"},
+	consts.OJPlatformLeetcode: {table: "leetcode_question_banks", codeExpr: "title_slug", difficulty: "''"},
+	consts.OJPlatformLanqiao:  {table: "lanqiao_question_banks", codeExpr: "CAST(problem_id AS CHAR)", difficulty: "''"},
+}
+
+// ojQuestionLikeEscaper 转义 LIKE 通配符；转义符选用 '!'，MySQL 与 SQLite 的 ESCAPE 子句写法一致。
+var ojQuestionLikeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// catalogQuery 把所选平台的题库表归一化为 platform/question_id/question_code/title/difficulty 等列后 UNION ALL，
+// 外层统一以别名 q 计数和排序。关键词、难度、来源状态与标签过滤在各题库子查询内完成，避免先合并全部题库再过滤。
+func (r *ojQuestionBankRepository) catalogQuery(ctx context.Context, query *readmodel.OJQuestionBankQuery) *gorm.DB {
+	const columns = "source_status, source_type, last_verified_at"
+
+	parts := make([]string, 0, 4)
+	subqueries := make([]any, 0, 4)
+	providerPlatforms := make([]string, 0, len(query.Platforms))
+	for _, platform := range query.Platforms {
+		catalog, ok := ojBuiltinQuestionCatalogs[platform]
+		if !ok {
+			providerPlatforms = append(providerPlatforms, platform)
+			continue
+		}
+		sub := r.db.Session(&gorm.Session{NewDB: true}).WithContext(ctx).Table(catalog.table).
+			Select("'"+platform+"' AS platform, id AS question_id, "+catalog.codeExpr+" AS question_code, title, "+
+				catalog.difficulty+" AS difficulty, "+columns).
+			Where("deleted_at IS NULL")
+		subqueries = append(subqueries, r.filterCatalog(sub, query, catalog.table, catalog.codeExpr, catalog.difficulty, "'"+platform+"'"))
+		parts = append(parts, "?")
+	}
+	if len(providerPlatforms) > 0 {
+		sub := r.db.Session(&gorm.Session{NewDB: true}).WithContext(ctx).Table("oj_provider_question_banks").
+			Select("platform, id AS question_id, problem_code AS question_code, title, difficulty, "+columns).
+			Where("platform IN ? AND deleted_at IS NULL", providerPlatforms)
+		subqueries = append(subqueries, r.filterCatalog(sub, query, "oj_provider_question_banks", "problem_code", "difficulty", "oj_provider_question_banks.platform"))
+		parts = append(parts, "?")
+	}
+	return r.db.WithContext(ctx).Table("("+strings.Join(parts, " UNION ALL ")+") AS q", subqueries...)
+}
+
+// filterCatalog 在单个题库子查询上追加检索条件；codeExpr / difficultyExpr / platformExpr 是该题库对应的列表达式，
+// 标签子查询里的题目 ID 需用 table 限定，否则会解析为绑定表自身的 id。
+func (r *ojQuestionBankRepository) filterCatalog(
+	db *gorm.DB,
+	query *readmodel.OJQuestionBankQuery,
+	table, codeExpr, difficultyExpr, platformExpr string,
+) *gorm.DB {
+	termCond := "(title LIKE ? ESCAPE '!' OR " + codeExpr + " LIKE ? ESCAPE '!')"
+	if query.MatchAnyTerm && len(query.Terms) > 0 {
+		anyTerm := r.db.Session(&gorm.Session{NewDB: true})
+		for _, term := range query.Terms {
+			like := "%" + ojQuestionLikeEscaper.Replace(term) + "%"
+			anyTerm = anyTerm.Or(termCond, like, like)
+		}
+		db = db.Where(anyTerm)
+	} else {
+		for _, term := range query.Terms {
+			like := "%" + ojQuestionLikeEscaper.Replace(term) + "%"
+			db = db.Where(termCond, like, like)
+		}
+	}
+	if query.Difficulty != "" {
+		db = db.Where(difficultyExpr+" = ?", query.Difficulty)
+	}
+	if query.SourceStatus != nil {
+		db = db.Where("source_status = ?", *query.SourceStatus)
+	}
+	for _, tagID := range query.TagIDs {
+		db = db.Where(`EXISTS (
+			SELECT 1 FROM oj_question_tag_bindings b
+			WHERE b.platform = `+platformExpr+` AND b.question_id = `+table+`.id AND b.tag_id = ? AND b.deleted_at IS NULL
+		)`, tagID)
+	}
+	return db
+}
+
+func (r *ojQuestionBankRepository) QuestionExists(ctx context.Context, platform string, questionID uint) (bool, error) {
+	var count int64
+	db := r.db.WithContext(ctx)
+	if catalog, ok := ojBuiltinQuestionCatalogs[platform]; ok {
+		db = db.Table(catalog.table).Where("id = ? AND deleted_at IS NULL", questionID)
+	} else {
+		db = db.Model(&entity.OJProviderQuestionBank{}).Where("platform = ? AND id = ?", platform, questionID)
+	}
+	if err := db.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *ojQuestionBankRepository) CreateTag(ctx context.Context, tag *entity.OJQuestionTag) error {
+	return r.db.WithContext(ctx).Create(tag).Error
+}
+
+func (r *ojQuestionBankRepository) UpdateTag(ctx context.Context, tag *entity.OJQuestionTag) error {
+	return r.db.WithContext(ctx).Save(tag).Error
+}
+
+// GetTagByID 获取指定 ID 的标签；未找到时返回 nil。
+func (r *ojQuestionBankRepository) GetTagByID(ctx context.Context, tagID uint) (*entity.OJQuestionTag, error) {
+	var tag entity.OJQuestionTag
+	err := r.db.WithContext(ctx).First(&tag, tagID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetTagByName 按名称精确查找标签；未找到时返回 nil。
+func (r *ojQuestionBankRepository) GetTagByName(ctx context.Context, name string) (*entity.OJQuestionTag, error) {
+	var tag entity.OJQuestionTag
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&tag).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// DeleteTag 物理删除标签与关联；名称唯一索引不区分软删除，软删除会占住名称导致无法重建同名标签。
+func (r *ojQuestionBankRepository) DeleteTag(ctx context.Context, tagID uint) error {
+	if err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("tag_id = ?", tagID).
+		Delete(&entity.OJQuestionTagBinding{}).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Unscoped().Delete(&entity.OJQuestionTag{}, tagID).Error
+}
+
+func (r *ojQuestionBankRepository) ListTags(ctx context.Context) ([]*readmodel.OJQuestionTagItem, error) {
+	var rows []*readmodel.OJQuestionTagItem
+	err := r.db.WithContext(ctx).
+		Table("oj_question_tags").
+		Select(`
+			oj_question_tags.id,
+			oj_question_tags.name,
+			oj_question_tags.description,
+			(SELECT COUNT(1) FROM oj_question_tag_bindings b WHERE b.tag_id = oj_question_tags.id AND b.deleted_at IS NULL) AS question_count`).
+		Where("oj_question_tags.deleted_at IS NULL").
+		Order("oj_question_tags.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *ojQuestionBankRepository) CountTagsByIDs(ctx context.Context, tagIDs []uint) (int64, error) {
+	if len(tagIDs) == 0 {
+		return 0, nil
+	}
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&entity.OJQuestionTag{}).
+		Where("id IN ?", tagIDs).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *ojQuestionBankRepository) ReplaceQuestionTags(
+	ctx context.Context,
+	platform string,
+	questionID uint,
+	tagIDs []uint,
+	operatorID uint,
+) error {
+	if err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("platform = ? AND question_id = ?", platform, questionID).
+		Delete(&entity.OJQuestionTagBinding{}).Error; err != nil {
+		return err
+	}
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	rows := make([]*entity.OJQuestionTagBinding, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		rows = append(rows, &entity.OJQuestionTagBinding{
+			TagID:      tagID,
+			Platform:   platform,
+			QuestionID: questionID,
+			CreatedBy:  operatorID,
+		})
+	}
+	return r.db.WithContext(ctx).Create(&rows).Error
+}
+
+func (r *ojQuestionBankRepository) ListTagBindings(
+	ctx context.Context,
+	refs []readmodel.OJQuestionRef,
+) ([]*readmodel.OJQuestionTagBindingItem, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	questionIDsByPlatform := make(map[string][]uint)
+	for _, ref := range refs {
+		questionIDsByPlatform[ref.Platform] = append(questionIDsByPlatform[ref.Platform], ref.QuestionID)
+	}
+	conditions := r.db.Session(&gorm.Session{NewDB: true})
+	first := true
+	for platform, questionIDs := range questionIDsByPlatform {
+		if first {
+			conditions = conditions.Where("b.platform = ? AND b.question_id IN ?", platform, questionIDs)
+			first = false
+			continue
+		}
+		conditions = conditions.Or("b.platform = ? AND b.question_id IN ?", platform, questionIDs)
+	}
+
+	var rows []*readmodel.OJQuestionTagBindingItem
+	err := r.db.WithContext(ctx).
+		Table("oj_question_tag_bindings AS b").
+		Select("b.platform, b.question_id, b.tag_id, t.name AS tag_name").
+		Joins("JOIN oj_question_tags t ON t.id = b.tag_id AND t.deleted_at IS NULL").
+		Where("b.deleted_at IS NULL").
+		Where(conditions).
+		Order("t.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
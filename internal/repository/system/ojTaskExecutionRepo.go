@@ -72,10 +72,14 @@ func (r *ojTaskExecutionRepository) GetDispatchExecutionByID(
 	return &row, nil
 }
 
-// GetByTaskID 根据任务版本 ID 获取对应的 OJTaskExecution 实体；未找到时返回 nil。
+// GetByTaskID 根据任务版本 ID 获取最近一个周期的 OJTaskExecution 实体；未找到时返回 nil。
+// 非周期任务只有一条执行记录，周期任务取 occurrence_no 最大的一条。
 func (r *ojTaskExecutionRepository) GetByTaskID(ctx context.Context, taskID uint) (*entity.OJTaskExecution, error) {
 	var execution entity.OJTaskExecution
-	err := r.db.WithContext(ctx).Where("task_id = ?", taskID).First(&execution).Error
+	err := r.db.WithContext(ctx).
+		Where("task_id = ?", taskID).
+		Order("occurrence_no DESC").
+		First(&execution).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -90,6 +94,7 @@ func (r *ojTaskExecutionRepository) GetByTaskIDForUpdate(ctx context.Context, ta
 	err := r.db.WithContext(ctx).
 		Clauses(clause.Locking{Strength: "UPDATE"}).
 		Where("task_id = ?", taskID).
+		Order("occurrence_no DESC").
 		First(&execution).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -100,6 +105,25 @@ func (r *ojTaskExecutionRepository) GetByTaskIDForUpdate(ctx context.Context, ta
 	return &execution, nil
 }
 
+// ListByTaskID 按周期倒序列出任务版本最近 limit 个周期的执行记录。
+func (r *ojTaskExecutionRepository) ListByTaskID(
+	ctx context.Context,
+	taskID uint,
+	limit int,
+) ([]*entity.OJTaskExecution, error) {
+	query := r.db.WithContext(ctx).
+		Where("task_id = ?", taskID).
+		Order("occurrence_no DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	var rows []*entity.OJTaskExecution
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 func (r *ojTaskExecutionRepository) ListDueExecutions(
 	ctx context.Context,
 	statuses []string,
@@ -143,6 +167,48 @@ func (r *ojTaskExecutionRepository) ClaimExecution(
 	return result.RowsAffected > 0, nil
 }
 
+// ListDeadlineTrackingExecutions 列出需要截止跟踪的成功执行：
+// 未冻结且仍有未完成题目或已到截止的执行，以及已冻结但仍处于逾期跟踪窗口内、还有未完成题目的执行。
+func (r *ojTaskExecutionRepository) ListDeadlineTrackingExecutions(
+	ctx context.Context,
+	now, lateCutoff time.Time,
+	limit int,
+) ([]*entity.OJTaskExecution, error) {
+	query := r.db.WithContext(ctx).
+		Where("status = ? AND deadline_at IS NOT NULL", string(consts.OJTaskExecutionStatusSucceeded)).
+		Where(
+			"(result_frozen_at IS NULL AND (pending_item_count > 0 OR deadline_at <= ?)) OR "+
+				"(result_frozen_at IS NOT NULL AND pending_item_count > 0 AND deadline_at > ?)",
+			now,
+			lateCutoff,
+		).
+		Order("deadline_at ASC, id ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	var rows []*entity.OJTaskExecution
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ClaimDeadlineReminder 以 last_reminded_at 做条件推进，保证多实例下同一提醒窗口只发送一次。
+func (r *ojTaskExecutionRepository) ClaimDeadlineReminder(
+	ctx context.Context,
+	executionID uint,
+	remindedBefore, remindedAt time.Time,
+) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.OJTaskExecution{}).
+		Where("id = ? AND (last_reminded_at IS NULL OR last_reminded_at <= ?)", executionID, remindedBefore).
+		Update("last_reminded_at", remindedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 func (r *ojTaskExecutionRepository) BatchCreateExecutionUsers(
 	ctx context.Context,
 	rows []*entity.OJTaskExecutionUser,
@@ -172,6 +238,25 @@ func (r *ojTaskExecutionRepository) ListExecutionUsersByExecutionID(
 	return rows, nil
 }
 
+// ListExecutionUsersByExecutionIDs 批量列出多次执行的用户快照，用于跨周期对比。
+func (r *ojTaskExecutionRepository) ListExecutionUsersByExecutionIDs(
+	ctx context.Context,
+	executionIDs []uint,
+) ([]*entity.OJTaskExecutionUser, error) {
+	if len(executionIDs) == 0 {
+		return nil, nil
+	}
+	var rows []*entity.OJTaskExecutionUser
+	err := r.db.WithContext(ctx).
+		Where("execution_id IN ?", executionIDs).
+		Order("user_id ASC, execution_id ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 func (r *ojTaskExecutionRepository) BatchCreateExecutionUserOrgs(
 	ctx context.Context,
 	rows []*entity.OJTaskExecutionUserOrg,
@@ -200,6 +285,48 @@ func (r *ojTaskExecutionRepository) BatchCreateExecutionUserItems(
 	return r.db.WithContext(ctx).CreateInBatches(rows, batchSize).Error
 }
 
+// ListUserItemsByResultStatus 列出某次执行中指定结果状态的用户题目明细。
+func (r *ojTaskExecutionRepository) ListUserItemsByResultStatus(
+	ctx context.Context,
+	executionID uint,
+	resultStatus string,
+) ([]*entity.OJTaskExecutionUserItem, error) {
+	var rows []*entity.OJTaskExecutionUserItem
+	err := r.db.WithContext(ctx).
+		Where("execution_id = ? AND result_status = ?", executionID, resultStatus).
+		Order("user_id ASC, id ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// UpdateUserItemResult 只更新用户题目明细的结果列，避免整行保存覆盖快照字段。
+func (r *ojTaskExecutionRepository) UpdateUserItemResult(ctx context.Context, item *entity.OJTaskExecutionUserItem) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.OJTaskExecutionUserItem{}).
+		Where("id = ?", item.ID).
+		Updates(map[string]any{
+			"result_status": item.ResultStatus,
+			"reason":        item.Reason,
+			"completed_at":  item.CompletedAt,
+		}).Error
+}
+
+// UpdateExecutionUserProgress 只更新执行用户快照的完成统计列。
+func (r *ojTaskExecutionRepository) UpdateExecutionUserProgress(ctx context.Context, row *entity.OJTaskExecutionUser) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.OJTaskExecutionUser{}).
+		Where("id = ?", row.ID).
+		Updates(map[string]any{
+			"completed_item_count": row.CompletedItemCount,
+			"pending_item_count":   row.PendingItemCount,
+			"late_item_count":      row.LateItemCount,
+			"all_completed":        row.AllCompleted,
+		}).Error
+}
+
 func (r *ojTaskExecutionRepository) GetVisibleExecutionDetail(
 	ctx context.Context,
 	userID uint,
@@ -220,11 +347,17 @@ func (r *ojTaskExecutionRepository) GetVisibleExecutionDetail(
 			oj_tasks.mode,
 			oj_tasks.status,
 			oj_tasks.execute_at,
+			oj_tasks.recurrence_rule,
+			oj_tasks.recurrence_end_at,
+			oj_tasks.next_occurrence_at,
+			oj_tasks.deadline_at,
+			oj_tasks.deadline_minutes,
 			oj_tasks.created_by,
 			oj_tasks.updated_by,
 			oj_tasks.created_at,
 			oj_tasks.updated_at,
 			oj_task_executions.id AS execution_id,
+			oj_task_executions.occurrence_no,
 			oj_task_executions.status AS execution_status,
 			oj_task_executions.total_user_count,
 			oj_task_executions.completed_user_count,
@@ -232,6 +365,9 @@ func (r *ojTaskExecutionRepository) GetVisibleExecutionDetail(
 			oj_task_executions.total_item_count,
 			oj_task_executions.completed_item_count,
 			oj_task_executions.pending_item_count,
+			oj_task_executions.late_item_count,
+			oj_task_executions.deadline_at AS execution_deadline_at,
+			oj_task_executions.result_frozen_at,
 			(SELECT COUNT(1) FROM oj_task_orgs WHERE oj_task_orgs.task_id = oj_tasks.id AND oj_task_orgs.deleted_at IS NULL) AS org_count,
 			(SELECT COUNT(1) FROM oj_task_items WHERE oj_task_items.task_id = oj_tasks.id AND oj_task_items.deleted_at IS NULL) AS item_count,
 			oj_task_executions.trigger_type,
@@ -240,7 +376,8 @@ func (r *ojTaskExecutionRepository) GetVisibleExecutionDetail(
 			oj_task_executions.finished_at,
 			oj_task_executions.error_message,
 			oj_task_executions.requested_by`).
-		Joins("JOIN oj_task_executions ON oj_task_executions.task_id = oj_tasks.id").
+		// 周期任务一个版本对应多条执行，必须按 execution_id 精确关联。
+		Joins("JOIN oj_task_executions ON oj_task_executions.task_id = oj_tasks.id AND oj_task_executions.id = ?", executionID).
 		Where("oj_tasks.id IN (?)", base.Select("DISTINCT oj_tasks.id")).
 		Limit(1).
 		Scan(&row).Error
@@ -279,6 +416,7 @@ func (r *ojTaskExecutionRepository) ListVisibleExecutionUsers(
 		oj_task_execution_users.user_status_snapshot,
 		oj_task_execution_users.completed_item_count,
 		oj_task_execution_users.pending_item_count,
+		oj_task_execution_users.late_item_count,
 		oj_task_execution_users.all_completed`).
 		Order("oj_task_execution_users.pending_item_count DESC, oj_task_execution_users.user_id ASC")
 	if req != nil && req.Page > 0 && req.PageSize > 0 {
@@ -292,6 +430,41 @@ func (r *ojTaskExecutionRepository) ListVisibleExecutionUsers(
 	return rows, total, nil
 }
 
+// ListVisibleExecutionUsersAfter 按执行用户快照 ID 游标分批读取可见用户，供导出流式遍历。
+// 与分页列表不同，这里按主键升序而不是未完成题数排序，保证批次之间不重不漏。
+func (r *ojTaskExecutionRepository) ListVisibleExecutionUsersAfter(
+	ctx context.Context,
+	userID uint,
+	isSuperAdmin bool,
+	taskID, executionID uint,
+	req *request.OJTaskExecutionUserListReq,
+	afterExecutionUserID uint,
+	limit int,
+) ([]*readmodel.OJTaskExecutionUserListItem, error) {
+	var rows []*readmodel.OJTaskExecutionUserListItem
+	err := r.visibleExecutionUserBase(ctx, userID, isSuperAdmin, taskID, executionID, req).
+		Where("oj_task_execution_users.id > ?", afterExecutionUserID).
+		Select(`
+			DISTINCT oj_task_execution_users.id AS execution_user_id,
+			oj_task_execution_users.execution_id,
+			oj_task_execution_users.user_id,
+			oj_task_execution_users.user_uuid_snapshot,
+			oj_task_execution_users.username_snapshot,
+			oj_task_execution_users.avatar_snapshot,
+			oj_task_execution_users.user_status_snapshot,
+			oj_task_execution_users.completed_item_count,
+			oj_task_execution_users.pending_item_count,
+			oj_task_execution_users.late_item_count,
+			oj_task_execution_users.all_completed`).
+		Order("oj_task_execution_users.id ASC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 func (r *ojTaskExecutionRepository) GetVisibleExecutionUser(
 	ctx context.Context,
 	userID uint,
@@ -311,6 +484,7 @@ func (r *ojTaskExecutionRepository) GetVisibleExecutionUser(
 			oj_task_execution_users.user_status_snapshot,
 			oj_task_execution_users.completed_item_count,
 			oj_task_execution_users.pending_item_count,
+			oj_task_execution_users.late_item_count,
 			oj_task_execution_users.all_completed`).
 		Limit(1).
 		Scan(&row).Error
@@ -381,7 +555,8 @@ func (r *ojTaskExecutionRepository) ListExecutionUserItems(
 			oj_task_items.resolved_question_code,
 			oj_task_items.resolved_title_snapshot,
 			oj_task_execution_user_items.result_status,
-			oj_task_execution_user_items.reason`).
+			oj_task_execution_user_items.reason,
+			oj_task_execution_user_items.completed_at`).
 		Joins("JOIN oj_task_items ON oj_task_items.id = oj_task_execution_user_items.task_item_id").
 		Where("oj_task_execution_user_items.execution_id = ? AND oj_task_execution_user_items.user_id = ?", executionID, targetUserID).
 		Order("oj_task_items.sort_no ASC, oj_task_execution_user_items.id ASC").
@@ -392,6 +567,34 @@ func (r *ojTaskExecutionRepository) ListExecutionUserItems(
 	return rows, nil
 }
 
+func (r *ojTaskExecutionRepository) ListExecutionUserItemsByExecutionUserIDs(
+	ctx context.Context,
+	executionID uint,
+	executionUserIDs []uint,
+) ([]*readmodel.OJTaskExecutionUserItemDetail, error) {
+	if len(executionUserIDs) == 0 {
+		return nil, nil
+	}
+	var rows []*readmodel.OJTaskExecutionUserItemDetail
+	err := r.db.WithContext(ctx).
+		Table("oj_task_execution_user_items").
+		Select(`
+			execution_user_id,
+			execution_id,
+			user_id,
+			task_item_id,
+			result_status,
+			reason,
+			completed_at`).
+		Where("execution_id = ? AND execution_user_id IN ?", executionID, executionUserIDs).
+		Order("execution_user_id ASC, id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 func (r *ojTaskExecutionRepository) visibleExecutionTaskBase(
 	ctx context.Context,
 	userID uint,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"personal_assistant/internal/model/consts"
 	"personal_assistant/internal/model/dto/request"
@@ -15,6 +16,14 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// ojTaskLatestExecutionJoin 只关联任务版本最近一个周期的执行记录；
+// 周期任务一个版本对应多条执行，列表和详情统一展示最新周期。
+const ojTaskLatestExecutionJoin = `JOIN oj_task_executions ON oj_task_executions.task_id = oj_tasks.id
+	AND oj_task_executions.occurrence_no = (
+		SELECT MAX(latest_executions.occurrence_no) FROM oj_task_executions latest_executions
+		WHERE latest_executions.task_id = oj_tasks.id
+	)`
+
 type ojTaskRepository struct {
 	db *gorm.DB
 }
@@ -45,6 +54,62 @@ func (r *ojTaskRepository) UpdateRootTaskID(ctx context.Context, taskID, rootTas
 		Update("root_task_id", rootTaskID).Error
 }
 
+// UpdateStatus 只更新任务版本状态，避免整行保存覆盖并发推进的周期字段。
+func (r *ojTaskRepository) UpdateStatus(ctx context.Context, taskID uint, status string, updatedBy uint) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.OJTask{}).
+		Where("id = ?", taskID).
+		Updates(map[string]any{
+			"status":     status,
+			"updated_by": updatedBy,
+		}).Error
+}
+
+// ListDueRecurringTasks 列出下一周期已到点、仍需物化执行记录的周期任务。
+func (r *ojTaskRepository) ListDueRecurringTasks(
+	ctx context.Context,
+	before time.Time,
+	limit int,
+) ([]*entity.OJTask, error) {
+	var tasks []*entity.OJTask
+	query := r.db.WithContext(ctx).
+		Where("mode = ?", string(consts.OJTaskModeRecurring)).
+		Where("status <> ?", string(consts.OJTaskStatusDeleted)).
+		Where("next_occurrence_at IS NOT NULL AND next_occurrence_at <= ?", before).
+		Order("next_occurrence_at ASC, id ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// AdvanceRecurrence 以 next_occurrence_at 为条件推进周期，仅当前值等于 expectedNext 时生效。
+// status 为空时不修改任务状态；返回值表示本实例是否抢到该周期。
+func (r *ojTaskRepository) AdvanceRecurrence(
+	ctx context.Context,
+	taskID uint,
+	expectedNext time.Time,
+	next *time.Time,
+	status string,
+) (bool, error) {
+	updates := map[string]any{"next_occurrence_at": next}
+	if status != "" {
+		updates["status"] = status
+	}
+	result := r.db.WithContext(ctx).
+		Model(&entity.OJTask{}).
+		Where("id = ? AND next_occurrence_at = ?", taskID, expectedNext).
+		Where("status <> ?", string(consts.OJTaskStatusDeleted)).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // GetByID 获取指定 ID 的任务版本实体；未找到时返回 nil。
 func (r *ojTaskRepository) GetByID(ctx context.Context, taskID uint) (*entity.OJTask, error) {
 	var task entity.OJTask
@@ -232,6 +297,45 @@ func (r *ojTaskRepository) ListPendingIntakesByTitle(
 	return rows, nil
 }
 
+func (r *ojTaskRepository) ListPendingIntakeGroups(
+	ctx context.Context,
+	platform string,
+	offset, limit int,
+) ([]*readmodel.OJPendingIntakeGroup, int64, error) {
+	// 已删除任务的 intake 不再参与整理。
+	query := r.db.WithContext(ctx).
+		Table("oj_question_intakes AS i").
+		Joins("JOIN oj_tasks t ON t.id = i.task_id AND t.deleted_at IS NULL").
+		Where("i.deleted_at IS NULL AND i.status = ?", string(consts.OJTaskItemResolutionStatusPendingResolution))
+	if platform != "" {
+		query = query.Where("i.platform = ?", platform)
+	}
+	query = query.
+		Select(`
+			i.platform,
+			i.input_title,
+			COUNT(1) AS intake_count,
+			COUNT(DISTINCT i.task_id) AS task_count,
+			MIN(i.id) AS first_intake_id`).
+		Group("i.platform, i.input_title")
+
+	var total int64
+	if err := r.db.WithContext(ctx).Table("(?) AS g", query).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []*readmodel.OJPendingIntakeGroup
+	err := query.
+		Order("first_intake_id ASC").
+		Offset(offset).
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
 func (r *ojTaskRepository) ListVisibleTasks(
 	ctx context.Context,
 	userID uint,
@@ -306,10 +410,13 @@ func (r *ojTaskRepository) ListVisibleVersions(
 			oj_tasks.mode,
 			oj_tasks.status,
 			oj_tasks.execute_at,
+			oj_tasks.recurrence_rule,
+			oj_tasks.next_occurrence_at,
 			oj_tasks.created_at,
 			oj_task_executions.id AS execution_id,
+			oj_task_executions.occurrence_no,
 			oj_task_executions.status AS execution_status`).
-		Joins("JOIN oj_task_executions ON oj_task_executions.task_id = oj_tasks.id").
+		Joins(ojTaskLatestExecutionJoin).
 		Where("oj_tasks.id IN (?)", base.Select("DISTINCT oj_tasks.id")).
 		Order("oj_tasks.version_no DESC, oj_tasks.id DESC").
 		Scan(&rows).Error
@@ -398,11 +505,17 @@ func (r *ojTaskRepository) taskSummaryQuery(ctx context.Context) *gorm.DB {
 			oj_tasks.mode,
 			oj_tasks.status,
 			oj_tasks.execute_at,
+			oj_tasks.recurrence_rule,
+			oj_tasks.recurrence_end_at,
+			oj_tasks.next_occurrence_at,
+			oj_tasks.deadline_at,
+			oj_tasks.deadline_minutes,
 			oj_tasks.created_by,
 			oj_tasks.updated_by,
 			oj_tasks.created_at,
 			oj_tasks.updated_at,
 			oj_task_executions.id AS execution_id,
+			oj_task_executions.occurrence_no,
 			oj_task_executions.status AS execution_status,
 			oj_task_executions.total_user_count,
 			oj_task_executions.completed_user_count,
@@ -410,9 +523,12 @@ func (r *ojTaskRepository) taskSummaryQuery(ctx context.Context) *gorm.DB {
 			oj_task_executions.total_item_count,
 			oj_task_executions.completed_item_count,
 			oj_task_executions.pending_item_count,
+			oj_task_executions.late_item_count,
+			oj_task_executions.deadline_at AS execution_deadline_at,
+			oj_task_executions.result_frozen_at,
 			(SELECT COUNT(1) FROM oj_task_orgs WHERE oj_task_orgs.task_id = oj_tasks.id AND oj_task_orgs.deleted_at IS NULL) AS org_count,
 			(SELECT COUNT(1) FROM oj_task_items WHERE oj_task_items.task_id = oj_tasks.id AND oj_task_items.deleted_at IS NULL) AS item_count`).
-		Joins("JOIN oj_task_executions ON oj_task_executions.task_id = oj_tasks.id")
+		Joins(ojTaskLatestExecutionJoin)
 }
 
 func (r *ojTaskRepository) taskDetailQuery(ctx context.Context) *gorm.DB {
@@ -426,11 +542,17 @@ func (r *ojTaskRepository) taskDetailQuery(ctx context.Context) *gorm.DB {
 		oj_tasks.mode,
 		oj_tasks.status,
 		oj_tasks.execute_at,
+		oj_tasks.recurrence_rule,
+		oj_tasks.recurrence_end_at,
+		oj_tasks.next_occurrence_at,
+		oj_tasks.deadline_at,
+		oj_tasks.deadline_minutes,
 		oj_tasks.created_by,
 		oj_tasks.updated_by,
 		oj_tasks.created_at,
 		oj_tasks.updated_at,
 		oj_task_executions.id AS execution_id,
+		oj_task_executions.occurrence_no,
 		oj_task_executions.status AS execution_status,
 		oj_task_executions.total_user_count,
 		oj_task_executions.completed_user_count,
@@ -438,6 +560,9 @@ func (r *ojTaskRepository) taskDetailQuery(ctx context.Context) *gorm.DB {
 		oj_task_executions.total_item_count,
 		oj_task_executions.completed_item_count,
 		oj_task_executions.pending_item_count,
+		oj_task_executions.late_item_count,
+		oj_task_executions.deadline_at AS execution_deadline_at,
+		oj_task_executions.result_frozen_at,
 		(SELECT COUNT(1) FROM oj_task_orgs WHERE oj_task_orgs.task_id = oj_tasks.id AND oj_task_orgs.deleted_at IS NULL) AS org_count,
 		(SELECT COUNT(1) FROM oj_task_items WHERE oj_task_items.task_id = oj_tasks.id AND oj_task_items.deleted_at IS NULL) AS item_count,
 		oj_task_executions.trigger_type,
@@ -0,0 +1,162 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository/interfaces"
+
+	"gorm.io/gorm"
+)
+
+// OrgInvitationGormRepository 组织邀请仓储GORM实现
+type OrgInvitationGormRepository struct {
+	db *gorm.DB
+}
+
+// NewOrgInvitationRepository 创建组织邀请仓储实例
+func NewOrgInvitationRepository(db *gorm.DB) interfaces.OrgInvitationRepository {
+	return &OrgInvitationGormRepository{db: db}
+}
+
+// WithTx 启用事务
+func (r *OrgInvitationGormRepository) WithTx(tx any) interfaces.OrgInvitationRepository {
+	if transaction, ok := tx.(*gorm.DB); ok {
+		return &OrgInvitationGormRepository{db: transaction}
+	}
+	return r
+}
+
+// Create 写入邀请
+func (r *OrgInvitationGormRepository) Create(ctx context.Context, invitation *entity.OrgInvitation) error {
+	return r.db.WithContext(ctx).Create(invitation).Error
+}
+
+// GetByID 获取邀请
+func (r *OrgInvitationGormRepository) GetByID(ctx context.Context, id uint) (*entity.OrgInvitation, error) {
+	var invitation entity.OrgInvitation
+	if err := r.db.WithContext(ctx).First(&invitation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// GetByTokenHash 按令牌摘要获取邀请
+func (r *OrgInvitationGormRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*entity.OrgInvitation, error) {
+	var invitation entity.OrgInvitation
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&invitation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// ListByOrg 列出组织的全部邀请
+func (r *OrgInvitationGormRepository) ListByOrg(ctx context.Context, orgID uint) ([]*entity.OrgInvitation, error) {
+	var invitations []*entity.OrgInvitation
+	err := r.db.WithContext(ctx).
+		Where("org_id = ?", orgID).
+		Order("id DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+// Revoke 条件撤销邀请
+func (r *OrgInvitationGormRepository) Revoke(ctx context.Context, id, operatorID uint, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.OrgInvitation{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]any{"revoked_at": now, "revoked_by": operatorID})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ConsumeUse 条件递增使用次数，并发接受同一邀请时不会超出次数上限
+func (r *OrgInvitationGormRepository) ConsumeUse(ctx context.Context, id uint, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.OrgInvitation{}).
+		Where("id = ? AND revoked_at IS NULL AND expires_at > ? AND used_count < max_uses", id, now).
+		Update("used_count", gorm.Expr("used_count + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// CreateJoinRequest 写入加入申请
+func (r *OrgInvitationGormRepository) CreateJoinRequest(ctx context.Context, joinRequest *entity.OrgJoinRequest) error {
+	return r.db.WithContext(ctx).Create(joinRequest).Error
+}
+
+// GetJoinRequestByID 获取加入申请
+func (r *OrgInvitationGormRepository) GetJoinRequestByID(ctx context.Context, id uint) (*entity.OrgJoinRequest, error) {
+	var joinRequest entity.OrgJoinRequest
+	if err := r.db.WithContext(ctx).First(&joinRequest, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &joinRequest, nil
+}
+
+// GetPendingJoinRequest 获取待审批的加入申请
+func (r *OrgInvitationGormRepository) GetPendingJoinRequest(ctx context.Context, orgID, userID uint) (*entity.OrgJoinRequest, error) {
+	var joinRequest entity.OrgJoinRequest
+	err := r.db.WithContext(ctx).
+		Where("org_id = ? AND user_id = ? AND status = ?", orgID, userID, consts.OrgJoinRequestStatusPending).
+		First(&joinRequest).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &joinRequest, nil
+}
+
+// ListJoinRequests 列出组织的加入申请
+func (r *OrgInvitationGormRepository) ListJoinRequests(
+	ctx context.Context,
+	orgID uint,
+	status consts.OrgJoinRequestStatus,
+) ([]*entity.OrgJoinRequest, error) {
+	query := r.db.WithContext(ctx).Where("org_id = ?", orgID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var joinRequests []*entity.OrgJoinRequest
+	err := query.Order("id DESC").Find(&joinRequests).Error
+	return joinRequests, err
+}
+
+// ReviewJoinRequest 条件写入审批结果
+func (r *OrgInvitationGormRepository) ReviewJoinRequest(
+	ctx context.Context,
+	id uint,
+	status consts.OrgJoinRequestStatus,
+	reviewerID uint,
+	note string,
+	now time.Time,
+) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.OrgJoinRequest{}).
+		Where("id = ? AND status = ?", id, consts.OrgJoinRequestStatusPending).
+		Updates(map[string]any{
+			"status":      status,
+			"reviewed_by": reviewerID,
+			"reviewed_at": now,
+			"review_note": note,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
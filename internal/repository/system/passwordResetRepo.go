@@ -0,0 +1,78 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository/interfaces"
+
+	"gorm.io/gorm"
+)
+
+// PasswordResetGormRepository 找回密码验证码仓储GORM实现
+type PasswordResetGormRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetRepository 创建找回密码验证码仓储实例
+func NewPasswordResetRepository(db *gorm.DB) interfaces.PasswordResetRepository {
+	return &PasswordResetGormRepository{db: db}
+}
+
+// WithTx 启用事务
+func (r *PasswordResetGormRepository) WithTx(tx any) interfaces.PasswordResetRepository {
+	if transaction, ok := tx.(*gorm.DB); ok {
+		return &PasswordResetGormRepository{db: transaction}
+	}
+	return r
+}
+
+// Create 写入新的验证码记录
+func (r *PasswordResetGormRepository) Create(ctx context.Context, code *entity.PasswordResetCode) error {
+	return r.db.WithContext(ctx).Create(code).Error
+}
+
+// GetLatestActiveByUserID 获取用户最近一条仍可使用的验证码
+func (r *PasswordResetGormRepository) GetLatestActiveByUserID(
+	ctx context.Context,
+	userID uint,
+	now time.Time,
+) (*entity.PasswordResetCode, error) {
+	var code entity.PasswordResetCode
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND used_at IS NULL AND expires_at > ?", userID, now).
+		Order("id DESC").
+		First(&code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &code, nil
+}
+
+// ClaimAttempt 条件自增校验次数，并发请求只有前 maxAttempts 次能占用成功
+func (r *PasswordResetGormRepository) ClaimAttempt(
+	ctx context.Context,
+	id uint,
+	maxAttempts int,
+	now time.Time,
+) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.PasswordResetCode{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ? AND attempts < ?", id, now, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// InvalidateByUserID 作废用户全部未使用的验证码
+func (r *PasswordResetGormRepository) InvalidateByUserID(ctx context.Context, userID uint, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.PasswordResetCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", now).Error
+}
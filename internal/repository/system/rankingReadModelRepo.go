@@ -3,12 +3,17 @@ package system
 import (
 	"context"
 
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/entity"
 	readmodel "personal_assistant/internal/model/readmodel"
 	"personal_assistant/internal/repository/interfaces"
 
 	"gorm.io/gorm"
 )
 
+// rankingProviderUserBatchSize 是补齐扩展平台资料时单次 IN 查询的用户数上限。
+const rankingProviderUserBatchSize = 500
+
 type rankingReadModelRepository struct {
 	db *gorm.DB
 }
@@ -72,12 +77,23 @@ func (r *rankingReadModelRepository) query(
 			COALESCE(luogu_user_details.identification, '') AS luogu_identifier,
 			COALESCE(luogu_user_details.user_avatar, '') AS luogu_avatar,
 			COALESCE(luogu_user_details.passed_number, 0) AS luogu_score,
+			COALESCE(luogu_weighted_scores.luogu_weighted_score, 0) AS luogu_weighted_score,
 			COALESCE(leetcode_user_details.user_slug, '') AS leetcode_identifier,
 			COALESCE(leetcode_user_details.user_avatar, '') AS leetcode_avatar,
 			COALESCE(leetcode_user_details.total_number, 0) AS leetcode_score,
+			COALESCE(
+				leetcode_user_details.easy_number * ? +
+				leetcode_user_details.medium_number * ? +
+				leetcode_user_details.hard_number * ?,
+				0
+			) AS leetcode_weighted_score,
 			COALESCE(lanqiao_user_details.masked_phone, '') AS lanqiao_identifier,
 			'' AS lanqiao_avatar,
-			COALESCE(lanqiao_scores.lanqiao_score, 0) AS lanqiao_score`).
+			COALESCE(lanqiao_scores.lanqiao_score, 0) AS lanqiao_score`,
+			consts.OJRankingWeightLeetcodeEasy,
+			consts.OJRankingWeightLeetcodeMedium,
+			consts.OJRankingWeightLeetcodeHard,
+		).
 		Joins("LEFT JOIN orgs current_orgs ON current_orgs.id = users.current_org_id").
 		Joins("LEFT JOIN luogu_user_details ON luogu_user_details.user_id = users.id").
 		Joins("LEFT JOIN leetcode_user_details ON leetcode_user_details.user_id = users.id").
@@ -88,6 +104,19 @@ func (r *rankingReadModelRepository) query(
 			LEFT JOIN lanqiao_user_questions luq ON luq.lanqiao_user_detail_id = lud.id
 			GROUP BY lud.user_id
 		) lanqiao_scores ON lanqiao_scores.user_id = users.id`).
+		// 洛谷难度以字符串 "0"~"7" 存储，0 表示暂无评定，按最低分计。
+		Joins(`LEFT JOIN (
+			SELECT lgd.user_id AS user_id,
+				SUM(CASE WHEN CAST(lqb.difficulty AS SIGNED) > ? THEN CAST(lqb.difficulty AS SIGNED) ELSE ? END) AS luogu_weighted_score
+			FROM luogu_user_details lgd
+			JOIN luogu_user_questions lgq ON lgq.luogu_user_detail_id = lgd.id AND lgq.deleted_at IS NULL
+			JOIN luogu_question_banks lqb ON lqb.id = lgq.luogu_question_id
+			WHERE lgd.deleted_at IS NULL
+			GROUP BY lgd.user_id
+		) luogu_weighted_scores ON luogu_weighted_scores.user_id = users.id`,
+			consts.OJRankingWeightLuoguUnrated,
+			consts.OJRankingWeightLuoguUnrated,
+		).
 		Where("users.deleted_at IS NULL").
 		Order("users.id ASC")
 	if apply != nil {
@@ -98,5 +127,51 @@ func (r *rankingReadModelRepository) query(
 	if err := query.Scan(&items).Error; err != nil {
 		return nil, err
 	}
+	if err := r.attachProviderProfiles(ctx, items); err != nil {
+		return nil, err
+	}
 	return items, nil
 }
+
+// attachProviderProfiles 按已登记的扩展平台补齐读模型的 Providers，
+// 扩展平台共用 oj_provider_user_details，新增平台无需改动主查询。
+func (r *rankingReadModelRepository) attachProviderProfiles(ctx context.Context, items []*readmodel.Ranking) error {
+	if len(items) == 0 || len(consts.OJProviderPlatforms) == 0 {
+		return nil
+	}
+	byUser := make(map[uint]*readmodel.Ranking, len(items))
+	userIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		byUser[item.UserID] = item
+		userIDs = append(userIDs, item.UserID)
+	}
+
+	for start := 0; start < len(userIDs); start += rankingProviderUserBatchSize {
+		end := min(start+rankingProviderUserBatchSize, len(userIDs))
+		var details []*entity.OJProviderUserDetail
+		if err := r.db.WithContext(ctx).
+			Select("user_id", "platform", "identifier", "user_avatar", "passed_number").
+			Where("platform IN ? AND user_id IN ?", consts.OJProviderPlatforms, userIDs[start:end]).
+			Find(&details).Error; err != nil {
+			return err
+		}
+		for _, detail := range details {
+			item := byUser[detail.UserID]
+			if item == nil {
+				continue
+			}
+			if item.Providers == nil {
+				item.Providers = make(map[string]readmodel.RankingProviderProfile, len(consts.OJProviderPlatforms))
+			}
+			item.Providers[detail.Platform] = readmodel.RankingProviderProfile{
+				Identifier: detail.Identifier,
+				Avatar:     detail.UserAvatar,
+				Score:      detail.PassedNumber,
+			}
+		}
+	}
+	return nil
+}
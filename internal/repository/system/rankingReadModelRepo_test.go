@@ -0,0 +1,72 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/entity"
+)
+
+func TestRankingReadModelRepositoryAttachesProviderProfiles(t *testing.T) {
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&entity.User{},
+		&entity.Org{},
+		&entity.LuoguUserDetail{},
+		&entity.LuoguUserQuestion{},
+		&entity.LuoguQuestionBank{},
+		&entity.LeetcodeUserDetail{},
+		&entity.LanqiaoUserDetail{},
+		&entity.LanqiaoUserQuestion{},
+		&entity.OJProviderUserDetail{},
+	); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+
+	alice := &entity.User{UUID: uuid.Must(uuid.NewV4()), Username: "alice", Phone: "13800000001", Status: consts.UserStatusActive}
+	bob := &entity.User{UUID: uuid.Must(uuid.NewV4()), Username: "bob", Phone: "13800000002", Status: consts.UserStatusActive}
+	for _, user := range []*entity.User{alice, bob} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+	details := []*entity.OJProviderUserDetail{
+		{Platform: consts.OJPlatformCodeforces, Identifier: "tourist", UserAvatar: "cf.png", PassedNumber: 30, UserID: alice.ID},
+		{Platform: consts.OJPlatformAtcoder, Identifier: "tourist_ac", PassedNumber: 12, UserID: alice.ID},
+		// 未登记的平台不应进入读模型。
+		{Platform: "unknown", Identifier: "ghost", PassedNumber: 99, UserID: bob.ID},
+	}
+	for _, detail := range details {
+		if err := db.Create(detail).Error; err != nil {
+			t.Fatalf("create provider detail: %v", err)
+		}
+	}
+
+	items, err := NewRankingReadModelRepository(db).GetByUserIDs(context.Background(), []uint{alice.ID, bob.ID})
+	if err != nil {
+		t.Fatalf("GetByUserIDs() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("len(items) = %d, want 2", len(items))
+	}
+	cf := items[0].Providers[consts.OJPlatformCodeforces]
+	if cf.Identifier != "tourist" || cf.Avatar != "cf.png" || cf.Score != 30 {
+		t.Fatalf("codeforces profile = %+v", cf)
+	}
+	if ac := items[0].Providers[consts.OJPlatformAtcoder]; ac.Identifier != "tourist_ac" || ac.Score != 12 {
+		t.Fatalf("atcoder profile = %+v", ac)
+	}
+	if len(items[1].Providers) != 0 {
+		t.Fatalf("bob providers = %+v, want none", items[1].Providers)
+	}
+}
@@ -409,6 +409,18 @@ func (r *roleRepository) GetUserGlobalRoles(ctx context.Context, userID uint) ([
 	return roles, err
 }
 
+// HasRoleInAnyOrg 判断用户是否在任一组织（org_id > 0）中持有指定角色
+func (r *roleRepository) HasRoleInAnyOrg(ctx context.Context, userID uint, roleCode string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Table("roles").
+		Joins("JOIN user_org_roles ON roles.id = user_org_roles.role_id").
+		Where("user_org_roles.user_id = ? AND user_org_roles.org_id > 0 AND roles.code = ? AND roles.status = 1 AND roles.deleted_at IS NULL",
+			userID, roleCode).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // ClearRoleUserRelations 清空角色的所有用户关联
 func (r *roleRepository) ClearRoleUserRelations(ctx context.Context, roleID uint) error {
 	return r.db.WithContext(ctx).Exec("DELETE FROM user_org_roles WHERE role_id = ?", roleID).Error
@@ -11,14 +11,20 @@ import (
 type Supplier interface {
 	GetAIRepository() interfaces.AIRepository
 	GetAIMemoryRepository() interfaces.AIMemoryRepository
+	GetAIMemoryVectorRepository() interfaces.AIMemoryVectorRepository
 	GetUserRepository() interfaces.UserRepository
 	GetJWTRepository() interfaces.JWTRepository
+	GetPasswordResetRepository() interfaces.PasswordResetRepository
+	GetUserSessionRepository() interfaces.UserSessionRepository
+	GetTwoFactorRepository() interfaces.TwoFactorRepository
+	GetOAuthStateRepository() interfaces.OAuthStateRepository
 	GetRoleRepository() interfaces.RoleRepository
 	GetCapabilityRepository() interfaces.CapabilityRepository
 	GetMenuRepository() interfaces.MenuRepository
 	GetAPIRepository() interfaces.APIRepository
 	GetOrgRepository() interfaces.OrgRepository
 	GetOrgMemberRepository() interfaces.OrgMemberRepository
+	GetOrgInvitationRepository() interfaces.OrgInvitationRepository
 	GetLeetcodeUserDetailRepository() interfaces.LeetcodeUserDetailRepository
 	GetLuoguUserDetailRepository() interfaces.LuoguUserDetailRepository
 	GetLanqiaoUserDetailRepository() interfaces.LanqiaoUserDetailRepository
@@ -28,8 +34,13 @@ type Supplier interface {
 	GetLeetcodeUserQuestionRepository() interfaces.LeetcodeUserQuestionRepository
 	GetLuoguUserQuestionRepository() interfaces.LuoguUserQuestionRepository
 	GetLanqiaoUserQuestionRepository() interfaces.LanqiaoUserQuestionRepository
+	GetOJProviderUserDetailRepository() interfaces.OJProviderUserDetailRepository
+	GetOJProviderQuestionBankRepository() interfaces.OJProviderQuestionBankRepository
+	GetOJProviderUserQuestionRepository() interfaces.OJProviderUserQuestionRepository
 	GetOJTaskRepository() interfaces.OJTaskRepository
 	GetOJTaskExecutionRepository() interfaces.OJTaskExecutionRepository
+	GetOJContestRepository() interfaces.OJContestRepository
+	GetOJQuestionBankRepository() interfaces.OJQuestionBankRepository
 	GetOJDailyStatsRepository() interfaces.OJDailyStatsRepository
 	GetOutboxRepository() interfaces.OutboxRepository
 	GetRankingReadModelRepository() interfaces.RankingReadModelRepository
@@ -44,14 +55,20 @@ func SetUp(factoryConfig *adapter.FactoryConfig) Supplier {
 	var gormDB *gorm.DB
 	var aiRepo interfaces.AIRepository
 	var aiMemoryRepo interfaces.AIMemoryRepository
+	var aiMemoryVectorRepo interfaces.AIMemoryVectorRepository
 	var userRepo interfaces.UserRepository
 	var jwtRepo interfaces.JWTRepository
+	var passwordResetRepo interfaces.PasswordResetRepository
+	var userSessionRepo interfaces.UserSessionRepository
+	var twoFactorRepo interfaces.TwoFactorRepository
+	var oauthStateRepo interfaces.OAuthStateRepository
 	var roleRepo interfaces.RoleRepository
 	var capabilityRepo interfaces.CapabilityRepository
 	var menuRepo interfaces.MenuRepository
 	var apiRepo interfaces.APIRepository
 	var orgRepo interfaces.OrgRepository
 	var orgMemberRepo interfaces.OrgMemberRepository
+	var orgInvitationRepo interfaces.OrgInvitationRepository
 	var leetcodeUserDetailRepo interfaces.LeetcodeUserDetailRepository
 	var luoguUserDetailRepo interfaces.LuoguUserDetailRepository
 	var lanqiaoUserDetailRepo interfaces.LanqiaoUserDetailRepository
@@ -61,8 +78,13 @@ func SetUp(factoryConfig *adapter.FactoryConfig) Supplier {
 	var leetcodeUserQuestionRepo interfaces.LeetcodeUserQuestionRepository
 	var luoguUserQuestionRepo interfaces.LuoguUserQuestionRepository
 	var lanqiaoUserQuestionRepo interfaces.LanqiaoUserQuestionRepository
+	var ojProviderUserDetailRepo interfaces.OJProviderUserDetailRepository
+	var ojProviderQuestionBankRepo interfaces.OJProviderQuestionBankRepository
+	var ojProviderUserQuestionRepo interfaces.OJProviderUserQuestionRepository
 	var ojTaskRepo interfaces.OJTaskRepository
 	var ojTaskExecutionRepo interfaces.OJTaskExecutionRepository
+	var ojContestRepo interfaces.OJContestRepository
+	var ojQuestionBankRepo interfaces.OJQuestionBankRepository
 	var ojDailyStatsRepo interfaces.OJDailyStatsRepository
 	var outboxRepo interfaces.OutboxRepository
 	var rankingReadModelRepo interfaces.RankingReadModelRepository
@@ -77,14 +99,20 @@ func SetUp(factoryConfig *adapter.FactoryConfig) Supplier {
 			gormDB = db
 			aiRepo = NewAIRepository(db)
 			aiMemoryRepo = NewAIMemoryRepository(db)
+			aiMemoryVectorRepo = NewAIMemoryVectorRepository(db)
 			userRepo = NewUserRepository(db)
 			jwtRepo = NewJwtRepository(db)
+			passwordResetRepo = NewPasswordResetRepository(db)
+			userSessionRepo = NewUserSessionRepository(db)
+			twoFactorRepo = NewTwoFactorRepository(db)
+			oauthStateRepo = NewOAuthStateRepository(db)
 			roleRepo = NewRoleRepository(db)
 			capabilityRepo = NewCapabilityRepository(db)
 			menuRepo = NewMenuRepository(db)
 			apiRepo = NewAPIRepository(db)
 			orgRepo = NewOrgRepository(db)
 			orgMemberRepo = NewOrgMemberRepository(db)
+			orgInvitationRepo = NewOrgInvitationRepository(db)
 			leetcodeUserDetailRepo = NewLeetcodeUserDetailRepository(db)
 			luoguUserDetailRepo = NewLuoguUserDetailRepository(db)
 			lanqiaoUserDetailRepo = NewLanqiaoUserDetailRepository(db)
@@ -94,8 +122,13 @@ func SetUp(factoryConfig *adapter.FactoryConfig) Supplier {
 			leetcodeUserQuestionRepo = NewLeetcodeUserQuestionRepository(db)
 			luoguUserQuestionRepo = NewLuoguUserQuestionRepository(db)
 			lanqiaoUserQuestionRepo = NewLanqiaoUserQuestionRepository(db)
+			ojProviderUserDetailRepo = NewOJProviderUserDetailRepository(db)
+			ojProviderQuestionBankRepo = NewOJProviderQuestionBankRepository(db)
+			ojProviderUserQuestionRepo = NewOJProviderUserQuestionRepository(db)
 			ojTaskRepo = NewOJTaskRepository(db)
 			ojTaskExecutionRepo = NewOJTaskExecutionRepository(db)
+			ojContestRepo = NewOJContestRepository(db)
+			ojQuestionBankRepo = NewOJQuestionBankRepository(db)
 			ojDailyStatsRepo = NewOJDailyStatsRepository(db)
 			outboxRepo = NewOutboxRepository(db)
 			rankingReadModelRepo = NewRankingReadModelRepository(db)
@@ -116,14 +149,20 @@ func SetUp(factoryConfig *adapter.FactoryConfig) Supplier {
 			gormDB = db
 			aiRepo = NewAIRepository(db)
 			aiMemoryRepo = NewAIMemoryRepository(db)
+			aiMemoryVectorRepo = NewAIMemoryVectorRepository(db)
 			userRepo = NewUserRepository(db)
 			jwtRepo = NewJwtRepository(db)
+			passwordResetRepo = NewPasswordResetRepository(db)
+			userSessionRepo = NewUserSessionRepository(db)
+			twoFactorRepo = NewTwoFactorRepository(db)
+			oauthStateRepo = NewOAuthStateRepository(db)
 			roleRepo = NewRoleRepository(db)
 			capabilityRepo = NewCapabilityRepository(db)
 			menuRepo = NewMenuRepository(db)
 			apiRepo = NewAPIRepository(db)
 			orgRepo = NewOrgRepository(db)
 			orgMemberRepo = NewOrgMemberRepository(db)
+			orgInvitationRepo = NewOrgInvitationRepository(db)
 			leetcodeUserDetailRepo = NewLeetcodeUserDetailRepository(db)
 			luoguUserDetailRepo = NewLuoguUserDetailRepository(db)
 			lanqiaoUserDetailRepo = NewLanqiaoUserDetailRepository(db)
@@ -133,8 +172,13 @@ func SetUp(factoryConfig *adapter.FactoryConfig) Supplier {
 			leetcodeUserQuestionRepo = NewLeetcodeUserQuestionRepository(db)
 			luoguUserQuestionRepo = NewLuoguUserQuestionRepository(db)
 			lanqiaoUserQuestionRepo = NewLanqiaoUserQuestionRepository(db)
+			ojProviderUserDetailRepo = NewOJProviderUserDetailRepository(db)
+			ojProviderQuestionBankRepo = NewOJProviderQuestionBankRepository(db)
+			ojProviderUserQuestionRepo = NewOJProviderUserQuestionRepository(db)
 			ojTaskRepo = NewOJTaskRepository(db)
 			ojTaskExecutionRepo = NewOJTaskExecutionRepository(db)
+			ojContestRepo = NewOJContestRepository(db)
+			ojQuestionBankRepo = NewOJQuestionBankRepository(db)
 			ojDailyStatsRepo = NewOJDailyStatsRepository(db)
 			outboxRepo = NewOutboxRepository(db)
 			rankingReadModelRepo = NewRankingReadModelRepository(db)
@@ -145,34 +189,45 @@ func SetUp(factoryConfig *adapter.FactoryConfig) Supplier {
 		}
 	}
 	return &RepositorySupplier{
-		db:                             gormDB,
-		aiRepository:                   aiRepo,
-		aiMemoryRepository:             aiMemoryRepo,
-		userRepository:                 userRepo,
-		jwtRepository:                  jwtRepo,
-		roleRepository:                 roleRepo,
-		capabilityRepository:           capabilityRepo,
-		menuRepository:                 menuRepo,
-		apiRepository:                  apiRepo,
-		orgRepository:                  orgRepo,
-		orgMemberRepository:            orgMemberRepo,
-		leetcodeUserDetailRepository:   leetcodeUserDetailRepo,
-		luoguUserDetailRepository:      luoguUserDetailRepo,
-		lanqiaoUserDetailRepository:    lanqiaoUserDetailRepo,
-		leetcodeQuestionBankRepository: leetcodeQuestionBankRepo,
-		luoguQuestionBankRepository:    luoguQuestionBankRepo,
-		lanqiaoQuestionBankRepository:  lanqiaoQuestionBankRepo,
-		leetcodeUserQuestionRepository: leetcodeUserQuestionRepo,
-		luoguUserQuestionRepository:    luoguUserQuestionRepo,
-		lanqiaoUserQuestionRepository:  lanqiaoUserQuestionRepo,
-		ojTaskRepository:               ojTaskRepo,
-		ojTaskExecutionRepository:      ojTaskExecutionRepo,
-		ojDailyStatsRepository:         ojDailyStatsRepo,
-		outboxRepository:               outboxRepo,
-		rankingReadModelRepository:     rankingReadModelRepo,
-		imageRepository:                imageRepo,
-		observabilityMetricRepository:  observabilityMetricRepo,
-		observabilityTraceRepository:   observabilityTraceRepo,
-		observabilityRuntimeRepository: observabilityRuntimeRepo,
+		db:                               gormDB,
+		aiRepository:                     aiRepo,
+		aiMemoryRepository:               aiMemoryRepo,
+		aiMemoryVectorRepository:         aiMemoryVectorRepo,
+		userRepository:                   userRepo,
+		jwtRepository:                    jwtRepo,
+		passwordResetRepository:          passwordResetRepo,
+		userSessionRepository:            userSessionRepo,
+		twoFactorRepository:              twoFactorRepo,
+		oauthStateRepository:             oauthStateRepo,
+		roleRepository:                   roleRepo,
+		capabilityRepository:             capabilityRepo,
+		menuRepository:                   menuRepo,
+		apiRepository:                    apiRepo,
+		orgRepository:                    orgRepo,
+		orgMemberRepository:              orgMemberRepo,
+		orgInvitationRepository:          orgInvitationRepo,
+		leetcodeUserDetailRepository:     leetcodeUserDetailRepo,
+		luoguUserDetailRepository:        luoguUserDetailRepo,
+		lanqiaoUserDetailRepository:      lanqiaoUserDetailRepo,
+		leetcodeQuestionBankRepository:   leetcodeQuestionBankRepo,
+		luoguQuestionBankRepository:      luoguQuestionBankRepo,
+		lanqiaoQuestionBankRepository:    lanqiaoQuestionBankRepo,
+		leetcodeUserQuestionRepository:   leetcodeUserQuestionRepo,
+		luoguUserQuestionRepository:      luoguUserQuestionRepo,
+		lanqiaoUserQuestionRepository:    lanqiaoUserQuestionRepo,
+		ojProviderUserDetailRepository:   ojProviderUserDetailRepo,
+		ojProviderQuestionBankRepository: ojProviderQuestionBankRepo,
+		ojProviderUserQuestionRepository: ojProviderUserQuestionRepo,
+		ojTaskRepository:                 ojTaskRepo,
+		ojTaskExecutionRepository:        ojTaskExecutionRepo,
+		ojContestRepository:              ojContestRepo,
+		ojQuestionBankRepository:         ojQuestionBankRepo,
+		ojDailyStatsRepository:           ojDailyStatsRepo,
+		outboxRepository:                 outboxRepo,
+		rankingReadModelRepository:       rankingReadModelRepo,
+		imageRepository:                  imageRepo,
+		observabilityMetricRepository:    observabilityMetricRepo,
+		observabilityTraceRepository:     observabilityTraceRepo,
+		observabilityRuntimeRepository:   observabilityRuntimeRepo,
 	}
 }
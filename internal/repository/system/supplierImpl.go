@@ -11,36 +11,47 @@ import (
 
 // RepositorySupplier 用于集中提供当前模块依赖对象。
 type RepositorySupplier struct {
-	db                   *gorm.DB
-	aiRepository         interfaces.AIRepository
-	aiMemoryRepository   interfaces.AIMemoryRepository
-	userRepository       interfaces.UserRepository
-	jwtRepository        interfaces.JWTRepository
-	roleRepository       interfaces.RoleRepository
-	capabilityRepository interfaces.CapabilityRepository
-	menuRepository       interfaces.MenuRepository
-	apiRepository        interfaces.APIRepository
-	orgRepository        interfaces.OrgRepository
-	orgMemberRepository  interfaces.OrgMemberRepository
-
-	leetcodeUserDetailRepository   interfaces.LeetcodeUserDetailRepository
-	luoguUserDetailRepository      interfaces.LuoguUserDetailRepository
-	lanqiaoUserDetailRepository    interfaces.LanqiaoUserDetailRepository
-	leetcodeQuestionBankRepository interfaces.LeetcodeQuestionBankRepository
-	luoguQuestionBankRepository    interfaces.LuoguQuestionBankRepository
-	lanqiaoQuestionBankRepository  interfaces.LanqiaoQuestionBankRepository
-	leetcodeUserQuestionRepository interfaces.LeetcodeUserQuestionRepository
-	luoguUserQuestionRepository    interfaces.LuoguUserQuestionRepository
-	lanqiaoUserQuestionRepository  interfaces.LanqiaoUserQuestionRepository
-	ojTaskRepository               interfaces.OJTaskRepository
-	ojTaskExecutionRepository      interfaces.OJTaskExecutionRepository
-	ojDailyStatsRepository         interfaces.OJDailyStatsRepository
-	outboxRepository               interfaces.OutboxRepository
-	rankingReadModelRepository     interfaces.RankingReadModelRepository
-	imageRepository                interfaces.ImageRepository
-	observabilityMetricRepository  interfaces.ObservabilityMetricRepository
-	observabilityTraceRepository   interfaces.ObservabilityTraceRepository
-	observabilityRuntimeRepository interfaces.ObservabilityRuntimeRepository
+	db                       *gorm.DB
+	aiRepository             interfaces.AIRepository
+	aiMemoryRepository       interfaces.AIMemoryRepository
+	aiMemoryVectorRepository interfaces.AIMemoryVectorRepository
+	userRepository           interfaces.UserRepository
+	jwtRepository            interfaces.JWTRepository
+	passwordResetRepository  interfaces.PasswordResetRepository
+	userSessionRepository    interfaces.UserSessionRepository
+	twoFactorRepository      interfaces.TwoFactorRepository
+	oauthStateRepository     interfaces.OAuthStateRepository
+	roleRepository           interfaces.RoleRepository
+	capabilityRepository     interfaces.CapabilityRepository
+	menuRepository           interfaces.MenuRepository
+	apiRepository            interfaces.APIRepository
+	orgRepository            interfaces.OrgRepository
+	orgMemberRepository      interfaces.OrgMemberRepository
+	orgInvitationRepository  interfaces.OrgInvitationRepository
+
+	leetcodeUserDetailRepository     interfaces.LeetcodeUserDetailRepository
+	luoguUserDetailRepository        interfaces.LuoguUserDetailRepository
+	lanqiaoUserDetailRepository      interfaces.LanqiaoUserDetailRepository
+	leetcodeQuestionBankRepository   interfaces.LeetcodeQuestionBankRepository
+	luoguQuestionBankRepository      interfaces.LuoguQuestionBankRepository
+	lanqiaoQuestionBankRepository    interfaces.LanqiaoQuestionBankRepository
+	leetcodeUserQuestionRepository   interfaces.LeetcodeUserQuestionRepository
+	luoguUserQuestionRepository      interfaces.LuoguUserQuestionRepository
+	lanqiaoUserQuestionRepository    interfaces.LanqiaoUserQuestionRepository
+	ojProviderUserDetailRepository   interfaces.OJProviderUserDetailRepository
+	ojProviderQuestionBankRepository interfaces.OJProviderQuestionBankRepository
+	ojProviderUserQuestionRepository interfaces.OJProviderUserQuestionRepository
+	ojTaskRepository                 interfaces.OJTaskRepository
+	ojTaskExecutionRepository        interfaces.OJTaskExecutionRepository
+	ojContestRepository              interfaces.OJContestRepository
+	ojQuestionBankRepository         interfaces.OJQuestionBankRepository
+	ojDailyStatsRepository           interfaces.OJDailyStatsRepository
+	outboxRepository                 interfaces.OutboxRepository
+	rankingReadModelRepository       interfaces.RankingReadModelRepository
+	imageRepository                  interfaces.ImageRepository
+	observabilityMetricRepository    interfaces.ObservabilityMetricRepository
+	observabilityTraceRepository     interfaces.ObservabilityTraceRepository
+	observabilityRuntimeRepository   interfaces.ObservabilityRuntimeRepository
 }
 
 // GetAIRepository 用于获取当前场景需要的对象或数据。
@@ -65,6 +76,11 @@ func (r *RepositorySupplier) GetAIMemoryRepository() interfaces.AIMemoryReposito
 	return r.aiMemoryRepository
 }
 
+// GetAIMemoryVectorRepository 返回本地向量库仓储依赖。
+func (r *RepositorySupplier) GetAIMemoryVectorRepository() interfaces.AIMemoryVectorRepository {
+	return r.aiMemoryVectorRepository
+}
+
 // GetUserRepository 用于获取当前场景需要的对象或数据。
 // 参数：
 //   - 无。
@@ -99,6 +115,26 @@ func (r *RepositorySupplier) GetJWTRepository() interfaces.JWTRepository {
 	return r.jwtRepository
 }
 
+// GetPasswordResetRepository 返回找回密码验证码仓储。
+func (r *RepositorySupplier) GetPasswordResetRepository() interfaces.PasswordResetRepository {
+	return r.passwordResetRepository
+}
+
+// GetUserSessionRepository 返回登录会话仓储。
+func (r *RepositorySupplier) GetUserSessionRepository() interfaces.UserSessionRepository {
+	return r.userSessionRepository
+}
+
+// GetTwoFactorRepository 返回二次验证仓储。
+func (r *RepositorySupplier) GetTwoFactorRepository() interfaces.TwoFactorRepository {
+	return r.twoFactorRepository
+}
+
+// GetOAuthStateRepository 返回第三方登录授权请求仓储。
+func (r *RepositorySupplier) GetOAuthStateRepository() interfaces.OAuthStateRepository {
+	return r.oauthStateRepository
+}
+
 // GetRoleRepository 用于获取当前场景需要的对象或数据。
 // 参数：
 //   - 无。
@@ -201,6 +237,11 @@ func (r *RepositorySupplier) GetOrgMemberRepository() interfaces.OrgMemberReposi
 	return r.orgMemberRepository
 }
 
+// GetOrgInvitationRepository 返回组织邀请与加入申请仓储。
+func (r *RepositorySupplier) GetOrgInvitationRepository() interfaces.OrgInvitationRepository {
+	return r.orgInvitationRepository
+}
+
 // GetLeetcodeUserDetailRepository 用于获取当前场景需要的对象或数据。
 // 参数：
 //   - 无。
@@ -354,6 +395,21 @@ func (r *RepositorySupplier) GetLanqiaoUserQuestionRepository() interfaces.Lanqi
 	return r.lanqiaoUserQuestionRepository
 }
 
+// GetOJProviderUserDetailRepository 返回可插拔 OJ 平台账号详情仓储。
+func (r *RepositorySupplier) GetOJProviderUserDetailRepository() interfaces.OJProviderUserDetailRepository {
+	return r.ojProviderUserDetailRepository
+}
+
+// GetOJProviderQuestionBankRepository 返回可插拔 OJ 平台题库仓储。
+func (r *RepositorySupplier) GetOJProviderQuestionBankRepository() interfaces.OJProviderQuestionBankRepository {
+	return r.ojProviderQuestionBankRepository
+}
+
+// GetOJProviderUserQuestionRepository 返回可插拔 OJ 平台过题关系仓储。
+func (r *RepositorySupplier) GetOJProviderUserQuestionRepository() interfaces.OJProviderUserQuestionRepository {
+	return r.ojProviderUserQuestionRepository
+}
+
 // GetOJTaskRepository 用于获取当前场景需要的对象或数据。
 // 参数：
 //   - 无。
@@ -388,6 +444,16 @@ func (r *RepositorySupplier) GetOJTaskExecutionRepository() interfaces.OJTaskExe
 	return r.ojTaskExecutionRepository
 }
 
+// GetOJContestRepository 返回 OJ 比赛与冻结榜单仓储。
+func (r *RepositorySupplier) GetOJContestRepository() interfaces.OJContestRepository {
+	return r.ojContestRepository
+}
+
+// GetOJQuestionBankRepository 返回跨平台题库目录与自定义标签仓储。
+func (r *RepositorySupplier) GetOJQuestionBankRepository() interfaces.OJQuestionBankRepository {
+	return r.ojQuestionBankRepository
+}
+
 // GetOutboxRepository 用于获取当前场景需要的对象或数据。
 // 参数：
 //   - 无。
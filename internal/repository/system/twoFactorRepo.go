@@ -0,0 +1,157 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository/interfaces"
+
+	"gorm.io/gorm"
+)
+
+// TwoFactorGormRepository 二次验证仓储GORM实现
+type TwoFactorGormRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository 创建二次验证仓储实例
+func NewTwoFactorRepository(db *gorm.DB) interfaces.TwoFactorRepository {
+	return &TwoFactorGormRepository{db: db}
+}
+
+// WithTx 启用事务
+func (r *TwoFactorGormRepository) WithTx(tx any) interfaces.TwoFactorRepository {
+	if transaction, ok := tx.(*gorm.DB); ok {
+		return &TwoFactorGormRepository{db: transaction}
+	}
+	return r
+}
+
+// GetByUserID 获取用户的二次验证配置
+func (r *TwoFactorGormRepository) GetByUserID(ctx context.Context, userID uint) (*entity.UserTwoFactor, error) {
+	var setting entity.UserTwoFactor
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&setting).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// Save 新建或更新二次验证配置
+func (r *TwoFactorGormRepository) Save(ctx context.Context, setting *entity.UserTwoFactor) error {
+	return r.db.WithContext(ctx).Save(setting).Error
+}
+
+// DeleteByUserID 物理删除二次验证配置与恢复码，user_id 唯一索引不允许残留软删除记录
+func (r *TwoFactorGormRepository) DeleteByUserID(ctx context.Context, userID uint) error {
+	db := r.db.WithContext(ctx).Unscoped()
+	if err := db.Where("user_id = ?", userID).Delete(&entity.UserRecoveryCode{}).Error; err != nil {
+		return err
+	}
+	return db.Where("user_id = ?", userID).Delete(&entity.UserTwoFactor{}).Error
+}
+
+// AdvanceLastUsedStep 条件更新最近使用的时间步
+func (r *TwoFactorGormRepository) AdvanceLastUsedStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReplaceRecoveryCodes 替换用户的恢复码
+func (r *TwoFactorGormRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Unscoped().Where("user_id = ?", userID).Delete(&entity.UserRecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if len(codeHashes) == 0 {
+		return nil
+	}
+	codes := make([]*entity.UserRecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, &entity.UserRecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	return db.Create(&codes).Error
+}
+
+// ConsumeRecoveryCode 使用恢复码，条件更新保证并发下只会成功一次
+func (r *TwoFactorGormRepository) ConsumeRecoveryCode(
+	ctx context.Context,
+	userID uint,
+	codeHash string,
+	now time.Time,
+) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CountUnusedRecoveryCodes 统计剩余恢复码
+func (r *TwoFactorGormRepository) CountUnusedRecoveryCodes(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.UserRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// CreateChallenge 写入登录挑战
+func (r *TwoFactorGormRepository) CreateChallenge(ctx context.Context, challenge *entity.TwoFactorChallenge) error {
+	return r.db.WithContext(ctx).Create(challenge).Error
+}
+
+// GetChallengeByTokenHash 按令牌摘要获取登录挑战
+func (r *TwoFactorGormRepository) GetChallengeByTokenHash(
+	ctx context.Context,
+	tokenHash string,
+) (*entity.TwoFactorChallenge, error) {
+	var challenge entity.TwoFactorChallenge
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&challenge).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// ClaimChallengeAttempt 条件自增校验次数，并发请求只有前 maxAttempts 次能占用成功
+func (r *TwoFactorGormRepository) ClaimChallengeAttempt(
+	ctx context.Context,
+	id uint,
+	maxAttempts int,
+	now time.Time,
+) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.TwoFactorChallenge{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ? AND attempts < ?", id, now, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ConsumeChallenge 标记挑战已使用
+func (r *TwoFactorGormRepository) ConsumeChallenge(ctx context.Context, id uint, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.TwoFactorChallenge{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -100,6 +100,25 @@ func (r *UserGormRepository) GetByEmail(
 	return &user, nil
 }
 
+// GetByOpenid 根据第三方登录标识获取用户
+func (r *UserGormRepository) GetByOpenid(
+	ctx context.Context,
+	openid string,
+) (*entity.User, error) {
+	var user entity.User
+	err := r.db.WithContext(ctx).
+		Preload("CurrentOrg").
+		Where("openid = ?", openid).
+		First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
 // GetByPhone 根据手机号获取用户
 func (r *UserGormRepository) GetByPhone(
 	ctx context.Context,
@@ -160,7 +179,7 @@ func (r *UserGormRepository) Create(
 	ctx context.Context,
 	user *entity.User,
 ) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return r.translateWriteError(r.db.WithContext(ctx).Create(user).Error)
 }
 
 // Update 更新用户
@@ -168,7 +187,18 @@ func (r *UserGormRepository) Update(
 	ctx context.Context,
 	user *entity.User,
 ) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	return r.translateWriteError(r.db.WithContext(ctx).Save(user).Error)
+}
+
+// translateWriteError 把唯一索引冲突统一转换为 gorm.ErrDuplicatedKey，服务层无需感知具体数据库
+func (r *UserGormRepository) translateWriteError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if translator, ok := r.db.Dialector.(gorm.ErrorTranslator); ok {
+		return translator.Translate(err)
+	}
+	return err
 }
 
 // Delete 删除用户（软删除）
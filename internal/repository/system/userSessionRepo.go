@@ -0,0 +1,82 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository/interfaces"
+
+	"gorm.io/gorm"
+)
+
+// UserSessionGormRepository 登录会话仓储GORM实现
+type UserSessionGormRepository struct {
+	db *gorm.DB
+}
+
+// NewUserSessionRepository 创建登录会话仓储实例
+func NewUserSessionRepository(db *gorm.DB) interfaces.UserSessionRepository {
+	return &UserSessionGormRepository{db: db}
+}
+
+// Create 写入新会话
+func (r *UserSessionGormRepository) Create(ctx context.Context, session *entity.UserSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// GetBySessionID 按会话标识查询
+func (r *UserSessionGormRepository) GetBySessionID(ctx context.Context, sessionID string) (*entity.UserSession, error) {
+	var session entity.UserSession
+	err := r.db.WithContext(ctx).Where("session_id = ?", sessionID).First(&session).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActiveByUserID 查询用户仍有效的会话
+func (r *UserSessionGormRepository) ListActiveByUserID(
+	ctx context.Context,
+	userID uint,
+	now time.Time,
+) ([]*entity.UserSession, error) {
+	var sessions []*entity.UserSession
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC, id DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Rotate 以当前刷新令牌ID作为乐观锁条件切换令牌，并发刷新时只有一个请求能成功
+func (r *UserSessionGormRepository) Rotate(
+	ctx context.Context,
+	sessionID, oldJTI string,
+	rotated *entity.UserSession,
+) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.UserSession{}).
+		Where("session_id = ? AND current_jti = ? AND revoked_at IS NULL", sessionID, oldJTI).
+		Updates(map[string]any{
+			"current_jti":  rotated.CurrentJTI,
+			"ip":           rotated.IP,
+			"user_agent":   rotated.UserAgent,
+			"last_seen_at": rotated.LastSeenAt,
+			"expires_at":   rotated.ExpiresAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Revoke 吊销会话
+func (r *UserSessionGormRepository) Revoke(ctx context.Context, sessionID, reason string, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.UserSession{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Updates(map[string]any{"revoked_at": now, "revoke_reason": reason}).Error
+}
@@ -58,10 +58,19 @@ func InitRouter() *gin.Engine {
 		systemRouter.InitRefreshTokenRouter(PublicGroup)
 		// 基础登录服务 - 获取验证码
 		systemRouter.InitBaseRouter(PublicGroup)
-		// 用户路由
-		systemRouter.InitUserRouter(PublicGroup)
+		// 用户路由：找回密码接口挂载 IP + 账号双维度限流，登录二次验证接口挂载 IP 限流
+		systemRouter.InitUserRouter(
+			PublicGroup,
+			middleware.PasswordResetRateLimitMiddleware(
+				global.PasswordResetIPLimiter,
+				global.PasswordResetAccountLimiter,
+			),
+			middleware.TwoFactorLoginRateLimitMiddleware(global.TwoFactorLoginIPLimiter),
+		)
 		// 组织路由（公共）
 		systemRouter.InitOrgRouter(PublicGroup)
+		// AI 会话只读分享查看
+		systemRouter.InitAIPublicRouter(PublicGroup, middleware.OptionalJWTAuth())
 		// todo 登录、注册、健康检测.
 	}
 
@@ -98,10 +107,18 @@ func InitRouter() *gin.Engine {
 	ojBindRateLimitMW := middleware.OJBindRateLimitMiddleware(global.OJBindLimiters)
 	{
 		systemRouter.InitAIRouter(BusinessGroup)
+		// AI 记忆管理：查看、修正、删除自动写回的记忆
+		systemRouter.InitAIMemoryRouter(BusinessGroup)
+		// AI 组织知识库：管理员导入 markdown / 纯文本讲义
+		systemRouter.InitAIKnowledgeRouter(BusinessGroup, uploadRateLimitMW)
 		// OJ 相关路由
 		systemRouter.InitOJRouter(BusinessGroup, ojBindRateLimitMW)
 		// OJ 任务相关路由
 		systemRouter.InitOJTaskRouter(BusinessGroup)
+		// OJ 比赛相关路由
+		systemRouter.InitOJContestRouter(BusinessGroup)
+		// OJ 题库目录与标签路由
+		systemRouter.InitOJQuestionBankRouter(BusinessGroup)
 		// 图片路由：登录即可访问，上传接口额外挂载限流中间件（全局+用户级双层限流）
 		systemRouter.InitImageRouter(BusinessGroup, uploadRateLimitMW)
 		// 组织路由：登录即可切换组织、查看我的组织
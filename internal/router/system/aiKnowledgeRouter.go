@@ -0,0 +1,26 @@
+package system
+
+import (
+	"personal_assistant/internal/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AIKnowledgeRouter 负责组织知识库路由的注册。
+type AIKnowledgeRouter struct{}
+
+// InitAIKnowledgeRouter 注册组织知识库文档路由。
+// 导入和重新导入接口支持 multipart 文件上传，因此复用上传限流中间件。
+func (r *AIKnowledgeRouter) InitAIKnowledgeRouter(router *gin.RouterGroup, uploadRateLimitMW gin.HandlerFunc) {
+	knowledgeRouter := router.Group("ai/knowledge/documents")
+	knowledgeCtrl := controller.ApiGroupApp.SystemApiGroup.GetAIKnowledgeCtrl()
+	{
+		knowledgeRouter.GET("", knowledgeCtrl.ListDocuments)                        // 分页查询组织知识库文档
+		knowledgeRouter.POST("", uploadRateLimitMW, knowledgeCtrl.CreateDocument)   // 导入知识库文档（JSON 或 markdown/txt 文件）
+		knowledgeRouter.GET(":id", knowledgeCtrl.GetDocument)                       // 获取知识库文档详情与召回统计
+		knowledgeRouter.PUT(":id", uploadRateLimitMW, knowledgeCtrl.UpdateDocument) // 重新导入知识库文档并生成新版本
+		knowledgeRouter.DELETE(":id", knowledgeCtrl.DeleteDocument)                 // 删除知识库文档及其向量与版本
+		knowledgeRouter.GET(":id/versions", knowledgeCtrl.ListVersions)             // 查询知识库文档版本历史
+		knowledgeRouter.GET(":id/versions/:versionNo", knowledgeCtrl.GetVersion)    // 获取指定版本快照
+	}
+}
@@ -0,0 +1,27 @@
+package system
+
+import (
+	"personal_assistant/internal/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AIMemoryRouter 负责 AI 记忆管理路由的注册。
+type AIMemoryRouter struct{}
+
+// InitAIMemoryRouter 注册用户侧 AI 记忆管理路由。
+// 所有接口通过 query 参数 scope（self / org / platform_ops）和 org_id 指定作用域，缺省为个人记忆。
+func (r *AIMemoryRouter) InitAIMemoryRouter(router *gin.RouterGroup) {
+	memoryRouter := router.Group("ai/memory")
+	memoryCtrl := controller.ApiGroupApp.SystemApiGroup.GetAIMemoryCtrl()
+	{
+		memoryRouter.GET("facts", memoryCtrl.ListFacts)                 // 分页检索结构化记忆事实
+		memoryRouter.PUT("facts/:id", memoryCtrl.UpdateFact)            // 修正结构化记忆事实
+		memoryRouter.DELETE("facts/:id", memoryCtrl.DeleteFact)         // 删除结构化记忆事实
+		memoryRouter.GET("documents", memoryCtrl.ListDocuments)         // 分页检索长期记忆文档
+		memoryRouter.GET("documents/:id", memoryCtrl.GetDocument)       // 获取长期记忆文档详情
+		memoryRouter.PUT("documents/:id", memoryCtrl.UpdateDocument)    // 修正长期记忆文档
+		memoryRouter.DELETE("documents/:id", memoryCtrl.DeleteDocument) // 删除长期记忆文档及其向量
+		memoryRouter.DELETE("", memoryCtrl.ClearScope)                  // 清空当前作用域下的全部记忆
+	}
+}
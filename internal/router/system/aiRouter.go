@@ -26,10 +26,30 @@ func (r *AIRouter) InitAIRouter(router *gin.RouterGroup) {
 	aiRouter := router.Group("ai/conversations")
 	aiCtrl := controller.ApiGroupApp.SystemApiGroup.GetAICtrl()
 	{
-		aiRouter.POST("", aiCtrl.CreateConversation)      // 创建会话
-		aiRouter.GET("", aiCtrl.ListConversations)        // 获取会话列表
-		aiRouter.GET(":id/messages", aiCtrl.ListMessages) // 获取某个会话下的消息列表
-		aiRouter.DELETE(":id", aiCtrl.DeleteConversation) // 删除指定会话
+		aiRouter.POST("", aiCtrl.CreateConversation)           // 创建会话
+		aiRouter.GET("", aiCtrl.ListConversations)             // 获取会话列表
+		aiRouter.GET("export", aiCtrl.ExportAllConversations)  // 打包导出当前用户全部会话
+		aiRouter.POST("import", aiCtrl.ImportConversation)     // 导入 JSON 导出文件为新会话
+		aiRouter.GET(":id/export", aiCtrl.ExportConversation)  // 导出指定会话为 Markdown 或 JSON
+		aiRouter.GET(":id/messages", aiCtrl.ListMessages)      // 获取某个会话活动分支上的消息列表
+		aiRouter.PUT(":id/active-branch", aiCtrl.SwitchBranch) // 切换会话活动分支
+		aiRouter.DELETE(":id", aiCtrl.DeleteConversation)      // 删除指定会话
+
+		aiRouter.POST(":id/shares", aiCtrl.CreateShare)            // 创建会话只读分享链接
+		aiRouter.GET(":id/shares", aiCtrl.ListShares)              // 获取会话分享链接列表
+		aiRouter.DELETE(":id/shares/:shareId", aiCtrl.RevokeShare) // 撤销会话分享链接
+	}
+}
+
+// InitAIPublicRouter 负责注册无需登录即可访问的 AI 路由。
+// 分享查看接口挂在公共路由组上，携带登录态时用于校验仅组织可见的分享。
+// optionalAuthMW: 可选登录中间件，只在会话有效时写入查看者身份。
+func (r *AIRouter) InitAIPublicRouter(router *gin.RouterGroup, optionalAuthMW gin.HandlerFunc) {
+	aiRouter := router.Group("ai/shares")
+	aiRouter.Use(optionalAuthMW)
+	aiCtrl := controller.ApiGroupApp.SystemApiGroup.GetAICtrl()
+	{
+		aiRouter.GET(":token", aiCtrl.ViewShare) // 通过分享 token 查看只读会话快照
 	}
 }
 
@@ -50,6 +70,9 @@ func (r *AIRouter) InitAISSERouter(router *gin.RouterGroup) {
 	aiRouter := router.Group("ai/conversations")
 	aiCtrl := controller.ApiGroupApp.SystemApiGroup.GetAICtrl()
 	{
-		aiRouter.POST(":id/stream", aiCtrl.StreamConversation) // 流式会话
+		aiRouter.POST(":id/stream", aiCtrl.StreamConversation)                        // 流式会话
+		aiRouter.POST(":id/interrupts/:interruptId/decide", aiCtrl.DecideInterrupt)   // 工具人工确认决策并继续流式生成
+		aiRouter.POST(":id/messages/:messageId/regenerate", aiCtrl.RegenerateMessage) // 重新生成回复，产生兄弟分支
+		aiRouter.POST(":id/messages/:messageId/edit", aiCtrl.EditMessage)             // 编辑用户消息并重新发送，产生兄弟分支
 	}
 }
@@ -0,0 +1,37 @@
+package system
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"personal_assistant/internal/controller"
+)
+
+// OJContestRouter OJ 比赛路由
+type OJContestRouter struct{}
+
+// InitOJContestRouter 初始化 OJ 比赛业务路由（需JWT，管理接口在服务层校验组织能力）
+// 挂载到 BusinessGroup
+// 路由前缀: /oj/contest
+func (r *OJContestRouter) InitOJContestRouter(router *gin.RouterGroup) {
+	ojContestRouter := router.Group("oj/contest")
+	ojContestCtrl := controller.ApiGroupApp.SystemApiGroup.GetOJContestCtrl()
+	{
+		// POST / - 创建比赛
+		ojContestRouter.POST("", ojContestCtrl.CreateContest)
+		// GET /list - 查询当前用户可见的比赛列表
+		ojContestRouter.GET("list", ojContestCtrl.GetVisibleContestList)
+
+		// PUT /:id - 更新未开赛的比赛
+		ojContestRouter.PUT(":id", ojContestCtrl.UpdateContest)
+		// DELETE /:id - 删除未开赛的比赛
+		ojContestRouter.DELETE(":id", ojContestCtrl.DeleteContest)
+
+		// GET /:id/scoreboard - 查询榜单（进行中为实时榜，结束后为冻结榜）
+		ojContestRouter.GET(":id/scoreboard", ojContestCtrl.GetScoreboard)
+		// GET /:id/scoreboard/stream - SSE 订阅实时榜单
+		ojContestRouter.GET(":id/scoreboard/stream", ojContestCtrl.StreamScoreboard)
+
+		// GET /:id - 查询比赛详情
+		ojContestRouter.GET(":id", ojContestCtrl.GetContestDetail)
+	}
+}
@@ -0,0 +1,34 @@
+package system
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"personal_assistant/internal/controller"
+)
+
+// OJQuestionBankRouter OJ 题库目录路由
+type OJQuestionBankRouter struct{}
+
+// InitOJQuestionBankRouter 初始化题库目录业务路由（需JWT，标签维护在服务层校验超级管理员）
+// 挂载到 BusinessGroup
+// 路由前缀: /oj/question-bank
+func (r *OJQuestionBankRouter) InitOJQuestionBankRouter(router *gin.RouterGroup) {
+	questionBankRouter := router.Group("oj/question-bank")
+	questionBankCtrl := controller.ApiGroupApp.SystemApiGroup.GetOJQuestionBankCtrl()
+	{
+		// GET /list - 按关键词、平台、难度、来源状态与标签检索题库
+		questionBankRouter.GET("list", questionBankCtrl.SearchQuestions)
+
+		// GET /tags - 查询全部标签
+		questionBankRouter.GET("tags", questionBankCtrl.ListTags)
+		// POST /tags - 创建标签
+		questionBankRouter.POST("tags", questionBankCtrl.CreateTag)
+		// PUT /tags/:id - 修改标签
+		questionBankRouter.PUT("tags/:id", questionBankCtrl.UpdateTag)
+		// DELETE /tags/:id - 删除标签并解除题目关联
+		questionBankRouter.DELETE("tags/:id", questionBankCtrl.DeleteTag)
+
+		// PUT /questions/tags - 整体设置一道题的标签
+		questionBankRouter.PUT("questions/tags", questionBankCtrl.SetQuestionTags)
+	}
+}
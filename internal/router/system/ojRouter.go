@@ -14,6 +14,7 @@ func (r *OJRouter) InitOJRouter(router *gin.RouterGroup, bindRateLimitMW gin.Han
 	{
 		ojRouter.POST("bind", bindRateLimitMW, ojCtrl.BindOJAccount) // 绑定OJ账号接口
 		ojRouter.POST("lanqiao/bind", bindRateLimitMW, ojCtrl.BindLanqiaoAccount)
+		ojRouter.POST("unbind", ojCtrl.UnbindOJAccount)      // 解绑OJ账号接口，重新绑定仍走上面的限流绑定接口
 		ojRouter.POST("ranking_list", ojCtrl.GetRankingList) // 获取OJ排行榜接口
 		ojRouter.POST("stats", ojCtrl.GetStats)              // 获取OJ统计数据接口
 		ojRouter.POST("curve", ojCtrl.GetCurve)              // 新增获取成绩曲线接口
@@ -23,6 +23,18 @@ func (r *OJTaskRouter) InitOJTaskRouter(router *gin.RouterGroup) {
 		ojTaskRouter.POST("", ojTaskCtrl.CreateTask)
 		// GET /list - 查询当前用户可见的任务列表
 		ojTaskRouter.GET("list", ojTaskCtrl.GetVisibleTaskList)
+		// GET /notices/stream - SSE 订阅当前用户的截止提醒与结果冻结通知
+		ojTaskRouter.GET("notices/stream", ojTaskCtrl.StreamTaskNotices)
+
+		// 待解析题目人工整理（仅超级管理员，服务层校验）
+		// GET /intakes - 跨任务按平台与输入标题聚合待解析题目
+		ojTaskRouter.GET("intakes", ojTaskCtrl.ListPendingIntakes)
+		// GET /intakes/candidates - 按标题相似度推荐题库候选
+		ojTaskRouter.GET("intakes/candidates", ojTaskCtrl.SuggestIntakeCandidates)
+		// POST /intakes/confirm - 确认匹配并回填所有任务版本
+		ojTaskRouter.POST("intakes/confirm", ojTaskCtrl.ConfirmIntake)
+		// POST /intakes/manual-question - 手工录入题目并回填所有任务版本
+		ojTaskRouter.POST("intakes/manual-question", ojTaskCtrl.CreateManualQuestionForIntake)
 
 		// 任务写操作
 		// PUT /:id - 更新任务
@@ -39,8 +51,12 @@ func (r *OJTaskRouter) InitOJTaskRouter(router *gin.RouterGroup) {
 		// 版本与执行记录查询
 		// GET /:id/versions - 查询任务版本列表
 		ojTaskRouter.GET(":id/versions", ojTaskCtrl.GetTaskVersions)
+		// GET /:id/occurrences - 对比周期任务最近若干个周期的执行结果
+		ojTaskRouter.GET(":id/occurrences", ojTaskCtrl.GetTaskOccurrences)
 		// GET /:id/executions/:executionId - 查询任务执行详情
 		ojTaskRouter.GET(":id/executions/:executionId", ojTaskCtrl.GetTaskExecutionDetail)
+		// GET /:id/executions/:executionId/export - 以 CSV/XLSX 导出执行结果矩阵
+		ojTaskRouter.GET(":id/executions/:executionId/export", ojTaskCtrl.ExportTaskExecution)
 		// GET /:id/executions/:executionId/users - 分页查询执行用户列表
 		ojTaskRouter.GET(":id/executions/:executionId/users", ojTaskCtrl.GetTaskExecutionUsers)
 		// GET /:id/executions/:executionId/users/:userId - 查询指定用户的执行详情
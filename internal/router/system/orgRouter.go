@@ -37,6 +37,14 @@ func (r *OrgRouter) InitOrgAuthRouter(router *gin.RouterGroup) {
 		orgGroup.DELETE(":id/member/:userId", orgCtrl.KickMember)
 		// 恢复成员
 		orgGroup.PUT(":id/member/:userId/recover", orgCtrl.RecoverMember)
+		// 邀请管理
+		orgGroup.POST(":id/invitations", orgCtrl.CreateOrgInvitation)
+		orgGroup.GET(":id/invitations", orgCtrl.ListOrgInvitations)
+		orgGroup.DELETE(":id/invitations/:invitationId", orgCtrl.RevokeOrgInvitation)
+		// 加入申请审批
+		orgGroup.GET(":id/join-requests", orgCtrl.ListOrgJoinRequests)
+		orgGroup.POST(":id/join-requests/:requestId/approve", orgCtrl.ApproveOrgJoinRequest)
+		orgGroup.POST(":id/join-requests/:requestId/reject", orgCtrl.RejectOrgJoinRequest)
 	}
 }
 
@@ -46,9 +54,10 @@ func (r *OrgRouter) InitOrgBusinessRouter(router *gin.RouterGroup) {
 	orgGroup := router.Group("system/org")
 	orgCtrl := controller.ApiGroupApp.SystemApiGroup.GetOrgCtrl()
 	{
-		orgGroup.GET("my", orgCtrl.GetMyOrgs)              // 获取我的组织
-		orgGroup.PUT("current", orgCtrl.SetCurrentOrg)     // 切换当前组织
-		orgGroup.POST("join", orgCtrl.JoinOrgByInviteCode) // 通过邀请码加入组织
-		orgGroup.POST("leave", orgCtrl.LeaveOrg)           // 退出组织
+		orgGroup.GET("my", orgCtrl.GetMyOrgs)                            // 获取我的组织
+		orgGroup.PUT("current", orgCtrl.SetCurrentOrg)                   // 切换当前组织
+		orgGroup.POST("join", orgCtrl.JoinOrgByInviteCode)               // 通过邀请码加入组织
+		orgGroup.POST("invitations/accept", orgCtrl.AcceptOrgInvitation) // 通过邀请链接加入组织
+		orgGroup.POST("leave", orgCtrl.LeaveOrg)                         // 退出组织
 	}
 }
@@ -9,12 +9,25 @@ import (
 type UserRouter struct{}
 
 // InitUserRouter 初始化用户公共路由（无需JWT）
-func (u *UserRouter) InitUserRouter(router *gin.RouterGroup) {
+// passwordResetRateLimitMW: 找回密码限流中间件（按 IP 与账号双维度，仅作用于找回密码路由）
+// twoFactorRateLimitMW: 登录二次验证限流中间件（按 IP，仅作用于登录第二步路由）
+func (u *UserRouter) InitUserRouter(
+	router *gin.RouterGroup,
+	passwordResetRateLimitMW gin.HandlerFunc,
+	twoFactorRateLimitMW gin.HandlerFunc,
+) {
 	userRouter := router.Group("user")
 	userCtrl := controller.ApiGroupApp.SystemApiGroup.GetUserCtrl()
 	{
-		userRouter.POST("register", userCtrl.Register) // 注册
-		userRouter.POST("login", userCtrl.Login)       // 登录
+		userRouter.POST("register", userCtrl.Register)                                                   // 注册
+		userRouter.POST("login", userCtrl.Login)                                                         // 登录
+		userRouter.POST("login/2fa", twoFactorRateLimitMW, userCtrl.LoginTwoFactor)                      // 登录第二步：校验动态码或恢复码
+		userRouter.POST("login/2fa/setup", twoFactorRateLimitMW, userCtrl.LoginTwoFactorSetup)           // 登录时强制绑定：获取绑定密钥
+		userRouter.POST("password/reset-code", passwordResetRateLimitMW, userCtrl.SendPasswordResetCode) // 找回密码：发送邮箱验证码
+		userRouter.POST("password/reset", passwordResetRateLimitMW, userCtrl.ResetPassword)              // 找回密码：校验验证码并重置
+		userRouter.GET("oauth/providers", userCtrl.ListOAuthProviders)                                   // 可用的第三方登录方式
+		userRouter.GET("oauth/:provider/authorize", userCtrl.OAuthAuthorize)                             // 发起第三方登录
+		userRouter.POST("oauth/:provider/callback", userCtrl.OAuthCallback)                              // 第三方登录回调：提交 code 与 state
 	}
 }
 
@@ -23,11 +36,24 @@ func (u *UserRouter) InitUserBusinessRouter(router *gin.RouterGroup) {
 	userRouter := router.Group("user")
 	userCtrl := controller.ApiGroupApp.SystemApiGroup.GetUserCtrl()
 	{
-		userRouter.POST("logout", userCtrl.Logout)                // 登出
-		userRouter.PUT("profile", userCtrl.UpdateProfile)         // 更新个人资料
-		userRouter.PUT("phone", userCtrl.ChangePhone)             // 换绑手机号
-		userRouter.PUT("password", userCtrl.ChangePassword)       // 修改密码
-		userRouter.POST("deactivate", userCtrl.DeactivateAccount) // 主动注销账号
+		userRouter.POST("logout", userCtrl.Logout)                                       // 登出
+		userRouter.PUT("profile", userCtrl.UpdateProfile)                                // 更新个人资料
+		userRouter.PUT("phone", userCtrl.ChangePhone)                                    // 换绑手机号
+		userRouter.PUT("email", userCtrl.BindEmail)                                      // 绑定或换绑邮箱（需邮箱验证码）
+		userRouter.PUT("password", userCtrl.ChangePassword)                              // 修改密码
+		userRouter.POST("deactivate", userCtrl.DeactivateAccount)                        // 主动注销账号
+		userRouter.GET("sessions", userCtrl.ListSessions)                                // 已登录设备列表
+		userRouter.DELETE("sessions/:session_id", userCtrl.RevokeSession)                // 下线指定设备
+		userRouter.POST("sessions/revoke-others", userCtrl.RevokeOtherSessions)          // 下线其他全部设备
+		userRouter.GET("2fa", userCtrl.GetTwoFactorStatus)                               // 二次验证状态
+		userRouter.POST("2fa/setup", userCtrl.SetupTwoFactor)                            // 生成待确认的绑定密钥
+		userRouter.POST("2fa/enable", userCtrl.EnableTwoFactor)                          // 校验动态码并启用
+		userRouter.POST("2fa/disable", userCtrl.DisableTwoFactor)                        // 关闭二次验证
+		userRouter.POST("2fa/recovery-codes", userCtrl.RegenerateTwoFactorRecoveryCodes) // 重新生成恢复码
+		userRouter.GET("oauth/binding", userCtrl.GetOAuthBinding)                        // 第三方账号绑定状态
+		userRouter.DELETE("oauth/binding", userCtrl.UnlinkOAuth)                         // 解绑第三方账号
+		userRouter.GET("oauth/:provider/link/authorize", userCtrl.OAuthLinkAuthorize)    // 发起第三方账号绑定
+		userRouter.POST("oauth/:provider/link/callback", userCtrl.OAuthLinkCallback)     // 第三方账号绑定回调
 	}
 }
 
@@ -18,10 +18,21 @@ import (
 
 // JWTServiceContract 定义当前服务对外暴露的能力契约。
 type JWTServiceContract interface {
-	IssueLoginTokens(ctx context.Context, user entity.User) (*resp.LoginResponse, string, int64, *erro.JWTError)
-	IsInBlacklist(jwt string) bool
-	GetAccessToken(ctx context.Context, token string) (*resp.RefreshTokenResponse, *erro.JWTError)
+	// IssueLoginTokens 登录后创建设备会话并签发令牌
+	IssueLoginTokens(ctx context.Context, user entity.User, device request.LoginDevice) (*resp.LoginResponse, string, int64, *erro.JWTError)
+	// IsInBlacklist 判断会话是否已被吊销
+	IsInBlacklist(sessionID string) bool
+	// RefreshTokens 轮换刷新令牌并签发新的访问令牌，旧刷新令牌重放会注销整个会话
+	RefreshTokens(ctx context.Context, token string, device request.LoginDevice) (*resp.RefreshTokenResponse, int64, *erro.JWTError)
 	JoinInBlacklist(ctx context.Context, jwtList entity.JwtBlacklist) error
+	// RevokeUserRefreshTokens 吊销用户全部会话（找回密码、禁用账号等安全场景），reason 记录吊销原因
+	RevokeUserRefreshTokens(ctx context.Context, user entity.User, reason string) error
+	// ListUserSessions 列出用户有效的设备会话
+	ListUserSessions(ctx context.Context, userID uint, currentSessionID string) ([]*resp.UserSessionItem, error)
+	// RevokeUserSession 吊销用户自己的某个会话
+	RevokeUserSession(ctx context.Context, userID uint, sessionID, reason string) error
+	// RevokeOtherUserSessions 吊销除当前会话外的全部会话
+	RevokeOtherUserSessions(ctx context.Context, userID uint, currentSessionID string) (int, error)
 }
 
 // AuthorizationServiceContract 定义当前服务对外暴露的能力契约。
@@ -62,6 +73,8 @@ type PermissionProjectionServiceContract interface {
 type BaseServiceContract interface {
 	GetCaptcha(store base64Captcha.Store) (string, string, error)
 	VerifyAndSendEmailCode(ctx *gin.Context, store base64Captcha.Store, req *request.SendEmailVerificationCodeReq) error
+	// ConsumeEmailVerificationCode 校验会话中的邮箱验证码，通过后立即作废
+	ConsumeEmailVerificationCode(ctx *gin.Context, email, code string) error
 }
 
 // HealthServiceContract 定义当前服务对外暴露的能力契约。
@@ -76,7 +89,34 @@ type UserServiceContract interface {
 	PhoneLogin(ctx context.Context, req *request.LoginReq) (*entity.User, error)
 	UpdateProfile(ctx context.Context, userID uint, req *request.UpdateProfileReq) (*entity.User, error)
 	ChangePhone(ctx context.Context, userID uint, req *request.ChangePhoneReq) (*entity.User, error)
+	// BindEmail 绑定已通过验证码校验的邮箱，用于找回密码与邮箱定向邀请
+	BindEmail(ctx context.Context, userID uint, email string) (*entity.User, error)
 	ChangePassword(ctx context.Context, userID uint, req *request.ChangePasswordReq) error
+	// SendPasswordResetCode 找回密码：校验图形验证码后向账号绑定邮箱发送重置验证码
+	SendPasswordResetCode(ctx context.Context, store base64Captcha.Store, clientIP string, req *request.SendPasswordResetCodeReq) error
+	// ResetPassword 找回密码：校验重置验证码、设置新密码并吊销全部刷新令牌
+	ResetPassword(ctx context.Context, req *request.ResetPasswordReq) error
+	// BeginTwoFactorLogin 密码校验通过后判断是否需要二次验证，需要时返回登录挑战
+	BeginTwoFactorLogin(ctx context.Context, user *entity.User) (*resp.TwoFactorLoginChallenge, error)
+	// SetupTwoFactorByChallenge 登录时被要求强制绑定，凭挑战令牌获取绑定密钥
+	SetupTwoFactorByChallenge(ctx context.Context, req *request.TwoFactorChallengeReq) (*resp.TwoFactorSetupResp, error)
+	// CompleteTwoFactorLogin 登录第二步，校验通过后返回用户；强制绑定时一并返回恢复码
+	CompleteTwoFactorLogin(ctx context.Context, req *request.TwoFactorLoginReq) (*entity.User, []string, error)
+	GetTwoFactorStatus(ctx context.Context, userID uint) (*resp.TwoFactorStatusResp, error)
+	SetupTwoFactor(ctx context.Context, userID uint) (*resp.TwoFactorSetupResp, error)
+	EnableTwoFactor(ctx context.Context, userID uint, req *request.TwoFactorCodeReq) (*resp.TwoFactorRecoveryCodesResp, error)
+	DisableTwoFactor(ctx context.Context, userID uint, req *request.TwoFactorCodeReq) error
+	RegenerateTwoFactorRecoveryCodes(ctx context.Context, userID uint, req *request.TwoFactorCodeReq) (*resp.TwoFactorRecoveryCodesResp, error)
+	// ListOAuthProviders 返回已启用的第三方登录方式
+	ListOAuthProviders(ctx context.Context) ([]resp.OAuthProviderItem, error)
+	// BeginOAuthLogin 发起第三方登录，返回授权地址
+	BeginOAuthLogin(ctx context.Context, provider string) (*resp.OAuthAuthorizeResp, error)
+	// CompleteOAuthLogin 处理第三方登录回调，按绑定关系登录或注册新用户
+	CompleteOAuthLogin(ctx context.Context, provider string, req *request.OAuthCallbackReq) (*entity.User, error)
+	BeginOAuthLink(ctx context.Context, userID uint, provider string) (*resp.OAuthAuthorizeResp, error)
+	CompleteOAuthLink(ctx context.Context, userID uint, provider string, req *request.OAuthCallbackReq) (*resp.OAuthBindingResp, error)
+	GetOAuthBinding(ctx context.Context, userID uint) (*resp.OAuthBindingResp, error)
+	UnlinkOAuth(ctx context.Context, userID uint) error
 	GetUserList(ctx context.Context, req *request.UserListReq) (*resp.PageDataUser, error)
 	GetUserDetail(ctx context.Context, id uint) (*entity.User, error)
 	GetUserRoles(ctx context.Context, userID, orgID uint) ([]*entity.Role, error)
@@ -114,18 +154,33 @@ type OrgServiceContract interface {
 
 	// RecoverMember 恢复成员（撤销踢出/移除）
 	RecoverMember(ctx context.Context, operatorID, orgID, targetUserID uint, reason string) error
+
+	// CreateOrgInvitation 创建邀请，令牌明文只返回一次
+	CreateOrgInvitation(ctx context.Context, operatorID, orgID uint, req *request.CreateOrgInvitationReq) (*resp.OrgInvitationCreatedResp, error)
+	ListOrgInvitations(ctx context.Context, operatorID, orgID uint) ([]*resp.OrgInvitationItem, error)
+	RevokeOrgInvitation(ctx context.Context, operatorID, orgID, invitationID uint) error
+
+	// AcceptOrgInvitation 接受邀请，需要审批时只生成待审批申请
+	AcceptOrgInvitation(ctx context.Context, userID uint, token string) (*resp.OrgInvitationAcceptResp, error)
+
+	// 加入申请审批
+	ListOrgJoinRequests(ctx context.Context, operatorID, orgID uint, status string) ([]*resp.OrgJoinRequestItem, error)
+	ApproveOrgJoinRequest(ctx context.Context, operatorID, orgID, requestID uint, note string) error
+	RejectOrgJoinRequest(ctx context.Context, operatorID, orgID, requestID uint, note string) error
 }
 
 // OJServiceContract 定义当前服务对外暴露的能力契约。
 type OJServiceContract interface {
 	BindOJAccount(ctx context.Context, userID uint, req *request.BindOJAccountReq) (*resp.BindOJAccountResp, error)
 	BindLanqiaoAccount(ctx context.Context, userID uint, req *request.BindLanqiaoAccountReq) (*resp.BindOJAccountResp, error)
+	UnbindOJAccount(ctx context.Context, userID uint, req *request.UnbindOJAccountReq) (*resp.UnbindOJAccountResp, error)
 	GetRankingList(ctx context.Context, userID uint, req *request.OJRankingListReq) (*resp.OJRankingListResp, error)
 	GetUserStats(ctx context.Context, userID uint, req *request.OJStatsReq) (*resp.OJStatsResp, error)
 	GetCurve(ctx context.Context, userID uint, req *request.OJCurveReq) (*resp.OJCurveResp, error)
 	SyncAllLuoguUsers(ctx context.Context) error
 	SyncAllLeetcodeUsers(ctx context.Context) error
 	SyncAllLanqiaoUsers(ctx context.Context) error
+	SyncAllProviderUsers(ctx context.Context) error
 	RefreshAllLanqiaoSubmissionStats(ctx context.Context) error
 	RebuildRankingCaches(ctx context.Context) error
 	HandleLuoguBindPayload(ctx context.Context, userID uint, payload *eventdto.LuoguBindPayload) error
@@ -145,11 +200,41 @@ type OJTaskServiceContract interface {
 	GetVisibleTaskList(ctx context.Context, userID uint, req *request.OJTaskListReq) ([]*resp.OJTaskListItemResp, int64, error)
 	GetTaskDetail(ctx context.Context, userID, taskID uint) (*resp.OJTaskDetailResp, error)
 	GetTaskVersions(ctx context.Context, userID, taskID uint) (*resp.OJTaskVersionListResp, error)
+	GetTaskOccurrences(ctx context.Context, userID, taskID uint, req *request.OJTaskOccurrenceListReq) (*resp.OJTaskOccurrenceCompareResp, error)
 	GetTaskExecutionDetail(ctx context.Context, userID, taskID, executionID uint) (*resp.OJTaskExecutionResp, error)
 	GetTaskExecutionUsers(ctx context.Context, userID, taskID, executionID uint, req *request.OJTaskExecutionUserListReq) (*resp.OJTaskExecutionUserListResp, error)
 	GetTaskExecutionUserDetail(ctx context.Context, userID, taskID, executionID, targetUserID uint) (*resp.OJTaskExecutionUserDetailResp, error)
+	ExportTaskExecution(ctx context.Context, userID, taskID, executionID uint, req *request.OJTaskExecutionExportReq) (*resp.OJTaskExecutionExportFile, error)
 	DispatchPendingExecutions(ctx context.Context) error
+	TrackExecutionDeadlines(ctx context.Context) error
+	StreamTaskNotices(ctx context.Context, userID uint, lastEventID string, writer streamsse.StreamWriter) error
 	HandleQuestionUpserted(ctx context.Context, event *eventdto.QuestionUpsertedEvent) error
+	ListPendingIntakes(ctx context.Context, operatorID uint, req *request.OJQuestionIntakeListReq) ([]*resp.OJQuestionIntakeGroupResp, int64, error)
+	SuggestIntakeCandidates(ctx context.Context, operatorID uint, req *request.OJQuestionIntakeCandidateReq) ([]*resp.OJQuestionIntakeCandidateResp, error)
+	ConfirmIntake(ctx context.Context, operatorID uint, req *request.ConfirmOJQuestionIntakeReq) (*resp.OJQuestionIntakeResolveResp, error)
+	CreateManualQuestionForIntake(ctx context.Context, operatorID uint, req *request.CreateManualOJQuestionReq) (*resp.OJQuestionIntakeResolveResp, error)
+}
+
+// OJContestServiceContract 定义限时 OJ 比赛对外暴露的能力契约。
+type OJContestServiceContract interface {
+	CreateContest(ctx context.Context, operatorID uint, req *request.CreateOJContestReq) (*resp.OJContestCreateResp, error)
+	UpdateContest(ctx context.Context, operatorID, contestID uint, req *request.UpdateOJContestReq) error
+	DeleteContest(ctx context.Context, operatorID, contestID uint) error
+	GetVisibleContestList(ctx context.Context, userID uint, req *request.OJContestListReq) ([]*resp.OJContestListItemResp, int64, error)
+	GetContestDetail(ctx context.Context, userID, contestID uint) (*resp.OJContestDetailResp, error)
+	GetScoreboard(ctx context.Context, userID, contestID uint) (*resp.OJContestScoreboardResp, error)
+	StreamScoreboard(ctx context.Context, userID, contestID uint, lastEventID string, writer streamsse.StreamWriter) error
+	PollContests(ctx context.Context) error
+}
+
+// OJQuestionBankServiceContract 定义跨平台题库目录与自定义标签对外暴露的能力契约。
+type OJQuestionBankServiceContract interface {
+	SearchQuestions(ctx context.Context, req *request.OJQuestionBankListReq) ([]*resp.OJQuestionBankItemResp, int64, error)
+	ListTags(ctx context.Context) ([]*resp.OJQuestionTagResp, error)
+	CreateTag(ctx context.Context, operatorID uint, req *request.CreateOJQuestionTagReq) (*resp.OJQuestionTagResp, error)
+	UpdateTag(ctx context.Context, operatorID, tagID uint, req *request.UpdateOJQuestionTagReq) error
+	DeleteTag(ctx context.Context, operatorID, tagID uint) error
+	SetQuestionTags(ctx context.Context, operatorID uint, req *request.SetOJQuestionTagsReq) error
 }
 
 // OJDailyStatsProjectionServiceContract 定义当前服务对外暴露的能力契约。
@@ -232,6 +317,46 @@ type AIServiceContract interface {
 	ListMessages(ctx context.Context, userID uint, conversationID string) ([]*resp.AssistantMessageResp, error)
 	DeleteConversation(ctx context.Context, userID uint, conversationID string) error
 	StreamConversation(ctx context.Context, userID uint, conversationID string, req *request.StreamAssistantMessageReq, writer streamsse.StreamWriter) error
+	DecideInterrupt(ctx context.Context, userID uint, conversationID string, interruptID string, req *request.DecideAssistantInterruptReq, writer streamsse.StreamWriter) error
+	RegenerateMessage(ctx context.Context, userID uint, conversationID string, messageID string, writer streamsse.StreamWriter) error
+	EditMessage(ctx context.Context, userID uint, conversationID string, messageID string, req *request.EditAssistantMessageReq, writer streamsse.StreamWriter) error
+	SwitchBranch(ctx context.Context, userID uint, conversationID string, req *request.SwitchAssistantBranchReq) ([]*resp.AssistantMessageResp, error)
+	ExportConversation(ctx context.Context, userID uint, conversationID string, format string) (*resp.AssistantExportFile, error)
+	ExportAllConversations(ctx context.Context, userID uint) (*resp.AssistantExportFile, error)
+	ImportConversation(ctx context.Context, userID uint, req *request.ImportAssistantConversationReq) (*resp.AssistantConversationResp, error)
+	CreateShare(ctx context.Context, userID uint, conversationID string, req *request.CreateAssistantShareReq) (*resp.AssistantShareResp, error)
+	ListShares(ctx context.Context, userID uint, conversationID string) ([]*resp.AssistantShareResp, error)
+	RevokeShare(ctx context.Context, userID uint, conversationID string, shareID string) error
+	ViewShare(ctx context.Context, viewerUserID uint, token string) (*resp.AssistantSharedConversationResp, error)
+}
+
+// AIMemoryServiceContract 定义用户侧记忆管理对外暴露的能力契约。
+type AIMemoryServiceContract interface {
+	ListFacts(ctx context.Context, userID uint, req *request.AIMemoryFactListReq) (*resp.AIMemoryFactListResp, error)
+	UpdateFact(ctx context.Context, userID uint, factID uint, scopeReq *request.AIMemoryScopeReq, req *request.UpdateAIMemoryFactReq) (*resp.AIMemoryFactResp, error)
+	DeleteFact(ctx context.Context, userID uint, factID uint, scopeReq *request.AIMemoryScopeReq) error
+	ListDocuments(ctx context.Context, userID uint, req *request.AIMemoryDocumentListReq) (*resp.AIMemoryDocumentListResp, error)
+	GetDocument(ctx context.Context, userID uint, documentID string, scopeReq *request.AIMemoryScopeReq) (*resp.AIMemoryDocumentResp, error)
+	UpdateDocument(ctx context.Context, userID uint, documentID string, scopeReq *request.AIMemoryScopeReq, req *request.UpdateAIMemoryDocumentReq) (*resp.AIMemoryDocumentResp, error)
+	DeleteDocument(ctx context.Context, userID uint, documentID string, scopeReq *request.AIMemoryScopeReq) error
+	ClearScope(ctx context.Context, userID uint, scopeReq *request.AIMemoryScopeReq) (*resp.AIMemoryClearResp, error)
+}
+
+// AIMemoryIndexServiceContract 定义长期记忆向量索引的后台补偿能力契约。
+// 切换 embedding provider 后，存量 chunks 也由它分批重建到新的向量空间。
+type AIMemoryIndexServiceContract interface {
+	IndexPendingDocuments(ctx context.Context, limit int) error
+}
+
+// AIKnowledgeServiceContract 定义组织知识库文档管理对外暴露的能力契约。
+type AIKnowledgeServiceContract interface {
+	ListDocuments(ctx context.Context, userID uint, req *request.AIKnowledgeDocumentListReq) (*resp.AIKnowledgeDocumentListResp, error)
+	CreateDocument(ctx context.Context, userID uint, req *request.CreateAIKnowledgeDocumentReq) (*resp.AIKnowledgeDocumentResp, error)
+	GetDocument(ctx context.Context, userID uint, documentID string) (*resp.AIKnowledgeDocumentResp, error)
+	UpdateDocument(ctx context.Context, userID uint, documentID string, req *request.UpdateAIKnowledgeDocumentReq) (*resp.AIKnowledgeDocumentResp, error)
+	DeleteDocument(ctx context.Context, userID uint, documentID string) error
+	ListVersions(ctx context.Context, userID uint, documentID string) ([]*resp.AIKnowledgeVersionResp, error)
+	GetVersion(ctx context.Context, userID uint, documentID string, versionNo int) (*resp.AIKnowledgeVersionResp, error)
 }
 
 // Supplier 用于集中提供当前模块依赖对象。
@@ -245,6 +370,8 @@ type Supplier interface {
 	GetOrgSvc() OrgServiceContract
 	GetOJSvc() OJServiceContract
 	GetOJTaskSvc() OJTaskServiceContract
+	GetOJContestSvc() OJContestServiceContract
+	GetOJQuestionBankSvc() OJQuestionBankServiceContract
 	GetApiSvc() ApiServiceContract
 	GetMenuSvc() MenuServiceContract
 	GetRoleSvc() RoleServiceContract
@@ -253,4 +380,7 @@ type Supplier interface {
 	GetCacheProjectionSvc() CacheProjectionServiceContract
 	GetOJDailyStatsProjectionSvc() OJDailyStatsProjectionServiceContract
 	GetAISvc() AIServiceContract
+	GetAIMemorySvc() AIMemoryServiceContract
+	GetAIMemoryIndexSvc() AIMemoryIndexServiceContract
+	GetAIKnowledgeSvc() AIKnowledgeServiceContract
 }
@@ -0,0 +1,315 @@
+package system
+
+import (
+	"context"
+	"strings"
+
+	aidomain "personal_assistant/internal/domain/ai"
+	streamsse "personal_assistant/internal/infrastructure/sse"
+	"personal_assistant/internal/model/dto/request"
+	resp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository/interfaces"
+	bizerrors "personal_assistant/pkg/errors"
+)
+
+// aiMessageTree 是会话消息按父指针组织后的只读视图。
+// 会话 ActiveLeafID 为空时视为尚未分叉的旧会话，父指针按创建顺序推断。
+type aiMessageTree struct {
+	// messages 按创建顺序保存会话内全部消息。
+	messages []*entity.AIMessage
+	// byID 按消息 ID 索引。
+	byID map[string]*entity.AIMessage
+	// children 按父消息 ID 聚合子消息，根消息挂在空字符串下。
+	children map[string][]*entity.AIMessage
+	// activeLeafID 是当前活动分支的叶子消息 ID。
+	activeLeafID string
+	// legacyParents 是按创建顺序推断出父指针、尚未落库的旧消息。
+	legacyParents []*entity.AIMessage
+}
+
+// newAIMessageTree 负责把会话消息整理成消息树。
+// 参数：
+//   - conversation：所属会话，提供活动分支叶子。
+//   - messages：按创建顺序排列的会话消息。
+//
+// 注意事项：
+//   - 旧会话推断出的父指针只写在内存对象上，调用方需要在写事务中通过 legacyParents 落库。
+func newAIMessageTree(conversation *entity.AIConversation, messages []*entity.AIMessage) *aiMessageTree {
+	tree := &aiMessageTree{
+		messages: make([]*entity.AIMessage, 0, len(messages)),
+		byID:     make(map[string]*entity.AIMessage, len(messages)),
+		children: make(map[string][]*entity.AIMessage),
+	}
+	if conversation != nil {
+		tree.activeLeafID = strings.TrimSpace(conversation.ActiveLeafID)
+	}
+	for _, message := range messages {
+		if message != nil {
+			tree.messages = append(tree.messages, message)
+		}
+	}
+
+	// 旧会话没有活动叶子，消息天然是一条直线，按顺序补齐父指针即可。
+	if tree.activeLeafID == "" {
+		for index, message := range tree.messages {
+			if index > 0 && message.ParentID == "" {
+				message.ParentID = tree.messages[index-1].ID
+				tree.legacyParents = append(tree.legacyParents, message)
+			}
+		}
+	}
+	for _, message := range tree.messages {
+		tree.byID[message.ID] = message
+		tree.children[message.ParentID] = append(tree.children[message.ParentID], message)
+	}
+	return tree
+}
+
+// find 按 ID 查找消息，不存在时返回 nil。
+func (t *aiMessageTree) find(messageID string) *entity.AIMessage {
+	return t.byID[strings.TrimSpace(messageID)]
+}
+
+// leaf 返回当前活动分支的叶子消息，活动叶子失效时回退到最后一条消息。
+func (t *aiMessageTree) leaf() *entity.AIMessage {
+	if message := t.find(t.activeLeafID); message != nil {
+		return message
+	}
+	if len(t.messages) == 0 {
+		return nil
+	}
+	return t.messages[len(t.messages)-1]
+}
+
+// activeBranch 返回从根到活动叶子的消息链。
+func (t *aiMessageTree) activeBranch() []*entity.AIMessage {
+	leaf := t.leaf()
+	if leaf == nil {
+		return nil
+	}
+	return t.pathTo(leaf.ID)
+}
+
+// pathTo 返回从根到指定消息（含）的消息链；messageID 为空时返回空链。
+func (t *aiMessageTree) pathTo(messageID string) []*entity.AIMessage {
+	path := make([]*entity.AIMessage, 0)
+	visited := make(map[string]struct{})
+	for current := t.find(messageID); current != nil; current = t.find(current.ParentID) {
+		// 父指针理论上无环，这里兜底防止脏数据导致死循环。
+		if _, ok := visited[current.ID]; ok {
+			break
+		}
+		visited[current.ID] = struct{}{}
+		path = append(path, current)
+	}
+	for left, right := 0, len(path)-1; left < right; left, right = left+1, right-1 {
+		path[left], path[right] = path[right], path[left]
+	}
+	return path
+}
+
+// descendLeaf 从指定消息沿最新创建的子消息向下走到叶子。
+func (t *aiMessageTree) descendLeaf(messageID string) *entity.AIMessage {
+	current := t.find(messageID)
+	visited := make(map[string]struct{})
+	for current != nil {
+		if _, ok := visited[current.ID]; ok {
+			break
+		}
+		visited[current.ID] = struct{}{}
+		children := t.children[current.ID]
+		if len(children) == 0 {
+			break
+		}
+		current = children[len(children)-1]
+	}
+	return current
+}
+
+// siblingIDs 返回与指定消息同父的全部消息 ID。
+func (t *aiMessageTree) siblingIDs(message *entity.AIMessage) []string {
+	if message == nil {
+		return nil
+	}
+	siblings := t.children[message.ParentID]
+	ids := make([]string, 0, len(siblings))
+	for _, sibling := range siblings {
+		ids = append(ids, sibling.ID)
+	}
+	return ids
+}
+
+// lastAIMessageID 返回消息链最后一条消息的 ID，空链返回空字符串。
+func lastAIMessageID(messages []*entity.AIMessage) string {
+	if len(messages) == 0 {
+		return ""
+	}
+	return messages[len(messages)-1].ID
+}
+
+// branchMessagesToResp 负责把活动分支转换成响应列表，并补齐父指针和兄弟分支信息。
+func branchMessagesToResp(tree *aiMessageTree, branch []*entity.AIMessage) ([]*resp.AssistantMessageResp, error) {
+	items := make([]*resp.AssistantMessageResp, 0, len(branch))
+	for _, message := range branch {
+		item, err := messageToResp(message)
+		if err != nil {
+			return nil, err
+		}
+		item.ParentID = message.ParentID
+		// 只有真正存在多个分支时才返回兄弟列表，避免普通消息携带冗余字段。
+		if siblings := tree.siblingIDs(message); len(siblings) > 1 {
+			item.SiblingIDs = siblings
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// RegenerateMessage 负责基于原用户消息重新生成一条 assistant 回复，新回复作为原回复的兄弟分支。
+// 参数：
+//   - messageID：要重新生成的 assistant 消息 ID。
+//
+// 核心流程：
+//  1. 校验会话归属、忙碌状态和目标消息角色。
+//  2. 以目标回复的父用户消息为本轮输入，历史截断到该用户消息之前。
+//  3. 复用 StreamConversation 的流式执行链路，只新建 assistant 消息并切换活动分支。
+func (s *AIService) RegenerateMessage(
+	ctx context.Context,
+	userID uint,
+	conversationID string,
+	messageID string,
+	writer streamsse.StreamWriter,
+) error {
+	if err := s.ensureStreamAvailable(writer); err != nil {
+		return err
+	}
+	conversation, user, tree, err := s.loadStreamTarget(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+
+	target := tree.find(messageID)
+	if target == nil || target.Role != aidomain.RoleAssistant {
+		return bizerrors.New(bizerrors.CodeAIMessageNotFound)
+	}
+	userMessage := tree.find(target.ParentID)
+	if userMessage == nil || userMessage.Role != aidomain.RoleUser {
+		return bizerrors.NewWithMsg(bizerrors.CodeAIMessageNotFound, "未找到该回复对应的用户消息")
+	}
+
+	turn := newAIStreamTurn(conversation.ID, userMessage.Content, userMessage.ParentID)
+	turn.userMessage = userMessage
+	turn.reuseUserMessage = true
+	turn.assistantMessage.ParentID = userMessage.ID
+	turn.history = tree.pathTo(userMessage.ParentID)
+	turn.legacyParents = tree.legacyParents
+	return s.runStreamTurn(ctx, conversation, user, turn, writer)
+}
+
+// EditMessage 负责编辑一条历史用户消息并重新发送，新消息作为原消息的兄弟分支。
+// 参数：
+//   - messageID：要编辑的用户消息 ID。
+//   - req：编辑后的消息内容。
+//
+// 注意事项：
+//   - 原消息及其后续回复保持不变，客户端可通过切换分支回到原对话。
+func (s *AIService) EditMessage(
+	ctx context.Context,
+	userID uint,
+	conversationID string,
+	messageID string,
+	req *request.EditAssistantMessageReq,
+	writer streamsse.StreamWriter,
+) error {
+	if req == nil || strings.TrimSpace(req.Content) == "" {
+		return bizerrors.New(bizerrors.CodeInvalidParams)
+	}
+	if err := s.ensureStreamAvailable(writer); err != nil {
+		return err
+	}
+	conversation, user, tree, err := s.loadStreamTarget(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+
+	target := tree.find(messageID)
+	if target == nil || target.Role != aidomain.RoleUser {
+		return bizerrors.New(bizerrors.CodeAIMessageNotFound)
+	}
+
+	turn := newAIStreamTurn(conversation.ID, strings.TrimSpace(req.Content), target.ParentID)
+	turn.history = tree.pathTo(target.ParentID)
+	turn.legacyParents = tree.legacyParents
+	return s.runStreamTurn(ctx, conversation, user, turn, writer)
+}
+
+// SwitchBranch 负责把会话活动分支切换到包含指定消息的分支，并返回切换后的消息列表。
+// 参数：
+//   - req.MessageID：目标消息 ID；切换后沿该消息最新的子消息一路向下确定叶子。
+func (s *AIService) SwitchBranch(
+	ctx context.Context,
+	userID uint,
+	conversationID string,
+	req *request.SwitchAssistantBranchReq,
+) ([]*resp.AssistantMessageResp, error) {
+	if req == nil {
+		return nil, bizerrors.New(bizerrors.CodeInvalidParams)
+	}
+	if _, err := s.requireConversationOwner(ctx, userID, conversationID); err != nil {
+		return nil, err
+	}
+
+	var tree *aiMessageTree
+	err := s.txRunner.InTx(ctx, func(tx any) error {
+		txAI := s.aiRepo.WithTx(tx)
+		conversation, err := txAI.GetConversationByIDForUpdate(ctx, conversationID)
+		if err != nil {
+			return err
+		}
+		if conversation == nil || conversation.UserID != userID {
+			return bizerrors.New(bizerrors.CodeAIConversationNotFound)
+		}
+		// 生成中切换分支会让本轮回复挂到用户看不到的分支上，直接拒绝。
+		if conversation.IsGenerating {
+			return bizerrors.New(bizerrors.CodeAIConversationBusy)
+		}
+		messages, err := txAI.ListMessagesByConversation(ctx, conversation.ID)
+		if err != nil {
+			return err
+		}
+		tree = newAIMessageTree(conversation, messages)
+		leaf := tree.descendLeaf(req.MessageID)
+		if leaf == nil {
+			return bizerrors.New(bizerrors.CodeAIMessageNotFound)
+		}
+		if err := persistAILegacyParents(ctx, txAI, tree.legacyParents); err != nil {
+			return err
+		}
+		conversation.ActiveLeafID = leaf.ID
+		tree.activeLeafID = leaf.ID
+		return txAI.UpdateConversation(ctx, conversation)
+	})
+	if err != nil {
+		if bizErr := bizerrors.FromError(err); bizErr != nil {
+			return nil, bizErr
+		}
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	items, err := branchMessagesToResp(tree, tree.activeBranch())
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+	return items, nil
+}
+
+// persistAILegacyParents 负责在写事务内落库旧会话推断出的父指针。
+func persistAILegacyParents(ctx context.Context, txAI interfaces.AIRepository, messages []*entity.AIMessage) error {
+	for _, message := range messages {
+		if err := txAI.UpdateMessage(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
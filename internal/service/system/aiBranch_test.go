@@ -0,0 +1,92 @@
+package system
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"personal_assistant/internal/model/entity"
+)
+
+func newAIBranchTestMessage(id string, parentID string, role string, offset int) *entity.AIMessage {
+	createdAt := time.Unix(1700000000, 0).Add(time.Duration(offset) * time.Second)
+	return &entity.AIMessage{
+		ID:             id,
+		ParentID:       parentID,
+		Role:           role,
+		Content:        id,
+		Status:         aiMessageStatusSuccess,
+		TraceItemsJSON: "[]",
+		ScopeJSON:      "{}",
+		CreatedAt:      createdAt,
+		UpdatedAt:      createdAt,
+	}
+}
+
+func aiBranchMessageIDs(messages []*entity.AIMessage) []string {
+	ids := make([]string, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+	return ids
+}
+
+func TestAIMessageTreeInfersLegacyLinearParents(t *testing.T) {
+	messages := []*entity.AIMessage{
+		newAIBranchTestMessage("u1", "", "user", 0),
+		newAIBranchTestMessage("a1", "", "assistant", 1),
+		newAIBranchTestMessage("u2", "", "user", 2),
+		newAIBranchTestMessage("a2", "", "assistant", 3),
+	}
+
+	tree := newAIMessageTree(&entity.AIConversation{}, messages)
+	if got := aiBranchMessageIDs(tree.activeBranch()); !reflect.DeepEqual(got, []string{"u1", "a1", "u2", "a2"}) {
+		t.Fatalf("activeBranch = %v", got)
+	}
+	if len(tree.legacyParents) != 3 || messages[3].ParentID != "u2" {
+		t.Fatalf("legacyParents = %d, a2.parent = %q", len(tree.legacyParents), messages[3].ParentID)
+	}
+}
+
+func TestAIMessageTreeFollowsActiveLeafAndExposesSiblings(t *testing.T) {
+	messages := []*entity.AIMessage{
+		newAIBranchTestMessage("u1", "", "user", 0),
+		newAIBranchTestMessage("a1", "u1", "assistant", 1),
+		newAIBranchTestMessage("u2", "a1", "user", 2),
+		newAIBranchTestMessage("a2", "u2", "assistant", 3),
+		// a2b 是对 u2 的重新生成，u2b 是对 u2 的编辑重发。
+		newAIBranchTestMessage("a2b", "u2", "assistant", 4),
+		newAIBranchTestMessage("u2b", "a1", "user", 5),
+		newAIBranchTestMessage("a2c", "u2b", "assistant", 6),
+	}
+
+	tree := newAIMessageTree(&entity.AIConversation{ActiveLeafID: "a2b"}, messages)
+	if len(tree.legacyParents) != 0 {
+		t.Fatalf("legacyParents = %d, want 0", len(tree.legacyParents))
+	}
+	if got := aiBranchMessageIDs(tree.activeBranch()); !reflect.DeepEqual(got, []string{"u1", "a1", "u2", "a2b"}) {
+		t.Fatalf("activeBranch = %v", got)
+	}
+
+	items, err := branchMessagesToResp(tree, tree.activeBranch())
+	if err != nil {
+		t.Fatalf("branchMessagesToResp() error = %v", err)
+	}
+	if items[0].SiblingIDs != nil {
+		t.Fatalf("root sibling_ids = %v, want nil", items[0].SiblingIDs)
+	}
+	if !reflect.DeepEqual(items[2].SiblingIDs, []string{"u2", "u2b"}) {
+		t.Fatalf("u2 sibling_ids = %v", items[2].SiblingIDs)
+	}
+	if !reflect.DeepEqual(items[3].SiblingIDs, []string{"a2", "a2b"}) || items[3].ParentID != "u2" {
+		t.Fatalf("a2b = %+v", items[3])
+	}
+
+	// 切到编辑分支时沿最新子消息走到叶子。
+	if leaf := tree.descendLeaf("u2b"); leaf == nil || leaf.ID != "a2c" {
+		t.Fatalf("descendLeaf(u2b) = %+v", leaf)
+	}
+	if got := aiBranchMessageIDs(tree.pathTo("a1")); !reflect.DeepEqual(got, []string{"u1", "a1"}) {
+		t.Fatalf("pathTo(a1) = %v", got)
+	}
+}
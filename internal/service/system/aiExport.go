@@ -0,0 +1,527 @@
+package system
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	aidomain "personal_assistant/internal/domain/ai"
+	"personal_assistant/internal/model/dto/request"
+	resp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	bizerrors "personal_assistant/pkg/errors"
+)
+
+const (
+	// aiConversationExportFormat 是 JSON 导出文件的格式标识，导入时据此识别文件来源。
+	aiConversationExportFormat = "personal_assistant.conversation"
+	// aiConversationExportVersion 是当前导出文件的格式版本。
+	aiConversationExportVersion = 1
+	// aiConversationImportMaxMessages 限制单次导入的消息条数，避免一次请求写入过多数据。
+	aiConversationImportMaxMessages = 2000
+	// aiConversationImportDefaultTitle 是导入文件缺少标题时使用的会话标题。
+	aiConversationImportDefaultTitle = "导入的会话"
+
+	// aiConversationExportFormatMarkdown 表示导出为 Markdown 文本。
+	aiConversationExportFormatMarkdown = "markdown"
+	// aiConversationExportFormatJSON 表示导出为可再次导入的 JSON 文件。
+	aiConversationExportFormatJSON = "json"
+
+	aiExportContentTypeMarkdown = "text/markdown; charset=utf-8"
+	aiExportContentTypeJSON     = "application/json; charset=utf-8"
+	aiExportContentTypeZip      = "application/zip"
+	aiExportFileTitleMaxRunes   = 40
+)
+
+// ExportConversation 负责把单个会话导出为 Markdown 或 JSON 文件。
+// 参数：
+//   - format：markdown 或 json，为空时按 markdown 处理。
+//
+// 注意事项：
+//   - JSON 导出包含全部分支，保证导入后可以继续切换分支；Markdown 只渲染活动分支，便于阅读。
+func (s *AIService) ExportConversation(
+	ctx context.Context,
+	userID uint,
+	conversationID string,
+	format string,
+) (*resp.AssistantExportFile, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" || format == "md" {
+		format = aiConversationExportFormatMarkdown
+	}
+	if format != aiConversationExportFormatMarkdown && format != aiConversationExportFormatJSON {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "导出格式仅支持 markdown 或 json")
+	}
+
+	conversation, err := s.requireConversationOwner(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := s.aiRepo.ListMessagesByConversation(ctx, conversation.ID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	now := time.Now()
+	tree := newAIMessageTree(conversation, messages)
+	if format == aiConversationExportFormatJSON {
+		content, err := encodeAIConversationExportJSON(conversation, tree, now)
+		if err != nil {
+			return nil, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+		}
+		return &resp.AssistantExportFile{
+			FileName:    conversation.ID + ".json",
+			ContentType: aiExportContentTypeJSON,
+			Content:     content,
+		}, nil
+	}
+	return &resp.AssistantExportFile{
+		FileName:    conversation.ID + ".md",
+		ContentType: aiExportContentTypeMarkdown,
+		Content:     []byte(renderAIConversationMarkdown(conversation, tree, now)),
+	}, nil
+}
+
+// ExportAllConversations 负责把当前用户全部会话打包成 zip。
+// 每个会话同时写出 Markdown 与 JSON 两份文件，并附带 index.md 作为目录。
+// 注意事项：
+//   - 这里只读取会话列表，返回的 Write 再逐个会话读取消息并直接写入 zip，内存只保留当前会话。
+func (s *AIService) ExportAllConversations(ctx context.Context, userID uint) (*resp.AssistantExportFile, error) {
+	conversations, err := s.aiRepo.ListConversationsByUser(ctx, userID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	now := time.Now()
+	return &resp.AssistantExportFile{
+		FileName:    "ai-conversations-" + now.Format("20060102150405") + ".zip",
+		ContentType: aiExportContentTypeZip,
+		Write: func(w io.Writer) error {
+			archive := zip.NewWriter(w)
+			if err := s.writeAIConversationsZip(ctx, archive, conversations, now); err != nil {
+				return err
+			}
+			return archive.Close()
+		},
+	}, nil
+}
+
+// writeAIConversationsZip 逐个会话写出 Markdown 与 JSON 文件，最后写出目录 index.md。
+func (s *AIService) writeAIConversationsZip(
+	ctx context.Context,
+	archive *zip.Writer,
+	conversations []*entity.AIConversation,
+	now time.Time,
+) error {
+	var index strings.Builder
+	index.WriteString("# AI 会话导出\n\n")
+	index.WriteString(fmt.Sprintf("- 导出时间：%s\n- 会话数量：%d\n\n", now.Format(time.RFC3339), len(conversations)))
+
+	for _, conversation := range conversations {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		messages, err := s.aiRepo.ListMessagesByConversation(ctx, conversation.ID)
+		if err != nil {
+			return err
+		}
+		tree := newAIMessageTree(conversation, messages)
+		jsonContent, err := encodeAIConversationExportJSON(conversation, tree, now)
+		if err != nil {
+			return err
+		}
+
+		baseName := aiExportFileBaseName(conversation)
+		entries := []struct {
+			name    string
+			content []byte
+		}{
+			{name: "conversations/" + baseName + ".md", content: []byte(renderAIConversationMarkdown(conversation, tree, now))},
+			{name: "conversations/" + baseName + ".json", content: jsonContent},
+		}
+		for _, entry := range entries {
+			if err := writeAIExportZipEntry(archive, entry.name, entry.content, now); err != nil {
+				return err
+			}
+		}
+		index.WriteString(fmt.Sprintf(
+			"- [%s](conversations/%s.md) · %d 条消息 · %s\n",
+			escapeAIMarkdownLinkText(conversation.Title),
+			baseName,
+			len(tree.messages),
+			conversation.CreatedAt.Format(time.RFC3339),
+		))
+	}
+	return writeAIExportZipEntry(archive, "index.md", []byte(index.String()), now)
+}
+
+// ImportConversation 负责把 JSON 导出文件导入为当前用户的一个新会话。
+// 核心流程：
+//  1. 校验文件格式、版本和消息数量。
+//  2. 为会话和消息重新生成 ID，并按原父指针重建消息树。
+//  3. 在同一事务内写入会话和全部消息，避免出现只导入一半的会话。
+//
+// 注意事项：
+//   - 原文件中未完成的消息（生成中、待确认）统一记为 stopped，中断确认相关字段不会导入。
+func (s *AIService) ImportConversation(
+	ctx context.Context,
+	userID uint,
+	req *request.ImportAssistantConversationReq,
+) (*resp.AssistantConversationResp, error) {
+	if req == nil {
+		return nil, bizerrors.New(bizerrors.CodeInvalidParams)
+	}
+	if strings.TrimSpace(req.Format) != aiConversationExportFormat {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "不支持的导入文件格式")
+	}
+	if req.Version <= 0 || req.Version > aiConversationExportVersion {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "不支持的导入文件版本")
+	}
+	if len(req.Messages) == 0 {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "导入文件中没有消息")
+	}
+	if len(req.Messages) > aiConversationImportMaxMessages {
+		return nil, bizerrors.NewWithMsg(
+			bizerrors.CodeInvalidParams,
+			fmt.Sprintf("单次最多导入 %d 条消息", aiConversationImportMaxMessages),
+		)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if user == nil {
+		return nil, bizerrors.New(bizerrors.CodeUserNotFound)
+	}
+
+	now := time.Now()
+	conversation := &entity.AIConversation{
+		ID:        newAIID("conv"),
+		UserID:    userID,
+		OrgID:     user.CurrentOrgID,
+		Title:     aiConversationImportDefaultTitle,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if title := truncateRunes(req.Conversation.Title, 100); title != "" {
+		conversation.Title = title
+	}
+	messages, err := buildImportedAIMessages(conversation, req, now)
+	if err != nil {
+		return nil, err
+	}
+	branch := newAIMessageTree(conversation, messages).activeBranch()
+	for index := len(branch) - 1; index >= 0; index-- {
+		if message := branch[index]; message.Role == aidomain.RoleUser {
+			conversation.Preview = buildConversationPreview(message.Content)
+			break
+		}
+	}
+	// 导入时间作为最后消息时间，保证导入的会话出现在列表顶部。
+	conversation.LastMessageAt = &now
+
+	err = s.txRunner.InTx(ctx, func(tx any) error {
+		txAI := s.aiRepo.WithTx(tx)
+		if err := txAI.CreateConversation(ctx, conversation); err != nil {
+			return err
+		}
+		for _, message := range messages {
+			if err := txAI.CreateMessage(ctx, message); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return conversationToResp(conversation), nil
+}
+
+// buildImportedAIMessages 负责把导入文件中的消息转换成待落库的实体，并完成 ID 重映射。
+// 父消息必须出现在子消息之前；找不到父消息时挂到上一条消息下，保证结果仍是一棵树。
+func buildImportedAIMessages(
+	conversation *entity.AIConversation,
+	req *request.ImportAssistantConversationReq,
+	now time.Time,
+) ([]*entity.AIMessage, error) {
+	idMapping := make(map[string]string, len(req.Messages))
+	messages := make([]*entity.AIMessage, 0, len(req.Messages))
+	var previousAt time.Time
+	for index, item := range req.Messages {
+		sourceID := strings.TrimSpace(item.ID)
+		if sourceID == "" {
+			return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, fmt.Sprintf("第 %d 条消息缺少 id", index+1))
+		}
+		if _, ok := idMapping[sourceID]; ok {
+			return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, fmt.Sprintf("消息 id 重复：%s", sourceID))
+		}
+		role := strings.TrimSpace(item.Role)
+		if role != aidomain.RoleUser && role != aidomain.RoleAssistant {
+			return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, fmt.Sprintf("第 %d 条消息角色不合法", index+1))
+		}
+
+		message := &entity.AIMessage{
+			ConversationID: conversation.ID,
+			Role:           role,
+			Content:        item.Content,
+			Status:         normalizeImportedAIMessageStatus(role, item.Status),
+			ErrorText:      truncateRunes(item.ErrorText, 500),
+		}
+		if role == aidomain.RoleUser {
+			message.ID = newAIID("msg_user")
+		} else {
+			message.ID = newAIID("msg_ai")
+		}
+
+		parentID := strings.TrimSpace(item.ParentID)
+		switch mapped, ok := idMapping[parentID]; {
+		case parentID == "":
+			message.ParentID = ""
+		case ok:
+			message.ParentID = mapped
+		case len(messages) > 0:
+			message.ParentID = messages[len(messages)-1].ID
+		}
+
+		// 消息按创建时间排序读取，这里保证时间单调不减，避免导入后顺序错乱。
+		createdAt, err := time.Parse(time.RFC3339, strings.TrimSpace(item.CreatedAt))
+		if err != nil || createdAt.After(now) {
+			createdAt = now
+		}
+		if createdAt.Before(previousAt) {
+			createdAt = previousAt
+		}
+		previousAt = createdAt
+		message.CreatedAt = createdAt
+		message.UpdatedAt = createdAt
+
+		traceJSON, err := encodeImportedAITraceItems(item.TraceItems)
+		if err != nil {
+			return nil, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+		}
+		message.TraceItemsJSON = traceJSON
+		scopeJSON, err := encodeImportedAIScope(item.Scope)
+		if err != nil {
+			return nil, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+		}
+		message.ScopeJSON = scopeJSON
+
+		idMapping[sourceID] = message.ID
+		messages = append(messages, message)
+	}
+
+	conversation.ActiveLeafID = messages[len(messages)-1].ID
+	if mapped, ok := idMapping[strings.TrimSpace(req.Conversation.ActiveLeafID)]; ok {
+		conversation.ActiveLeafID = mapped
+	}
+	return messages, nil
+}
+
+// normalizeImportedAIMessageStatus 把导入消息的状态收敛到终态，未完成的回复无法在新会话里继续生成。
+func normalizeImportedAIMessageStatus(role string, status string) string {
+	if role == aidomain.RoleUser {
+		return aiMessageStatusSuccess
+	}
+	switch strings.TrimSpace(status) {
+	case aiMessageStatusSuccess, "":
+		return aiMessageStatusSuccess
+	case aiMessageStatusError:
+		return aiMessageStatusError
+	default:
+		return aiMessageStatusStopped
+	}
+}
+
+// encodeImportedAITraceItems 只保留轨迹的展示字段，中断 ID 与确认动作在新会话中没有意义。
+func encodeImportedAITraceItems(items []request.ImportAssistantTraceItem) (string, error) {
+	traceItems := make([]resp.AssistantTraceItem, 0, len(items))
+	for _, item := range items {
+		traceItems = append(traceItems, resp.AssistantTraceItem{
+			Key:            item.Key,
+			Title:          item.Title,
+			Description:    item.Description,
+			Status:         item.Status,
+			DurationMS:     item.DurationMS,
+			Content:        item.Content,
+			DetailMarkdown: item.DetailMarkdown,
+		})
+	}
+	raw, err := json.Marshal(traceItems)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// encodeImportedAIScope 负责把导入的业务上下文编码成 scope JSON，缺失时写入空对象。
+func encodeImportedAIScope(scope *request.ImportAssistantConversationScope) (string, error) {
+	if scope == nil {
+		return "{}", nil
+	}
+	raw, err := json.Marshal(resp.AssistantScopeInfo{
+		UserName:      scope.UserName,
+		OrgName:       scope.OrgName,
+		ScopeLabel:    scope.ScopeLabel,
+		TaskName:      scope.TaskName,
+		DocScopeLabel: scope.DocScopeLabel,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// encodeAIConversationExportJSON 负责生成会话的 JSON 导出内容，包含全部分支上的消息。
+func encodeAIConversationExportJSON(
+	conversation *entity.AIConversation,
+	tree *aiMessageTree,
+	exportedAt time.Time,
+) ([]byte, error) {
+	document := resp.AssistantConversationExport{
+		Format:     aiConversationExportFormat,
+		Version:    aiConversationExportVersion,
+		ExportedAt: exportedAt.Format(time.RFC3339),
+		Conversation: resp.AssistantConversationExportMeta{
+			ID:           conversation.ID,
+			Title:        conversation.Title,
+			CreatedAt:    conversation.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:    conversation.UpdatedAt.Format(time.RFC3339),
+			ActiveLeafID: lastAIMessageID(tree.activeBranch()),
+		},
+		Messages: make([]resp.AssistantConversationExportMessage, 0, len(tree.messages)),
+	}
+	for _, message := range tree.messages {
+		document.Messages = append(document.Messages, resp.AssistantConversationExportMessage{
+			ID:         message.ID,
+			ParentID:   message.ParentID,
+			Role:       message.Role,
+			Content:    message.Content,
+			Status:     message.Status,
+			CreatedAt:  message.CreatedAt.Format(time.RFC3339),
+			TraceItems: decodeAssistantTraceItems(message.TraceItemsJSON),
+			Scope:      decodeAssistantScope(message.ScopeJSON),
+			ErrorText:  message.ErrorText,
+		})
+	}
+	return json.MarshalIndent(document, "", "  ")
+}
+
+// renderAIConversationMarkdown 负责把会话活动分支渲染成 Markdown，工具调用轨迹折叠在 details 中。
+func renderAIConversationMarkdown(conversation *entity.AIConversation, tree *aiMessageTree, exportedAt time.Time) string {
+	var builder strings.Builder
+	builder.WriteString("# " + strings.TrimSpace(conversation.Title) + "\n\n")
+	builder.WriteString("- 会话 ID：" + conversation.ID + "\n")
+	builder.WriteString("- 创建时间：" + conversation.CreatedAt.Format(time.RFC3339) + "\n")
+	builder.WriteString("- 导出时间：" + exportedAt.Format(time.RFC3339) + "\n")
+
+	for _, message := range tree.activeBranch() {
+		builder.WriteString("\n---\n\n")
+		builder.WriteString("### " + aiExportRoleLabel(message.Role) + " · " + message.CreatedAt.Format(time.RFC3339) + "\n\n")
+		for _, item := range decodeAssistantTraceItems(message.TraceItemsJSON) {
+			renderAIExportTraceItem(&builder, item)
+		}
+		if content := strings.TrimSpace(message.Content); content != "" {
+			builder.WriteString(content + "\n")
+		}
+		if message.Role == aidomain.RoleAssistant && message.Status != aiMessageStatusSuccess {
+			note := "> 状态：" + aiExportStatusLabel(message.Status)
+			if errorText := strings.TrimSpace(message.ErrorText); errorText != "" {
+				note += "（" + errorText + "）"
+			}
+			builder.WriteString("\n" + note + "\n")
+		}
+	}
+	return builder.String()
+}
+
+// renderAIExportTraceItem 负责把单个轨迹节点渲染成可折叠的 details 区块。
+func renderAIExportTraceItem(builder *strings.Builder, item resp.AssistantTraceItem) {
+	summary := strings.TrimSpace(item.Title)
+	if summary == "" {
+		summary = item.Key
+	}
+	if status := strings.TrimSpace(item.Status); status != "" {
+		summary += " · " + aiExportStatusLabel(status)
+	}
+	if item.DurationMS > 0 {
+		summary += fmt.Sprintf(" · %dms", item.DurationMS)
+	}
+	builder.WriteString("<details>\n<summary>" + escapeAIExportHTML(summary) + "</summary>\n\n")
+	for _, part := range []string{item.Description, item.Content, item.DetailMarkdown} {
+		if part = strings.TrimSpace(part); part != "" {
+			builder.WriteString(part + "\n\n")
+		}
+	}
+	builder.WriteString("</details>\n\n")
+}
+
+func aiExportRoleLabel(role string) string {
+	switch role {
+	case aidomain.RoleUser:
+		return "用户"
+	case aidomain.RoleAssistant:
+		return "助手"
+	default:
+		return role
+	}
+}
+
+func aiExportStatusLabel(status string) string {
+	switch status {
+	case aiMessageStatusSuccess:
+		return "成功"
+	case aiMessageStatusError, "failed":
+		return "失败"
+	case aiMessageStatusStopped:
+		return "已停止"
+	case aiMessageStatusLoading, "running":
+		return "进行中"
+	case aiMessageStatusWaitingConfirmation, "waiting":
+		return "待确认"
+	default:
+		return status
+	}
+}
+
+func escapeAIExportHTML(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+func escapeAIMarkdownLinkText(text string) string {
+	return strings.NewReplacer("[", "\\[", "]", "\\]").Replace(strings.TrimSpace(text))
+}
+
+// aiExportFileBaseName 负责生成 zip 内的文件名：标题在前便于浏览，会话 ID 在后保证唯一。
+func aiExportFileBaseName(conversation *entity.AIConversation) string {
+	title := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, truncateRunes(conversation.Title, aiExportFileTitleMaxRunes))
+	title = strings.Trim(title, ". ")
+	if title == "" {
+		return conversation.ID
+	}
+	return title + "_" + conversation.ID
+}
+
+func writeAIExportZipEntry(archive *zip.Writer, name string, content []byte, modified time.Time) error {
+	writer, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(content)
+	return err
+}
@@ -0,0 +1,248 @@
+package system
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"personal_assistant/internal/model/dto/request"
+	resp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	reposystem "personal_assistant/internal/repository/system"
+	bizerrors "personal_assistant/pkg/errors"
+)
+
+func newAIExportTestService(t *testing.T) (*AIService, *entity.AIConversation) {
+	t.Helper()
+	db := newAIMemoryWritebackTestDB(t)
+	orgID := uint(3)
+	service := &AIService{
+		txRunner: &stubTxRunner{},
+		aiRepo:   reposystem.NewAIRepository(db),
+		userRepo: &stubUserRepository{user: &entity.User{CurrentOrgID: &orgID}},
+	}
+
+	conversation := &entity.AIConversation{ID: "conv_src", UserID: 7, Title: "两数之和", ActiveLeafID: "a2b"}
+	messages := []*entity.AIMessage{
+		newAIBranchTestMessage("u1", "", "user", 0),
+		newAIBranchTestMessage("a1", "u1", "assistant", 1),
+		newAIBranchTestMessage("u2", "a1", "user", 2),
+		newAIBranchTestMessage("a2", "u2", "assistant", 3),
+		newAIBranchTestMessage("a2b", "u2", "assistant", 4),
+	}
+	messages[1].TraceItemsJSON = `[{"key":"tool-1","title":"查询题目","description":"查询 P1001","status":"success","duration_ms":120,"detail_markdown":"| 题号 | 标题 |"}]`
+	messages[3].Status = aiMessageStatusWaitingConfirmation
+	messages[3].TraceItemsJSON = `[{"key":"tool-2","title":"创建任务","status":"waiting","interrupt_id":"intr_1","requires_confirmation":true,"actions":[{"key":"approve","label":"批准","action":"approve"}]}]`
+	messages[3].ScopeJSON = `{"user_name":"alice","org_name":"ACM","scope_label":"组织"}`
+
+	ctx := context.Background()
+	conversation.CreatedAt = messages[0].CreatedAt
+	conversation.UpdatedAt = messages[0].CreatedAt
+	if err := service.aiRepo.CreateConversation(ctx, conversation); err != nil {
+		t.Fatalf("CreateConversation() error = %v", err)
+	}
+	for _, message := range messages {
+		message.ConversationID = conversation.ID
+		if err := service.aiRepo.CreateMessage(ctx, message); err != nil {
+			t.Fatalf("CreateMessage(%s) error = %v", message.ID, err)
+		}
+	}
+	return service, conversation
+}
+
+func TestAIExportConversationJSONRoundTripsBranchesIntoNewConversation(t *testing.T) {
+	service, source := newAIExportTestService(t)
+	ctx := context.Background()
+
+	file, err := service.ExportConversation(ctx, 7, source.ID, "json")
+	if err != nil {
+		t.Fatalf("ExportConversation(json) error = %v", err)
+	}
+	if file.FileName != "conv_src.json" || !strings.HasPrefix(file.ContentType, "application/json") {
+		t.Fatalf("export file = %s %s", file.FileName, file.ContentType)
+	}
+	var req request.ImportAssistantConversationReq
+	if err := json.Unmarshal(file.Content, &req); err != nil {
+		t.Fatalf("unmarshal export: %v", err)
+	}
+	if req.Format != aiConversationExportFormat || req.Version != 1 || len(req.Messages) != 5 || req.Conversation.ActiveLeafID != "a2b" {
+		t.Fatalf("export document = %+v", req)
+	}
+
+	// 导入到另一个账号，原会话归属不应影响导入。
+	imported, err := service.ImportConversation(ctx, 9, &req)
+	if err != nil {
+		t.Fatalf("ImportConversation() error = %v", err)
+	}
+	if imported.ID == source.ID || imported.Title != "两数之和" || imported.Preview != "u2" {
+		t.Fatalf("imported conversation = %+v", imported)
+	}
+	conversation, err := service.aiRepo.GetConversationByID(ctx, imported.ID)
+	if err != nil || conversation == nil {
+		t.Fatalf("GetConversationByID() = %+v, %v", conversation, err)
+	}
+	if conversation.UserID != 9 || conversation.OrgID == nil || *conversation.OrgID != 3 {
+		t.Fatalf("stored conversation = %+v", conversation)
+	}
+
+	items, err := service.ListMessages(ctx, 9, imported.ID)
+	if err != nil {
+		t.Fatalf("ListMessages() error = %v", err)
+	}
+	contents := make([]string, 0, len(items))
+	for _, item := range items {
+		contents = append(contents, item.Content)
+		if strings.HasPrefix(item.ID, "u") || strings.HasPrefix(item.ID, "a") {
+			t.Fatalf("message id should be regenerated: %s", item.ID)
+		}
+	}
+	if !reflect.DeepEqual(contents, []string{"u1", "a1", "u2", "a2b"}) {
+		t.Fatalf("active branch = %v", contents)
+	}
+	if len(items[3].SiblingIDs) != 2 {
+		t.Fatalf("regenerated sibling should survive import: %+v", items[3])
+	}
+	if len(items[1].TraceItems) != 1 || items[1].TraceItems[0].DetailMarkdown != "| 题号 | 标题 |" {
+		t.Fatalf("trace items = %+v", items[1].TraceItems)
+	}
+
+	messages, err := service.aiRepo.ListMessagesByConversation(ctx, imported.ID)
+	if err != nil {
+		t.Fatalf("ListMessagesByConversation() error = %v", err)
+	}
+	waiting := messages[3]
+	if waiting.Content != "a2" || waiting.Status != aiMessageStatusStopped {
+		t.Fatalf("unfinished message = %+v", waiting)
+	}
+	traces := decodeAssistantTraceItems(waiting.TraceItemsJSON)
+	if len(traces) != 1 || traces[0].InterruptID != "" || traces[0].RequiresConfirmation || len(traces[0].Actions) != 0 {
+		t.Fatalf("confirmation fields should be dropped: %+v", traces)
+	}
+	if scope := decodeAssistantScope(waiting.ScopeJSON); scope == nil || scope.OrgName != "ACM" {
+		t.Fatalf("scope = %+v", scope)
+	}
+}
+
+func TestAIExportConversationMarkdownRendersActiveBranchAndTrace(t *testing.T) {
+	service, source := newAIExportTestService(t)
+
+	file, err := service.ExportConversation(context.Background(), 7, source.ID, "")
+	if err != nil {
+		t.Fatalf("ExportConversation(markdown) error = %v", err)
+	}
+	content := string(file.Content)
+	for _, want := range []string{
+		"# 两数之和",
+		"### 用户 · ",
+		"### 助手 · ",
+		"<summary>查询题目 · 成功 · 120ms</summary>",
+		"| 题号 | 标题 |",
+		"a2b",
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("markdown missing %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "创建任务") {
+		t.Fatalf("markdown should only render active branch:\n%s", content)
+	}
+
+	if _, err := service.ExportConversation(context.Background(), 8, source.ID, "json"); bizerrors.FromError(err) == nil ||
+		bizerrors.FromError(err).Code != bizerrors.CodeAIConversationNotFound {
+		t.Fatalf("ExportConversation(other user) error = %v", err)
+	}
+	if _, err := service.ExportConversation(context.Background(), 7, source.ID, "pdf"); bizerrors.FromError(err) == nil ||
+		bizerrors.FromError(err).Code != bizerrors.CodeInvalidParams {
+		t.Fatalf("ExportConversation(pdf) error = %v", err)
+	}
+}
+
+func TestAIExportAllConversationsBuildsZipWithIndex(t *testing.T) {
+	service, _ := newAIExportTestService(t)
+
+	file, err := service.ExportAllConversations(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("ExportAllConversations() error = %v", err)
+	}
+	var buffer bytes.Buffer
+	if err := file.Write(&buffer); err != nil {
+		t.Fatalf("write zip: %v", err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("open zip: %v", err)
+	}
+	names := make([]string, 0, len(reader.File))
+	var document resp.AssistantConversationExport
+	for _, entry := range reader.File {
+		names = append(names, entry.Name)
+		if strings.HasSuffix(entry.Name, ".json") {
+			handle, err := entry.Open()
+			if err != nil {
+				t.Fatalf("open %s: %v", entry.Name, err)
+			}
+			raw, _ := io.ReadAll(handle)
+			_ = handle.Close()
+			if err := json.Unmarshal(raw, &document); err != nil {
+				t.Fatalf("unmarshal %s: %v", entry.Name, err)
+			}
+		}
+	}
+	sort.Strings(names)
+	want := []string{
+		"conversations/两数之和_conv_src.json",
+		"conversations/两数之和_conv_src.md",
+		"index.md",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("zip entries = %v, want %v", names, want)
+	}
+	if document.Conversation.ID != "conv_src" || len(document.Messages) != 5 {
+		t.Fatalf("zip json document = %+v", document.Conversation)
+	}
+}
+
+func TestAIImportConversationRejectsInvalidDocuments(t *testing.T) {
+	service, _ := newAIExportTestService(t)
+	message := request.ImportAssistantConversationItem{ID: "u1", Role: "user", Content: "hi"}
+
+	for name, req := range map[string]*request.ImportAssistantConversationReq{
+		"format":    {Format: "other", Version: 1, Messages: []request.ImportAssistantConversationItem{message}},
+		"version":   {Format: aiConversationExportFormat, Version: 2, Messages: []request.ImportAssistantConversationItem{message}},
+		"empty":     {Format: aiConversationExportFormat, Version: 1},
+		"duplicate": {Format: aiConversationExportFormat, Version: 1, Messages: []request.ImportAssistantConversationItem{message, message}},
+	} {
+		_, err := service.ImportConversation(context.Background(), 7, req)
+		if bizErr := bizerrors.FromError(err); bizErr == nil || bizErr.Code != bizerrors.CodeInvalidParams {
+			t.Fatalf("ImportConversation(%s) error = %v, want invalid params", name, err)
+		}
+	}
+}
+
+func TestBuildImportedAIMessagesReattachesOrphansAndKeepsOrder(t *testing.T) {
+	conversation := &entity.AIConversation{ID: "conv_new"}
+	messages, err := buildImportedAIMessages(conversation, &request.ImportAssistantConversationReq{
+		Messages: []request.ImportAssistantConversationItem{
+			{ID: "u1", Role: "user", Content: "q1", CreatedAt: "2024-01-01T10:00:00Z"},
+			{ID: "a1", ParentID: "missing", Role: "assistant", Content: "r1", Status: "loading", CreatedAt: "2023-12-31T00:00:00Z"},
+		},
+	}, newAIBranchTestMessage("now", "", "user", 0).CreatedAt.AddDate(1, 0, 0))
+	if err != nil {
+		t.Fatalf("buildImportedAIMessages() error = %v", err)
+	}
+	if messages[1].ParentID != messages[0].ID || messages[1].Status != aiMessageStatusStopped {
+		t.Fatalf("orphan message = %+v", messages[1])
+	}
+	if messages[1].CreatedAt.Before(messages[0].CreatedAt) {
+		t.Fatalf("created_at should be monotonic: %v < %v", messages[1].CreatedAt, messages[0].CreatedAt)
+	}
+	if conversation.ActiveLeafID != messages[1].ID {
+		t.Fatalf("active leaf = %q, want last message", conversation.ActiveLeafID)
+	}
+}
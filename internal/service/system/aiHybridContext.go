@@ -57,6 +57,8 @@ type aiHybridContextDiagnostics struct {
 	CurrentQueryInHistory     bool
 	VisibleTools              int
 	RAGRemainingChars         int
+	// RAGTrace 是长期文档召回的逐条明细，开启 recall_trace 时写入 trace 详情。
+	RAGTrace []aiMemoryRAGTraceEntry
 }
 
 type defaultAIHybridContextPlanner struct{}
@@ -142,7 +144,7 @@ func buildAIMemoryContextContent(
 
 	factLines := renderSortedAIMemoryFactLines(facts)
 	diagnostics.FactCandidates = len(factLines)
-	ragLines := renderSortedAIMemoryRAGLines(ragItems)
+	ragLines, ragRendered := renderSortedAIMemoryRAGLines(ragItems)
 	diagnostics.RAGCandidates = len(ragLines)
 	if summaryText == "" && len(keyPoints) == 0 && len(openLoops) == 0 && len(factLines) == 0 && len(ragLines) == 0 {
 		return "", diagnostics
@@ -207,6 +209,7 @@ func buildAIMemoryContextContent(
 		}
 	}
 	diagnostics.RAGDropped = diagnostics.RAGCandidates - diagnostics.RAGKept
+	diagnostics.RAGTrace = buildAIMemoryRAGTraceEntries(ragRendered, diagnostics.RAGKept)
 
 	content := strings.TrimRight(builder.String(), "\n")
 	diagnostics.MemoryChars = utf8.RuneCountInString(content)
@@ -271,9 +274,14 @@ func renderAIMemoryFactLine(fact *entity.AIMemoryFact) (string, bool) {
 	return fmt.Sprintf("%s/%s: %s", namespace, factKey, value), true
 }
 
-func renderSortedAIMemoryRAGLines(items []aiMemoryRAGRecallItem) []string {
+// renderSortedAIMemoryRAGLines 按融合名次渲染召回片段，同时返回与行一一对应的片段供 trace 使用。
+// 没有融合名次的片段（例如测试直接构造）按向量得分排序。
+func renderSortedAIMemoryRAGLines(items []aiMemoryRAGRecallItem) ([]string, []aiMemoryRAGRecallItem) {
 	sorted := append([]aiMemoryRAGRecallItem(nil), items...)
 	sort.SliceStable(sorted, func(i, j int) bool {
+		if leftRank, rightRank := sorted[i].Debug.Rank, sorted[j].Debug.Rank; leftRank > 0 && rightRank > 0 && leftRank != rightRank {
+			return leftRank < rightRank
+		}
 		if sorted[i].Score != sorted[j].Score {
 			return sorted[i].Score > sorted[j].Score
 		}
@@ -289,13 +297,15 @@ func renderSortedAIMemoryRAGLines(items []aiMemoryRAGRecallItem) []string {
 	})
 
 	lines := make([]string, 0, len(sorted))
+	rendered := make([]aiMemoryRAGRecallItem, 0, len(sorted))
 	for _, item := range sorted {
 		line, ok := renderAIMemoryRAGLine(item)
 		if ok {
 			lines = append(lines, line)
+			rendered = append(rendered, item)
 		}
 	}
-	return lines
+	return lines, rendered
 }
 
 func renderAIMemoryRAGLine(item aiMemoryRAGRecallItem) (string, bool) {
@@ -325,7 +335,11 @@ func renderAIMemoryRAGLine(item aiMemoryRAGRecallItem) (string, bool) {
 			}
 			builder.WriteString(topic)
 		}
-		builder.WriteString(fmt.Sprintf(" score=%.3f] ", item.Score))
+		// 只被关键词命中的片段没有向量分，不输出 score。
+		if item.Score > 0 {
+			builder.WriteString(fmt.Sprintf(" score=%.3f", item.Score))
+		}
+		builder.WriteString("] ")
 	}
 	builder.WriteString(content)
 	return builder.String(), true
@@ -338,7 +352,7 @@ func aiMemorySourcePriority(sourceKind string) int {
 		return 0
 	case string(aidomain.MemorySourceToolVerifiedSummary):
 		return 1
-	case string(aidomain.MemorySourceAdminSet):
+	case string(aidomain.MemorySourceAdminSet), string(aidomain.MemorySourceKnowledgeBase):
 		return 2
 	case string(aidomain.MemorySourceModelInferred):
 		return 3
@@ -0,0 +1,408 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	aidomain "personal_assistant/internal/domain/ai"
+	streamsse "personal_assistant/internal/infrastructure/sse"
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository/interfaces"
+	bizerrors "personal_assistant/pkg/errors"
+)
+
+// aiInterruptEnvelope 是写入 AIInterrupt.RuntimeStateJSON 的持久化信封。
+// Service 自己需要的恢复上下文放在信封字段里，runtime 私有快照原样放在 RuntimeState。
+type aiInterruptEnvelope struct {
+	// UserMessageID 是触发本轮对话的用户消息，恢复后用于记忆写回。
+	UserMessageID string `json:"user_message_id"`
+	// ToolNames 是中断时本轮暴露给模型的工具名，恢复时按它重建工具集合。
+	ToolNames []string `json:"tool_names"`
+	// ToolName 是等待确认的工具名。
+	ToolName string `json:"tool_name"`
+	// ArgumentsJSON 是等待确认的工具参数，便于审计。
+	ArgumentsJSON string `json:"arguments_json"`
+	// Confirmation 是展示给用户的确认信息。
+	Confirmation aidomain.ToolConfirmation `json:"confirmation"`
+	// Runtime 是产生快照的 runtime 名称，快照格式由 runtime 私有，只能交回同一种 runtime 恢复。
+	Runtime string `json:"runtime"`
+	// RuntimeState 是 runtime 返回的不透明恢复快照。
+	RuntimeState string `json:"runtime_state"`
+}
+
+// DecideInterrupt 负责处理用户对待确认工具调用的决策，并以 SSE 形式继续本轮生成。
+// 参数：
+//   - ctx：本次流式请求的上下文。
+//   - userID：当前用户 ID。
+//   - conversationID：目标会话 ID。
+//   - interruptID：待决策的中断 ID。
+//   - req：决策请求。
+//   - writer：SSE 输出器。
+//
+// 核心流程：
+//  1. 校验 runtime 恢复能力、会话归属、中断状态和决策参数。
+//  2. 重新构造 principal 与可见工具，批准时确认被拦截工具仍然可见。
+//  3. 事务内锁定中断与会话，写入决策并把会话切到生成中。
+//  4. 在原 assistant 消息上恢复 runtime，结束后复用 finishStream 收尾。
+//
+// 注意事项：
+//   - 工具集合按当前权限重新过滤，权限在等待期间被收回时批准会被拒绝。
+func (s *AIService) DecideInterrupt(
+	ctx context.Context,
+	userID uint,
+	conversationID string,
+	interruptID string,
+	req *request.DecideAssistantInterruptReq,
+	writer streamsse.StreamWriter,
+) error {
+	// 第一阶段：挡住非法输入和不支持恢复的 runtime。
+	if req == nil {
+		return bizerrors.New(bizerrors.CodeInvalidParams)
+	}
+	decision := strings.TrimSpace(req.Decision)
+	if decision != aidomain.InterruptDecisionApprove && decision != aidomain.InterruptDecisionReject {
+		return bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "decision 仅支持 approve 或 reject")
+	}
+	if writer == nil {
+		return bizerrors.New(bizerrors.CodeAIStreamingUnsupported)
+	}
+	resumable, ok := s.runtime.(aidomain.ResumableRuntime)
+	if !ok || resumable == nil {
+		return bizerrors.New(bizerrors.CodeAIInterruptUnavailable)
+	}
+
+	// 第二阶段：读取会话、中断和消息，确认它们属于同一轮对话且中断仍待处理。
+	conversation, err := s.requireConversationOwner(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+	if conversation.IsGenerating {
+		return bizerrors.New(bizerrors.CodeAIConversationBusy)
+	}
+	interrupt, err := s.aiRepo.GetInterruptByID(ctx, interruptID)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if interrupt == nil || interrupt.ConversationID != conversation.ID || interrupt.UserID != userID {
+		return bizerrors.New(bizerrors.CodeAIInterruptNotFound)
+	}
+	if interrupt.Status != aidomain.InterruptStatusPending {
+		return bizerrors.New(bizerrors.CodeAIInterruptConflict)
+	}
+	// 快照随信封持久化在库中，任意节点都能恢复；只要求 runtime 种类一致。
+	var envelope aiInterruptEnvelope
+	if err := json.Unmarshal([]byte(interrupt.RuntimeStateJSON), &envelope); err != nil {
+		return bizerrors.Wrap(bizerrors.CodeAIInterruptUnavailable, err)
+	}
+	if envelope.Runtime != "" && envelope.Runtime != s.runtime.Name() {
+		return bizerrors.New(bizerrors.CodeAIInterruptUnavailable)
+	}
+	assistantMessage, err := s.aiRepo.GetMessageByID(ctx, interrupt.MessageID)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if assistantMessage == nil || assistantMessage.ConversationID != conversation.ID {
+		return bizerrors.New(bizerrors.CodeAIInterruptNotFound)
+	}
+	userMessage, err := s.aiRepo.GetMessageByID(ctx, envelope.UserMessageID)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if user == nil {
+		return bizerrors.New(bizerrors.CodeUserNotFound)
+	}
+
+	// 第三阶段：按当前权限重建工具集合，批准时被拦截工具必须仍然可见。
+	toolPrincipal, err := s.buildAIToolPrincipal(ctx, user)
+	if err != nil {
+		return err
+	}
+	toolCallCtx := aidomain.ToolCallContext{
+		ConversationID:     conversation.ID,
+		UserMessageID:      envelope.UserMessageID,
+		AssistantMessageID: assistantMessage.ID,
+		Principal:          toolPrincipal,
+	}
+	visibleTools, err := s.filterVisibleAITools(ctx, toolCallCtx)
+	if err != nil {
+		return err
+	}
+	tools := selectAIToolsByName(visibleTools, envelope.ToolNames)
+	if decision == aidomain.InterruptDecisionApprove && !containsAITool(tools, envelope.ToolName) {
+		return bizerrors.NewWithMsg(bizerrors.CodePermissionDenied, "当前账号已无权执行该工具调用")
+	}
+
+	// 第四阶段：事务内写入决策并占用会话，避免并发决策或并发新消息。
+	now := time.Now()
+	if err := s.persistInterruptDecision(ctx, conversation, interrupt, assistantMessage, decision, req.Reason, now); err != nil {
+		return err
+	}
+
+	sink := newAIResumeStreamSink(s.aiRepo, writer, assistantMessage)
+	execErr := sink.Emit(ctx, aidomain.Event{
+		Name: aidomain.EventToolCallConfirmationResult,
+		Payload: aidomain.ToolCallConfirmationResultPayload{
+			Key:         interrupt.ToolKey,
+			InterruptID: interrupt.InterruptID,
+			Decision:    decision,
+			Reason:      strings.TrimSpace(req.Reason),
+		},
+	})
+	var result aidomain.StreamResult
+	if execErr == nil {
+		result, execErr = resumable.Resume(ctx, aidomain.ResumeInput{
+			UserID:             userID,
+			ConversationID:     conversation.ID,
+			AssistantMessageID: assistantMessage.ID,
+			Tools:              tools,
+			ToolCallContext:    toolCallCtx,
+			StateJSON:          envelope.RuntimeState,
+			Decision:           decision,
+			Reason:             strings.TrimSpace(req.Reason),
+		}, sink)
+	}
+	if execErr == nil && result.Interrupt != nil {
+		// 恢复后同一轮再次命中待确认工具时，继续以新的中断收尾。
+		execErr = s.suspendForInterrupt(ctx, conversation, envelope.UserMessageID, assistantMessage.ID, sink, result.Interrupt, tools)
+	}
+
+	// 第五阶段：复用普通流式收尾逻辑，成功完成时补做记忆写回。
+	if finishErr := s.finishStream(ctx, conversation, sink, execErr); finishErr != nil {
+		return finishErr
+	}
+	if execErr == nil && result.Interrupt == nil {
+		s.triggerMemoryWriteback(ctx, conversation, userMessage, assistantMessage, toolPrincipal)
+	}
+	return nil
+}
+
+// persistInterruptDecision 负责在事务内写入用户决策并把会话切到生成中。
+func (s *AIService) persistInterruptDecision(
+	ctx context.Context,
+	conversation *entity.AIConversation,
+	interrupt *entity.AIInterrupt,
+	assistantMessage *entity.AIMessage,
+	decision string,
+	reason string,
+	now time.Time,
+) error {
+	err := s.txRunner.InTx(ctx, func(tx any) error {
+		txAI := s.aiRepo.WithTx(tx)
+		lockedInterrupt, err := txAI.GetInterruptByIDForUpdate(ctx, interrupt.InterruptID)
+		if err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		if lockedInterrupt == nil {
+			return bizerrors.New(bizerrors.CodeAIInterruptNotFound)
+		}
+		if lockedInterrupt.Status != aidomain.InterruptStatusPending {
+			return bizerrors.New(bizerrors.CodeAIInterruptConflict)
+		}
+		lockedConversation, err := txAI.GetConversationByIDForUpdate(ctx, conversation.ID)
+		if err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		if lockedConversation == nil || lockedConversation.UserID != conversation.UserID {
+			return bizerrors.New(bizerrors.CodeAIConversationNotFound)
+		}
+		if lockedConversation.IsGenerating {
+			return bizerrors.New(bizerrors.CodeAIConversationBusy)
+		}
+
+		lockedInterrupt.Status = aidomain.InterruptStatusApproved
+		if decision == aidomain.InterruptDecisionReject {
+			lockedInterrupt.Status = aidomain.InterruptStatusRejected
+		}
+		lockedInterrupt.Decision = decision
+		lockedInterrupt.Reason = truncateRunes(reason, 500)
+		lockedInterrupt.UpdatedAt = now
+		if err := txAI.UpdateInterrupt(ctx, lockedInterrupt); err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+
+		lockedConversation.IsGenerating = true
+		lockedConversation.LastMessageAt = &now
+		lockedConversation.UpdatedAt = now
+		if err := txAI.UpdateConversation(ctx, lockedConversation); err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+
+		assistantMessage.Status = aiMessageStatusLoading
+		assistantMessage.UpdatedAt = now
+		if err := txAI.UpdateMessage(ctx, assistantMessage); err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		*interrupt = *lockedInterrupt
+		*conversation = *lockedConversation
+		return nil
+	})
+	if err != nil {
+		if bizerrors.FromError(err) != nil {
+			return err
+		}
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return nil
+}
+
+// suspendForInterrupt 负责持久化 runtime 中断，并向前端发出待确认事件和 done。
+func (s *AIService) suspendForInterrupt(
+	ctx context.Context,
+	conversation *entity.AIConversation,
+	userMessageID string,
+	assistantMessageID string,
+	sink *aiStreamSink,
+	runtimeInterrupt *aidomain.RuntimeInterrupt,
+	tools []aidomain.Tool,
+) error {
+	envelope := aiInterruptEnvelope{
+		UserMessageID: userMessageID,
+		ToolNames:     aiToolNames(tools),
+		ToolName:      runtimeInterrupt.ToolName,
+		ArgumentsJSON: runtimeInterrupt.ArgumentsJSON,
+		Confirmation:  runtimeInterrupt.Confirmation,
+		Runtime:       s.runtime.Name(),
+		RuntimeState:  runtimeInterrupt.StateJSON,
+	}
+	raw, err := json.Marshal(envelope)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+
+	now := time.Now()
+	interrupt := &entity.AIInterrupt{
+		InterruptID:      newAIID("intr"),
+		ConversationID:   conversation.ID,
+		MessageID:        assistantMessageID,
+		UserID:           conversation.UserID,
+		Status:           aidomain.InterruptStatusPending,
+		ToolKey:          runtimeInterrupt.ToolCallKey,
+		RuntimeStateJSON: string(raw),
+		OwnerNodeID:      aiNodeID(),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := s.aiRepo.CreateInterrupt(ctx, interrupt); err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	if err := sink.Emit(ctx, aidomain.Event{
+		Name: aidomain.EventToolCallWaitingConfirmation,
+		Payload: aidomain.ToolCallWaitingConfirmationPayload{
+			Key:            runtimeInterrupt.ToolCallKey,
+			InterruptID:    interrupt.InterruptID,
+			ToolName:       runtimeInterrupt.ToolName,
+			Title:          runtimeInterrupt.Confirmation.Title,
+			Description:    runtimeInterrupt.Confirmation.Description,
+			DetailMarkdown: runtimeInterrupt.Confirmation.DetailMarkdown,
+		},
+	}); err != nil {
+		return err
+	}
+	return sink.Emit(ctx, aidomain.Event{Name: aidomain.EventDone, Payload: map[string]any{}})
+}
+
+// cancelPendingAIInterrupts 负责在事务内作废会话下仍待确认的中断，并把对应消息标记为停止。
+func cancelPendingAIInterrupts(ctx context.Context, txAI interfaces.AIRepository, conversationID string) error {
+	interrupts, err := txAI.ListInterruptsByConversationAndStatuses(
+		ctx,
+		conversationID,
+		[]string{aidomain.InterruptStatusPending},
+	)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, interrupt := range interrupts {
+		if interrupt == nil {
+			continue
+		}
+		interrupt.Status = aidomain.InterruptStatusCancelled
+		interrupt.UpdatedAt = now
+		if err := txAI.UpdateInterrupt(ctx, interrupt); err != nil {
+			return err
+		}
+
+		message, err := txAI.GetMessageByID(ctx, interrupt.MessageID)
+		if err != nil {
+			return err
+		}
+		if message == nil || message.Status != aiMessageStatusWaitingConfirmation {
+			continue
+		}
+		message.Status = aiMessageStatusStopped
+		message.UpdatedAt = now
+		if err := txAI.UpdateMessage(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aiToolNames 负责提取工具集合中的工具名。
+func aiToolNames(tools []aidomain.Tool) []string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		if tool == nil {
+			continue
+		}
+		names = append(names, strings.TrimSpace(tool.Spec().Name))
+	}
+	return names
+}
+
+// selectAIToolsByName 负责按名称从当前可见工具中挑出中断时暴露过的工具。
+func selectAIToolsByName(tools []aidomain.Tool, names []string) []aidomain.Tool {
+	allowed := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		allowed[strings.TrimSpace(name)] = struct{}{}
+	}
+	selected := make([]aidomain.Tool, 0, len(names))
+	for _, tool := range tools {
+		if tool == nil {
+			continue
+		}
+		if _, ok := allowed[strings.TrimSpace(tool.Spec().Name)]; ok {
+			selected = append(selected, tool)
+		}
+	}
+	return selected
+}
+
+// containsAITool 判断工具集合中是否包含指定名称的工具。
+func containsAITool(tools []aidomain.Tool, name string) bool {
+	name = strings.TrimSpace(name)
+	for _, tool := range tools {
+		if tool != nil && strings.TrimSpace(tool.Spec().Name) == name {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	aiNodeIDOnce  sync.Once
+	aiNodeIDValue string
+)
+
+// aiNodeID 返回当前进程的节点标识（主机名:进程号），写入 AIInterrupt.OwnerNodeID 供排障定位。
+func aiNodeID() string {
+	aiNodeIDOnce.Do(func() {
+		host, err := os.Hostname()
+		if err != nil || strings.TrimSpace(host) == "" {
+			host = "unknown"
+		}
+		aiNodeIDValue = fmt.Sprintf("%s:%d", host, os.Getpid())
+	})
+	return aiNodeIDValue
+}
@@ -0,0 +1,522 @@
+package system
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	aidomain "personal_assistant/internal/domain/ai"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	resp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository"
+	svccontract "personal_assistant/internal/service/contract"
+	bizerrors "personal_assistant/pkg/errors"
+)
+
+const (
+	// aiKnowledgeMaxContentBytes 限制单份知识库文档正文大小，避免一次导入产生过多 chunk。
+	aiKnowledgeMaxContentBytes = 1 << 20
+	// aiKnowledgeSummaryRunes 是未填写摘要时从正文截取的字符数。
+	aiKnowledgeSummaryRunes = 200
+	// aiKnowledgeDocumentImportance 是管理员导入文档的默认重要度，高于 LLM 抽取的文档。
+	aiKnowledgeDocumentImportance = 1
+)
+
+// AIKnowledgeService 负责组织知识库文档的导入、重新导入、版本追溯和召回统计。
+// 知识库文档直接复用长期记忆文档表和 chunk / embedding / Qdrant 索引流程，
+// 以 org scope + org visibility 落库，因此召回侧只需按组织作用域检索即可命中。
+type AIKnowledgeService struct {
+	// memory 提供记忆仓储、索引触发和向量删除能力。
+	memory *AIMemoryService
+	// txRunner 保证文档元数据和版本快照在同一事务内落库。
+	txRunner repository.TxRunner
+	// authorizationService 用于校验组织知识库管理能力。
+	authorizationService svccontract.AuthorizationServiceContract
+}
+
+// NewAIKnowledgeService 基于记忆服务、事务执行器和授权服务构造知识库服务。
+func NewAIKnowledgeService(
+	memory *AIMemoryService,
+	txRunner repository.TxRunner,
+	authorizationService svccontract.AuthorizationServiceContract,
+) *AIKnowledgeService {
+	return &AIKnowledgeService{
+		memory:               memory,
+		txRunner:             txRunner,
+		authorizationService: authorizationService,
+	}
+}
+
+// ListDocuments 分页查询组织知识库文档，附带当前版本号和召回统计。
+func (s *AIKnowledgeService) ListDocuments(
+	ctx context.Context,
+	userID uint,
+	req *request.AIKnowledgeDocumentListReq,
+) (*resp.AIKnowledgeDocumentListResp, error) {
+	if req == nil || req.OrgID == 0 {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "org_id 不能为空")
+	}
+	if err := s.authorizeOrg(ctx, userID, req.OrgID); err != nil {
+		return nil, err
+	}
+	page, pageSize := normalizeAIMemoryPage(req.Page, req.PageSize)
+	rows, total, err := s.memory.repo.PageDocuments(ctx, aidomain.MemoryManageQuery{
+		ScopeKey:   aidomain.BuildOrgMemoryScopeKey(req.OrgID),
+		Visibility: aidomain.MemoryVisibilityOrg,
+		Keyword:    req.Keyword,
+		MemoryType: aidomain.MemoryType(strings.TrimSpace(req.MemoryType)),
+		SourceKind: aidomain.MemorySourceKnowledgeBase,
+		Offset:     (page - 1) * pageSize,
+		Limit:      pageSize,
+	})
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	versions, err := s.memory.repo.ListLatestDocumentVersionNos(ctx, ids)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	stats, err := s.memory.repo.ListDocumentRecallStats(ctx, ids)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	list := make([]*resp.AIKnowledgeDocumentResp, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, aiKnowledgeDocumentToResp(row, versions[row.ID], stats[row.ID]))
+	}
+	return &resp.AIKnowledgeDocumentListResp{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// CreateDocument 导入一份组织知识库文档。
+// 核心流程：
+//  1. 校验组织知识库管理能力，规范化正文并补齐默认摘要。
+//  2. 在同一事务内写入长期记忆文档和版本 1 快照。
+//  3. 触发异步建索引；索引未就绪时由补偿任务按 updated_at 兜底。
+func (s *AIKnowledgeService) CreateDocument(
+	ctx context.Context,
+	userID uint,
+	req *request.CreateAIKnowledgeDocumentReq,
+) (*resp.AIKnowledgeDocumentResp, error) {
+	if req == nil || req.OrgID == 0 {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "org_id 不能为空")
+	}
+	memoryType, err := normalizeAIKnowledgeMemoryType(req.MemoryType)
+	if err != nil {
+		return nil, err
+	}
+	format, err := normalizeAIKnowledgeFormat(req.Format, consts.AIKnowledgeFormatMarkdown)
+	if err != nil {
+		return nil, err
+	}
+	content, err := normalizeAIKnowledgeContent(req.ContentText)
+	if err != nil {
+		return nil, err
+	}
+	topic := strings.TrimSpace(req.Topic)
+	title := strings.TrimSpace(req.Title)
+	if topic == "" || title == "" {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "topic 和 title 不能为空")
+	}
+	if err := s.authorizeOrg(ctx, userID, req.OrgID); err != nil {
+		return nil, err
+	}
+
+	orgID := req.OrgID
+	scopeKey := aidomain.BuildOrgMemoryScopeKey(orgID)
+	sourceID := newAIID("kb")
+	summary := buildAIKnowledgeSummary(req.Summary, content)
+	dedupKey := aidomain.BuildMemoryDocumentDedupKey(aidomain.MemorySourceKnowledgeBase, sourceID, topic, summary, content)
+	now := time.Now()
+	doc := &entity.AIMemoryDocument{
+		ID:           buildMemoryDocumentID(scopeKey, dedupKey),
+		ScopeKey:     scopeKey,
+		ScopeType:    string(aidomain.MemoryScopeOrg),
+		Visibility:   string(aidomain.MemoryVisibilityOrg),
+		OrgID:        &orgID,
+		MemoryType:   string(memoryType),
+		Topic:        topic,
+		Title:        title,
+		Summary:      summary,
+		ContentText:  content,
+		DedupKey:     dedupKey,
+		Importance:   aiKnowledgeDocumentImportance,
+		QualityScore: aiKnowledgeDocumentImportance,
+		SourceKind:   string(aidomain.MemorySourceKnowledgeBase),
+		SourceID:     sourceID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	version := buildAIKnowledgeVersion(doc, 1, format, req.ChangeNote, userID)
+	if err := s.txRunner.InTx(ctx, func(tx any) error {
+		repo := s.memory.repo.WithTx(tx)
+		if err := repo.BatchUpsertDocuments(ctx, []*entity.AIMemoryDocument{doc}); err != nil {
+			return err
+		}
+		return repo.CreateDocumentVersion(ctx, version)
+	}); err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	s.memory.triggerDocumentIndex(ctx, []*entity.AIMemoryDocument{doc})
+
+	item := aiKnowledgeDocumentToResp(doc, version.VersionNo, nil)
+	item.Format = version.Format
+	item.ContentText = doc.ContentText
+	return item, nil
+}
+
+// GetDocument 返回知识库文档详情，包含正文、当前格式、已索引片段数和召回统计。
+func (s *AIKnowledgeService) GetDocument(
+	ctx context.Context,
+	userID uint,
+	documentID string,
+) (*resp.AIKnowledgeDocumentResp, error) {
+	doc, err := s.loadDocument(ctx, userID, documentID)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.loadLatestVersion(ctx, doc.ID)
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := s.memory.repo.ListDocumentChunks(ctx, doc.ID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	stats, err := s.memory.repo.ListDocumentRecallStats(ctx, []string{doc.ID})
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	versionNo := 0
+	if latest != nil {
+		versionNo = latest.VersionNo
+	}
+	item := aiKnowledgeDocumentToResp(doc, versionNo, stats[doc.ID])
+	if latest != nil {
+		item.Format = latest.Format
+	}
+	item.ContentText = doc.ContentText
+	item.ChunkCount = len(chunks)
+	return item, nil
+}
+
+// UpdateDocument 重新导入一份知识库文档，生成新版本并重建向量索引。
+// 核心流程：
+//  1. 未填写的字段沿用当前版本；内容和元数据都没有变化时直接返回，不产生空版本。
+//  2. 先同步清掉旧向量和旧 chunks，避免召回继续命中旧版本内容。
+//  3. 在同一事务内更新文档并写入新版本快照，最后触发异步重建索引。
+func (s *AIKnowledgeService) UpdateDocument(
+	ctx context.Context,
+	userID uint,
+	documentID string,
+	req *request.UpdateAIKnowledgeDocumentReq,
+) (*resp.AIKnowledgeDocumentResp, error) {
+	if req == nil {
+		return nil, bizerrors.New(bizerrors.CodeInvalidParams)
+	}
+	doc, err := s.loadDocument(ctx, userID, documentID)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.loadLatestVersion(ctx, doc.ID)
+	if err != nil {
+		return nil, err
+	}
+	currentVersionNo, currentFormat := 0, consts.AIKnowledgeFormatMarkdown
+	if latest != nil {
+		currentVersionNo, currentFormat = latest.VersionNo, consts.AIKnowledgeFormat(latest.Format)
+	}
+
+	next := *doc
+	if strings.TrimSpace(req.MemoryType) != "" {
+		memoryType, err := normalizeAIKnowledgeMemoryType(req.MemoryType)
+		if err != nil {
+			return nil, err
+		}
+		next.MemoryType = string(memoryType)
+	}
+	if topic := strings.TrimSpace(req.Topic); topic != "" {
+		next.Topic = topic
+	}
+	if title := strings.TrimSpace(req.Title); title != "" {
+		next.Title = title
+	}
+	format, err := normalizeAIKnowledgeFormat(req.Format, currentFormat)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.ContentText) != "" {
+		content, err := normalizeAIKnowledgeContent(req.ContentText)
+		if err != nil {
+			return nil, err
+		}
+		next.ContentText = content
+	}
+	if summary := strings.TrimSpace(req.Summary); summary != "" {
+		next.Summary = summary
+	} else if next.ContentText != doc.ContentText {
+		next.Summary = buildAIKnowledgeSummary("", next.ContentText)
+	}
+
+	if next.MemoryType == doc.MemoryType &&
+		next.Topic == doc.Topic &&
+		next.Title == doc.Title &&
+		next.Summary == doc.Summary &&
+		next.ContentText == doc.ContentText &&
+		format == currentFormat {
+		return s.GetDocument(ctx, userID, doc.ID)
+	}
+
+	if err := s.memory.dropDocumentIndex(ctx, doc.ID); err != nil {
+		return nil, err
+	}
+	version := buildAIKnowledgeVersion(&next, currentVersionNo+1, format, req.ChangeNote, userID)
+	if err := s.txRunner.InTx(ctx, func(tx any) error {
+		repo := s.memory.repo.WithTx(tx)
+		if err := repo.UpdateDocumentContent(ctx, &next); err != nil {
+			return err
+		}
+		return repo.CreateDocumentVersion(ctx, version)
+	}); err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	s.memory.triggerDocumentIndex(ctx, []*entity.AIMemoryDocument{&next})
+
+	stats, err := s.memory.repo.ListDocumentRecallStats(ctx, []string{doc.ID})
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	next.UpdatedAt = time.Now()
+	item := aiKnowledgeDocumentToResp(&next, version.VersionNo, stats[doc.ID])
+	item.Format = version.Format
+	item.ContentText = next.ContentText
+	return item, nil
+}
+
+// DeleteDocument 删除知识库文档，级联删除向量、chunks、版本快照和召回统计。
+func (s *AIKnowledgeService) DeleteDocument(ctx context.Context, userID uint, documentID string) error {
+	doc, err := s.loadDocument(ctx, userID, documentID)
+	if err != nil {
+		return err
+	}
+	return s.memory.purgeDocuments(ctx, []string{doc.ID})
+}
+
+// ListVersions 按版本号倒序返回知识库文档的版本列表，不含正文快照。
+func (s *AIKnowledgeService) ListVersions(
+	ctx context.Context,
+	userID uint,
+	documentID string,
+) ([]*resp.AIKnowledgeVersionResp, error) {
+	doc, err := s.loadDocument(ctx, userID, documentID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.memory.repo.ListDocumentVersions(ctx, doc.ID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	list := make([]*resp.AIKnowledgeVersionResp, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, aiKnowledgeVersionToResp(row))
+	}
+	return list, nil
+}
+
+// GetVersion 返回指定版本的完整快照。
+func (s *AIKnowledgeService) GetVersion(
+	ctx context.Context,
+	userID uint,
+	documentID string,
+	versionNo int,
+) (*resp.AIKnowledgeVersionResp, error) {
+	if versionNo <= 0 {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "version_no 不合法")
+	}
+	doc, err := s.loadDocument(ctx, userID, documentID)
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.memory.repo.GetDocumentVersion(ctx, doc.ID, versionNo)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if version == nil {
+		return nil, bizerrors.New(bizerrors.CodeAIMemoryNotFound)
+	}
+	item := aiKnowledgeVersionToResp(version)
+	item.ContentText = version.ContentText
+	return item, nil
+}
+
+// loadDocument 读取知识库文档并校验操作人在文档所属组织具备知识库管理能力。
+// 非知识库来源的文档统一视为不存在，避免通过该入口改写 LLM 沉淀的组织记忆。
+func (s *AIKnowledgeService) loadDocument(
+	ctx context.Context,
+	userID uint,
+	documentID string,
+) (*entity.AIMemoryDocument, error) {
+	doc, err := s.memory.repo.GetDocumentByID(ctx, documentID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if doc == nil ||
+		doc.SourceKind != string(aidomain.MemorySourceKnowledgeBase) ||
+		doc.OrgID == nil ||
+		doc.ScopeKey != aidomain.BuildOrgMemoryScopeKey(*doc.OrgID) {
+		return nil, bizerrors.New(bizerrors.CodeAIMemoryNotFound)
+	}
+	if err := s.authorizeOrg(ctx, userID, *doc.OrgID); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (s *AIKnowledgeService) loadLatestVersion(
+	ctx context.Context,
+	documentID string,
+) (*entity.AIMemoryDocumentVersion, error) {
+	versionNo, err := s.memory.repo.GetLatestDocumentVersionNo(ctx, documentID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if versionNo == 0 {
+		return nil, nil
+	}
+	version, err := s.memory.repo.GetDocumentVersion(ctx, documentID, versionNo)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return version, nil
+}
+
+func (s *AIKnowledgeService) authorizeOrg(ctx context.Context, userID uint, orgID uint) error {
+	if s.authorizationService == nil {
+		return bizerrors.NewWithMsg(bizerrors.CodeInternalError, "授权服务未初始化")
+	}
+	return s.authorizationService.AuthorizeOrgCapability(ctx, userID, orgID, consts.CapabilityCodeAIKnowledgeManage)
+}
+
+func normalizeAIKnowledgeMemoryType(value string) (aidomain.MemoryType, error) {
+	switch memoryType := aidomain.MemoryType(strings.TrimSpace(value)); memoryType {
+	case aidomain.MemoryTypeFAQ, aidomain.MemoryTypeProcedural:
+		return memoryType, nil
+	default:
+		return "", bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "memory_type 仅支持 faq 或 procedural")
+	}
+}
+
+func normalizeAIKnowledgeFormat(value string, fallback consts.AIKnowledgeFormat) (consts.AIKnowledgeFormat, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fallback, nil
+	}
+	if !consts.IsValidAIKnowledgeFormat(value) {
+		return "", bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "format 仅支持 markdown 或 text")
+	}
+	return consts.AIKnowledgeFormat(value), nil
+}
+
+// normalizeAIKnowledgeContent 统一换行符并校验正文，保证同一内容多次导入得到相同的内容哈希。
+func normalizeAIKnowledgeContent(value string) (string, error) {
+	content := strings.TrimSpace(strings.ReplaceAll(value, "\r\n", "\n"))
+	if content == "" {
+		return "", bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "文档正文不能为空")
+	}
+	if !utf8.ValidString(content) {
+		return "", bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "文档正文必须是 UTF-8 文本")
+	}
+	if len(content) > aiKnowledgeMaxContentBytes {
+		return "", bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "文档正文不能超过 1MB")
+	}
+	return content, nil
+}
+
+func buildAIKnowledgeSummary(summary string, content string) string {
+	if summary = strings.TrimSpace(summary); summary != "" {
+		return summary
+	}
+	return truncateRunes(strings.Join(strings.Fields(content), " "), aiKnowledgeSummaryRunes)
+}
+
+func buildAIKnowledgeVersion(
+	doc *entity.AIMemoryDocument,
+	versionNo int,
+	format consts.AIKnowledgeFormat,
+	changeNote string,
+	operatorID uint,
+) *entity.AIMemoryDocumentVersion {
+	return &entity.AIMemoryDocumentVersion{
+		DocumentID:  doc.ID,
+		VersionNo:   versionNo,
+		OrgID:       doc.OrgID,
+		MemoryType:  doc.MemoryType,
+		Topic:       doc.Topic,
+		Title:       doc.Title,
+		Format:      string(format),
+		ContentText: doc.ContentText,
+		ContentHash: aidomain.BuildMemoryDocumentContentHash(doc.ContentText),
+		ChangeNote:  strings.TrimSpace(changeNote),
+		OperatorID:  operatorID,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func aiKnowledgeDocumentToResp(
+	doc *entity.AIMemoryDocument,
+	versionNo int,
+	stat *entity.AIMemoryDocumentRecallStat,
+) *resp.AIKnowledgeDocumentResp {
+	if doc == nil {
+		return nil
+	}
+	item := &resp.AIKnowledgeDocumentResp{
+		ID:             doc.ID,
+		MemoryType:     doc.MemoryType,
+		Topic:          doc.Topic,
+		Title:          doc.Title,
+		Summary:        doc.Summary,
+		CurrentVersion: versionNo,
+		CreatedAt:      formatTime(doc.CreatedAt),
+		UpdatedAt:      formatTime(doc.UpdatedAt),
+	}
+	if doc.OrgID != nil {
+		item.OrgID = *doc.OrgID
+	}
+	if stat != nil {
+		item.RecallCount = stat.RecallCount
+		item.LastRecallScore = stat.LastScore
+		item.LastRecalledAt = formatTimePtr(stat.LastRecalledAt)
+	}
+	return item
+}
+
+func aiKnowledgeVersionToResp(version *entity.AIMemoryDocumentVersion) *resp.AIKnowledgeVersionResp {
+	if version == nil {
+		return nil
+	}
+	return &resp.AIKnowledgeVersionResp{
+		VersionNo:   version.VersionNo,
+		MemoryType:  version.MemoryType,
+		Topic:       version.Topic,
+		Title:       version.Title,
+		Format:      version.Format,
+		ContentHash: version.ContentHash,
+		ChangeNote:  version.ChangeNote,
+		OperatorID:  version.OperatorID,
+		CreatedAt:   formatTime(version.CreatedAt),
+	}
+}
@@ -0,0 +1,256 @@
+package system
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	aidomain "personal_assistant/internal/domain/ai"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/model/entity"
+	bizerrors "personal_assistant/pkg/errors"
+)
+
+type scopedFakeMemoryVectorSearcher struct {
+	results map[string][]aidomain.MemoryVectorSearchResult
+	inputs  []aidomain.MemoryVectorSearchInput
+}
+
+func (f *scopedFakeMemoryVectorSearcher) SearchChunks(
+	_ context.Context,
+	input aidomain.MemoryVectorSearchInput,
+) ([]aidomain.MemoryVectorSearchResult, error) {
+	f.inputs = append(f.inputs, input)
+	return f.results[input.ScopeKey], nil
+}
+
+func TestAIKnowledgeCreateAndReingestKeepsVersionHistory(t *testing.T) {
+	db := newAIMemoryWritebackTestDB(t)
+	memory := newAIMemoryWritebackTestService(db, nil)
+	store := &fakeMemoryVectorStore{}
+	memory.vectorStore = store
+	orgID := uint(5)
+	auth := &fakeAIMemoryManageAuthorization{capabilities: map[uint]map[string]bool{
+		orgID: {consts.CapabilityCodeAIKnowledgeManage: true},
+	}}
+	service := NewAIKnowledgeService(memory, &stubTxRunner{}, auth)
+	ctx := context.Background()
+
+	_, err := service.CreateDocument(ctx, 7, &request.CreateAIKnowledgeDocumentReq{
+		OrgID: 6, MemoryType: "faq", Topic: "contest_rules", Title: "赛制说明", ContentText: "# 赛制",
+	})
+	if bizErr := bizerrors.FromError(err); bizErr == nil || bizErr.Code != bizerrors.CodePermissionDenied {
+		t.Fatalf("CreateDocument(without capability) error = %v, want permission denied", err)
+	}
+
+	created, err := service.CreateDocument(ctx, 7, &request.CreateAIKnowledgeDocumentReq{
+		OrgID:       orgID,
+		MemoryType:  "faq",
+		Topic:       "contest_rules",
+		Title:       "赛制说明",
+		ContentText: "# 赛制\r\n\r\n比赛采用 ICPC 赛制，罚时 20 分钟。",
+		ChangeNote:  "首次导入",
+	})
+	if err != nil {
+		t.Fatalf("CreateDocument() error = %v", err)
+	}
+	if created.CurrentVersion != 1 || created.Format != string(consts.AIKnowledgeFormatMarkdown) || created.OrgID != orgID {
+		t.Fatalf("CreateDocument() = %+v", created)
+	}
+	doc, err := memory.repo.GetDocumentByID(ctx, created.ID)
+	if err != nil || doc == nil {
+		t.Fatalf("GetDocumentByID() = %+v, %v", doc, err)
+	}
+	if doc.ScopeKey != aidomain.BuildOrgMemoryScopeKey(orgID) ||
+		doc.Visibility != string(aidomain.MemoryVisibilityOrg) ||
+		doc.SourceKind != string(aidomain.MemorySourceKnowledgeBase) ||
+		strings.Contains(doc.ContentText, "\r") ||
+		doc.Summary == "" {
+		t.Fatalf("stored document = %+v", doc)
+	}
+
+	unchanged, err := service.UpdateDocument(ctx, 7, created.ID, &request.UpdateAIKnowledgeDocumentReq{
+		ContentText: "# 赛制\n\n比赛采用 ICPC 赛制，罚时 20 分钟。",
+	})
+	if err != nil {
+		t.Fatalf("UpdateDocument(unchanged) error = %v", err)
+	}
+	if unchanged.CurrentVersion != 1 || store.deletedDocumentID != "" {
+		t.Fatalf("unchanged update should not create version or drop index, got %+v, deleted = %q", unchanged, store.deletedDocumentID)
+	}
+
+	updated, err := service.UpdateDocument(ctx, 7, created.ID, &request.UpdateAIKnowledgeDocumentReq{
+		MemoryType:  "procedural",
+		Format:      "text",
+		ContentText: "比赛采用 IOI 赛制，按子任务给分。",
+		ChangeNote:  "改为 IOI 赛制",
+	})
+	if err != nil {
+		t.Fatalf("UpdateDocument() error = %v", err)
+	}
+	if updated.ID != created.ID || updated.CurrentVersion != 2 || updated.MemoryType != "procedural" || updated.Format != "text" {
+		t.Fatalf("UpdateDocument() = %+v", updated)
+	}
+	if store.deletedDocumentID != created.ID {
+		t.Fatalf("deletedDocumentID = %q, want old vectors dropped", store.deletedDocumentID)
+	}
+
+	versions, err := service.ListVersions(ctx, 7, created.ID)
+	if err != nil {
+		t.Fatalf("ListVersions() error = %v", err)
+	}
+	if len(versions) != 2 || versions[0].VersionNo != 2 || versions[1].VersionNo != 1 || versions[0].ContentText != "" {
+		t.Fatalf("ListVersions() = %+v", versions)
+	}
+	first, err := service.GetVersion(ctx, 7, created.ID, 1)
+	if err != nil {
+		t.Fatalf("GetVersion(1) error = %v", err)
+	}
+	if !strings.Contains(first.ContentText, "ICPC") || first.Format != "markdown" || first.ChangeNote != "首次导入" {
+		t.Fatalf("GetVersion(1) = %+v", first)
+	}
+
+	manage := NewAIMemoryManageService(memory, auth)
+	auth.capabilities[orgID][consts.CapabilityCodeOrgManageUpdate] = true
+	_, err = manage.UpdateDocument(ctx, 7, created.ID, &request.AIMemoryScopeReq{Scope: "org", OrgID: &orgID},
+		&request.UpdateAIMemoryDocumentReq{ContentText: aiMemoryManageStringPtr("绕过版本的修改")})
+	if bizErr := bizerrors.FromError(err); bizErr == nil || bizErr.Code != bizerrors.CodeInvalidParams {
+		t.Fatalf("memory manage UpdateDocument(knowledge base) error = %v, want invalid params", err)
+	}
+
+	if err := service.DeleteDocument(ctx, 7, created.ID); err != nil {
+		t.Fatalf("DeleteDocument() error = %v", err)
+	}
+	var versionCount int64
+	db.Model(&entity.AIMemoryDocumentVersion{}).Where("document_id = ?", created.ID).Count(&versionCount)
+	if versionCount != 0 {
+		t.Fatalf("version rows = %d, want purged", versionCount)
+	}
+}
+
+func TestAIMemoryRecallIncludesCurrentOrgKnowledgeAndRecordsStats(t *testing.T) {
+	db := newAIMemoryWritebackTestDB(t)
+	memory := newAIMemoryWritebackTestService(db, nil)
+	memory.embedder = &fakeMemoryEmbedder{vectors: [][]float32{{0.1, 0.2, 0.3}}}
+	restore := setAIMemoryTestConfig(t, config.AIMemory{
+		Enabled:              true,
+		EnableLongTermMemory: true,
+		RecallTopK:           3,
+		RecallMaxChars:       4000,
+		RecallMinScore:       0.5,
+		RAGMaxChars:          2000,
+		EmbedModel:           "qwen3-vl-embedding",
+		EmbedDimension:       3,
+	})
+	defer restore()
+
+	ctx := context.Background()
+	userID, orgID, otherOrgID := uint(21), uint(5), uint(6)
+	upsertAIMemoryRecallDocumentChunk(t, memory, userID, "doc-self", "chunk-self", "point-self", "个人笔记：DP 先写状态转移。")
+	mustCreateAIKnowledgeRecallChunk(t, memory, orgID, "doc-org", "point-org", "组织讲义：比赛采用 ICPC 赛制。")
+	mustCreateAIKnowledgeRecallChunk(t, memory, otherOrgID, "doc-other-org", "point-other-org", "其他组织的内部讲义。")
+	searcher := &scopedFakeMemoryVectorSearcher{results: map[string][]aidomain.MemoryVectorSearchResult{
+		aidomain.BuildSelfMemoryScopeKey(userID): {{QdrantPointID: "point-self", Score: 0.7}},
+		aidomain.BuildOrgMemoryScopeKey(orgID): {
+			{QdrantPointID: "point-org", Score: 0.9},
+			// 模拟向量库过滤失效，数据库侧校验必须把其他组织的 chunk 挡住。
+			{QdrantPointID: "point-other-org", Score: 0.95},
+		},
+	}}
+	memory.vectorSearcher = searcher
+
+	input := aiMemoryRecallInput{
+		ConversationID: "conv-org-recall",
+		UserID:         userID,
+		Query:          "比赛是什么赛制？",
+		ToolCallCtx: aidomain.ToolCallContext{
+			Principal: aidomain.AIToolPrincipal{UserID: userID, CurrentOrgID: &orgID},
+		},
+	}
+	messages, err := memory.RecallMessages(ctx, input)
+	if err != nil {
+		t.Fatalf("RecallMessages() error = %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("RecallMessages() len = %d, want 1", len(messages))
+	}
+	content := messages[0].Content
+	orgIndex := strings.Index(content, "组织讲义")
+	selfIndex := strings.Index(content, "个人笔记")
+	if orgIndex < 0 || selfIndex < 0 || orgIndex > selfIndex {
+		t.Fatalf("recall content should contain org doc before self doc:\n%s", content)
+	}
+	if strings.Contains(content, "其他组织") {
+		t.Fatalf("other org content leaked:\n%s", content)
+	}
+	if len(searcher.inputs) != 2 || searcher.inputs[1].Visibility != string(aidomain.MemoryVisibilityOrg) || searcher.inputs[1].UserID != 0 {
+		t.Fatalf("search inputs = %+v", searcher.inputs)
+	}
+
+	if _, err := memory.RecallMessages(ctx, input); err != nil {
+		t.Fatalf("RecallMessages(second) error = %v", err)
+	}
+	stats, err := memory.repo.ListDocumentRecallStats(ctx, []string{"doc-org", "doc-self", "doc-other-org"})
+	if err != nil {
+		t.Fatalf("ListDocumentRecallStats() error = %v", err)
+	}
+	if stats["doc-org"] == nil || stats["doc-org"].RecallCount != 2 || stats["doc-org"].LastScore != 0.9 {
+		t.Fatalf("org doc stats = %+v", stats["doc-org"])
+	}
+	if stats["doc-self"] == nil || stats["doc-self"].RecallCount != 2 {
+		t.Fatalf("self doc stats = %+v", stats["doc-self"])
+	}
+	if stats["doc-other-org"] != nil {
+		t.Fatalf("other org doc should not be counted, got %+v", stats["doc-other-org"])
+	}
+}
+
+func mustCreateAIKnowledgeRecallChunk(
+	t *testing.T,
+	memory *AIMemoryService,
+	orgID uint,
+	documentID string,
+	pointID string,
+	content string,
+) {
+	t.Helper()
+	now := time.Now()
+	scopeKey := aidomain.BuildOrgMemoryScopeKey(orgID)
+	if err := memory.repo.BatchUpsertDocuments(context.Background(), []*entity.AIMemoryDocument{{
+		ID:          documentID,
+		ScopeKey:    scopeKey,
+		ScopeType:   string(aidomain.MemoryScopeOrg),
+		Visibility:  string(aidomain.MemoryVisibilityOrg),
+		OrgID:       &orgID,
+		MemoryType:  string(aidomain.MemoryTypeFAQ),
+		Topic:       "contest_rules",
+		Title:       "赛制说明",
+		Summary:     "赛制说明",
+		ContentText: content,
+		SourceKind:  string(aidomain.MemorySourceKnowledgeBase),
+		SourceID:    "kb_" + documentID,
+	}}); err != nil {
+		t.Fatalf("BatchUpsertDocuments() error = %v", err)
+	}
+	if err := memory.repo.ReplaceDocumentChunks(context.Background(), documentID, []*entity.AIMemoryDocumentChunk{{
+		ID:                 documentID + "-0",
+		DocumentID:         documentID,
+		ScopeKey:           scopeKey,
+		ScopeType:          string(aidomain.MemoryScopeOrg),
+		Visibility:         string(aidomain.MemoryVisibilityOrg),
+		OrgID:              &orgID,
+		MemoryType:         string(aidomain.MemoryTypeFAQ),
+		Topic:              "contest_rules",
+		ContentText:        content,
+		ContentHash:        aidomain.BuildMemoryDocumentContentHash(content),
+		EmbeddingModel:     "qwen3-vl-embedding",
+		EmbeddingDimension: 3,
+		QdrantPointID:      pointID,
+		IndexedAt:          &now,
+	}}); err != nil {
+		t.Fatalf("ReplaceDocumentChunks() error = %v", err)
+	}
+}
@@ -0,0 +1,495 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"personal_assistant/global"
+	aidomain "personal_assistant/internal/domain/ai"
+	aimemory "personal_assistant/internal/infrastructure/ai/memory"
+	"personal_assistant/internal/model/entity"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAIMemoryRerankTopN           = 20
+	defaultAIMemoryRerankTimeoutSeconds = 10
+	// aiMemoryLexicalPostingLimit 限制单个作用域一次最多读取的倒排记录数，防止高频 term 拖慢召回。
+	aiMemoryLexicalPostingLimit = 2000
+	// aiMemoryTermBackfillBatchSize 是存量 chunk 补建关键词倒排时单批处理的 chunk 数。
+	aiMemoryTermBackfillBatchSize = 200
+	aiMemoryRecallTraceKey        = "memory_recall"
+	aiMemoryRecallTracePreview    = 48
+)
+
+// aiMemoryRAGRecallDebug 记录单个召回片段的来源与打分，用于排查“为什么召回了这个片段”。
+// 名次从 1 开始，0 表示该路没有命中。
+type aiMemoryRAGRecallDebug struct {
+	VectorRank   int
+	VectorScore  float64
+	LexicalRank  int
+	LexicalScore float64
+	MatchedTerms []string
+	FusionScore  float64
+	Reranked     bool
+	RerankScore  float64
+	// Rank 是融合与重排后的最终名次，注入 prompt 时按它排序。
+	Rank int
+}
+
+// aiMemoryRankedChunk 是单路召回（向量或关键词）按得分排好序的候选。
+type aiMemoryRankedChunk struct {
+	Chunk        *entity.AIMemoryDocumentChunk
+	Score        float64
+	MatchedTerms []string
+}
+
+// aiMemoryRAGTraceEntry 是写入 trace 详情的一条召回明细。
+type aiMemoryRAGTraceEntry struct {
+	DocumentID string
+	ChunkIndex int
+	MemoryType string
+	Topic      string
+	Preview    string
+	Debug      aiMemoryRAGRecallDebug
+	Kept       bool
+}
+
+// recallLexicalChunks 按 BM25 在各作用域内检索关键词命中的 chunks。
+// 各作用域分别统计 idf 后合并排序，与向量召回一样每个作用域最多取 TopK。
+func (s *AIMemoryService) recallLexicalChunks(
+	ctx context.Context,
+	query string,
+	scopes []aiMemoryRAGRecallScope,
+) ([]aiMemoryRankedChunk, error) {
+	if !aiMemoryLexicalRecallEnabled() {
+		return nil, nil
+	}
+	terms := aimemory.LexicalQueryTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	scores := make([]aimemory.LexicalScore, 0, aiMemoryRecallTopK()*len(scopes))
+	for _, scope := range scopes {
+		stats, err := s.repo.SearchChunkTerms(ctx, aidomain.MemoryLexicalQuery{
+			ScopeKey:     scope.ScopeKey,
+			Visibility:   string(scope.Visibility),
+			UserID:       scope.UserID,
+			Terms:        terms,
+			PostingLimit: aiMemoryLexicalPostingLimit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		scopeScores := aimemory.ScoreBM25(terms, stats, aimemory.BM25Options{})
+		if len(scopeScores) > aiMemoryRecallTopK() {
+			scopeScores = scopeScores[:aiMemoryRecallTopK()]
+		}
+		scores = append(scores, scopeScores...)
+	}
+	if len(scores) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Score == scores[j].Score {
+			return scores[i].ChunkID < scores[j].ChunkID
+		}
+		return scores[i].Score > scores[j].Score
+	})
+
+	chunkIDs := make([]string, 0, len(scores))
+	for _, score := range scores {
+		chunkIDs = append(chunkIDs, score.ChunkID)
+	}
+	chunks, err := s.repo.ListDocumentChunksByIDs(ctx, chunkIDs)
+	if err != nil {
+		return nil, err
+	}
+	chunkByID := make(map[string]*entity.AIMemoryDocumentChunk, len(chunks))
+	for _, chunk := range chunks {
+		if isAIMemoryChunkInRecallScopes(chunk, scopes) {
+			chunkByID[chunk.ID] = chunk
+		}
+	}
+	ranked := make([]aiMemoryRankedChunk, 0, len(scores))
+	for _, score := range scores {
+		chunk := chunkByID[score.ChunkID]
+		if chunk == nil {
+			continue
+		}
+		ranked = append(ranked, aiMemoryRankedChunk{
+			Chunk:        chunk,
+			Score:        score.Score,
+			MatchedTerms: score.MatchedTerms,
+		})
+	}
+	return ranked, nil
+}
+
+// fuseAIMemoryRAGCandidates 用 reciprocal rank fusion 合并向量与关键词两路结果，再做可选重排并截断到 TopK。
+// RRF 只看名次不看分值，向量余弦分和 BM25 分不在同一量纲也能直接融合。
+func (s *AIMemoryService) fuseAIMemoryRAGCandidates(
+	ctx context.Context,
+	query string,
+	vectorRanked []aiMemoryRankedChunk,
+	lexicalRanked []aiMemoryRankedChunk,
+) []aiMemoryRAGRecallItem {
+	itemByID := make(map[string]*aiMemoryRAGRecallItem, len(vectorRanked)+len(lexicalRanked))
+	ensure := func(chunk *entity.AIMemoryDocumentChunk) *aiMemoryRAGRecallItem {
+		item := itemByID[chunk.ID]
+		if item == nil {
+			item = &aiMemoryRAGRecallItem{Chunk: chunk}
+			itemByID[chunk.ID] = item
+		}
+		return item
+	}
+	vectorIDs := make([]string, 0, len(vectorRanked))
+	for index, hit := range vectorRanked {
+		item := ensure(hit.Chunk)
+		item.Score = hit.Score
+		item.Debug.VectorRank = index + 1
+		item.Debug.VectorScore = hit.Score
+		vectorIDs = append(vectorIDs, hit.Chunk.ID)
+	}
+	lexicalIDs := make([]string, 0, len(lexicalRanked))
+	for index, hit := range lexicalRanked {
+		item := ensure(hit.Chunk)
+		item.Debug.LexicalRank = index + 1
+		item.Debug.LexicalScore = hit.Score
+		item.Debug.MatchedTerms = hit.MatchedTerms
+		lexicalIDs = append(lexicalIDs, hit.Chunk.ID)
+	}
+
+	order, fused := aimemory.ReciprocalRankFusion(aimemory.DefaultRRFK, vectorIDs, lexicalIDs)
+	items := make([]aiMemoryRAGRecallItem, 0, len(order))
+	for _, chunkID := range order {
+		item := itemByID[chunkID]
+		item.Debug.FusionScore = fused[chunkID]
+		items = append(items, *item)
+	}
+	items = s.rerankAIMemoryRAGItems(ctx, query, items)
+	if limit := aiMemoryRecallTopK(); len(items) > limit {
+		items = items[:limit]
+	}
+	for index := range items {
+		items[index].Debug.Rank = index + 1
+	}
+	return items
+}
+
+// rerankAIMemoryRAGItems 对融合结果的前 N 条做重排，失败时保持融合顺序（fail-open）。
+// 重排器没有给分的候选保持原有相对顺序，排在已打分候选之后。
+func (s *AIMemoryService) rerankAIMemoryRAGItems(
+	ctx context.Context,
+	query string,
+	items []aiMemoryRAGRecallItem,
+) []aiMemoryRAGRecallItem {
+	if s.reranker == nil || len(items) < 2 {
+		return items
+	}
+	limit := aiMemoryRerankTopN()
+	if limit > len(items) {
+		limit = len(items)
+	}
+	head := append([]aiMemoryRAGRecallItem(nil), items[:limit]...)
+	documents := make([]string, 0, len(head))
+	for _, item := range head {
+		documents = append(documents, buildAIMemoryRerankDocument(item.Chunk))
+	}
+	results, err := s.reranker.Rerank(ctx, aidomain.MemoryRerankInput{Query: query, Documents: documents})
+	if err != nil {
+		if global.Log != nil {
+			global.Log.Warn("AI memory rerank failed, keep fused order", zap.Error(err))
+		}
+		return items
+	}
+	if len(results) == 0 {
+		return items
+	}
+	for _, result := range results {
+		if result.Index < 0 || result.Index >= len(head) || head[result.Index].Debug.Reranked {
+			continue
+		}
+		head[result.Index].Debug.Reranked = true
+		head[result.Index].Debug.RerankScore = result.Score
+	}
+	sort.SliceStable(head, func(i, j int) bool {
+		left, right := head[i].Debug, head[j].Debug
+		if left.Reranked != right.Reranked {
+			return left.Reranked
+		}
+		return left.Reranked && left.RerankScore > right.RerankScore
+	})
+	return append(head, items[limit:]...)
+}
+
+func buildAIMemoryRerankDocument(chunk *entity.AIMemoryDocumentChunk) string {
+	if chunk == nil {
+		return ""
+	}
+	topic := strings.TrimSpace(chunk.Topic)
+	if topic == "" {
+		return chunk.ContentText
+	}
+	return topic + "\n" + chunk.ContentText
+}
+
+// saveChunkTermsFailOpen 为新写入的 chunks 建立关键词倒排。
+// 失败或关闭关键词召回时 chunk 的 lexical_length 保持 0，会被补建扫描重新拾取。
+func (s *AIMemoryService) saveChunkTermsFailOpen(ctx context.Context, chunks []*entity.AIMemoryDocumentChunk) {
+	if !aiMemoryLexicalRecallEnabled() || len(chunks) == 0 {
+		return
+	}
+	if err := s.repo.SaveChunkTerms(ctx, chunks, buildAIMemoryChunkTerms(chunks)); err != nil && global.Log != nil {
+		global.Log.Warn("AI memory save chunk terms failed", zap.Error(err))
+	}
+}
+
+// IndexPendingChunkTerms 为尚未建立关键词倒排的存量 chunks 补建倒排，不需要重新 embedding。
+func (s *AIMemoryService) IndexPendingChunkTerms(ctx context.Context, limit int) error {
+	if !aiMemoryEnabled() || !aiMemoryLongTermEnabled() || !aiMemoryLexicalRecallEnabled() || s == nil || s.repo == nil {
+		return nil
+	}
+	if limit <= 0 {
+		limit = aiMemoryTermBackfillBatchSize
+	}
+	chunks, err := s.repo.ListChunksMissingTerms(ctx, limit)
+	if err != nil || len(chunks) == 0 {
+		return err
+	}
+	return s.repo.SaveChunkTerms(ctx, chunks, buildAIMemoryChunkTerms(chunks))
+}
+
+// buildAIMemoryChunkTerms 切分 chunk 的主题与正文生成倒排记录，并回填 chunk.LexicalLength。
+// 没有任何可索引 term 的 chunk 也记为长度 1，避免被补建扫描反复拾取。
+func buildAIMemoryChunkTerms(chunks []*entity.AIMemoryDocumentChunk) []*entity.AIMemoryChunkTerm {
+	terms := make([]*entity.AIMemoryChunkTerm, 0, len(chunks)*32)
+	for _, chunk := range chunks {
+		if chunk == nil || strings.TrimSpace(chunk.ID) == "" {
+			continue
+		}
+		freq := aimemory.LexicalTermFrequencies(chunk.Topic + "\n" + chunk.ContentText)
+		chunk.LexicalLength = aimemory.LexicalLength(freq)
+		if chunk.LexicalLength <= 0 {
+			chunk.LexicalLength = 1
+		}
+		for term, count := range freq {
+			terms = append(terms, &entity.AIMemoryChunkTerm{
+				ChunkID:    chunk.ID,
+				Term:       term,
+				DocumentID: chunk.DocumentID,
+				ScopeKey:   chunk.ScopeKey,
+				Visibility: chunk.Visibility,
+				UserID:     cloneMemoryUintPtr(chunk.UserID),
+				TermFreq:   count,
+			})
+		}
+	}
+	return terms
+}
+
+// buildAIMemoryRAGTraceEntries 把排序后的召回片段转换成 trace 明细，前 kept 条为实际注入 prompt 的片段。
+func buildAIMemoryRAGTraceEntries(items []aiMemoryRAGRecallItem, kept int) []aiMemoryRAGTraceEntry {
+	entries := make([]aiMemoryRAGTraceEntry, 0, len(items))
+	for index, item := range items {
+		if item.Chunk == nil {
+			continue
+		}
+		entries = append(entries, aiMemoryRAGTraceEntry{
+			DocumentID: item.Chunk.DocumentID,
+			ChunkIndex: item.Chunk.ChunkIndex,
+			MemoryType: item.Chunk.MemoryType,
+			Topic:      item.Chunk.Topic,
+			Preview:    truncateAIMemoryTracePreview(item.Chunk.ContentText),
+			Debug:      item.Debug,
+			Kept:       index < kept,
+		})
+	}
+	return entries
+}
+
+// renderAIMemoryRecallTraceMarkdown 把召回明细渲染成 trace 详情表格。
+func renderAIMemoryRecallTraceMarkdown(entries []aiMemoryRAGTraceEntry) string {
+	var builder strings.Builder
+	builder.WriteString("| # | 片段 | 向量 | 关键词 | RRF | 重排 | 注入 |\n")
+	builder.WriteString("| --- | --- | --- | --- | --- | --- | --- |\n")
+	for index, entry := range entries {
+		label := entry.DocumentID + "#" + fmt.Sprintf("%d", entry.ChunkIndex)
+		if tag := strings.Trim(entry.MemoryType+"/"+entry.Topic, "/"); tag != "" {
+			label = "[" + tag + "] " + label
+		}
+		vector := "-"
+		if entry.Debug.VectorRank > 0 {
+			vector = fmt.Sprintf("#%d %.3f", entry.Debug.VectorRank, entry.Debug.VectorScore)
+		}
+		lexical := "-"
+		if entry.Debug.LexicalRank > 0 {
+			lexical = fmt.Sprintf("#%d %.3f", entry.Debug.LexicalRank, entry.Debug.LexicalScore)
+			if len(entry.Debug.MatchedTerms) > 0 {
+				lexical += " (" + strings.Join(entry.Debug.MatchedTerms, ", ") + ")"
+			}
+		}
+		rerank := "-"
+		if entry.Debug.Reranked {
+			rerank = fmt.Sprintf("%.3f", entry.Debug.RerankScore)
+		}
+		kept := "否"
+		if entry.Kept {
+			kept = "是"
+		}
+		builder.WriteString(fmt.Sprintf(
+			"| %d | %s<br>%s | %s | %s | %.4f | %s | %s |\n",
+			index+1,
+			escapeAIMemoryTraceCell(label),
+			escapeAIMemoryTraceCell(entry.Preview),
+			vector,
+			escapeAIMemoryTraceCell(lexical),
+			entry.Debug.FusionScore,
+			rerank,
+			kept,
+		))
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+func truncateAIMemoryTracePreview(content string) string {
+	content = strings.Join(strings.Fields(content), " ")
+	if utf8.RuneCountInString(content) <= aiMemoryRecallTracePreview {
+		return content
+	}
+	return string([]rune(content)[:aiMemoryRecallTracePreview]) + "…"
+}
+
+func escapeAIMemoryTraceCell(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
+
+// emitMemoryRecallTrace 在开启 recall_trace 时把长期文档召回明细写成一条 trace item。
+// trace 只用于排障，写入失败不影响本轮回答。
+func (s *AIService) emitMemoryRecallTrace(
+	ctx context.Context,
+	sink aidomain.Sink,
+	diagnostics aiHybridContextDiagnostics,
+) {
+	if !aiMemoryRecallTraceEnabled() || sink == nil || len(diagnostics.RAGTrace) == 0 {
+		return
+	}
+	err := sink.Emit(ctx, aidomain.Event{
+		Name: aidomain.EventToolCallStarted,
+		Payload: aidomain.ToolCallStartedPayload{
+			Key:         aiMemoryRecallTraceKey,
+			Title:       "检索长期记忆",
+			Description: "按向量与关键词召回长期文档片段。",
+		},
+	})
+	if err == nil {
+		err = sink.Emit(ctx, aidomain.Event{
+			Name: aidomain.EventToolCallFinished,
+			Payload: aidomain.ToolCallFinishedPayload{
+				Key:            aiMemoryRecallTraceKey,
+				Description:    "长期记忆召回完成。",
+				Status:         "success",
+				Content:        fmt.Sprintf("召回 %d 条候选，注入 %d 条", diagnostics.RAGCandidates, diagnostics.RAGKept),
+				DetailMarkdown: renderAIMemoryRecallTraceMarkdown(diagnostics.RAGTrace),
+			},
+		})
+	}
+	if err != nil && global.Log != nil {
+		global.Log.Warn("AI memory recall trace emit failed", zap.Error(err))
+	}
+}
+
+// newAIMemoryReranker 按配置创建重排器；配置非法时只告警并关闭重排，不阻断启动。
+func newAIMemoryReranker() aidomain.MemoryReranker {
+	model := aiMemoryRerankModel()
+	if model == "" && aiMemoryRerankProvider() == aimemory.RerankProviderLLM {
+		model = aiMemoryModel()
+	}
+	reranker, err := aimemory.NewReranker(context.Background(), aimemory.RerankerOptions{
+		Provider:            aiMemoryRerankProvider(),
+		ChatProvider:        aiMemoryProviderName(),
+		APIKey:              aiMemoryRerankAPIKey(),
+		BaseURL:             aiMemoryRerankBaseURL(),
+		Model:               model,
+		ByAzure:             aiMemoryByAzure(),
+		APIVersion:          aiMemoryAPIVersion(),
+		MaxCompletionTokens: aiMemoryMaxCompletionTokens(),
+		Timeout:             aiMemoryRerankTimeout(),
+	})
+	if err != nil {
+		if global.Log != nil {
+			global.Log.Warn("AI memory reranker disabled", zap.Error(err))
+		}
+		return nil
+	}
+	return reranker
+}
+
+func aiMemoryLexicalRecallEnabled() bool {
+	return global.Config != nil && global.Config.AI.Memory.LexicalRecallEnabled
+}
+
+func aiMemoryRecallTraceEnabled() bool {
+	return global.Config != nil && global.Config.AI.Memory.RecallTrace
+}
+
+func aiMemoryRerankProvider() string {
+	if global.Config == nil {
+		return aimemory.RerankProviderNone
+	}
+	provider := strings.ToLower(strings.TrimSpace(global.Config.AI.Memory.RerankProvider))
+	if provider == "" {
+		return aimemory.RerankProviderNone
+	}
+	return provider
+}
+
+func aiMemoryRerankModel() string {
+	if global.Config == nil {
+		return ""
+	}
+	return strings.TrimSpace(global.Config.AI.Memory.RerankModel)
+}
+
+// aiMemoryRerankBaseURL 返回重排服务地址；llm 重排复用对话模型的 base_url。
+func aiMemoryRerankBaseURL() string {
+	if aiMemoryRerankProvider() == aimemory.RerankProviderLLM {
+		return aiMemoryBaseURL()
+	}
+	if global.Config == nil {
+		return ""
+	}
+	return strings.TrimSpace(global.Config.AI.Memory.RerankBaseURL)
+}
+
+// aiMemoryRerankAPIKey 返回重排服务密钥；llm 重排复用对话模型的 api_key。
+func aiMemoryRerankAPIKey() string {
+	if aiMemoryRerankProvider() == aimemory.RerankProviderLLM {
+		return aiMemoryAPIKey()
+	}
+	if global.Config == nil {
+		return ""
+	}
+	return strings.TrimSpace(global.Config.AI.Memory.RerankAPIKey)
+}
+
+func aiMemoryRerankTopN() int {
+	if global.Config == nil || global.Config.AI.Memory.RerankTopN <= 0 {
+		return defaultAIMemoryRerankTopN
+	}
+	return global.Config.AI.Memory.RerankTopN
+}
+
+func aiMemoryRerankTimeout() time.Duration {
+	seconds := defaultAIMemoryRerankTimeoutSeconds
+	if global.Config != nil && global.Config.AI.Memory.RerankTimeoutSeconds > 0 {
+		seconds = global.Config.AI.Memory.RerankTimeoutSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,323 @@
+package system
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	aidomain "personal_assistant/internal/domain/ai"
+	aimemory "personal_assistant/internal/infrastructure/ai/memory"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/entity"
+)
+
+type fakeMemoryReranker struct {
+	results []aidomain.MemoryRerankResult
+	err     error
+	input   aidomain.MemoryRerankInput
+}
+
+func (f *fakeMemoryReranker) Rerank(
+	_ context.Context,
+	input aidomain.MemoryRerankInput,
+) ([]aidomain.MemoryRerankResult, error) {
+	f.input = input
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.results, nil
+}
+
+type recordingAISink struct {
+	events []aidomain.Event
+}
+
+func (s *recordingAISink) Emit(_ context.Context, event aidomain.Event) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingAISink) Heartbeat(context.Context) error {
+	return nil
+}
+
+func TestAIMemoryHybridRecallFindsProblemIDByKeywordWhenVectorMisses(t *testing.T) {
+	db := newAIMemoryWritebackTestDB(t)
+	service := newAIMemoryWritebackTestService(db, nil)
+	service.embedder = &fakeMemoryEmbedder{vectors: [][]float32{{0.1, 0.2, 0.3}}}
+	service.vectorSearcher = &fakeMemoryVectorStore{
+		searchResults: []aidomain.MemoryVectorSearchResult{
+			{QdrantPointID: "41111111-1111-1111-1111-111111111111", Score: 0.88},
+		},
+	}
+	restore := setAIMemoryTestConfig(t, config.AIMemory{
+		Enabled:              true,
+		EnableLongTermMemory: true,
+		LexicalRecallEnabled: true,
+		RecallTopK:           3,
+		RecallMaxChars:       4000,
+		RecallMinScore:       0.5,
+		RAGMaxChars:          2000,
+		EmbedModel:           "qwen3-vl-embedding",
+		EmbedDimension:       3,
+	})
+	defer restore()
+
+	ctx := context.Background()
+	userID := uint(41)
+	upsertAIMemoryRecallDocumentChunk(t, service, userID, "doc-hybrid-dp", "chunk-hybrid-dp",
+		"41111111-1111-1111-1111-111111111111", "动态规划入门：背包问题要倒序枚举容量。")
+	upsertAIMemoryRecallDocumentChunk(t, service, userID, "doc-hybrid-pid", "chunk-hybrid-pid",
+		"42222222-2222-2222-2222-222222222222", "P1001 A+B Problem 的题解：直接读入两个整数相加输出。")
+	upsertAIMemoryRecallDocumentChunk(t, service, userID, "doc-hybrid-slug", "chunk-hybrid-slug",
+		"43333333-3333-3333-3333-333333333333", "LeetCode two-sum 用哈希表记录已经出现过的数字。")
+	// 测试 helper 直接写仓储，不经过索引流程，这里顺带验证存量 chunk 的倒排补建。
+	if err := service.IndexPendingChunkTerms(ctx, 0); err != nil {
+		t.Fatalf("IndexPendingChunkTerms() error = %v", err)
+	}
+
+	items, err := service.recallLongTermDocuments(ctx, aiMemoryRecallInput{
+		ConversationID: "conv-hybrid-pid",
+		UserID:         userID,
+		Query:          "P1001 怎么做",
+	})
+	if err != nil {
+		t.Fatalf("recallLongTermDocuments() error = %v", err)
+	}
+	byChunkID := make(map[string]aiMemoryRAGRecallItem, len(items))
+	for _, item := range items {
+		byChunkID[item.Chunk.ID] = item
+	}
+	pidItem, ok := byChunkID["chunk-hybrid-pid"]
+	if !ok {
+		t.Fatalf("keyword hit missing, items = %+v", items)
+	}
+	if pidItem.Debug.VectorRank != 0 || pidItem.Debug.LexicalRank != 1 || pidItem.Score != 0 {
+		t.Fatalf("pid debug = %+v score = %v", pidItem.Debug, pidItem.Score)
+	}
+	if strings.Join(pidItem.Debug.MatchedTerms, ",") != "1001,p1001" {
+		t.Fatalf("matched terms = %v", pidItem.Debug.MatchedTerms)
+	}
+	if pidItem.Debug.FusionScore <= 0 || pidItem.Debug.Rank == 0 {
+		t.Fatalf("pid fusion debug = %+v", pidItem.Debug)
+	}
+	if vectorItem := byChunkID["chunk-hybrid-dp"]; vectorItem.Debug.VectorRank != 1 || vectorItem.Debug.LexicalRank != 0 {
+		t.Fatalf("vector debug = %+v", vectorItem.Debug)
+	}
+	if _, ok := byChunkID["chunk-hybrid-slug"]; ok {
+		t.Fatalf("unrelated slug chunk should not be recalled: %+v", items)
+	}
+
+	messages, err := service.RecallMessages(ctx, aiMemoryRecallInput{
+		ConversationID: "conv-hybrid-slug",
+		UserID:         userID,
+		Query:          "two-sum 的思路",
+	})
+	if err != nil {
+		t.Fatalf("RecallMessages() error = %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("RecallMessages() len = %d, want 1", len(messages))
+	}
+	assertAIMemoryRecallContains(t, messages[0].Content, "[semantic/rag] LeetCode two-sum")
+}
+
+func TestAIMemoryHybridRecallUsesKeywordsWithoutEmbedder(t *testing.T) {
+	db := newAIMemoryWritebackTestDB(t)
+	service := newAIMemoryWritebackTestService(db, nil)
+	service.embedder = nil
+	service.vectorSearcher = nil
+	restore := setAIMemoryTestConfig(t, config.AIMemory{
+		Enabled:              true,
+		EnableLongTermMemory: true,
+		LexicalRecallEnabled: true,
+		RecallTopK:           3,
+		RecallMaxChars:       4000,
+		RAGMaxChars:          2000,
+	})
+	defer restore()
+
+	ctx := context.Background()
+	userID := uint(42)
+	upsertAIMemoryRecallDocumentChunk(t, service, userID, "doc-lexical-only", "chunk-lexical-only",
+		"44444444-4444-4444-4444-444444444444", "线段树区间修改需要懒标记下传。")
+	otherUserID := uint(43)
+	upsertAIMemoryRecallDocumentChunk(t, service, otherUserID, "doc-lexical-other", "chunk-lexical-other",
+		"45555555-5555-5555-5555-555555555555", "线段树懒标记，这是别人的笔记。")
+	if err := service.IndexPendingChunkTerms(ctx, 0); err != nil {
+		t.Fatalf("IndexPendingChunkTerms() error = %v", err)
+	}
+
+	messages, err := service.RecallMessages(ctx, aiMemoryRecallInput{
+		ConversationID: "conv-lexical-only",
+		UserID:         userID,
+		Query:          "线段树懒标记",
+	})
+	if err != nil {
+		t.Fatalf("RecallMessages() error = %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("RecallMessages() len = %d, want 1", len(messages))
+	}
+	assertAIMemoryRecallContains(t, messages[0].Content, "懒标记下传")
+	if strings.Contains(messages[0].Content, "别人的笔记") {
+		t.Fatalf("keyword recall leaked other user's chunk:\n%s", messages[0].Content)
+	}
+}
+
+func TestAIMemoryHybridRecallRerankReordersAndFailsOpen(t *testing.T) {
+	db := newAIMemoryWritebackTestDB(t)
+	service := newAIMemoryWritebackTestService(db, nil)
+	service.embedder = &fakeMemoryEmbedder{vectors: [][]float32{{0.1, 0.2, 0.3}}}
+	service.vectorSearcher = &fakeMemoryVectorStore{
+		searchResults: []aidomain.MemoryVectorSearchResult{
+			{QdrantPointID: "46666666-6666-6666-6666-666666666666", Score: 0.93},
+			{QdrantPointID: "47777777-7777-7777-7777-777777777777", Score: 0.81},
+		},
+	}
+	restore := setAIMemoryTestConfig(t, config.AIMemory{
+		Enabled:              true,
+		EnableLongTermMemory: true,
+		RecallTopK:           3,
+		RecallMaxChars:       4000,
+		RecallMinScore:       0.5,
+		RAGMaxChars:          2000,
+		EmbedModel:           "qwen3-vl-embedding",
+		EmbedDimension:       3,
+		RerankTopN:           5,
+	})
+	defer restore()
+
+	ctx := context.Background()
+	userID := uint(44)
+	upsertAIMemoryRecallDocumentChunk(t, service, userID, "doc-rerank-a", "chunk-rerank-a",
+		"46666666-6666-6666-6666-666666666666", "向量最相近但答非所问的片段。")
+	upsertAIMemoryRecallDocumentChunk(t, service, userID, "doc-rerank-b", "chunk-rerank-b",
+		"47777777-7777-7777-7777-777777777777", "真正回答问题的片段。")
+	input := aiMemoryRecallInput{ConversationID: "conv-rerank", UserID: userID, Query: "哪个片段回答了问题"}
+
+	reranker := &fakeMemoryReranker{results: []aidomain.MemoryRerankResult{
+		{Index: 1, Score: 0.97},
+		{Index: 0, Score: 0.12},
+	}}
+	service.reranker = reranker
+	items, err := service.recallLongTermDocuments(ctx, input)
+	if err != nil {
+		t.Fatalf("recallLongTermDocuments() error = %v", err)
+	}
+	if len(items) != 2 || items[0].Chunk.ID != "chunk-rerank-b" || items[1].Chunk.ID != "chunk-rerank-a" {
+		t.Fatalf("reranked items = %+v", items)
+	}
+	if !items[0].Debug.Reranked || items[0].Debug.RerankScore != 0.97 || items[0].Debug.Rank != 1 || items[0].Debug.VectorRank != 2 {
+		t.Fatalf("reranked debug = %+v", items[0].Debug)
+	}
+	if reranker.input.Query != input.Query || len(reranker.input.Documents) != 2 ||
+		!strings.Contains(reranker.input.Documents[0], "答非所问") {
+		t.Fatalf("rerank input = %+v", reranker.input)
+	}
+	messages, err := service.RecallMessages(ctx, input)
+	if err != nil {
+		t.Fatalf("RecallMessages() error = %v", err)
+	}
+	content := messages[0].Content
+	if first, second := strings.Index(content, "真正回答问题"), strings.Index(content, "答非所问"); first < 0 || second < 0 || first > second {
+		t.Fatalf("prompt should follow rerank order:\n%s", content)
+	}
+
+	service.reranker = &fakeMemoryReranker{err: stderrors.New("rerank timeout")}
+	items, err = service.recallLongTermDocuments(ctx, input)
+	if err != nil {
+		t.Fatalf("recallLongTermDocuments() with failing reranker error = %v", err)
+	}
+	if len(items) != 2 || items[0].Chunk.ID != "chunk-rerank-a" || items[0].Debug.Reranked {
+		t.Fatalf("fail-open items = %+v", items)
+	}
+}
+
+func TestAIMemoryIndexDocumentsBuildsKeywordTerms(t *testing.T) {
+	db := newAIMemoryWritebackTestDB(t)
+	service := newAIMemoryWritebackTestService(db, nil)
+	service.chunker = aimemory.NewParagraphChunker(aimemory.ChunkerOptions{MaxChars: 100, OverlapChars: 0})
+	service.embedder = &fakeMemoryEmbedder{}
+	service.vectorStore = &fakeMemoryVectorStore{}
+	restore := setAIMemoryTestConfig(t, config.AIMemory{
+		Enabled:              true,
+		EnableLongTermMemory: true,
+		LexicalRecallEnabled: true,
+		EmbedModel:           "qwen3-vl-embedding",
+		EmbedDimension:       3,
+	})
+	defer restore()
+
+	doc := createMemoryIndexDocument(t, service, "doc-index-terms", "AtCoder abc300_a 的题解要点。")
+	if err := service.IndexDocuments(context.Background(), []string{doc.ID}); err != nil {
+		t.Fatalf("IndexDocuments() error = %v", err)
+	}
+	var terms []*entity.AIMemoryChunkTerm
+	if err := db.Where("document_id = ?", doc.ID).Find(&terms).Error; err != nil {
+		t.Fatalf("load terms error = %v", err)
+	}
+	got := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		got[term.Term] = true
+	}
+	for _, want := range []string{"atcoder", "abc300-a", "abc300", "300"} {
+		if !got[want] {
+			t.Fatalf("term %q missing from %v", want, got)
+		}
+	}
+	pending, err := service.repo.ListChunksMissingTerms(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("ListChunksMissingTerms() error = %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("pending chunks = %d, want 0", len(pending))
+	}
+}
+
+func TestAIMemoryRecallTraceExplainsChunkSelection(t *testing.T) {
+	restore := setAIMemoryTestConfig(t, config.AIMemory{
+		Enabled:              true,
+		EnableLongTermMemory: true,
+		RecallMaxChars:       4000,
+		RAGMaxChars:          2000,
+		RecallTrace:          true,
+	})
+	defer restore()
+
+	items := []aiMemoryRAGRecallItem{
+		{
+			Chunk: &entity.AIMemoryDocumentChunk{ID: "chunk-trace-2", DocumentID: "doc-trace-2", MemoryType: "semantic", Topic: "oj", ContentText: "P1001 | A+B 题解"},
+			Debug: aiMemoryRAGRecallDebug{LexicalRank: 1, LexicalScore: 2.5, MatchedTerms: []string{"p1001"}, FusionScore: 0.0164, Rank: 1},
+		},
+		{
+			Score: 0.91,
+			Chunk: &entity.AIMemoryDocumentChunk{ID: "chunk-trace-1", DocumentID: "doc-trace-1", MemoryType: "semantic", Topic: "oj", ContentText: "向量命中的片段"},
+			Debug: aiMemoryRAGRecallDebug{VectorRank: 1, VectorScore: 0.91, FusionScore: 0.0164, Reranked: true, RerankScore: 0.4, Rank: 2},
+		},
+	}
+	content, diagnostics := buildAIMemoryContextContent(nil, nil, items, 4000)
+	if strings.Index(content, "P1001") > strings.Index(content, "向量命中的片段") {
+		t.Fatalf("RAG lines should follow fused rank:\n%s", content)
+	}
+	assertAIMemoryRecallContains(t, content, "- [semantic/oj] P1001")
+	if len(diagnostics.RAGTrace) != 2 || diagnostics.RAGTrace[0].DocumentID != "doc-trace-2" || !diagnostics.RAGTrace[1].Kept {
+		t.Fatalf("RAG trace = %+v", diagnostics.RAGTrace)
+	}
+
+	sink := &recordingAISink{}
+	(&AIService{}).emitMemoryRecallTrace(context.Background(), sink, diagnostics)
+	if len(sink.events) != 2 ||
+		sink.events[0].Name != aidomain.EventToolCallStarted ||
+		sink.events[1].Name != aidomain.EventToolCallFinished {
+		t.Fatalf("trace events = %+v", sink.events)
+	}
+	finished, ok := sink.events[1].Payload.(aidomain.ToolCallFinishedPayload)
+	if !ok || finished.Key != aiMemoryRecallTraceKey || finished.Content != "召回 2 条候选，注入 2 条" {
+		t.Fatalf("finished payload = %+v", sink.events[1].Payload)
+	}
+	assertAIMemoryRecallContains(t, finished.DetailMarkdown, "#1 2.500 (p1001)")
+	assertAIMemoryRecallContains(t, finished.DetailMarkdown, "P1001 \\| A+B 题解")
+	assertAIMemoryRecallContains(t, finished.DetailMarkdown, "#1 0.910 | - | 0.0164 | 0.400 | 是")
+}
@@ -8,6 +8,7 @@ import (
 	"personal_assistant/global"
 	aidomain "personal_assistant/internal/domain/ai"
 	"personal_assistant/internal/model/entity"
+	bizerrors "personal_assistant/pkg/errors"
 
 	"go.uber.org/zap"
 )
@@ -32,11 +33,15 @@ func (s *AIMemoryService) IndexPendingDocuments(ctx context.Context, limit int)
 	if limit <= 0 {
 		limit = aiMemoryIndexBatchSize()
 	}
-	docs, err := s.repo.ListDocumentsNeedingIndex(ctx, limit, aiMemoryEmbedModel(), aiMemoryEmbedDimension())
+	docs, err := s.repo.ListDocumentsNeedingIndex(ctx, limit, aiMemoryEmbedModelKey(), aiMemoryEmbedDimension())
 	if err != nil {
 		return err
 	}
-	return s.indexDocumentRows(ctx, docs)
+	if err := s.indexDocumentRows(ctx, docs); err != nil {
+		return err
+	}
+	// 顺带为升级前写入的存量 chunks 补建关键词倒排。
+	return s.IndexPendingChunkTerms(ctx, 0)
 }
 
 func (s *AIMemoryService) memoryIndexingReady() bool {
@@ -71,7 +76,7 @@ func (s *AIMemoryService) indexOneDocument(ctx context.Context, doc *entity.AIMe
 	}
 	texts := make([]string, 0, len(chunks))
 	for i := range chunks {
-		chunks[i].EmbeddingModel = aiMemoryEmbedModel()
+		chunks[i].EmbeddingModel = aiMemoryEmbedModelKey()
 		chunks[i].EmbeddingDimension = aiMemoryEmbedDimension()
 		texts = append(texts, chunks[i].ContentText)
 	}
@@ -87,6 +92,10 @@ func (s *AIMemoryService) indexOneDocument(ctx context.Context, doc *entity.AIMe
 	entities := make([]*entity.AIMemoryDocumentChunk, 0, len(chunks))
 	indexedAt := time.Now()
 	for i, chunk := range chunks {
+		// 任何 provider 返回的维度都必须和 collection 一致，否则拒绝写入，避免污染向量空间。
+		if len(embeddings.Vectors[i]) != chunk.EmbeddingDimension {
+			return fmt.Errorf("memory embedding dimension = %d, want %d", len(embeddings.Vectors[i]), chunk.EmbeddingDimension)
+		}
 		vectorChunks = append(vectorChunks, aidomain.MemoryVectorChunk{
 			Chunk:  chunk,
 			Vector: embeddings.Vectors[i],
@@ -99,7 +108,11 @@ func (s *AIMemoryService) indexOneDocument(ctx context.Context, doc *entity.AIMe
 	if err := s.vectorStore.UpsertChunks(ctx, vectorChunks); err != nil {
 		return err
 	}
-	return s.repo.ReplaceDocumentChunks(ctx, doc.ID, entities)
+	if err := s.repo.ReplaceDocumentChunks(ctx, doc.ID, entities); err != nil {
+		return err
+	}
+	s.saveChunkTermsFailOpen(ctx, entities)
+	return nil
 }
 
 func (s *AIMemoryService) triggerDocumentIndex(ctx context.Context, docs []*entity.AIMemoryDocument) {
@@ -124,6 +137,37 @@ func (s *AIMemoryService) triggerDocumentIndex(ctx context.Context, docs []*enti
 	}()
 }
 
+// dropDocumentIndex 同步删除文档的向量和 chunk 记录。
+func (s *AIMemoryService) dropDocumentIndex(ctx context.Context, documentID string) error {
+	if s.vectorStore != nil {
+		if err := s.vectorStore.DeleteDocumentChunks(ctx, documentID); err != nil {
+			return bizerrors.Wrap(bizerrors.CodeInternalError, err)
+		}
+	}
+	if err := s.repo.ReplaceDocumentChunks(ctx, documentID, nil); err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return nil
+}
+
+// purgeDocuments 逐份删除 Qdrant 向量后，再物理删除文档及其 chunks、版本和召回统计。
+func (s *AIMemoryService) purgeDocuments(ctx context.Context, documentIDs []string) error {
+	if len(documentIDs) == 0 {
+		return nil
+	}
+	if s.vectorStore != nil {
+		for _, documentID := range documentIDs {
+			if err := s.vectorStore.DeleteDocumentChunks(ctx, documentID); err != nil {
+				return bizerrors.Wrap(bizerrors.CodeInternalError, err)
+			}
+		}
+	}
+	if err := s.repo.PurgeDocuments(ctx, documentIDs); err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return nil
+}
+
 func memoryDocumentEntityToIndex(doc *entity.AIMemoryDocument) aidomain.MemoryDocumentForIndex {
 	if doc == nil {
 		return aidomain.MemoryDocumentForIndex{}
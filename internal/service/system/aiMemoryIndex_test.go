@@ -142,6 +142,57 @@ func TestAIMemoryIndexDocumentsDoesNotPersistChunksWhenVectorStoreFails(t *testi
 	}
 }
 
+func TestAIMemoryIndexPendingDocumentsReembedsAfterProviderSwitch(t *testing.T) {
+	db := newAIMemoryWritebackTestDB(t)
+	service := newAIMemoryWritebackTestService(db, nil)
+	service.chunker = aimemory.NewParagraphChunker(aimemory.ChunkerOptions{MaxChars: 100, OverlapChars: 0})
+	service.embedder = &fakeMemoryEmbedder{vectors: [][]float32{{0.1, 0.2, 0.3}}}
+	service.vectorStore = &fakeMemoryVectorStore{}
+	restore := setAIMemoryTestConfig(t, config.AIMemory{
+		Enabled:              true,
+		EnableLongTermMemory: true,
+		EmbedModel:           "qwen3-vl-embedding",
+		EmbedDimension:       3,
+	})
+	doc := createMemoryIndexDocument(t, service, "doc-index-switch", "切换 provider 前已索引的内容")
+	if err := service.IndexDocuments(context.Background(), []string{doc.ID}); err != nil {
+		t.Fatalf("IndexDocuments() error = %v", err)
+	}
+	restore()
+
+	restore = setAIMemoryTestConfig(t, config.AIMemory{
+		Enabled:              true,
+		EnableLongTermMemory: true,
+		EmbedProvider:        "local",
+		EmbedDimension:       16,
+	})
+	defer restore()
+	service.embedder = aimemory.NewHashingEmbedder(aimemory.EmbedderOptions{Dimension: 16})
+	vectorStore := &fakeMemoryVectorStore{}
+	service.vectorStore = vectorStore
+
+	if err := service.IndexPendingDocuments(context.Background(), 10); err != nil {
+		t.Fatalf("IndexPendingDocuments() error = %v", err)
+	}
+	chunks, err := service.repo.ListDocumentChunks(context.Background(), doc.ID)
+	if err != nil {
+		t.Fatalf("ListDocumentChunks() error = %v", err)
+	}
+	if len(chunks) != 1 || chunks[0].EmbeddingModel != "local/local-hash-v1" || chunks[0].EmbeddingDimension != 16 {
+		t.Fatalf("chunks after switch = %+v", chunks)
+	}
+	if len(vectorStore.upserted) != 1 || len(vectorStore.upserted[0].Vector) != 16 {
+		t.Fatalf("upserted after switch = %+v", vectorStore.upserted)
+	}
+
+	// 维度错误的 embedder 不允许写入新 collection。
+	service.embedder = &fakeMemoryEmbedder{vectors: [][]float32{{0.1, 0.2, 0.3}}}
+	mismatchDoc := createMemoryIndexDocument(t, service, "doc-index-switch-mismatch", "维度不一致的新内容")
+	if err := service.IndexDocuments(context.Background(), []string{mismatchDoc.ID}); err == nil {
+		t.Fatal("IndexDocuments() error = nil, want dimension mismatch")
+	}
+}
+
 func TestAIMemoryWritebackIndexFailureDoesNotFailTurnCompleted(t *testing.T) {
 	db := newAIMemoryWritebackTestDB(t)
 	service := newAIMemoryWritebackTestService(db, aimemory.NewRuleExtractor(aimemory.Options{DocumentMinRunes: 40}))
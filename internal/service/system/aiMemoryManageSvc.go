@@ -0,0 +1,588 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	aidomain "personal_assistant/internal/domain/ai"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	resp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	svccontract "personal_assistant/internal/service/contract"
+	bizerrors "personal_assistant/pkg/errors"
+)
+
+// AIMemoryManageService 为用户和组织管理员提供记忆的查看、修正和删除入口。
+// 记忆由 AIMemoryService 的 writeback 自动写入，这里只负责面向人的治理，
+// 权限仍然统一走 aiMemoryPolicy，保证管理入口和召回入口使用同一套 scope / visibility 边界。
+type AIMemoryManageService struct {
+	// memory 提供仓储、策略、向量存储和索引触发能力。
+	memory *AIMemoryService
+	// authorizationService 用于判断组织能力和超级管理员身份。
+	authorizationService svccontract.AuthorizationServiceContract
+}
+
+// aiMemoryManageScope 是一次管理请求解析出的作用域和授权事实。
+type aiMemoryManageScope struct {
+	scopeType  aidomain.MemoryScopeType
+	scopeKey   string
+	visibility aidomain.MemoryVisibility
+	access     aidomain.MemoryAccessContext
+}
+
+// NewAIMemoryManageService 基于记忆服务和授权服务构造记忆管理服务。
+func NewAIMemoryManageService(
+	memory *AIMemoryService,
+	authorizationService svccontract.AuthorizationServiceContract,
+) *AIMemoryManageService {
+	return &AIMemoryManageService{
+		memory:               memory,
+		authorizationService: authorizationService,
+	}
+}
+
+// ListFacts 分页检索当前作用域下的结构化事实，包含已过期记录。
+func (s *AIMemoryManageService) ListFacts(
+	ctx context.Context,
+	userID uint,
+	req *request.AIMemoryFactListReq,
+) (*resp.AIMemoryFactListResp, error) {
+	if req == nil {
+		req = &request.AIMemoryFactListReq{}
+	}
+	page, pageSize := normalizeAIMemoryPage(req.Page, req.PageSize)
+	scope, err := s.resolveScope(ctx, userID, &req.AIMemoryScopeReq)
+	if err != nil {
+		return nil, err
+	}
+	rows, total, err := s.memory.repo.PageFacts(ctx, aidomain.MemoryManageQuery{
+		ScopeKey:   scope.scopeKey,
+		Visibility: scope.visibility,
+		Keyword:    req.Keyword,
+		Namespace:  req.Namespace,
+		Offset:     (page - 1) * pageSize,
+		Limit:      pageSize,
+	})
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	now := time.Now()
+	list := make([]*resp.AIMemoryFactResp, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, aiMemoryFactToResp(row, now))
+	}
+	return &resp.AIMemoryFactListResp{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// UpdateFact 修正一条结构化事实。
+// 注意事项：
+//   - 修正后的来源改为用户声明或管理员设定，避免后续模型推断的写回把人工修正覆盖掉。
+func (s *AIMemoryManageService) UpdateFact(
+	ctx context.Context,
+	userID uint,
+	factID uint,
+	scopeReq *request.AIMemoryScopeReq,
+	req *request.UpdateAIMemoryFactReq,
+) (*resp.AIMemoryFactResp, error) {
+	if req == nil {
+		return nil, bizerrors.New(bizerrors.CodeInvalidParams)
+	}
+	if req.FactValueJSON != nil && !json.Valid([]byte(strings.TrimSpace(*req.FactValueJSON))) {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "fact_value_json 不是合法 JSON")
+	}
+	scope, err := s.resolveScope(ctx, userID, scopeReq)
+	if err != nil {
+		return nil, err
+	}
+	fact, err := s.loadFact(ctx, scope, factID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Summary != nil {
+		fact.Summary = strings.TrimSpace(*req.Summary)
+	}
+	if req.FactValueJSON != nil {
+		fact.FactValueJSON = strings.TrimSpace(*req.FactValueJSON)
+	}
+	if req.ClearExpiresAt {
+		fact.ExpiresAt = nil
+	} else if req.ExpiresAt != nil {
+		expiresAt := *req.ExpiresAt
+		fact.ExpiresAt = &expiresAt
+	}
+	fact.Confidence = 1
+	fact.SourceKind = string(aiMemoryManageSourceKind(scope))
+	fact.SourceID = aiMemoryManageSourceID(userID)
+	if err := s.memory.repo.UpdateFactContent(ctx, fact); err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	fact.UpdatedAt = time.Now()
+	return aiMemoryFactToResp(fact, time.Now()), nil
+}
+
+// DeleteFact 物理删除一条结构化事实。
+func (s *AIMemoryManageService) DeleteFact(
+	ctx context.Context,
+	userID uint,
+	factID uint,
+	scopeReq *request.AIMemoryScopeReq,
+) error {
+	scope, err := s.resolveScope(ctx, userID, scopeReq)
+	if err != nil {
+		return err
+	}
+	if _, err := s.loadFact(ctx, scope, factID); err != nil {
+		return err
+	}
+	if err := s.memory.repo.DeleteFact(ctx, factID); err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return nil
+}
+
+// ListDocuments 分页检索当前作用域下的长期记忆文档，列表不返回正文。
+func (s *AIMemoryManageService) ListDocuments(
+	ctx context.Context,
+	userID uint,
+	req *request.AIMemoryDocumentListReq,
+) (*resp.AIMemoryDocumentListResp, error) {
+	if req == nil {
+		req = &request.AIMemoryDocumentListReq{}
+	}
+	page, pageSize := normalizeAIMemoryPage(req.Page, req.PageSize)
+	scope, err := s.resolveScope(ctx, userID, &req.AIMemoryScopeReq)
+	if err != nil {
+		return nil, err
+	}
+	rows, total, err := s.memory.repo.PageDocuments(ctx, aidomain.MemoryManageQuery{
+		ScopeKey:   scope.scopeKey,
+		Visibility: scope.visibility,
+		Keyword:    req.Keyword,
+		MemoryType: aidomain.MemoryType(strings.TrimSpace(req.MemoryType)),
+		Offset:     (page - 1) * pageSize,
+		Limit:      pageSize,
+	})
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	now := time.Now()
+	list := make([]*resp.AIMemoryDocumentResp, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, aiMemoryDocumentToResp(row, now))
+	}
+	return &resp.AIMemoryDocumentListResp{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// GetDocument 返回单份长期记忆文档详情，包含正文和已索引片段数。
+func (s *AIMemoryManageService) GetDocument(
+	ctx context.Context,
+	userID uint,
+	documentID string,
+	scopeReq *request.AIMemoryScopeReq,
+) (*resp.AIMemoryDocumentResp, error) {
+	scope, err := s.resolveScope(ctx, userID, scopeReq)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := s.loadDocument(ctx, scope, documentID)
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := s.memory.repo.ListDocumentChunks(ctx, doc.ID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	item := aiMemoryDocumentToResp(doc, time.Now())
+	item.ContentText = doc.ContentText
+	item.ChunkCount = len(chunks)
+	return item, nil
+}
+
+// UpdateDocument 修正一份长期记忆文档。
+// 核心流程：
+//  1. 校验作用域与文档归属，按请求覆盖标题、摘要和正文。
+//  2. 正文变化时先同步清掉旧向量和旧 chunks，避免召回继续命中修正前的内容。
+//  3. 落库后触发异步重建索引；索引未就绪时由补偿任务按 updated_at 兜底。
+func (s *AIMemoryManageService) UpdateDocument(
+	ctx context.Context,
+	userID uint,
+	documentID string,
+	scopeReq *request.AIMemoryScopeReq,
+	req *request.UpdateAIMemoryDocumentReq,
+) (*resp.AIMemoryDocumentResp, error) {
+	if req == nil {
+		return nil, bizerrors.New(bizerrors.CodeInvalidParams)
+	}
+	if req.ContentText != nil && strings.TrimSpace(*req.ContentText) == "" {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "content_text 不能为空")
+	}
+	scope, err := s.resolveScope(ctx, userID, scopeReq)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := s.loadDocument(ctx, scope, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if doc.SourceKind == string(aidomain.MemorySourceKnowledgeBase) {
+		// 知识库文档需要保留版本历史，统一走知识库接口重新导入。
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "知识库文档请通过知识库接口更新")
+	}
+
+	contentChanged := false
+	if req.Title != nil {
+		doc.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Summary != nil {
+		doc.Summary = strings.TrimSpace(*req.Summary)
+	}
+	if req.ContentText != nil {
+		content := strings.TrimSpace(*req.ContentText)
+		contentChanged = content != doc.ContentText
+		doc.ContentText = content
+	}
+	doc.SourceKind = string(aiMemoryManageSourceKind(scope))
+	doc.SourceID = aiMemoryManageSourceID(userID)
+
+	if contentChanged {
+		if err := s.memory.dropDocumentIndex(ctx, doc.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.memory.repo.UpdateDocumentContent(ctx, doc); err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if contentChanged {
+		s.memory.triggerDocumentIndex(ctx, []*entity.AIMemoryDocument{doc})
+	}
+
+	doc.UpdatedAt = time.Now()
+	item := aiMemoryDocumentToResp(doc, time.Now())
+	item.ContentText = doc.ContentText
+	return item, nil
+}
+
+// DeleteDocument 物理删除一份长期记忆文档，并级联删除 Qdrant 向量。
+// 注意事项：
+//   - 先删向量再删库；向量删除失败时保留数据库记录，方便用户重试，不留下无主向量。
+func (s *AIMemoryManageService) DeleteDocument(
+	ctx context.Context,
+	userID uint,
+	documentID string,
+	scopeReq *request.AIMemoryScopeReq,
+) error {
+	scope, err := s.resolveScope(ctx, userID, scopeReq)
+	if err != nil {
+		return err
+	}
+	doc, err := s.loadDocument(ctx, scope, documentID)
+	if err != nil {
+		return err
+	}
+	return s.memory.purgeDocuments(ctx, []string{doc.ID})
+}
+
+// ClearScope 清空当前作用域下的全部 facts 和 documents，用于数据主体删除请求。
+func (s *AIMemoryManageService) ClearScope(
+	ctx context.Context,
+	userID uint,
+	scopeReq *request.AIMemoryScopeReq,
+) (*resp.AIMemoryClearResp, error) {
+	scope, err := s.resolveScope(ctx, userID, scopeReq)
+	if err != nil {
+		return nil, err
+	}
+	// 清空操作作用于整个 scope，先用策略确认当前主体对该 scope 本身有写权限。
+	if err := s.requireAccess(
+		scope,
+		scope.scopeKey,
+		string(scope.scopeType),
+		string(scope.visibility),
+		scopeTargetUserID(scope),
+		scopeTargetOrgID(scope),
+	); err != nil {
+		return nil, bizerrors.New(bizerrors.CodePermissionDenied)
+	}
+
+	// 软删除过的文档也要一并物理清理，数据主体删除不能留下任何残留。
+	documentIDs, err := s.memory.repo.ListDocumentIDsByScopeKey(ctx, scope.scopeKey)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if err := s.memory.purgeDocuments(ctx, documentIDs); err != nil {
+		return nil, err
+	}
+	deletedFacts, err := s.memory.repo.DeleteFactsByScopeKey(ctx, scope.scopeKey)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return &resp.AIMemoryClearResp{
+		DeletedFacts:     deletedFacts,
+		DeletedDocuments: len(documentIDs),
+	}, nil
+}
+
+// resolveScope 负责把请求中的作用域参数解析成 scope_key、visibility 和授权上下文。
+// 参数：
+//   - scopeReq.Scope：self / org / platform_ops，缺省为 self。
+//   - scopeReq.OrgID：scope=org 时必填。
+//
+// 注意事项：
+//   - org 作用域要求 org.manage.update 能力或超级管理员身份；platform_ops 只允许超级管理员。
+func (s *AIMemoryManageService) resolveScope(
+	ctx context.Context,
+	userID uint,
+	scopeReq *request.AIMemoryScopeReq,
+) (*aiMemoryManageScope, error) {
+	if s == nil || s.memory == nil || s.memory.repo == nil {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInternalError, "记忆服务未初始化")
+	}
+	if userID == 0 {
+		return nil, bizerrors.New(bizerrors.CodeUnauthorized)
+	}
+	if scopeReq == nil {
+		scopeReq = &request.AIMemoryScopeReq{}
+	}
+
+	principal := aidomain.AIToolPrincipal{UserID: userID}
+	scopeType := aidomain.MemoryScopeType(strings.TrimSpace(scopeReq.Scope))
+	switch scopeType {
+	case "", aidomain.MemoryScopeSelf:
+		return &aiMemoryManageScope{
+			scopeType:  aidomain.MemoryScopeSelf,
+			scopeKey:   aidomain.BuildSelfMemoryScopeKey(userID),
+			visibility: aidomain.MemoryVisibilitySelf,
+			access:     aidomain.MemoryAccessContext{Principal: principal},
+		}, nil
+
+	case aidomain.MemoryScopeOrg:
+		if scopeReq.OrgID == nil || *scopeReq.OrgID == 0 {
+			return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "scope=org 时必须指定 org_id")
+		}
+		orgID := *scopeReq.OrgID
+		isSuperAdmin, err := s.isSuperAdmin(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isSuperAdmin {
+			allowed, err := s.authorizationService.CheckUserCapabilityInOrg(ctx, userID, orgID, consts.CapabilityCodeOrgManageUpdate)
+			if err != nil {
+				return nil, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+			}
+			if !allowed {
+				return nil, bizerrors.New(bizerrors.CodePermissionDenied)
+			}
+		}
+		principal.IsSuperAdmin = isSuperAdmin
+		return &aiMemoryManageScope{
+			scopeType:  aidomain.MemoryScopeOrg,
+			scopeKey:   aidomain.BuildOrgMemoryScopeKey(orgID),
+			visibility: aidomain.MemoryVisibilityOrg,
+			access: aidomain.MemoryAccessContext{
+				Principal:      principal,
+				ApprovedOrgIDs: []uint{orgID},
+			},
+		}, nil
+
+	case aidomain.MemoryScopePlatformOps:
+		isSuperAdmin, err := s.isSuperAdmin(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isSuperAdmin {
+			return nil, bizerrors.New(bizerrors.CodePermissionDenied)
+		}
+		principal.IsSuperAdmin = true
+		return &aiMemoryManageScope{
+			scopeType:  aidomain.MemoryScopePlatformOps,
+			scopeKey:   aidomain.BuildPlatformOpsMemoryScopeKey(),
+			visibility: aidomain.MemoryVisibilitySuperAdmin,
+			access: aidomain.MemoryAccessContext{
+				Principal:        principal,
+				AllowPlatformOps: true,
+			},
+		}, nil
+	}
+	return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "不支持的记忆作用域")
+}
+
+func (s *AIMemoryManageService) isSuperAdmin(ctx context.Context, userID uint) (bool, error) {
+	if s.authorizationService == nil {
+		return false, bizerrors.NewWithMsg(bizerrors.CodeInternalError, "授权服务未初始化")
+	}
+	isSuperAdmin, err := s.authorizationService.IsSuperAdmin(ctx, userID)
+	if err != nil {
+		return false, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+	return isSuperAdmin, nil
+}
+
+// loadFact 读取 fact 并校验它属于当前作用域；越权与不存在统一返回不存在，避免泄露他人记忆是否存在。
+func (s *AIMemoryManageService) loadFact(
+	ctx context.Context,
+	scope *aiMemoryManageScope,
+	factID uint,
+) (*entity.AIMemoryFact, error) {
+	fact, err := s.memory.repo.GetFactByID(ctx, factID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if fact == nil {
+		return nil, bizerrors.New(bizerrors.CodeAIMemoryNotFound)
+	}
+	if err := s.requireAccess(scope, fact.ScopeKey, fact.ScopeType, fact.Visibility, fact.UserID, fact.OrgID); err != nil {
+		return nil, err
+	}
+	return fact, nil
+}
+
+// loadDocument 读取 document 并校验它属于当前作用域，语义同 loadFact。
+func (s *AIMemoryManageService) loadDocument(
+	ctx context.Context,
+	scope *aiMemoryManageScope,
+	documentID string,
+) (*entity.AIMemoryDocument, error) {
+	doc, err := s.memory.repo.GetDocumentByID(ctx, documentID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if doc == nil {
+		return nil, bizerrors.New(bizerrors.CodeAIMemoryNotFound)
+	}
+	if err := s.requireAccess(scope, doc.ScopeKey, doc.ScopeType, doc.Visibility, doc.UserID, doc.OrgID); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// requireAccess 要求记录落在请求的 scope 内，并通过记忆策略的写权限判定。
+func (s *AIMemoryManageService) requireAccess(
+	scope *aiMemoryManageScope,
+	scopeKey string,
+	scopeType string,
+	visibility string,
+	userID *uint,
+	orgID *uint,
+) error {
+	if strings.TrimSpace(scopeKey) != scope.scopeKey {
+		return bizerrors.New(bizerrors.CodeAIMemoryNotFound)
+	}
+	decision := s.memory.policy.CanWriteMemory(aidomain.MemoryAccessTarget{
+		ScopeType:  aidomain.MemoryScopeType(scopeType),
+		ScopeKey:   scopeKey,
+		Visibility: aidomain.MemoryVisibility(visibility),
+		UserID:     userID,
+		OrgID:      orgID,
+	}, scope.access)
+	if !decision.Allowed {
+		return bizerrors.New(bizerrors.CodeAIMemoryNotFound)
+	}
+	return nil
+}
+
+// aiMemoryManageSourceKind 返回人工修正后的来源类型：个人记忆视为用户声明，其余视为管理员设定。
+func aiMemoryManageSourceKind(scope *aiMemoryManageScope) aidomain.MemorySourceKind {
+	if scope.scopeType == aidomain.MemoryScopeSelf {
+		return aidomain.MemorySourceExplicitUserStatement
+	}
+	return aidomain.MemorySourceAdminSet
+}
+
+func aiMemoryManageSourceID(userID uint) string {
+	return fmt.Sprintf("memory_manage:user:%d", userID)
+}
+
+func scopeTargetUserID(scope *aiMemoryManageScope) *uint {
+	if scope.scopeType != aidomain.MemoryScopeSelf {
+		return nil
+	}
+	userID := scope.access.Principal.UserID
+	return &userID
+}
+
+func scopeTargetOrgID(scope *aiMemoryManageScope) *uint {
+	if scope.scopeType != aidomain.MemoryScopeOrg || len(scope.access.ApprovedOrgIDs) == 0 {
+		return nil
+	}
+	orgID := scope.access.ApprovedOrgIDs[0]
+	return &orgID
+}
+
+func normalizeAIMemoryPage(page int, pageSize int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	return page, pageSize
+}
+
+func aiMemoryExpired(expiresAt *time.Time, now time.Time) bool {
+	return expiresAt != nil && !expiresAt.After(now)
+}
+
+func aiMemoryFactToResp(fact *entity.AIMemoryFact, now time.Time) *resp.AIMemoryFactResp {
+	if fact == nil {
+		return nil
+	}
+	return &resp.AIMemoryFactResp{
+		ID:            fact.ID,
+		ScopeType:     fact.ScopeType,
+		Visibility:    fact.Visibility,
+		OrgID:         fact.OrgID,
+		Namespace:     fact.Namespace,
+		FactKey:       fact.FactKey,
+		FactValueJSON: fact.FactValueJSON,
+		Summary:       fact.Summary,
+		Confidence:    fact.Confidence,
+		SourceKind:    fact.SourceKind,
+		SourceID:      fact.SourceID,
+		EffectiveAt:   formatTimePtr(fact.EffectiveAt),
+		ExpiresAt:     formatTimePtr(fact.ExpiresAt),
+		Expired:       aiMemoryExpired(fact.ExpiresAt, now),
+		CreatedAt:     formatTime(fact.CreatedAt),
+		UpdatedAt:     formatTime(fact.UpdatedAt),
+	}
+}
+
+func aiMemoryDocumentToResp(doc *entity.AIMemoryDocument, now time.Time) *resp.AIMemoryDocumentResp {
+	if doc == nil {
+		return nil
+	}
+	return &resp.AIMemoryDocumentResp{
+		ID:          doc.ID,
+		ScopeType:   doc.ScopeType,
+		Visibility:  doc.Visibility,
+		OrgID:       doc.OrgID,
+		MemoryType:  doc.MemoryType,
+		Topic:       doc.Topic,
+		Title:       doc.Title,
+		Summary:     doc.Summary,
+		Importance:  doc.Importance,
+		SourceKind:  doc.SourceKind,
+		SourceID:    doc.SourceID,
+		EffectiveAt: formatTimePtr(doc.EffectiveAt),
+		ExpiresAt:   formatTimePtr(doc.ExpiresAt),
+		Expired:     aiMemoryExpired(doc.ExpiresAt, now),
+		CreatedAt:   formatTime(doc.CreatedAt),
+		UpdatedAt:   formatTime(doc.UpdatedAt),
+	}
+}
@@ -0,0 +1,242 @@
+package system
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	aidomain "personal_assistant/internal/domain/ai"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/model/entity"
+	bizerrors "personal_assistant/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+type fakeAIMemoryManageAuthorization struct {
+	superAdmins  map[uint]bool
+	capabilities map[uint]map[string]bool
+}
+
+func (f *fakeAIMemoryManageAuthorization) GetUserRoles(context.Context, uint) ([]entity.Role, error) {
+	return nil, nil
+}
+
+func (f *fakeAIMemoryManageAuthorization) CheckUserAPIPermission(context.Context, uint, string, string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeAIMemoryManageAuthorization) AuthorizeOrgCapability(
+	_ context.Context,
+	userID uint,
+	orgID uint,
+	capabilityCode string,
+) error {
+	if f.superAdmins[userID] || f.capabilities[orgID][capabilityCode] {
+		return nil
+	}
+	return bizerrors.New(bizerrors.CodePermissionDenied)
+}
+
+func (f *fakeAIMemoryManageAuthorization) IsSuperAdmin(_ context.Context, userID uint) (bool, error) {
+	return f.superAdmins[userID], nil
+}
+
+func (f *fakeAIMemoryManageAuthorization) CheckUserCapabilityInOrg(
+	_ context.Context,
+	_ uint,
+	orgID uint,
+	capabilityCode string,
+) (bool, error) {
+	return f.capabilities[orgID][capabilityCode], nil
+}
+
+func TestAIMemoryManageSelfScopeIsolatesUsers(t *testing.T) {
+	db := newAIMemoryWritebackTestDB(t)
+	memory := newAIMemoryWritebackTestService(db, nil)
+	service := NewAIMemoryManageService(memory, &fakeAIMemoryManageAuthorization{})
+	ctx := context.Background()
+
+	mustUpsertAIMemoryManageFact(t, memory, 7, "current_goal", "准备 NOI")
+	otherFactID := mustUpsertAIMemoryManageFact(t, memory, 8, "current_goal", "准备 ICPC")
+
+	list, err := service.ListFacts(ctx, 7, &request.AIMemoryFactListReq{Keyword: "NOI"})
+	if err != nil {
+		t.Fatalf("ListFacts() error = %v", err)
+	}
+	if list.Total != 1 || len(list.List) != 1 || list.List[0].Summary != "准备 NOI" {
+		t.Fatalf("ListFacts() = %+v, want only own NOI fact", list)
+	}
+
+	_, err = service.UpdateFact(ctx, 7, otherFactID, &request.AIMemoryScopeReq{}, &request.UpdateAIMemoryFactReq{
+		Summary: aiMemoryManageStringPtr("篡改"),
+	})
+	if bizErr := bizerrors.FromError(err); bizErr == nil || bizErr.Code != bizerrors.CodeAIMemoryNotFound {
+		t.Fatalf("UpdateFact(other user) error = %v, want memory not found", err)
+	}
+
+	updated, err := service.UpdateFact(ctx, 7, list.List[0].ID, &request.AIMemoryScopeReq{}, &request.UpdateAIMemoryFactReq{
+		Summary:       aiMemoryManageStringPtr("准备 CSP"),
+		FactValueJSON: aiMemoryManageStringPtr(`{"goal":"CSP"}`),
+	})
+	if err != nil {
+		t.Fatalf("UpdateFact() error = %v", err)
+	}
+	if updated.Summary != "准备 CSP" || updated.SourceKind != string(aidomain.MemorySourceExplicitUserStatement) {
+		t.Fatalf("UpdateFact() = %+v", updated)
+	}
+
+	if err := service.DeleteFact(ctx, 7, list.List[0].ID, nil); err != nil {
+		t.Fatalf("DeleteFact() error = %v", err)
+	}
+	if fact, _ := memory.repo.GetFactByID(ctx, otherFactID); fact == nil {
+		t.Fatal("other user's fact should be kept")
+	}
+}
+
+func TestAIMemoryManageDeleteDocumentCascadesToVectorStore(t *testing.T) {
+	db := newAIMemoryWritebackTestDB(t)
+	memory := newAIMemoryWritebackTestService(db, nil)
+	store := &fakeMemoryVectorStore{}
+	memory.vectorStore = store
+	service := NewAIMemoryManageService(memory, &fakeAIMemoryManageAuthorization{})
+	ctx := context.Background()
+
+	mustCreateAIMemoryManageDocument(t, db, "doc-self-7", 7)
+	mustCreateAIMemoryManageDocument(t, db, "doc-self-8", 8)
+
+	if err := service.DeleteDocument(ctx, 7, "doc-self-8", nil); bizerrors.FromError(err) == nil {
+		t.Fatalf("DeleteDocument(other user) error = %v, want biz error", err)
+	}
+	if store.deletedDocumentID != "" {
+		t.Fatalf("vector store should not be touched, deleted = %q", store.deletedDocumentID)
+	}
+
+	if err := service.DeleteDocument(ctx, 7, "doc-self-7", nil); err != nil {
+		t.Fatalf("DeleteDocument() error = %v", err)
+	}
+	if store.deletedDocumentID != "doc-self-7" {
+		t.Fatalf("deletedDocumentID = %q, want doc-self-7", store.deletedDocumentID)
+	}
+	var docCount, chunkCount int64
+	db.Unscoped().Model(&entity.AIMemoryDocument{}).Where("id = ?", "doc-self-7").Count(&docCount)
+	db.Model(&entity.AIMemoryDocumentChunk{}).Where("document_id = ?", "doc-self-7").Count(&chunkCount)
+	if docCount != 0 || chunkCount != 0 {
+		t.Fatalf("document rows = %d, chunk rows = %d, want purged", docCount, chunkCount)
+	}
+}
+
+func TestAIMemoryManageOrgAndPlatformScopesRequireAuthorization(t *testing.T) {
+	db := newAIMemoryWritebackTestDB(t)
+	memory := newAIMemoryWritebackTestService(db, nil)
+	auth := &fakeAIMemoryManageAuthorization{capabilities: map[uint]map[string]bool{}}
+	service := NewAIMemoryManageService(memory, auth)
+	ctx := context.Background()
+
+	orgID := uint(9)
+	if err := memory.repo.UpsertFact(ctx, &entity.AIMemoryFact{
+		ScopeKey:      aidomain.BuildOrgMemoryScopeKey(orgID),
+		ScopeType:     string(aidomain.MemoryScopeOrg),
+		Visibility:    string(aidomain.MemoryVisibilityOrg),
+		OrgID:         &orgID,
+		Namespace:     "org_policy",
+		FactKey:       "training_focus",
+		FactValueJSON: `{"focus":"dp"}`,
+		Summary:       "本月训练重点是动态规划",
+	}); err != nil {
+		t.Fatalf("UpsertFact() error = %v", err)
+	}
+	orgScope := request.AIMemoryScopeReq{Scope: "org", OrgID: &orgID}
+
+	_, err := service.ListFacts(ctx, 7, &request.AIMemoryFactListReq{AIMemoryScopeReq: orgScope})
+	if bizErr := bizerrors.FromError(err); bizErr == nil || bizErr.Code != bizerrors.CodePermissionDenied {
+		t.Fatalf("ListFacts(org without capability) error = %v, want permission denied", err)
+	}
+
+	auth.capabilities[orgID] = map[string]bool{consts.CapabilityCodeOrgManageUpdate: true}
+	list, err := service.ListFacts(ctx, 7, &request.AIMemoryFactListReq{AIMemoryScopeReq: orgScope})
+	if err != nil {
+		t.Fatalf("ListFacts(org) error = %v", err)
+	}
+	if list.Total != 1 {
+		t.Fatalf("ListFacts(org).Total = %d, want 1", list.Total)
+	}
+
+	_, err = service.ListDocuments(ctx, 7, &request.AIMemoryDocumentListReq{
+		AIMemoryScopeReq: request.AIMemoryScopeReq{Scope: "platform_ops"},
+	})
+	if bizErr := bizerrors.FromError(err); bizErr == nil || bizErr.Code != bizerrors.CodePermissionDenied {
+		t.Fatalf("ListDocuments(platform_ops) error = %v, want permission denied", err)
+	}
+
+	cleared, err := service.ClearScope(ctx, 7, &orgScope)
+	if err != nil {
+		t.Fatalf("ClearScope(org) error = %v", err)
+	}
+	if cleared.DeletedFacts != 1 {
+		t.Fatalf("ClearScope(org).DeletedFacts = %d, want 1", cleared.DeletedFacts)
+	}
+}
+
+func mustUpsertAIMemoryManageFact(t *testing.T, memory *AIMemoryService, userID uint, factKey string, summary string) uint {
+	t.Helper()
+	ctx := context.Background()
+	scopeKey := aidomain.BuildSelfMemoryScopeKey(userID)
+	if err := memory.repo.UpsertFact(ctx, &entity.AIMemoryFact{
+		ScopeKey:      scopeKey,
+		ScopeType:     string(aidomain.MemoryScopeSelf),
+		Visibility:    string(aidomain.MemoryVisibilitySelf),
+		UserID:        &userID,
+		Namespace:     "oj_goal",
+		FactKey:       factKey,
+		FactValueJSON: `{}`,
+		Summary:       summary,
+		SourceKind:    string(aidomain.MemorySourceModelInferred),
+	}); err != nil {
+		t.Fatalf("UpsertFact() error = %v", err)
+	}
+	rows, _, err := memory.repo.PageFacts(ctx, aidomain.MemoryManageQuery{
+		ScopeKey:   scopeKey,
+		Visibility: aidomain.MemoryVisibilitySelf,
+	})
+	if err != nil || len(rows) == 0 {
+		t.Fatalf("PageFacts() rows = %d, error = %v", len(rows), err)
+	}
+	return rows[0].ID
+}
+
+func mustCreateAIMemoryManageDocument(t *testing.T, db *gorm.DB, id string, userID uint) {
+	t.Helper()
+	now := time.Now()
+	if err := db.Create(&entity.AIMemoryDocument{
+		ID:          id,
+		ScopeKey:    aidomain.BuildSelfMemoryScopeKey(userID),
+		ScopeType:   string(aidomain.MemoryScopeSelf),
+		Visibility:  string(aidomain.MemoryVisibilitySelf),
+		UserID:      &userID,
+		MemoryType:  "semantic",
+		Title:       "学习计划",
+		ContentText: "每周完成 20 道动态规划题。",
+		DedupKey:    "dedup-" + id,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}).Error; err != nil {
+		t.Fatalf("create document: %v", err)
+	}
+	if err := db.Create(&entity.AIMemoryDocumentChunk{
+		ID:            id + "-0",
+		DocumentID:    id,
+		QdrantPointID: "point-" + id,
+		ChunkIndex:    0,
+		ContentText:   "每周完成 20 道动态规划题。",
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}).Error; err != nil {
+		t.Fatalf("create chunk: %v", err)
+	}
+}
+
+func aiMemoryManageStringPtr(value string) *string {
+	return &value
+}
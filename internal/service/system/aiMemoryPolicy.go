@@ -716,7 +716,7 @@ func privateMemorySourcePriority(sourceKind aidomain.MemorySourceKind) int {
 
 func publicMemorySourcePriority(sourceKind aidomain.MemorySourceKind) int {
 	switch sourceKind {
-	case aidomain.MemorySourceAdminSet:
+	case aidomain.MemorySourceAdminSet, aidomain.MemorySourceKnowledgeBase:
 		return 4
 	case aidomain.MemorySourceExplicitUserStatement:
 		return 3
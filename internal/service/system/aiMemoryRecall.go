@@ -2,9 +2,11 @@ package system
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"personal_assistant/global"
@@ -28,10 +30,20 @@ const (
 )
 
 type aiMemoryRAGRecallItem struct {
+	// Score 是向量相似度；只被关键词命中的片段为 0。
 	Score          float64
 	Chunk          *entity.AIMemoryDocumentChunk
 	ExpandedChunks []*entity.AIMemoryDocumentChunk
 	ExpandedText   string
+	// Debug 记录该片段在各路召回中的名次和分数。
+	Debug aiMemoryRAGRecallDebug
+}
+
+// aiMemoryRAGRecallScope 描述一次向量检索的作用域过滤条件；UserID 为 0 表示不按用户过滤。
+type aiMemoryRAGRecallScope struct {
+	ScopeKey   string
+	Visibility aidomain.MemoryVisibility
+	UserID     uint
 }
 
 type aiRecentMessageSelection struct {
@@ -172,6 +184,8 @@ func (s *AIMemoryService) recallLongTermDocumentsFailOpen(
 	return nil
 }
 
+// recallLongTermDocuments 混合召回长期文档：向量与 BM25 关键词两路分别检索，按 RRF 融合后可选重排。
+// 任意一路失败时降级为只用另一路，两路都失败才返回错误。
 func (s *AIMemoryService) recallLongTermDocuments(
 	ctx context.Context,
 	input aiMemoryRecallInput,
@@ -181,12 +195,50 @@ func (s *AIMemoryService) recallLongTermDocuments(
 		query == "" ||
 		s == nil ||
 		s.repo == nil ||
-		s.embedder == nil ||
-		s.vectorSearcher == nil ||
 		input.UserID == 0 {
 		return nil, nil
 	}
 
+	scopes := buildAIMemoryRAGRecallScopes(input)
+	vectorRanked, vectorErr := s.recallVectorChunks(ctx, query, scopes)
+	lexicalRanked, lexicalErr := s.recallLexicalChunks(ctx, query, scopes)
+	if vectorErr != nil && lexicalErr != nil {
+		return nil, errors.Join(vectorErr, lexicalErr)
+	}
+	if vectorErr != nil || lexicalErr != nil {
+		if global.Log != nil {
+			global.Log.Warn(
+				"AI memory hybrid recall degraded",
+				zap.String("conversation_id", input.ConversationID),
+				zap.NamedError("vector_error", vectorErr),
+				zap.NamedError("lexical_error", lexicalErr),
+			)
+		}
+		if vectorErr != nil && len(lexicalRanked) == 0 {
+			return nil, vectorErr
+		}
+	}
+
+	items := s.fuseAIMemoryRAGCandidates(ctx, query, vectorRanked, lexicalRanked)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	if err := s.expandRAGItems(ctx, scopes, items); err != nil {
+		return nil, err
+	}
+	s.recordDocumentRecallsFailOpen(ctx, items)
+	return items, nil
+}
+
+// recallVectorChunks 按 query vector 在各作用域内检索 chunks，未配置 embedder 或向量库时返回空。
+func (s *AIMemoryService) recallVectorChunks(
+	ctx context.Context,
+	query string,
+	scopes []aiMemoryRAGRecallScope,
+) ([]aiMemoryRankedChunk, error) {
+	if s.embedder == nil || s.vectorSearcher == nil {
+		return nil, nil
+	}
 	embedding, err := s.embedder.Embed(ctx, aidomain.MemoryEmbeddingInput{Texts: []string{query}})
 	if err != nil {
 		return nil, err
@@ -200,17 +252,26 @@ func (s *AIMemoryService) recallLongTermDocuments(
 	}
 
 	minScore := aiMemoryRecallMinScore()
-	results, err := s.vectorSearcher.SearchChunks(ctx, aidomain.MemoryVectorSearchInput{
-		Vector:     vector,
-		ScopeKey:   aidomain.BuildSelfMemoryScopeKey(input.UserID),
-		Visibility: string(aidomain.MemoryVisibilitySelf),
-		UserID:     input.UserID,
-		Limit:      aiMemoryRecallTopK(),
-		MinScore:   minScore,
-	})
-	if err != nil {
-		return nil, err
+	results := make([]aidomain.MemoryVectorSearchResult, 0, aiMemoryRecallTopK()*len(scopes))
+	for _, scope := range scopes {
+		scopeResults, err := s.vectorSearcher.SearchChunks(ctx, aidomain.MemoryVectorSearchInput{
+			Vector:     vector,
+			ScopeKey:   scope.ScopeKey,
+			Visibility: string(scope.Visibility),
+			UserID:     scope.UserID,
+			Limit:      aiMemoryRecallTopK(),
+			MinScore:   minScore,
+		})
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, scopeResults...)
 	}
+	// 个人记忆与组织知识库分别检索后按得分统一排序，
+	// 避免组织文档数量较多时挤占个人记忆，或反过来。
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
 	pointIDs := make([]string, 0, len(results))
 	for _, result := range results {
 		if result.Score < minScore {
@@ -229,37 +290,70 @@ func (s *AIMemoryService) recallLongTermDocuments(
 		return nil, err
 	}
 	chunkByPointID := make(map[string]*entity.AIMemoryDocumentChunk, len(chunks))
-	scopeKey := aidomain.BuildSelfMemoryScopeKey(input.UserID)
 	for _, chunk := range chunks {
-		if !isValidAIMemoryRAGChunk(chunk, scopeKey, input.UserID) {
+		if !isValidAIMemoryRAGChunk(chunk, scopes) {
 			continue
 		}
 		chunkByPointID[strings.TrimSpace(chunk.QdrantPointID)] = chunk
 	}
 
-	items := make([]aiMemoryRAGRecallItem, 0, len(results))
+	ranked := make([]aiMemoryRankedChunk, 0, len(results))
+	seenPointIDs := make(map[string]struct{}, len(results))
 	for _, result := range results {
 		if result.Score < minScore {
 			continue
 		}
-		chunk := chunkByPointID[strings.TrimSpace(result.QdrantPointID)]
+		pointID := strings.TrimSpace(result.QdrantPointID)
+		if _, ok := seenPointIDs[pointID]; ok {
+			continue
+		}
+		chunk := chunkByPointID[pointID]
 		if chunk == nil {
 			continue
 		}
-		items = append(items, aiMemoryRAGRecallItem{Score: result.Score, Chunk: chunk})
+		seenPointIDs[pointID] = struct{}{}
+		ranked = append(ranked, aiMemoryRankedChunk{Chunk: chunk, Score: result.Score})
 	}
-	if len(items) == 0 {
-		return nil, nil
+	return ranked, nil
+}
+
+// buildAIMemoryRAGRecallScopes 返回本轮允许召回的长期记忆作用域。
+// 个人记忆始终参与召回；用户选中组织时，额外召回该组织的 org 可见文档（包括管理员导入的知识库）。
+func buildAIMemoryRAGRecallScopes(input aiMemoryRecallInput) []aiMemoryRAGRecallScope {
+	scopes := []aiMemoryRAGRecallScope{{
+		ScopeKey:   aidomain.BuildSelfMemoryScopeKey(input.UserID),
+		Visibility: aidomain.MemoryVisibilitySelf,
+		UserID:     input.UserID,
+	}}
+	if orgID := input.ToolCallCtx.Principal.CurrentOrgID; orgID != nil && *orgID > 0 {
+		scopes = append(scopes, aiMemoryRAGRecallScope{
+			ScopeKey:   aidomain.BuildOrgMemoryScopeKey(*orgID),
+			Visibility: aidomain.MemoryVisibilityOrg,
+		})
 	}
-	if err := s.expandRAGItems(ctx, input.UserID, items); err != nil {
-		return nil, err
+	return scopes
+}
+
+// recordDocumentRecallsFailOpen 按文档累计召回统计，得分取向量相似度（纯关键词命中记 0）；统计失败只记日志，不影响本轮回答。
+func (s *AIMemoryService) recordDocumentRecallsFailOpen(ctx context.Context, items []aiMemoryRAGRecallItem) {
+	scores := make(map[string]float64, len(items))
+	for _, item := range items {
+		if item.Chunk == nil {
+			continue
+		}
+		documentID := strings.TrimSpace(item.Chunk.DocumentID)
+		if score, ok := scores[documentID]; !ok || item.Score > score {
+			scores[documentID] = item.Score
+		}
+	}
+	if err := s.repo.RecordDocumentRecalls(ctx, scores, time.Now()); err != nil && global.Log != nil {
+		global.Log.Warn("AI memory record document recalls failed", zap.Error(err))
 	}
-	return items, nil
 }
 
 func (s *AIMemoryService) expandRAGItems(
 	ctx context.Context,
-	userID uint,
+	scopes []aiMemoryRAGRecallScope,
 	items []aiMemoryRAGRecallItem,
 ) error {
 	refs := make([]aidomain.MemoryDocumentChunkRef, 0, len(items)*3)
@@ -285,10 +379,9 @@ func (s *AIMemoryService) expandRAGItems(
 	if err != nil {
 		return err
 	}
-	scopeKey := aidomain.BuildSelfMemoryScopeKey(userID)
 	chunkByRef := make(map[string]*entity.AIMemoryDocumentChunk, len(rows))
 	for _, row := range rows {
-		if !isValidAIMemoryRAGChunk(row, scopeKey, userID) {
+		if !isAIMemoryChunkInRecallScopes(row, scopes) {
 			continue
 		}
 		chunkByRef[buildAIMemoryChunkRefKey(row.DocumentID, row.ChunkIndex)] = row
@@ -322,20 +415,31 @@ func (s *AIMemoryService) expandRAGItems(
 	return nil
 }
 
-func isValidAIMemoryRAGChunk(chunk *entity.AIMemoryDocumentChunk, scopeKey string, userID uint) bool {
+func isValidAIMemoryRAGChunk(chunk *entity.AIMemoryDocumentChunk, scopes []aiMemoryRAGRecallScope) bool {
 	if chunk == nil {
 		return false
 	}
-	if chunk.EmbeddingModel != aiMemoryEmbedModel() || chunk.EmbeddingDimension != aiMemoryEmbedDimension() {
+	if chunk.EmbeddingModel != aiMemoryEmbedModelKey() || chunk.EmbeddingDimension != aiMemoryEmbedDimension() {
 		return false
 	}
-	if chunk.ScopeKey != scopeKey || chunk.Visibility != string(aidomain.MemoryVisibilitySelf) {
+	return isAIMemoryChunkInRecallScopes(chunk, scopes)
+}
+
+// isAIMemoryChunkInRecallScopes 只复核作用域；关键词召回不依赖向量空间，不校验 embedding 模型与维度。
+func isAIMemoryChunkInRecallScopes(chunk *entity.AIMemoryDocumentChunk, scopes []aiMemoryRAGRecallScope) bool {
+	if chunk == nil {
 		return false
 	}
-	if chunk.UserID == nil || *chunk.UserID != userID {
-		return false
+	for _, scope := range scopes {
+		if chunk.ScopeKey != scope.ScopeKey || chunk.Visibility != string(scope.Visibility) {
+			continue
+		}
+		if scope.UserID > 0 && (chunk.UserID == nil || *chunk.UserID != scope.UserID) {
+			continue
+		}
+		return true
 	}
-	return true
+	return false
 }
 
 func buildAIMemoryChunkRefKey(documentID string, chunkIndex int) string {
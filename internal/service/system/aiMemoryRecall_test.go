@@ -9,8 +9,10 @@ import (
 	"time"
 
 	aidomain "personal_assistant/internal/domain/ai"
+	aimemory "personal_assistant/internal/infrastructure/ai/memory"
 	"personal_assistant/internal/model/config"
 	"personal_assistant/internal/model/entity"
+	reposystem "personal_assistant/internal/repository/system"
 )
 
 func TestAIMemoryRecallMessagesDisabledReturnsEmpty(t *testing.T) {
@@ -289,6 +291,76 @@ func TestAIMemoryRecallMessagesExpandsAdjacentChunksAndDedupesOverlap(t *testing
 	}
 }
 
+func TestAIMemoryRecallMessagesWithLocalVectorStoreEndToEnd(t *testing.T) {
+	db := newAIMemoryWritebackTestDB(t)
+	service := newAIMemoryWritebackTestService(db, nil)
+	service.chunker = aimemory.NewParagraphChunker(aimemory.ChunkerOptions{MaxChars: 200, OverlapChars: 0})
+	service.embedder = aimemory.NewHashingEmbedder(aimemory.EmbedderOptions{Dimension: 64})
+	restore := setAIMemoryTestConfig(t, config.AIMemory{
+		Enabled:              true,
+		EnableLongTermMemory: true,
+		RecallTopK:           3,
+		RecallMaxChars:       4000,
+		RecallMinScore:       0.1,
+		RAGMaxChars:          2000,
+		EmbedProvider:        "local",
+		EmbedDimension:       64,
+		VectorStore:          "local",
+	})
+	defer restore()
+	store := aimemory.NewLocalVectorStore(reposystem.NewAIMemoryVectorRepository(db), aimemory.LocalVectorStoreOptions{
+		CollectionName: aiMemoryCollectionName(),
+	})
+	service.vectorStore = store
+	service.vectorSearcher = store
+
+	ownDoc := createMemoryIndexDocument(t, service, "doc-local-own", "背包问题的状态转移：dp[j] = max(dp[j], dp[j-w] + v)，倒序枚举容量。")
+	otherUserID := uint(99)
+	otherDoc := &entity.AIMemoryDocument{
+		ID:          "doc-local-other",
+		ScopeKey:    aidomain.BuildSelfMemoryScopeKey(otherUserID),
+		ScopeType:   string(aidomain.MemoryScopeSelf),
+		Visibility:  string(aidomain.MemoryVisibilitySelf),
+		UserID:      &otherUserID,
+		MemoryType:  string(aidomain.MemoryTypeSemantic),
+		Title:       "other",
+		ContentText: "背包问题的状态转移：dp[j] = max(dp[j], dp[j-w] + v)，这是别人的笔记。",
+		SourceKind:  string(aidomain.MemorySourceModelInferred),
+		SourceID:    "msg-other",
+	}
+	if err := service.repo.BatchUpsertDocuments(context.Background(), []*entity.AIMemoryDocument{otherDoc}); err != nil {
+		t.Fatalf("BatchUpsertDocuments() error = %v", err)
+	}
+	if err := service.IndexDocuments(context.Background(), []string{ownDoc.ID, otherDoc.ID}); err != nil {
+		t.Fatalf("IndexDocuments() error = %v", err)
+	}
+
+	messages, err := service.RecallMessages(context.Background(), aiMemoryRecallInput{
+		ConversationID: "conv-local-vector",
+		UserID:         *ownDoc.UserID,
+		Query:          "背包问题 状态转移 怎么写",
+	})
+	if err != nil {
+		t.Fatalf("RecallMessages() error = %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("RecallMessages() len = %d, want 1", len(messages))
+	}
+	assertAIMemoryRecallContains(t, messages[0].Content, "倒序枚举容量")
+	if strings.Contains(messages[0].Content, "别人的笔记") {
+		t.Fatalf("recall leaked other user's document:\n%s", messages[0].Content)
+	}
+
+	if err := service.dropDocumentIndex(context.Background(), ownDoc.ID); err != nil {
+		t.Fatalf("dropDocumentIndex() error = %v", err)
+	}
+	var remaining int64
+	db.Model(&entity.AIMemoryChunkVector{}).Where("document_id = ?", ownDoc.ID).Count(&remaining)
+	if remaining != 0 {
+		t.Fatalf("remaining vectors = %d, want 0", remaining)
+	}
+}
+
 func TestAIMemoryRecallMessagesRAGFailureKeepsSummary(t *testing.T) {
 	db := newAIMemoryWritebackTestDB(t)
 	service := newAIMemoryWritebackTestService(db, nil)
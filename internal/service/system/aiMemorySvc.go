@@ -12,6 +12,8 @@ import (
 	"personal_assistant/internal/model/entity"
 	"personal_assistant/internal/repository"
 	"personal_assistant/internal/repository/interfaces"
+
+	"go.uber.org/zap"
 )
 
 var errAIMemoryPhase1NotImplemented = errors.New("ai memory phase 1 skeleton is not integrated yet")
@@ -66,6 +68,8 @@ type AIMemoryService struct {
 	vectorStore aidomain.MemoryVectorStore
 	// vectorSearcher 负责从 Qdrant 召回 memory chunks。
 	vectorSearcher aidomain.MemoryVectorSearcher
+	// reranker 对融合后的召回候选做可选重排，为空表示不重排。
+	reranker aidomain.MemoryReranker
 }
 
 // NewAIMemoryService 基于正式 repository group 构造记忆服务骨架。
@@ -74,7 +78,7 @@ func NewAIMemoryService(repositoryGroup *repository.Group) *AIMemoryService {
 		// Phase 1 先保证骨架可安全构造，不把 memory 变成启动期硬依赖。
 		return &AIMemoryService{}
 	}
-	qdrantStore := newAIMemoryQdrantStore()
+	vectorStore := newAIMemoryVectorStore(repositoryGroup)
 	return &AIMemoryService{
 		aiRepo:     repositoryGroup.SystemRepositorySupplier.GetAIRepository(),
 		repo:       repositoryGroup.SystemRepositorySupplier.GetAIMemoryRepository(),
@@ -85,15 +89,10 @@ func NewAIMemoryService(repositoryGroup *repository.Group) *AIMemoryService {
 			MaxChars:     aiMemoryChunkMaxChars(),
 			OverlapChars: aiMemoryChunkOverlapChars(),
 		}),
-		embedder: aimemory.NewDashScopeEmbedder(aimemory.EmbedderOptions{
-			APIKey:    aiMemoryAPIKey(),
-			Endpoint:  aiMemoryEmbedEndpoint(),
-			Model:     aiMemoryEmbedModel(),
-			Dimension: aiMemoryEmbedDimension(),
-			Timeout:   time.Duration(aiMemoryIndexTimeoutSeconds()) * time.Second,
-		}),
-		vectorStore:    qdrantStore,
-		vectorSearcher: qdrantStore,
+		embedder:       newAIMemoryEmbedder(),
+		vectorStore:    vectorStore,
+		vectorSearcher: vectorStore,
+		reranker:       newAIMemoryReranker(),
 	}
 }
 
@@ -127,7 +126,49 @@ func (s *AIMemoryService) ScheduleDocumentUpsert(ctx context.Context, docs []*en
 	return nil
 }
 
-func newAIMemoryQdrantStore() *aimemory.QdrantVectorStore {
+// newAIMemoryEmbedder 按配置创建 embedder；provider 非法时只告警并关闭索引与向量召回，不阻断启动。
+func newAIMemoryEmbedder() aidomain.MemoryEmbedder {
+	embedder, err := aimemory.NewEmbedder(aimemory.EmbedderOptions{
+		Provider:  aiMemoryEmbeddingSpace().Provider,
+		APIKey:    aiMemoryEmbedAPIKey(),
+		Endpoint:  aiMemoryEmbedEndpoint(),
+		BaseURL:   aiMemoryEmbedBaseURL(),
+		Model:     aiMemoryEmbedModel(),
+		Dimension: aiMemoryEmbedDimension(),
+		Timeout:   time.Duration(aiMemoryIndexTimeoutSeconds()) * time.Second,
+	})
+	if err != nil {
+		if global.Log != nil {
+			global.Log.Warn("AI memory embedder disabled", zap.Error(err))
+		}
+		return nil
+	}
+	return embedder
+}
+
+// aiMemoryVectorBackend 同时具备写入和检索能力，Qdrant 与本地实现都满足该约束。
+type aiMemoryVectorBackend interface {
+	aidomain.MemoryVectorStore
+	aidomain.MemoryVectorSearcher
+}
+
+// newAIMemoryVectorStore 按 ai.memory.vector_store 选择向量后端。
+// auto 模式下 Qdrant client 可用时走 Qdrant，否则回退到数据库暴力检索；显式 qdrant 但 client 不可用时关闭向量能力。
+func newAIMemoryVectorStore(repositoryGroup *repository.Group) aiMemoryVectorBackend {
+	switch aiMemoryVectorStoreMode() {
+	case aiMemoryVectorStoreQdrant:
+		return newAIMemoryQdrantStore()
+	case aiMemoryVectorStoreLocal:
+		return newAIMemoryLocalStore(repositoryGroup)
+	default:
+		if global.QdrantClient != nil {
+			return newAIMemoryQdrantStore()
+		}
+		return newAIMemoryLocalStore(repositoryGroup)
+	}
+}
+
+func newAIMemoryQdrantStore() aiMemoryVectorBackend {
 	if global.QdrantClient == nil {
 		return nil
 	}
@@ -135,3 +176,13 @@ func newAIMemoryQdrantStore() *aimemory.QdrantVectorStore {
 		CollectionName: aiMemoryCollectionName(),
 	})
 }
+
+func newAIMemoryLocalStore(repositoryGroup *repository.Group) aiMemoryVectorBackend {
+	records := repositoryGroup.SystemRepositorySupplier.GetAIMemoryVectorRepository()
+	if records == nil {
+		return nil
+	}
+	return aimemory.NewLocalVectorStore(records, aimemory.LocalVectorStoreOptions{
+		CollectionName: aiMemoryCollectionName(),
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	"personal_assistant/global"
 	aidomain "personal_assistant/internal/domain/ai"
+	aimemory "personal_assistant/internal/infrastructure/ai/memory"
 	"personal_assistant/internal/model/entity"
 )
 
@@ -70,16 +71,20 @@ func (s *AIMemoryService) buildWritebackSnapshot(
 	ctx context.Context,
 	input aiMemoryWritebackInput,
 ) (*aiMemoryWritebackSnapshot, error) {
-	messages, err := s.aiRepo.ListMessagesByConversation(ctx, input.ConversationID)
+	conversation, err := s.aiRepo.GetConversationByID(ctx, input.ConversationID)
 	if err != nil {
 		return nil, err
 	}
+	stored, err := s.aiRepo.ListMessagesByConversation(ctx, input.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	// 只基于活动分支写回记忆；本轮回复已被切走时，说明用户不再沿用这条分支，直接跳过。
+	tree := newAIMessageTree(conversation, stored)
+	messages := tree.activeBranch()
 	var userMessage *entity.AIMessage
 	var assistantMessage *entity.AIMessage
 	for _, item := range messages {
-		if item == nil {
-			continue
-		}
 		if item.ID == input.UserMessageID {
 			userMessage = item
 		}
@@ -292,7 +297,7 @@ func buildMemoryDocumentEntity(
 		DedupKey:       decision.DedupKey,
 		Importance:     normalizeMemoryConfidence(candidate.Confidence, 0.8),
 		QualityScore:   normalizeMemoryConfidence(candidate.Confidence, 0.8),
-		EmbeddingModel: aiMemoryEmbedModel(),
+		EmbeddingModel: aiMemoryEmbedModelKey(),
 		SourceKind:     string(candidate.SourceKind),
 		SourceID:       candidate.SourceID,
 		EffectiveAt:    &now,
@@ -548,14 +553,23 @@ func aiMemoryLongTermEnabled() bool {
 	return global.Config != nil && global.Config.AI.Memory.EnableLongTermMemory
 }
 
-func aiMemoryEmbedModel() string {
+// aiMemoryEmbeddingSpace 返回当前配置对应的 embedding 向量空间（已补齐 provider 默认值）。
+func aiMemoryEmbeddingSpace() aimemory.EmbeddingSpace {
 	if global.Config == nil {
-		return "qwen3-vl-embedding"
-	}
-	if value := strings.TrimSpace(global.Config.AI.Memory.EmbedModel); value != "" {
-		return value
+		return aimemory.NormalizeEmbeddingSpace("", "", 0)
 	}
-	return "qwen3-vl-embedding"
+	memoryCfg := global.Config.AI.Memory
+	return aimemory.NormalizeEmbeddingSpace(memoryCfg.EmbedProvider, memoryCfg.EmbedModel, memoryCfg.EmbedDimension)
+}
+
+// aiMemoryEmbedModel 返回调用 embedding 接口时使用的模型名称。
+func aiMemoryEmbedModel() string {
+	return aiMemoryEmbeddingSpace().Model
+}
+
+// aiMemoryEmbedModelKey 返回写入 chunk 的向量空间标识，用于识别需要重建的存量 chunks。
+func aiMemoryEmbedModelKey() string {
+	return aiMemoryEmbeddingSpace().ModelKey()
 }
 
 func aiMemoryAPIKey() string {
@@ -565,6 +579,16 @@ func aiMemoryAPIKey() string {
 	return strings.TrimSpace(global.Config.AI.APIKey)
 }
 
+func aiMemoryEmbedAPIKey() string {
+	if global.Config == nil {
+		return ""
+	}
+	if value := strings.TrimSpace(global.Config.AI.Memory.EmbedAPIKey); value != "" {
+		return value
+	}
+	return aiMemoryAPIKey()
+}
+
 func aiMemoryEmbedEndpoint() string {
 	if global.Config == nil {
 		return ""
@@ -572,11 +596,15 @@ func aiMemoryEmbedEndpoint() string {
 	return strings.TrimSpace(global.Config.AI.Memory.EmbedEndpoint)
 }
 
-func aiMemoryEmbedDimension() int {
-	if global.Config == nil || global.Config.AI.Memory.EmbedDimension <= 0 {
-		return 1024
+func aiMemoryEmbedBaseURL() string {
+	if global.Config == nil {
+		return ""
 	}
-	return global.Config.AI.Memory.EmbedDimension
+	return strings.TrimSpace(global.Config.AI.Memory.EmbedBaseURL)
+}
+
+func aiMemoryEmbedDimension() int {
+	return aiMemoryEmbeddingSpace().Dimension
 }
 
 func aiMemoryChunkMaxChars() int {
@@ -607,12 +635,36 @@ func aiMemoryIndexTimeoutSeconds() int {
 	return global.Config.AI.Memory.IndexTimeoutSeconds
 }
 
+const (
+	aiMemoryVectorStoreAuto   = "auto"
+	aiMemoryVectorStoreQdrant = "qdrant"
+	aiMemoryVectorStoreLocal  = "local"
+)
+
+func aiMemoryVectorStoreMode() string {
+	if global.Config == nil {
+		return aiMemoryVectorStoreAuto
+	}
+	switch mode := strings.ToLower(strings.TrimSpace(global.Config.AI.Memory.VectorStore)); mode {
+	case aiMemoryVectorStoreQdrant, aiMemoryVectorStoreLocal:
+		return mode
+	default:
+		return aiMemoryVectorStoreAuto
+	}
+}
+
+// aiMemoryCollectionName 返回 memory 向量集合名；本地向量库复用同一名称区分 embedding 空间。
+// Qdrant 未配置任何集合名时回退固定名称，保证本地后端也能按空间隔离。
 func aiMemoryCollectionName() string {
 	if global.Config == nil {
 		return ""
 	}
-	if value := strings.TrimSpace(global.Config.Qdrant.MemoryCollectionName); value != "" {
-		return value
+	base := strings.TrimSpace(global.Config.Qdrant.MemoryCollectionName)
+	if base == "" {
+		base = strings.TrimSpace(global.Config.Qdrant.CollectionName)
+	}
+	if base == "" {
+		base = "ai_memory_chunks"
 	}
-	return strings.TrimSpace(global.Config.Qdrant.CollectionName)
+	return aimemory.ResolveMemoryCollectionName(base, aiMemoryEmbeddingSpace())
 }
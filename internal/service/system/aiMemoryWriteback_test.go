@@ -476,6 +476,60 @@ func TestAIMemoryWritebackSkipsUnsuccessfulAssistantMessage(t *testing.T) {
 	assertAIMemoryWritebackCount(t, db, &entity.AIConversationSummary{}, 0)
 }
 
+func TestAIMemoryWritebackSkipsAssistantMessageOffActiveBranch(t *testing.T) {
+	db := newAIMemoryWritebackTestDB(t)
+	service := newAIMemoryWritebackTestService(db, aimemory.NewRuleExtractor(aimemory.Options{}))
+	restore := setAIMemoryTestConfig(t, config.AIMemory{
+		Enabled:              true,
+		EnableEntityMemory:   true,
+		EnableLongTermMemory: true,
+	})
+	defer restore()
+
+	createAIWritebackMessages(t, db, "conv-branch", "msg-user-branch", "msg-ai-branch-old", aiMessageStatusSuccess)
+	now := time.Now().Add(time.Second)
+	createAIWritebackMessageRows(t, db, &entity.AIMessage{
+		ID:             "msg-ai-branch-new",
+		ConversationID: "conv-branch",
+		ParentID:       "msg-user-branch",
+		Role:           aidomain.RoleAssistant,
+		Content:        "好的，之后会尽量简洁。",
+		Status:         aiMessageStatusSuccess,
+		TraceItemsJSON: "[]",
+		ScopeJSON:      "{}",
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	if err := db.Model(&entity.AIMessage{}).
+		Where("id = ?", "msg-ai-branch-old").
+		Update("parent_id", "msg-user-branch").Error; err != nil {
+		t.Fatalf("update parent: %v", err)
+	}
+	if err := db.Create(&entity.AIConversation{
+		ID:           "conv-branch",
+		UserID:       9,
+		Title:        "branch",
+		ActiveLeafID: "msg-ai-branch-new",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}).Error; err != nil {
+		t.Fatalf("create conversation: %v", err)
+	}
+
+	err := service.OnTurnCompleted(context.Background(), aiMemoryWritebackInput{
+		ConversationID:     "conv-branch",
+		UserID:             9,
+		UserMessageID:      "msg-user-branch",
+		AssistantMessageID: "msg-ai-branch-old",
+		Principal:          aidomain.AIToolPrincipal{UserID: 9},
+	})
+	if err != nil {
+		t.Fatalf("OnTurnCompleted() error = %v", err)
+	}
+	assertAIMemoryWritebackCount(t, db, &entity.AIConversationSummary{}, 0)
+	assertAIMemoryWritebackCount(t, db, &entity.AIMemoryFact{}, 0)
+}
+
 func TestAIServiceTriggerMemoryWritebackSwallowsHookError(t *testing.T) {
 	restore := setAIMemoryTestConfig(t, config.AIMemory{
 		Enabled:        true,
@@ -619,9 +673,14 @@ func newAIMemoryWritebackTestDB(t *testing.T) *gorm.DB {
 	if err := db.AutoMigrate(
 		&entity.AIConversation{},
 		&entity.AIMessage{},
+		&entity.AIConversationShare{},
 		&entity.AIMemoryFact{},
 		&entity.AIMemoryDocument{},
 		&entity.AIMemoryDocumentChunk{},
+		&entity.AIMemoryDocumentVersion{},
+		&entity.AIMemoryDocumentRecallStat{},
+		&entity.AIMemoryChunkVector{},
+		&entity.AIMemoryChunkTerm{},
 		&entity.AIConversationSummary{},
 	); err != nil {
 		t.Fatalf("auto migrate: %v", err)
@@ -13,7 +13,7 @@ import (
 )
 
 // aiMessageProjector 负责把 runtime 事件折叠成 assistant 消息快照。
-// 当前阶段维护文本输出和 tool trace，不恢复 interrupt / approval 状态机。
+// 它维护文本输出、tool trace，以及工具人工确认的 waiting / 决策状态。
 type aiMessageProjector struct {
 	mu      sync.Mutex
 	repo    interfaces.AIRepository
@@ -44,6 +44,19 @@ func newAIMessageProjector(repo interfaces.AIRepository, message *entity.AIMessa
 	}
 }
 
+// restoreAIMessageProjector 基于已持久化的 trace 快照创建投影器。
+// 用于人工确认后的恢复执行：同一条 assistant 消息需要在原有 trace 上继续折叠事件。
+func restoreAIMessageProjector(repo interfaces.AIRepository, message *entity.AIMessage) *aiMessageProjector {
+	projector := newAIMessageProjector(repo, message)
+	if message == nil {
+		return projector
+	}
+	for _, item := range decodeAssistantTraceItems(message.TraceItemsJSON) {
+		projector.upsertTraceItem(item)
+	}
+	return projector
+}
+
 // setStopped 把 assistant 消息标记为停止态。
 // 作用：用于请求取消、超时等非系统故障场景的收尾。
 func (p *aiMessageProjector) setStopped() {
@@ -156,6 +169,32 @@ func (p *aiMessageProjector) applyEvent(event aidomain.Event) {
 			}
 			p.upsertTraceItem(item)
 		}
+	case aidomain.EventToolCallWaitingConfirmation:
+		if payload, ok := event.Payload.(aidomain.ToolCallWaitingConfirmationPayload); ok {
+			// waiting 事件把 trace 项切到待确认态，并挂上决策所需的 interrupt 和动作。
+			item := resp.AssistantTraceItem{
+				Key:                     payload.Key,
+				Description:             "等待用户确认后执行。",
+				Status:                  "waiting",
+				InterruptID:             payload.InterruptID,
+				DetailMarkdown:          payload.DetailMarkdown,
+				RequiresConfirmation:    true,
+				ConfirmationTitle:       payload.Title,
+				ConfirmationDescription: payload.Description,
+				Actions:                 aiInterruptTraceActions(),
+			}
+			if payload.ToolName != "" {
+				item.Title = "调用工具 " + payload.ToolName
+			}
+			p.upsertTraceItem(item)
+		}
+		p.message.Status = aiMessageStatusWaitingConfirmation
+	case aidomain.EventToolCallConfirmationResult:
+		if payload, ok := event.Payload.(aidomain.ToolCallConfirmationResultPayload); ok {
+			// 用户决策后收起确认动作，批准时回到 running，拒绝时直接落 rejected。
+			p.resolveTraceConfirmation(payload)
+		}
+		p.message.Status = aiMessageStatusLoading
 	case aidomain.EventError:
 		if payload, ok := event.Payload.(aidomain.ErrorPayload); ok {
 			// error 事件把用户可见错误文案同步到消息实体。
@@ -189,6 +228,15 @@ func (p *aiMessageProjector) upsertTraceItem(item resp.AssistantTraceItem) {
 		if item.DetailMarkdown != "" {
 			current.DetailMarkdown = item.DetailMarkdown
 		}
+		if item.InterruptID != "" {
+			current.InterruptID = item.InterruptID
+		}
+		if item.RequiresConfirmation {
+			current.RequiresConfirmation = true
+			current.ConfirmationTitle = item.ConfirmationTitle
+			current.ConfirmationDescription = item.ConfirmationDescription
+			current.Actions = item.Actions
+		}
 		p.traceItems[stringsIndex] = current
 		return
 	}
@@ -198,6 +246,58 @@ func (p *aiMessageProjector) upsertTraceItem(item resp.AssistantTraceItem) {
 	p.traceItems = append(p.traceItems, item)
 }
 
+// resolveTraceConfirmation 负责把待确认 trace 项收敛为用户决策后的状态。
+func (p *aiMessageProjector) resolveTraceConfirmation(payload aidomain.ToolCallConfirmationResultPayload) {
+	index, ok := p.traceIndex[payload.Key]
+	if !ok {
+		return
+	}
+	current := p.traceItems[index]
+	current.RequiresConfirmation = false
+	current.Actions = nil
+	current.Status, current.Description = aiInterruptDecisionTraceState(payload.Decision)
+	p.traceItems[index] = current
+}
+
+// aiInterruptDecisionTraceState 返回用户决策后 trace 项应展示的状态和说明。
+func aiInterruptDecisionTraceState(decision string) (status string, description string) {
+	if decision == aidomain.InterruptDecisionApprove {
+		return "running", "用户已确认，正在执行工具调用。"
+	}
+	return "rejected", "用户已拒绝执行该工具调用。"
+}
+
+// redactTraceItemsForShare 负责按投影器的字段语义裁剪 trace，供只读分享等非本人视图使用。
+// 注意事项：
+//   - 保留 started/finished 事件折叠出的标题、简述、状态、耗时和简要结果，这些本来就折叠在消息里展示。
+//   - detail_markdown 承载工具原始明细与待确认详情，中断 ID 和确认动作只对会话本人有意义，一律去掉。
+//   - 长期记忆召回 trace 暴露的是个人记忆片段，整项不对外展示。
+func redactTraceItemsForShare(items []resp.AssistantTraceItem) []resp.AssistantTraceItem {
+	redacted := make([]resp.AssistantTraceItem, 0, len(items))
+	for _, item := range items {
+		if item.Key == aiMemoryRecallTraceKey {
+			continue
+		}
+		redacted = append(redacted, resp.AssistantTraceItem{
+			Key:         item.Key,
+			Title:       item.Title,
+			Description: item.Description,
+			Status:      item.Status,
+			DurationMS:  item.DurationMS,
+			Content:     item.Content,
+		})
+	}
+	return redacted
+}
+
+// aiInterruptTraceActions 返回待确认 trace 项上固定的批准 / 拒绝动作。
+func aiInterruptTraceActions() []resp.AssistantTraceAction {
+	return []resp.AssistantTraceAction{
+		{Key: aidomain.InterruptDecisionApprove, Label: "确认执行", Action: aidomain.InterruptDecisionApprove, Style: "primary"},
+		{Key: aidomain.InterruptDecisionReject, Label: "拒绝", Action: aidomain.InterruptDecisionReject, Style: "danger"},
+	}
+}
+
 // encodeAssistantTraceItems 负责把 trace_items 安全编码成 JSON 字符串。
 func encodeAssistantTraceItems(items []resp.AssistantTraceItem) string {
 	// 空结果固定返回 []，保持数据库字段格式稳定。
@@ -40,6 +40,9 @@ func (s *projectorRepoStub) UpdateMessage(_ context.Context, message *entity.AIM
 	s.lastMessage = &cloned
 	return nil
 }
+func (s *projectorRepoStub) GetMessageByID(context.Context, string) (*entity.AIMessage, error) {
+	return nil, nil
+}
 func (s *projectorRepoStub) ListMessagesByConversation(context.Context, string) ([]*entity.AIMessage, error) {
 	return nil, nil
 }
@@ -53,11 +56,34 @@ func (s *projectorRepoStub) GetInterruptByIDForUpdate(context.Context, string) (
 func (s *projectorRepoStub) ListInterruptsByUserAndStatuses(context.Context, uint, []string) ([]*entity.AIInterrupt, error) {
 	return nil, nil
 }
+func (s *projectorRepoStub) ListInterruptsByConversationAndStatuses(
+	context.Context,
+	string,
+	[]string,
+) ([]*entity.AIInterrupt, error) {
+	return nil, nil
+}
 func (s *projectorRepoStub) ListInterruptsForRecovery(context.Context, []string, time.Time, int) ([]*entity.AIInterrupt, error) {
 	return nil, nil
 }
 func (s *projectorRepoStub) UpdateInterrupt(context.Context, *entity.AIInterrupt) error { return nil }
-func (s *projectorRepoStub) WithTx(any) interfaces.AIRepository                         { return s }
+func (s *projectorRepoStub) CreateShare(context.Context, *entity.AIConversationShare) error {
+	return nil
+}
+func (s *projectorRepoStub) GetShareByID(context.Context, string) (*entity.AIConversationShare, error) {
+	return nil, nil
+}
+func (s *projectorRepoStub) GetShareByTokenHash(context.Context, string) (*entity.AIConversationShare, error) {
+	return nil, nil
+}
+func (s *projectorRepoStub) ListSharesByConversation(context.Context, string) ([]*entity.AIConversationShare, error) {
+	return nil, nil
+}
+func (s *projectorRepoStub) UpdateShare(context.Context, *entity.AIConversationShare) error {
+	return nil
+}
+func (s *projectorRepoStub) IncrementShareView(context.Context, string, time.Time) error { return nil }
+func (s *projectorRepoStub) WithTx(any) interfaces.AIRepository                          { return s }
 
 func TestAIMessageProjectorPersistsBasicMessageAndClearsTraceJSON(t *testing.T) {
 	repo := &projectorRepoStub{}
@@ -182,3 +208,74 @@ func TestAIMessageProjectorPersistsToolTraceItems(t *testing.T) {
 		t.Fatalf("trace item content = %q", items[0].Content)
 	}
 }
+
+func TestAIMessageProjectorFoldsInterruptConfirmationAcrossResume(t *testing.T) {
+	repo := &projectorRepoStub{}
+	message := &entity.AIMessage{
+		ID:             "msg_ai_interrupt",
+		ConversationID: "conv_interrupt",
+		Role:           "assistant",
+		Status:         aiMessageStatusLoading,
+		TraceItemsJSON: "[]",
+		ScopeJSON:      "{}",
+	}
+	projector := newAIMessageProjector(repo, message)
+
+	projector.applyEvent(aidomain.Event{
+		Name: aidomain.EventToolCallStarted,
+		Payload: aidomain.ToolCallStartedPayload{
+			Key:         "call_create",
+			ToolName:    "create_oj_task",
+			Title:       "调用工具 create_oj_task",
+			Description: "正在执行工具调用。",
+		},
+	})
+	projector.applyEvent(aidomain.Event{
+		Name: aidomain.EventToolCallWaitingConfirmation,
+		Payload: aidomain.ToolCallWaitingConfirmationPayload{
+			Key:            "call_create",
+			InterruptID:    "intr_1",
+			ToolName:       "create_oj_task",
+			Title:          "确认创建任务",
+			Description:    "将创建一个新的 OJ 任务。",
+			DetailMarkdown: "预览",
+		},
+	})
+	if err := projector.persistMessage(context.Background()); err != nil {
+		t.Fatalf("persistMessage() error = %v", err)
+	}
+	if repo.lastMessage.Status != aiMessageStatusWaitingConfirmation {
+		t.Fatalf("status = %q, want waiting", repo.lastMessage.Status)
+	}
+	items := decodeAssistantTraceItems(repo.lastMessage.TraceItemsJSON)
+	if len(items) != 1 || items[0].Status != "waiting" || items[0].InterruptID != "intr_1" {
+		t.Fatalf("waiting trace items = %+v", items)
+	}
+	if !items[0].RequiresConfirmation || len(items[0].Actions) != 2 {
+		t.Fatalf("waiting trace confirmation = %+v", items[0])
+	}
+
+	// 恢复时基于已持久化的快照重建投影器，原有 trace 项应继续被合并而不是清空。
+	resumed := restoreAIMessageProjector(repo, repo.lastMessage)
+	resumed.applyEvent(aidomain.Event{
+		Name: aidomain.EventToolCallConfirmationResult,
+		Payload: aidomain.ToolCallConfirmationResultPayload{
+			Key:         "call_create",
+			InterruptID: "intr_1",
+			Decision:    aidomain.InterruptDecisionReject,
+		},
+	})
+	if err := resumed.persistMessage(context.Background()); err != nil {
+		t.Fatalf("persistMessage() error = %v", err)
+	}
+	if repo.lastMessage.Status != aiMessageStatusLoading {
+		t.Fatalf("status after decision = %q, want loading", repo.lastMessage.Status)
+	}
+	items = decodeAssistantTraceItems(repo.lastMessage.TraceItemsJSON)
+	if len(items) != 1 || items[0].Status != "rejected" {
+		t.Fatalf("resolved trace items = %+v", items)
+	}
+	if items[0].RequiresConfirmation || len(items[0].Actions) != 0 {
+		t.Fatalf("resolved trace should drop actions: %+v", items[0])
+	}
+}
@@ -0,0 +1,260 @@
+package system
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/dto/request"
+	resp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	bizerrors "personal_assistant/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// aiShareTokenBytes 是分享 token 的随机字节数，base64url 编码后为 32 个字符。
+const aiShareTokenBytes = 24
+
+// CreateShare 负责为会话活动分支创建只读分享链接。
+// 核心流程：
+//  1. 校验会话归属；生成中的会话内容不完整，拒绝分享。
+//  2. 把活动分支按分享规则脱敏后固化成快照。
+//  3. 生成随机 token，只保存其哈希，明文 token 仅在本次响应中返回。
+//
+// 注意事项：
+//   - org_only 要求会话本身归属某个组织，查看时按该组织的活跃成员身份校验。
+func (s *AIService) CreateShare(
+	ctx context.Context,
+	userID uint,
+	conversationID string,
+	req *request.CreateAssistantShareReq,
+) (*resp.AssistantShareResp, error) {
+	if req == nil {
+		req = &request.CreateAssistantShareReq{}
+	}
+	if req.ExpiresInHours < 0 {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "有效期不能为负数")
+	}
+	conversation, err := s.requireConversationOwner(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation.IsGenerating {
+		return nil, bizerrors.New(bizerrors.CodeAIConversationBusy)
+	}
+	if req.OrgOnly && conversation.OrgID == nil {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "该会话不属于任何组织，无法限制为组织内可见")
+	}
+
+	messages, err := s.aiRepo.ListMessagesByConversation(ctx, conversation.ID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	branch := newAIMessageTree(conversation, messages).activeBranch()
+	if len(branch) == 0 {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "会话还没有消息，无法分享")
+	}
+	snapshot, err := json.Marshal(buildAIShareSnapshot(branch))
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+	token, err := newAIShareToken()
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+
+	now := time.Now()
+	share := &entity.AIConversationShare{
+		ID:             newAIID("share"),
+		ConversationID: conversation.ID,
+		UserID:         userID,
+		OrgID:          conversation.OrgID,
+		OrgOnly:        req.OrgOnly,
+		TokenHash:      hashAIShareToken(token),
+		Title:          conversation.Title,
+		SnapshotJSON:   string(snapshot),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if req.ExpiresInHours > 0 {
+		expiresAt := now.Add(time.Duration(req.ExpiresInHours) * time.Hour)
+		share.ExpiresAt = &expiresAt
+	}
+	if err := s.aiRepo.CreateShare(ctx, share); err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	item := shareToResp(share)
+	item.Token = token
+	return item, nil
+}
+
+// ListShares 负责返回会话下的全部分享链接，包括已撤销和已过期的记录。
+func (s *AIService) ListShares(ctx context.Context, userID uint, conversationID string) ([]*resp.AssistantShareResp, error) {
+	conversation, err := s.requireConversationOwner(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	shares, err := s.aiRepo.ListSharesByConversation(ctx, conversation.ID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	items := make([]*resp.AssistantShareResp, 0, len(shares))
+	for _, share := range shares {
+		items = append(items, shareToResp(share))
+	}
+	return items, nil
+}
+
+// RevokeShare 负责撤销分享链接；重复撤销保持幂等。
+func (s *AIService) RevokeShare(ctx context.Context, userID uint, conversationID string, shareID string) error {
+	conversation, err := s.requireConversationOwner(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+	share, err := s.aiRepo.GetShareByID(ctx, strings.TrimSpace(shareID))
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if share == nil || share.ConversationID != conversation.ID {
+		return bizerrors.New(bizerrors.CodeAIShareNotFound)
+	}
+	if share.RevokedAt != nil {
+		return nil
+	}
+	now := time.Now()
+	share.RevokedAt = &now
+	share.UpdatedAt = now
+	if err := s.aiRepo.UpdateShare(ctx, share); err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return nil
+}
+
+// ViewShare 负责按分享 token 返回只读会话快照，无需登录。
+// 参数：
+//   - viewerUserID：当前请求携带的登录用户，未登录时为 0。
+//
+// 注意事项：
+//   - 不存在、已撤销、已过期统一返回同一个错误，避免探测 token 状态。
+//   - 仅组织可见的分享要求登录，并且是分享时会话所属组织的活跃成员；分享者本人始终可以查看。
+func (s *AIService) ViewShare(
+	ctx context.Context,
+	viewerUserID uint,
+	token string,
+) (*resp.AssistantSharedConversationResp, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, bizerrors.New(bizerrors.CodeAIShareNotFound)
+	}
+	share, err := s.aiRepo.GetShareByTokenHash(ctx, hashAIShareToken(token))
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	now := time.Now()
+	if share == nil || share.RevokedAt != nil || (share.ExpiresAt != nil && !share.ExpiresAt.After(now)) {
+		return nil, bizerrors.New(bizerrors.CodeAIShareNotFound)
+	}
+	if err := s.requireShareViewer(ctx, share, viewerUserID); err != nil {
+		return nil, err
+	}
+
+	messages := make([]resp.AssistantSharedMessageResp, 0)
+	if err := json.Unmarshal([]byte(share.SnapshotJSON), &messages); err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+	// 查看计数只用于展示，失败不影响本次查看。
+	if err := s.aiRepo.IncrementShareView(ctx, share.ID, now); err != nil && global.Log != nil {
+		global.Log.Warn("AI 分享查看计数失败", zap.String("share_id", share.ID), zap.Error(err))
+	}
+
+	result := &resp.AssistantSharedConversationResp{
+		Title:    share.Title,
+		SharedAt: share.CreatedAt.Format(time.RFC3339),
+		OrgOnly:  share.OrgOnly,
+		Messages: messages,
+	}
+	if share.ExpiresAt != nil {
+		result.ExpiresAt = share.ExpiresAt.Format(time.RFC3339)
+	}
+	return result, nil
+}
+
+// requireShareViewer 负责校验仅组织可见分享的查看者身份。
+func (s *AIService) requireShareViewer(ctx context.Context, share *entity.AIConversationShare, viewerUserID uint) error {
+	if !share.OrgOnly || (viewerUserID != 0 && viewerUserID == share.UserID) {
+		return nil
+	}
+	if viewerUserID == 0 {
+		return bizerrors.NewWithMsg(bizerrors.CodeLoginRequired, "该分享仅限组织成员查看，请先登录")
+	}
+	if share.OrgID == nil || s.orgMemberRepo == nil {
+		return bizerrors.New(bizerrors.CodePermissionDenied)
+	}
+	active, err := s.orgMemberRepo.IsUserActiveInOrg(ctx, viewerUserID, *share.OrgID)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if !active {
+		return bizerrors.NewWithMsg(bizerrors.CodePermissionDenied, "该分享仅限组织成员查看")
+	}
+	return nil
+}
+
+// buildAIShareSnapshot 负责把活动分支转换成分享快照，轨迹按分享规则脱敏，不携带消息 ID 与业务上下文。
+func buildAIShareSnapshot(branch []*entity.AIMessage) []resp.AssistantSharedMessageResp {
+	items := make([]resp.AssistantSharedMessageResp, 0, len(branch))
+	for _, message := range branch {
+		items = append(items, resp.AssistantSharedMessageResp{
+			Role:       message.Role,
+			Content:    message.Content,
+			CreatedAt:  message.CreatedAt.Format(time.RFC3339),
+			Status:     message.Status,
+			TraceItems: redactTraceItemsForShare(decodeAssistantTraceItems(message.TraceItemsJSON)),
+		})
+	}
+	return items
+}
+
+// shareToResp 负责把分享实体转换成管理视图，不包含 token。
+func shareToResp(share *entity.AIConversationShare) *resp.AssistantShareResp {
+	item := &resp.AssistantShareResp{
+		ID:             share.ID,
+		ConversationID: share.ConversationID,
+		Title:          share.Title,
+		OrgOnly:        share.OrgOnly,
+		OrgID:          share.OrgID,
+		ViewCount:      share.ViewCount,
+		CreatedAt:      share.CreatedAt.Format(time.RFC3339),
+	}
+	if share.ExpiresAt != nil {
+		item.ExpiresAt = share.ExpiresAt.Format(time.RFC3339)
+	}
+	if share.RevokedAt != nil {
+		item.RevokedAt = share.RevokedAt.Format(time.RFC3339)
+	}
+	if share.LastViewedAt != nil {
+		item.LastViewedAt = share.LastViewedAt.Format(time.RFC3339)
+	}
+	return item
+}
+
+func newAIShareToken() (string, error) {
+	buf := make([]byte, aiShareTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashAIShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
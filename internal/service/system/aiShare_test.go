@@ -0,0 +1,151 @@
+package system
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/repository/interfaces"
+	bizerrors "personal_assistant/pkg/errors"
+)
+
+type shareOrgMemberRepo struct {
+	interfaces.OrgMemberRepository
+	active map[uint]bool
+}
+
+func (r *shareOrgMemberRepo) IsUserActiveInOrg(_ context.Context, userID, _ uint) (bool, error) {
+	return r.active[userID], nil
+}
+
+func TestAIShareSnapshotRedactsTracesAndTracksViews(t *testing.T) {
+	service, source := newAIExportTestService(t)
+	ctx := context.Background()
+
+	created, err := service.CreateShare(ctx, 7, source.ID, nil)
+	if err != nil {
+		t.Fatalf("CreateShare() error = %v", err)
+	}
+	if len(created.Token) != 32 || created.ExpiresAt != "" || created.OrgOnly {
+		t.Fatalf("CreateShare() = %+v", created)
+	}
+	stored, err := service.aiRepo.GetShareByID(ctx, created.ID)
+	if err != nil || stored == nil || stored.TokenHash == created.Token || strings.Contains(stored.SnapshotJSON, created.Token) {
+		t.Fatalf("stored share = %+v, %v", stored, err)
+	}
+
+	// 分享之后继续对话不影响快照。
+	source.Title = "改名后的会话"
+	if err := service.aiRepo.UpdateConversation(ctx, source); err != nil {
+		t.Fatalf("UpdateConversation() error = %v", err)
+	}
+
+	view, err := service.ViewShare(ctx, 0, created.Token)
+	if err != nil {
+		t.Fatalf("ViewShare() error = %v", err)
+	}
+	if view.Title != "两数之和" || len(view.Messages) != 4 || view.Messages[3].Content != "a2b" {
+		t.Fatalf("ViewShare() = %+v", view)
+	}
+	traces := view.Messages[1].TraceItems
+	if len(traces) != 1 || traces[0].Title != "查询题目" || traces[0].DurationMS != 120 || traces[0].DetailMarkdown != "" {
+		t.Fatalf("shared traces = %+v", traces)
+	}
+
+	items, err := service.ListShares(ctx, 7, source.ID)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("ListShares() = %+v, %v", items, err)
+	}
+	if items[0].Token != "" || items[0].ViewCount != 1 || items[0].LastViewedAt == "" {
+		t.Fatalf("listed share = %+v", items[0])
+	}
+
+	if err := service.RevokeShare(ctx, 7, source.ID, created.ID); err != nil {
+		t.Fatalf("RevokeShare() error = %v", err)
+	}
+	if err := service.RevokeShare(ctx, 7, source.ID, created.ID); err != nil {
+		t.Fatalf("RevokeShare(again) error = %v", err)
+	}
+	_, err = service.ViewShare(ctx, 0, created.Token)
+	if !hasBizCode(err, bizerrors.CodeAIShareNotFound) {
+		t.Fatalf("error = %v, want code %d", err, bizerrors.CodeAIShareNotFound)
+	}
+	if err := service.RevokeShare(ctx, 8, source.ID, created.ID); !hasBizCode(err, bizerrors.CodeAIConversationNotFound) {
+		t.Fatalf("RevokeShare(other user) error = %v", err)
+	}
+}
+
+func TestRedactTraceItemsForShareDropsInteractiveFieldsAndMemoryRecall(t *testing.T) {
+	items := redactTraceItemsForShare(decodeAssistantTraceItems(`[
+		{"key":"memory_recall","title":"检索长期记忆","detail_markdown":"| 私人记忆 |"},
+		{"key":"tool-2","title":"创建任务","status":"waiting","content":"已生成草稿","detail_markdown":"参数明细","interrupt_id":"intr_1","requires_confirmation":true,"confirmation_title":"确认创建","actions":[{"key":"approve"}]}
+	]`))
+	if len(items) != 1 {
+		t.Fatalf("redacted items = %+v", items)
+	}
+	item := items[0]
+	if item.Key != "tool-2" || item.Content != "已生成草稿" || item.Status != "waiting" {
+		t.Fatalf("kept fields = %+v", item)
+	}
+	if item.DetailMarkdown != "" || item.InterruptID != "" || item.RequiresConfirmation || item.ConfirmationTitle != "" || item.Actions != nil {
+		t.Fatalf("interactive fields should be dropped: %+v", item)
+	}
+}
+
+func TestAIShareOrgOnlyAndExpiry(t *testing.T) {
+	service, source := newAIExportTestService(t)
+	ctx := context.Background()
+
+	_, err := service.CreateShare(ctx, 7, source.ID, &request.CreateAssistantShareReq{OrgOnly: true})
+	if !hasBizCode(err, bizerrors.CodeInvalidParams) {
+		t.Fatalf("error = %v, want code %d", err, bizerrors.CodeInvalidParams)
+	}
+
+	orgID := uint(3)
+	source.OrgID = &orgID
+	if err := service.aiRepo.UpdateConversation(ctx, source); err != nil {
+		t.Fatalf("UpdateConversation() error = %v", err)
+	}
+	service.orgMemberRepo = &shareOrgMemberRepo{active: map[uint]bool{11: true}}
+	created, err := service.CreateShare(ctx, 7, source.ID, &request.CreateAssistantShareReq{OrgOnly: true, ExpiresInHours: 24})
+	if err != nil {
+		t.Fatalf("CreateShare(org_only) error = %v", err)
+	}
+	if created.ExpiresAt == "" || created.OrgID == nil || *created.OrgID != orgID {
+		t.Fatalf("CreateShare(org_only) = %+v", created)
+	}
+
+	_, err = service.ViewShare(ctx, 0, created.Token)
+	if !hasBizCode(err, bizerrors.CodeLoginRequired) {
+		t.Fatalf("error = %v, want code %d", err, bizerrors.CodeLoginRequired)
+	}
+	_, err = service.ViewShare(ctx, 12, created.Token)
+	if !hasBizCode(err, bizerrors.CodePermissionDenied) {
+		t.Fatalf("error = %v, want code %d", err, bizerrors.CodePermissionDenied)
+	}
+	for _, viewer := range []uint{11, 7} {
+		if _, err := service.ViewShare(ctx, viewer, created.Token); err != nil {
+			t.Fatalf("ViewShare(viewer=%d) error = %v", viewer, err)
+		}
+	}
+
+	share, err := service.aiRepo.GetShareByID(ctx, created.ID)
+	if err != nil || share == nil {
+		t.Fatalf("GetShareByID() = %+v, %v", share, err)
+	}
+	expired := time.Now().Add(-time.Minute)
+	share.ExpiresAt = &expired
+	if err := service.aiRepo.UpdateShare(ctx, share); err != nil {
+		t.Fatalf("UpdateShare() error = %v", err)
+	}
+	_, err = service.ViewShare(ctx, 11, created.Token)
+	if !hasBizCode(err, bizerrors.CodeAIShareNotFound) {
+		t.Fatalf("error = %v, want code %d", err, bizerrors.CodeAIShareNotFound)
+	}
+	_, err = service.ViewShare(ctx, 11, "unknown-token")
+	if !hasBizCode(err, bizerrors.CodeAIShareNotFound) {
+		t.Fatalf("error = %v, want code %d", err, bizerrors.CodeAIShareNotFound)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	aidomain "personal_assistant/internal/domain/ai"
 	streamsse "personal_assistant/internal/infrastructure/sse"
+	resp "personal_assistant/internal/model/dto/response"
 	"personal_assistant/internal/model/entity"
 	"personal_assistant/internal/repository/interfaces"
 )
@@ -35,6 +36,20 @@ func newAIStreamSink(
 	}
 }
 
+// newAIResumeStreamSink 负责为人工确认后的恢复执行创建 sink。
+// 与 newAIStreamSink 的区别是会先还原消息已有的 trace 快照，避免恢复后丢失中断前的工具轨迹。
+func newAIResumeStreamSink(
+	repo interfaces.AIRepository,
+	writer streamsse.StreamWriter,
+	message *entity.AIMessage,
+) *aiStreamSink {
+	return &aiStreamSink{
+		writer:          writer,
+		projector:       restoreAIMessageProjector(repo, message),
+		persistInterval: 400 * time.Millisecond,
+	}
+}
+
 // Emit 负责发出一个运行时事件，并把事件影响同步折叠到消息快照。
 // 参数：
 //   - ctx：链路上下文。
@@ -43,7 +58,7 @@ func newAIStreamSink(
 //
 // 作用：发出一个运行时事件，并把这个事件的影响同步到内存快照和数据库。
 func (s *aiStreamSink) Emit(ctx context.Context, event aidomain.Event) error {
-	raw, err := json.Marshal(event.Payload)
+	raw, err := json.Marshal(aiEventWirePayload(event))
 	if err != nil {
 		return err
 	}
@@ -98,6 +113,8 @@ func (s *aiStreamSink) shouldPersist(name aidomain.EventName) bool {
 	case aidomain.EventMessageCompleted,
 		aidomain.EventToolCallStarted,
 		aidomain.EventToolCallFinished,
+		aidomain.EventToolCallWaitingConfirmation,
+		aidomain.EventToolCallConfirmationResult,
 		aidomain.EventError,
 		aidomain.EventDone:
 		return true
@@ -109,3 +126,32 @@ func (s *aiStreamSink) shouldPersist(name aidomain.EventName) bool {
 	}
 	return time.Since(s.lastPersistAt) >= s.persistInterval
 }
+
+// aiEventWirePayload 负责把需要前端交互的 domain 载荷转换成对外 SSE 协议结构。
+// 人工确认事件需要补充 trace 展示字段和动作按钮，其余事件直接透传 domain 载荷。
+func aiEventWirePayload(event aidomain.Event) any {
+	switch payload := event.Payload.(type) {
+	case aidomain.ToolCallWaitingConfirmationPayload:
+		return resp.AssistantToolCallWaitingConfirmationPayload{
+			InterruptID:             payload.InterruptID,
+			Key:                     payload.Key,
+			Title:                   "调用工具 " + payload.ToolName,
+			Description:             "等待用户确认后执行。",
+			DetailMarkdown:          payload.DetailMarkdown,
+			ConfirmationTitle:       payload.Title,
+			ConfirmationDescription: payload.Description,
+			Actions:                 aiInterruptTraceActions(),
+		}
+	case aidomain.ToolCallConfirmationResultPayload:
+		status, description := aiInterruptDecisionTraceState(payload.Decision)
+		return resp.AssistantToolCallConfirmationResultPayload{
+			InterruptID:    payload.InterruptID,
+			Key:            payload.Key,
+			Decision:       payload.Decision,
+			Status:         status,
+			Description:    description,
+			DetailMarkdown: payload.Reason,
+		}
+	}
+	return event.Payload
+}
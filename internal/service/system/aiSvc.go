@@ -33,6 +33,9 @@ const (
 
 	// aiMessageStatusStopped 表示消息因取消、超时或撤销被中断。
 	aiMessageStatusStopped = aidomain.MessageStatusStopped
+
+	// aiMessageStatusWaitingConfirmation 表示消息因工具等待人工确认而暂停。
+	aiMessageStatusWaitingConfirmation = aidomain.MessageStatusWaitingConfirmation
 )
 
 // AIService 负责编排 AI 会话、消息与最小流式运行时之间的业务流程。
@@ -41,8 +44,10 @@ type AIService struct {
 	txRunner repository.TxRunner
 	aiRepo   interfaces.AIRepository
 	userRepo interfaces.UserRepository
-	runtime  aidomain.Runtime
-	policy   streamsse.ConnectionPolicy
+	// orgMemberRepo 用于校验仅限组织成员查看的分享链接。
+	orgMemberRepo interfaces.OrgMemberRepository
+	runtime       aidomain.Runtime
+	policy        streamsse.ConnectionPolicy
 	// authorizationSvc 用于补充超级管理员和组织能力的真实鉴权。
 	authorizationSvc aitool.AuthorizationService
 	// toolRegistry 负责注册、过滤并执行本轮可见 AI tool。
@@ -103,11 +108,12 @@ func newAIServiceWithDeps(
 	registry := aitool.NewRegistry(deps.Tools)
 
 	return &AIService{
-		txRunner: repositoryGroup,
-		aiRepo:   repositoryGroup.SystemRepositorySupplier.GetAIRepository(),
-		userRepo: repositoryGroup.SystemRepositorySupplier.GetUserRepository(),
-		runtime:  runtime,
-		policy:   policy.Normalize(),
+		txRunner:      repositoryGroup,
+		aiRepo:        repositoryGroup.SystemRepositorySupplier.GetAIRepository(),
+		userRepo:      repositoryGroup.SystemRepositorySupplier.GetUserRepository(),
+		orgMemberRepo: repositoryGroup.SystemRepositorySupplier.GetOrgMemberRepository(),
+		runtime:       runtime,
+		policy:        policy.Normalize(),
 		// 授权服务只保存到 AIService，供构造 principal 和执行前鉴权复用。
 		authorizationSvc: deps.Tools.Authorization,
 		// tool registry 根据当前注入依赖决定哪些工具真正可用。
@@ -178,7 +184,7 @@ func (s *AIService) ListConversations(ctx context.Context, userID uint) ([]*resp
 	return items, nil
 }
 
-// ListMessages 负责返回指定会话下的消息列表。
+// ListMessages 负责返回指定会话活动分支上的消息列表。
 func (s *AIService) ListMessages(ctx context.Context, userID uint, conversationID string) ([]*resp.AssistantMessageResp, error) {
 	// 第一阶段：先处理入口参数、依赖或前置状态，尽早挡住不能继续推进的情况。
 	// 把前置判断集中在这里，是为了避免后续主逻辑夹杂过多防御性分支。
@@ -194,13 +200,11 @@ func (s *AIService) ListMessages(ctx context.Context, userID uint, conversationI
 		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
 	}
 
-	items := make([]*resp.AssistantMessageResp, 0, len(messages))
-	for _, message := range messages {
-		item, mapErr := messageToResp(message)
-		if mapErr != nil {
-			return nil, bizerrors.Wrap(bizerrors.CodeInternalError, mapErr)
-		}
-		items = append(items, item)
+	// 只返回活动分支，兄弟分支通过 sibling_ids 暴露给客户端切换。
+	tree := newAIMessageTree(conversation, messages)
+	items, err := branchMessagesToResp(tree, tree.activeBranch())
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeInternalError, err)
 	}
 	// 第三阶段：统一收口结果、状态更新或返回动作，保证对外行为一致。
 	// 把收尾逻辑显式标出来，可以降低后续维护时遗漏边界处理的风险。
@@ -245,41 +249,49 @@ func (s *AIService) StreamConversation(
 	if strings.TrimSpace(req.ConversationID) != conversationID {
 		return bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "conversation_id 与路径参数不一致")
 	}
-	if writer == nil {
-		return bizerrors.New(bizerrors.CodeAIStreamingUnsupported)
-	}
-	if s.runtime == nil {
-		return bizerrors.New(bizerrors.CodeAIStreamingUnsupported)
+	if err := s.ensureStreamAvailable(writer); err != nil {
+		return err
 	}
 
 	// 第二阶段：读取会话与用户上下文，保证本次流式执行建立在合法归属和可用会话之上。
-	conversation, err := s.requireConversationOwner(ctx, userID, conversationID)
+	conversation, user, tree, err := s.loadStreamTarget(ctx, userID, conversationID)
 	if err != nil {
 		return err
 	}
-	if conversation.IsGenerating {
-		return bizerrors.New(bizerrors.CodeAIConversationBusy)
-	}
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		return bizerrors.Wrap(bizerrors.CodeDBError, err)
-	}
-	if user == nil {
-		return bizerrors.New(bizerrors.CodeUserNotFound)
-	}
 
-	storedMessages, err := s.aiRepo.ListMessagesByConversation(ctx, conversation.ID)
-	if err != nil {
-		return bizerrors.Wrap(bizerrors.CodeDBError, err)
-	}
+	// 第三阶段：新一轮对话总是追加在活动分支末尾。
+	branch := tree.activeBranch()
+	turn := newAIStreamTurn(conversation.ID, strings.TrimSpace(req.Content), lastAIMessageID(branch))
+	turn.history = branch
+	turn.legacyParents = tree.legacyParents
+	return s.runStreamTurn(ctx, conversation, user, turn, writer)
+}
+
+// aiStreamTurn 描述一次流式轮次要挂到消息树上的节点。
+type aiStreamTurn struct {
+	// userMessage 是本轮用户消息。
+	userMessage *entity.AIMessage
+	// reuseUserMessage 为 true 时 userMessage 已存在（重新生成），不再新建。
+	reuseUserMessage bool
+	// assistantMessage 是本轮新建的 assistant 消息。
+	assistantMessage *entity.AIMessage
+	// history 是本轮之前活动分支上的消息，供上下文装配使用。
+	history []*entity.AIMessage
+	// legacyParents 是需要在起始事务中回填父指针的旧消息。
+	legacyParents []*entity.AIMessage
+}
 
-	// 第三阶段：构造本次对话会产生的持久化对象，确保运行时开始前有可追踪的消息骨架。
+// newAIStreamTurn 负责构造一轮新的用户消息与 assistant 消息骨架。
+// 参数：
+//   - parentID：新用户消息挂载的父消息 ID，根消息为空。
+func newAIStreamTurn(conversationID string, content string, parentID string) *aiStreamTurn {
 	now := time.Now()
 	userMessage := &entity.AIMessage{
 		ID:             newAIID("msg_user"),
-		ConversationID: conversation.ID,
+		ConversationID: conversationID,
+		ParentID:       parentID,
 		Role:           "user",
-		Content:        strings.TrimSpace(req.Content),
+		Content:        content,
 		Status:         aiMessageStatusSuccess,
 		TraceItemsJSON: "[]",
 		ScopeJSON:      "{}",
@@ -288,7 +300,8 @@ func (s *AIService) StreamConversation(
 	}
 	assistantMessage := &entity.AIMessage{
 		ID:             newAIID("msg_ai"),
-		ConversationID: conversation.ID,
+		ConversationID: conversationID,
+		ParentID:       userMessage.ID,
 		Role:           "assistant",
 		Content:        "",
 		Status:         aiMessageStatusLoading,
@@ -297,9 +310,62 @@ func (s *AIService) StreamConversation(
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
+	return &aiStreamTurn{userMessage: userMessage, assistantMessage: assistantMessage}
+}
+
+// ensureStreamAvailable 负责校验当前请求是否具备流式输出条件。
+func (s *AIService) ensureStreamAvailable(writer streamsse.StreamWriter) error {
+	if writer == nil || s.runtime == nil {
+		return bizerrors.New(bizerrors.CodeAIStreamingUnsupported)
+	}
+	return nil
+}
+
+// loadStreamTarget 负责读取流式轮次所需的会话、用户与消息树，并挡住忙碌会话。
+func (s *AIService) loadStreamTarget(
+	ctx context.Context,
+	userID uint,
+	conversationID string,
+) (*entity.AIConversation, *entity.User, *aiMessageTree, error) {
+	conversation, err := s.requireConversationOwner(ctx, userID, conversationID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if conversation.IsGenerating {
+		return nil, nil, nil, bizerrors.New(bizerrors.CodeAIConversationBusy)
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if user == nil {
+		return nil, nil, nil, bizerrors.New(bizerrors.CodeUserNotFound)
+	}
+	storedMessages, err := s.aiRepo.ListMessagesByConversation(ctx, conversation.ID)
+	if err != nil {
+		return nil, nil, nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return conversation, user, newAIMessageTree(conversation, storedMessages), nil
+}
+
+// runStreamTurn 负责执行一轮已挂载到消息树上的流式生成。
+// 核心流程：
+//  1. 事务化落库会话状态与初始消息，确保流开始前数据库状态完整。
+//  2. 按活动分支历史组装上下文与工具计划。
+//  3. 创建 sink 执行运行时，并在结束后统一做收尾与记忆写回。
+func (s *AIService) runStreamTurn(
+	ctx context.Context,
+	conversation *entity.AIConversation,
+	user *entity.User,
+	turn *aiStreamTurn,
+	writer streamsse.StreamWriter,
+) error {
+	userMessage := turn.userMessage
+	assistantMessage := turn.assistantMessage
+	content := strings.TrimSpace(userMessage.Content)
 
-	// 第四阶段：事务化写入起始状态，确保“会话进入生成中”与“消息骨架落库”具备一致性。
-	if err := s.persistStreamStart(ctx, conversation, user, userMessage, assistantMessage, now); err != nil {
+	// 事务化写入起始状态，确保“会话进入生成中”与“消息骨架落库”具备一致性。
+	if err := s.persistStreamStart(ctx, conversation, user, turn, time.Now()); err != nil {
 		return err
 	}
 
@@ -329,9 +395,9 @@ func (s *AIService) StreamConversation(
 	// 统一由上下文装配器收口历史消息和动态 prompt，方便后续接入记忆召回和压缩。
 	contextSnapshot, err := s.contextAssembler.Build(ctx, aiContextBuildArgs{
 		ConversationID: conversation.ID,
-		UserID:         userID,
-		Query:          strings.TrimSpace(req.Content),
-		StoredMessages: storedMessages,
+		UserID:         user.ID,
+		Query:          content,
+		StoredMessages: turn.history,
 		VisibleTools:   visibleTools,
 		ToolCallCtx:    toolCallCtx,
 	})
@@ -342,7 +408,7 @@ func (s *AIService) StreamConversation(
 		global.Log.Debug(
 			"AI hybrid context planned",
 			zap.String("conversation_id", conversation.ID),
-			zap.Uint("user_id", userID),
+			zap.Uint("user_id", user.ID),
 			zap.Int("summary_kept", contextSnapshot.Diagnostics.SummaryKept),
 			zap.Int("facts_kept", contextSnapshot.Diagnostics.FactsKept),
 			zap.Int("rag_kept", contextSnapshot.Diagnostics.RAGKept),
@@ -351,11 +417,12 @@ func (s *AIService) StreamConversation(
 			zap.Int("history_tokens", contextSnapshot.Diagnostics.HistoryTokens),
 		)
 	}
+	s.emitMemoryRecallTrace(ctx, sink, contextSnapshot.Diagnostics)
 
 	// 按渐进式 selector 解析最终执行计划；失败时自动回退单阶段全量工具。
 	executionPlan, err := s.buildAIToolExecutionPlan(
 		ctx,
-		strings.TrimSpace(req.Content),
+		content,
 		contextSnapshot.History,
 		visibleTools,
 		toolPrincipal,
@@ -365,24 +432,28 @@ func (s *AIService) StreamConversation(
 	}
 
 	// 把最终 prompt、已选工具和调用上下文一并注入 runtime。
-	_, execErr := s.runtime.Stream(ctx, aidomain.StreamInput{
-		UserID:              userID,
+	result, execErr := s.runtime.Stream(ctx, aidomain.StreamInput{
+		UserID:              user.ID,
 		ConversationID:      conversation.ID,
 		UserMessageID:       userMessage.ID,
 		AssistantMessageID:  assistantMessage.ID,
-		Content:             strings.TrimSpace(req.Content),
+		Content:             content,
 		History:             contextSnapshot.History,
 		DynamicSystemPrompt: executionPlan.DynamicSystemPrompt,
 		Tools:               executionPlan.Tools,
 		ToolCallContext:     toolCallCtx,
 	}, sink)
+	if execErr == nil && result.Interrupt != nil {
+		// 命中需要人工确认的工具时，持久化中断并通知前端，本轮以 waiting 态收尾。
+		execErr = s.suspendForInterrupt(ctx, conversation, userMessage.ID, assistantMessage.ID, sink, result.Interrupt, executionPlan.Tools)
+	}
 
 	// 所有已开始的流式请求都统一走 finishStream 收尾，避免成功和失败路径各自写一套状态处理逻辑。
 	finishErr := s.finishStream(ctx, conversation, sink, execErr)
 	if finishErr != nil {
 		return finishErr
 	}
-	if execErr == nil {
+	if execErr == nil && result.Interrupt == nil {
 		s.triggerMemoryWriteback(ctx, conversation, userMessage, assistantMessage, toolPrincipal)
 	}
 	return nil
@@ -407,10 +478,10 @@ func (s *AIService) persistStreamStart(
 	ctx context.Context,
 	conversation *entity.AIConversation,
 	user *entity.User,
-	userMessage *entity.AIMessage,
-	assistantMessage *entity.AIMessage,
+	turn *aiStreamTurn,
 	now time.Time,
 ) error {
+	userMessage := turn.userMessage
 	return s.txRunner.InTx(ctx, func(tx any) error {
 		txAI := s.aiRepo.WithTx(tx)
 		lockedConversation, err := txAI.GetConversationByIDForUpdate(ctx, conversation.ID)
@@ -423,6 +494,16 @@ func (s *AIService) persistStreamStart(
 		if lockedConversation.IsGenerating {
 			return bizerrors.New(bizerrors.CodeAIConversationBusy)
 		}
+		// 旧会话第一次写入时先补齐父指针，之后所有消息都按消息树读取。
+		if lockedConversation.ActiveLeafID == "" {
+			if err := persistAILegacyParents(ctx, txAI, turn.legacyParents); err != nil {
+				return err
+			}
+		}
+		// 用户直接发起新一轮对话时，旧的待确认中断随之失效。
+		if err := cancelPendingAIInterrupts(ctx, txAI, lockedConversation.ID); err != nil {
+			return err
+		}
 
 		lockedConversation.Title = deriveConversationTitle(lockedConversation.Title, userMessage.Content)
 		lockedConversation.Preview = buildConversationPreview(userMessage.Content)
@@ -430,15 +511,19 @@ func (s *AIService) persistStreamStart(
 		lockedConversation.LastMessageAt = &now
 		lockedConversation.UpdatedAt = now
 		lockedConversation.OrgID = user.CurrentOrgID
+		// 新 assistant 消息成为活动分支叶子，重新生成和编辑重发也因此自动切到新分支。
+		lockedConversation.ActiveLeafID = turn.assistantMessage.ID
 
 		// 会话状态先更新，是为了保证后续若消息已落库，列表页也能立即看到“生成中”态。
 		if err := txAI.UpdateConversation(ctx, lockedConversation); err != nil {
 			return err
 		}
-		if err := txAI.CreateMessage(ctx, userMessage); err != nil {
-			return err
+		if !turn.reuseUserMessage {
+			if err := txAI.CreateMessage(ctx, userMessage); err != nil {
+				return err
+			}
 		}
-		if err := txAI.CreateMessage(ctx, assistantMessage); err != nil {
+		if err := txAI.CreateMessage(ctx, turn.assistantMessage); err != nil {
 			return err
 		}
 		*conversation = *lockedConversation
@@ -220,8 +220,9 @@ func newProgressivePlanTestPlanner(t *testing.T) (*Planner, []aidomain.Tool, *fa
 	if err != nil {
 		t.Fatalf("FilterVisibleTools() error = %v", err)
 	}
-	if len(visibleTools) != 3 {
-		t.Fatalf("visibleTools len = %d, want 3", len(visibleTools))
+	// 个人 OJ 三个工具加上组织成员可见的综合排行工具。
+	if len(visibleTools) != 4 {
+		t.Fatalf("visibleTools len = %d, want 4", len(visibleTools))
 	}
 	builder := &fakePromptBuilder{output: "prompt"}
 	return NewPlanner(registry, nil, builder), visibleTools, builder
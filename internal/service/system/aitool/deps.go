@@ -21,8 +21,12 @@ type OJService interface {
 	GetCurve(ctx context.Context, userID uint, req *request.OJCurveReq) (*resp.OJCurveResp, error)
 }
 
-// OJTaskService 表示 AI tool 查询 OJ 任务、执行和分析结果时依赖的最小任务能力。
+// OJTaskService 表示 AI tool 查询和管理 OJ 任务时依赖的最小任务能力。
+// 写操作类方法只会被需要人工确认的工具调用。
 type OJTaskService interface {
+	CreateTask(ctx context.Context, operatorID uint, req *request.CreateOJTaskReq) (*resp.OJTaskCreateResp, error)
+	RetryTask(ctx context.Context, operatorID, taskID uint) (*resp.OJTaskCreateResp, error)
+	ExecuteTaskNow(ctx context.Context, operatorID, taskID uint) (*resp.OJTaskCreateResp, error)
 	AnalyzeTaskTitles(ctx context.Context, req *request.AnalyzeOJTaskTitlesReq) (*resp.OJTaskAnalyzeResp, error)
 	GetTaskDetail(ctx context.Context, userID, taskID uint) (*resp.OJTaskDetailResp, error)
 	GetTaskExecutionDetail(ctx context.Context, userID, taskID, executionID uint) (*resp.OJTaskExecutionResp, error)
@@ -18,8 +18,8 @@ var toolGroupProfiles = map[aidomain.ToolGroupID]aidomain.ToolGroupProfile{
 		DomainTags: []string{"oj", "org"},
 	},
 	aidomain.ToolGroupOJTask: {
-		Summary:    "查询 OJ 任务、执行明细、用户执行明细和题目分析。",
-		WhenToUse:  "用户提问 OJ 任务、作业执行、名单或题目分析时使用。",
+		Summary:    "查询 OJ 任务、执行明细、用户执行明细和题目分析，并在用户确认后创建、重试或立即执行任务。",
+		WhenToUse:  "用户提问 OJ 任务、作业执行、名单或题目分析，或要求布置、重试、立即执行任务时使用。",
 		DomainTags: []string{"oj", "task"},
 	},
 	aidomain.ToolGroupObservabilityTrace: {
@@ -55,13 +55,16 @@ func (f *fakeAIToolAuthorization) hasCapability(orgID uint, capabilityCode strin
 	return false
 }
 
-type fakeAIToolOJService struct{}
+type fakeAIToolOJService struct {
+	rankingReqs []*request.OJRankingListReq
+}
 
 func (f *fakeAIToolOJService) GetRankingList(
-	context.Context,
-	uint,
-	*request.OJRankingListReq,
+	_ context.Context,
+	_ uint,
+	req *request.OJRankingListReq,
 ) (*resp.OJRankingListResp, error) {
+	f.rankingReqs = append(f.rankingReqs, req)
 	return &resp.OJRankingListResp{}, nil
 }
 
@@ -92,6 +95,36 @@ type fakeAIToolOJTaskService struct {
 	taskExecutionUsersCalls int
 	taskExecutionUserCalls  int
 	analyzeCalls            int
+	createReqs              []*request.CreateOJTaskReq
+	retryCalls              int
+	executeNowCalls         int
+}
+
+func (f *fakeAIToolOJTaskService) CreateTask(
+	_ context.Context,
+	_ uint,
+	req *request.CreateOJTaskReq,
+) (*resp.OJTaskCreateResp, error) {
+	f.createReqs = append(f.createReqs, req)
+	return &resp.OJTaskCreateResp{TaskID: 100}, nil
+}
+
+func (f *fakeAIToolOJTaskService) RetryTask(
+	_ context.Context,
+	_ uint,
+	taskID uint,
+) (*resp.OJTaskCreateResp, error) {
+	f.retryCalls++
+	return &resp.OJTaskCreateResp{TaskID: taskID + 1}, nil
+}
+
+func (f *fakeAIToolOJTaskService) ExecuteTaskNow(
+	_ context.Context,
+	_ uint,
+	taskID uint,
+) (*resp.OJTaskCreateResp, error) {
+	f.executeNowCalls++
+	return &resp.OJTaskCreateResp{TaskID: taskID}, nil
 }
 
 func (f *fakeAIToolOJTaskService) AnalyzeTaskTitles(
@@ -313,6 +346,47 @@ func TestAIToolExecutionReauthorizesTaskOrgCapability(t *testing.T) {
 	}
 }
 
+func TestAIToolOrgOverallRankingUsesCombinedCurrentOrgRanking(t *testing.T) {
+	ojSvc := &fakeAIToolOJService{}
+	tool := newAIToolRegistry(Deps{OJ: ojSvc}).findTool("get_org_overall_ranking")
+	if tool == nil {
+		t.Fatal("tool get_org_overall_ranking not found")
+	}
+
+	_, err := tool.Call(context.Background(), aidomain.ToolCall{
+		ID:            "call_1",
+		Name:          "get_org_overall_ranking",
+		ArgumentsJSON: `{"period":"week","limit":50}`,
+	}, aidomain.ToolCallContext{
+		Principal: aidomain.AIToolPrincipal{UserID: 7},
+	})
+	if err != nil {
+		t.Fatalf("tool.Call() error = %v", err)
+	}
+	if len(ojSvc.rankingReqs) != 1 {
+		t.Fatalf("rankingReqs len = %d, want 1", len(ojSvc.rankingReqs))
+	}
+	req := ojSvc.rankingReqs[0]
+	if req.Platform != "all" || req.Scope != "current_org" || req.PageSize != 20 ||
+		req.Mode != consts.OJRankingModeWindow || req.Window != consts.OJRankingWindowWeek {
+		t.Fatalf("ranking request = %+v, want all/current_org/window week with capped page size", req)
+	}
+
+	_, err = tool.Call(context.Background(), aidomain.ToolCall{
+		ID:            "call_2",
+		Name:          "get_org_overall_ranking",
+		ArgumentsJSON: `{"period":"year"}`,
+	}, aidomain.ToolCallContext{
+		Principal: aidomain.AIToolPrincipal{UserID: 7},
+	})
+	if err == nil {
+		t.Fatal("tool.Call() error = nil, want invalid period error")
+	}
+	if len(ojSvc.rankingReqs) != 1 {
+		t.Fatalf("rankingReqs len after invalid call = %d, want still 1", len(ojSvc.rankingReqs))
+	}
+}
+
 func TestAIToolSuperAdminExecutionDeniedWhenNotSuperAdmin(t *testing.T) {
 	auth := &fakeAIToolAuthorization{}
 	obsSvc := &fakeAIToolObservabilityService{}
@@ -386,7 +460,7 @@ func TestAIToolRegistrySchemasExposeDetailedConstraints(t *testing.T) {
 			toolName: "get_my_ranking",
 			param:    "platform",
 			assert: func(t *testing.T, param aidomain.ToolParameter) {
-				assertEnum(t, param, []string{"leetcode", "luogu", "lanqiao"})
+				assertEnum(t, param, []string{"leetcode", "luogu", "lanqiao", "codeforces", "atcoder"})
 				assertExamplesPresent(t, param)
 			},
 		},
@@ -516,7 +590,7 @@ func TestAIToolRegistrySchemasExposeDetailedConstraints(t *testing.T) {
 		t.Fatal("items param missing item schema")
 	}
 	platformParam := mustFindParam(t, itemsParam.Items.Properties, "platform")
-	assertEnum(t, platformParam, []string{"leetcode", "luogu", "lanqiao"})
+	assertEnum(t, platformParam, []string{"leetcode", "luogu", "lanqiao", "codeforces", "atcoder"})
 	titleParam := mustFindParam(t, itemsParam.Items.Properties, "title")
 	if titleParam.MaxLength == nil || *titleParam.MaxLength != 255 {
 		t.Fatalf("title max_length = %v, want 255", titleParam.MaxLength)
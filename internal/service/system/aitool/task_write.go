@@ -0,0 +1,512 @@
+package aitool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	aidomain "personal_assistant/internal/domain/ai"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	resp "personal_assistant/internal/model/dto/response"
+)
+
+// aiCreateTaskItemArgs 表示创建任务时单个题目的输入参数。
+type aiCreateTaskItemArgs struct {
+	// Platform 表示题目所属 OJ 平台。
+	Platform string `json:"platform"`
+	// Title 表示题目标题。
+	Title string `json:"title"`
+}
+
+// aiCreateTaskArgs 表示创建 OJ 任务工具的输入参数。
+type aiCreateTaskArgs struct {
+	// OrgIDs 表示任务下发的组织；省略时默认当前组织。
+	OrgIDs []uint `json:"org_ids,omitempty"`
+	// Title 表示任务标题。
+	Title string `json:"title"`
+	// Description 表示任务说明。
+	Description string `json:"description,omitempty"`
+	// Mode 表示执行模式，immediate 或 scheduled。
+	Mode string `json:"mode"`
+	// ExecuteAt 表示定时任务的执行时间，RFC3339 格式。
+	ExecuteAt string `json:"execute_at,omitempty"`
+	// Items 表示任务题目列表。
+	Items []aiCreateTaskItemArgs `json:"items"`
+}
+
+// aiTaskIDArgs 表示只需要任务 ID 的写工具输入参数。
+type aiTaskIDArgs struct {
+	// TaskID 表示目标任务 ID。
+	TaskID uint `json:"task_id"`
+}
+
+// newAICreateTaskTool 创建 OJ 任务创建工具，执行前需要用户确认。
+func newAICreateTaskTool(
+	taskSvc aiOJTaskService,
+	authorization aiAuthorizationService,
+) *aiServiceTool {
+	itemParam := aidomain.ToolParameter{
+		Type: aidomain.ToolParameterTypeObject,
+		Properties: []aidomain.ToolParameter{
+			{
+				Name:        "platform",
+				Type:        aidomain.ToolParameterTypeString,
+				Description: "OJ 平台。",
+				Required:    true,
+				Enum:        consts.SupportedOJPlatforms(),
+				Examples:    []string{"luogu"},
+			},
+			{
+				Name:        "title",
+				Type:        aidomain.ToolParameterTypeString,
+				Description: "题目标题。",
+				Required:    true,
+				MinLength:   aiIntPtr(1),
+				MaxLength:   aiIntPtr(255),
+				Examples:    []string{"A+B Problem"},
+			},
+		},
+	}
+	descriptor := newAIToolDescriptor(aidomain.ToolSpec{
+		Name:        "create_oj_task",
+		Description: "为一个或多个组织创建 OJ 任务，需要具备所有目标组织的 OJ 任务管理能力；执行前会向用户展示预览并等待确认。",
+		Parameters: []aidomain.ToolParameter{
+			{
+				Name:         "org_ids",
+				Type:         aidomain.ToolParameterTypeArray,
+				Description:  "任务下发的组织 ID 列表；省略时默认当前组织。",
+				MinItems:     aiIntPtr(1),
+				Examples:     []string{"[3]"},
+				DefaultValue: "[current_org_id]",
+				Items: &aidomain.ToolParameter{
+					Type:    aidomain.ToolParameterTypeInteger,
+					Minimum: aiFloatPtr(1),
+				},
+			},
+			{
+				Name:        "title",
+				Type:        aidomain.ToolParameterTypeString,
+				Description: "任务标题。",
+				Required:    true,
+				MinLength:   aiIntPtr(1),
+				MaxLength:   aiIntPtr(200),
+				Examples:    []string{"第 3 周洛谷练习"},
+			},
+			{
+				Name:        "description",
+				Type:        aidomain.ToolParameterTypeString,
+				Description: "任务说明。",
+				MaxLength:   aiIntPtr(2000),
+				Examples:    []string{"本周完成以下五道基础题。"},
+			},
+			{
+				Name:        "mode",
+				Type:        aidomain.ToolParameterTypeString,
+				Description: "执行模式：immediate 立即执行，scheduled 定时执行。",
+				Required:    true,
+				Enum:        []string{string(consts.OJTaskModeImmediate), string(consts.OJTaskModeScheduled)},
+				Examples:    []string{"scheduled"},
+			},
+			{
+				Name:        "execute_at",
+				Type:        aidomain.ToolParameterTypeString,
+				Description: "定时执行时间，mode=scheduled 时必填且必须是未来时间；immediate 时不要传。",
+				Format:      aidomain.ToolParameterFormatRFC3339,
+				Examples:    []string{"2026-04-25T08:00:00+08:00"},
+			},
+			{
+				Name:        "items",
+				Type:        aidomain.ToolParameterTypeArray,
+				Description: "任务题目列表。",
+				Required:    true,
+				MinItems:    aiIntPtr(1),
+				Examples:    []string{`[{"platform":"luogu","title":"A+B Problem"}]`},
+				Items:       &itemParam,
+			},
+		},
+	}, aidomain.ToolGroupOJTask, "创建 OJ 任务（需用户确认）。", "用户明确要求为组织布置、创建或定时下发 OJ 题目任务时使用。", "oj", "task", "create", "write")
+	descriptor.RequiresConfirmation = true
+
+	return &aiServiceTool{
+		descriptor: descriptor,
+		// policy 与 HTTP 创建任务接口保持同一项组织能力。
+		policy: newAIOrgCapabilityPolicy(consts.CapabilityCodeOJTaskManage),
+		// validate 在确认前拦下模式与时间组合错误，避免用户确认一个必然失败的操作。
+		validate: func(_ context.Context, call aidomain.ToolCall, _ aidomain.ToolCallContext) error {
+			var args aiCreateTaskArgs
+			if err := decodeAIToolArgs(call, &args); err != nil {
+				return err
+			}
+			_, err := aiResolveCreateTaskExecuteAt(args)
+			return err
+		},
+		// confirm 只读地分析题目命中情况，并生成待创建任务的 diff 预览。
+		confirm: func(ctx context.Context, call aidomain.ToolCall, callCtx aidomain.ToolCallContext) (aidomain.ToolConfirmation, error) {
+			args, orgIDs, executeAt, err := prepareAICreateTask(ctx, authorization, call, callCtx)
+			if err != nil {
+				return aidomain.ToolConfirmation{}, err
+			}
+			analysis, err := taskSvc.AnalyzeTaskTitles(ctx, buildAIAnalyzeTaskReq(args.Items))
+			if err != nil {
+				return aidomain.ToolConfirmation{}, err
+			}
+			return aidomain.ToolConfirmation{
+				Title:          "确认创建 OJ 任务",
+				Description:    fmt.Sprintf("将为 %d 个组织创建包含 %d 道题目的任务「%s」。", len(orgIDs), len(args.Items), strings.TrimSpace(args.Title)),
+				DetailMarkdown: renderAICreateTaskDiff(args, orgIDs, executeAt, analysis),
+			}, nil
+		},
+		// call 在用户确认后再次鉴权，并带上分析 token 调用正式创建接口。
+		call: func(ctx context.Context, call aidomain.ToolCall, callCtx aidomain.ToolCallContext) (aidomain.ToolResult, error) {
+			args, orgIDs, executeAt, err := prepareAICreateTask(ctx, authorization, call, callCtx)
+			if err != nil {
+				return aidomain.ToolResult{}, err
+			}
+			analysis, err := taskSvc.AnalyzeTaskTitles(ctx, buildAIAnalyzeTaskReq(args.Items))
+			if err != nil {
+				return aidomain.ToolResult{}, err
+			}
+
+			// 已唯一命中的题目带上 analysis_token，避免创建时再次按标题模糊匹配。
+			tokens := make(map[int]string, len(analysis.Resolved))
+			for _, item := range analysis.Resolved {
+				if item != nil {
+					tokens[item.InputIndex] = item.AnalysisToken
+				}
+			}
+			items := make([]request.OJTaskItemReq, 0, len(args.Items))
+			for idx, item := range args.Items {
+				items = append(items, request.OJTaskItemReq{
+					Platform:      strings.TrimSpace(item.Platform),
+					Title:         strings.TrimSpace(item.Title),
+					AnalysisToken: tokens[idx],
+				})
+			}
+
+			out, err := taskSvc.CreateTask(ctx, callCtx.Principal.UserID, &request.CreateOJTaskReq{
+				Title:       strings.TrimSpace(args.Title),
+				Description: strings.TrimSpace(args.Description),
+				Mode:        strings.TrimSpace(args.Mode),
+				ExecuteAt:   executeAt,
+				OrgIDs:      orgIDs,
+				Items:       items,
+			})
+			if err != nil {
+				return aidomain.ToolResult{}, err
+			}
+			return buildAIToolResult(out, fmt.Sprintf("已创建任务 #%d", out.TaskID))
+		},
+	}
+}
+
+// newAIRetryTaskTool 创建 OJ 任务重试工具，执行前需要用户确认。
+func newAIRetryTaskTool(
+	taskSvc aiOJTaskService,
+	authorization aiAuthorizationService,
+) *aiServiceTool {
+	descriptor := newAIToolDescriptor(aidomain.ToolSpec{
+		Name:        "retry_oj_task",
+		Description: "基于已完成或已失败的任务版本派生一个立即执行的新版本，需要具备任务关联组织的 OJ 任务管理能力；执行前需用户确认。",
+		Parameters: []aidomain.ToolParameter{
+			{
+				Name:        "task_id",
+				Type:        aidomain.ToolParameterTypeInteger,
+				Description: "要重试的任务版本 ID。",
+				Required:    true,
+				Minimum:     aiFloatPtr(1),
+				Examples:    []string{"42"},
+			},
+		},
+	}, aidomain.ToolGroupOJTask, "重试 OJ 任务（需用户确认）。", "用户要求重新执行某个已完成或失败的任务时使用。", "oj", "task", "retry", "write")
+	descriptor.RequiresConfirmation = true
+
+	return &aiServiceTool{
+		descriptor: descriptor,
+		policy:     newAIOrgCapabilityPolicy(consts.CapabilityCodeOJTaskManage),
+		confirm: func(ctx context.Context, call aidomain.ToolCall, callCtx aidomain.ToolCallContext) (aidomain.ToolConfirmation, error) {
+			detail, err := loadAIManagedTaskDetail(ctx, taskSvc, authorization, call, callCtx)
+			if err != nil {
+				return aidomain.ToolConfirmation{}, err
+			}
+			lines := []string{
+				fmt.Sprintf("  任务 #%d「%s」 v%d", detail.TaskID, detail.Title, detail.VersionNo),
+				"- 当前执行状态: " + aiTaskExecutionStatus(detail),
+				fmt.Sprintf("+ 新版本: v%d（retry，立即执行）", detail.VersionNo+1),
+				"+ 组织: " + aiTaskOrgLabel(detail),
+				fmt.Sprintf("+ 题目: %d 道（沿用当前版本）", len(detail.Items)),
+			}
+			return aidomain.ToolConfirmation{
+				Title:          "确认重试 OJ 任务",
+				Description:    fmt.Sprintf("将基于任务 #%d 派生新版本并立即执行。", detail.TaskID),
+				DetailMarkdown: renderAIDiff(lines),
+			}, nil
+		},
+		call: func(ctx context.Context, call aidomain.ToolCall, callCtx aidomain.ToolCallContext) (aidomain.ToolResult, error) {
+			detail, err := loadAIManagedTaskDetail(ctx, taskSvc, authorization, call, callCtx)
+			if err != nil {
+				return aidomain.ToolResult{}, err
+			}
+			out, err := taskSvc.RetryTask(ctx, callCtx.Principal.UserID, detail.TaskID)
+			if err != nil {
+				return aidomain.ToolResult{}, err
+			}
+			return buildAIToolResult(out, fmt.Sprintf("已重试任务，新版本 #%d", out.TaskID))
+		},
+	}
+}
+
+// newAIExecuteTaskNowTool 创建定时任务立即执行工具，执行前需要用户确认。
+func newAIExecuteTaskNowTool(
+	taskSvc aiOJTaskService,
+	authorization aiAuthorizationService,
+) *aiServiceTool {
+	descriptor := newAIToolDescriptor(aidomain.ToolSpec{
+		Name:        "execute_oj_task_now",
+		Description: "让尚未执行的定时任务立即执行，需要具备任务关联组织的 OJ 任务管理能力；执行前需用户确认。",
+		Parameters: []aidomain.ToolParameter{
+			{
+				Name:        "task_id",
+				Type:        aidomain.ToolParameterTypeInteger,
+				Description: "要立即执行的定时任务 ID。",
+				Required:    true,
+				Minimum:     aiFloatPtr(1),
+				Examples:    []string{"42"},
+			},
+		},
+	}, aidomain.ToolGroupOJTask, "立即执行定时 OJ 任务（需用户确认）。", "用户要求不等定时时间、马上执行某个定时任务时使用。", "oj", "task", "execute", "write")
+	descriptor.RequiresConfirmation = true
+
+	return &aiServiceTool{
+		descriptor: descriptor,
+		policy:     newAIOrgCapabilityPolicy(consts.CapabilityCodeOJTaskManage),
+		confirm: func(ctx context.Context, call aidomain.ToolCall, callCtx aidomain.ToolCallContext) (aidomain.ToolConfirmation, error) {
+			detail, err := loadAIManagedTaskDetail(ctx, taskSvc, authorization, call, callCtx)
+			if err != nil {
+				return aidomain.ToolConfirmation{}, err
+			}
+			lines := []string{
+				fmt.Sprintf("  任务 #%d「%s」 v%d", detail.TaskID, detail.Title, detail.VersionNo),
+				"- 状态: " + detail.Status,
+				"- 计划执行时间: " + defaultString(detail.ExecuteAt, "未设置"),
+				"+ 状态: " + string(consts.OJTaskStatusQueued),
+				"+ 计划执行时间: 立即（execute_now）",
+			}
+			return aidomain.ToolConfirmation{
+				Title:          "确认立即执行 OJ 任务",
+				Description:    fmt.Sprintf("任务 #%d 将跳过定时，立即进入执行队列。", detail.TaskID),
+				DetailMarkdown: renderAIDiff(lines),
+			}, nil
+		},
+		call: func(ctx context.Context, call aidomain.ToolCall, callCtx aidomain.ToolCallContext) (aidomain.ToolResult, error) {
+			detail, err := loadAIManagedTaskDetail(ctx, taskSvc, authorization, call, callCtx)
+			if err != nil {
+				return aidomain.ToolResult{}, err
+			}
+			out, err := taskSvc.ExecuteTaskNow(ctx, callCtx.Principal.UserID, detail.TaskID)
+			if err != nil {
+				return aidomain.ToolResult{}, err
+			}
+			return buildAIToolResult(out, fmt.Sprintf("任务 #%d 已进入执行队列", out.TaskID))
+		},
+	}
+}
+
+// prepareAICreateTask 负责解析创建任务参数、补全默认组织并逐个校验组织能力。
+func prepareAICreateTask(
+	ctx context.Context,
+	authorization aiAuthorizationService,
+	call aidomain.ToolCall,
+	callCtx aidomain.ToolCallContext,
+) (aiCreateTaskArgs, []uint, *time.Time, error) {
+	var args aiCreateTaskArgs
+	if err := decodeAIToolArgs(call, &args); err != nil {
+		return args, nil, nil, err
+	}
+	executeAt, err := aiResolveCreateTaskExecuteAt(args)
+	if err != nil {
+		return args, nil, nil, err
+	}
+
+	orgIDs := args.OrgIDs
+	if len(orgIDs) == 0 {
+		orgID, err := resolveAIOrgID(nil, callCtx.Principal.CurrentOrgID)
+		if err != nil {
+			return args, nil, nil, err
+		}
+		orgIDs = []uint{orgID}
+	}
+	if err := requireAIOrgCapabilityForMany(
+		ctx,
+		authorization,
+		callCtx.Principal,
+		orgIDs,
+		consts.CapabilityCodeOJTaskManage,
+	); err != nil {
+		return args, nil, nil, err
+	}
+	return args, orgIDs, executeAt, nil
+}
+
+// aiResolveCreateTaskExecuteAt 负责校验执行模式与 execute_at 的组合。
+func aiResolveCreateTaskExecuteAt(args aiCreateTaskArgs) (*time.Time, error) {
+	switch strings.TrimSpace(args.Mode) {
+	case string(consts.OJTaskModeImmediate):
+		if strings.TrimSpace(args.ExecuteAt) != "" {
+			return nil, aidomain.NewRepairableInvalidParamError(
+				"mode=immediate 时不允许传 execute_at。",
+				aidomain.ToolFieldError{
+					Field:    "execute_at",
+					Reason:   "invalid_combination",
+					Expected: "immediate 模式省略 execute_at",
+				},
+			)
+		}
+		return nil, nil
+	case string(consts.OJTaskModeScheduled):
+		value, err := aiParseRFC3339Field("execute_at", args.ExecuteAt, true, "2026-04-25T08:00:00+08:00")
+		if err != nil {
+			return nil, err
+		}
+		if !value.After(time.Now()) {
+			return nil, aidomain.NewRepairableInvalidParamError(
+				"execute_at 必须是未来时间。",
+				aidomain.ToolFieldError{
+					Field:    "execute_at",
+					Reason:   "invalid_range",
+					Expected: "晚于当前时间的 RFC3339 时间",
+					Example:  "2026-04-25T08:00:00+08:00",
+				},
+			)
+		}
+		return &value, nil
+	default:
+		return nil, aidomain.NewRepairableInvalidParamError(
+			"mode 仅支持 immediate 或 scheduled。",
+			aidomain.ToolFieldError{
+				Field:    "mode",
+				Reason:   "invalid_enum",
+				Expected: "immediate | scheduled",
+				Example:  "scheduled",
+			},
+		)
+	}
+}
+
+// loadAIManagedTaskDetail 负责解析任务 ID、读取任务详情并按关联组织做能力收口。
+func loadAIManagedTaskDetail(
+	ctx context.Context,
+	taskSvc aiOJTaskService,
+	authorization aiAuthorizationService,
+	call aidomain.ToolCall,
+	callCtx aidomain.ToolCallContext,
+) (*resp.OJTaskDetailResp, error) {
+	var args aiTaskIDArgs
+	if err := decodeAIToolArgs(call, &args); err != nil {
+		return nil, err
+	}
+	detail, err := taskSvc.GetTaskDetail(ctx, callCtx.Principal.UserID, args.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAITaskOrgCapability(
+		ctx,
+		authorization,
+		callCtx.Principal,
+		detail,
+		consts.CapabilityCodeOJTaskManage,
+	); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// buildAIAnalyzeTaskReq 负责把创建任务的题目参数转换成题目分析请求。
+func buildAIAnalyzeTaskReq(items []aiCreateTaskItemArgs) *request.AnalyzeOJTaskTitlesReq {
+	reqItems := make([]request.AnalyzeOJTaskTitleItemReq, 0, len(items))
+	for _, item := range items {
+		reqItems = append(reqItems, request.AnalyzeOJTaskTitleItemReq{
+			Platform: strings.TrimSpace(item.Platform),
+			Title:    strings.TrimSpace(item.Title),
+		})
+	}
+	return &request.AnalyzeOJTaskTitlesReq{Items: reqItems}
+}
+
+// renderAICreateTaskDiff 负责把待创建任务渲染成 diff 预览，题目行标注题库命中情况。
+func renderAICreateTaskDiff(
+	args aiCreateTaskArgs,
+	orgIDs []uint,
+	executeAt *time.Time,
+	analysis *resp.OJTaskAnalyzeResp,
+) string {
+	schedule := "立即执行"
+	if executeAt != nil {
+		schedule = "定时 " + executeAt.Format(time.RFC3339)
+	}
+	orgLabels := make([]string, 0, len(orgIDs))
+	for _, orgID := range orgIDs {
+		orgLabels = append(orgLabels, fmt.Sprintf("#%d", orgID))
+	}
+	lines := []string{
+		"+ 任务: " + strings.TrimSpace(args.Title),
+		"+ 模式: " + schedule,
+		"+ 组织: " + strings.Join(orgLabels, ", "),
+	}
+	if description := strings.TrimSpace(args.Description); description != "" {
+		lines = append(lines, "+ 说明: "+description)
+	}
+
+	notes := make(map[int]string, len(args.Items))
+	if analysis != nil {
+		for _, item := range analysis.Resolved {
+			if item != nil {
+				notes[item.InputIndex] = fmt.Sprintf("已命中 %s %s", item.ResolvedQuestionCode, item.ResolvedTitleSnapshot)
+			}
+		}
+		for _, item := range analysis.Ambiguous {
+			if item != nil {
+				notes[item.InputIndex] = fmt.Sprintf("存在 %d 个候选，创建会失败，请先明确题号", len(item.Options))
+			}
+		}
+		for _, item := range analysis.Missing {
+			if item != nil {
+				notes[item.InputIndex] = "题库暂未收录，将等待题目同步"
+			}
+		}
+	}
+	for idx, item := range args.Items {
+		line := fmt.Sprintf("+ 题目 %d: [%s] %s", idx+1, strings.TrimSpace(item.Platform), strings.TrimSpace(item.Title))
+		if note := notes[idx]; note != "" {
+			line += "（" + note + "）"
+		}
+		lines = append(lines, line)
+	}
+	return renderAIDiff(lines)
+}
+
+// renderAIDiff 负责把预览行包装成 diff 代码块。
+func renderAIDiff(lines []string) string {
+	return "```diff\n" + strings.Join(lines, "\n") + "\n```"
+}
+
+// aiTaskExecutionStatus 返回任务当前执行状态，缺失时回退任务状态。
+func aiTaskExecutionStatus(detail *resp.OJTaskDetailResp) string {
+	if detail.CurrentExecution != nil && detail.CurrentExecution.Status != "" {
+		return detail.CurrentExecution.Status
+	}
+	return detail.Status
+}
+
+// aiTaskOrgLabel 返回任务关联组织的展示文本。
+func aiTaskOrgLabel(detail *resp.OJTaskDetailResp) string {
+	labels := make([]string, 0, len(detail.Orgs))
+	for _, org := range detail.Orgs {
+		if org == nil {
+			continue
+		}
+		labels = append(labels, defaultString(org.OrgName, fmt.Sprintf("#%d", org.OrgID)))
+	}
+	return strings.Join(labels, ", ")
+}
@@ -0,0 +1,158 @@
+package aitool
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	aidomain "personal_assistant/internal/domain/ai"
+	"personal_assistant/internal/model/consts"
+	resp "personal_assistant/internal/model/dto/response"
+	bizerrors "personal_assistant/pkg/errors"
+)
+
+func TestAICreateTaskToolPreviewsDiffAndAttachesAnalysisToken(t *testing.T) {
+	orgID := uint(9)
+	taskSvc := &fakeAIToolOJTaskService{
+		analyzeResp: &resp.OJTaskAnalyzeResp{
+			Resolved: []*resp.OJTaskAnalyzeResolvedItemResp{
+				{
+					InputIndex:            0,
+					Platform:              "luogu",
+					Title:                 "A+B Problem",
+					AnalysisToken:         "token-0",
+					ResolvedQuestionCode:  "P1001",
+					ResolvedTitleSnapshot: "A+B Problem",
+				},
+			},
+			Missing: []*resp.OJTaskAnalyzeMissingItemResp{
+				{InputIndex: 1, Platform: "leetcode", Title: "new problem"},
+			},
+		},
+	}
+	auth := &fakeAIToolAuthorization{
+		capabilities: map[uint]map[string]bool{
+			orgID: {consts.CapabilityCodeOJTaskManage: true},
+		},
+	}
+	tool := newAIToolRegistry(Deps{
+		Authorization: auth,
+		OJTask:        taskSvc,
+	}).findTool("create_oj_task")
+	if tool == nil {
+		t.Fatal("tool create_oj_task not found")
+	}
+	if !tool.RequiresConfirmation() {
+		t.Fatal("create_oj_task should require confirmation")
+	}
+
+	call := aidomain.ToolCall{
+		ID:   "call_create",
+		Name: "create_oj_task",
+		ArgumentsJSON: `{"title":"第 3 周练习","mode":"immediate","items":[` +
+			`{"platform":"luogu","title":"A+B Problem"},{"platform":"leetcode","title":"new problem"}]}`,
+	}
+	callCtx := aidomain.ToolCallContext{
+		Principal: aidomain.AIToolPrincipal{UserID: 7, CurrentOrgID: &orgID},
+	}
+	if err := tool.Validate(context.Background(), call, callCtx); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	confirmation, err := tool.PrepareConfirmation(context.Background(), call, callCtx)
+	if err != nil {
+		t.Fatalf("PrepareConfirmation() error = %v", err)
+	}
+	if !strings.HasPrefix(confirmation.DetailMarkdown, "```diff") {
+		t.Fatalf("DetailMarkdown = %q, want diff block", confirmation.DetailMarkdown)
+	}
+	for _, want := range []string{"+ 任务: 第 3 周练习", "+ 组织: #9", "已命中 P1001", "将等待题目同步"} {
+		if !strings.Contains(confirmation.DetailMarkdown, want) {
+			t.Fatalf("DetailMarkdown missing %q:\n%s", want, confirmation.DetailMarkdown)
+		}
+	}
+	if len(taskSvc.createReqs) != 0 {
+		t.Fatalf("preview should not create task, createReqs = %d", len(taskSvc.createReqs))
+	}
+
+	if _, err := tool.Call(context.Background(), call, callCtx); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if len(taskSvc.createReqs) != 1 {
+		t.Fatalf("createReqs = %d, want 1", len(taskSvc.createReqs))
+	}
+	created := taskSvc.createReqs[0]
+	if len(created.OrgIDs) != 1 || created.OrgIDs[0] != orgID {
+		t.Fatalf("OrgIDs = %v, want [%d]", created.OrgIDs, orgID)
+	}
+	if created.Items[0].AnalysisToken != "token-0" || created.Items[1].AnalysisToken != "" {
+		t.Fatalf("items = %+v", created.Items)
+	}
+}
+
+func TestAICreateTaskToolRejectsInvalidModeCombination(t *testing.T) {
+	tool := newAIToolRegistry(Deps{
+		Authorization: &fakeAIToolAuthorization{},
+		OJTask:        &fakeAIToolOJTaskService{},
+	}).findTool("create_oj_task")
+
+	for _, args := range []string{
+		`{"title":"t","mode":"immediate","execute_at":"2099-01-01T08:00:00+08:00","items":[{"platform":"luogu","title":"x"}]}`,
+		`{"title":"t","mode":"scheduled","items":[{"platform":"luogu","title":"x"}]}`,
+		`{"title":"t","mode":"scheduled","execute_at":"2000-01-01T08:00:00+08:00","items":[{"platform":"luogu","title":"x"}]}`,
+	} {
+		err := tool.Validate(context.Background(), aidomain.ToolCall{
+			Name:          "create_oj_task",
+			ArgumentsJSON: args,
+		}, aidomain.ToolCallContext{})
+		if aidomain.FromToolIssueError(err) == nil {
+			t.Fatalf("Validate(%s) error = %v, want tool argument error", args, err)
+		}
+	}
+}
+
+func TestAIRetryTaskToolReauthorizesBeforeRetry(t *testing.T) {
+	taskSvc := &fakeAIToolOJTaskService{
+		taskDetailResp: &resp.OJTaskDetailResp{
+			TaskID:    5,
+			VersionNo: 2,
+			Title:     "周练",
+			Status:    "succeeded",
+			Orgs: []*resp.OJTaskOrgItemResp{
+				{OrgID: 9, OrgName: "org-9"},
+			},
+		},
+	}
+	auth := &fakeAIToolAuthorization{
+		capabilities: map[uint]map[string]bool{
+			9: {consts.CapabilityCodeOJTaskManage: true},
+		},
+	}
+	tool := newAIToolRegistry(Deps{
+		Authorization: auth,
+		OJTask:        taskSvc,
+	}).findTool("retry_oj_task")
+	if tool == nil {
+		t.Fatal("tool retry_oj_task not found")
+	}
+
+	call := aidomain.ToolCall{ID: "call_retry", Name: "retry_oj_task", ArgumentsJSON: `{"task_id":5}`}
+	callCtx := aidomain.ToolCallContext{Principal: aidomain.AIToolPrincipal{UserID: 7}}
+	confirmation, err := tool.PrepareConfirmation(context.Background(), call, callCtx)
+	if err != nil {
+		t.Fatalf("PrepareConfirmation() error = %v", err)
+	}
+	if !strings.Contains(confirmation.DetailMarkdown, "+ 新版本: v3") {
+		t.Fatalf("DetailMarkdown = %q", confirmation.DetailMarkdown)
+	}
+
+	// 确认等待期间能力被收回时，正式调用必须拒绝。
+	auth.capabilities[9][consts.CapabilityCodeOJTaskManage] = false
+	_, err = tool.Call(context.Background(), call, callCtx)
+	if bizErr := bizerrors.FromError(err); bizErr == nil || bizErr.Code != bizerrors.CodePermissionDenied {
+		t.Fatalf("Call() error = %v, want permission denied", err)
+	}
+	if taskSvc.retryCalls != 0 {
+		t.Fatalf("retryCalls = %d, want 0", taskSvc.retryCalls)
+	}
+}
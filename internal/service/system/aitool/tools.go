@@ -47,6 +47,8 @@ type aiServiceTool struct {
 	validate func(ctx context.Context, call aidomain.ToolCall, callCtx aidomain.ToolCallContext) error
 	// call 承载工具的实际业务执行逻辑。
 	call func(ctx context.Context, call aidomain.ToolCall, callCtx aidomain.ToolCallContext) (aidomain.ToolResult, error)
+	// confirm 生成人工确认预览；为空时回退为按参数 JSON 展示的默认预览。
+	confirm func(ctx context.Context, call aidomain.ToolCall, callCtx aidomain.ToolCallContext) (aidomain.ToolConfirmation, error)
 }
 
 // Spec 返回工具的稳定协议定义。
@@ -82,6 +84,39 @@ func (t *aiServiceTool) Validate(
 	return t.validate(ctx, call, callCtx)
 }
 
+// RequiresConfirmation 返回工具执行前是否需要用户人工确认。
+func (t *aiServiceTool) RequiresConfirmation() bool {
+	return t != nil && t.descriptor.RequiresConfirmation
+}
+
+// PrepareConfirmation 生成展示给用户的确认预览，只允许只读操作。
+func (t *aiServiceTool) PrepareConfirmation(
+	ctx context.Context,
+	call aidomain.ToolCall,
+	callCtx aidomain.ToolCallContext,
+) (aidomain.ToolConfirmation, error) {
+	if t == nil {
+		return aidomain.ToolConfirmation{}, bizerrors.NewWithMsg(bizerrors.CodeInternalError, "AI tool 未正确初始化")
+	}
+	if t.confirm != nil {
+		return t.confirm(ctx, call, callCtx)
+	}
+
+	// 默认预览直接展示归一化后的参数，保证用户至少能看到将要提交的内容。
+	detail := strings.TrimSpace(call.ArgumentsJSON)
+	var pretty any
+	if err := json.Unmarshal([]byte(detail), &pretty); err == nil {
+		if raw, marshalErr := json.MarshalIndent(pretty, "", "  "); marshalErr == nil {
+			detail = string(raw)
+		}
+	}
+	return aidomain.ToolConfirmation{
+		Title:          "确认执行 " + t.descriptor.Spec.Name,
+		Description:    defaultString(t.descriptor.Brief.Summary, t.descriptor.Spec.Description),
+		DetailMarkdown: "```json\n" + detail + "\n```",
+	}, nil
+}
+
 // Registry 负责注册工具、过滤可见性，并提供执行前定位能力。
 type Registry struct {
 	// authorization 用于按 principal 判断组织能力类工具是否可见。
@@ -115,7 +150,7 @@ func newAIToolRegistry(deps Deps) *Registry {
 // buildCatalog 根据当前依赖拼出本进程真正可提供的工具目录。
 func (r *Registry) buildCatalog(deps Deps) []*aiServiceTool {
 	// 先按预估容量创建切片，减少追加时的扩容次数。
-	tools := make([]*aiServiceTool, 0, 11)
+	tools := make([]*aiServiceTool, 0, 14)
 	if deps.OJ != nil {
 		// 个人 OJ 工具只依赖 OJService，本身不需要额外授权服务。
 		tools = append(tools,
@@ -128,6 +163,10 @@ func (r *Registry) buildCatalog(deps Deps) []*aiServiceTool {
 		// 组织排行榜工具既需要 OJ 数据，也需要组织能力鉴权。
 		tools = append(tools, newAIGetOrgRankingSummaryTool(deps.OJ, deps.Authorization))
 	}
+	if deps.OJ != nil {
+		// 综合排行只读取当前组织榜单，成员本人即可查看，与 /oj/ranking_list 的 current_org 口径一致。
+		tools = append(tools, newAIGetOrgOverallRankingTool(deps.OJ))
+	}
 	if deps.OJTask != nil && deps.Authorization != nil {
 		// 任务执行类工具统一依赖 OJTaskService 和授权服务。
 		tools = append(tools,
@@ -135,6 +174,10 @@ func (r *Registry) buildCatalog(deps Deps) []*aiServiceTool {
 			newAIListTaskExecutionUsersTool(deps.OJTask, deps.Authorization),
 			newAIGetTaskExecutionUserDetailTool(deps.OJTask, deps.Authorization),
 			newAIAnalyzeTaskTitlesTool(deps.OJTask, deps.Authorization),
+			// 写操作类任务工具统一声明人工确认，执行前先展示预览。
+			newAICreateTaskTool(deps.OJTask, deps.Authorization),
+			newAIRetryTaskTool(deps.OJTask, deps.Authorization),
+			newAIExecuteTaskNowTool(deps.OJTask, deps.Authorization),
 		)
 	}
 	if deps.Observability != nil && deps.Authorization != nil {
@@ -490,7 +533,7 @@ func newAIGetMyRankingTool(ojSvc aiOJService) *aiServiceTool {
 					Type:         aidomain.ToolParameterTypeString,
 					Description:  "OJ 平台，只支持个人已绑定数据的平台。",
 					Required:     true,
-					Enum:         []string{"leetcode", "luogu", "lanqiao"},
+					Enum:         consts.SupportedOJPlatforms(),
 					Examples:     []string{"leetcode", "luogu"},
 					DefaultValue: "",
 				},
@@ -560,7 +603,7 @@ func newAIGetMyOJStatsTool(ojSvc aiOJService) *aiServiceTool {
 					Type:        aidomain.ToolParameterTypeString,
 					Description: "OJ 平台。",
 					Required:    true,
-					Enum:        []string{"leetcode", "luogu", "lanqiao"},
+					Enum:        consts.SupportedOJPlatforms(),
 					Examples:    []string{"leetcode"},
 				},
 			},
@@ -600,7 +643,7 @@ func newAIGetMyOJCurveTool(ojSvc aiOJService) *aiServiceTool {
 					Type:        aidomain.ToolParameterTypeString,
 					Description: "OJ 平台。",
 					Required:    true,
-					Enum:        []string{"leetcode", "luogu", "lanqiao"},
+					Enum:        consts.SupportedOJPlatforms(),
 					Examples:    []string{"leetcode"},
 				},
 			},
@@ -663,7 +706,7 @@ func newAIGetOrgRankingSummaryTool(
 					Type:        aidomain.ToolParameterTypeString,
 					Description: "OJ 平台。",
 					Required:    true,
-					Enum:        []string{"leetcode", "luogu", "lanqiao"},
+					Enum:        consts.SupportedOJPlatforms(),
 					Examples:    []string{"leetcode"},
 				},
 				{
@@ -727,6 +770,99 @@ func newAIGetOrgRankingSummaryTool(
 	}
 }
 
+// aiOrgOverallRankingArgs 表示组织综合排行工具的输入参数。
+type aiOrgOverallRankingArgs struct {
+	// Period 表示统计周期，省略时默认 total（累计）。
+	Period string `json:"period,omitempty"`
+	// Limit 表示返回前几名，省略时默认 10。
+	Limit int `json:"limit,omitempty"`
+}
+
+// newAIGetOrgOverallRankingTool 创建当前组织跨平台综合排行工具。
+func newAIGetOrgOverallRankingTool(ojSvc aiOJService) *aiServiceTool {
+	return &aiServiceTool{
+		descriptor: newAIToolDescriptor(aidomain.ToolSpec{
+			Name:        "get_org_overall_ranking",
+			Description: "获取当前组织跨平台（力扣、洛谷、蓝桥）综合排行榜前几名及当前用户的综合名次，综合分按平台权重折算。",
+			Parameters: []aidomain.ToolParameter{
+				{
+					Name:         "period",
+					Type:         aidomain.ToolParameterTypeString,
+					Description:  "统计周期：total 为累计，week 为本周新增，month 为本月新增；省略时默认 total。",
+					Enum:         []string{"total", "week", "month"},
+					Examples:     []string{"total", "week"},
+					DefaultValue: "total",
+				},
+				{
+					Name:         "limit",
+					Type:         aidomain.ToolParameterTypeInteger,
+					Description:  "返回前几名。",
+					Minimum:      aiFloatPtr(1),
+					Maximum:      aiFloatPtr(20),
+					Examples:     []string{"10"},
+					DefaultValue: "10",
+				},
+			},
+		}, aidomain.ToolGroupOJOrg, "查询当前组织的跨平台综合排名。", "用户想知道组织里综合实力最强、总体排名靠前的人时使用。", "oj", "org", "ranking", "overall"),
+		// 只读取当前用户所在组织的榜单，组织成员资格由 OJService 校验。
+		policy: newAISelfOnlyPolicy(),
+		call: func(ctx context.Context, call aidomain.ToolCall, callCtx aidomain.ToolCallContext) (aidomain.ToolResult, error) {
+			var args aiOrgOverallRankingArgs
+			if err := decodeAIToolArgs(call, &args); err != nil {
+				return aidomain.ToolResult{}, err
+			}
+
+			limit := args.Limit
+			if limit <= 0 {
+				limit = 10
+			}
+			if limit > 20 {
+				limit = 20
+			}
+			period := defaultString(strings.ToLower(strings.TrimSpace(args.Period)), "total")
+			req := &request.OJRankingListReq{
+				Page:     1,
+				PageSize: limit,
+				Platform: "all",
+				Scope:    "current_org",
+			}
+			switch period {
+			case "total":
+			case "week", "month":
+				req.Mode = consts.OJRankingModeWindow
+				req.Window = period
+			default:
+				return aidomain.ToolResult{}, aidomain.NewRepairableInvalidParamError(
+					"period 只支持 total、week、month。",
+					aidomain.ToolFieldError{
+						Field:    "period",
+						Reason:   "invalid_enum",
+						Expected: "total / week / month",
+						Example:  `{"period":"week"}`,
+					},
+				)
+			}
+
+			out, err := ojSvc.GetRankingList(ctx, callCtx.Principal.UserID, req)
+			if err != nil {
+				return aidomain.ToolResult{}, err
+			}
+			payload := map[string]any{
+				"period":  period,
+				"top":     []*resp.OJRankingListItem{},
+				"my_rank": nil,
+				"total":   int64(0),
+			}
+			if out != nil {
+				payload["top"] = out.List
+				payload["my_rank"] = out.MyRank
+				payload["total"] = out.Total
+			}
+			return buildAIToolResult(payload, "已返回当前组织的综合排行")
+		},
+	}
+}
+
 // aiTaskExecutionArgs 表示任务执行摘要工具的输入参数。
 type aiTaskExecutionArgs struct {
 	// TaskID 表示目标任务 ID。
@@ -1036,7 +1172,7 @@ func newAIAnalyzeTaskTitlesTool(
 				Type:        aidomain.ToolParameterTypeString,
 				Description: "OJ 平台。",
 				Required:    true,
-				Enum:        []string{"luogu", "leetcode", "lanqiao"},
+				Enum:        consts.SupportedOJPlatforms(),
 				Examples:    []string{"leetcode"},
 			},
 			{
@@ -59,6 +59,8 @@ func newAuthorizationTestEnv(t *testing.T) *authorizationTestEnv {
 		&entity.RoleCapability{},
 		&entity.Image{},
 		&entity.OutboxEvent{},
+		&entity.OrgInvitation{},
+		&entity.OrgJoinRequest{},
 	); err != nil {
 		t.Fatalf("auto migrate: %v", err)
 	}
@@ -110,7 +112,7 @@ func newAuthorizationTestEnv(t *testing.T) *authorizationTestEnv {
 		authorization: authorizationService,
 		projection:    projectionService,
 		orgService:    NewOrgService(repoGroup, authorizationService, projectionService),
-		userService:   NewUserService(repoGroup, authorizationService, projectionService),
+		userService:   NewUserService(repoGroup, authorizationService, projectionService, NewJWTService(repoGroup)),
 	}
 }
 
@@ -1,8 +1,10 @@
 package system
 
 import (
+	"crypto/subtle"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/sessions"
@@ -12,6 +14,7 @@ import (
 
 	"personal_assistant/global"
 	"personal_assistant/internal/model/dto/request"
+	bizerrors "personal_assistant/pkg/errors"
 	"personal_assistant/pkg/util"
 )
 
@@ -96,3 +99,27 @@ func (b *BaseService) SendEmailVerificationCode(ctx *gin.Context, to string) err
 
 	return nil
 }
+
+// ConsumeEmailVerificationCode 校验 SendEmailVerificationCode 写入会话的验证码，邮箱需与发送时一致；
+// 通过后清除会话中的验证码，保证一次性使用。
+func (b *BaseService) ConsumeEmailVerificationCode(ctx *gin.Context, email, code string) error {
+	session := sessions.Default(ctx)
+	storedCode, _ := session.Get("verification_code").(string)
+	storedEmail, _ := session.Get("email").(string)
+	expireTime, _ := session.Get("expire_time").(int64)
+	if storedCode == "" || time.Now().Unix() > expireTime {
+		return bizerrors.New(bizerrors.CodeCaptchaExpired)
+	}
+	if !strings.EqualFold(strings.TrimSpace(email), storedEmail) ||
+		subtle.ConstantTimeCompare([]byte(strings.TrimSpace(code)), []byte(storedCode)) != 1 {
+		return bizerrors.New(bizerrors.CodeCaptchaError)
+	}
+
+	session.Delete("verification_code")
+	session.Delete("email")
+	session.Delete("expire_time")
+	if err := session.Save(); err != nil {
+		global.Log.Error("保存session错误：", zap.Error(err))
+	}
+	return nil
+}
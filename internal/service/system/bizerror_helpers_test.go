@@ -0,0 +1,9 @@
+package system
+
+import bizerrors "personal_assistant/pkg/errors"
+
+// hasBizCode 判断 err 是否为指定业务码的错误，供各服务测试共用。
+func hasBizCode(err error, code bizerrors.BizCode) bool {
+	bizErr := bizerrors.FromError(err)
+	return bizErr != nil && bizErr.Code == code
+}
@@ -115,6 +115,10 @@ func (s *CacheProjectionService) RebuildAll(ctx context.Context) error {
 	if err := s.deleteByPattern(ctx, "ranking:all_members:*"); err != nil {
 		return err
 	}
+	// 时间窗口榜依赖累计榜成员集合，按需重新计算即可，这里直接清空避免沿用旧成员。
+	if err := s.deleteByPattern(ctx, "ranking:window:*"); err != nil {
+		return err
+	}
 
 	// 回源查询全部可投影用户的聚合读模型，作为本次全量重建的数据源。
 	items, err := s.readModelRepo.ListAll(ctx)
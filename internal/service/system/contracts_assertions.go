@@ -13,6 +13,8 @@ var (
 	_ contract.OrgServiceContract                    = (*OrgService)(nil)
 	_ contract.OJServiceContract                     = (*OJService)(nil)
 	_ contract.OJTaskServiceContract                 = (*OJTaskService)(nil)
+	_ contract.OJContestServiceContract              = (*OJContestService)(nil)
+	_ contract.OJQuestionBankServiceContract         = (*OJQuestionBankService)(nil)
 	_ contract.OJDailyStatsProjectionServiceContract = (*OJDailyStatsProjectionService)(nil)
 	_ contract.CacheProjectionServiceContract        = (*CacheProjectionService)(nil)
 	_ contract.ApiServiceContract                    = (*ApiService)(nil)
@@ -3,6 +3,8 @@ package system
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"personal_assistant/global"
 	"personal_assistant/internal/model/consts"
@@ -12,9 +14,6 @@ import (
 	"personal_assistant/internal/repository"
 	erro "personal_assistant/pkg/errors"
 	"personal_assistant/pkg/jwt"
-	"personal_assistant/pkg/util"
-
-	"github.com/go-redis/redis/v8"
 
 	"github.com/gofrs/uuid"
 )
@@ -29,115 +28,301 @@ func NewJWTService(repositoryGroup *repository.Group) *JWTService {
 	}
 }
 
-// SetRedisJWT 将JWT存储到Redis中
-func (j *JWTService) SetRedisJWT(jwt string, uuid uuid.UUID) error {
-	// 解析配置中的JWT过期时间
-	dr, err := util.ParseDuration(global.Config.JWT.RefreshTokenExpiryTime)
-	if err != nil {
-		return err
-	}
-	// 设置JWT在Redis中的过期时间
-	return global.Redis.Set(context.Background(), uuid.String(), jwt, dr).Err()
+// sessionTokens 一次签发得到的访问令牌与刷新令牌
+type sessionTokens struct {
+	accessToken      string
+	accessExpiresAt  time.Time
+	refreshToken     string
+	refreshExpiresAt time.Time
 }
 
-// GetRedisJWT 从Redis中获取JWT
-func (j *JWTService) GetRedisJWT(ctx context.Context, uuid uuid.UUID) (string, error) {
-	// 从Redis获取指定uuid对应的JWT
-	return global.Redis.Get(ctx, uuid.String()).Result()
-}
-
-// JoinInBlacklist 将JWT添加到黑名单
+// JoinInBlacklist 将会话加入黑名单
 func (j *JWTService) JoinInBlacklist(ctx context.Context, jwtList entity.JwtBlacklist) error {
-	// 将JWT记录插入到数据库中的黑名单表
+	if strings.TrimSpace(jwtList.SessionID) == "" {
+		return errors.New("blacklist session id is empty")
+	}
+	// 将会话记录插入到数据库中的黑名单表
 	jwtRepo := j.repositoryGroup.SystemRepositorySupplier.GetJWTRepository()
 	if err := jwtRepo.CreateJwtBlacklist(ctx, &jwtList); err != nil {
 		return err
 	}
-	// 将JWT添加到内存中的黑名单缓存
-	global.BlackCache.SetDefault(jwtList.JWT, struct{}{})
+	// 将会话添加到内存中的黑名单缓存，访问令牌校验也据此拦截
+	global.BlackCache.SetDefault(jwtList.SessionID, struct{}{})
 	return nil
 }
 
-// IsInBlacklist 检查JWT是否在黑名单中
-func (j *JWTService) IsInBlacklist(jwt string) bool {
-	// 从黑名单缓存中检查JWT是否存在
-	_, ok := global.BlackCache.Get(jwt)
+// IsInBlacklist 检查会话是否在黑名单中
+func (j *JWTService) IsInBlacklist(sessionID string) bool {
+	if sessionID == "" {
+		return false
+	}
+	_, ok := global.BlackCache.Get(sessionID)
 	return ok
 }
 
-// GetUserFromJWT 获取用户信息
-func (j *JWTService) GetUserFromJWT(ctx context.Context, token string) (user *entity.User, jwtError *erro.JWTError) {
-	jwtTool := jwt.NewJWT()
+// IssueLoginTokens 登录后创建设备会话，并签发访问令牌与刷新令牌
+func (j *JWTService) IssueLoginTokens(
+	ctx context.Context,
+	user entity.User,
+	device request.LoginDevice,
+) (*response.LoginResponse, string, int64, *erro.JWTError) {
+	if user.Freeze || user.Status != consts.UserStatusActive {
+		return nil, "", 0, &erro.JWTError{
+			Code:    erro.CodeUserDisabled,
+			Message: "用户已被禁用",
+			Err:     errors.New("user disabled"),
+		}
+	}
+
+	// 多点登录拦截：新登录会挤掉该账号已有的全部会话
+	if global.Config.System.UseMultipoint {
+		if _, err := j.revokeUserSessions(ctx, user.ID, "", consts.UserSessionRevokeReasonSingleLogin); err != nil {
+			return nil, "", 0, &erro.JWTError{
+				Code:    erro.CodeInternalError,
+				Message: "注销旧会话失败",
+				Err:     err,
+			}
+		}
+	}
+
+	now := time.Now()
+	session := &entity.UserSession{
+		UserID:      user.ID,
+		SessionID:   newSessionTokenID(),
+		CurrentJTI:  newSessionTokenID(),
+		DeviceLabel: resolveSessionDeviceLabel(device.Label, device.UserAgent),
+		IP:          device.IP,
+		UserAgent:   truncateRunes(device.UserAgent, 500),
+		LastSeenAt:  now,
+	}
+	tokens, jwtErr := j.signSessionTokens(user, session.SessionID, session.CurrentJTI)
+	if jwtErr != nil {
+		return nil, "", 0, jwtErr
+	}
+	session.ExpiresAt = tokens.refreshExpiresAt
+
+	sessionRepo := j.repositoryGroup.SystemRepositorySupplier.GetUserSessionRepository()
+	if err := sessionRepo.Create(ctx, session); err != nil {
+		return nil, "", 0, &erro.JWTError{
+			Code:    erro.CodeInternalError,
+			Message: "设置登录状态失败",
+			Err:     err,
+		}
+	}
+
+	// 响应封装
+	res := &response.LoginResponse{
+		User:                 user,
+		AccessToken:          tokens.accessToken,
+		AccessTokenExpiresAt: tokens.accessExpiresAt.Unix() * 1000,
+		RefreshToken:         tokens.refreshToken,
+	}
+	return res, tokens.refreshToken, tokens.refreshExpiresAt.Unix() * 1000, nil
+}
+
+// RefreshTokens 用刷新令牌换取新的访问令牌，同时轮换刷新令牌。
+// 同一会话内只有最近一次签发的刷新令牌可用；旧令牌再次出现视为被盗用，整个会话立即下线。
+// 返回新的令牌与新刷新令牌的过期时间（毫秒）。
+func (j *JWTService) RefreshTokens(
+	ctx context.Context,
+	token string,
+	device request.LoginDevice,
+) (*response.RefreshTokenResponse, int64, *erro.JWTError) {
 	// 注意：这里解析的是刷新令牌（来自 HttpOnly Cookie 的 x-refresh-token），
 	// 因此必须使用 ParseRefreshToken，而不是 ParseAccessToken。
-	refreshClaims, err := jwtTool.ParseRefreshToken(token)
+	claims, err := jwt.NewJWT().ParseRefreshToken(token)
+	if err != nil {
+		return nil, 0, erro.ClassifyJWTError(err)
+	}
+	// 会话注册表上线前签发的刷新令牌没有会话信息，要求重新登录
+	if claims.SessionID == "" || claims.ID == "" {
+		return nil, 0, &erro.JWTError{
+			Code:    erro.CodeTokenInvalid,
+			Message: "登录状态已失效，请重新登录",
+			Err:     errors.New("refresh token without session"),
+		}
+	}
+	if j.IsInBlacklist(claims.SessionID) {
+		return nil, 0, sessionRevokedJWTError("会话已下线，请重新登录")
+	}
+
+	sessionRepo := j.repositoryGroup.SystemRepositorySupplier.GetUserSessionRepository()
+	session, err := sessionRepo.GetBySessionID(ctx, claims.SessionID)
 	if err != nil {
-		return nil, erro.ClassifyJWTError(err)
+		return nil, 0, erro.ClassifyJWTError(err)
+	}
+	now := time.Now()
+	if session == nil || session.UserID != claims.UserID || session.RevokedAt != nil || !session.ExpiresAt.After(now) {
+		return nil, 0, sessionRevokedJWTError("会话已下线，请重新登录")
 	}
+	if claims.ID != session.CurrentJTI {
+		return nil, 0, j.killReusedSession(ctx, session)
+	}
+
 	// 验证用户是否存在，且未被冻结
-	jwtRepo := j.repositoryGroup.SystemRepositorySupplier.GetJWTRepository()
-	user, err = jwtRepo.GetUserByID(ctx, refreshClaims.UserID)
+	user, err := j.repositoryGroup.SystemRepositorySupplier.GetJWTRepository().GetUserByID(ctx, claims.UserID)
 	if err != nil {
-		return nil, erro.ClassifyJWTError(err)
+		return nil, 0, erro.ClassifyJWTError(err)
 	}
 	if user.Freeze || user.Status != consts.UserStatusActive {
-		return user, &erro.JWTError{
+		return nil, 0, &erro.JWTError{
 			Code:    erro.CodeUserDisabled,
 			Message: "用户已被禁用",
 			Err:     errors.New("user has been disabled"),
 		}
 	}
-	return user, nil
-}
 
-// GetAccessToken 获取
-func (j *JWTService) GetAccessToken(ctx context.Context, token string) (resR *response.RefreshTokenResponse, jwtError *erro.JWTError) {
-	user, jwtErr := j.GetUserFromJWT(ctx, token)
+	nextJTI := newSessionTokenID()
+	tokens, jwtErr := j.signSessionTokens(*user, session.SessionID, nextJTI)
 	if jwtErr != nil {
-		return nil, jwtErr
+		return nil, 0, jwtErr
 	}
-	jwtTool := jwt.NewJWT()
-	claims := jwtTool.CreateAccessClaims(request.BaseClaims{
-		UserID: user.ID,
-		UUID:   user.UUID,
-		// 注意：移除了RoleID，现在通过权限服务动态获取用户角色
-	})
-	Token, err := jwtTool.CreateAccessToken(claims)
+	rotated := &entity.UserSession{
+		CurrentJTI: nextJTI,
+		IP:         session.IP,
+		UserAgent:  session.UserAgent,
+		LastSeenAt: now,
+		ExpiresAt:  tokens.refreshExpiresAt,
+	}
+	if device.IP != "" {
+		rotated.IP = device.IP
+	}
+	if device.UserAgent != "" {
+		rotated.UserAgent = truncateRunes(device.UserAgent, 500)
+	}
+	ok, err := sessionRepo.Rotate(ctx, session.SessionID, claims.ID, rotated)
 	if err != nil {
-		return nil, &erro.JWTError{
-			Code:    erro.CodeInternalError,
-			Message: "生成Token失败",
-			Err:     errors.New("create token failed"),
-		}
+		return nil, 0, erro.ClassifyJWTError(err)
 	}
-	resR = &response.RefreshTokenResponse{
-		AccessToken:          Token,
-		AccessTokenExpiresAt: claims.ExpiresAt.Unix() * 1000,
+	if !ok {
+		// 读取之后令牌已被其他请求轮换，同样按重放处理
+		return nil, 0, j.killReusedSession(ctx, session)
 	}
-	return resR, nil
+
+	return &response.RefreshTokenResponse{
+		AccessToken:          tokens.accessToken,
+		AccessTokenExpiresAt: tokens.accessExpiresAt.Unix() * 1000,
+		RefreshToken:         tokens.refreshToken,
+	}, tokens.refreshExpiresAt.Unix() * 1000, nil
 }
 
-// IssueLoginTokens 登录后签发访问令牌与刷新令牌（支持多点登录管控）
-func (j *JWTService) IssueLoginTokens(
+// RevokeUserRefreshTokens 吊销用户全部会话，会话内签发的刷新令牌随之失效（找回密码、禁用账号等安全场景）
+func (j *JWTService) RevokeUserRefreshTokens(ctx context.Context, user entity.User, reason string) error {
+	_, err := j.revokeUserSessions(ctx, user.ID, "", reason)
+	return err
+}
+
+// ListUserSessions 列出用户当前有效的设备会话，并标记发起请求的会话
+func (j *JWTService) ListUserSessions(
 	ctx context.Context,
-	user entity.User,
-) (*response.LoginResponse, string, int64, *erro.JWTError) {
-	if user.Freeze || user.Status != consts.UserStatusActive {
-		return nil, "", 0, &erro.JWTError{
-			Code:    erro.CodeUserDisabled,
-			Message: "用户已被禁用",
-			Err:     errors.New("user disabled"),
+	userID uint,
+	currentSessionID string,
+) ([]*response.UserSessionItem, error) {
+	sessionRepo := j.repositoryGroup.SystemRepositorySupplier.GetUserSessionRepository()
+	sessions, err := sessionRepo.ListActiveByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return nil, erro.Wrap(erro.CodeDBError, err)
+	}
+	items := make([]*response.UserSessionItem, 0, len(sessions))
+	for _, session := range sessions {
+		items = append(items, &response.UserSessionItem{
+			SessionID:   session.SessionID,
+			DeviceLabel: session.DeviceLabel,
+			IP:          session.IP,
+			UserAgent:   session.UserAgent,
+			LastSeenAt:  session.LastSeenAt,
+			CreatedAt:   session.CreatedAt,
+			ExpiresAt:   session.ExpiresAt,
+			Current:     session.SessionID == currentSessionID,
+		})
+	}
+	return items, nil
+}
+
+// RevokeUserSession 吊销用户自己的某个会话，重复吊销视为成功
+func (j *JWTService) RevokeUserSession(ctx context.Context, userID uint, sessionID, reason string) error {
+	sessionRepo := j.repositoryGroup.SystemRepositorySupplier.GetUserSessionRepository()
+	session, err := sessionRepo.GetBySessionID(ctx, strings.TrimSpace(sessionID))
+	if err != nil {
+		return erro.Wrap(erro.CodeDBError, err)
+	}
+	if session == nil || session.UserID != userID {
+		return erro.New(erro.CodeSessionNotFound)
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+	if err := j.revokeSession(ctx, session, reason); err != nil {
+		return erro.Wrap(erro.CodeDBError, err)
+	}
+	return nil
+}
+
+// RevokeOtherUserSessions 吊销除当前会话外的全部会话，返回吊销数量
+func (j *JWTService) RevokeOtherUserSessions(ctx context.Context, userID uint, currentSessionID string) (int, error) {
+	if currentSessionID == "" {
+		return 0, erro.NewWithMsg(erro.CodeTokenInvalid, "当前登录会话无法识别，请重新登录")
+	}
+	count, err := j.revokeUserSessions(ctx, userID, currentSessionID, consts.UserSessionRevokeReasonUserRevoke)
+	if err != nil {
+		return 0, erro.Wrap(erro.CodeDBError, err)
+	}
+	return count, nil
+}
+
+// revokeUserSessions 吊销用户全部有效会话，keepSessionID 非空时保留该会话
+func (j *JWTService) revokeUserSessions(ctx context.Context, userID uint, keepSessionID, reason string) (int, error) {
+	sessionRepo := j.repositoryGroup.SystemRepositorySupplier.GetUserSessionRepository()
+	sessions, err := sessionRepo.ListActiveByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, session := range sessions {
+		if session.SessionID == keepSessionID {
+			continue
+		}
+		if err := j.revokeSession(ctx, session, reason); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// revokeSession 标记会话吊销并加入黑名单
+func (j *JWTService) revokeSession(ctx context.Context, session *entity.UserSession, reason string) error {
+	sessionRepo := j.repositoryGroup.SystemRepositorySupplier.GetUserSessionRepository()
+	if err := sessionRepo.Revoke(ctx, session.SessionID, reason, time.Now()); err != nil {
+		return err
+	}
+	expiresAt := session.ExpiresAt
+	return j.JoinInBlacklist(ctx, entity.JwtBlacklist{SessionID: session.SessionID, ExpiresAt: &expiresAt})
+}
+
+// killReusedSession 刷新令牌重放时注销整个会话
+func (j *JWTService) killReusedSession(ctx context.Context, session *entity.UserSession) *erro.JWTError {
+	global.Log.Warn("检测到刷新令牌重复使用，注销会话")
+	if err := j.revokeSession(ctx, session, consts.UserSessionRevokeReasonTokenReused); err != nil {
+		return &erro.JWTError{
+			Code:    erro.CodeInternalError,
+			Message: "注销会话失败",
+			Err:     err,
 		}
 	}
+	return sessionRevokedJWTError("检测到登录凭证被重复使用，该设备已下线，请重新登录")
+}
 
+// signSessionTokens 为指定会话签发访问令牌与刷新令牌
+func (j *JWTService) signSessionTokens(user entity.User, sessionID, jti string) (*sessionTokens, *erro.JWTError) {
 	jwtTool := jwt.NewJWT()
-	base := request.BaseClaims{UserID: user.ID, UUID: user.UUID}
+	base := request.BaseClaims{UserID: user.ID, UUID: user.UUID, SessionID: sessionID}
 
 	// 访问令牌
 	accessClaims := jwtTool.CreateAccessClaims(base)
 	accessToken, err := jwtTool.CreateAccessToken(accessClaims)
 	if err != nil {
-		return nil, "", 0, &erro.JWTError{
+		return nil, &erro.JWTError{
 			Code:    erro.CodeInternalError,
 			Message: "生成访问令牌失败",
 			Err:     err,
@@ -146,63 +331,59 @@ func (j *JWTService) IssueLoginTokens(
 
 	// 刷新令牌
 	refreshClaims := jwtTool.CreateRefreshClaims(base)
+	refreshClaims.ID = jti
 	refreshToken, err := jwtTool.CreateRefreshToken(refreshClaims)
 	if err != nil {
-		return nil, "", 0, &erro.JWTError{
+		return nil, &erro.JWTError{
 			Code:    erro.CodeInternalError,
 			Message: "生成刷新令牌失败",
 			Err:     err,
 		}
 	}
+	return &sessionTokens{
+		accessToken:      accessToken,
+		accessExpiresAt:  accessClaims.ExpiresAt.Time,
+		refreshToken:     refreshToken,
+		refreshExpiresAt: refreshClaims.ExpiresAt.Time,
+	}, nil
+}
 
-	// 多点登录控制
-	if global.Config.System.UseMultipoint {
-		// 尝试获取旧的JWT
-		old, err := j.GetRedisJWT(ctx, user.UUID)
-
-		if errors.Is(err, redis.Nil) {
-			// 无旧记录（Redis中不存在该用户的token），这属于正常情况，直接设置新的刷新令牌
-			if err := j.SetRedisJWT(refreshToken, user.UUID); err != nil {
-				return nil, "", 0, &erro.JWTError{
-					Code:    erro.CodeInternalError,
-					Message: "设置登录状态失败",
-					Err:     err,
-				}
-			}
-		} else if err != nil {
-			// 读取Redis失败（真正的错误，如连接超时、认证失败等）
-			return nil, "", 0, &erro.JWTError{
-				Code:    erro.CodeInternalError,
-				Message: "读取登录状态失败",
-				Err:     err,
-			}
-		} else {
-			// 存在旧记录，将旧刷新令牌加入黑名单
-			bl := entity.JwtBlacklist{JWT: old}
-			if err = j.JoinInBlacklist(ctx, bl); err != nil {
-				return nil, "", 0, &erro.JWTError{
-					Code:    erro.CodeInternalError,
-					Message: "旧令牌加入黑名单失败",
-					Err:     err,
-				}
-			}
-			// 写入新令牌
-			if err = j.SetRedisJWT(refreshToken, user.UUID); err != nil {
-				return nil, "", 0, &erro.JWTError{
-					Code:    erro.CodeInternalError,
-					Message: "设置登录状态失败",
-					Err:     err,
-				}
+func sessionRevokedJWTError(message string) *erro.JWTError {
+	return &erro.JWTError{
+		Code:    erro.CodeTokenBlacklisted,
+		Message: message,
+		Err:     errors.New("session revoked"),
+	}
+}
+
+func newSessionTokenID() string {
+	return uuid.Must(uuid.NewV4()).String()
+}
+
+// resolveSessionDeviceLabel 优先使用客户端上报的设备名，否则从 User-Agent 粗略推断“浏览器 · 系统”。
+func resolveSessionDeviceLabel(label, userAgent string) string {
+	if label = strings.TrimSpace(label); label != "" {
+		return truncateRunes(label, 100)
+	}
+	pick := func(rules [][2]string) string {
+		for _, rule := range rules {
+			if strings.Contains(userAgent, rule[0]) {
+				return rule[1]
 			}
 		}
+		return ""
 	}
-
-	// 响应封装
-	res := &response.LoginResponse{
-		User:                 user,
-		AccessToken:          accessToken,
-		AccessTokenExpiresAt: accessClaims.ExpiresAt.Unix() * 1000,
-		RefreshToken:         refreshToken,
+	// 顺序有意义：Edge/Opera 的 UA 同时包含 Chrome，Chrome 的 UA 同时包含 Safari
+	browser := pick([][2]string{{"Edg/", "Edge"}, {"OPR/", "Opera"}, {"Chrome/", "Chrome"}, {"Firefox/", "Firefox"}, {"Safari/", "Safari"}})
+	system := pick([][2]string{{"Windows", "Windows"}, {"iPhone", "iOS"}, {"iPad", "iPadOS"}, {"Android", "Android"}, {"Mac OS X", "macOS"}, {"Linux", "Linux"}})
+	switch {
+	case browser != "" && system != "":
+		return browser + " · " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	default:
+		return "未知设备"
 	}
-	return res, refreshToken, refreshClaims.ExpiresAt.Unix() * 1000, nil
 }
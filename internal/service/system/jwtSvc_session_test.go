@@ -0,0 +1,178 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository"
+	repositoryadapter "personal_assistant/internal/repository/adapter"
+	reposystem "personal_assistant/internal/repository/system"
+	bizerrors "personal_assistant/pkg/errors"
+
+	"github.com/glebarez/sqlite"
+	"github.com/gofrs/uuid"
+	"github.com/songzhibin97/gkit/cache/local_cache"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestRefreshTokensRotateAndDetectReuse(t *testing.T) {
+	env := newJWTSessionTestEnv(t)
+	ctx := context.Background()
+
+	login, refreshToken, _, jwtErr := env.svc.IssueLoginTokens(ctx, *env.user, request.LoginDevice{
+		IP:        "10.0.0.1",
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 Chrome/126.0 Safari/537.36",
+	})
+	if jwtErr != nil {
+		t.Fatalf("IssueLoginTokens() error = %v", jwtErr)
+	}
+	if login.AccessToken == "" || refreshToken == "" {
+		t.Fatal("expected tokens to be issued")
+	}
+	var session entity.UserSession
+	if err := env.db.Where("user_id = ?", env.user.ID).First(&session).Error; err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	if session.DeviceLabel != "Chrome · Windows" || session.IP != "10.0.0.1" {
+		t.Fatalf("unexpected session device: %+v", session)
+	}
+
+	rotated, _, jwtErr := env.svc.RefreshTokens(ctx, refreshToken, request.LoginDevice{IP: "10.0.0.2"})
+	if jwtErr != nil {
+		t.Fatalf("RefreshTokens() error = %v", jwtErr)
+	}
+	if rotated.RefreshToken == "" || rotated.RefreshToken == refreshToken {
+		t.Fatal("expected refresh token to be rotated")
+	}
+
+	// 旧刷新令牌被重放：整个会话下线，新令牌也随之失效
+	if _, _, jwtErr := env.svc.RefreshTokens(ctx, refreshToken, request.LoginDevice{}); jwtErr == nil || jwtErr.Code != bizerrors.CodeTokenBlacklisted {
+		t.Fatalf("expected reused token to be rejected, got %v", jwtErr)
+	}
+	if _, _, jwtErr := env.svc.RefreshTokens(ctx, rotated.RefreshToken, request.LoginDevice{}); jwtErr == nil || jwtErr.Code != bizerrors.CodeTokenBlacklisted {
+		t.Fatalf("expected session family to be revoked, got %v", jwtErr)
+	}
+	if err := env.db.Where("session_id = ?", session.SessionID).First(&session).Error; err != nil {
+		t.Fatalf("reload session: %v", err)
+	}
+	if session.RevokedAt == nil || session.RevokeReason != consts.UserSessionRevokeReasonTokenReused {
+		t.Fatalf("expected session revoked for reuse, got %+v", session)
+	}
+	if !env.svc.IsInBlacklist(session.SessionID) {
+		t.Fatal("expected revoked session to be blacklisted")
+	}
+}
+
+func TestRevokeOtherUserSessionsKeepsCurrent(t *testing.T) {
+	env := newJWTSessionTestEnv(t)
+	ctx := context.Background()
+
+	var sessionIDs []string
+	for _, label := range []string{"办公电脑", "手机", "平板"} {
+		if _, _, _, jwtErr := env.svc.IssueLoginTokens(ctx, *env.user, request.LoginDevice{Label: label}); jwtErr != nil {
+			t.Fatalf("IssueLoginTokens(%s) error = %v", label, jwtErr)
+		}
+	}
+	items, err := env.svc.ListUserSessions(ctx, env.user.ID, "")
+	if err != nil || len(items) != 3 {
+		t.Fatalf("ListUserSessions() = %v, %v", items, err)
+	}
+	for _, item := range items {
+		sessionIDs = append(sessionIDs, item.SessionID)
+	}
+	current := sessionIDs[0]
+
+	// 不能下线其他用户的会话
+	if err := env.svc.RevokeUserSession(ctx, env.user.ID+1, current, consts.UserSessionRevokeReasonUserRevoke); !hasBizCode(err, bizerrors.CodeSessionNotFound) {
+		t.Fatalf("expected session not found, got %v", err)
+	}
+
+	count, err := env.svc.RevokeOtherUserSessions(ctx, env.user.ID, current)
+	if err != nil || count != 2 {
+		t.Fatalf("RevokeOtherUserSessions() = %d, %v", count, err)
+	}
+	items, err = env.svc.ListUserSessions(ctx, env.user.ID, current)
+	if err != nil || len(items) != 1 || items[0].SessionID != current || !items[0].Current {
+		t.Fatalf("unexpected remaining sessions: %+v, %v", items, err)
+	}
+	for _, sid := range sessionIDs[1:] {
+		if !env.svc.IsInBlacklist(sid) {
+			t.Fatalf("expected session %s blacklisted", sid)
+		}
+	}
+}
+
+func TestResolveSessionDeviceLabel(t *testing.T) {
+	cases := []struct {
+		label, userAgent, want string
+	}{
+		{label: " 我的电脑 ", want: "我的电脑"},
+		{userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) AppleWebKit/605.1.15 Version/17.5 Safari/605.1.15", want: "Safari · macOS"},
+		{userAgent: "Mozilla/5.0 (Windows NT 10.0) AppleWebKit/537.36 Chrome/126.0 Safari/537.36 Edg/126.0", want: "Edge · Windows"},
+		{userAgent: "Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 Chrome/126.0 Mobile Safari/537.36", want: "Chrome · Android"},
+		{userAgent: "curl/8.5.0", want: "未知设备"},
+	}
+	for _, tc := range cases {
+		if got := resolveSessionDeviceLabel(tc.label, tc.userAgent); got != tc.want {
+			t.Fatalf("resolveSessionDeviceLabel(%q, %q) = %q, want %q", tc.label, tc.userAgent, got, tc.want)
+		}
+	}
+}
+
+type jwtSessionTestEnv struct {
+	db   *gorm.DB
+	svc  *JWTService
+	user *entity.User
+}
+
+func newJWTSessionTestEnv(t *testing.T) *jwtSessionTestEnv {
+	t.Helper()
+
+	oldConfig := global.Config
+	oldLog := global.Log
+	oldBlackCache := global.BlackCache
+	global.Config = &config.Config{JWT: config.JWT{
+		AccessTokenSecret:      "test-access-secret",
+		RefreshTokenSecret:     "test-refresh-secret",
+		AccessTokenExpiryTime:  "15m",
+		RefreshTokenExpiryTime: "7d",
+		Issuer:                 "test",
+	}}
+	global.Log = zap.NewNop()
+	global.BlackCache = local_cache.NewCache()
+	t.Cleanup(func() {
+		global.Config = oldConfig
+		global.Log = oldLog
+		global.BlackCache = oldBlackCache
+	})
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&entity.Org{}, &entity.User{}, &entity.UserSession{}, &entity.JwtBlacklist{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	user := &entity.User{
+		UUID:     uuid.Must(uuid.NewV4()),
+		Username: "alice",
+		Phone:    "13800000000",
+		Status:   consts.UserStatusActive,
+	}
+	mustCreate(t, db, user)
+
+	repoGroup := &repository.Group{
+		SystemRepositorySupplier: reposystem.SetUp(&repositoryadapter.FactoryConfig{
+			DatabaseType: repositoryadapter.MySQL,
+			Connection:   db,
+		}),
+	}
+	return &jwtSessionTestEnv{db: db, svc: NewJWTService(repoGroup), user: user}
+}
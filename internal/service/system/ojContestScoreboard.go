@@ -0,0 +1,559 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"personal_assistant/global"
+	streamsse "personal_assistant/internal/infrastructure/sse"
+	"personal_assistant/internal/model/consts"
+	dtoresp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	readmodel "personal_assistant/internal/model/readmodel"
+	"personal_assistant/internal/repository/interfaces"
+	bizerrors "personal_assistant/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// ojContestPollBatchSize 限制单轮轮询处理的比赛数量，避免一次同步过多参赛者拖长任务。
+const ojContestPollBatchSize = 20
+
+// ojContestScoreboardPublisher 负责把榜单事件投递到比赛频道。
+type ojContestScoreboardPublisher interface {
+	Publish(ctx context.Context, evt *streamsse.StreamEvent) error
+}
+
+// ojContestStreamPublisher 基于全局 SSE 基础设施发布榜单：先写回放流拿到 EventID，再经背板广播到各实例。
+type ojContestStreamPublisher struct{}
+
+func newOJContestStreamPublisher() ojContestScoreboardPublisher {
+	return &ojContestStreamPublisher{}
+}
+
+func (p *ojContestStreamPublisher) Publish(ctx context.Context, evt *streamsse.StreamEvent) error {
+	infra := global.StreamInfra
+	if infra == nil || evt == nil {
+		return nil
+	}
+	if infra.ReplayStore != nil {
+		if err := infra.ReplayStore.Append(ctx, evt); err != nil {
+			return err
+		}
+	}
+	// 背板不可用时退化为只推本机连接，其他实例的订阅者等下一轮或重连回放补齐。
+	if infra.Backplane != nil {
+		err := infra.Backplane.Publish(ctx, evt)
+		if err == nil {
+			return nil
+		}
+		global.Log.Warn("publish oj contest scoreboard to backplane failed", zap.Error(err))
+	}
+	if infra.Broker != nil {
+		infra.Broker.PublishToChannel(evt.Channel, evt)
+	}
+	return nil
+}
+
+// PollContests 推进比赛状态并刷新榜单：
+//  1. 到点的 scheduled 比赛抢占为 running；
+//  2. 进行中的比赛同步参赛者过题事实后发布实时榜单；
+//  3. 已过结束时间的比赛做最后一次同步，冻结最终榜单并发布 final 事件。
+//
+// 单场比赛失败只记录日志，不影响同一轮中的其他比赛。
+func (s *OJContestService) PollContests(ctx context.Context) error {
+	contests, err := s.contestRepo.ListPollable(ctx, time.Now(), ojContestPollBatchSize)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	for _, contest := range contests {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.pollContest(ctx, contest); err != nil {
+			global.Log.Error("poll oj contest failed", zap.Uint("contest_id", contest.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (s *OJContestService) pollContest(ctx context.Context, contest *entity.OJContest) error {
+	if contest.Status == string(consts.OJContestStatusScheduled) {
+		claimed, err := s.contestRepo.UpdateStatus(
+			ctx,
+			contest.ID,
+			string(consts.OJContestStatusScheduled),
+			string(consts.OJContestStatusRunning),
+			nil,
+		)
+		if err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		if !claimed {
+			return nil
+		}
+		contest.Status = string(consts.OJContestStatusRunning)
+	}
+
+	problems, err := s.contestRepo.ListProblemsByContestID(ctx, contest.ID)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	participants, err := s.listParticipants(ctx, contest.OrgID)
+	if err != nil {
+		return err
+	}
+	if s.participantSyncer != nil && len(participants) > 0 {
+		userIDs := make([]uint, 0, len(participants))
+		for _, user := range participants {
+			userIDs = append(userIDs, user.ID)
+		}
+		if err := s.participantSyncer.SyncContestParticipants(ctx, contestPlatforms(problems), userIDs); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	if now.Before(contest.EndAt) {
+		board, err := s.computeScoreboard(ctx, contest, problems, participants, now)
+		if err != nil {
+			return err
+		}
+		return s.publishScoreboard(ctx, contest.ID, board)
+	}
+	return s.freezeContest(ctx, contest, problems, participants, now)
+}
+
+// freezeContest 以结束时间为上界计算最终榜单，并在同一事务里抢占 running -> frozen 与写入排名快照，
+// 保证多实例下快照只写一次；冻结后榜单不再随后续刷题或账号变化而改变。
+func (s *OJContestService) freezeContest(
+	ctx context.Context,
+	contest *entity.OJContest,
+	problems []*entity.OJContestProblem,
+	participants []*entity.User,
+	now time.Time,
+) error {
+	board, err := s.computeScoreboard(ctx, contest, problems, participants, contest.EndAt)
+	if err != nil {
+		return err
+	}
+	frozenAt := now
+	claimed := false
+	err = s.txRunner.InTx(ctx, func(tx any) error {
+		repo := s.contestRepo.WithTx(tx)
+		ok, err := repo.UpdateStatus(
+			ctx,
+			contest.ID,
+			string(consts.OJContestStatusRunning),
+			string(consts.OJContestStatusFrozen),
+			&frozenAt,
+		)
+		if err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		if !ok {
+			return nil
+		}
+		claimed = true
+		return s.persistStandings(ctx, repo, contest.ID, board, participants)
+	})
+	if err != nil || !claimed {
+		return err
+	}
+
+	board.Status = string(consts.OJContestStatusFrozen)
+	board.Frozen = true
+	board.GeneratedAt = formatTime(frozenAt)
+	return s.publishScoreboard(ctx, contest.ID, board)
+}
+
+func (s *OJContestService) persistStandings(
+	ctx context.Context,
+	repo interfaces.OJContestRepository,
+	contestID uint,
+	board *dtoresp.OJContestScoreboardResp,
+	participants []*entity.User,
+) error {
+	userMap := make(map[uint]*entity.User, len(participants))
+	for _, user := range participants {
+		userMap[user.ID] = user
+	}
+	standings := make([]*entity.OJContestStanding, 0, len(board.Rows))
+	for _, row := range board.Rows {
+		standing := &entity.OJContestStanding{
+			ContestID:        contestID,
+			UserID:           row.UserID,
+			UsernameSnapshot: row.Username,
+			AvatarSnapshot:   row.Avatar,
+			Rank:             row.Rank,
+			SolvedCount:      row.SolvedCount,
+			PenaltyMinutes:   row.PenaltyMinutes,
+			Score:            row.Score,
+		}
+		if user := userMap[row.UserID]; user != nil {
+			standing.UserUUIDSnapshot = user.UUID.String()
+		}
+		standings = append(standings, standing)
+	}
+	if err := repo.CreateStandings(ctx, standings); err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	items := make([]*entity.OJContestStandingItem, 0, len(board.Rows)*len(board.Problems))
+	for idx, row := range board.Rows {
+		for _, cell := range row.Items {
+			item := &entity.OJContestStandingItem{
+				ContestID:      contestID,
+				StandingID:     standings[idx].ID,
+				UserID:         row.UserID,
+				ProblemID:      cell.ProblemID,
+				Solved:         cell.Solved,
+				ElapsedMinutes: cell.ElapsedMinutes,
+				Points:         cell.Points,
+			}
+			if cell.Solved {
+				if solvedAt, err := time.Parse(time.RFC3339, cell.SolvedAt); err == nil {
+					item.SolvedAt = &solvedAt
+				}
+			}
+			items = append(items, item)
+		}
+	}
+	if err := repo.CreateStandingItems(ctx, items); err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return nil
+}
+
+func (s *OJContestService) publishScoreboard(
+	ctx context.Context,
+	contestID uint,
+	board *dtoresp.OJContestScoreboardResp,
+) error {
+	if s.scoreboardPublisher == nil {
+		return nil
+	}
+	evt, err := buildOJContestScoreboardEvent(contestID, board, true)
+	if err != nil {
+		return err
+	}
+	return s.scoreboardPublisher.Publish(ctx, evt)
+}
+
+// loadScoreboard 读取榜单：已冻结读快照，scheduled 返回空榜，running 以 now 为上界实时计算。
+func (s *OJContestService) loadScoreboard(
+	ctx context.Context,
+	contest *entity.OJContest,
+	now time.Time,
+) (*dtoresp.OJContestScoreboardResp, error) {
+	problems, err := s.contestRepo.ListProblemsByContestID(ctx, contest.ID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if contest.Status == string(consts.OJContestStatusFrozen) {
+		return s.loadFrozenScoreboard(ctx, contest, problems)
+	}
+	if now.Before(contest.StartAt) {
+		return newOJContestScoreboard(contest, problems, now), nil
+	}
+	participants, err := s.listParticipants(ctx, contest.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	if now.After(contest.EndAt) {
+		now = contest.EndAt
+	}
+	return s.computeScoreboard(ctx, contest, problems, participants, now)
+}
+
+func (s *OJContestService) loadFrozenScoreboard(
+	ctx context.Context,
+	contest *entity.OJContest,
+	problems []*entity.OJContestProblem,
+) (*dtoresp.OJContestScoreboardResp, error) {
+	standings, err := s.contestRepo.ListStandingsByContestID(ctx, contest.ID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	items, err := s.contestRepo.ListStandingItemsByContestID(ctx, contest.ID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	generatedAt := contest.EndAt
+	if contest.FrozenAt != nil {
+		generatedAt = *contest.FrozenAt
+	}
+	board := newOJContestScoreboard(contest, problems, generatedAt)
+	board.Frozen = true
+
+	labels := make(map[uint]string, len(problems))
+	for _, problem := range problems {
+		labels[problem.ID] = problem.Label
+	}
+	itemMap := make(map[uint]map[uint]*entity.OJContestStandingItem, len(standings))
+	for _, item := range items {
+		if itemMap[item.StandingID] == nil {
+			itemMap[item.StandingID] = make(map[uint]*entity.OJContestStandingItem, len(problems))
+		}
+		itemMap[item.StandingID][item.ProblemID] = item
+	}
+	for _, standing := range standings {
+		row := &dtoresp.OJContestScoreboardRowResp{
+			Rank:           standing.Rank,
+			UserID:         standing.UserID,
+			Username:       standing.UsernameSnapshot,
+			Avatar:         standing.AvatarSnapshot,
+			SolvedCount:    standing.SolvedCount,
+			PenaltyMinutes: standing.PenaltyMinutes,
+			Score:          standing.Score,
+			Items:          make([]*dtoresp.OJContestScoreboardCellResp, 0, len(problems)),
+		}
+		for _, problem := range problems {
+			cell := &dtoresp.OJContestScoreboardCellResp{ProblemID: problem.ID, Label: labels[problem.ID]}
+			if item := itemMap[standing.ID][problem.ID]; item != nil {
+				cell.Solved = item.Solved
+				cell.SolvedAt = formatTimePtr(item.SolvedAt)
+				cell.ElapsedMinutes = item.ElapsedMinutes
+				cell.Points = item.Points
+			}
+			row.Items = append(row.Items, cell)
+		}
+		board.Rows = append(board.Rows, row)
+	}
+	return board, nil
+}
+
+// computeScoreboard 以 [StartAt, until] 为时间窗，从各平台过题事实中取每道题的首次通过时间并排名。
+// ICPC：先比通过题数（降序），再比罚时（各题通过时距开赛的分钟数之和，升序）；
+// IOI：按通过题目分值之和降序。成绩相同的参赛者名次并列，行内按用户 ID 稳定排序。
+func (s *OJContestService) computeScoreboard(
+	ctx context.Context,
+	contest *entity.OJContest,
+	problems []*entity.OJContestProblem,
+	participants []*entity.User,
+	until time.Time,
+) (*dtoresp.OJContestScoreboardResp, error) {
+	board := newOJContestScoreboard(contest, problems, until)
+	if len(participants) == 0 || len(problems) == 0 {
+		return board, nil
+	}
+	userIDs := make([]uint, 0, len(participants))
+	for _, user := range participants {
+		userIDs = append(userIDs, user.ID)
+	}
+	solvedAt, err := s.loadContestSolvedAt(ctx, problems, userIDs, contest.StartAt, until)
+	if err != nil {
+		return nil, err
+	}
+
+	icpc := contest.ScoringMode == string(consts.OJContestScoringICPC)
+	for _, user := range participants {
+		row := &dtoresp.OJContestScoreboardRowResp{
+			UserID:   user.ID,
+			Username: user.Username,
+			Avatar:   user.Avatar,
+			Items:    make([]*dtoresp.OJContestScoreboardCellResp, 0, len(problems)),
+		}
+		for _, problem := range problems {
+			cell := &dtoresp.OJContestScoreboardCellResp{ProblemID: problem.ID, Label: problem.Label}
+			if at, ok := solvedAt[user.ID][problem.ID]; ok {
+				cell.Solved = true
+				cell.SolvedAt = formatTime(at)
+				cell.ElapsedMinutes = int(at.Sub(contest.StartAt) / time.Minute)
+				row.SolvedCount++
+				if icpc {
+					row.PenaltyMinutes += cell.ElapsedMinutes
+				} else {
+					cell.Points = problem.Points
+					row.Score += problem.Points
+				}
+			}
+			row.Items = append(row.Items, cell)
+		}
+		board.Rows = append(board.Rows, row)
+	}
+	rankOJContestRows(board.Rows, icpc)
+	return board, nil
+}
+
+// loadContestSolvedAt 返回 userID -> contestProblemID -> 首次通过时间。
+func (s *OJContestService) loadContestSolvedAt(
+	ctx context.Context,
+	problems []*entity.OJContestProblem,
+	userIDs []uint,
+	start, end time.Time,
+) (map[uint]map[uint]time.Time, error) {
+	byPlatform := make(map[string][]*entity.OJContestProblem)
+	for _, problem := range problems {
+		byPlatform[problem.Platform] = append(byPlatform[problem.Platform], problem)
+	}
+
+	out := make(map[uint]map[uint]time.Time, len(userIDs))
+	for platform, platformProblems := range byPlatform {
+		detailUsers, rows, err := s.loadPlatformSolvedAt(ctx, platform, platformProblems, userIDs, start, end)
+		if err != nil {
+			return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		problemIDs := make(map[uint]uint, len(platformProblems))
+		for _, problem := range platformProblems {
+			problemIDs[problem.QuestionID] = problem.ID
+		}
+		for _, row := range rows {
+			userID, ok := detailUsers[row.DetailID]
+			if !ok {
+				continue
+			}
+			problemID, ok := problemIDs[row.QuestionID]
+			if !ok {
+				continue
+			}
+			if out[userID] == nil {
+				out[userID] = make(map[uint]time.Time, len(problems))
+			}
+			if prev, exists := out[userID][problemID]; !exists || row.SolvedAt.Before(prev) {
+				out[userID][problemID] = row.SolvedAt
+			}
+		}
+	}
+	return out, nil
+}
+
+// loadPlatformSolvedAt 读取单个平台的 detailID -> userID 映射与时间窗内的过题时间。
+func (s *OJContestService) loadPlatformSolvedAt(
+	ctx context.Context,
+	platform string,
+	problems []*entity.OJContestProblem,
+	userIDs []uint,
+	start, end time.Time,
+) (map[uint]uint, []*readmodel.SolvedQuestionAt, error) {
+	questionIDs := make([]uint, 0, len(problems))
+	for _, problem := range problems {
+		questionIDs = append(questionIDs, problem.QuestionID)
+	}
+	store, ok := s.platformStores().lookup(platform)
+	if !ok {
+		return map[uint]uint{}, nil, nil
+	}
+	detailUsers, err := store.DetailUsers(ctx, userIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, err := store.ListSolvedAtInRange(ctx, uintMapKeys(detailUsers), questionIDs, start, end)
+	return detailUsers, rows, err
+}
+
+// listParticipants 返回比赛组织当前的 active 成员中账号仍处于启用状态的用户，按用户 ID 升序。
+func (s *OJContestService) listParticipants(ctx context.Context, orgID uint) ([]*entity.User, error) {
+	pairs, err := s.orgMemberRepo.ListActiveUserOrgPairsByOrgIDs(ctx, []uint{orgID})
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	userIDs := make([]uint, 0, len(pairs))
+	for _, pair := range pairs {
+		if pair != nil && pair.UserID > 0 {
+			userIDs = append(userIDs, pair.UserID)
+		}
+	}
+	userIDs = normalizeUintSlice(userIDs)
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	users, err := s.userRepo.GetByIDsActive(ctx, userIDs)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+// rankOJContestRows 按赛制排序并写入名次；成绩完全相同的参赛者共享名次，下一名次跳过并列人数。
+func rankOJContestRows(rows []*dtoresp.OJContestScoreboardRowResp, icpc bool) {
+	better := func(a, b *dtoresp.OJContestScoreboardRowResp) int {
+		if icpc {
+			if a.SolvedCount != b.SolvedCount {
+				return b.SolvedCount - a.SolvedCount
+			}
+			return a.PenaltyMinutes - b.PenaltyMinutes
+		}
+		return b.Score - a.Score
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if cmp := better(rows[i], rows[j]); cmp != 0 {
+			return cmp < 0
+		}
+		return rows[i].UserID < rows[j].UserID
+	})
+	for idx, row := range rows {
+		if idx > 0 && better(rows[idx-1], row) == 0 {
+			row.Rank = rows[idx-1].Rank
+			continue
+		}
+		row.Rank = idx + 1
+	}
+}
+
+func newOJContestScoreboard(
+	contest *entity.OJContest,
+	problems []*entity.OJContestProblem,
+	generatedAt time.Time,
+) *dtoresp.OJContestScoreboardResp {
+	return &dtoresp.OJContestScoreboardResp{
+		ContestID:   contest.ID,
+		Status:      contest.Status,
+		ScoringMode: contest.ScoringMode,
+		Frozen:      contest.Status == string(consts.OJContestStatusFrozen),
+		StartAt:     formatTime(contest.StartAt),
+		EndAt:       formatTime(contest.EndAt),
+		GeneratedAt: formatTime(generatedAt),
+		Problems:    mapOJContestProblems(problems),
+		Rows:        make([]*dtoresp.OJContestScoreboardRowResp, 0),
+	}
+}
+
+// buildOJContestScoreboardEvent 把榜单封装为比赛频道事件；durable 事件会进入回放流，供断线重连补齐。
+func buildOJContestScoreboardEvent(
+	contestID uint,
+	board *dtoresp.OJContestScoreboardResp,
+	durable bool,
+) (*streamsse.StreamEvent, error) {
+	data, err := json.Marshal(board)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+	eventName := consts.OJContestEventScoreboard
+	if board.Frozen {
+		eventName = consts.OJContestEventFinal
+	}
+	return &streamsse.StreamEvent{
+		StreamKind: streamsse.StreamKindChannel,
+		Channel:    consts.OJContestScoreboardChannel(contestID),
+		EventName:  eventName,
+		Data:       data,
+		OccurredAt: time.Now(),
+		Durable:    durable,
+	}, nil
+}
+
+// contestPlatforms 返回题单涉及的平台，保持题目顺序并去重。
+func contestPlatforms(problems []*entity.OJContestProblem) []string {
+	seen := make(map[string]struct{}, len(problems))
+	out := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		if _, ok := seen[problem.Platform]; ok {
+			continue
+		}
+		seen[problem.Platform] = struct{}{}
+		out = append(out, problem.Platform)
+	}
+	return out
+}
+
+func uintMapKeys(in map[uint]uint) []uint {
+	out := make([]uint, 0, len(in))
+	for key := range in {
+		out = append(out, key)
+	}
+	return out
+}
@@ -0,0 +1,470 @@
+package system
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"personal_assistant/global"
+	streamsse "personal_assistant/internal/infrastructure/sse"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	dtoresp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository"
+	"personal_assistant/internal/repository/interfaces"
+	svccontract "personal_assistant/internal/service/contract"
+	bizerrors "personal_assistant/pkg/errors"
+)
+
+// ojContestQuestionResolver 把比赛题单中的平台题目解析为本地题库中的已验证题目，由 OJTaskService 提供。
+type ojContestQuestionResolver interface {
+	getVerifiedCandidateByID(ctx context.Context, tx any, platform string, questionID uint) (ojTaskAnalyzeCandidate, error)
+}
+
+// ojContestParticipantSyncer 在比赛期间为参赛者拉取最新过题事实，由 OJService 提供。
+type ojContestParticipantSyncer interface {
+	SyncContestParticipants(ctx context.Context, platforms []string, userIDs []uint) error
+}
+
+// OJContestService 负责限时比赛的题单维护、可见性校验、实时榜单计算与最终榜单冻结。
+type OJContestService struct {
+	txRunner                 repository.TxRunner
+	contestRepo              interfaces.OJContestRepository
+	orgMemberRepo            interfaces.OrgMemberRepository
+	userRepo                 interfaces.UserRepository
+	luoguDetailRepo          interfaces.LuoguUserDetailRepository
+	leetcodeDetailRepo       interfaces.LeetcodeUserDetailRepository
+	lanqiaoDetailRepo        interfaces.LanqiaoUserDetailRepository
+	providerDetailRepo       interfaces.OJProviderUserDetailRepository
+	luoguUserQuestionRepo    interfaces.LuoguUserQuestionRepository
+	leetcodeUserQuestionRepo interfaces.LeetcodeUserQuestionRepository
+	lanqiaoUserQuestionRepo  interfaces.LanqiaoUserQuestionRepository
+	providerUserQuestionRepo interfaces.OJProviderUserQuestionRepository
+	questionResolver         ojContestQuestionResolver
+	participantSyncer        ojContestParticipantSyncer
+	scoreboardPublisher      ojContestScoreboardPublisher
+	authorizationService     svccontract.AuthorizationServiceContract
+}
+
+func NewOJContestService(
+	repositoryGroup *repository.Group,
+	authorizationService svccontract.AuthorizationServiceContract,
+	ojTaskService *OJTaskService,
+	ojService *OJService,
+) *OJContestService {
+	return &OJContestService{
+		txRunner:                 repositoryGroup,
+		contestRepo:              repositoryGroup.SystemRepositorySupplier.GetOJContestRepository(),
+		orgMemberRepo:            repositoryGroup.SystemRepositorySupplier.GetOrgMemberRepository(),
+		userRepo:                 repositoryGroup.SystemRepositorySupplier.GetUserRepository(),
+		luoguDetailRepo:          repositoryGroup.SystemRepositorySupplier.GetLuoguUserDetailRepository(),
+		leetcodeDetailRepo:       repositoryGroup.SystemRepositorySupplier.GetLeetcodeUserDetailRepository(),
+		lanqiaoDetailRepo:        repositoryGroup.SystemRepositorySupplier.GetLanqiaoUserDetailRepository(),
+		providerDetailRepo:       repositoryGroup.SystemRepositorySupplier.GetOJProviderUserDetailRepository(),
+		luoguUserQuestionRepo:    repositoryGroup.SystemRepositorySupplier.GetLuoguUserQuestionRepository(),
+		leetcodeUserQuestionRepo: repositoryGroup.SystemRepositorySupplier.GetLeetcodeUserQuestionRepository(),
+		lanqiaoUserQuestionRepo:  repositoryGroup.SystemRepositorySupplier.GetLanqiaoUserQuestionRepository(),
+		providerUserQuestionRepo: repositoryGroup.SystemRepositorySupplier.GetOJProviderUserQuestionRepository(),
+		questionResolver:         ojTaskService,
+		participantSyncer:        ojService,
+		scoreboardPublisher:      newOJContestStreamPublisher(),
+		authorizationService:     authorizationService,
+	}
+}
+
+func (s *OJContestService) CreateContest(
+	ctx context.Context,
+	operatorID uint,
+	req *request.CreateOJContestReq,
+) (*dtoresp.OJContestCreateResp, error) {
+	if err := s.authorizationService.AuthorizeOrgCapability(
+		ctx,
+		operatorID,
+		req.OrgID,
+		consts.CapabilityCodeOJContestManage,
+	); err != nil {
+		return nil, err
+	}
+	title, startAt, endAt, err := validateOJContestDraft(req.Title, req.ScoringMode, req.StartAt, req.EndAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	problems, err := s.resolveContestProblems(ctx, req.ScoringMode, req.Problems)
+	if err != nil {
+		return nil, err
+	}
+
+	contest := &entity.OJContest{
+		OrgID:       req.OrgID,
+		Title:       title,
+		Description: strings.TrimSpace(req.Description),
+		ScoringMode: req.ScoringMode,
+		StartAt:     startAt,
+		EndAt:       endAt,
+		Status:      string(consts.OJContestStatusScheduled),
+		CreatedBy:   operatorID,
+		UpdatedBy:   operatorID,
+	}
+	err = s.txRunner.InTx(ctx, func(tx any) error {
+		repo := s.contestRepo.WithTx(tx)
+		if err := repo.Create(ctx, contest); err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		for _, problem := range problems {
+			problem.ContestID = contest.ID
+		}
+		if err := repo.CreateProblems(ctx, problems); err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dtoresp.OJContestCreateResp{ContestID: contest.ID, Status: contest.Status}, nil
+}
+
+// UpdateContest 修改未开赛的比赛；题单整体替换，组织不可变更。
+func (s *OJContestService) UpdateContest(
+	ctx context.Context,
+	operatorID, contestID uint,
+	req *request.UpdateOJContestReq,
+) error {
+	contest, err := s.getEditableContest(ctx, operatorID, contestID)
+	if err != nil {
+		return err
+	}
+	title, startAt, endAt, err := validateOJContestDraft(req.Title, req.ScoringMode, req.StartAt, req.EndAt, time.Now())
+	if err != nil {
+		return err
+	}
+	problems, err := s.resolveContestProblems(ctx, req.ScoringMode, req.Problems)
+	if err != nil {
+		return err
+	}
+
+	contest.Title = title
+	contest.Description = strings.TrimSpace(req.Description)
+	contest.ScoringMode = req.ScoringMode
+	contest.StartAt = startAt
+	contest.EndAt = endAt
+	contest.UpdatedBy = operatorID
+	for _, problem := range problems {
+		problem.ContestID = contest.ID
+	}
+	return s.txRunner.InTx(ctx, func(tx any) error {
+		repo := s.contestRepo.WithTx(tx)
+		if err := repo.Update(ctx, contest); err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		if err := repo.ReplaceProblems(ctx, contest.ID, problems); err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		return nil
+	})
+}
+
+// DeleteContest 删除未开赛的比赛；已开赛的比赛需要保留榜单，不允许删除。
+func (s *OJContestService) DeleteContest(ctx context.Context, operatorID, contestID uint) error {
+	contest, err := s.getEditableContest(ctx, operatorID, contestID)
+	if err != nil {
+		return err
+	}
+	return s.txRunner.InTx(ctx, func(tx any) error {
+		if err := s.contestRepo.WithTx(tx).Delete(ctx, contest.ID); err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		return nil
+	})
+}
+
+// GetVisibleContestList 返回当前用户可见的比赛：超级管理员看全部，其他用户只看自己 active 组织的比赛。
+func (s *OJContestService) GetVisibleContestList(
+	ctx context.Context,
+	userID uint,
+	req *request.OJContestListReq,
+) ([]*dtoresp.OJContestListItemResp, int64, error) {
+	page, pageSize := req.Page, req.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	isSuperAdmin, err := s.isSuperAdmin(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var orgIDs []uint
+	if !isSuperAdmin {
+		orgIDs, err = s.orgMemberRepo.ListActiveOrgIDsByUser(ctx, userID)
+		if err != nil {
+			return nil, 0, bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+	}
+	if req.OrgID != nil {
+		if !isSuperAdmin && !slices.Contains(orgIDs, *req.OrgID) {
+			return []*dtoresp.OJContestListItemResp{}, 0, nil
+		}
+		orgIDs = []uint{*req.OrgID}
+		isSuperAdmin = false
+	}
+
+	rows, total, err := s.contestRepo.ListByOrgIDs(ctx, orgIDs, isSuperAdmin, req.Status, page, pageSize)
+	if err != nil {
+		return nil, 0, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	list := make([]*dtoresp.OJContestListItemResp, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, mapOJContestListItem(row))
+	}
+	return list, total, nil
+}
+
+func (s *OJContestService) GetContestDetail(
+	ctx context.Context,
+	userID, contestID uint,
+) (*dtoresp.OJContestDetailResp, error) {
+	contest, err := s.getVisibleContest(ctx, userID, contestID)
+	if err != nil {
+		return nil, err
+	}
+	problems, err := s.contestRepo.ListProblemsByContestID(ctx, contest.ID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return &dtoresp.OJContestDetailResp{
+		OJContestListItemResp: *mapOJContestListItem(contest),
+		Description:           contest.Description,
+		Problems:              mapOJContestProblems(problems),
+	}, nil
+}
+
+// GetScoreboard 返回比赛榜单：已冻结的比赛读取落库快照，其余情况按当前过题事实实时计算。
+func (s *OJContestService) GetScoreboard(
+	ctx context.Context,
+	userID, contestID uint,
+) (*dtoresp.OJContestScoreboardResp, error) {
+	contest, err := s.getVisibleContest(ctx, userID, contestID)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadScoreboard(ctx, contest, time.Now())
+}
+
+// StreamScoreboard 以 SSE 推送比赛榜单。
+// 首次连接先写一份当前榜单；携带 Last-Event-ID 重连时改由回放补齐，避免快照覆盖后又回放出更旧的榜单。
+// 之后挂到比赛频道上，轮询器每轮发布的榜单经背板转投到本机连接。
+func (s *OJContestService) StreamScoreboard(
+	ctx context.Context,
+	userID, contestID uint,
+	lastEventID string,
+	writer streamsse.StreamWriter,
+) error {
+	contest, err := s.getVisibleContest(ctx, userID, contestID)
+	if err != nil {
+		return err
+	}
+	infra := global.StreamInfra
+	if infra == nil || infra.Broker == nil {
+		return bizerrors.NewWithMsg(bizerrors.CodeInternalError, "SSE 未启用")
+	}
+
+	if strings.TrimSpace(lastEventID) == "" {
+		board, err := s.loadScoreboard(ctx, contest, time.Now())
+		if err != nil {
+			return err
+		}
+		evt, err := buildOJContestScoreboardEvent(contest.ID, board, false)
+		if err != nil {
+			return err
+		}
+		if err := writer.WriteEvent(ctx, evt); err != nil {
+			return err
+		}
+		// 最终榜单不会再变化，写完即可结束连接。
+		if board.Frozen {
+			return nil
+		}
+	}
+
+	handler := &streamsse.ChannelStreamHandler{
+		Broker:     infra.Broker,
+		Replay:     infra.ReplayStore,
+		Authorizer: &streamsse.AllowAllAuthorizer{},
+		Policy:     infra.Policy,
+	}
+	return handler.Serve(ctx, streamsse.ConnectRequest{
+		StreamKind:  streamsse.StreamKindChannel,
+		Channel:     consts.OJContestScoreboardChannel(contest.ID),
+		SubjectID:   uint64(userID),
+		LastEventID: lastEventID,
+	}, writer)
+}
+
+// getEditableContest 校验管理权限并确认比赛仍处于 scheduled 且尚未到开赛时间。
+func (s *OJContestService) getEditableContest(
+	ctx context.Context,
+	operatorID, contestID uint,
+) (*entity.OJContest, error) {
+	contest, err := s.contestRepo.GetByID(ctx, contestID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if contest == nil {
+		return nil, bizerrors.New(bizerrors.CodeOJContestNotFound)
+	}
+	if err := s.authorizationService.AuthorizeOrgCapability(
+		ctx,
+		operatorID,
+		contest.OrgID,
+		consts.CapabilityCodeOJContestManage,
+	); err != nil {
+		return nil, err
+	}
+	if contest.Status != string(consts.OJContestStatusScheduled) || !time.Now().Before(contest.StartAt) {
+		return nil, bizerrors.New(bizerrors.CodeOJContestNotEditable)
+	}
+	return contest, nil
+}
+
+// getVisibleContest 校验比赛可见性：超级管理员或比赛所属组织的 active 成员。
+func (s *OJContestService) getVisibleContest(
+	ctx context.Context,
+	userID, contestID uint,
+) (*entity.OJContest, error) {
+	contest, err := s.contestRepo.GetByID(ctx, contestID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if contest == nil {
+		return nil, bizerrors.New(bizerrors.CodeOJContestNotFound)
+	}
+	isSuperAdmin, err := s.isSuperAdmin(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if isSuperAdmin {
+		return contest, nil
+	}
+	active, err := s.orgMemberRepo.IsUserActiveInOrg(ctx, userID, contest.OrgID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if !active {
+		return nil, bizerrors.New(bizerrors.CodeOJContestVisibleDenied)
+	}
+	return contest, nil
+}
+
+// resolveContestProblems 把请求题单解析为已验证的本地题目，按输入顺序编号 A、B、C…；同一题目不允许重复出现。
+func (s *OJContestService) resolveContestProblems(
+	ctx context.Context,
+	scoringMode string,
+	items []request.OJContestProblemReq,
+) ([]*entity.OJContestProblem, error) {
+	if len(items) == 0 || len(items) > consts.OJContestMaxProblems {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "比赛题目数量需在 1~26 之间")
+	}
+	seen := make(map[string]struct{}, len(items))
+	problems := make([]*entity.OJContestProblem, 0, len(items))
+	for idx, item := range items {
+		platform := strings.TrimSpace(item.Platform)
+		candidate, err := s.questionResolver.getVerifiedCandidateByID(ctx, nil, platform, item.QuestionID)
+		if err != nil {
+			return nil, err
+		}
+		key := candidate.Platform + ":" + candidate.QuestionCode
+		if _, ok := seen[key]; ok {
+			return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "比赛题单中存在重复题目")
+		}
+		seen[key] = struct{}{}
+
+		points := 0
+		if scoringMode == string(consts.OJContestScoringIOI) {
+			points = item.Points
+			if points <= 0 {
+				points = consts.OJContestDefaultProblemPoints
+			}
+		}
+		problems = append(problems, &entity.OJContestProblem{
+			SortNo:        idx + 1,
+			Label:         string(rune('A' + idx)),
+			Platform:      candidate.Platform,
+			QuestionID:    candidate.QuestionID,
+			QuestionCode:  candidate.QuestionCode,
+			TitleSnapshot: candidate.Title,
+			Points:        points,
+		})
+	}
+	return problems, nil
+}
+
+func (s *OJContestService) isSuperAdmin(ctx context.Context, userID uint) (bool, error) {
+	ok, err := s.authorizationService.IsSuperAdmin(ctx, userID)
+	if err != nil {
+		return false, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return ok, nil
+}
+
+// validateOJContestDraft 校验标题、赛制与时间窗；开赛时间必须晚于当前时间，结束时间必须晚于开赛时间。
+func validateOJContestDraft(
+	title, scoringMode string,
+	startAt, endAt *time.Time,
+	now time.Time,
+) (string, time.Time, time.Time, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return "", time.Time{}, time.Time{}, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "比赛标题不能为空")
+	}
+	if !consts.IsValidOJContestScoringMode(scoringMode) {
+		return "", time.Time{}, time.Time{}, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "不支持的计分方式")
+	}
+	if startAt == nil || endAt == nil || startAt.IsZero() || endAt.IsZero() {
+		return "", time.Time{}, time.Time{}, bizerrors.New(bizerrors.CodeOJContestTimeInvalid)
+	}
+	if !startAt.After(now) {
+		return "", time.Time{}, time.Time{}, bizerrors.NewWithMsg(bizerrors.CodeOJContestTimeInvalid, "开赛时间必须晚于当前时间")
+	}
+	if !endAt.After(*startAt) {
+		return "", time.Time{}, time.Time{}, bizerrors.NewWithMsg(bizerrors.CodeOJContestTimeInvalid, "结束时间必须晚于开赛时间")
+	}
+	return title, *startAt, *endAt, nil
+}
+
+func mapOJContestListItem(contest *entity.OJContest) *dtoresp.OJContestListItemResp {
+	return &dtoresp.OJContestListItemResp{
+		ContestID:   contest.ID,
+		OrgID:       contest.OrgID,
+		Title:       contest.Title,
+		ScoringMode: contest.ScoringMode,
+		Status:      contest.Status,
+		StartAt:     formatTime(contest.StartAt),
+		EndAt:       formatTime(contest.EndAt),
+		FrozenAt:    formatTimePtr(contest.FrozenAt),
+		CreatedBy:   contest.CreatedBy,
+		CreatedAt:   formatTime(contest.CreatedAt),
+		UpdatedAt:   formatTime(contest.UpdatedAt),
+	}
+}
+
+func mapOJContestProblems(problems []*entity.OJContestProblem) []*dtoresp.OJContestProblemResp {
+	out := make([]*dtoresp.OJContestProblemResp, 0, len(problems))
+	for _, problem := range problems {
+		out = append(out, &dtoresp.OJContestProblemResp{
+			ID:           problem.ID,
+			SortNo:       problem.SortNo,
+			Label:        problem.Label,
+			Platform:     problem.Platform,
+			QuestionID:   problem.QuestionID,
+			QuestionCode: problem.QuestionCode,
+			Title:        problem.TitleSnapshot,
+			Points:       problem.Points,
+		})
+	}
+	return out
+}
@@ -0,0 +1,425 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"personal_assistant/global"
+	streamsse "personal_assistant/internal/infrastructure/sse"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	dtoresp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	reposystem "personal_assistant/internal/repository/system"
+	bizerrors "personal_assistant/pkg/errors"
+
+	"github.com/glebarez/sqlite"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type stubOJContestSyncer struct {
+	platforms []string
+	userIDs   []uint
+	calls     int
+}
+
+func (s *stubOJContestSyncer) SyncContestParticipants(_ context.Context, platforms []string, userIDs []uint) error {
+	s.calls++
+	s.platforms = platforms
+	s.userIDs = userIDs
+	return nil
+}
+
+type stubOJContestPublisher struct {
+	events []*streamsse.StreamEvent
+}
+
+func (p *stubOJContestPublisher) Publish(_ context.Context, evt *streamsse.StreamEvent) error {
+	p.events = append(p.events, evt)
+	return nil
+}
+
+type stubOJContestQuestionResolver struct {
+	candidates map[string]ojTaskAnalyzeCandidate
+}
+
+func (r *stubOJContestQuestionResolver) getVerifiedCandidateByID(
+	_ context.Context,
+	_ any,
+	platform string,
+	questionID uint,
+) (ojTaskAnalyzeCandidate, error) {
+	candidate, ok := r.candidates[fmt.Sprintf("%s:%d", platform, questionID)]
+	if !ok {
+		return ojTaskAnalyzeCandidate{}, bizerrors.New(bizerrors.CodeInvalidParams)
+	}
+	return candidate, nil
+}
+
+func TestOJContestPollFreezesICPCStandingsWithinWindow(t *testing.T) {
+	svc, db, syncer, publisher := newOJContestTestService(t)
+	ctx := context.Background()
+	startAt := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Minute)
+	endAt := time.Now().UTC().Add(-time.Minute)
+	contest := seedOJContest(t, db, string(consts.OJContestScoringICPC), string(consts.OJContestStatusRunning), startAt, endAt)
+
+	detail1 := &entity.LeetcodeUserDetail{UserSlug: "u1", UserID: 1}
+	detail2 := &entity.LeetcodeUserDetail{UserSlug: "u2", UserID: 2}
+	detail3 := &entity.LeetcodeUserDetail{UserSlug: "u3", UserID: 3}
+	mustCreate(t, db, detail1)
+	mustCreate(t, db, detail2)
+	mustCreate(t, db, detail3)
+	lanqiao1 := &entity.LanqiaoUserDetail{UserID: 1, CredentialHash: "h1"}
+	lanqiao2 := &entity.LanqiaoUserDetail{UserID: 2, CredentialHash: "h2"}
+	lanqiao3 := &entity.LanqiaoUserDetail{UserID: 3, CredentialHash: "h3"}
+	mustCreate(t, db, lanqiao1)
+	mustCreate(t, db, lanqiao2)
+	mustCreate(t, db, lanqiao3)
+
+	// 用户 1：A 10 分钟、B 30 分钟，罚时 40；用户 2：A、B 均 20 分钟，罚时 40，与用户 1 并列；
+	// 用户 3：A 在开赛前通过、B 在结束后通过，均不计入。
+	mustCreate(t, db, &entity.LeetcodeUserQuestion{
+		MODEL:                entity.MODEL{CreatedAt: startAt.Add(10 * time.Minute)},
+		LeetcodeUserDetailID: detail1.ID,
+		LeetcodeQuestionID:   100,
+	})
+	mustCreate(t, db, &entity.LeetcodeUserQuestion{
+		MODEL:                entity.MODEL{CreatedAt: startAt.Add(20 * time.Minute)},
+		LeetcodeUserDetailID: detail2.ID,
+		LeetcodeQuestionID:   100,
+	})
+	mustCreate(t, db, &entity.LeetcodeUserQuestion{
+		MODEL:                entity.MODEL{CreatedAt: startAt.Add(-time.Minute)},
+		LeetcodeUserDetailID: detail3.ID,
+		LeetcodeQuestionID:   100,
+	})
+	mustCreate(t, db, &entity.LanqiaoUserQuestion{
+		LanqiaoUserDetailID: lanqiao1.ID,
+		LanqiaoQuestionID:   200,
+		SolvedAt:            startAt.Add(30 * time.Minute),
+	})
+	mustCreate(t, db, &entity.LanqiaoUserQuestion{
+		LanqiaoUserDetailID: lanqiao2.ID,
+		LanqiaoQuestionID:   200,
+		SolvedAt:            startAt.Add(20 * time.Minute),
+	})
+	mustCreate(t, db, &entity.LanqiaoUserQuestion{
+		LanqiaoUserDetailID: lanqiao3.ID,
+		LanqiaoQuestionID:   200,
+		SolvedAt:            endAt.Add(time.Minute),
+	})
+
+	if err := svc.PollContests(ctx); err != nil {
+		t.Fatalf("PollContests() error = %v", err)
+	}
+
+	if syncer.calls != 1 || len(syncer.userIDs) != 3 {
+		t.Fatalf("syncer calls=%d userIDs=%v, want 1 call for 3 active participants", syncer.calls, syncer.userIDs)
+	}
+	var stored entity.OJContest
+	if err := db.First(&stored, contest.ID).Error; err != nil {
+		t.Fatalf("load contest: %v", err)
+	}
+	if stored.Status != string(consts.OJContestStatusFrozen) || stored.FrozenAt == nil {
+		t.Fatalf("contest status=%s frozen_at=%v, want frozen", stored.Status, stored.FrozenAt)
+	}
+
+	var standings []*entity.OJContestStanding
+	if err := db.Order("user_id ASC").Find(&standings, "contest_id = ?", contest.ID).Error; err != nil {
+		t.Fatalf("load standings: %v", err)
+	}
+	if len(standings) != 3 {
+		t.Fatalf("standings len = %d, want 3", len(standings))
+	}
+	wantRanks := map[uint][3]int{1: {1, 2, 40}, 2: {1, 2, 40}, 3: {3, 0, 0}}
+	for _, standing := range standings {
+		want := wantRanks[standing.UserID]
+		if standing.Rank != want[0] || standing.SolvedCount != want[1] || standing.PenaltyMinutes != want[2] {
+			t.Fatalf("standing user=%d got rank=%d solved=%d penalty=%d, want %v",
+				standing.UserID, standing.Rank, standing.SolvedCount, standing.PenaltyMinutes, want)
+		}
+	}
+	assertUnscopedCount(t, db, &entity.OJContestStandingItem{}, "contest_id = ?", contest.ID, 6)
+
+	if len(publisher.events) != 1 || publisher.events[0].EventName != consts.OJContestEventFinal {
+		t.Fatalf("published events = %+v, want one final event", publisher.events)
+	}
+	if publisher.events[0].Channel != consts.OJContestScoreboardChannel(contest.ID) {
+		t.Fatalf("event channel = %s", publisher.events[0].Channel)
+	}
+
+	// 冻结后的比赛不再被轮询，后续补到的过题事实也不会改变最终榜单。
+	mustCreate(t, db, &entity.LeetcodeUserQuestion{
+		MODEL:                entity.MODEL{CreatedAt: startAt.Add(5 * time.Minute)},
+		LeetcodeUserDetailID: detail3.ID,
+		LeetcodeQuestionID:   101,
+	})
+	if err := svc.PollContests(ctx); err != nil {
+		t.Fatalf("second PollContests() error = %v", err)
+	}
+	if len(publisher.events) != 1 {
+		t.Fatalf("published events after second poll = %d, want 1", len(publisher.events))
+	}
+	board, err := svc.GetScoreboard(ctx, 1, contest.ID)
+	if err != nil {
+		t.Fatalf("GetScoreboard() error = %v", err)
+	}
+	if !board.Frozen || len(board.Rows) != 3 || board.Rows[2].UserID != 3 || board.Rows[2].SolvedCount != 0 {
+		t.Fatalf("frozen board = %+v", board)
+	}
+}
+
+func TestOJContestScoreboardIOIRanksByPoints(t *testing.T) {
+	svc, db, _, publisher := newOJContestTestService(t)
+	ctx := context.Background()
+	startAt := time.Now().UTC().Add(-time.Hour)
+	endAt := time.Now().UTC().Add(time.Hour)
+	contest := seedOJContest(t, db, string(consts.OJContestScoringIOI), string(consts.OJContestStatusScheduled), startAt, endAt)
+
+	detail1 := &entity.LeetcodeUserDetail{UserSlug: "u1", UserID: 1}
+	detail2 := &entity.LanqiaoUserDetail{UserID: 2, CredentialHash: "h2"}
+	mustCreate(t, db, detail1)
+	mustCreate(t, db, detail2)
+	mustCreate(t, db, &entity.LeetcodeUserQuestion{
+		MODEL:                entity.MODEL{CreatedAt: startAt.Add(5 * time.Minute)},
+		LeetcodeUserDetailID: detail1.ID,
+		LeetcodeQuestionID:   100,
+	})
+	mustCreate(t, db, &entity.LanqiaoUserQuestion{
+		LanqiaoUserDetailID: detail2.ID,
+		LanqiaoQuestionID:   200,
+		SolvedAt:            startAt.Add(50 * time.Minute),
+	})
+
+	if err := svc.PollContests(ctx); err != nil {
+		t.Fatalf("PollContests() error = %v", err)
+	}
+	var stored entity.OJContest
+	if err := db.First(&stored, contest.ID).Error; err != nil {
+		t.Fatalf("load contest: %v", err)
+	}
+	if stored.Status != string(consts.OJContestStatusRunning) {
+		t.Fatalf("contest status = %s, want running", stored.Status)
+	}
+	if len(publisher.events) != 1 || publisher.events[0].EventName != consts.OJContestEventScoreboard {
+		t.Fatalf("published events = %+v, want one live scoreboard event", publisher.events)
+	}
+	var published dtoresp.OJContestScoreboardResp
+	if err := json.Unmarshal(publisher.events[0].Data, &published); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	if published.Frozen || len(published.Rows) != 3 {
+		t.Fatalf("published board = %+v", published)
+	}
+
+	// B 题 150 分高于 A 题 100 分，因此用户 2 排第一；用户 3 未通过任何题。
+	board, err := svc.GetScoreboard(ctx, 2, contest.ID)
+	if err != nil {
+		t.Fatalf("GetScoreboard() error = %v", err)
+	}
+	got := make([][3]int, 0, len(board.Rows))
+	for _, row := range board.Rows {
+		got = append(got, [3]int{int(row.UserID), row.Rank, row.Score})
+	}
+	want := [][3]int{{2, 1, 150}, {1, 2, 100}, {3, 3, 0}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("rows = %v, want %v", got, want)
+	}
+	assertUnscopedCount(t, db, &entity.OJContestStanding{}, "contest_id = ?", contest.ID, 0)
+}
+
+func TestOJContestAccessControl(t *testing.T) {
+	svc, db, _, _ := newOJContestTestService(t)
+	ctx := context.Background()
+	startAt := time.Now().UTC().Add(time.Hour)
+	contest := seedOJContest(
+		t,
+		db,
+		string(consts.OJContestScoringICPC),
+		string(consts.OJContestStatusScheduled),
+		startAt,
+		startAt.Add(2*time.Hour),
+	)
+
+	if _, err := svc.GetScoreboard(ctx, 9, contest.ID); !hasBizCode(err, bizerrors.CodeOJContestVisibleDenied) {
+		t.Fatalf("GetScoreboard() by outsider error = %v, want visible denied", err)
+	}
+	if _, err := svc.GetContestDetail(ctx, 3, contest.ID); err != nil {
+		t.Fatalf("GetContestDetail() by member error = %v", err)
+	}
+
+	endAt := startAt.Add(time.Hour)
+	req := &request.CreateOJContestReq{
+		OrgID:       10,
+		Title:       "周赛",
+		ScoringMode: string(consts.OJContestScoringICPC),
+		StartAt:     &startAt,
+		EndAt:       &endAt,
+		Problems:    []request.OJContestProblemReq{{Platform: consts.OJPlatformLeetcode, QuestionID: 100}},
+	}
+	if _, err := svc.CreateContest(ctx, 2, req); !hasBizCode(err, bizerrors.CodePermissionDenied) {
+		t.Fatalf("CreateContest() by non-manager error = %v, want permission denied", err)
+	}
+	resp, err := svc.CreateContest(ctx, 1, req)
+	if err != nil {
+		t.Fatalf("CreateContest() by manager error = %v", err)
+	}
+	assertUnscopedCount(t, db, &entity.OJContestProblem{}, "contest_id = ?", resp.ContestID, 1)
+
+	running := seedOJContest(
+		t,
+		db,
+		string(consts.OJContestScoringICPC),
+		string(consts.OJContestStatusRunning),
+		time.Now().UTC().Add(-time.Minute),
+		startAt,
+	)
+	if err := svc.DeleteContest(ctx, 1, running.ID); !hasBizCode(err, bizerrors.CodeOJContestNotEditable) {
+		t.Fatalf("DeleteContest() on running contest error = %v, want not editable", err)
+	}
+}
+
+func TestValidateOJContestDraftRejectsInvalidWindow(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	start := now.Add(time.Hour)
+	end := start.Add(-time.Minute)
+
+	cases := []struct {
+		name    string
+		startAt *time.Time
+		endAt   *time.Time
+	}{
+		{name: "missing", startAt: nil, endAt: &start},
+		{name: "start in past", startAt: &past, endAt: &start},
+		{name: "end before start", startAt: &start, endAt: &end},
+	}
+	for _, tc := range cases {
+		_, _, _, err := validateOJContestDraft("周赛", string(consts.OJContestScoringIOI), tc.startAt, tc.endAt, now)
+		if !hasBizCode(err, bizerrors.CodeOJContestTimeInvalid) {
+			t.Fatalf("%s: error = %v, want time invalid", tc.name, err)
+		}
+	}
+	if _, _, _, err := validateOJContestDraft("周赛", "acm", &start, &start, now); !hasBizCode(err, bizerrors.CodeInvalidParams) {
+		t.Fatalf("unknown scoring mode error = %v, want invalid params", err)
+	}
+}
+
+func newOJContestTestService(
+	t *testing.T,
+) (*OJContestService, *gorm.DB, *stubOJContestSyncer, *stubOJContestPublisher) {
+	t.Helper()
+
+	oldConfig := global.Config
+	oldLog := global.Log
+	global.Config = &config.Config{}
+	global.Log = zap.NewNop()
+	t.Cleanup(func() {
+		global.Config = oldConfig
+		global.Log = oldLog
+	})
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&entity.Org{},
+		&entity.User{},
+		&entity.OrgMember{},
+		&entity.LeetcodeUserDetail{},
+		&entity.LeetcodeQuestionBank{},
+		&entity.LeetcodeUserQuestion{},
+		&entity.LuoguUserDetail{},
+		&entity.LanqiaoUserDetail{},
+		&entity.LanqiaoQuestionBank{},
+		&entity.LanqiaoUserQuestion{},
+		&entity.OJProviderUserDetail{},
+		&entity.OJContest{},
+		&entity.OJContestProblem{},
+		&entity.OJContestStanding{},
+		&entity.OJContestStandingItem{},
+	); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+
+	// 组织 10 的 active 成员为 1、2、3；用户 4 是成员但已禁用，不参与榜单。
+	for id := uint(1); id <= 4; id++ {
+		status := consts.UserStatusActive
+		if id == 4 {
+			status = consts.UserStatusDisabled
+		}
+		mustCreate(t, db, &entity.User{
+			MODEL:    entity.MODEL{ID: id},
+			UUID:     uuid.Must(uuid.NewV4()),
+			Username: fmt.Sprintf("user%d", id),
+			Phone:    fmt.Sprintf("1380000000%d", id),
+			Status:   status,
+		})
+		mustCreate(t, db, &entity.OrgMember{
+			OrgID:        10,
+			UserID:       id,
+			MemberStatus: consts.OrgMemberStatusActive,
+			JoinedAt:     time.Now(),
+		})
+	}
+
+	syncer := &stubOJContestSyncer{}
+	publisher := &stubOJContestPublisher{}
+	svc := &OJContestService{
+		txRunner:                 &stubTxRunner{},
+		contestRepo:              reposystem.NewOJContestRepository(db),
+		orgMemberRepo:            reposystem.NewOrgMemberRepository(db),
+		userRepo:                 reposystem.NewUserRepository(db),
+		luoguDetailRepo:          reposystem.NewLuoguUserDetailRepository(db),
+		leetcodeDetailRepo:       reposystem.NewLeetcodeUserDetailRepository(db),
+		lanqiaoDetailRepo:        reposystem.NewLanqiaoUserDetailRepository(db),
+		providerDetailRepo:       reposystem.NewOJProviderUserDetailRepository(db),
+		luoguUserQuestionRepo:    reposystem.NewLuoguUserQuestionRepository(db),
+		leetcodeUserQuestionRepo: reposystem.NewLeetcodeUserQuestionRepository(db),
+		lanqiaoUserQuestionRepo:  reposystem.NewLanqiaoUserQuestionRepository(db),
+		providerUserQuestionRepo: reposystem.NewOJProviderUserQuestionRepository(db),
+		questionResolver: &stubOJContestQuestionResolver{candidates: map[string]ojTaskAnalyzeCandidate{
+			"leetcode:100": {Platform: consts.OJPlatformLeetcode, QuestionID: 100, QuestionCode: "two-sum", Title: "Two Sum"},
+		}},
+		participantSyncer:   syncer,
+		scoreboardPublisher: publisher,
+		// 用户 1 以超级管理员身份拥有比赛管理权限，其余成员只有查看权限。
+		authorizationService: &fakeAIMemoryManageAuthorization{superAdmins: map[uint]bool{1: true}},
+	}
+	return svc, db, syncer, publisher
+}
+
+// seedOJContest 创建组织 10 下的比赛，题单为 A=力扣 100（100 分）、B=蓝桥 200（150 分）。
+func seedOJContest(
+	t *testing.T,
+	db *gorm.DB,
+	scoringMode, status string,
+	startAt, endAt time.Time,
+) *entity.OJContest {
+	t.Helper()
+	contest := &entity.OJContest{
+		OrgID:       10,
+		Title:       "contest",
+		ScoringMode: scoringMode,
+		StartAt:     startAt,
+		EndAt:       endAt,
+		Status:      status,
+		CreatedBy:   1,
+		UpdatedBy:   1,
+	}
+	mustCreate(t, db, contest)
+	mustCreate(t, db, &entity.OJContestProblem{
+		ContestID: contest.ID, SortNo: 1, Label: "A", Platform: consts.OJPlatformLeetcode, QuestionID: 100, Points: 100,
+	})
+	mustCreate(t, db, &entity.OJContestProblem{
+		ContestID: contest.ID, SortNo: 2, Label: "B", Platform: consts.OJPlatformLanqiao, QuestionID: 200, Points: 150,
+	})
+	return contest
+}
@@ -0,0 +1,190 @@
+package system
+
+import (
+	"context"
+	stderrors "errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/pkg/redislock"
+
+	"go.uber.org/zap"
+)
+
+const defaultOJContestSyncUserInterval = 1
+
+// SyncContestParticipants 只为比赛题单涉及的平台、且已绑定账号的参赛者执行一轮增量同步。
+// 复用各平台的单用户同步与用户级锁：锁被绑定或定时全量同步占用时直接跳过，下一轮轮询再补；
+// 单个用户失败只记录日志，不影响其他参赛者。
+func (s *OJService) SyncContestParticipants(ctx context.Context, platforms []string, userIDs []uint) error {
+	if len(platforms) == 0 || len(userIDs) == 0 {
+		return nil
+	}
+	activeUsers, err := s.buildActiveUserSet(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	activeIDs := make([]uint, 0, len(activeUsers))
+	for _, userID := range userIDs {
+		if activeUsers[userID] {
+			activeIDs = append(activeIDs, userID)
+		}
+	}
+	if len(activeIDs) == 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(time.Duration(resolveOJContestSyncUserIntervalSeconds()) * time.Second)
+	defer ticker.Stop()
+
+	for _, platform := range platforms {
+		var syncErr error
+		switch platform {
+		case consts.OJPlatformLeetcode:
+			syncErr = s.syncContestLeetcodeUsers(ctx, ticker, activeIDs)
+		case consts.OJPlatformLuogu:
+			syncErr = s.syncContestLuoguUsers(ctx, ticker, activeIDs)
+		case consts.OJPlatformLanqiao:
+			syncErr = s.syncContestLanqiaoUsers(ctx, ticker, activeIDs)
+		default:
+			provider, ok := lookupOJPlatformProvider(platform)
+			if !ok {
+				continue
+			}
+			syncErr = s.syncContestProviderUsers(ctx, ticker, provider, activeIDs)
+		}
+		if syncErr != nil {
+			return syncErr
+		}
+	}
+	return nil
+}
+
+func (s *OJService) syncContestLeetcodeUsers(ctx context.Context, ticker *time.Ticker, userIDs []uint) error {
+	details, err := s.leetcodeRepo.GetByUserIDs(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	for _, u := range details {
+		if u == nil {
+			continue
+		}
+		if err := s.waitTickerOrCancel(ctx, ticker); err != nil {
+			return err
+		}
+		identifier := strings.TrimSpace(u.UserSlug)
+		if identifier == "" {
+			identifier = strconv.FormatUint(uint64(u.UserID), 10)
+		}
+		err := redislock.WithLock(ctx, redislock.LockKeyLeetcodeSyncSingleUser(identifier), 10*time.Second, func() error {
+			return s.syncSingleLeetcodeUser(ctx, u)
+		})
+		logContestSyncError(consts.OJPlatformLeetcode, u.UserID, err)
+	}
+	return nil
+}
+
+func (s *OJService) syncContestLuoguUsers(ctx context.Context, ticker *time.Ticker, userIDs []uint) error {
+	details, err := s.luoguRepo.GetByUserIDs(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	for _, u := range details {
+		if u == nil {
+			continue
+		}
+		if err := s.waitTickerOrCancel(ctx, ticker); err != nil {
+			return err
+		}
+		err := redislock.WithLock(ctx, redislock.LockKeyLuoguSyncSingleUser(u.Identification), 10*time.Second, func() error {
+			return s.syncSingleLuoguUser(ctx, u)
+		})
+		logContestSyncError(consts.OJPlatformLuogu, u.UserID, err)
+	}
+	return nil
+}
+
+func (s *OJService) syncContestLanqiaoUsers(ctx context.Context, ticker *time.Ticker, userIDs []uint) error {
+	details, err := s.lanqiaoRepo.GetByUserIDs(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	for _, u := range details {
+		if u == nil {
+			continue
+		}
+		disabled, err := s.isLanqiaoSyncDisabled(ctx, u.UserID)
+		if err != nil {
+			return err
+		}
+		if disabled {
+			continue
+		}
+		if err := s.waitTickerOrCancel(ctx, ticker); err != nil {
+			return err
+		}
+		err = redislock.WithLock(ctx, redislock.LockKeyLanqiaoSyncSingleUser(u.UserID), 10*time.Second, func() error {
+			return s.syncSingleLanqiaoUser(ctx, u)
+		})
+		if err != nil && !stderrors.Is(err, redislock.ErrLockFailed) {
+			s.handleLanqiaoSyncFailure(ctx, u.UserID, err)
+		} else if err == nil {
+			s.clearLanqiaoFailureCounter(ctx, u.UserID)
+		}
+		logContestSyncError(consts.OJPlatformLanqiao, u.UserID, err)
+	}
+	return nil
+}
+
+func (s *OJService) syncContestProviderUsers(
+	ctx context.Context,
+	ticker *time.Ticker,
+	provider ojPlatformProvider,
+	userIDs []uint,
+) error {
+	platform := provider.Platform()
+	details, err := s.providerDetailRepo.GetByUserIDs(ctx, platform, userIDs)
+	if err != nil {
+		return err
+	}
+	for _, detail := range details {
+		if detail == nil {
+			continue
+		}
+		if err := s.waitTickerOrCancel(ctx, ticker); err != nil {
+			return err
+		}
+		err := redislock.WithLock(ctx, redislock.LockKeyOJProviderSyncSingleUser(platform, detail.UserID), ojProviderSyncUserLockTTL, func() error {
+			return s.syncSingleProviderUser(ctx, provider, detail)
+		})
+		logContestSyncError(platform, detail.UserID, err)
+	}
+	return nil
+}
+
+// logContestSyncError 统一记录比赛参赛者同步结果；锁冲突按跳过处理。
+func logContestSyncError(platform string, userID uint, err error) {
+	if err == nil {
+		return
+	}
+	if stderrors.Is(err, redislock.ErrLockFailed) {
+		global.Log.Warn("skip syncing contest participant: lock held",
+			zap.String("platform", platform),
+			zap.Uint("user_id", userID))
+		return
+	}
+	global.Log.Error("failed to sync contest participant",
+		zap.String("platform", platform),
+		zap.Uint("user_id", userID),
+		zap.Error(err))
+}
+
+func resolveOJContestSyncUserIntervalSeconds() int {
+	if global.Config != nil && global.Config.Task.OJContestSyncUserIntervalSeconds > 0 {
+		return global.Config.Task.OJContestSyncUserIntervalSeconds
+	}
+	return defaultOJContestSyncUserInterval
+}
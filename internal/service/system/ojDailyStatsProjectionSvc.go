@@ -30,6 +30,8 @@ type OJDailyStatsProjectionService struct {
 	leetcodeUserQuestionRepo interfaces.LeetcodeUserQuestionRepository
 	luoguUserQuestionRepo    interfaces.LuoguUserQuestionRepository
 	lanqiaoUserQuestionRepo  interfaces.LanqiaoUserQuestionRepository
+	providerDetailRepo       interfaces.OJProviderUserDetailRepository
+	providerUserQuestionRepo interfaces.OJProviderUserQuestionRepository
 	ojDailyStatsRepo         interfaces.OJDailyStatsRepository
 	projectionEventPublisher ojDailyStatsProjectionEventPublisher
 }
@@ -43,6 +45,8 @@ func NewOJDailyStatsProjectionService(repositoryGroup *repository.Group) *OJDail
 		leetcodeUserQuestionRepo: repositoryGroup.SystemRepositorySupplier.GetLeetcodeUserQuestionRepository(),
 		luoguUserQuestionRepo:    repositoryGroup.SystemRepositorySupplier.GetLuoguUserQuestionRepository(),
 		lanqiaoUserQuestionRepo:  repositoryGroup.SystemRepositorySupplier.GetLanqiaoUserQuestionRepository(),
+		providerDetailRepo:       repositoryGroup.SystemRepositorySupplier.GetOJProviderUserDetailRepository(),
+		providerUserQuestionRepo: repositoryGroup.SystemRepositorySupplier.GetOJProviderUserQuestionRepository(),
 		ojDailyStatsRepo:         repositoryGroup.SystemRepositorySupplier.GetOJDailyStatsRepository(),
 		projectionEventPublisher: newOJDailyStatsProjectionOutboxPublisher(
 			repositoryGroup.SystemRepositorySupplier.GetOutboxRepository(),
@@ -148,7 +152,25 @@ func (s *OJDailyStatsProjectionService) RebuildRecentWindow(
 		}
 		dateSolvedCounts, err = s.lanqiaoUserQuestionRepo.CountSolvedByDateRange(ctx, detail.ID, startDate, endDateExclusive)
 	default:
-		return errors.New("unsupported oj daily stats platform")
+		if _, ok := lookupOJPlatformProvider(platform); !ok {
+			return errors.New("unsupported oj daily stats platform")
+		}
+		detail, detailErr := s.providerDetailRepo.GetByUserPlatform(ctx, userID, platform)
+		if detailErr != nil {
+			return detailErr
+		}
+		if detail == nil {
+			return s.ojDailyStatsRepo.DeleteByUserPlatform(ctx, userID, platform)
+		}
+		passedCount, countErr := s.providerUserQuestionRepo.CountPassed(ctx, detail.ID)
+		if countErr != nil {
+			return countErr
+		}
+		currentTotal = int(passedCount)
+		if detail.LastSyncAt != nil {
+			sourceUpdatedAt = *detail.LastSyncAt
+		}
+		dateSolvedCounts, err = s.providerUserQuestionRepo.CountSolvedByDateRange(ctx, detail.ID, startDate, endDateExclusive)
 	}
 	if err != nil {
 		return err
@@ -199,7 +221,20 @@ func (s *OJDailyStatsProjectionService) RepairRecentWindow(ctx context.Context)
 	if err != nil {
 		return err
 	}
-	return s.repairPlatformUsers(ctx, "lanqiao", activeSet, lanqiaoDetails)
+	if err := s.repairPlatformUsers(ctx, "lanqiao", activeSet, lanqiaoDetails); err != nil {
+		return err
+	}
+
+	for _, platform := range ojProviderPlatforms() {
+		providerDetails, err := s.providerDetailRepo.ListByPlatform(ctx, platform)
+		if err != nil {
+			return err
+		}
+		if err := s.repairPlatformUsers(ctx, platform, activeSet, providerDetails); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // repairPlatformUsers 批量修复指定平台的用户数据，activeSet 用于过滤非活跃用户，details 是平台用户详情列表
@@ -239,6 +274,15 @@ func (s *OJDailyStatsProjectionService) repairPlatformUsers(
 				userIDs = append(userIDs, detail.UserID)
 			}
 		}
+	case []*entity.OJProviderUserDetail:
+		for _, detail := range typed {
+			if detail == nil {
+				continue
+			}
+			if _, ok := activeSet[detail.UserID]; ok {
+				userIDs = append(userIDs, detail.UserID)
+			}
+		}
 	default:
 		return errors.New("unsupported repair detail type")
 	}
@@ -272,6 +316,9 @@ func normalizeOJDailyStatsPlatform(platform string) string {
 	case "lanqiao":
 		return "lanqiao"
 	default:
+		if provider, ok := lookupOJPlatformProvider(platform); ok {
+			return provider.Platform()
+		}
 		return ""
 	}
 }
@@ -0,0 +1,309 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/entity"
+	readmodel "personal_assistant/internal/model/readmodel"
+	"personal_assistant/internal/repository/interfaces"
+	bizerrors "personal_assistant/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// ojPlatformStore 封装单个平台的题库写入与过题时间查询。
+// 内置平台各自使用独立表，扩展平台共用 oj_provider_* 表，调用方只按平台标识取 store，不再逐平台分支。
+type ojPlatformStore interface {
+	// CreateManualQuestion 在平台题库中写入一条 manual 来源的已验证题目；题目编号已存在时返回 CodeOJQuestionDuplicate。
+	CreateManualQuestion(ctx context.Context, tx any, code, title, difficulty string) (ojTaskAnalyzeCandidate, error)
+	// DetailUsers 返回指定用户在该平台的账号详情 ID 到用户 ID 的映射，未绑定的用户不出现。
+	DetailUsers(ctx context.Context, userIDs []uint) (map[uint]uint, error)
+	// ListSolvedAtInRange 查询账号详情在时间窗口内对指定题目的首次通过时间。
+	ListSolvedAtInRange(ctx context.Context, detailIDs, questionIDs []uint, start, end time.Time) ([]*readmodel.SolvedQuestionAt, error)
+}
+
+// ojPlatformStoreRepos 汇总平台 store 所需的仓储，由各服务按自身持有的仓储组装；未使用的能力对应仓储可留空。
+type ojPlatformStoreRepos struct {
+	luoguQuestion        interfaces.LuoguQuestionBankRepository
+	leetcodeQuestion     interfaces.LeetcodeQuestionBankRepository
+	lanqiaoQuestion      interfaces.LanqiaoQuestionBankRepository
+	providerQuestion     interfaces.OJProviderQuestionBankRepository
+	luoguDetail          interfaces.LuoguUserDetailRepository
+	leetcodeDetail       interfaces.LeetcodeUserDetailRepository
+	lanqiaoDetail        interfaces.LanqiaoUserDetailRepository
+	providerDetail       interfaces.OJProviderUserDetailRepository
+	luoguUserQuestion    interfaces.LuoguUserQuestionRepository
+	leetcodeUserQuestion interfaces.LeetcodeUserQuestionRepository
+	lanqiaoUserQuestion  interfaces.LanqiaoUserQuestionRepository
+	providerUserQuestion interfaces.OJProviderUserQuestionRepository
+}
+
+// ojBuiltinPlatformStores 是内置平台的 store 构造器；扩展平台统一由 ojProviderPlatformStore 承接。
+var ojBuiltinPlatformStores = map[string]func(repos ojPlatformStoreRepos) ojPlatformStore{
+	consts.OJPlatformLuogu:    func(repos ojPlatformStoreRepos) ojPlatformStore { return ojLuoguPlatformStore{repos: repos} },
+	consts.OJPlatformLeetcode: func(repos ojPlatformStoreRepos) ojPlatformStore { return ojLeetcodePlatformStore{repos: repos} },
+	consts.OJPlatformLanqiao:  func(repos ojPlatformStoreRepos) ojPlatformStore { return ojLanqiaoPlatformStore{repos: repos} },
+}
+
+// lookup 按平台标识返回 store，内置平台优先，其次查找已注册的扩展平台 provider。
+func (repos ojPlatformStoreRepos) lookup(platform string) (ojPlatformStore, bool) {
+	if build, ok := ojBuiltinPlatformStores[platform]; ok {
+		return build(repos), true
+	}
+	provider, ok := lookupOJPlatformProvider(platform)
+	if !ok {
+		return nil, false
+	}
+	return ojProviderPlatformStore{repos: repos, provider: provider}, true
+}
+
+// detailUserMap 把账号详情列表转换为详情 ID 到用户 ID 的映射。
+func detailUserMap[T any](details []*T, ids func(*T) (uint, uint)) map[uint]uint {
+	out := make(map[uint]uint, len(details))
+	for _, detail := range details {
+		if detail == nil {
+			continue
+		}
+		if detailID, userID := ids(detail); detailID > 0 {
+			out[detailID] = userID
+		}
+	}
+	return out
+}
+
+// ensureManualQuestionAbsent 把按编号查询题目的结果转换为重复或数据库错误。
+func ensureManualQuestionAbsent(err error) error {
+	if err == nil {
+		return bizerrors.New(bizerrors.CodeOJQuestionDuplicate)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return nil
+}
+
+type ojLuoguPlatformStore struct {
+	repos ojPlatformStoreRepos
+}
+
+func (s ojLuoguPlatformStore) CreateManualQuestion(
+	ctx context.Context,
+	tx any,
+	code, title, difficulty string,
+) (ojTaskAnalyzeCandidate, error) {
+	repo := s.repos.luoguQuestion.WithTx(tx)
+	_, err := repo.GetByPID(ctx, code)
+	if err := ensureManualQuestionAbsent(err); err != nil {
+		return ojTaskAnalyzeCandidate{}, err
+	}
+	now := time.Now()
+	row := &entity.LuoguQuestionBank{
+		Pid:            code,
+		Title:          title,
+		Difficulty:     difficulty,
+		SourceStatus:   int8(consts.OJQuestionSourceStatusVerified),
+		SourceType:     string(consts.OJQuestionSourceTypeManual),
+		LastVerifiedAt: &now,
+	}
+	if err := repo.Create(ctx, row); err != nil {
+		return ojTaskAnalyzeCandidate{}, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return ojTaskAnalyzeCandidate{Platform: consts.OJPlatformLuogu, QuestionID: row.ID, QuestionCode: row.Pid, Title: row.Title}, nil
+}
+
+func (s ojLuoguPlatformStore) DetailUsers(ctx context.Context, userIDs []uint) (map[uint]uint, error) {
+	details, err := s.repos.luoguDetail.GetByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return detailUserMap(details, func(d *entity.LuoguUserDetail) (uint, uint) { return d.ID, d.UserID }), nil
+}
+
+func (s ojLuoguPlatformStore) ListSolvedAtInRange(
+	ctx context.Context,
+	detailIDs, questionIDs []uint,
+	start, end time.Time,
+) ([]*readmodel.SolvedQuestionAt, error) {
+	return s.repos.luoguUserQuestion.ListSolvedAtInRange(ctx, detailIDs, questionIDs, start, end)
+}
+
+type ojLeetcodePlatformStore struct {
+	repos ojPlatformStoreRepos
+}
+
+func (s ojLeetcodePlatformStore) CreateManualQuestion(
+	ctx context.Context,
+	tx any,
+	code, title, _ string,
+) (ojTaskAnalyzeCandidate, error) {
+	repo := s.repos.leetcodeQuestion.WithTx(tx)
+	slug := strings.ToLower(code)
+	_, err := repo.GetByTitleSlug(ctx, slug)
+	if err := ensureManualQuestionAbsent(err); err != nil {
+		return ojTaskAnalyzeCandidate{}, err
+	}
+	now := time.Now()
+	row := &entity.LeetcodeQuestionBank{
+		TitleSlug:      slug,
+		Title:          title,
+		SourceStatus:   int8(consts.OJQuestionSourceStatusVerified),
+		SourceType:     string(consts.OJQuestionSourceTypeManual),
+		LastVerifiedAt: &now,
+	}
+	if err := repo.Create(ctx, row); err != nil {
+		return ojTaskAnalyzeCandidate{}, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return ojTaskAnalyzeCandidate{Platform: consts.OJPlatformLeetcode, QuestionID: row.ID, QuestionCode: row.TitleSlug, Title: row.Title}, nil
+}
+
+func (s ojLeetcodePlatformStore) DetailUsers(ctx context.Context, userIDs []uint) (map[uint]uint, error) {
+	details, err := s.repos.leetcodeDetail.GetByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return detailUserMap(details, func(d *entity.LeetcodeUserDetail) (uint, uint) { return d.ID, d.UserID }), nil
+}
+
+func (s ojLeetcodePlatformStore) ListSolvedAtInRange(
+	ctx context.Context,
+	detailIDs, questionIDs []uint,
+	start, end time.Time,
+) ([]*readmodel.SolvedQuestionAt, error) {
+	return s.repos.leetcodeUserQuestion.ListSolvedAtInRange(ctx, detailIDs, questionIDs, start, end)
+}
+
+type ojLanqiaoPlatformStore struct {
+	repos ojPlatformStoreRepos
+}
+
+func (s ojLanqiaoPlatformStore) CreateManualQuestion(
+	ctx context.Context,
+	tx any,
+	code, title, _ string,
+) (ojTaskAnalyzeCandidate, error) {
+	problemID, err := strconv.Atoi(code)
+	if err != nil || problemID <= 0 {
+		return ojTaskAnalyzeCandidate{}, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "蓝桥题目编号必须为正整数")
+	}
+	repo := s.repos.lanqiaoQuestion.WithTx(tx)
+	_, err = repo.GetByProblemID(ctx, problemID)
+	if err := ensureManualQuestionAbsent(err); err != nil {
+		return ojTaskAnalyzeCandidate{}, err
+	}
+	now := time.Now()
+	row := &entity.LanqiaoQuestionBank{
+		ProblemID:      problemID,
+		Title:          title,
+		SourceStatus:   int8(consts.OJQuestionSourceStatusVerified),
+		SourceType:     string(consts.OJQuestionSourceTypeManual),
+		LastVerifiedAt: &now,
+	}
+	if err := repo.Create(ctx, row); err != nil {
+		return ojTaskAnalyzeCandidate{}, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return ojTaskAnalyzeCandidate{Platform: consts.OJPlatformLanqiao, QuestionID: row.ID, QuestionCode: strconv.Itoa(problemID), Title: row.Title}, nil
+}
+
+func (s ojLanqiaoPlatformStore) DetailUsers(ctx context.Context, userIDs []uint) (map[uint]uint, error) {
+	details, err := s.repos.lanqiaoDetail.GetByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return detailUserMap(details, func(d *entity.LanqiaoUserDetail) (uint, uint) { return d.ID, d.UserID }), nil
+}
+
+func (s ojLanqiaoPlatformStore) ListSolvedAtInRange(
+	ctx context.Context,
+	detailIDs, questionIDs []uint,
+	start, end time.Time,
+) ([]*readmodel.SolvedQuestionAt, error) {
+	return s.repos.lanqiaoUserQuestion.ListSolvedAtInRange(ctx, detailIDs, questionIDs, start, end)
+}
+
+// ojProviderPlatformStore 是全部扩展平台共用的 store，题号格式由 provider 解析。
+type ojProviderPlatformStore struct {
+	repos    ojPlatformStoreRepos
+	provider ojPlatformProvider
+}
+
+func (s ojProviderPlatformStore) CreateManualQuestion(
+	ctx context.Context,
+	tx any,
+	code, title, difficulty string,
+) (ojTaskAnalyzeCandidate, error) {
+	problemCode, ok := s.provider.ParseQuestionCode(code)
+	if !ok {
+		return ojTaskAnalyzeCandidate{}, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "题目编号格式不正确")
+	}
+	now := time.Now()
+	row := &entity.OJProviderQuestionBank{
+		Platform:       s.provider.Platform(),
+		ProblemCode:    problemCode,
+		Title:          title,
+		Difficulty:     difficulty,
+		SourceStatus:   int8(consts.OJQuestionSourceStatusVerified),
+		SourceType:     string(consts.OJQuestionSourceTypeManual),
+		LastVerifiedAt: &now,
+	}
+	questionID, created, err := s.repos.providerQuestion.WithTx(tx).EnsureQuestionID(ctx, row)
+	if err != nil {
+		return ojTaskAnalyzeCandidate{}, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if !created {
+		return ojTaskAnalyzeCandidate{}, bizerrors.New(bizerrors.CodeOJQuestionDuplicate)
+	}
+	return ojTaskAnalyzeCandidate{Platform: row.Platform, QuestionID: questionID, QuestionCode: row.ProblemCode, Title: row.Title}, nil
+}
+
+func (s ojProviderPlatformStore) DetailUsers(ctx context.Context, userIDs []uint) (map[uint]uint, error) {
+	details, err := s.repos.providerDetail.GetByUserIDs(ctx, s.provider.Platform(), userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return detailUserMap(details, func(d *entity.OJProviderUserDetail) (uint, uint) { return d.ID, d.UserID }), nil
+}
+
+func (s ojProviderPlatformStore) ListSolvedAtInRange(
+	ctx context.Context,
+	detailIDs, questionIDs []uint,
+	start, end time.Time,
+) ([]*readmodel.SolvedQuestionAt, error) {
+	return s.repos.providerUserQuestion.ListSolvedAtInRange(ctx, detailIDs, questionIDs, start, end)
+}
+
+// platformStores 按 OJTaskService 持有的仓储组装平台 store。
+func (s *OJTaskService) platformStores() ojPlatformStoreRepos {
+	return ojPlatformStoreRepos{
+		luoguQuestion:        s.luoguQuestionRepo,
+		leetcodeQuestion:     s.leetcodeQuestionRepo,
+		lanqiaoQuestion:      s.lanqiaoQuestionRepo,
+		providerQuestion:     s.providerQuestionRepo,
+		luoguDetail:          s.luoguDetailRepo,
+		leetcodeDetail:       s.leetcodeDetailRepo,
+		lanqiaoDetail:        s.lanqiaoDetailRepo,
+		providerDetail:       s.providerDetailRepo,
+		luoguUserQuestion:    s.luoguUserQuestionRepo,
+		leetcodeUserQuestion: s.leetcodeUserQuestionRepo,
+		lanqiaoUserQuestion:  s.lanqiaoUserQuestionRepo,
+		providerUserQuestion: s.providerUserQuestionRepo,
+	}
+}
+
+// platformStores 按 OJContestService 持有的仓储组装平台 store，比赛只用到过题时间查询。
+func (s *OJContestService) platformStores() ojPlatformStoreRepos {
+	return ojPlatformStoreRepos{
+		luoguDetail:          s.luoguDetailRepo,
+		leetcodeDetail:       s.leetcodeDetailRepo,
+		lanqiaoDetail:        s.lanqiaoDetailRepo,
+		providerDetail:       s.providerDetailRepo,
+		luoguUserQuestion:    s.luoguUserQuestionRepo,
+		leetcodeUserQuestion: s.leetcodeUserQuestionRepo,
+		lanqiaoUserQuestion:  s.lanqiaoUserQuestionRepo,
+		providerUserQuestion: s.providerUserQuestionRepo,
+	}
+}
@@ -0,0 +1,556 @@
+package system
+
+import (
+	"context"
+	stderrors "errors"
+	"sort"
+	"strings"
+	"time"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/consts"
+	eventdto "personal_assistant/internal/model/dto/event"
+	resp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository/interfaces"
+	svccontract "personal_assistant/internal/service/contract"
+	bizerrors "personal_assistant/pkg/errors"
+	"personal_assistant/pkg/redislock"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultOJProviderSyncUserInterval = 2
+	ojProviderBindLockTTL             = 60 * time.Second
+	ojProviderSyncUserLockTTL         = 60 * time.Second
+)
+
+// errOJProviderAccountNotFound 表示平台侧不存在该账号，绑定时转换为 CodeOJIdentifierInvalid。
+var errOJProviderAccountNotFound = stderrors.New("oj provider account not found")
+
+// ojPlatformProvider 描述一个可插拔的 OJ 平台，数据统一存放在 oj_provider_* 表中。
+// 绑定、资料同步、过题同步和题单解析只依赖这组能力，落库、题库写入与过题时间查询由 ojProviderPlatformStore 统一完成。
+// 新增扩展平台需要：实现 provider 并注册到 ojPlatformProviders，同时在 consts.OJProviderPlatforms 登记平台标识；
+// 请求校验、绑定限流、排行榜投影与 AI 工具枚举都从该列表派生。
+type ojPlatformProvider interface {
+	// Platform 返回平台标识，与 consts.OJPlatform* 以及 rankingcache.Platform* 保持一致。
+	Platform() string
+	// NormalizeIdentifier 负责校验并规范化用户提交的平台账号标识。
+	NormalizeIdentifier(raw string) (string, error)
+	// FetchProfile 拉取账号资料；账号不存在时返回 errOJProviderAccountNotFound。
+	FetchProfile(ctx context.Context, identifier string) (*ojProviderProfile, error)
+	// FetchSolved 拉取游标之后新增的通过题目，并返回推进后的游标；cursor 为 0 表示全量拉取。
+	FetchSolved(ctx context.Context, identifier string, cursor int64) (*ojProviderSolvedBatch, error)
+	// ParseQuestionCode 识别题单输入里的平台题号，如 Codeforces 的 "1A"、AtCoder 的 "abc300_a"。
+	ParseQuestionCode(input string) (string, bool)
+}
+
+// ojProviderProfile 是 provider 返回的账号资料快照。
+type ojProviderProfile struct {
+	Identifier string
+	RealName   string
+	Avatar     string
+	Rating     int
+}
+
+// ojProviderSolvedProblem 是 provider 返回的单道通过题目，同一 Code 只应出现一次且保留首次通过时间。
+type ojProviderSolvedProblem struct {
+	Code       string
+	Title      string
+	Difficulty string
+	SolvedAt   time.Time
+}
+
+// ojProviderSolvedBatch 是一次增量拉取的结果。
+type ojProviderSolvedBatch struct {
+	Problems []ojProviderSolvedProblem
+	Cursor   int64
+}
+
+// ojPlatformProviders 是已注册的扩展平台，键为平台标识，须与 consts.OJProviderPlatforms 一致。
+var ojPlatformProviders = map[string]ojPlatformProvider{
+	consts.OJPlatformCodeforces: newCodeforcesOJProvider(),
+	consts.OJPlatformAtcoder:    newAtcoderOJProvider(),
+}
+
+// lookupOJPlatformProvider 按平台标识查找扩展平台 provider。
+func lookupOJPlatformProvider(platform string) (ojPlatformProvider, bool) {
+	provider, ok := ojPlatformProviders[strings.ToLower(strings.TrimSpace(platform))]
+	return provider, ok && provider != nil
+}
+
+// ojProviderPlatforms 返回已注册扩展平台的标识，按字典序排列，保证全量同步顺序稳定。
+func ojProviderPlatforms() []string {
+	platforms := make([]string, 0, len(ojPlatformProviders))
+	for platform := range ojPlatformProviders {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
+
+// bindProviderAccount 绑定扩展平台账号。
+// 核心流程：
+//  1. 规范化账号标识并检查绑定冷却。
+//  2. 拉取资料与过题记录；换绑或首次绑定时从头拉取，同账号重绑则沿用游标增量拉取。
+//  3. 在单用户锁与事务内写入账号详情、题库与过题事实，换绑时先物理清空旧账号数据。
+//  4. 事务提交后发布排行榜投影与日统计投影事件。
+func (s *OJService) bindProviderAccount(
+	ctx context.Context,
+	userID uint,
+	provider ojPlatformProvider,
+	rawIdentifier string,
+) (*resp.BindOJAccountResp, error) {
+	if userID == 0 {
+		return nil, bizerrors.New(bizerrors.CodeLoginRequired)
+	}
+	platform := provider.Platform()
+	identifier, err := provider.NormalizeIdentifier(rawIdentifier)
+	if err != nil {
+		return nil, svccontract.ErrInvalidIdentifier
+	}
+
+	existing, err := s.providerDetailRepo.GetByUserPlatform(ctx, userID, platform)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if existing != nil {
+		coolDownHours := global.Config.System.BindCoolDownHours
+		if coolDownHours <= 0 {
+			coolDownHours = 24
+		}
+		if existing.LastBindAt != nil && time.Since(*existing.LastBindAt) < time.Duration(coolDownHours)*time.Hour {
+			return nil, svccontract.ErrBindCoolDown
+		}
+	}
+	resetCurve := existing == nil || !strings.EqualFold(existing.Identifier, identifier)
+
+	profile, err := provider.FetchProfile(ctx, identifier)
+	if err != nil {
+		if stderrors.Is(err, errOJProviderAccountNotFound) {
+			return nil, bizerrors.NewWithMsg(bizerrors.CodeOJIdentifierInvalid, "平台账号不存在")
+		}
+		return nil, bizerrors.Wrap(bizerrors.CodeOJSyncFailed, err)
+	}
+	if profile == nil {
+		return nil, bizerrors.New(bizerrors.CodeOJSyncFailed)
+	}
+	if strings.TrimSpace(profile.Identifier) != "" {
+		identifier = strings.TrimSpace(profile.Identifier)
+	}
+
+	var cursor int64
+	if !resetCurve {
+		cursor = existing.SyncCursor
+	}
+	batch, err := provider.FetchSolved(ctx, identifier, cursor)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeOJSyncFailed, err)
+	}
+	if batch == nil {
+		batch = &ojProviderSolvedBatch{Cursor: cursor}
+	}
+
+	if s.txRunner == nil {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInternalError, "事务执行器未初始化")
+	}
+	var saved *entity.OJProviderUserDetail
+	err = redislock.WithLock(ctx, redislock.LockKeyOJProviderSyncSingleUser(platform, userID), ojProviderBindLockTTL, func() error {
+		return s.txRunner.InTx(ctx, func(tx any) error {
+			detailRepo := s.providerDetailRepo.WithTx(tx)
+			if resetCurve && existing != nil {
+				if err := detailRepo.DeleteByUserPlatform(ctx, userID, platform); err != nil {
+					return err
+				}
+			}
+
+			now := time.Now()
+			detail := &entity.OJProviderUserDetail{
+				Platform:   platform,
+				Identifier: identifier,
+				RealName:   profile.RealName,
+				UserAvatar: profile.Avatar,
+				Rating:     profile.Rating,
+				SyncCursor: maxInt64(cursor, batch.Cursor),
+				LastBindAt: &now,
+				LastSyncAt: &now,
+				UserID:     userID,
+			}
+			var err error
+			saved, err = detailRepo.UpsertByUserPlatform(ctx, detail)
+			if err != nil {
+				return err
+			}
+
+			_, passedCount, err := s.persistProviderSolvedFacts(
+				ctx,
+				tx,
+				s.providerQuestionBankRepo.WithTx(tx),
+				s.providerUserQuestionRepo.WithTx(tx),
+				platform,
+				saved.ID,
+				batch.Problems,
+			)
+			if err != nil {
+				return err
+			}
+			saved.PassedNumber = int(passedCount)
+			return detailRepo.UpdateSyncState(ctx, saved)
+		})
+	})
+	if err != nil {
+		if stderrors.Is(err, redislock.ErrLockFailed) {
+			return nil, bizerrors.NewWithMsg(bizerrors.CodeOJSyncFailed, "账号正在同步中，请稍后重试")
+		}
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	if err := s.updateRankingCache(ctx, userID, platform, saved.PassedNumber); err != nil {
+		global.Log.Error("failed to publish oj profile projection event",
+			zap.Uint("user_id", userID),
+			zap.String("platform", platform),
+			zap.Error(err))
+	}
+	if err := s.publishOJDailyStatsProjectionEvent(ctx, userID, platform, resetCurve); err != nil {
+		global.Log.Error("failed to publish oj daily stats projection event",
+			zap.Uint("user_id", userID),
+			zap.String("platform", platform),
+			zap.Bool("reset", resetCurve),
+			zap.Error(err))
+	}
+
+	return &resp.BindOJAccountResp{
+		Platform:     platform,
+		Identifier:   saved.Identifier,
+		RealName:     saved.RealName,
+		UserAvatar:   saved.UserAvatar,
+		PassedNumber: saved.PassedNumber,
+		Rating:       saved.Rating,
+	}, nil
+}
+
+// getProviderUserStats 返回扩展平台账号统计，通过题数以过题事实表的去重数量为准。
+func (s *OJService) getProviderUserStats(
+	ctx context.Context,
+	userID uint,
+	platform string,
+) (*resp.OJStatsResp, error) {
+	detail, err := s.providerDetailRepo.GetByUserPlatform(ctx, userID, platform)
+	if err != nil {
+		return nil, err
+	}
+	if detail == nil {
+		return nil, svccontract.ErrOJAccountNotBound
+	}
+	passedCount, err := s.providerUserQuestionRepo.CountPassed(ctx, detail.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.OJStatsResp{
+		Platform:     platform,
+		Identifier:   detail.Identifier,
+		RealName:     detail.RealName,
+		UserAvatar:   detail.UserAvatar,
+		PassedNumber: int(passedCount),
+		Rating:       detail.Rating,
+	}, nil
+}
+
+// loadProviderCurveSource 加载扩展平台曲线所需的当前总数与最近同步时间。
+func (s *OJService) loadProviderCurveSource(
+	ctx context.Context,
+	userID uint,
+	platform string,
+) (int, time.Time, bool, error) {
+	detail, err := s.providerDetailRepo.GetByUserPlatform(ctx, userID, platform)
+	if err != nil {
+		return 0, time.Time{}, false, err
+	}
+	if detail == nil {
+		return 0, time.Time{}, false, nil
+	}
+	passedCount, err := s.providerUserQuestionRepo.CountPassed(ctx, detail.ID)
+	if err != nil {
+		return 0, time.Time{}, false, err
+	}
+	if detail.LastSyncAt != nil {
+		return int(passedCount), *detail.LastSyncAt, true, nil
+	}
+	return int(passedCount), detail.UpdatedAt, true, nil
+}
+
+// SyncAllProviderUsers 依次同步所有扩展平台的已绑定用户，单个平台失败不影响其他平台。
+func (s *OJService) SyncAllProviderUsers(ctx context.Context) error {
+	var firstErr error
+	for _, platform := range ojProviderPlatforms() {
+		provider, ok := lookupOJPlatformProvider(platform)
+		if !ok {
+			continue
+		}
+		lockKey := redislock.LockKeyOJProviderSyncAllUsers(platform)
+		err := redislock.WithLock(ctx, lockKey, 30*time.Second, func() error {
+			return s.syncProviderUsersWithRateLimit(ctx, provider)
+		})
+		if err != nil {
+			if stderrors.Is(err, redislock.ErrLockFailed) {
+				global.Log.Info("oj provider sync skipped: lock is held",
+					zap.String("platform", platform),
+					zap.String("lock_key", lockKey))
+				continue
+			}
+			if ctx.Err() != nil {
+				return err
+			}
+			global.Log.Error("failed to sync oj provider users", zap.String("platform", platform), zap.Error(err))
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+func (s *OJService) syncProviderUsersWithRateLimit(ctx context.Context, provider ojPlatformProvider) error {
+	platform := provider.Platform()
+	details, err := s.providerDetailRepo.ListByPlatform(ctx, platform)
+	if err != nil {
+		return err
+	}
+	userIDs := make([]uint, 0, len(details))
+	for _, item := range details {
+		if item != nil && item.UserID > 0 {
+			userIDs = append(userIDs, item.UserID)
+		}
+	}
+	activeUsers, err := s.buildActiveUserSet(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(time.Duration(resolveOJProviderSyncUserIntervalSeconds()) * time.Second)
+	defer ticker.Stop()
+
+	for _, detail := range details {
+		if detail == nil || !activeUsers[detail.UserID] {
+			continue
+		}
+		if err := s.waitTickerOrCancel(ctx, ticker); err != nil {
+			global.Log.Info("sync oj provider users canceled", zap.String("platform", platform), zap.Error(err))
+			return err
+		}
+
+		err := redislock.WithLock(ctx, redislock.LockKeyOJProviderSyncSingleUser(platform, detail.UserID), ojProviderSyncUserLockTTL, func() error {
+			return s.syncSingleProviderUser(ctx, provider, detail)
+		})
+		if err != nil {
+			if stderrors.Is(err, redislock.ErrLockFailed) {
+				global.Log.Warn("skip syncing oj provider user: lock held",
+					zap.String("platform", platform),
+					zap.Uint("user_id", detail.UserID))
+				continue
+			}
+			global.Log.Error("failed to sync oj provider user",
+				zap.String("platform", platform),
+				zap.Uint("user_id", detail.UserID),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// syncSingleProviderUser 从游标处增量同步单个用户，只有出现新的过题事实才发布投影事件。
+func (s *OJService) syncSingleProviderUser(
+	ctx context.Context,
+	provider ojPlatformProvider,
+	detail *entity.OJProviderUserDetail,
+) error {
+	if detail == nil || detail.ID == 0 {
+		return stderrors.New("invalid oj provider detail")
+	}
+	platform := provider.Platform()
+
+	profile, err := provider.FetchProfile(ctx, detail.Identifier)
+	if err != nil {
+		return err
+	}
+	batch, err := provider.FetchSolved(ctx, detail.Identifier, detail.SyncCursor)
+	if err != nil {
+		return err
+	}
+	if batch == nil {
+		batch = &ojProviderSolvedBatch{Cursor: detail.SyncCursor}
+	}
+
+	if s.txRunner == nil {
+		return stderrors.New("transaction runner is nil")
+	}
+	var newRecords int
+	err = s.txRunner.InTx(ctx, func(tx any) error {
+		var passedCount int64
+		var err error
+		newRecords, passedCount, err = s.persistProviderSolvedFacts(
+			ctx,
+			tx,
+			s.providerQuestionBankRepo.WithTx(tx),
+			s.providerUserQuestionRepo.WithTx(tx),
+			platform,
+			detail.ID,
+			batch.Problems,
+		)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		updated := *detail
+		if profile != nil {
+			updated.RealName = profile.RealName
+			updated.UserAvatar = profile.Avatar
+			updated.Rating = profile.Rating
+		}
+		updated.PassedNumber = int(passedCount)
+		updated.SyncCursor = maxInt64(detail.SyncCursor, batch.Cursor)
+		updated.LastSyncAt = &now
+		return s.providerDetailRepo.WithTx(tx).UpdateSyncState(ctx, &updated)
+	})
+	if err != nil {
+		return err
+	}
+
+	profileChanged := profile != nil && (profile.RealName != detail.RealName || profile.Avatar != detail.UserAvatar || profile.Rating != detail.Rating)
+	if newRecords > 0 || profileChanged {
+		if err := s.publishOJProfileProjectionEvent(ctx, detail.UserID, platform); err != nil {
+			global.Log.Error("failed to publish oj profile projection event",
+				zap.String("platform", platform),
+				zap.Uint("user_id", detail.UserID),
+				zap.Error(err))
+		}
+	}
+	if newRecords > 0 {
+		if err := s.publishOJDailyStatsProjectionEvent(ctx, detail.UserID, platform, false); err != nil {
+			global.Log.Error("failed to publish oj daily stats projection event",
+				zap.String("platform", platform),
+				zap.Uint("user_id", detail.UserID),
+				zap.Error(err))
+		}
+		global.Log.Info("synced oj provider user records",
+			zap.String("platform", platform),
+			zap.Uint("user_id", detail.UserID),
+			zap.Int("new_records", newRecords))
+	}
+	return nil
+}
+
+// persistProviderSolvedFacts 在事务内写入题库与过题事实，返回新增过题数与当前通过题总数。
+// 注意：只有题库中新建的题目才发布 QuestionUpsertedEvent，避免每次同步都触发题单重新解析。
+func (s *OJService) persistProviderSolvedFacts(
+	ctx context.Context,
+	tx any,
+	questionBankRepo interfaces.OJProviderQuestionBankRepository,
+	userQuestionRepo interfaces.OJProviderUserQuestionRepository,
+	platform string,
+	detailID uint,
+	problems []ojProviderSolvedProblem,
+) (int, int64, error) {
+	if detailID == 0 {
+		return 0, 0, stderrors.New("invalid oj provider detail id")
+	}
+
+	firstSolved := make(map[string]ojProviderSolvedProblem, len(problems))
+	for _, problem := range problems {
+		code := strings.TrimSpace(problem.Code)
+		if code == "" {
+			continue
+		}
+		problem.Code = code
+		existing, ok := firstSolved[code]
+		if !ok || (!problem.SolvedAt.IsZero() && (existing.SolvedAt.IsZero() || problem.SolvedAt.Before(existing.SolvedAt))) {
+			firstSolved[code] = problem
+		}
+	}
+	if len(firstSolved) == 0 {
+		count, err := userQuestionRepo.CountPassed(ctx, detailID)
+		return 0, count, err
+	}
+
+	existingSolved, err := userQuestionRepo.GetSolvedProblemIDs(ctx, detailID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	codes := make([]string, 0, len(firstSolved))
+	for code := range firstSolved {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	now := time.Now()
+	newRelations := make([]*entity.OJProviderUserQuestion, 0, len(codes))
+	for _, code := range codes {
+		problem := firstSolved[code]
+		title := strings.TrimSpace(problem.Title)
+		if title == "" {
+			title = code
+		}
+		question := &entity.OJProviderQuestionBank{
+			Platform:       platform,
+			ProblemCode:    code,
+			Title:          title,
+			Difficulty:     strings.TrimSpace(problem.Difficulty),
+			SourceStatus:   int8(consts.OJQuestionSourceStatusVerified),
+			SourceType:     string(consts.OJQuestionSourceTypeSync),
+			LastVerifiedAt: &now,
+		}
+		questionID, created, err := questionBankRepo.EnsureQuestionID(ctx, question)
+		if err != nil {
+			return 0, 0, err
+		}
+		if created && s.questionUpsertPublisher != nil {
+			if err := s.questionUpsertPublisher.PublishInTx(ctx, tx, &eventdto.QuestionUpsertedEvent{
+				Platform:     platform,
+				QuestionID:   questionID,
+				QuestionCode: code,
+				Title:        title,
+			}); err != nil {
+				return 0, 0, err
+			}
+		}
+		if _, ok := existingSolved[questionID]; ok {
+			continue
+		}
+		solvedAt := problem.SolvedAt
+		if solvedAt.IsZero() {
+			solvedAt = now
+		}
+		newRelations = append(newRelations, &entity.OJProviderUserQuestion{
+			DetailID:   detailID,
+			QuestionID: questionID,
+			SolvedAt:   solvedAt,
+		})
+		existingSolved[questionID] = struct{}{}
+	}
+
+	if err := userQuestionRepo.BatchCreate(ctx, newRelations); err != nil {
+		return 0, 0, err
+	}
+	passedCount, err := userQuestionRepo.CountPassed(ctx, detailID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return len(newRelations), passedCount, nil
+}
+
+func resolveOJProviderSyncUserIntervalSeconds() int {
+	if global.Config != nil && global.Config.Task.OJProviderSyncUserIntervalSeconds > 0 {
+		return global.Config.Task.OJProviderSyncUserIntervalSeconds
+	}
+	return defaultOJProviderSyncUserInterval
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
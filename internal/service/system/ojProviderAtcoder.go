@@ -0,0 +1,210 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"personal_assistant/internal/infrastructure"
+	ac "personal_assistant/internal/infrastructure/atcoder"
+	"personal_assistant/internal/model/consts"
+)
+
+const (
+	// atcoderSubmissionMaxPages 限制单次同步最多翻页数，超出部分留给下一轮同步继续推进游标。
+	atcoderSubmissionMaxPages = 20
+	// atcoderPageInterval 是 AtCoder Problems 要求的最小请求间隔。
+	atcoderPageInterval  = time.Second
+	atcoderProblemsTTL   = 12 * time.Hour
+	atcoderMaxUserLength = 16
+)
+
+var (
+	atcoderUserPattern         = regexp.MustCompile(`^[A-Za-z0-9_]{3,16}$`)
+	atcoderProblemIDPattern    = regexp.MustCompile(`^[a-z][a-z0-9_]*\d+[a-z0-9]*_[a-z0-9]+$`)
+	atcoderQuestionCodePattern = regexp.MustCompile(`(?i)^(abc|arc|agc|ahc)\s*(\d{3})\s*[-_ ]?\s*([a-z]|ex)$`)
+)
+
+// atcoderOJProvider 基于 AtCoder Problems（kenkoooo）公开 API 实现 ojPlatformProvider。
+// 过题同步按提交时间升序翻页，游标为最近一次已处理提交的秒级时间戳。
+type atcoderOJProvider struct {
+	client       func() *ac.Client
+	pageInterval time.Duration
+
+	mu             sync.Mutex
+	titles         map[string]string
+	titlesLoadedAt time.Time
+}
+
+func newAtcoderOJProvider() *atcoderOJProvider {
+	return &atcoderOJProvider{
+		client:       infrastructure.AtCoder,
+		pageInterval: atcoderPageInterval,
+	}
+}
+
+func (p *atcoderOJProvider) Platform() string {
+	return consts.OJPlatformAtcoder
+}
+
+func (p *atcoderOJProvider) NormalizeIdentifier(raw string) (string, error) {
+	user := strings.TrimSpace(raw)
+	if len(user) > atcoderMaxUserLength || !atcoderUserPattern.MatchString(user) {
+		return "", errors.New("invalid atcoder user id")
+	}
+	return user, nil
+}
+
+// FetchProfile 使用 ac_rank 校验账号是否存在；AtCoder Problems 不提供头像与 rating，只回填账号本身。
+func (p *atcoderOJProvider) FetchProfile(ctx context.Context, identifier string) (*ojProviderProfile, error) {
+	client := p.client()
+	if client == nil {
+		return nil, errors.New("atcoder client is not initialized")
+	}
+	if _, err := client.UserACRank(ctx, identifier); err != nil {
+		if ac.IsNotFound(err) {
+			return nil, errOJProviderAccountNotFound
+		}
+		return nil, err
+	}
+	return &ojProviderProfile{
+		Identifier: identifier,
+		RealName:   identifier,
+	}, nil
+}
+
+// FetchSolved 从游标之后按页拉取提交记录，页间遵守 AtCoder Problems 的限频要求。
+func (p *atcoderOJProvider) FetchSolved(
+	ctx context.Context,
+	identifier string,
+	cursor int64,
+) (*ojProviderSolvedBatch, error) {
+	client := p.client()
+	if client == nil {
+		return nil, errors.New("atcoder client is not initialized")
+	}
+
+	solved := make(map[string]ojProviderSolvedProblem)
+	seenSubmissions := make(map[int64]struct{})
+	newCursor := cursor
+	fromSecond := int64(0)
+	if cursor > 0 {
+		fromSecond = cursor + 1
+	}
+	for page := 0; page < atcoderSubmissionMaxPages; page++ {
+		if page > 0 {
+			if err := sleepWithContext(ctx, p.pageInterval); err != nil {
+				return nil, err
+			}
+		}
+		submissions, err := client.UserSubmissions(ctx, identifier, fromSecond)
+		if err != nil {
+			return nil, err
+		}
+		for _, submission := range submissions {
+			if _, ok := seenSubmissions[submission.ID]; ok {
+				continue
+			}
+			seenSubmissions[submission.ID] = struct{}{}
+			if submission.EpochSecond > newCursor {
+				newCursor = submission.EpochSecond
+			}
+			if submission.Result != ac.ResultAccepted {
+				continue
+			}
+			code := strings.TrimSpace(submission.ProblemID)
+			if code == "" {
+				continue
+			}
+			solvedAt := time.Unix(submission.EpochSecond, 0)
+			if existing, ok := solved[code]; ok && !solvedAt.Before(existing.SolvedAt) {
+				continue
+			}
+			solved[code] = ojProviderSolvedProblem{Code: code, SolvedAt: solvedAt}
+		}
+		if len(submissions) < ac.SubmissionPageSize {
+			break
+		}
+		// 同一秒内可能有多条提交落在页边界，下一页从最后一条的时间点（含）继续，按提交 ID 去重。
+		fromSecond = submissions[len(submissions)-1].EpochSecond
+	}
+
+	problems := make([]ojProviderSolvedProblem, 0, len(solved))
+	if len(solved) > 0 {
+		titles := p.loadProblemTitles(ctx)
+		for code, problem := range solved {
+			problem.Title = titles[code]
+			problems = append(problems, problem)
+		}
+	}
+	return &ojProviderSolvedBatch{Problems: problems, Cursor: newCursor}, nil
+}
+
+// ParseQuestionCode 识别 "abc300_a" 形式的原始题号，以及 "ABC300 A"、"abc300-ex" 这类常见写法。
+func (p *atcoderOJProvider) ParseQuestionCode(input string) (string, bool) {
+	raw := strings.ToLower(strings.TrimSpace(input))
+	if raw == "" {
+		return "", false
+	}
+	if atcoderProblemIDPattern.MatchString(raw) {
+		return raw, true
+	}
+	matches := atcoderQuestionCodePattern.FindStringSubmatch(raw)
+	if len(matches) != 4 {
+		return "", false
+	}
+	index := matches[3]
+	if index == "ex" {
+		index = "h"
+	}
+	return matches[1] + matches[2] + "_" + index, true
+}
+
+// loadProblemTitles 返回题号到标题的映射；题目列表较大，进程内缓存一段时间。
+// 拉取失败时沿用旧缓存，标题缺失的题目由落库逻辑回退为题号。
+func (p *atcoderOJProvider) loadProblemTitles(ctx context.Context) map[string]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.titles != nil && time.Since(p.titlesLoadedAt) < atcoderProblemsTTL {
+		return p.titles
+	}
+	client := p.client()
+	if client == nil {
+		return p.titles
+	}
+	items, err := client.Problems(ctx)
+	if err != nil {
+		return p.titles
+	}
+	titles := make(map[string]string, len(items))
+	for _, item := range items {
+		title := strings.TrimSpace(item.Name)
+		if title == "" {
+			title = strings.TrimSpace(item.Title)
+		}
+		if item.ID != "" && title != "" {
+			titles[item.ID] = title
+		}
+	}
+	p.titles = titles
+	p.titlesLoadedAt = time.Now()
+	return titles
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,157 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"personal_assistant/internal/infrastructure"
+	cf "personal_assistant/internal/infrastructure/codeforces"
+	"personal_assistant/internal/model/consts"
+)
+
+const (
+	codeforcesStatusPageSize = 1000
+	// codeforcesStatusMaxPages 限制单次同步最多翻页数，避免异常账号拖垮同步任务。
+	codeforcesStatusMaxPages = 50
+)
+
+var (
+	codeforcesHandlePattern       = regexp.MustCompile(`^[A-Za-z0-9_.\-]{3,24}$`)
+	codeforcesQuestionCodePattern = regexp.MustCompile(`(?i)^(?:cf)?\s*(\d{1,5})\s*([a-z][0-9]?)$`)
+)
+
+// codeforcesOJProvider 基于 Codeforces 官方 API 实现 ojPlatformProvider。
+// 过题同步使用 user.status 按提交时间倒序翻页，游标为最近一次已处理提交的秒级时间戳。
+type codeforcesOJProvider struct {
+	client func() *cf.Client
+}
+
+func newCodeforcesOJProvider() *codeforcesOJProvider {
+	return &codeforcesOJProvider{client: infrastructure.Codeforces}
+}
+
+func (p *codeforcesOJProvider) Platform() string {
+	return consts.OJPlatformCodeforces
+}
+
+func (p *codeforcesOJProvider) NormalizeIdentifier(raw string) (string, error) {
+	handle := strings.TrimSpace(raw)
+	if !codeforcesHandlePattern.MatchString(handle) {
+		return "", errors.New("invalid codeforces handle")
+	}
+	return handle, nil
+}
+
+func (p *codeforcesOJProvider) FetchProfile(ctx context.Context, identifier string) (*ojProviderProfile, error) {
+	client := p.client()
+	if client == nil {
+		return nil, errors.New("codeforces client is not initialized")
+	}
+	user, err := client.UserInfo(ctx, identifier)
+	if err != nil {
+		if cf.IsNotFound(err) {
+			return nil, errOJProviderAccountNotFound
+		}
+		return nil, err
+	}
+
+	handle := strings.TrimSpace(user.Handle)
+	if handle == "" {
+		handle = identifier
+	}
+	realName := strings.TrimSpace(strings.TrimSpace(user.FirstName) + " " + strings.TrimSpace(user.LastName))
+	if realName == "" {
+		realName = handle
+	}
+	avatar := strings.TrimSpace(user.TitlePhoto)
+	if avatar == "" {
+		avatar = strings.TrimSpace(user.Avatar)
+	}
+	return &ojProviderProfile{
+		Identifier: handle,
+		RealName:   realName,
+		Avatar:     avatar,
+		Rating:     user.Rating,
+	}, nil
+}
+
+// FetchSolved 从最新提交开始倒序翻页，遇到不晚于游标的提交即停止；
+// 同一道题只保留最早一次 OK 提交的时间作为首次通过时间。
+func (p *codeforcesOJProvider) FetchSolved(
+	ctx context.Context,
+	identifier string,
+	cursor int64,
+) (*ojProviderSolvedBatch, error) {
+	client := p.client()
+	if client == nil {
+		return nil, errors.New("codeforces client is not initialized")
+	}
+
+	solved := make(map[string]ojProviderSolvedProblem)
+	newCursor := cursor
+	from := 1
+	for page := 0; page < codeforcesStatusMaxPages; page++ {
+		submissions, err := client.UserStatus(ctx, identifier, from, codeforcesStatusPageSize)
+		if err != nil {
+			return nil, err
+		}
+		reachedCursor := false
+		for _, submission := range submissions {
+			if submission.CreationTimeSeconds <= cursor {
+				reachedCursor = true
+				break
+			}
+			if submission.CreationTimeSeconds > newCursor {
+				newCursor = submission.CreationTimeSeconds
+			}
+			if submission.Verdict != cf.VerdictOK {
+				continue
+			}
+			code := submission.Problem.Code()
+			if code == "" {
+				continue
+			}
+			solvedAt := time.Unix(submission.CreationTimeSeconds, 0)
+			if existing, ok := solved[code]; ok && !solvedAt.Before(existing.SolvedAt) {
+				continue
+			}
+			difficulty := ""
+			if submission.Problem.Rating > 0 {
+				difficulty = strconv.Itoa(submission.Problem.Rating)
+			}
+			solved[code] = ojProviderSolvedProblem{
+				Code:       code,
+				Title:      strings.TrimSpace(submission.Problem.Name),
+				Difficulty: difficulty,
+				SolvedAt:   solvedAt,
+			}
+		}
+		if reachedCursor || len(submissions) < codeforcesStatusPageSize {
+			break
+		}
+		from += codeforcesStatusPageSize
+	}
+
+	problems := make([]ojProviderSolvedProblem, 0, len(solved))
+	for _, problem := range solved {
+		problems = append(problems, problem)
+	}
+	return &ojProviderSolvedBatch{Problems: problems, Cursor: newCursor}, nil
+}
+
+// ParseQuestionCode 识别 "1A"、"CF1791C"、"cf 1791 c" 这类题号，统一规范为 "1791C"。
+func (p *codeforcesOJProvider) ParseQuestionCode(input string) (string, bool) {
+	matches := codeforcesQuestionCodePattern.FindStringSubmatch(strings.TrimSpace(input))
+	if len(matches) != 3 {
+		return "", false
+	}
+	contestID, err := strconv.Atoi(matches[1])
+	if err != nil || contestID <= 0 {
+		return "", false
+	}
+	return strconv.Itoa(contestID) + strings.ToUpper(matches[2]), true
+}
@@ -0,0 +1,431 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"personal_assistant/global"
+	cf "personal_assistant/internal/infrastructure/codeforces"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/consts"
+	eventdto "personal_assistant/internal/model/dto/event"
+	"personal_assistant/internal/model/entity"
+	reposystem "personal_assistant/internal/repository/system"
+	svccontract "personal_assistant/internal/service/contract"
+	bizerrors "personal_assistant/pkg/errors"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestBindProviderAccountPersistsSolvedFacts(t *testing.T) {
+	svc, db, cachePublisher, questionPublisher := newOJProviderTestService(t)
+	provider := &fakeOJPlatformProvider{
+		platform: consts.OJPlatformCodeforces,
+		profile:  &ojProviderProfile{Identifier: "tourist", RealName: "Gennady", Rating: 3800},
+		batch: &ojProviderSolvedBatch{
+			Cursor: 200,
+			Problems: []ojProviderSolvedProblem{
+				{Code: "1A", Title: "Theatre Square", SolvedAt: time.Unix(150, 0)},
+				{Code: "1A", Title: "Theatre Square", SolvedAt: time.Unix(100, 0)},
+				{Code: "4A", Title: "Watermelon", Difficulty: "800", SolvedAt: time.Unix(200, 0)},
+			},
+		},
+	}
+
+	out, err := svc.bindProviderAccount(context.Background(), 1, provider, " tourist ")
+	if err != nil {
+		t.Fatalf("bindProviderAccount() error = %v", err)
+	}
+	if out.Platform != consts.OJPlatformCodeforces || out.Identifier != "tourist" || out.PassedNumber != 2 || out.Rating != 3800 {
+		t.Fatalf("bindProviderAccount() = %+v", out)
+	}
+	if provider.lastCursor != 0 {
+		t.Fatalf("first bind cursor = %d, want 0", provider.lastCursor)
+	}
+
+	detail, err := svc.providerDetailRepo.GetByUserPlatform(context.Background(), 1, consts.OJPlatformCodeforces)
+	if err != nil || detail == nil {
+		t.Fatalf("GetByUserPlatform() = %v, %v", detail, err)
+	}
+	if detail.SyncCursor != 200 || detail.PassedNumber != 2 || detail.LastBindAt == nil {
+		t.Fatalf("detail = %+v, want cursor 200 and passed 2", detail)
+	}
+
+	var relation entity.OJProviderUserQuestion
+	if err := db.Joins("Question").Where("Question.problem_code = ?", "1A").First(&relation).Error; err != nil {
+		t.Fatalf("load 1A relation error = %v", err)
+	}
+	if !relation.SolvedAt.Equal(time.Unix(100, 0)) {
+		t.Fatalf("1A solved_at = %v, want earliest accepted time", relation.SolvedAt)
+	}
+	if len(questionPublisher.events) != 2 {
+		t.Fatalf("question upsert events = %d, want 2", len(questionPublisher.events))
+	}
+	if len(cachePublisher.events) != 1 {
+		t.Fatalf("cache projection events = %d, want 1", len(cachePublisher.events))
+	}
+}
+
+func TestBindProviderAccountRejectsCoolDownAndResetsOnIdentifierChange(t *testing.T) {
+	svc, db, _, _ := newOJProviderTestService(t)
+	provider := &fakeOJPlatformProvider{
+		platform: consts.OJPlatformCodeforces,
+		profile:  &ojProviderProfile{Identifier: "alice"},
+		batch: &ojProviderSolvedBatch{
+			Cursor:   100,
+			Problems: []ojProviderSolvedProblem{{Code: "1A", Title: "Theatre Square", SolvedAt: time.Unix(100, 0)}},
+		},
+	}
+	if _, err := svc.bindProviderAccount(context.Background(), 1, provider, "alice"); err != nil {
+		t.Fatalf("first bind error = %v", err)
+	}
+
+	if _, err := svc.bindProviderAccount(context.Background(), 1, provider, "bob_1"); err != svccontract.ErrBindCoolDown {
+		t.Fatalf("rebind within cooldown error = %v, want %v", err, svccontract.ErrBindCoolDown)
+	}
+
+	past := time.Now().Add(-48 * time.Hour)
+	if err := db.Model(&entity.OJProviderUserDetail{}).Where("user_id = ?", 1).Update("last_bind_at", past).Error; err != nil {
+		t.Fatalf("age last_bind_at error = %v", err)
+	}
+	provider.profile = &ojProviderProfile{Identifier: "bob_1"}
+	provider.batch = &ojProviderSolvedBatch{Cursor: 50}
+
+	out, err := svc.bindProviderAccount(context.Background(), 1, provider, "bob_1")
+	if err != nil {
+		t.Fatalf("rebind error = %v", err)
+	}
+	if out.Identifier != "bob_1" || out.PassedNumber != 0 {
+		t.Fatalf("rebind = %+v, want fresh account without old solved facts", out)
+	}
+	if provider.lastCursor != 0 {
+		t.Fatalf("rebind cursor = %d, want 0 for a new identifier", provider.lastCursor)
+	}
+	var count int64
+	if err := db.Unscoped().Model(&entity.OJProviderUserDetail{}).Where("user_id = ?", 1).Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("detail rows = %d, %v; want 1", count, err)
+	}
+}
+
+func TestBindProviderAccountMapsNotFound(t *testing.T) {
+	svc, _, _, _ := newOJProviderTestService(t)
+	provider := &fakeOJPlatformProvider{
+		platform:   consts.OJPlatformAtcoder,
+		profileErr: errOJProviderAccountNotFound,
+	}
+
+	_, err := svc.bindProviderAccount(context.Background(), 1, provider, "ghost")
+	if !hasBizCode(err, bizerrors.CodeOJIdentifierInvalid) {
+		t.Fatalf("bindProviderAccount() error = %v, want CodeOJIdentifierInvalid", err)
+	}
+}
+
+func TestSyncSingleProviderUserUsesCursor(t *testing.T) {
+	svc, _, cachePublisher, _ := newOJProviderTestService(t)
+	provider := &fakeOJPlatformProvider{
+		platform: consts.OJPlatformCodeforces,
+		profile:  &ojProviderProfile{Identifier: "alice"},
+		batch: &ojProviderSolvedBatch{
+			Cursor:   100,
+			Problems: []ojProviderSolvedProblem{{Code: "1A", Title: "Theatre Square", SolvedAt: time.Unix(100, 0)}},
+		},
+	}
+	if _, err := svc.bindProviderAccount(context.Background(), 1, provider, "alice"); err != nil {
+		t.Fatalf("bind error = %v", err)
+	}
+	detail, err := svc.providerDetailRepo.GetByUserPlatform(context.Background(), 1, consts.OJPlatformCodeforces)
+	if err != nil || detail == nil {
+		t.Fatalf("GetByUserPlatform() = %v, %v", detail, err)
+	}
+	eventsAfterBind := len(cachePublisher.events)
+
+	provider.batch = &ojProviderSolvedBatch{
+		Cursor:   300,
+		Problems: []ojProviderSolvedProblem{{Code: "4A", Title: "Watermelon", SolvedAt: time.Unix(300, 0)}},
+	}
+	if err := svc.syncSingleProviderUser(context.Background(), provider, detail); err != nil {
+		t.Fatalf("syncSingleProviderUser() error = %v", err)
+	}
+	if provider.lastCursor != 100 {
+		t.Fatalf("sync cursor = %d, want 100", provider.lastCursor)
+	}
+
+	synced, err := svc.providerDetailRepo.GetByUserPlatform(context.Background(), 1, consts.OJPlatformCodeforces)
+	if err != nil || synced == nil {
+		t.Fatalf("GetByUserPlatform() = %v, %v", synced, err)
+	}
+	if synced.SyncCursor != 300 || synced.PassedNumber != 2 {
+		t.Fatalf("synced detail = %+v, want cursor 300 and passed 2", synced)
+	}
+	if len(cachePublisher.events) != eventsAfterBind+1 {
+		t.Fatalf("cache projection events = %d, want %d", len(cachePublisher.events), eventsAfterBind+1)
+	}
+
+	// 没有新增过题时不再发布投影事件。
+	provider.batch = &ojProviderSolvedBatch{Cursor: 300}
+	if err := svc.syncSingleProviderUser(context.Background(), provider, synced); err != nil {
+		t.Fatalf("syncSingleProviderUser() error = %v", err)
+	}
+	if len(cachePublisher.events) != eventsAfterBind+1 {
+		t.Fatalf("cache projection events = %d, want unchanged", len(cachePublisher.events))
+	}
+}
+
+func TestFindProviderTitleCandidatesMatchesQuestionCode(t *testing.T) {
+	svc, db, _, _ := newOJProviderTestService(t)
+	rows := []*entity.OJProviderQuestionBank{
+		{Platform: consts.OJPlatformCodeforces, ProblemCode: "1791C", Title: "Prepend and Append", SourceStatus: 1},
+		{Platform: consts.OJPlatformAtcoder, ProblemCode: "abc300_a", Title: "N-choice question", SourceStatus: 1},
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		t.Fatalf("seed question bank error = %v", err)
+	}
+	taskSvc := &OJTaskService{providerQuestionRepo: svc.providerQuestionBankRepo}
+
+	cases := []struct {
+		platform string
+		input    string
+		wantCode string
+	}{
+		{consts.OJPlatformCodeforces, "Prepend and Append", "1791C"},
+		{consts.OJPlatformCodeforces, "CF1791C", "1791C"},
+		{consts.OJPlatformAtcoder, "ABC300 A", "abc300_a"},
+		{consts.OJPlatformAtcoder, "abc300_a", "abc300_a"},
+	}
+	for _, tc := range cases {
+		candidates, err := taskSvc.findVerifiedTitleCandidates(context.Background(), nil, tc.platform, tc.input)
+		if err != nil {
+			t.Fatalf("findVerifiedTitleCandidates(%q) error = %v", tc.input, err)
+		}
+		if len(candidates) != 1 || candidates[0].QuestionCode != tc.wantCode {
+			t.Fatalf("findVerifiedTitleCandidates(%q) = %+v, want %s", tc.input, candidates, tc.wantCode)
+		}
+	}
+
+	// question_id 属于其他扩展平台时不能被解析。
+	if _, err := taskSvc.getVerifiedCandidateByID(context.Background(), nil, consts.OJPlatformCodeforces, rows[1].ID); !hasBizCode(err, bizerrors.CodeOJTaskQuestionNotFound) {
+		t.Fatalf("getVerifiedCandidateByID() error = %v, want CodeOJTaskQuestionNotFound", err)
+	}
+}
+
+func TestResolveOJProviderTaskItemResult(t *testing.T) {
+	t.Parallel()
+
+	item := &entity.OJTaskItem{
+		MODEL:              entity.MODEL{ID: 1},
+		Platform:           consts.OJPlatformCodeforces,
+		ResolutionStatus:   string(consts.OJTaskItemResolutionStatusResolved),
+		ResolvedQuestionID: 9,
+	}
+	facts := &ojProviderExecutionFacts{
+		detailMap: map[uint]*entity.OJProviderUserDetail{
+			1: {MODEL: entity.MODEL{ID: 11}, UserID: 1},
+			2: {MODEL: entity.MODEL{ID: 12}, UserID: 2},
+		},
+		solvedMap: map[uint]map[uint]struct{}{
+			11: {9: {}},
+		},
+	}
+
+	cases := []struct {
+		userID     uint
+		wantStatus string
+		wantReason string
+	}{
+		{1, string(consts.OJTaskExecutionUserItemResultCompleted), ""},
+		{2, string(consts.OJTaskExecutionUserItemResultPending), string(consts.OJTaskExecutionUserItemReasonUnsolved)},
+		{3, string(consts.OJTaskExecutionUserItemResultPending), string(consts.OJTaskExecutionUserItemReasonAccountUnbound)},
+	}
+	for _, tc := range cases {
+		status, reason := resolveOJProviderTaskItemResult(tc.userID, item, facts)
+		if status != tc.wantStatus || reason != tc.wantReason {
+			t.Fatalf("user %d result = (%s,%s), want (%s,%s)", tc.userID, status, reason, tc.wantStatus, tc.wantReason)
+		}
+	}
+}
+
+func TestCodeforcesProviderFetchSolvedStopsAtCursor(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user.status" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"OK","result":[
+			{"id":5,"creationTimeSeconds":500,"verdict":"OK","problem":{"contestId":4,"index":"a","name":"Watermelon","rating":800}},
+			{"id":4,"creationTimeSeconds":400,"verdict":"WRONG_ANSWER","problem":{"contestId":5,"index":"B","name":"Center Alignment"}},
+			{"id":3,"creationTimeSeconds":300,"verdict":"OK","problem":{"contestId":4,"index":"A","name":"Watermelon","rating":800}},
+			{"id":2,"creationTimeSeconds":200,"verdict":"OK","problem":{"index":"A","name":"Gym Problem"}},
+			{"id":1,"creationTimeSeconds":100,"verdict":"OK","problem":{"contestId":1,"index":"A","name":"Theatre Square"}}
+		]}`))
+	}))
+	defer srv.Close()
+
+	client, err := cf.NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	provider := &codeforcesOJProvider{client: func() *cf.Client { return client }}
+
+	batch, err := provider.FetchSolved(context.Background(), "tourist", 150)
+	if err != nil {
+		t.Fatalf("FetchSolved() error = %v", err)
+	}
+	if batch.Cursor != 500 {
+		t.Fatalf("cursor = %d, want 500", batch.Cursor)
+	}
+	if len(batch.Problems) != 1 {
+		t.Fatalf("problems = %+v, want only 4A", batch.Problems)
+	}
+	got := batch.Problems[0]
+	if got.Code != "4A" || got.Difficulty != "800" || !got.SolvedAt.Equal(time.Unix(300, 0)) {
+		t.Fatalf("problem = %+v, want 4A solved at its earliest accepted time", got)
+	}
+}
+
+func TestOJProviderParseQuestionCode(t *testing.T) {
+	t.Parallel()
+
+	cfProvider := &codeforcesOJProvider{}
+	acProvider := &atcoderOJProvider{}
+	cases := []struct {
+		provider ojPlatformProvider
+		input    string
+		want     string
+		ok       bool
+	}{
+		{cfProvider, "1A", "1A", true},
+		{cfProvider, "cf 1791 c", "1791C", true},
+		{cfProvider, "1850H1", "1850H1", true},
+		{cfProvider, "Theatre Square", "", false},
+		{acProvider, "abc300_a", "abc300_a", true},
+		{acProvider, "ABC300-Ex", "abc300_h", true},
+		{acProvider, "arc150 b", "arc150_b", true},
+		{acProvider, "two sum", "", false},
+	}
+	for _, tc := range cases {
+		got, ok := tc.provider.ParseQuestionCode(tc.input)
+		if got != tc.want || ok != tc.ok {
+			t.Fatalf("%s.ParseQuestionCode(%q) = (%q,%v), want (%q,%v)", tc.provider.Platform(), tc.input, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func newOJProviderTestService(
+	t *testing.T,
+) (*OJService, *gorm.DB, *stubCacheProjectionPublisher, *stubQuestionUpsertPublisher) {
+	t.Helper()
+	setupRankingRedis(t)
+
+	oldConfig := global.Config
+	oldLog := global.Log
+	global.Config = &config.Config{System: config.System{BindCoolDownHours: 24}}
+	global.Log = zap.NewNop()
+	t.Cleanup(func() {
+		global.Config = oldConfig
+		global.Log = oldLog
+	})
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&entity.OJProviderUserDetail{},
+		&entity.OJProviderQuestionBank{},
+		&entity.OJProviderUserQuestion{},
+	); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+
+	cachePublisher := &stubCacheProjectionPublisher{}
+	questionPublisher := &stubQuestionUpsertPublisher{}
+	svc := &OJService{
+		txRunner:                 &stubTxRunner{},
+		providerDetailRepo:       reposystem.NewOJProviderUserDetailRepository(db),
+		providerQuestionBankRepo: reposystem.NewOJProviderQuestionBankRepository(db),
+		providerUserQuestionRepo: reposystem.NewOJProviderUserQuestionRepository(db),
+		cacheProjectionPublisher: cachePublisher,
+		questionUpsertPublisher:  questionPublisher,
+	}
+	return svc, db, cachePublisher, questionPublisher
+}
+
+type fakeOJPlatformProvider struct {
+	platform   string
+	profile    *ojProviderProfile
+	profileErr error
+	batch      *ojProviderSolvedBatch
+	lastCursor int64
+}
+
+func (p *fakeOJPlatformProvider) Platform() string {
+	return p.platform
+}
+
+func (p *fakeOJPlatformProvider) NormalizeIdentifier(raw string) (string, error) {
+	return strings.TrimSpace(raw), nil
+}
+
+func (p *fakeOJPlatformProvider) FetchProfile(_ context.Context, _ string) (*ojProviderProfile, error) {
+	return p.profile, p.profileErr
+}
+
+func (p *fakeOJPlatformProvider) FetchSolved(_ context.Context, _ string, cursor int64) (*ojProviderSolvedBatch, error) {
+	p.lastCursor = cursor
+	return p.batch, nil
+}
+
+func (p *fakeOJPlatformProvider) ParseQuestionCode(string) (string, bool) {
+	return "", false
+}
+
+type stubQuestionUpsertPublisher struct {
+	events []*eventdto.QuestionUpsertedEvent
+}
+
+func (p *stubQuestionUpsertPublisher) Publish(_ context.Context, event *eventdto.QuestionUpsertedEvent) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *stubQuestionUpsertPublisher) PublishInTx(_ context.Context, _ any, event *eventdto.QuestionUpsertedEvent) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestOJPlatformRegistriesStayConsistent(t *testing.T) {
+	if got, want := ojProviderPlatforms(), sortedStrings(consts.OJProviderPlatforms); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("registered providers = %v, want consts.OJProviderPlatforms %v", got, want)
+	}
+	for _, platform := range consts.OJProviderPlatforms {
+		if provider, ok := lookupOJPlatformProvider(platform); !ok || provider.Platform() != platform {
+			t.Fatalf("provider for %q missing or mismatched", platform)
+		}
+	}
+	var repos ojPlatformStoreRepos
+	for _, platform := range consts.SupportedOJPlatforms() {
+		if _, ok := repos.lookup(platform); !ok {
+			t.Fatalf("platform store for %q missing", platform)
+		}
+	}
+	if _, ok := repos.lookup("unknown"); ok {
+		t.Fatal("expected unknown platform to have no store")
+	}
+}
+
+func sortedStrings(items []string) []string {
+	out := append([]string{}, items...)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,326 @@
+package system
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	dtoresp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	readmodel "personal_assistant/internal/model/readmodel"
+	"personal_assistant/internal/repository"
+	"personal_assistant/internal/repository/interfaces"
+	svccontract "personal_assistant/internal/service/contract"
+	bizerrors "personal_assistant/pkg/errors"
+)
+
+const (
+	// ojQuestionBankMaxKeywordTerms 限制关键词拆分后的词数，避免过多 LIKE 条件拖慢联合查询。
+	ojQuestionBankMaxKeywordTerms = 5
+	ojQuestionBankDefaultPageSize = 20
+)
+
+// OJQuestionBankService 提供跨平台题库目录检索与管理员维护的自定义标签。
+type OJQuestionBankService struct {
+	txRunner             repository.TxRunner
+	questionBankRepo     interfaces.OJQuestionBankRepository
+	authorizationService svccontract.AuthorizationServiceContract
+	analysisTokenCodec   *ojTaskAnalysisTokenCodec
+}
+
+func NewOJQuestionBankService(
+	repositoryGroup *repository.Group,
+	authorizationService svccontract.AuthorizationServiceContract,
+) *OJQuestionBankService {
+	return &OJQuestionBankService{
+		txRunner:             repositoryGroup,
+		questionBankRepo:     repositoryGroup.SystemRepositorySupplier.GetOJQuestionBankRepository(),
+		authorizationService: authorizationService,
+		analysisTokenCodec:   newOJTaskAnalysisTokenCodec(),
+	}
+}
+
+// SearchQuestions 按关键词、平台、难度、来源状态与标签检索题库目录。
+// 已验证题目附带 analysis_token，创建任务时可直接引用，无需再走标题分析。
+func (s *OJQuestionBankService) SearchQuestions(
+	ctx context.Context,
+	req *request.OJQuestionBankListReq,
+) ([]*dtoresp.OJQuestionBankItemResp, int64, error) {
+	if req == nil {
+		req = &request.OJQuestionBankListReq{}
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = ojQuestionBankDefaultPageSize
+	}
+	platforms, err := resolveOJQuestionBankPlatforms(req.Platform)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	keyword := strings.TrimSpace(req.Keyword)
+	terms := strings.Fields(keyword)
+	if len(terms) > ojQuestionBankMaxKeywordTerms {
+		return nil, 0, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "关键词最多包含 5 个词")
+	}
+	rows, total, err := s.questionBankRepo.Search(ctx, &readmodel.OJQuestionBankQuery{
+		Platforms:    platforms,
+		Terms:        terms,
+		Keyword:      keyword,
+		Difficulty:   strings.TrimSpace(req.Difficulty),
+		SourceStatus: req.SourceStatus,
+		TagIDs:       uniqueOJQuestionTagIDs(req.TagIDs),
+		Offset:       (req.Page - 1) * req.PageSize,
+		Limit:        req.PageSize,
+	})
+	if err != nil {
+		return nil, 0, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if len(rows) == 0 {
+		return []*dtoresp.OJQuestionBankItemResp{}, total, nil
+	}
+
+	refs := make([]readmodel.OJQuestionRef, 0, len(rows))
+	for _, row := range rows {
+		refs = append(refs, readmodel.OJQuestionRef{Platform: row.Platform, QuestionID: row.QuestionID})
+	}
+	bindings, err := s.questionBankRepo.ListTagBindings(ctx, refs)
+	if err != nil {
+		return nil, 0, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	tagsByQuestion := make(map[readmodel.OJQuestionRef][]*dtoresp.OJQuestionTagBriefResp, len(rows))
+	for _, binding := range bindings {
+		ref := readmodel.OJQuestionRef{Platform: binding.Platform, QuestionID: binding.QuestionID}
+		tagsByQuestion[ref] = append(tagsByQuestion[ref], &dtoresp.OJQuestionTagBriefResp{
+			ID:   binding.TagID,
+			Name: binding.TagName,
+		})
+	}
+
+	list := make([]*dtoresp.OJQuestionBankItemResp, 0, len(rows))
+	for _, row := range rows {
+		item := &dtoresp.OJQuestionBankItemResp{
+			Platform:          row.Platform,
+			QuestionID:        row.QuestionID,
+			QuestionCode:      row.QuestionCode,
+			Title:             row.Title,
+			Difficulty:        row.Difficulty,
+			SourceStatus:      row.SourceStatus,
+			SourceStatusLabel: consts.OJQuestionSourceStatusLabel(row.SourceStatus),
+			SourceType:        row.SourceType,
+			LastVerifiedAt:    row.LastVerifiedAt,
+			Tags:              tagsByQuestion[readmodel.OJQuestionRef{Platform: row.Platform, QuestionID: row.QuestionID}],
+		}
+		if item.Tags == nil {
+			item.Tags = []*dtoresp.OJQuestionTagBriefResp{}
+		}
+		if row.SourceStatus == int8(consts.OJQuestionSourceStatusVerified) {
+			token, err := s.analysisTokenCodec.Encode(row.Platform, normalizeOJTaskTitle(row.Title), row.QuestionID)
+			if err != nil {
+				return nil, 0, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+			}
+			item.AnalysisToken = token
+		}
+		list = append(list, item)
+	}
+	return list, total, nil
+}
+
+// ListTags 返回全部题库标签及其关联题目数。
+func (s *OJQuestionBankService) ListTags(ctx context.Context) ([]*dtoresp.OJQuestionTagResp, error) {
+	rows, err := s.questionBankRepo.ListTags(ctx)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	list := make([]*dtoresp.OJQuestionTagResp, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, &dtoresp.OJQuestionTagResp{
+			ID:            row.ID,
+			Name:          row.Name,
+			Description:   row.Description,
+			QuestionCount: row.QuestionCount,
+		})
+	}
+	return list, nil
+}
+
+// CreateTag 创建题库标签，仅超级管理员可操作。
+func (s *OJQuestionBankService) CreateTag(
+	ctx context.Context,
+	operatorID uint,
+	req *request.CreateOJQuestionTagReq,
+) (*dtoresp.OJQuestionTagResp, error) {
+	if err := s.requireSuperAdmin(ctx, operatorID); err != nil {
+		return nil, err
+	}
+	name, err := s.validateTagName(ctx, req.Name, 0)
+	if err != nil {
+		return nil, err
+	}
+	tag := &entity.OJQuestionTag{
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		CreatedBy:   operatorID,
+		UpdatedBy:   operatorID,
+	}
+	if err := s.questionBankRepo.CreateTag(ctx, tag); err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return &dtoresp.OJQuestionTagResp{ID: tag.ID, Name: tag.Name, Description: tag.Description}, nil
+}
+
+// UpdateTag 修改标签名称与说明，仅超级管理员可操作。
+func (s *OJQuestionBankService) UpdateTag(
+	ctx context.Context,
+	operatorID, tagID uint,
+	req *request.UpdateOJQuestionTagReq,
+) error {
+	if err := s.requireSuperAdmin(ctx, operatorID); err != nil {
+		return err
+	}
+	tag, err := s.getTag(ctx, tagID)
+	if err != nil {
+		return err
+	}
+	name, err := s.validateTagName(ctx, req.Name, tag.ID)
+	if err != nil {
+		return err
+	}
+	tag.Name = name
+	tag.Description = strings.TrimSpace(req.Description)
+	tag.UpdatedBy = operatorID
+	if err := s.questionBankRepo.UpdateTag(ctx, tag); err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return nil
+}
+
+// DeleteTag 删除标签并解除它与所有题目的关联，仅超级管理员可操作。
+func (s *OJQuestionBankService) DeleteTag(ctx context.Context, operatorID, tagID uint) error {
+	if err := s.requireSuperAdmin(ctx, operatorID); err != nil {
+		return err
+	}
+	tag, err := s.getTag(ctx, tagID)
+	if err != nil {
+		return err
+	}
+	return s.txRunner.InTx(ctx, func(tx any) error {
+		if err := s.questionBankRepo.WithTx(tx).DeleteTag(ctx, tag.ID); err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		return nil
+	})
+}
+
+// SetQuestionTags 用给定标签整体替换一道题的标签，仅超级管理员可操作。
+func (s *OJQuestionBankService) SetQuestionTags(
+	ctx context.Context,
+	operatorID uint,
+	req *request.SetOJQuestionTagsReq,
+) error {
+	if err := s.requireSuperAdmin(ctx, operatorID); err != nil {
+		return err
+	}
+	platform := strings.ToLower(strings.TrimSpace(req.Platform))
+	if !isOJQuestionBankPlatform(platform) {
+		return bizerrors.New(bizerrors.CodeOJPlatformInvalid)
+	}
+	exists, err := s.questionBankRepo.QuestionExists(ctx, platform, req.QuestionID)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if !exists {
+		return bizerrors.New(bizerrors.CodeOJQuestionNotFound)
+	}
+	tagIDs := uniqueOJQuestionTagIDs(req.TagIDs)
+	if len(tagIDs) > 0 {
+		count, err := s.questionBankRepo.CountTagsByIDs(ctx, tagIDs)
+		if err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		if count != int64(len(tagIDs)) {
+			return bizerrors.New(bizerrors.CodeOJQuestionTagNotFound)
+		}
+	}
+	return s.txRunner.InTx(ctx, func(tx any) error {
+		if err := s.questionBankRepo.WithTx(tx).ReplaceQuestionTags(ctx, platform, req.QuestionID, tagIDs, operatorID); err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		return nil
+	})
+}
+
+func (s *OJQuestionBankService) requireSuperAdmin(ctx context.Context, userID uint) error {
+	ok, err := s.authorizationService.IsSuperAdmin(ctx, userID)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if !ok {
+		return bizerrors.New(bizerrors.CodePermissionDenied)
+	}
+	return nil
+}
+
+func (s *OJQuestionBankService) getTag(ctx context.Context, tagID uint) (*entity.OJQuestionTag, error) {
+	tag, err := s.questionBankRepo.GetTagByID(ctx, tagID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if tag == nil {
+		return nil, bizerrors.New(bizerrors.CodeOJQuestionTagNotFound)
+	}
+	return tag, nil
+}
+
+// validateTagName 规范化标签名称并校验唯一性；selfID 为正在修改的标签，允许与自身同名。
+func (s *OJQuestionBankService) validateTagName(ctx context.Context, raw string, selfID uint) (string, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" {
+		return "", bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "标签名称不能为空")
+	}
+	existing, err := s.questionBankRepo.GetTagByName(ctx, name)
+	if err != nil {
+		return "", bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if existing != nil && existing.ID != selfID {
+		return "", bizerrors.New(bizerrors.CodeOJQuestionTagDuplicate)
+	}
+	return name, nil
+}
+
+// resolveOJQuestionBankPlatforms 把请求中的平台解析为检索范围；为空时覆盖内置平台与全部已注册扩展平台。
+func resolveOJQuestionBankPlatforms(raw string) ([]string, error) {
+	platform := strings.ToLower(strings.TrimSpace(raw))
+	if platform == "" {
+		platforms := []string{consts.OJPlatformLuogu, consts.OJPlatformLeetcode, consts.OJPlatformLanqiao}
+		return append(platforms, ojProviderPlatforms()...), nil
+	}
+	if !isOJQuestionBankPlatform(platform) {
+		return nil, bizerrors.New(bizerrors.CodeOJPlatformInvalid)
+	}
+	return []string{platform}, nil
+}
+
+func isOJQuestionBankPlatform(platform string) bool {
+	switch platform {
+	case consts.OJPlatformLuogu, consts.OJPlatformLeetcode, consts.OJPlatformLanqiao:
+		return true
+	}
+	_, ok := lookupOJPlatformProvider(platform)
+	return ok
+}
+
+func uniqueOJQuestionTagIDs(tagIDs []uint) []uint {
+	result := make([]uint, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		if tagID == 0 || slices.Contains(result, tagID) {
+			continue
+		}
+		result = append(result, tagID)
+	}
+	return result
+}
@@ -0,0 +1,244 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/model/entity"
+	reposystem "personal_assistant/internal/repository/system"
+	bizerrors "personal_assistant/pkg/errors"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestSearchQuestionsAcrossPlatforms(t *testing.T) {
+	svc, _ := newOJQuestionBankTestService(t)
+	ctx := context.Background()
+
+	list, total, err := svc.SearchQuestions(ctx, &request.OJQuestionBankListReq{Keyword: "sum"})
+	if err != nil {
+		t.Fatalf("SearchQuestions() error = %v", err)
+	}
+	if total != 3 || len(list) != 3 {
+		t.Fatalf("expected 3 sum questions across platforms, got total=%d len=%d", total, len(list))
+	}
+	got := make([]string, 0, len(list))
+	for _, item := range list {
+		got = append(got, fmt.Sprintf("%s:%s", item.Platform, item.QuestionCode))
+	}
+	want := "codeforces:1A|leetcode:two-sum|luogu:P1001"
+	if joined := fmt.Sprint(got[0], "|", got[1], "|", got[2]); joined != want {
+		t.Fatalf("unexpected search order: %s", joined)
+	}
+	// 待验证题目不能直接落任务，不下发 analysis_token。
+	if list[0].AnalysisToken != "" || list[0].SourceStatusLabel != "pending" {
+		t.Fatalf("pending question should not carry token: %+v", list[0])
+	}
+	claims, err := svc.analysisTokenCodec.Decode(list[1].AnalysisToken)
+	if err != nil || claims.Platform != consts.OJPlatformLeetcode || claims.QuestionID != list[1].QuestionID || claims.Title != "Two Sum" {
+		t.Fatalf("unexpected analysis token claims: %+v, err=%v", claims, err)
+	}
+
+	list, total, err = svc.SearchQuestions(ctx, &request.OJQuestionBankListReq{Platform: "luogu", Difficulty: "入门"})
+	if err != nil {
+		t.Fatalf("SearchQuestions() by difficulty error = %v", err)
+	}
+	if total != 1 || list[0].QuestionCode != "P1001" {
+		t.Fatalf("unexpected difficulty filter result: total=%d list=%+v", total, list)
+	}
+
+	// 题号完全一致的题目排在最前。
+	list, _, err = svc.SearchQuestions(ctx, &request.OJQuestionBankListReq{Keyword: "1002"})
+	if err != nil {
+		t.Fatalf("SearchQuestions() by code error = %v", err)
+	}
+	if len(list) != 2 || list[0].Platform != consts.OJPlatformLanqiao {
+		t.Fatalf("expected exact lanqiao code first, got %+v", list)
+	}
+
+	if _, _, err := svc.SearchQuestions(ctx, &request.OJQuestionBankListReq{Platform: "unknown"}); !hasBizCode(err, bizerrors.CodeOJPlatformInvalid) {
+		t.Fatalf("expected invalid platform, got %v", err)
+	}
+}
+
+func TestSearchQuestionsTreatsLikeWildcardsLiterally(t *testing.T) {
+	svc, db := newOJQuestionBankTestService(t)
+	ctx := context.Background()
+	mustCreate(t, db, &entity.LuoguQuestionBank{
+		Pid:          "P2000",
+		Title:        "100% A_B",
+		SourceStatus: int8(consts.OJQuestionSourceStatusVerified),
+		SourceType:   "sync",
+	})
+
+	for _, keyword := range []string{"%", "_", "0%", "A_B", "!"} {
+		list, total, err := svc.SearchQuestions(ctx, &request.OJQuestionBankListReq{Keyword: keyword})
+		if err != nil {
+			t.Fatalf("SearchQuestions(%q) error = %v", keyword, err)
+		}
+		want := int64(1)
+		if keyword == "!" {
+			want = 0
+		}
+		if total != want || len(list) != int(want) {
+			t.Fatalf("SearchQuestions(%q) total=%d len=%d, want %d", keyword, total, len(list), want)
+		}
+		if want == 1 && list[0].QuestionCode != "P2000" {
+			t.Fatalf("SearchQuestions(%q) matched %+v, want P2000", keyword, list[0])
+		}
+	}
+}
+
+func TestQuestionTagsFilterSearch(t *testing.T) {
+	svc, db := newOJQuestionBankTestService(t)
+	ctx := context.Background()
+
+	dp, err := svc.CreateTag(ctx, 1, &request.CreateOJQuestionTagReq{Name: " DP "})
+	if err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+	graph, err := svc.CreateTag(ctx, 1, &request.CreateOJQuestionTagReq{Name: "图论"})
+	if err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+	if _, err := svc.CreateTag(ctx, 1, &request.CreateOJQuestionTagReq{Name: "DP"}); !hasBizCode(err, bizerrors.CodeOJQuestionTagDuplicate) {
+		t.Fatalf("expected duplicate tag, got %v", err)
+	}
+
+	leetcode := findOJQuestionBankSeed(t, db, "leetcode_question_banks", "two-sum", "title_slug")
+	luogu := findOJQuestionBankSeed(t, db, "luogu_question_banks", "P1001", "pid")
+	if err := svc.SetQuestionTags(ctx, 1, &request.SetOJQuestionTagsReq{
+		Platform: consts.OJPlatformLeetcode, QuestionID: leetcode, TagIDs: []uint{dp.ID, graph.ID, dp.ID},
+	}); err != nil {
+		t.Fatalf("SetQuestionTags() error = %v", err)
+	}
+	if err := svc.SetQuestionTags(ctx, 1, &request.SetOJQuestionTagsReq{
+		Platform: consts.OJPlatformLuogu, QuestionID: luogu, TagIDs: []uint{dp.ID},
+	}); err != nil {
+		t.Fatalf("SetQuestionTags() error = %v", err)
+	}
+
+	list, total, err := svc.SearchQuestions(ctx, &request.OJQuestionBankListReq{TagIDs: []uint{dp.ID, graph.ID}})
+	if err != nil {
+		t.Fatalf("SearchQuestions() by tags error = %v", err)
+	}
+	if total != 1 || list[0].QuestionCode != "two-sum" || len(list[0].Tags) != 2 {
+		t.Fatalf("expected only two-sum with both tags, got total=%d list=%+v", total, list)
+	}
+
+	tags, err := svc.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+	if len(tags) != 2 || tags[0].Name != "DP" || tags[0].QuestionCount != 2 {
+		t.Fatalf("unexpected tag list: %+v", tags)
+	}
+
+	if err := svc.DeleteTag(ctx, 1, dp.ID); err != nil {
+		t.Fatalf("DeleteTag() error = %v", err)
+	}
+	var bindings int64
+	if err := db.Model(&entity.OJQuestionTagBinding{}).Count(&bindings).Error; err != nil {
+		t.Fatalf("count bindings: %v", err)
+	}
+	if bindings != 1 {
+		t.Fatalf("expected only the graph binding left, got %d", bindings)
+	}
+	// 删除后允许重建同名标签。
+	if _, err := svc.CreateTag(ctx, 1, &request.CreateOJQuestionTagReq{Name: "DP"}); err != nil {
+		t.Fatalf("recreate tag error = %v", err)
+	}
+}
+
+func TestQuestionTagManagementRequiresSuperAdmin(t *testing.T) {
+	svc, db := newOJQuestionBankTestService(t)
+	ctx := context.Background()
+
+	if _, err := svc.CreateTag(ctx, 2, &request.CreateOJQuestionTagReq{Name: "DP"}); !hasBizCode(err, bizerrors.CodePermissionDenied) {
+		t.Fatalf("expected permission denied, got %v", err)
+	}
+
+	tag, err := svc.CreateTag(ctx, 1, &request.CreateOJQuestionTagReq{Name: "DP"})
+	if err != nil {
+		t.Fatalf("CreateTag() error = %v", err)
+	}
+	if err := svc.SetQuestionTags(ctx, 1, &request.SetOJQuestionTagsReq{
+		Platform: consts.OJPlatformLeetcode, QuestionID: 9999, TagIDs: []uint{tag.ID},
+	}); !hasBizCode(err, bizerrors.CodeOJQuestionNotFound) {
+		t.Fatalf("expected question not found, got %v", err)
+	}
+	leetcode := findOJQuestionBankSeed(t, db, "leetcode_question_banks", "two-sum", "title_slug")
+	if err := svc.SetQuestionTags(ctx, 1, &request.SetOJQuestionTagsReq{
+		Platform: consts.OJPlatformLeetcode, QuestionID: leetcode, TagIDs: []uint{tag.ID, tag.ID + 100},
+	}); !hasBizCode(err, bizerrors.CodeOJQuestionTagNotFound) {
+		t.Fatalf("expected tag not found, got %v", err)
+	}
+}
+
+// newOJQuestionBankTestService 预置四个平台的题库：洛谷 P1001/P1002、力扣 two-sum、蓝桥 1002、Codeforces 1A（待验证）。
+func newOJQuestionBankTestService(t *testing.T) (*OJQuestionBankService, *gorm.DB) {
+	t.Helper()
+
+	oldConfig := global.Config
+	oldLog := global.Log
+	global.Config = &config.Config{}
+	global.Config.JWT.AccessTokenSecret = "question-bank-test-secret"
+	global.Log = zap.NewNop()
+	t.Cleanup(func() {
+		global.Config = oldConfig
+		global.Log = oldLog
+	})
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&entity.LuoguQuestionBank{},
+		&entity.LeetcodeQuestionBank{},
+		&entity.LanqiaoQuestionBank{},
+		&entity.OJProviderQuestionBank{},
+		&entity.OJQuestionTag{},
+		&entity.OJQuestionTagBinding{},
+	); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+
+	verified := int8(consts.OJQuestionSourceStatusVerified)
+	mustCreate(t, db, &entity.LuoguQuestionBank{Pid: "P1001", Title: "A+B Sum", Difficulty: "入门", SourceStatus: verified, SourceType: "sync"})
+	mustCreate(t, db, &entity.LuoguQuestionBank{Pid: "P1002", Title: "过河卒", Difficulty: "普及-", SourceStatus: verified, SourceType: "sync"})
+	mustCreate(t, db, &entity.LeetcodeQuestionBank{TitleSlug: "two-sum", Title: "Two Sum", SourceStatus: verified, SourceType: "sync"})
+	mustCreate(t, db, &entity.LanqiaoQuestionBank{ProblemID: 1002, Title: "数字三角形", SourceStatus: verified, SourceType: "sync"})
+	mustCreate(t, db, &entity.OJProviderQuestionBank{
+		Platform:     consts.OJPlatformCodeforces,
+		ProblemCode:  "1A",
+		Title:        "Theatre Square Sum",
+		Difficulty:   "1000",
+		SourceStatus: int8(consts.OJQuestionSourceStatusPending),
+		SourceType:   "manual",
+	})
+
+	svc := &OJQuestionBankService{
+		txRunner:             &stubTxRunner{},
+		questionBankRepo:     reposystem.NewOJQuestionBankRepository(db),
+		authorizationService: &fakeAIMemoryManageAuthorization{superAdmins: map[uint]bool{1: true}},
+		analysisTokenCodec:   newOJTaskAnalysisTokenCodec(),
+	}
+	return svc, db
+}
+
+func findOJQuestionBankSeed(t *testing.T, db *gorm.DB, table, code, column string) uint {
+	t.Helper()
+	var id uint
+	if err := db.Table(table).Select("id").Where(column+" = ?", code).Scan(&id).Error; err != nil || id == 0 {
+		t.Fatalf("find %s %s: id=%d err=%v", table, code, id, err)
+	}
+	return id
+}
@@ -0,0 +1,197 @@
+package system
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	bizerrors "personal_assistant/pkg/errors"
+	"personal_assistant/pkg/rankingcache"
+	"personal_assistant/pkg/rediskey"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// rankingWindowTTL 控制时间窗口榜的缓存时长；日统计由投影异步刷新，短 TTL 足以兼顾时效与开销。
+	rankingWindowTTL = 5 * time.Minute
+	// rankingWindowMaxDays 限制自定义区间跨度，避免一次聚合扫描过多日统计行。
+	rankingWindowMaxDays = 366
+	rankingDateLayout    = "2006-01-02"
+)
+
+// rankingModeQuery 表示规范化后的排名模式；StartDate / EndDate 仅在 window 模式下有值，均为包含的自然日。
+type rankingModeQuery struct {
+	Mode      string
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// normalizeRankingMode 校验排名模式，并在 window 模式下按日统计时区解析日期区间。
+func normalizeRankingMode(
+	platform string,
+	req *request.OJRankingListReq,
+	now time.Time,
+) (*rankingModeQuery, error) {
+	mode := strings.ToLower(strings.TrimSpace(req.Mode))
+	switch mode {
+	case "", consts.OJRankingModeTotal:
+		return &rankingModeQuery{Mode: consts.OJRankingModeTotal}, nil
+	case consts.OJRankingModeWeighted:
+		if !rankingcache.SupportsWeighted(platform) {
+			return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "加权排行榜仅支持力扣与洛谷")
+		}
+		return &rankingModeQuery{Mode: consts.OJRankingModeWeighted}, nil
+	case consts.OJRankingModeWindow:
+	default:
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "不支持的排名模式")
+	}
+
+	loc := ojDailyStatsLocation()
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	query := &rankingModeQuery{Mode: consts.OJRankingModeWindow, EndDate: today}
+	switch strings.ToLower(strings.TrimSpace(req.Window)) {
+	case "", consts.OJRankingWindowWeek:
+		// 自然周从周一开始。
+		offset := (int(today.Weekday()) + 6) % 7
+		query.StartDate = today.AddDate(0, 0, -offset)
+	case consts.OJRankingWindowMonth:
+		query.StartDate = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, loc)
+	case consts.OJRankingWindowCustom:
+		start, err := time.ParseInLocation(rankingDateLayout, strings.TrimSpace(req.StartDate), loc)
+		if err != nil {
+			return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "自定义区间需要合法的 start_date")
+		}
+		end, err := time.ParseInLocation(rankingDateLayout, strings.TrimSpace(req.EndDate), loc)
+		if err != nil {
+			return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "自定义区间需要合法的 end_date")
+		}
+		if end.Before(start) {
+			return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "end_date 不能早于 start_date")
+		}
+		if end.After(today) {
+			return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "end_date 不能晚于今天")
+		}
+		if end.Sub(start) >= rankingWindowMaxDays*24*time.Hour {
+			return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "自定义区间不能超过 366 天")
+		}
+		query.StartDate = start
+		query.EndDate = end
+	default:
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "不支持的时间窗口")
+	}
+	return query, nil
+}
+
+// rankingAllMembersKey 返回全站范围在指定模式下的持久排行榜 key；window 模式以累计榜作为成员来源。
+func rankingAllMembersKey(platform string, mode string) string {
+	if mode == consts.OJRankingModeWeighted {
+		return rediskey.RankingAllMembersWeightedZSetKey(platform)
+	}
+	return rediskey.RankingAllMembersZSetKey(platform)
+}
+
+// rankingOrgKey 返回组织范围在指定模式下的持久排行榜 key。
+func rankingOrgKey(orgID uint, platform string, mode string) string {
+	if mode == consts.OJRankingModeWeighted {
+		return rediskey.RankingOrgWeightedZSetKey(orgID, platform)
+	}
+	return rediskey.RankingOrgZSetKey(orgID, platform)
+}
+
+// ensureRankingWindowKey 确保时间窗口榜存在并返回其 key。
+// 成员取自同范围的累计榜，分数为 oj_user_daily_stats 中区间内 SolvedCount 之和；区间内没有新增的成员记 0 分，
+// 保证窗口榜与累计榜的成员集合一致。
+func (s *OJService) ensureRankingWindowKey(
+	ctx context.Context,
+	baseKey string,
+	platform string,
+	query *rankingModeQuery,
+) (string, error) {
+	key := rediskey.RankingWindowZSetKey(
+		query.StartDate.Format("20060102"),
+		query.EndDate.Format("20060102"),
+		baseKey,
+	)
+	exists, err := global.Redis.Exists(ctx, key).Result()
+	if err != nil {
+		return "", err
+	}
+	if exists > 0 {
+		return key, nil
+	}
+
+	members, err := global.Redis.ZRange(ctx, baseKey, 0, -1).Result()
+	if err != nil {
+		return "", err
+	}
+	userIDs := make([]uint, 0, len(members))
+	for _, member := range members {
+		id, parseErr := strconv.ParseUint(member, 10, 64)
+		if parseErr != nil || id == 0 {
+			continue
+		}
+		userIDs = append(userIDs, uint(id))
+	}
+	if len(userIDs) == 0 {
+		return key, nil
+	}
+
+	solved, err := s.sumRankingWindowSolved(ctx, platform, userIDs, query)
+	if err != nil {
+		return "", err
+	}
+	entries := make([]*redis.Z, 0, len(userIDs))
+	for _, userID := range userIDs {
+		entries = append(entries, &redis.Z{
+			Score:  float64(solved[userID]),
+			Member: strconv.FormatUint(uint64(userID), 10),
+		})
+	}
+
+	pipe := global.Redis.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.ZAdd(ctx, key, entries...)
+	pipe.Expire(ctx, key, rankingWindowTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// sumRankingWindowSolved 汇总区间内的新增过题数；综合榜按各平台权重折算后相加。
+func (s *OJService) sumRankingWindowSolved(
+	ctx context.Context,
+	platform string,
+	userIDs []uint,
+	query *rankingModeQuery,
+) (map[uint]int, error) {
+	if platform != rankingcache.PlatformAll {
+		return s.ojDailyStatsRepo.SumSolvedByUsers(ctx, platform, userIDs, query.StartDate, query.EndDate)
+	}
+
+	byUser := make(map[uint]map[string]int, len(userIDs))
+	for _, item := range rankingcache.CombinedPlatforms {
+		solved, err := s.ojDailyStatsRepo.SumSolvedByUsers(ctx, item, userIDs, query.StartDate, query.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		for userID, count := range solved {
+			if byUser[userID] == nil {
+				byUser[userID] = make(map[string]int, len(rankingcache.CombinedPlatforms))
+			}
+			byUser[userID][item] = count
+		}
+	}
+	out := make(map[uint]int, len(byUser))
+	for userID, solved := range byUser {
+		out[userID] = rankingcache.CombinedScore(solved)
+	}
+	return out, nil
+}
@@ -0,0 +1,301 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/model/entity"
+	readmodel "personal_assistant/internal/model/readmodel"
+	reposystem "personal_assistant/internal/repository/system"
+	bizerrors "personal_assistant/pkg/errors"
+	"personal_assistant/pkg/rankingcache"
+	"personal_assistant/pkg/rediskey"
+
+	"github.com/glebarez/sqlite"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+func TestGetRankingListWeightedModeUsesWeightedZSet(t *testing.T) {
+	setupRankingRedis(t)
+	ctx := context.Background()
+
+	items := map[uint]*readmodel.Ranking{
+		// 2 号做题多但以简单题为主，3 号做题少但难度更高。
+		2: {
+			UserID:                2,
+			Username:              "alice",
+			Status:                consts.UserStatusActive,
+			LeetcodeIdentifier:    "alice",
+			LeetcodeScore:         10,
+			LeetcodeWeightedScore: 10,
+		},
+		3: {
+			UserID:                3,
+			Username:              "bob",
+			Status:                consts.UserStatusActive,
+			LeetcodeIdentifier:    "bob",
+			LeetcodeScore:         6,
+			LeetcodeWeightedScore: 18,
+		},
+	}
+	for _, item := range items {
+		if err := rankingcache.SyncProjectionRanks(ctx, global.Redis, rankingcache.FromReadModel(item), nil); err != nil {
+			t.Fatalf("SyncProjectionRanks() error = %v", err)
+		}
+	}
+
+	svc := newRankingModeTestService(items)
+	out, err := svc.GetRankingList(ctx, 1, &request.OJRankingListReq{
+		Platform: "leetcode",
+		Scope:    rankingScopeAllMembers,
+		Mode:     consts.OJRankingModeWeighted,
+	})
+	if err != nil {
+		t.Fatalf("GetRankingList() error = %v", err)
+	}
+	if out.Mode != consts.OJRankingModeWeighted || len(out.List) != 2 {
+		t.Fatalf("unexpected ranking result: mode=%q len=%d", out.Mode, len(out.List))
+	}
+	if out.List[0].UserID != 3 || out.List[0].TotalPassed != 18 {
+		t.Fatalf("first = %+v, want user 3 with weighted score 18", out.List[0])
+	}
+
+	total, err := svc.GetRankingList(ctx, 1, &request.OJRankingListReq{
+		Platform: "leetcode",
+		Scope:    rankingScopeAllMembers,
+	})
+	if err != nil {
+		t.Fatalf("GetRankingList(total) error = %v", err)
+	}
+	if total.Mode != consts.OJRankingModeTotal || total.List[0].UserID != 2 {
+		t.Fatalf("total mode first = %+v, want user 2", total.List[0])
+	}
+}
+
+func TestGetRankingListWeightedModeRejectsUnsupportedPlatform(t *testing.T) {
+	setupRankingRedis(t)
+	svc := newRankingModeTestService(nil)
+
+	_, err := svc.GetRankingList(context.Background(), 1, &request.OJRankingListReq{
+		Platform: "lanqiao",
+		Mode:     consts.OJRankingModeWeighted,
+	})
+	if !hasBizCode(err, bizerrors.CodeInvalidParams) {
+		t.Fatalf("GetRankingList() error = %v, want CodeInvalidParams", err)
+	}
+}
+
+func TestGetRankingListWindowModeSumsDailyStats(t *testing.T) {
+	setupRankingRedis(t)
+	ctx := context.Background()
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&entity.OJUserDailyStat{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+
+	loc := ojDailyStatsLocation()
+	day := func(value string) time.Time {
+		parsed, err := time.ParseInLocation(rankingDateLayout, value, loc)
+		if err != nil {
+			t.Fatalf("parse date: %v", err)
+		}
+		return parsed
+	}
+	if err := db.Create([]*entity.OJUserDailyStat{
+		{UserID: 2, Platform: "luogu", StatDate: day("2026-03-02"), SolvedCount: 3, SolvedTotal: 103},
+		{UserID: 2, Platform: "luogu", StatDate: day("2026-03-04"), SolvedCount: 2, SolvedTotal: 105},
+		// 区间外与其他平台的数据不应计入。
+		{UserID: 2, Platform: "luogu", StatDate: day("2026-03-10"), SolvedCount: 9, SolvedTotal: 114},
+		{UserID: 3, Platform: "leetcode", StatDate: day("2026-03-03"), SolvedCount: 7, SolvedTotal: 7},
+	}).Error; err != nil {
+		t.Fatalf("seed daily stats: %v", err)
+	}
+
+	// 累计榜上 3 号领先，但窗口内只有 2 号有新增。
+	for member, score := range map[string]float64{"2": 105, "3": 300} {
+		if err := global.Redis.ZAdd(ctx, rediskey.RankingAllMembersZSetKey("luogu"), &redis.Z{
+			Score:  score,
+			Member: member,
+		}).Err(); err != nil {
+			t.Fatalf("seed ranking zset error = %v", err)
+		}
+	}
+
+	svc := newRankingModeTestService(map[uint]*readmodel.Ranking{
+		2: {UserID: 2, Username: "alice", Status: consts.UserStatusActive, LuoguIdentifier: "1001"},
+		3: {UserID: 3, Username: "bob", Status: consts.UserStatusActive, LuoguIdentifier: "1002"},
+	})
+	svc.ojDailyStatsRepo = reposystem.NewOJDailyStatsRepository(db)
+
+	out, err := svc.GetRankingList(ctx, 3, &request.OJRankingListReq{
+		Platform:  "luogu",
+		Scope:     rankingScopeAllMembers,
+		Mode:      consts.OJRankingModeWindow,
+		Window:    consts.OJRankingWindowCustom,
+		StartDate: "2026-03-01",
+		EndDate:   "2026-03-07",
+	})
+	if err != nil {
+		t.Fatalf("GetRankingList() error = %v", err)
+	}
+	if out.StartDate != "2026-03-01" || out.EndDate != "2026-03-07" || out.Total != 2 {
+		t.Fatalf("unexpected window result: %+v", out)
+	}
+	if out.List[0].UserID != 2 || out.List[0].TotalPassed != 5 {
+		t.Fatalf("first = %+v, want user 2 with 5 solved", out.List[0])
+	}
+	if out.List[1].UserID != 3 || out.List[1].TotalPassed != 0 {
+		t.Fatalf("second = %+v, want user 3 with 0 solved", out.List[1])
+	}
+	if out.MyRank == nil || out.MyRank.Rank != 2 {
+		t.Fatalf("MyRank = %+v, want rank 2", out.MyRank)
+	}
+
+	windowKey := rediskey.RankingWindowZSetKey("20260301", "20260307", rediskey.RankingAllMembersZSetKey("luogu"))
+	ttl, err := global.Redis.TTL(ctx, windowKey).Result()
+	if err != nil || ttl <= 0 || ttl > rankingWindowTTL {
+		t.Fatalf("window key ttl = %v, %v; want within %v", ttl, err, rankingWindowTTL)
+	}
+}
+
+func TestNormalizeRankingModeWindowRanges(t *testing.T) {
+	loc := ojDailyStatsLocation()
+	// 2026-03-05 是周四。
+	now := time.Date(2026, 3, 5, 10, 0, 0, 0, loc)
+
+	week, err := normalizeRankingMode("luogu", &request.OJRankingListReq{Mode: consts.OJRankingModeWindow}, now)
+	if err != nil {
+		t.Fatalf("normalizeRankingMode(week) error = %v", err)
+	}
+	if got := week.StartDate.Format(rankingDateLayout); got != "2026-03-02" {
+		t.Fatalf("week start = %s, want 2026-03-02", got)
+	}
+	if got := week.EndDate.Format(rankingDateLayout); got != "2026-03-05" {
+		t.Fatalf("week end = %s, want 2026-03-05", got)
+	}
+
+	month, err := normalizeRankingMode("luogu", &request.OJRankingListReq{
+		Mode:   consts.OJRankingModeWindow,
+		Window: consts.OJRankingWindowMonth,
+	}, now)
+	if err != nil {
+		t.Fatalf("normalizeRankingMode(month) error = %v", err)
+	}
+	if got := month.StartDate.Format(rankingDateLayout); got != "2026-03-01" {
+		t.Fatalf("month start = %s, want 2026-03-01", got)
+	}
+
+	invalid := []*request.OJRankingListReq{
+		{Mode: consts.OJRankingModeWindow, Window: consts.OJRankingWindowCustom},
+		{Mode: consts.OJRankingModeWindow, Window: consts.OJRankingWindowCustom, StartDate: "2026-03-04", EndDate: "2026-03-01"},
+		{Mode: consts.OJRankingModeWindow, Window: consts.OJRankingWindowCustom, StartDate: "2026-03-01", EndDate: "2026-03-06"},
+		{Mode: consts.OJRankingModeWindow, Window: consts.OJRankingWindowCustom, StartDate: "2025-01-01", EndDate: "2026-03-01"},
+	}
+	for _, req := range invalid {
+		if _, err := normalizeRankingMode("luogu", req, now); !hasBizCode(err, bizerrors.CodeInvalidParams) {
+			t.Fatalf("normalizeRankingMode(%+v) error = %v, want CodeInvalidParams", req, err)
+		}
+	}
+}
+
+func TestGetRankingListAllPlatformUsesCombinedProjection(t *testing.T) {
+	setupRankingRedis(t)
+	ctx := context.Background()
+
+	oldConfig := global.Config
+	global.Config = &config.Config{System: config.System{
+		RankingWeightLeetcode: 1,
+		RankingWeightLuogu:    0.5,
+		RankingWeightLanqiao:  2,
+	}}
+	t.Cleanup(func() { global.Config = oldConfig })
+
+	items := map[uint]*readmodel.Ranking{
+		// 综合分 = 10*1 + 20*0.5 + 0*2 = 20。
+		2: {
+			UserID:             2,
+			Username:           "alice",
+			Status:             consts.UserStatusActive,
+			LeetcodeIdentifier: "alice",
+			LeetcodeScore:      10,
+			LuoguIdentifier:    "1001",
+			LuoguScore:         20,
+		},
+		// 综合分 = 12*2 = 24，题数更少但蓝桥权重更高。
+		3: {
+			UserID:            3,
+			Username:          "bob",
+			Status:            consts.UserStatusActive,
+			LanqiaoIdentifier: "138****0000",
+			LanqiaoScore:      12,
+		},
+		// 只绑定了 Codeforces，不进入综合榜。
+		4: {
+			UserID:   4,
+			Username: "carol",
+			Status:   consts.UserStatusActive,
+			Providers: map[string]readmodel.RankingProviderProfile{
+				consts.OJPlatformCodeforces: {Identifier: "tourist", Score: 500},
+			},
+		},
+	}
+	for _, item := range items {
+		if err := rankingcache.SyncProjectionRanks(ctx, global.Redis, rankingcache.FromReadModel(item), nil); err != nil {
+			t.Fatalf("SyncProjectionRanks() error = %v", err)
+		}
+	}
+
+	svc := newRankingModeTestService(items)
+	out, err := svc.GetRankingList(ctx, 1, &request.OJRankingListReq{
+		Platform: rankingcache.PlatformAll,
+		Scope:    rankingScopeAllMembers,
+	})
+	if err != nil {
+		t.Fatalf("GetRankingList() error = %v", err)
+	}
+	if out.Total != 2 || len(out.List) != 2 {
+		t.Fatalf("unexpected ranking result: total=%d len=%d", out.Total, len(out.List))
+	}
+	if out.List[0].UserID != 3 || out.List[0].TotalPassed != 24 || out.List[0].SolvedTotal != 12 {
+		t.Fatalf("first = %+v, want user 3 with combined score 24", out.List[0])
+	}
+	second := out.List[1]
+	if second.UserID != 2 || second.TotalPassed != 20 || second.SolvedTotal != 30 {
+		t.Fatalf("second = %+v, want user 2 with combined score 20", second)
+	}
+	if second.PlatformDetails == nil || second.PlatformDetails[consts.OJPlatformLeetcode] != 10 || second.PlatformDetails[consts.OJPlatformLuogu] != 20 {
+		t.Fatalf("PlatformDetails = %+v, want per-platform solved counts", second.PlatformDetails)
+	}
+
+	_, err = svc.GetRankingList(ctx, 1, &request.OJRankingListReq{
+		Platform: rankingcache.PlatformAll,
+		Mode:     consts.OJRankingModeWeighted,
+	})
+	if !hasBizCode(err, bizerrors.CodeInvalidParams) {
+		t.Fatalf("weighted all error = %v, want CodeInvalidParams", err)
+	}
+}
+
+func newRankingModeTestService(items map[uint]*readmodel.Ranking) *OJService {
+	return &OJService{
+		userRepo: &stubRankingUserRepository{
+			users: map[uint]*entity.User{
+				1: {MODEL: entity.MODEL{ID: 1}, Status: consts.UserStatusActive},
+				3: {MODEL: entity.MODEL{ID: 3}, Status: consts.UserStatusActive},
+			},
+		},
+		roleRepo:             &stubRankingRoleRepository{},
+		rankingReadModelRepo: &stubRankingReadModelRepository{items: items},
+	}
+}
@@ -191,7 +191,7 @@ func TestGetRankingListLanqiaoUsesLanqiaoScore(t *testing.T) {
 	if out.List[0].TotalPassed != 12 {
 		t.Fatalf("TotalPassed = %d, want 12", out.List[0].TotalPassed)
 	}
-	if out.List[0].PlatformDetails == nil || out.List[0].PlatformDetails.Lanqiao != 12 {
+	if out.List[0].PlatformDetails == nil || out.List[0].PlatformDetails[consts.OJPlatformLanqiao] != 12 {
 		t.Fatalf("PlatformDetails = %+v, want lanqiao score 12", out.List[0].PlatformDetails)
 	}
 	if out.List[0].Avatar != "base-avatar" {
@@ -51,6 +51,9 @@ type OJService struct {
 	leetcodeUserQuestionRepo  interfaces.LeetcodeUserQuestionRepository
 	luoguUserQuestionRepo     interfaces.LuoguUserQuestionRepository
 	lanqiaoUserQuestionRepo   interfaces.LanqiaoUserQuestionRepository
+	providerDetailRepo        interfaces.OJProviderUserDetailRepository
+	providerQuestionBankRepo  interfaces.OJProviderQuestionBankRepository
+	providerUserQuestionRepo  interfaces.OJProviderUserQuestionRepository
 	ojDailyStatsRepo          interfaces.OJDailyStatsRepository
 	rankingReadModelRepo      interfaces.RankingReadModelRepository
 	outboxRepo                interfaces.OutboxRepository
@@ -80,6 +83,9 @@ func NewOJService(
 		leetcodeUserQuestionRepo: repositoryGroup.SystemRepositorySupplier.GetLeetcodeUserQuestionRepository(),
 		luoguUserQuestionRepo:    repositoryGroup.SystemRepositorySupplier.GetLuoguUserQuestionRepository(),
 		lanqiaoUserQuestionRepo:  repositoryGroup.SystemRepositorySupplier.GetLanqiaoUserQuestionRepository(),
+		providerDetailRepo:       repositoryGroup.SystemRepositorySupplier.GetOJProviderUserDetailRepository(),
+		providerQuestionBankRepo: repositoryGroup.SystemRepositorySupplier.GetOJProviderQuestionBankRepository(),
+		providerUserQuestionRepo: repositoryGroup.SystemRepositorySupplier.GetOJProviderUserQuestionRepository(),
 		ojDailyStatsRepo:         repositoryGroup.SystemRepositorySupplier.GetOJDailyStatsRepository(),
 		rankingReadModelRepo:     repositoryGroup.SystemRepositorySupplier.GetRankingReadModelRepository(),
 		outboxRepo:               repositoryGroup.SystemRepositorySupplier.GetOutboxRepository(),
@@ -117,7 +123,11 @@ func (s *OJService) BindOJAccount(
 	case "luogu":
 		return s.bindLuogu(ctx, userID, identifier, sleepSec)
 	default:
-		return nil, svccontract.ErrInvalidPlatform
+		provider, ok := lookupOJPlatformProvider(platform)
+		if !ok {
+			return nil, svccontract.ErrInvalidPlatform
+		}
+		return s.bindProviderAccount(ctx, userID, provider, identifier)
 	}
 }
 
@@ -134,10 +144,11 @@ func (s *OJService) GetUserStats(
 	}
 
 	platform := strings.ToLower(strings.TrimSpace(req.Platform))
-	if platform != "luogu" && platform != "leetcode" {
-		if platform != "lanqiao" {
+	if platform != "luogu" && platform != "leetcode" && platform != "lanqiao" {
+		if _, ok := lookupOJPlatformProvider(platform); !ok {
 			return nil, svccontract.ErrInvalidPlatform
 		}
+		return s.getProviderUserStats(ctx, userID, platform)
 	}
 
 	if platform == "luogu" {
@@ -295,6 +306,9 @@ func (s *OJService) loadOJCurveSource(
 		}
 		return int(passedCount), detail.UpdatedAt, true, nil
 	default:
+		if _, ok := lookupOJPlatformProvider(platform); ok {
+			return s.loadProviderCurveSource(ctx, userID, platform)
+		}
 		return 0, time.Time{}, false, svccontract.ErrInvalidPlatform
 	}
 }
@@ -1265,6 +1279,10 @@ func (s *OJService) GetRankingList(
 	if err != nil {
 		return nil, err
 	}
+	modeQuery, err := normalizeRankingMode(platform, req, time.Now())
+	if err != nil {
+		return nil, err
+	}
 	if global.Redis == nil {
 		return nil, errors.New("redis is not initialized")
 	}
@@ -1276,10 +1294,17 @@ func (s *OJService) GetRankingList(
 	}
 
 	// 解析排行榜键，确保用户有权限访问对应范围的排行榜
-	key, err := s.resolveRankingKey(ctx, requester, isSuperAdmin, platform, scope, orgID)
+	key, err := s.resolveRankingKey(ctx, requester, isSuperAdmin, platform, scope, orgID, modeQuery.Mode)
 	if err != nil {
 		return nil, err
 	}
+	// 时间窗口榜按需从日统计聚合，成员与同范围累计榜保持一致
+	if modeQuery.Mode == consts.OJRankingModeWindow {
+		key, err = s.ensureRankingWindowKey(ctx, key, platform, modeQuery)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// 从 Redis 获取排行榜数据，包含总数、分页起始位置和当前页数据范围
 	total, start, ranges, err := fetchRankingRanges(ctx, key, page, pageSize)
@@ -1299,11 +1324,17 @@ func (s *OJService) GetRankingList(
 		return nil, err
 	}
 
-	return &resp.OJRankingListResp{
+	out := &resp.OJRankingListResp{
 		List:   list,
 		MyRank: myRank,
 		Total:  total,
-	}, nil
+		Mode:   modeQuery.Mode,
+	}
+	if modeQuery.Mode == consts.OJRankingModeWindow {
+		out.StartDate = modeQuery.StartDate.Format(rankingDateLayout)
+		out.EndDate = modeQuery.EndDate.Format(rankingDateLayout)
+	}
+	return out, nil
 }
 
 // rankingEntry 表示排行榜单条数据的解析结果
@@ -1344,8 +1375,10 @@ func normalizeRankingRequest(
 	}
 
 	platform := strings.ToLower(strings.TrimSpace(req.Platform))
-	if platform != "luogu" && platform != "leetcode" && platform != "lanqiao" {
-		return "", 0, 0, "", nil, svccontract.ErrInvalidPlatform
+	if platform != "luogu" && platform != "leetcode" && platform != "lanqiao" && platform != rankingcache.PlatformAll {
+		if _, ok := lookupOJPlatformProvider(platform); !ok {
+			return "", 0, 0, "", nil, svccontract.ErrInvalidPlatform
+		}
 	}
 
 	page := req.Page
@@ -1403,7 +1436,7 @@ func (s *OJService) getActiveRankingRequester(
 	return user, false, nil
 }
 
-// resolveRankingKey 根据排行榜范围解析对应的 Redis 键
+// resolveRankingKey 根据排行榜范围与排名模式解析对应的 Redis 键；window 模式返回同范围的累计榜键
 func (s *OJService) resolveRankingKey(
 	ctx context.Context,
 	requester *entity.User,
@@ -1411,11 +1444,12 @@ func (s *OJService) resolveRankingKey(
 	platform string,
 	scope string,
 	orgID *uint,
+	mode string,
 ) (string, error) {
 	switch scope {
 	// 全员范围直接使用全局排行榜键，无需组织校验
 	case rankingScopeAllMembers:
-		return rediskey.RankingAllMembersZSetKey(platform), nil
+		return rankingAllMembersKey(platform, mode), nil
 		// 组织范围需要校验组织 ID 和成员资格，并根据是否全员组织解析对应的排行榜键
 	case rankingScopeOrg:
 		if orgID == nil || *orgID == 0 {
@@ -1430,13 +1464,13 @@ func (s *OJService) resolveRankingKey(
 				return "", errors.New("user organization not active")
 			}
 		}
-		return s.resolveOrgRankingKey(ctx, *orgID, platform)
+		return s.resolveOrgRankingKey(ctx, *orgID, platform, mode)
 	case rankingScopeCurrentOrg:
 		if requester == nil || requester.CurrentOrgID == nil || *requester.CurrentOrgID == 0 {
 			return "", errors.New("user organization not found")
 		}
 		if requester.CurrentOrg != nil && isAllMembersBuiltinOrg(requester.CurrentOrg) {
-			return rediskey.RankingAllMembersZSetKey(platform), nil
+			return rankingAllMembersKey(platform, mode), nil
 		}
 		active, err := s.orgMemberRepo.IsUserActiveInOrg(ctx, requester.ID, *requester.CurrentOrgID)
 		if err != nil {
@@ -1445,7 +1479,7 @@ func (s *OJService) resolveRankingKey(
 		if !active {
 			return "", errors.New("user organization not active")
 		}
-		return s.resolveOrgRankingKey(ctx, *requester.CurrentOrgID, platform)
+		return s.resolveOrgRankingKey(ctx, *requester.CurrentOrgID, platform, mode)
 	default:
 		return "", errors.New("invalid ranking scope")
 	}
@@ -1456,6 +1490,7 @@ func (s *OJService) resolveOrgRankingKey(
 	ctx context.Context,
 	orgID uint,
 	platform string,
+	mode string,
 ) (string, error) {
 	// 查询组织信息，判断是否为全员组织
 	org, err := s.orgRepo.GetByID(ctx, orgID)
@@ -1467,11 +1502,11 @@ func (s *OJService) resolveOrgRankingKey(
 	}
 	// 全员组织使用全局排行榜键，非全员组织使用特定组织排行榜键
 	if isAllMembersBuiltinOrg(org) {
-		return rediskey.RankingAllMembersZSetKey(platform), nil
+		return rankingAllMembersKey(platform, mode), nil
 	}
 
 	// 非全员组织使用特定组织排行榜键
-	return rediskey.RankingOrgZSetKey(orgID, platform), nil
+	return rankingOrgKey(orgID, platform, mode), nil
 }
 
 // fetchRankingRanges 从 Redis 拉取分页排行榜数据
@@ -1615,18 +1650,15 @@ func buildRankingList(
 				Name: strings.TrimSpace(projection.CurrentOrgName),
 			}
 		}
-		if platform == "luogu" {
-			item.PlatformDetails = &resp.OJRankingPlatformDetails{
-				Luogu: entry.Score,
-			}
-		} else if platform == "lanqiao" {
-			item.PlatformDetails = &resp.OJRankingPlatformDetails{
-				Lanqiao: entry.Score,
+		item.PlatformDetails = resp.OJRankingPlatformDetails{}
+		if platform == rankingcache.PlatformAll {
+			// 综合榜展示各平台原始题数，便于对照综合分的构成
+			for _, combined := range rankingcache.CombinedPlatforms {
+				item.PlatformDetails.Set(combined, projection.Platform(combined).Score)
 			}
+			item.SolvedTotal = projection.Combined.SolvedTotal
 		} else {
-			item.PlatformDetails = &resp.OJRankingPlatformDetails{
-				Leetcode: entry.Score,
-			}
+			item.PlatformDetails.Set(platform, entry.Score)
 		}
 		list = append(list, item)
 	}
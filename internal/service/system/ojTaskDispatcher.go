@@ -64,14 +64,20 @@ type ojTaskExecutionAttemptResult struct {
 }
 
 // DispatchPendingExecutions 扫描到期执行并按配置并发度触发本轮调度。
-// 调度器只消费 scheduled / queued 且到达 planned_at 的执行记录。
+// 调度器先为到点的周期任务物化本周期执行，再消费 scheduled / queued 且到达 planned_at 的执行记录。
 func (s *OJTaskService) DispatchPendingExecutions(ctx context.Context) error {
+	now := time.Now().UTC()
+	// 物化失败只影响对应周期任务，不阻塞已有执行记录的调度。
+	if err := s.materializeRecurringExecutions(ctx, now); err != nil {
+		global.Log.Error("物化 OJTask 周期执行失败", zap.Error(err))
+	}
+
 	batchSize := resolveOJTaskDispatchBatchSize()
 	workerCount := resolveOJTaskDispatchWorkerCount()
 	rows, err := s.executionRepo.ListDueExecutions(
 		ctx,
 		[]string{string(consts.OJTaskExecutionStatusScheduled), string(consts.OJTaskExecutionStatusQueued)},
-		time.Now().UTC(),
+		now,
 		batchSize,
 	)
 	if err != nil {
@@ -201,7 +207,8 @@ func (s *OJTaskService) dispatchSingleExecution(
 	if execution.RequestedBy > 0 {
 		task.UpdatedBy = execution.RequestedBy
 	}
-	if err := s.taskRepo.Update(ctx, task); err != nil {
+	// 只更新状态列，避免整行保存覆盖周期物化并发推进的 next_occurrence_at。
+	if err := s.taskRepo.UpdateStatus(ctx, task.ID, task.Status, task.UpdatedBy); err != nil {
 		return s.failExecutionAttempt(
 			ctx,
 			row.TaskID,
@@ -372,15 +379,7 @@ func (s *OJTaskService) buildExecutionSnapshot(
 		}
 	}
 
-	luoguDetailMap, luoguSolvedMap, err := s.loadLuoguExecutionFacts(ctx, userIDs)
-	if err != nil {
-		return nil, err
-	}
-	leetcodeDetailMap, leetcodeSolvedMap, err := s.loadLeetcodeExecutionFacts(ctx, userIDs)
-	if err != nil {
-		return nil, err
-	}
-	lanqiaoDetailMap, lanqiaoSolvedMap, err := s.loadLanqiaoExecutionFacts(ctx, userIDs)
+	facts, err := s.loadExecutionFacts(ctx, userIDs, taskItems)
 	if err != nil {
 		return nil, err
 	}
@@ -409,16 +408,7 @@ func (s *OJTaskService) buildExecutionSnapshot(
 		}
 
 		for _, item := range taskItems {
-			resultStatus, reason := resolveOJTaskItemResult(
-				user.ID,
-				item,
-				luoguDetailMap,
-				luoguSolvedMap,
-				leetcodeDetailMap,
-				leetcodeSolvedMap,
-				lanqiaoDetailMap,
-				lanqiaoSolvedMap,
-			)
+			resultStatus, reason := facts.resolve(user.ID, item)
 			if resultStatus == string(consts.OJTaskExecutionUserItemResultCompleted) {
 				executionUser.CompletedItemCount++
 				snapshot.CompletedItemCount++
@@ -448,6 +438,89 @@ func (s *OJTaskService) buildExecutionSnapshot(
 	return snapshot, nil
 }
 
+// ojTaskExecutionFacts 汇总一批用户在各平台上的账号绑定与做题事实，供执行快照与截止复评共用。
+type ojTaskExecutionFacts struct {
+	luoguDetailMap    map[uint]*entity.LuoguUserDetail
+	luoguSolvedMap    map[uint]map[uint]struct{}
+	leetcodeDetailMap map[uint]*entity.LeetcodeUserDetail
+	leetcodeSolvedMap map[uint]map[uint]struct{}
+	lanqiaoDetailMap  map[uint]*entity.LanqiaoUserDetail
+	lanqiaoSolvedMap  map[uint]map[uint]struct{}
+	providerFacts     map[string]*ojProviderExecutionFacts
+}
+
+// loadExecutionFacts 读取执行判定所需的全部平台事实；扩展平台只读取任务中实际出现的平台。
+func (s *OJTaskService) loadExecutionFacts(
+	ctx context.Context,
+	userIDs []uint,
+	taskItems []*entity.OJTaskItem,
+) (*ojTaskExecutionFacts, error) {
+	facts := &ojTaskExecutionFacts{}
+	var err error
+	facts.luoguDetailMap, facts.luoguSolvedMap, err = s.loadLuoguExecutionFacts(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	facts.leetcodeDetailMap, facts.leetcodeSolvedMap, err = s.loadLeetcodeExecutionFacts(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	facts.lanqiaoDetailMap, facts.lanqiaoSolvedMap, err = s.loadLanqiaoExecutionFacts(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	facts.providerFacts, err = s.loadProviderExecutionFacts(ctx, userIDs, taskItems)
+	if err != nil {
+		return nil, err
+	}
+	return facts, nil
+}
+
+// resolve 计算单个用户在单个任务题目上的执行结果，按平台分派到对应判定函数。
+func (f *ojTaskExecutionFacts) resolve(userID uint, item *entity.OJTaskItem) (string, string) {
+	if item != nil {
+		if providerFacts, ok := f.providerFacts[item.Platform]; ok {
+			return resolveOJProviderTaskItemResult(userID, item, providerFacts)
+		}
+	}
+	return resolveOJTaskItemResult(
+		userID,
+		item,
+		f.luoguDetailMap,
+		f.luoguSolvedMap,
+		f.leetcodeDetailMap,
+		f.leetcodeSolvedMap,
+		f.lanqiaoDetailMap,
+		f.lanqiaoSolvedMap,
+	)
+}
+
+// detailUsers 返回指定平台 detailID -> userID 映射，用于把过题时间回挂到用户。
+func (f *ojTaskExecutionFacts) detailUsers(platform string) map[uint]uint {
+	out := make(map[uint]uint)
+	switch platform {
+	case consts.OJPlatformLuogu:
+		for userID, detail := range f.luoguDetailMap {
+			out[detail.ID] = userID
+		}
+	case consts.OJPlatformLeetcode:
+		for userID, detail := range f.leetcodeDetailMap {
+			out[detail.ID] = userID
+		}
+	case consts.OJPlatformLanqiao:
+		for userID, detail := range f.lanqiaoDetailMap {
+			out[detail.ID] = userID
+		}
+	default:
+		if providerFacts, ok := f.providerFacts[platform]; ok {
+			for userID, detail := range providerFacts.detailMap {
+				out[detail.ID] = userID
+			}
+		}
+	}
+	return out
+}
+
 // loadLuoguExecutionFacts 读取洛谷平台执行判定所需的账号绑定与做题事实。
 func (s *OJTaskService) loadLuoguExecutionFacts(
 	ctx context.Context,
@@ -523,6 +596,71 @@ func (s *OJTaskService) loadLanqiaoExecutionFacts(
 	return detailMap, solvedMap, nil
 }
 
+// ojProviderExecutionFacts 是单个扩展平台执行判定所需的账号绑定与做题事实。
+type ojProviderExecutionFacts struct {
+	detailMap map[uint]*entity.OJProviderUserDetail
+	solvedMap map[uint]map[uint]struct{}
+}
+
+// loadProviderExecutionFacts 只为任务中实际出现的扩展平台读取执行判定事实，键为平台标识。
+func (s *OJTaskService) loadProviderExecutionFacts(
+	ctx context.Context,
+	userIDs []uint,
+	taskItems []*entity.OJTaskItem,
+) (map[string]*ojProviderExecutionFacts, error) {
+	factsMap := make(map[string]*ojProviderExecutionFacts)
+	for _, item := range taskItems {
+		if item == nil {
+			continue
+		}
+		if _, loaded := factsMap[item.Platform]; loaded {
+			continue
+		}
+		if _, ok := lookupOJPlatformProvider(item.Platform); !ok {
+			continue
+		}
+		details, err := s.providerDetailRepo.GetByUserIDs(ctx, item.Platform, userIDs)
+		if err != nil {
+			return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		facts := &ojProviderExecutionFacts{
+			detailMap: make(map[uint]*entity.OJProviderUserDetail, len(details)),
+		}
+		detailIDs := make([]uint, 0, len(details))
+		for _, detail := range details {
+			if detail == nil || detail.ID == 0 {
+				continue
+			}
+			facts.detailMap[detail.UserID] = detail
+			detailIDs = append(detailIDs, detail.ID)
+		}
+		facts.solvedMap, err = s.providerUserQuestionRepo.GetSolvedProblemIDsByDetailIDs(ctx, detailIDs)
+		if err != nil {
+			return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		factsMap[item.Platform] = facts
+	}
+	return factsMap, nil
+}
+
+// resolveOJProviderTaskItemResult 计算单个用户在扩展平台任务题目上的执行结果，判定优先级与 resolveOJTaskItemResult 一致。
+func resolveOJProviderTaskItemResult(
+	userID uint,
+	item *entity.OJTaskItem,
+	facts *ojProviderExecutionFacts,
+) (string, string) {
+	if item == nil || item.ResolutionStatus != string(consts.OJTaskItemResolutionStatusResolved) || item.ResolvedQuestionID == 0 {
+		return string(consts.OJTaskExecutionUserItemResultPending), string(consts.OJTaskExecutionUserItemReasonQuestionNotFound)
+	}
+	if facts == nil || facts.detailMap[userID] == nil {
+		return string(consts.OJTaskExecutionUserItemResultPending), string(consts.OJTaskExecutionUserItemReasonAccountUnbound)
+	}
+	if _, ok := facts.solvedMap[facts.detailMap[userID].ID][item.ResolvedQuestionID]; ok {
+		return string(consts.OJTaskExecutionUserItemResultCompleted), ""
+	}
+	return string(consts.OJTaskExecutionUserItemResultPending), string(consts.OJTaskExecutionUserItemReasonUnsolved)
+}
+
 // resolveOJTaskItemResult 计算单个用户在单个任务题目上的执行结果。
 // 判定优先级为：未绑定账号 -> pending/account_unbound；已完成 -> completed；其余 -> pending/unsolved。
 func resolveOJTaskItemResult(
@@ -645,15 +783,24 @@ func (s *OJTaskService) persistExecutionSnapshot(
 		execution.TotalItemCount = snapshot.TotalItemCount
 		execution.CompletedItemCount = snapshot.CompletedItemCount
 		execution.PendingItemCount = snapshot.PendingItemCount
+		execution.DeadlineAt = resolveOJTaskExecutionDeadline(task, execution.PlannedAt)
 		if err := txExecutionRepo.Update(ctx, execution); err != nil {
 			return bizerrors.Wrap(bizerrors.CodeDBError, err)
 		}
 
-		task.Status = string(consts.OJTaskStatusSucceeded)
+		// 周期任务在执行期间可能已推进到下一周期，需按最新行判断收口状态。
+		latestTask, err := txTaskRepo.GetByIDForUpdate(ctx, task.ID)
+		if err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		if latestTask == nil {
+			latestTask = task
+		}
+		task.Status = resolveOJTaskFinishedStatus(latestTask, true)
 		if execution.RequestedBy > 0 {
 			task.UpdatedBy = execution.RequestedBy
 		}
-		if err := txTaskRepo.Update(ctx, task); err != nil {
+		if err := txTaskRepo.UpdateStatus(ctx, task.ID, task.Status, task.UpdatedBy); err != nil {
 			return bizerrors.Wrap(bizerrors.CodeDBError, err)
 		}
 		return nil
@@ -661,6 +808,7 @@ func (s *OJTaskService) persistExecutionSnapshot(
 }
 
 // markExecutionFailed 将执行记录和任务版本统一收口到 failed 状态，并保留错误信息。
+// 周期任务仍有后续周期时，任务版本回到 scheduled，失败只记录在本周期的执行记录上。
 func (s *OJTaskService) markExecutionFailed(
 	ctx context.Context,
 	taskID, executionID uint,
@@ -699,11 +847,11 @@ func (s *OJTaskService) markExecutionFailed(
 		return err
 	}
 	if task != nil {
-		task.Status = string(consts.OJTaskStatusFailed)
+		task.Status = resolveOJTaskFinishedStatus(task, false)
 		if execution != nil && execution.RequestedBy > 0 {
 			task.UpdatedBy = execution.RequestedBy
 		}
-		if err := s.taskRepo.Update(ctx, task); err != nil {
+		if err := s.taskRepo.UpdateStatus(ctx, task.ID, task.Status, task.UpdatedBy); err != nil {
 			return err
 		}
 	}
@@ -33,12 +33,17 @@ type validatedOJTaskItem struct {
 }
 
 type validatedOJTaskDraft struct {
-	Title       string
-	Description string
-	Mode        string
-	ExecuteAt   *time.Time
-	OrgIDs      []uint
-	Items       []validatedOJTaskItem
+	Title            string
+	Description      string
+	Mode             string
+	ExecuteAt        *time.Time
+	RecurrenceRule   string
+	RecurrenceEndAt  *time.Time
+	NextOccurrenceAt *time.Time
+	DeadlineAt       *time.Time
+	DeadlineMinutes  int
+	OrgIDs           []uint
+	Items            []validatedOJTaskItem
 }
 
 // OJTaskService OJ 任务业务编排服务。
@@ -58,7 +63,12 @@ type OJTaskService struct {
 	luoguUserQuestionRepo    interfaces.LuoguUserQuestionRepository
 	leetcodeUserQuestionRepo interfaces.LeetcodeUserQuestionRepository
 	lanqiaoUserQuestionRepo  interfaces.LanqiaoUserQuestionRepository
+	providerQuestionRepo     interfaces.OJProviderQuestionBankRepository
+	providerDetailRepo       interfaces.OJProviderUserDetailRepository
+	providerUserQuestionRepo interfaces.OJProviderUserQuestionRepository
+	questionBankRepo         interfaces.OJQuestionBankRepository
 	triggerPublisher         ojTaskExecutionTriggerEventPublisher
+	deadlineNotifier         ojTaskDeadlineNotifier
 	authorizationService     svccontract.AuthorizationServiceContract
 	analysisTokenCodec       *ojTaskAnalysisTokenCodec
 }
@@ -83,9 +93,14 @@ func NewOJTaskService(
 		luoguUserQuestionRepo:    repositoryGroup.SystemRepositorySupplier.GetLuoguUserQuestionRepository(),
 		leetcodeUserQuestionRepo: repositoryGroup.SystemRepositorySupplier.GetLeetcodeUserQuestionRepository(),
 		lanqiaoUserQuestionRepo:  repositoryGroup.SystemRepositorySupplier.GetLanqiaoUserQuestionRepository(),
+		providerQuestionRepo:     repositoryGroup.SystemRepositorySupplier.GetOJProviderQuestionBankRepository(),
+		providerDetailRepo:       repositoryGroup.SystemRepositorySupplier.GetOJProviderUserDetailRepository(),
+		providerUserQuestionRepo: repositoryGroup.SystemRepositorySupplier.GetOJProviderUserQuestionRepository(),
+		questionBankRepo:         repositoryGroup.SystemRepositorySupplier.GetOJQuestionBankRepository(),
 		triggerPublisher: newOJTaskExecutionTriggerOutboxPublisher(
 			repositoryGroup.SystemRepositorySupplier.GetOutboxRepository(),
 		),
+		deadlineNotifier:     newOJTaskMailStreamNotifier(),
 		authorizationService: authorizationService,
 		analysisTokenCodec:   newOJTaskAnalysisTokenCodec(),
 	}
@@ -97,7 +112,19 @@ func (s *OJTaskService) CreateTask(
 	req *request.CreateOJTaskReq,
 ) (*dtoresp.OJTaskCreateResp, error) {
 	// 预检任务草稿
-	draft, err := s.validateDraft(ctx, req.Title, req.Description, req.Mode, req.ExecuteAt, req.OrgIDs, req.Items)
+	draft, err := s.validateDraft(
+		ctx,
+		req.Title,
+		req.Description,
+		req.Mode,
+		req.ExecuteAt,
+		req.RecurrenceRule,
+		req.RecurrenceEndAt,
+		req.DeadlineAt,
+		req.DeadlineMinutes,
+		req.OrgIDs,
+		req.Items,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -143,7 +170,19 @@ func (s *OJTaskService) UpdateTask(
 	operatorID, taskID uint,
 	req *request.UpdateOJTaskReq,
 ) error {
-	draft, err := s.validateDraft(ctx, req.Title, req.Description, req.Mode, req.ExecuteAt, req.OrgIDs, req.Items)
+	draft, err := s.validateDraft(
+		ctx,
+		req.Title,
+		req.Description,
+		req.Mode,
+		req.ExecuteAt,
+		req.RecurrenceRule,
+		req.RecurrenceEndAt,
+		req.DeadlineAt,
+		req.DeadlineMinutes,
+		req.OrgIDs,
+		req.Items,
+	)
 	if err != nil {
 		return err
 	}
@@ -159,6 +198,9 @@ func (s *OJTaskService) UpdateTask(
 		if err := s.authorizeManageOrgIDs(ctx, operatorID, mergeUintSlices(currentOrgIDs, draft.OrgIDs)); err != nil {
 			return err
 		}
+		if draft.Mode != task.Mode {
+			return bizerrors.NewWithMsg(bizerrors.CodeOJTaskNotEditable, "任务模式不可变更，请通过修订创建新版本")
+		}
 		if draft.ExecuteAt == nil {
 			return bizerrors.New(bizerrors.CodeOJTaskExecuteAtInvalid)
 		}
@@ -170,6 +212,11 @@ func (s *OJTaskService) UpdateTask(
 		task.Title = materializedDraft.Title
 		task.Description = materializedDraft.Description
 		task.ExecuteAt = materializedDraft.ExecuteAt
+		task.RecurrenceRule = materializedDraft.RecurrenceRule
+		task.RecurrenceEndAt = materializedDraft.RecurrenceEndAt
+		task.NextOccurrenceAt = materializedDraft.NextOccurrenceAt
+		task.DeadlineAt = materializedDraft.DeadlineAt
+		task.DeadlineMinutes = materializedDraft.DeadlineMinutes
 		task.UpdatedBy = operatorID
 		if err := txTaskRepo.Update(ctx, task); err != nil {
 			return bizerrors.Wrap(bizerrors.CodeDBError, err)
@@ -199,7 +246,7 @@ func (s *OJTaskService) DeleteTask(ctx context.Context, operatorID, taskID uint)
 		txTaskRepo := s.taskRepo.WithTx(tx)
 		txExecutionRepo := s.executionRepo.WithTx(tx)
 
-		task, execution, currentOrgIDs, err := s.loadEditableScheduledTaskTx(ctx, txTaskRepo, txExecutionRepo, taskID)
+		task, execution, currentOrgIDs, err := s.loadDeletableTaskTx(ctx, txTaskRepo, txExecutionRepo, taskID)
 		if err != nil {
 			return err
 		}
@@ -209,14 +256,18 @@ func (s *OJTaskService) DeleteTask(ctx context.Context, operatorID, taskID uint)
 
 		now := time.Now()
 		task.Status = string(consts.OJTaskStatusDeleted)
+		task.NextOccurrenceAt = nil
 		task.UpdatedBy = operatorID
-		execution.Status = string(consts.OJTaskExecutionStatusCancelled)
-		execution.FinishedAt = &now
-		execution.ErrorMessage = ""
-
 		if err := txTaskRepo.Update(ctx, task); err != nil {
 			return bizerrors.Wrap(bizerrors.CodeDBError, err)
 		}
+		// 周期任务删除时，已完成的历史周期保留结果，仅取消尚未开始的周期。
+		if execution.Status != string(consts.OJTaskExecutionStatusScheduled) {
+			return nil
+		}
+		execution.Status = string(consts.OJTaskExecutionStatusCancelled)
+		execution.FinishedAt = &now
+		execution.ErrorMessage = ""
 		if err := txExecutionRepo.Update(ctx, execution); err != nil {
 			return bizerrors.Wrap(bizerrors.CodeDBError, err)
 		}
@@ -283,7 +334,19 @@ func (s *OJTaskService) ReviseTask(
 	operatorID, taskID uint,
 	req *request.ReviseOJTaskReq,
 ) (*dtoresp.OJTaskCreateResp, error) {
-	draft, err := s.validateDraft(ctx, req.Title, req.Description, req.Mode, req.ExecuteAt, req.OrgIDs, req.Items)
+	draft, err := s.validateDraft(
+		ctx,
+		req.Title,
+		req.Description,
+		req.Mode,
+		req.ExecuteAt,
+		req.RecurrenceRule,
+		req.RecurrenceEndAt,
+		req.DeadlineAt,
+		req.DeadlineMinutes,
+		req.OrgIDs,
+		req.Items,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -362,8 +425,10 @@ func (s *OJTaskService) RetryTask(
 		Title:       sourceTask.Title,
 		Description: sourceTask.Description,
 		Mode:        string(consts.OJTaskModeImmediate),
-		OrgIDs:      sourceOrgIDs,
-		Items:       taskItemsToValidated(items),
+		// 重试沿用源版本尚未到期的截止时间；周期任务重试为立即任务时不带截止。
+		DeadlineAt: retainableOJTaskDeadline(sourceTask, time.Now()),
+		OrgIDs:     sourceOrgIDs,
+		Items:      taskItemsToValidated(items),
 	}
 
 	var out *dtoresp.OJTaskCreateResp
@@ -459,6 +524,88 @@ func (s *OJTaskService) GetTaskVersions(
 	return resp, nil
 }
 
+// GetTaskOccurrences 返回任务版本最近若干个周期的执行汇总，以及每个用户跨周期的结果，
+// 用于对比同一份周期题单在不同周期的完成情况；非周期任务只会返回一个周期。
+func (s *OJTaskService) GetTaskOccurrences(
+	ctx context.Context,
+	userID, taskID uint,
+	req *request.OJTaskOccurrenceListReq,
+) (*dtoresp.OJTaskOccurrenceCompareResp, error) {
+	limit := defaultOJTaskOccurrenceLimit
+	if req != nil && req.Limit > 0 {
+		limit = req.Limit
+	}
+
+	row, _, err := s.getVisibleTaskOrError(ctx, userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	executions, err := s.executionRepo.ListByTaskID(ctx, taskID, limit)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	// 仓储按周期倒序取最近 N 个，对比视图按周期正序展示。
+	sort.Slice(executions, func(i, j int) bool {
+		return executions[i].OccurrenceNo < executions[j].OccurrenceNo
+	})
+
+	executionIDs := make([]uint, 0, len(executions))
+	occurrenceNoByExecution := make(map[uint]int, len(executions))
+	for _, execution := range executions {
+		executionIDs = append(executionIDs, execution.ID)
+		occurrenceNoByExecution[execution.ID] = execution.OccurrenceNo
+	}
+	users, err := s.executionRepo.ListExecutionUsersByExecutionIDs(ctx, executionIDs)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	resp := &dtoresp.OJTaskOccurrenceCompareResp{
+		TaskID:           row.TaskID,
+		Mode:             row.Mode,
+		RecurrenceRule:   row.RecurrenceRule,
+		RecurrenceEndAt:  formatTimePtr(row.RecurrenceEndAt),
+		NextOccurrenceAt: formatTimePtr(row.NextOccurrenceAt),
+		Occurrences:      make([]*dtoresp.OJTaskOccurrenceItemResp, 0, len(executions)),
+		Users:            make([]*dtoresp.OJTaskOccurrenceUserResp, 0),
+	}
+	for _, execution := range executions {
+		resp.Occurrences = append(resp.Occurrences, mapTaskOccurrence(execution))
+	}
+
+	userIndex := make(map[uint]*dtoresp.OJTaskOccurrenceUserResp)
+	for _, item := range users {
+		if item == nil {
+			continue
+		}
+		user, ok := userIndex[item.UserID]
+		if !ok {
+			user = &dtoresp.OJTaskOccurrenceUserResp{UserID: item.UserID}
+			userIndex[item.UserID] = user
+			resp.Users = append(resp.Users, user)
+		}
+		// 以最近一个周期的快照作为展示用户名和头像。
+		user.UsernameSnapshot = item.UsernameSnapshot
+		user.AvatarSnapshot = item.AvatarSnapshot
+		if item.AllCompleted {
+			user.CompletedOccurrenceCount++
+		}
+		user.Results = append(user.Results, &dtoresp.OJTaskOccurrenceUserResultResp{
+			ExecutionID:        item.ExecutionID,
+			OccurrenceNo:       occurrenceNoByExecution[item.ExecutionID],
+			CompletedItemCount: item.CompletedItemCount,
+			PendingItemCount:   item.PendingItemCount,
+			AllCompleted:       item.AllCompleted,
+		})
+	}
+	for _, user := range resp.Users {
+		sort.Slice(user.Results, func(i, j int) bool {
+			return user.Results[i].OccurrenceNo < user.Results[j].OccurrenceNo
+		})
+	}
+	return resp, nil
+}
+
 func (s *OJTaskService) GetTaskExecutionDetail(
 	ctx context.Context,
 	userID, taskID, executionID uint,
@@ -619,8 +766,14 @@ func (s *OJTaskService) createTaskVersionTx(
 		Mode:         draft.Mode,
 		Status:       taskStatus,
 		ExecuteAt:    draft.ExecuteAt,
-		CreatedBy:    operatorID,
-		UpdatedBy:    operatorID,
+		// 周期任务首个周期随版本一起创建为 scheduled 执行，NextOccurrenceAt 指向其后的周期。
+		RecurrenceRule:   draft.RecurrenceRule,
+		RecurrenceEndAt:  draft.RecurrenceEndAt,
+		NextOccurrenceAt: draft.NextOccurrenceAt,
+		DeadlineAt:       draft.DeadlineAt,
+		DeadlineMinutes:  draft.DeadlineMinutes,
+		CreatedBy:        operatorID,
+		UpdatedBy:        operatorID,
 	}
 	if err := txTaskRepo.Create(ctx, task); err != nil {
 		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
@@ -645,11 +798,12 @@ func (s *OJTaskService) createTaskVersionTx(
 	}
 
 	execution := &entity.OJTaskExecution{
-		TaskID:      task.ID,
-		TriggerType: string(trigger),
-		PlannedAt:   plannedAt,
-		RequestedBy: operatorID,
-		Status:      executionStatus,
+		TaskID:       task.ID,
+		OccurrenceNo: 1,
+		TriggerType:  string(trigger),
+		PlannedAt:    plannedAt,
+		RequestedBy:  operatorID,
+		Status:       executionStatus,
 	}
 	if err := txExecutionRepo.Create(ctx, execution); err != nil {
 		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
@@ -691,7 +845,7 @@ func (s *OJTaskService) loadEditableScheduledTaskTx(
 	if execution == nil {
 		return nil, nil, nil, bizerrors.New(bizerrors.CodeOJTaskExecutionNotFound)
 	}
-	if task.Mode != string(consts.OJTaskModeScheduled) ||
+	if !isEditableOJTaskMode(task.Mode) ||
 		task.Status != string(consts.OJTaskStatusScheduled) ||
 		execution.Status != string(consts.OJTaskExecutionStatusScheduled) {
 		return nil, nil, nil, bizerrors.New(bizerrors.CodeOJTaskNotEditable)
@@ -704,6 +858,52 @@ func (s *OJTaskService) loadEditableScheduledTaskTx(
 	return task, execution, taskOrgIDs(orgs), nil
 }
 
+// loadDeletableTaskTx 在可编辑规则之外，额外允许删除处于两个周期之间的周期任务：
+// 此时任务版本为 scheduled，最近一次周期已收口，删除只会停止后续周期的物化。
+func (s *OJTaskService) loadDeletableTaskTx(
+	ctx context.Context,
+	taskRepo interfaces.OJTaskRepository,
+	executionRepo interfaces.OJTaskExecutionRepository,
+	taskID uint,
+) (*entity.OJTask, *entity.OJTaskExecution, []uint, error) {
+	task, err := taskRepo.GetByIDForUpdate(ctx, taskID)
+	if err != nil {
+		return nil, nil, nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if task == nil {
+		return nil, nil, nil, bizerrors.New(bizerrors.CodeOJTaskNotFound)
+	}
+	if task.Status == string(consts.OJTaskStatusDeleted) {
+		return nil, nil, nil, bizerrors.New(bizerrors.CodeOJTaskDeleted)
+	}
+
+	execution, err := executionRepo.GetByTaskIDForUpdate(ctx, taskID)
+	if err != nil {
+		return nil, nil, nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if execution == nil {
+		return nil, nil, nil, bizerrors.New(bizerrors.CodeOJTaskExecutionNotFound)
+	}
+	editable := isEditableOJTaskMode(task.Mode) &&
+		execution.Status == string(consts.OJTaskExecutionStatusScheduled)
+	betweenOccurrences := task.Mode == string(consts.OJTaskModeRecurring) &&
+		isFinishedOJTaskExecutionStatus(execution.Status)
+	if task.Status != string(consts.OJTaskStatusScheduled) || (!editable && !betweenOccurrences) {
+		return nil, nil, nil, bizerrors.New(bizerrors.CodeOJTaskNotEditable)
+	}
+
+	orgs, err := taskRepo.ListOrgsByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, nil, nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return task, execution, taskOrgIDs(orgs), nil
+}
+
+// isEditableOJTaskMode 判断任务模式是否支持在执行前原地编辑。
+func isEditableOJTaskMode(mode string) bool {
+	return mode == string(consts.OJTaskModeScheduled) || mode == string(consts.OJTaskModeRecurring)
+}
+
 func (s *OJTaskService) loadTaskWithExecution(
 	ctx context.Context,
 	taskID uint,
@@ -844,10 +1044,14 @@ func (s *OJTaskService) executionUserOrgMap(
 }
 
 func resolveExecutionTrigger(mode string) consts.OJTaskExecutionTriggerType {
-	if mode == string(consts.OJTaskModeImmediate) {
+	switch mode {
+	case string(consts.OJTaskModeImmediate):
 		return consts.OJTaskExecutionTriggerCreateImmediate
+	case string(consts.OJTaskModeRecurring):
+		return consts.OJTaskExecutionTriggerRecurrenceDue
+	default:
+		return consts.OJTaskExecutionTriggerScheduleDue
 	}
-	return consts.OJTaskExecutionTriggerScheduleDue
 }
 
 func normalizeOJTaskListReq(req *request.OJTaskListReq) *request.OJTaskListReq {
@@ -1053,11 +1257,17 @@ func mapTaskListItem(row *readmodel.OJTaskListItem) *dtoresp.OJTaskListItemResp
 		Mode:               row.Mode,
 		Status:             row.Status,
 		ExecuteAt:          formatTimePtr(row.ExecuteAt),
+		RecurrenceRule:     row.RecurrenceRule,
+		RecurrenceEndAt:    formatTimePtr(row.RecurrenceEndAt),
+		NextOccurrenceAt:   formatTimePtr(row.NextOccurrenceAt),
+		DeadlineAt:         formatTimePtr(row.DeadlineAt),
+		DeadlineMinutes:    row.DeadlineMinutes,
 		CreatedBy:          row.CreatedBy,
 		UpdatedBy:          row.UpdatedBy,
 		CreatedAt:          formatTime(row.CreatedAt),
 		UpdatedAt:          formatTime(row.UpdatedAt),
 		ExecutionID:        row.ExecutionID,
+		OccurrenceNo:       row.OccurrenceNo,
 		ExecutionStatus:    row.ExecutionStatus,
 		TotalUserCount:     row.TotalUserCount,
 		CompletedUserCount: row.CompletedUserCount,
@@ -1065,6 +1275,7 @@ func mapTaskListItem(row *readmodel.OJTaskListItem) *dtoresp.OJTaskListItemResp
 		TotalItemCount:     row.TotalItemCount,
 		CompletedItemCount: row.CompletedItemCount,
 		PendingItemCount:   row.PendingItemCount,
+		LateItemCount:      row.LateItemCount,
 		OrgCount:           row.OrgCount,
 		ItemCount:          row.ItemCount,
 	}
@@ -1080,21 +1291,26 @@ func mapTaskDetail(
 	}
 
 	resp := &dtoresp.OJTaskDetailResp{
-		TaskID:       row.TaskID,
-		RootTaskID:   row.RootTaskID,
-		ParentTaskID: row.ParentTaskID,
-		VersionNo:    row.VersionNo,
-		Title:        row.Title,
-		Description:  row.Description,
-		Mode:         row.Mode,
-		Status:       row.Status,
-		ExecuteAt:    formatTimePtr(row.ExecuteAt),
-		CreatedBy:    row.CreatedBy,
-		UpdatedBy:    row.UpdatedBy,
-		CreatedAt:    formatTime(row.CreatedAt),
-		UpdatedAt:    formatTime(row.UpdatedAt),
-		Orgs:         make([]*dtoresp.OJTaskOrgItemResp, 0, len(orgs)),
-		Items:        make([]*dtoresp.OJTaskItemResp, 0, len(items)),
+		TaskID:           row.TaskID,
+		RootTaskID:       row.RootTaskID,
+		ParentTaskID:     row.ParentTaskID,
+		VersionNo:        row.VersionNo,
+		Title:            row.Title,
+		Description:      row.Description,
+		Mode:             row.Mode,
+		Status:           row.Status,
+		ExecuteAt:        formatTimePtr(row.ExecuteAt),
+		RecurrenceRule:   row.RecurrenceRule,
+		RecurrenceEndAt:  formatTimePtr(row.RecurrenceEndAt),
+		NextOccurrenceAt: formatTimePtr(row.NextOccurrenceAt),
+		DeadlineAt:       formatTimePtr(row.DeadlineAt),
+		DeadlineMinutes:  row.DeadlineMinutes,
+		CreatedBy:        row.CreatedBy,
+		UpdatedBy:        row.UpdatedBy,
+		CreatedAt:        formatTime(row.CreatedAt),
+		UpdatedAt:        formatTime(row.UpdatedAt),
+		Orgs:             make([]*dtoresp.OJTaskOrgItemResp, 0, len(orgs)),
+		Items:            make([]*dtoresp.OJTaskItemResp, 0, len(items)),
 	}
 
 	for _, org := range orgs {
@@ -1131,17 +1347,20 @@ func mapTaskVersion(item *readmodel.OJTaskVersionItem) *dtoresp.OJTaskVersionIte
 		return nil
 	}
 	return &dtoresp.OJTaskVersionItemResp{
-		TaskID:          item.TaskID,
-		RootTaskID:      item.RootTaskID,
-		ParentTaskID:    item.ParentTaskID,
-		VersionNo:       item.VersionNo,
-		Title:           item.Title,
-		Mode:            item.Mode,
-		Status:          item.Status,
-		ExecuteAt:       formatTimePtr(item.ExecuteAt),
-		CreatedAt:       formatTime(item.CreatedAt),
-		ExecutionID:     item.ExecutionID,
-		ExecutionStatus: item.ExecutionStatus,
+		TaskID:           item.TaskID,
+		RootTaskID:       item.RootTaskID,
+		ParentTaskID:     item.ParentTaskID,
+		VersionNo:        item.VersionNo,
+		Title:            item.Title,
+		Mode:             item.Mode,
+		Status:           item.Status,
+		ExecuteAt:        formatTimePtr(item.ExecuteAt),
+		RecurrenceRule:   item.RecurrenceRule,
+		NextOccurrenceAt: formatTimePtr(item.NextOccurrenceAt),
+		CreatedAt:        formatTime(item.CreatedAt),
+		ExecutionID:      item.ExecutionID,
+		OccurrenceNo:     item.OccurrenceNo,
+		ExecutionStatus:  item.ExecutionStatus,
 	}
 }
 
@@ -1152,6 +1371,7 @@ func mapExecutionResp(row *readmodel.OJTaskVisibleTask) *dtoresp.OJTaskExecution
 	return &dtoresp.OJTaskExecutionResp{
 		ExecutionID:        row.ExecutionID,
 		TaskID:             row.TaskID,
+		OccurrenceNo:       row.OccurrenceNo,
 		TriggerType:        row.TriggerType,
 		RequestedBy:        row.RequestedBy,
 		Status:             row.ExecutionStatus,
@@ -1165,6 +1385,33 @@ func mapExecutionResp(row *readmodel.OJTaskVisibleTask) *dtoresp.OJTaskExecution
 		TotalItemCount:     row.TotalItemCount,
 		CompletedItemCount: row.CompletedItemCount,
 		PendingItemCount:   row.PendingItemCount,
+		LateItemCount:      row.LateItemCount,
+		DeadlineAt:         formatTimePtr(row.ExecutionDeadlineAt),
+		ResultFrozenAt:     formatTimePtr(row.ResultFrozenAt),
+	}
+}
+
+func mapTaskOccurrence(execution *entity.OJTaskExecution) *dtoresp.OJTaskOccurrenceItemResp {
+	if execution == nil {
+		return nil
+	}
+	completionRate := 0.0
+	if execution.TotalUserCount > 0 {
+		completionRate = float64(execution.CompletedUserCount) / float64(execution.TotalUserCount)
+	}
+	return &dtoresp.OJTaskOccurrenceItemResp{
+		ExecutionID:        execution.ID,
+		OccurrenceNo:       execution.OccurrenceNo,
+		Status:             execution.Status,
+		TriggerType:        execution.TriggerType,
+		PlannedAt:          formatTime(execution.PlannedAt),
+		FinishedAt:         formatTimePtr(execution.FinishedAt),
+		TotalUserCount:     execution.TotalUserCount,
+		CompletedUserCount: execution.CompletedUserCount,
+		PendingUserCount:   execution.PendingUserCount,
+		TotalItemCount:     execution.TotalItemCount,
+		CompletedItemCount: execution.CompletedItemCount,
+		CompletionRate:     completionRate,
 	}
 }
 
@@ -1183,6 +1430,7 @@ func mapExecutionUserSummary(
 		UserStatusSnapshot: row.UserStatusSnapshot,
 		CompletedItemCount: row.CompletedItemCount,
 		PendingItemCount:   row.PendingItemCount,
+		LateItemCount:      row.LateItemCount,
 		AllCompleted:       row.AllCompleted,
 		Orgs:               orgs,
 	}
@@ -1205,9 +1453,11 @@ func mapExecutionUserDetail(
 		UserStatusSnapshot: row.UserStatusSnapshot,
 		CompletedItemCount: row.CompletedItemCount,
 		PendingItemCount:   row.PendingItemCount,
+		LateItemCount:      row.LateItemCount,
 		AllCompleted:       row.AllCompleted,
 		Orgs:               make([]*dtoresp.OJTaskExecutionUserOrgResp, 0, len(orgs)),
 		CompletedItems:     make([]*dtoresp.OJTaskExecutionUserItemResp, 0),
+		LateItems:          make([]*dtoresp.OJTaskExecutionUserItemResp, 0),
 		PendingItems:       make([]*dtoresp.OJTaskExecutionUserItemResp, 0),
 	}
 
@@ -1225,9 +1475,13 @@ func mapExecutionUserDetail(
 		if mapped == nil {
 			continue
 		}
-		if mapped.ResultStatus == string(consts.OJTaskExecutionUserItemResultCompleted) {
+		switch mapped.ResultStatus {
+		case string(consts.OJTaskExecutionUserItemResultCompleted):
 			resp.CompletedItems = append(resp.CompletedItems, mapped)
 			continue
+		case string(consts.OJTaskExecutionUserItemResultCompletedLate):
+			resp.LateItems = append(resp.LateItems, mapped)
+			continue
 		}
 		resp.PendingItems = append(resp.PendingItems, mapped)
 	}
@@ -1250,6 +1504,7 @@ func mapExecutionUserItem(item *readmodel.OJTaskExecutionUserItemDetail) *dtores
 		ResolvedTitleSnapshot: item.ResolvedTitleSnapshot,
 		ResultStatus:          item.ResultStatus,
 		Reason:                item.Reason,
+		CompletedAt:           formatTimePtr(item.CompletedAt),
 	}
 }
 
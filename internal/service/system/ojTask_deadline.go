@@ -0,0 +1,581 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"personal_assistant/global"
+	streamsse "personal_assistant/internal/infrastructure/sse"
+	"personal_assistant/internal/model/consts"
+	dtoresp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	bizerrors "personal_assistant/pkg/errors"
+	"personal_assistant/pkg/util"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// ojTaskDeadlineTrackBatchSize 是单轮截止跟踪最多处理的执行数。
+	ojTaskDeadlineTrackBatchSize = 50
+	// ojTaskDeadlineMaxMinutes 是周期任务单个周期完成时限的上限（30 天），与请求校验保持一致。
+	ojTaskDeadlineMaxMinutes = 30 * 24 * 60
+	// defaultOJTaskDeadlineReminderLead 是距截止多久开始提醒的默认值。
+	defaultOJTaskDeadlineReminderLead = 24 * time.Hour
+	// defaultOJTaskDeadlineReminderInterval 是同一执行两次提醒之间的默认最小间隔。
+	defaultOJTaskDeadlineReminderInterval = 6 * time.Hour
+	// defaultOJTaskLateTrackingDays 是截止后继续标记逾期完成的默认天数。
+	defaultOJTaskLateTrackingDays = 7
+)
+
+// ojTaskNotice 是一条待送达用户的任务通知。
+type ojTaskNotice struct {
+	EventName string
+	Deadline  time.Time
+	Payload   *dtoresp.OJTaskNoticeResp
+}
+
+// ojTaskDeadlineNotifier 负责把截止提醒与结果冻结通知送达用户。
+type ojTaskDeadlineNotifier interface {
+	Notify(ctx context.Context, user *entity.User, notice *ojTaskNotice) error
+}
+
+// ojTaskMailStreamNotifier 通过用户通知频道推送 SSE，截止提醒额外发送邮件。
+// SSE 发布复用比赛榜单的“回放流 + 背板”链路，离线用户重连后可按 Last-Event-ID 补齐。
+type ojTaskMailStreamNotifier struct {
+	stream ojContestScoreboardPublisher
+}
+
+func newOJTaskMailStreamNotifier() ojTaskDeadlineNotifier {
+	return &ojTaskMailStreamNotifier{stream: newOJContestStreamPublisher()}
+}
+
+func (n *ojTaskMailStreamNotifier) Notify(ctx context.Context, user *entity.User, notice *ojTaskNotice) error {
+	if user == nil || notice == nil || notice.Payload == nil {
+		return nil
+	}
+	data, err := json.Marshal(notice.Payload)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+	joined := n.stream.Publish(ctx, &streamsse.StreamEvent{
+		StreamKind: streamsse.StreamKindChannel,
+		Channel:    consts.OJTaskNoticeChannel(user.ID),
+		EventName:  notice.EventName,
+		Data:       data,
+		OccurredAt: time.Now(),
+		Durable:    true,
+	})
+	// 结果冻结只走站内推送；邮件只用于截止前催办，避免截止时群发打扰已完成的用户。
+	if notice.EventName == consts.OJTaskNoticeEventDeadlineReminder && strings.TrimSpace(user.Email) != "" {
+		subject, body := buildOJTaskDeadlineReminderEmail(user, notice)
+		if err := util.Email(user.Email, subject, body); err != nil {
+			joined = stderrors.Join(joined, err)
+		}
+	}
+	return joined
+}
+
+// buildOJTaskDeadlineReminderEmail 生成截止提醒邮件，时间按北京时间展示。
+func buildOJTaskDeadlineReminderEmail(user *entity.User, notice *ojTaskNotice) (string, string) {
+	websiteTitle := ""
+	if global.Config != nil {
+		websiteTitle = global.Config.Website.Title
+	}
+	title := html.EscapeString(notice.Payload.Title)
+	deadline := notice.Deadline.In(ojDailyStatsLocation()).Format("2006-01-02 15:04")
+	subject := "OJ 任务即将截止：" + notice.Payload.Title
+	body := `亲爱的用户[` + html.EscapeString(user.Username) + `]，<br/>
+<br/>
+您在任务「` + title + `」中还有 <b>` + fmt.Sprint(notice.Payload.PendingItemCount) + `</b> 道题目未完成，截止时间为 ` + deadline + `（北京时间）。<br/>
+截止后才完成的题目会被记为逾期完成，请尽快完成。<br/>
+<br/>
+祝好，<br/>` + websiteTitle + `<br/>
+<br/>`
+	return subject, body
+}
+
+// TrackExecutionDeadlines 推进设有截止时间的执行：
+//  1. 截止前复评未完成题目，新完成的题目计入完成统计，并在提醒窗口内通知仍未完成的用户；
+//  2. 首次越过截止时按过题时间区分按时与逾期，冻结按时结果并通知执行命中的用户；
+//  3. 冻结后的逾期跟踪窗口内，新完成的题目一律标记为 completed_late，不再改变按时统计。
+//
+// 单个执行失败只记录日志，不影响同一轮中的其他执行。
+func (s *OJTaskService) TrackExecutionDeadlines(ctx context.Context) error {
+	now := time.Now().UTC()
+	rows, err := s.executionRepo.ListDeadlineTrackingExecutions(
+		ctx,
+		now,
+		now.Add(-resolveOJTaskLateTrackingWindow()),
+		ojTaskDeadlineTrackBatchSize,
+	)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	for _, execution := range rows {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.trackExecutionDeadline(ctx, execution, now); err != nil {
+			global.Log.Error("跟踪 OJTask 执行截止失败", zap.Uint("execution_id", execution.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// StreamTaskNotices 订阅当前用户的任务通知频道。
+func (s *OJTaskService) StreamTaskNotices(
+	ctx context.Context,
+	userID uint,
+	lastEventID string,
+	writer streamsse.StreamWriter,
+) error {
+	if userID == 0 {
+		return bizerrors.New(bizerrors.CodeInvalidParams)
+	}
+	infra := global.StreamInfra
+	if infra == nil || infra.Broker == nil {
+		return bizerrors.NewWithMsg(bizerrors.CodeInternalError, "SSE 未启用")
+	}
+	handler := &streamsse.ChannelStreamHandler{
+		Broker:     infra.Broker,
+		Replay:     infra.ReplayStore,
+		Authorizer: &streamsse.AllowAllAuthorizer{},
+		Policy:     infra.Policy,
+	}
+	return handler.Serve(ctx, streamsse.ConnectRequest{
+		StreamKind:  streamsse.StreamKindChannel,
+		Channel:     consts.OJTaskNoticeChannel(userID),
+		SubjectID:   uint64(userID),
+		LastEventID: lastEventID,
+	}, writer)
+}
+
+// trackExecutionDeadline 对单个执行做一次复评、冻结与提醒。
+func (s *OJTaskService) trackExecutionDeadline(ctx context.Context, execution *entity.OJTaskExecution, now time.Time) error {
+	if execution == nil || execution.DeadlineAt == nil {
+		return nil
+	}
+	// 与调度器共用执行级锁，避免多实例同时复评同一执行导致统计重复累加。
+	lock, contended, err := acquireOJTaskExecutionLock(ctx, execution.ID)
+	if err != nil {
+		return err
+	}
+	if contended {
+		return nil
+	}
+	if lock != nil {
+		defer releaseOJTaskExecutionLock(execution.ID, lock)
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, execution.TaskID)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if task == nil {
+		return nil
+	}
+	pendingItems, err := s.executionRepo.ListUserItemsByResultStatus(
+		ctx,
+		execution.ID,
+		string(consts.OJTaskExecutionUserItemResultPending),
+	)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	changedItems, err := s.reevaluatePendingItems(ctx, task, execution, pendingItems, now)
+	if err != nil {
+		return err
+	}
+
+	deadline := *execution.DeadlineAt
+	freezing := execution.ResultFrozenAt == nil && !now.Before(deadline)
+	var users []*entity.OJTaskExecutionUser
+	err = s.txRunner.InTx(ctx, func(tx any) error {
+		txExecutionRepo := s.executionRepo.WithTx(tx)
+
+		var innerErr error
+		users, innerErr = txExecutionRepo.ListExecutionUsersByExecutionID(ctx, execution.ID)
+		if innerErr != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, innerErr)
+		}
+		userMap := make(map[uint]*entity.OJTaskExecutionUser, len(users))
+		for _, user := range users {
+			if user != nil {
+				userMap[user.ID] = user
+			}
+		}
+
+		touchedUsers := make(map[uint]*entity.OJTaskExecutionUser)
+		for _, item := range changedItems {
+			if err := txExecutionRepo.UpdateUserItemResult(ctx, item); err != nil {
+				return bizerrors.Wrap(bizerrors.CodeDBError, err)
+			}
+			user := userMap[item.ExecutionUserID]
+			if user == nil || item.ResultStatus == string(consts.OJTaskExecutionUserItemResultPending) {
+				continue
+			}
+			applyOJTaskItemCompletion(execution, user, item.ResultStatus)
+			touchedUsers[user.ID] = user
+		}
+		for _, user := range touchedUsers {
+			if err := txExecutionRepo.UpdateExecutionUserProgress(ctx, user); err != nil {
+				return bizerrors.Wrap(bizerrors.CodeDBError, err)
+			}
+		}
+
+		if freezing {
+			frozenAt := now
+			execution.ResultFrozenAt = &frozenAt
+		}
+		if len(touchedUsers) == 0 && !freezing {
+			return nil
+		}
+		if err := txExecutionRepo.Update(ctx, execution); err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case freezing:
+		s.notifyExecutionUsers(ctx, task, execution, users, consts.OJTaskNoticeEventResultFrozen)
+	case shouldRemindOJTaskDeadline(execution, now):
+		claimed, err := s.executionRepo.ClaimDeadlineReminder(
+			ctx,
+			execution.ID,
+			now.Add(-resolveOJTaskDeadlineReminderInterval()),
+			now,
+		)
+		if err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		if !claimed {
+			return nil
+		}
+		pendingUsers := make([]*entity.OJTaskExecutionUser, 0, len(users))
+		for _, user := range users {
+			if user != nil && user.PendingItemCount > 0 {
+				pendingUsers = append(pendingUsers, user)
+			}
+		}
+		s.notifyExecutionUsers(ctx, task, execution, pendingUsers, consts.OJTaskNoticeEventDeadlineReminder)
+	}
+	return nil
+}
+
+// reevaluatePendingItems 用当前做题事实复评 pending 明细，返回结果或原因发生变化的明细。
+// 冻结前按过题时间判断是否按时完成；冻结后新完成的题目一律记为逾期。
+func (s *OJTaskService) reevaluatePendingItems(
+	ctx context.Context,
+	task *entity.OJTask,
+	execution *entity.OJTaskExecution,
+	pendingItems []*entity.OJTaskExecutionUserItem,
+	now time.Time,
+) ([]*entity.OJTaskExecutionUserItem, error) {
+	if len(pendingItems) == 0 {
+		return nil, nil
+	}
+	taskItems, err := s.taskRepo.ListItemsByTaskID(ctx, task.ID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	taskItemMap := make(map[uint]*entity.OJTaskItem, len(taskItems))
+	for _, item := range taskItems {
+		if item != nil {
+			taskItemMap[item.ID] = item
+		}
+	}
+	userSet := make(map[uint]uint)
+	for _, row := range pendingItems {
+		userSet[row.UserID] = row.UserID
+	}
+	facts, err := s.loadExecutionFacts(ctx, uintMapKeys(userSet), taskItems)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make([]*entity.OJTaskExecutionUserItem, 0)
+	completedByPlatform := make(map[string][]*entity.OJTaskExecutionUserItem)
+	for _, row := range pendingItems {
+		taskItem := taskItemMap[row.TaskItemID]
+		if taskItem == nil {
+			continue
+		}
+		resultStatus, reason := facts.resolve(row.UserID, taskItem)
+		if resultStatus == string(consts.OJTaskExecutionUserItemResultPending) {
+			// 原因变化（如补绑账号后由 account_unbound 变为 unsolved）也同步，便于用户知道卡在哪里。
+			if reason != row.Reason {
+				row.Reason = reason
+				changed = append(changed, row)
+			}
+			continue
+		}
+		completedByPlatform[taskItem.Platform] = append(completedByPlatform[taskItem.Platform], row)
+	}
+
+	for platform, rows := range completedByPlatform {
+		solvedAt, err := s.loadOJTaskSolvedAt(ctx, facts, platform, rows, taskItemMap, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			completedAt := now
+			if at, ok := solvedAt[row.UserID][taskItemMap[row.TaskItemID].ResolvedQuestionID]; ok {
+				completedAt = at.UTC()
+			}
+			row.ResultStatus = string(consts.OJTaskExecutionUserItemResultCompleted)
+			if execution.ResultFrozenAt != nil || completedAt.After(*execution.DeadlineAt) {
+				row.ResultStatus = string(consts.OJTaskExecutionUserItemResultCompletedLate)
+			}
+			row.Reason = ""
+			row.CompletedAt = &completedAt
+			changed = append(changed, row)
+		}
+	}
+	return changed, nil
+}
+
+// loadOJTaskSolvedAt 读取指定平台上一批明细的最早过题时间，结果为 userID -> questionID -> solvedAt。
+// 洛谷与 LeetCode 以关联记录创建时间近似过题时间，与比赛榜单口径一致。
+func (s *OJTaskService) loadOJTaskSolvedAt(
+	ctx context.Context,
+	facts *ojTaskExecutionFacts,
+	platform string,
+	rows []*entity.OJTaskExecutionUserItem,
+	taskItemMap map[uint]*entity.OJTaskItem,
+	end time.Time,
+) (map[uint]map[uint]time.Time, error) {
+	detailUsers := facts.detailUsers(platform)
+	questionSet := make(map[uint]uint, len(rows))
+	for _, row := range rows {
+		if item := taskItemMap[row.TaskItemID]; item != nil && item.ResolvedQuestionID > 0 {
+			questionSet[item.ResolvedQuestionID] = item.ResolvedQuestionID
+		}
+	}
+	detailIDs := uintMapKeys(detailUsers)
+	questionIDs := uintMapKeys(questionSet)
+	start := time.Unix(0, 0).UTC()
+
+	store, ok := s.platformStores().lookup(platform)
+	if !ok {
+		return map[uint]map[uint]time.Time{}, nil
+	}
+	solved, err := store.ListSolvedAtInRange(ctx, detailIDs, questionIDs, start, end)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	out := make(map[uint]map[uint]time.Time)
+	for _, row := range solved {
+		userID, ok := detailUsers[row.DetailID]
+		if !ok {
+			continue
+		}
+		if out[userID] == nil {
+			out[userID] = make(map[uint]time.Time)
+		}
+		if prev, exists := out[userID][row.QuestionID]; !exists || row.SolvedAt.Before(prev) {
+			out[userID][row.QuestionID] = row.SolvedAt
+		}
+	}
+	return out, nil
+}
+
+// applyOJTaskItemCompletion 把一道 pending 明细转为按时或逾期完成，并同步用户与执行两级汇总。
+// 只有全部按时完成的用户计入 CompletedUserCount；逾期补完的用户只移出 PendingUserCount。
+func applyOJTaskItemCompletion(
+	execution *entity.OJTaskExecution,
+	user *entity.OJTaskExecutionUser,
+	resultStatus string,
+) {
+	if user.PendingItemCount <= 0 {
+		return
+	}
+	user.PendingItemCount--
+	execution.PendingItemCount--
+	if resultStatus == string(consts.OJTaskExecutionUserItemResultCompletedLate) {
+		user.LateItemCount++
+		execution.LateItemCount++
+	} else {
+		user.CompletedItemCount++
+		execution.CompletedItemCount++
+	}
+	user.AllCompleted = user.PendingItemCount == 0 && user.LateItemCount == 0
+	if user.PendingItemCount == 0 {
+		execution.PendingUserCount--
+		if user.AllCompleted {
+			execution.CompletedUserCount++
+		}
+	}
+}
+
+// shouldRemindOJTaskDeadline 判断执行是否处于截止前的提醒窗口内且仍有未完成题目。
+func shouldRemindOJTaskDeadline(execution *entity.OJTaskExecution, now time.Time) bool {
+	if execution == nil || execution.DeadlineAt == nil || execution.PendingItemCount <= 0 {
+		return false
+	}
+	deadline := *execution.DeadlineAt
+	return now.Before(deadline) && !now.Before(deadline.Add(-resolveOJTaskDeadlineReminderLead()))
+}
+
+// notifyExecutionUsers 向执行用户逐个发送通知；单个用户送达失败只记录告警。
+func (s *OJTaskService) notifyExecutionUsers(
+	ctx context.Context,
+	task *entity.OJTask,
+	execution *entity.OJTaskExecution,
+	executionUsers []*entity.OJTaskExecutionUser,
+	eventName string,
+) {
+	if s.deadlineNotifier == nil || len(executionUsers) == 0 {
+		return
+	}
+	userIDs := make([]uint, 0, len(executionUsers))
+	for _, row := range executionUsers {
+		userIDs = append(userIDs, row.UserID)
+	}
+	users, err := s.userRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		global.Log.Warn("加载 OJTask 通知用户失败", zap.Uint("execution_id", execution.ID), zap.Error(err))
+		return
+	}
+	userMap := make(map[uint]*entity.User, len(users))
+	for _, user := range users {
+		if user != nil {
+			userMap[user.ID] = user
+		}
+	}
+
+	for _, row := range executionUsers {
+		user := userMap[row.UserID]
+		if user == nil {
+			continue
+		}
+		notice := &ojTaskNotice{
+			EventName: eventName,
+			Deadline:  *execution.DeadlineAt,
+			Payload: &dtoresp.OJTaskNoticeResp{
+				TaskID:             task.ID,
+				ExecutionID:        execution.ID,
+				OccurrenceNo:       execution.OccurrenceNo,
+				Title:              task.Title,
+				DeadlineAt:         formatTimePtr(execution.DeadlineAt),
+				TotalItemCount:     row.CompletedItemCount + row.PendingItemCount + row.LateItemCount,
+				CompletedItemCount: row.CompletedItemCount,
+				PendingItemCount:   row.PendingItemCount,
+				LateItemCount:      row.LateItemCount,
+				Frozen:             execution.ResultFrozenAt != nil,
+			},
+		}
+		if err := s.deadlineNotifier.Notify(ctx, user, notice); err != nil {
+			global.Log.Warn(
+				"发送 OJTask 通知失败",
+				zap.Uint("execution_id", execution.ID),
+				zap.Uint("user_id", user.ID),
+				zap.String("event", eventName),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// validateOJTaskDeadline 校验截止配置：立即与定时任务只接受绝对截止时间 deadline_at，
+// 周期任务只接受相对每个周期计划时间的 deadline_minutes；两者都不传表示不做截止跟踪。
+func validateOJTaskDeadline(
+	mode string,
+	executeAt, deadlineAt *time.Time,
+	deadlineMinutes int,
+	now time.Time,
+) (*time.Time, error) {
+	if mode == string(consts.OJTaskModeRecurring) {
+		if deadlineAt != nil {
+			return nil, bizerrors.NewWithMsg(
+				bizerrors.CodeOJTaskDeadlineInvalid,
+				"周期任务按 deadline_minutes 为每个周期设置截止，不允许传 deadline_at",
+			)
+		}
+		if deadlineMinutes < 0 || deadlineMinutes > ojTaskDeadlineMaxMinutes {
+			return nil, bizerrors.NewWithMsg(bizerrors.CodeOJTaskDeadlineInvalid, "deadline_minutes 超出允许范围")
+		}
+		return nil, nil
+	}
+	if deadlineMinutes != 0 {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeOJTaskDeadlineInvalid, "非周期任务不允许传 deadline_minutes")
+	}
+	if deadlineAt == nil {
+		return nil, nil
+	}
+	value := deadlineAt.UTC()
+	start := now
+	if executeAt != nil && executeAt.After(start) {
+		start = *executeAt
+	}
+	if !value.After(start) {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeOJTaskDeadlineInvalid, "deadline_at 必须晚于执行时间")
+	}
+	return &value, nil
+}
+
+// resolveOJTaskExecutionDeadline 推算某次执行的截止时间：周期任务为计划时间加完成时限，其余沿用任务截止时间。
+func resolveOJTaskExecutionDeadline(task *entity.OJTask, plannedAt time.Time) *time.Time {
+	if task == nil {
+		return nil
+	}
+	if task.Mode == string(consts.OJTaskModeRecurring) {
+		if task.DeadlineMinutes <= 0 {
+			return nil
+		}
+		value := plannedAt.Add(time.Duration(task.DeadlineMinutes) * time.Minute).UTC()
+		return &value
+	}
+	if task.DeadlineAt == nil {
+		return nil
+	}
+	value := task.DeadlineAt.UTC()
+	return &value
+}
+
+// retainableOJTaskDeadline 返回派生立即任务可沿用的截止时间：仅非周期任务且尚未到期时保留。
+func retainableOJTaskDeadline(task *entity.OJTask, now time.Time) *time.Time {
+	if task == nil || task.Mode == string(consts.OJTaskModeRecurring) || task.DeadlineAt == nil {
+		return nil
+	}
+	if !task.DeadlineAt.After(now) {
+		return nil
+	}
+	value := task.DeadlineAt.UTC()
+	return &value
+}
+
+// resolveOJTaskDeadlineReminderLead 返回距截止多久开始提醒。
+func resolveOJTaskDeadlineReminderLead() time.Duration {
+	if global.Config == nil || global.Config.Task.OJTaskDeadlineReminderLeadSeconds <= 0 {
+		return defaultOJTaskDeadlineReminderLead
+	}
+	return time.Duration(global.Config.Task.OJTaskDeadlineReminderLeadSeconds) * time.Second
+}
+
+// resolveOJTaskDeadlineReminderInterval 返回同一执行两次提醒之间的最小间隔。
+func resolveOJTaskDeadlineReminderInterval() time.Duration {
+	if global.Config == nil || global.Config.Task.OJTaskDeadlineReminderIntervalSeconds <= 0 {
+		return defaultOJTaskDeadlineReminderInterval
+	}
+	return time.Duration(global.Config.Task.OJTaskDeadlineReminderIntervalSeconds) * time.Second
+}
+
+// resolveOJTaskLateTrackingWindow 返回截止后继续标记逾期完成的时间窗口。
+func resolveOJTaskLateTrackingWindow() time.Duration {
+	days := defaultOJTaskLateTrackingDays
+	if global.Config != nil && global.Config.Task.OJTaskLateTrackingDays > 0 {
+		days = global.Config.Task.OJTaskLateTrackingDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
@@ -0,0 +1,312 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/entity"
+	reposystem "personal_assistant/internal/repository/system"
+	bizerrors "personal_assistant/pkg/errors"
+
+	"github.com/glebarez/sqlite"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type stubOJTaskDeadlineNotifier struct {
+	notices []stubOJTaskDeadlineNotice
+}
+
+type stubOJTaskDeadlineNotice struct {
+	userID uint
+	notice *ojTaskNotice
+}
+
+func (n *stubOJTaskDeadlineNotifier) Notify(_ context.Context, user *entity.User, notice *ojTaskNotice) error {
+	n.notices = append(n.notices, stubOJTaskDeadlineNotice{userID: user.ID, notice: notice})
+	return nil
+}
+
+func TestValidateOJTaskDeadline(t *testing.T) {
+	now := time.Date(2026, 3, 4, 2, 0, 0, 0, time.UTC)
+	future := now.Add(24 * time.Hour)
+	past := now.Add(-time.Hour)
+	executeAt := now.Add(48 * time.Hour)
+
+	got, err := validateOJTaskDeadline(string(consts.OJTaskModeImmediate), nil, &future, 0, now)
+	if err != nil || got == nil || !got.Equal(future) {
+		t.Fatalf("expected immediate deadline accepted, got %v %v", got, err)
+	}
+	got, err = validateOJTaskDeadline(string(consts.OJTaskModeRecurring), &executeAt, nil, 90, now)
+	if err != nil || got != nil {
+		t.Fatalf("expected recurring minutes accepted, got %v %v", got, err)
+	}
+
+	cases := []struct {
+		name      string
+		mode      string
+		executeAt *time.Time
+		deadline  *time.Time
+		minutes   int
+	}{
+		{name: "past deadline", mode: string(consts.OJTaskModeImmediate), deadline: &past},
+		{name: "before execute", mode: string(consts.OJTaskModeScheduled), executeAt: &executeAt, deadline: &future},
+		{name: "minutes on immediate", mode: string(consts.OJTaskModeImmediate), minutes: 30},
+		{name: "absolute on recurring", mode: string(consts.OJTaskModeRecurring), executeAt: &executeAt, deadline: &future},
+		{name: "minutes too large", mode: string(consts.OJTaskModeRecurring), executeAt: &executeAt, minutes: ojTaskDeadlineMaxMinutes + 1},
+	}
+	for _, tc := range cases {
+		if _, err := validateOJTaskDeadline(tc.mode, tc.executeAt, tc.deadline, tc.minutes, now); !hasBizCode(err, bizerrors.CodeOJTaskDeadlineInvalid) {
+			t.Fatalf("%s: expected deadline invalid, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestTrackExecutionDeadlinesCompletesAndReminds(t *testing.T) {
+	svc, db, notifier := newOJTaskDeadlineTestService(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	execution := seedOJTaskDeadlineExecution(t, db, now.Add(2*time.Hour))
+
+	// 用户 2 在截止前补完了题目，用户 3 仍未完成。
+	mustCreate(t, db, &entity.LeetcodeUserQuestion{
+		MODEL:                entity.MODEL{CreatedAt: now.Add(-10 * time.Minute)},
+		LeetcodeUserDetailID: ojTaskDeadlineDetailID(t, db, 2),
+		LeetcodeQuestionID:   100,
+	})
+
+	if err := svc.TrackExecutionDeadlines(ctx); err != nil {
+		t.Fatalf("TrackExecutionDeadlines() error = %v", err)
+	}
+
+	var reloaded entity.OJTaskExecution
+	if err := db.First(&reloaded, execution.ID).Error; err != nil {
+		t.Fatalf("reload execution: %v", err)
+	}
+	if reloaded.CompletedItemCount != 1 || reloaded.PendingItemCount != 1 || reloaded.LateItemCount != 0 ||
+		reloaded.CompletedUserCount != 1 || reloaded.PendingUserCount != 1 {
+		t.Fatalf("unexpected execution counters: %+v", reloaded)
+	}
+	if reloaded.ResultFrozenAt != nil || reloaded.LastRemindedAt == nil {
+		t.Fatalf("expected reminder without freeze, got frozen=%v reminded=%v", reloaded.ResultFrozenAt, reloaded.LastRemindedAt)
+	}
+
+	var item entity.OJTaskExecutionUserItem
+	if err := db.Where("execution_id = ? AND user_id = ?", execution.ID, 2).First(&item).Error; err != nil {
+		t.Fatalf("load user item: %v", err)
+	}
+	if item.ResultStatus != string(consts.OJTaskExecutionUserItemResultCompleted) || item.CompletedAt == nil {
+		t.Fatalf("expected user 2 item completed, got %+v", item)
+	}
+
+	if len(notifier.notices) != 1 || notifier.notices[0].userID != 3 ||
+		notifier.notices[0].notice.EventName != consts.OJTaskNoticeEventDeadlineReminder {
+		t.Fatalf("expected one reminder to user 3, got %+v", notifier.notices)
+	}
+
+	// 提醒间隔内再次跟踪不重复提醒。
+	if err := svc.TrackExecutionDeadlines(ctx); err != nil {
+		t.Fatalf("second TrackExecutionDeadlines() error = %v", err)
+	}
+	if len(notifier.notices) != 1 {
+		t.Fatalf("expected no repeated reminder, got %d notices", len(notifier.notices))
+	}
+}
+
+func TestTrackExecutionDeadlinesFreezesAndMarksLate(t *testing.T) {
+	svc, db, notifier := newOJTaskDeadlineTestService(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+	deadline := now.Add(-time.Hour)
+	execution := seedOJTaskDeadlineExecution(t, db, deadline)
+
+	// 用户 2 在截止前过题但同步滞后；用户 3 截止后才过题。
+	mustCreate(t, db, &entity.LeetcodeUserQuestion{
+		MODEL:                entity.MODEL{CreatedAt: deadline.Add(-30 * time.Minute)},
+		LeetcodeUserDetailID: ojTaskDeadlineDetailID(t, db, 2),
+		LeetcodeQuestionID:   100,
+	})
+	mustCreate(t, db, &entity.LeetcodeUserQuestion{
+		MODEL:                entity.MODEL{CreatedAt: now.Add(-10 * time.Minute)},
+		LeetcodeUserDetailID: ojTaskDeadlineDetailID(t, db, 3),
+		LeetcodeQuestionID:   100,
+	})
+
+	if err := svc.TrackExecutionDeadlines(ctx); err != nil {
+		t.Fatalf("TrackExecutionDeadlines() error = %v", err)
+	}
+
+	var reloaded entity.OJTaskExecution
+	if err := db.First(&reloaded, execution.ID).Error; err != nil {
+		t.Fatalf("reload execution: %v", err)
+	}
+	if reloaded.ResultFrozenAt == nil {
+		t.Fatal("expected execution result frozen")
+	}
+	if reloaded.CompletedItemCount != 1 || reloaded.LateItemCount != 1 || reloaded.PendingItemCount != 0 ||
+		reloaded.CompletedUserCount != 1 || reloaded.PendingUserCount != 0 {
+		t.Fatalf("unexpected execution counters: %+v", reloaded)
+	}
+
+	var lateUser entity.OJTaskExecutionUser
+	if err := db.Where("execution_id = ? AND user_id = ?", execution.ID, 3).First(&lateUser).Error; err != nil {
+		t.Fatalf("load late user: %v", err)
+	}
+	if lateUser.AllCompleted || lateUser.LateItemCount != 1 || lateUser.PendingItemCount != 0 {
+		t.Fatalf("unexpected late user summary: %+v", lateUser)
+	}
+	var lateItem entity.OJTaskExecutionUserItem
+	if err := db.Where("execution_id = ? AND user_id = ?", execution.ID, 3).First(&lateItem).Error; err != nil {
+		t.Fatalf("load late item: %v", err)
+	}
+	if lateItem.ResultStatus != string(consts.OJTaskExecutionUserItemResultCompletedLate) {
+		t.Fatalf("expected completed_late, got %q", lateItem.ResultStatus)
+	}
+
+	if len(notifier.notices) != 2 {
+		t.Fatalf("expected frozen notice for both users, got %+v", notifier.notices)
+	}
+	for _, row := range notifier.notices {
+		if row.notice.EventName != consts.OJTaskNoticeEventResultFrozen || !row.notice.Payload.Frozen {
+			t.Fatalf("unexpected notice: %+v", row.notice)
+		}
+	}
+
+	// 冻结且无待完成题目后不再被跟踪。
+	if err := svc.TrackExecutionDeadlines(ctx); err != nil {
+		t.Fatalf("second TrackExecutionDeadlines() error = %v", err)
+	}
+	if len(notifier.notices) != 2 {
+		t.Fatalf("expected no further notices, got %d", len(notifier.notices))
+	}
+}
+
+func newOJTaskDeadlineTestService(t *testing.T) (*OJTaskService, *gorm.DB, *stubOJTaskDeadlineNotifier) {
+	t.Helper()
+
+	oldConfig := global.Config
+	oldLog := global.Log
+	global.Config = &config.Config{}
+	global.Log = zap.NewNop()
+	t.Cleanup(func() {
+		global.Config = oldConfig
+		global.Log = oldLog
+	})
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&entity.User{},
+		&entity.OJTask{},
+		&entity.OJTaskItem{},
+		&entity.OJTaskExecution{},
+		&entity.OJTaskExecutionUser{},
+		&entity.OJTaskExecutionUserItem{},
+		&entity.LuoguUserDetail{},
+		&entity.LeetcodeUserDetail{},
+		&entity.LeetcodeUserQuestion{},
+		&entity.LanqiaoUserDetail{},
+		&entity.LanqiaoUserQuestion{},
+	); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+
+	notifier := &stubOJTaskDeadlineNotifier{}
+	svc := &OJTaskService{
+		txRunner:                 &stubTxRunner{},
+		taskRepo:                 reposystem.NewOJTaskRepository(db),
+		executionRepo:            reposystem.NewOJTaskExecutionRepository(db),
+		userRepo:                 reposystem.NewUserRepository(db),
+		luoguDetailRepo:          reposystem.NewLuoguUserDetailRepository(db),
+		leetcodeDetailRepo:       reposystem.NewLeetcodeUserDetailRepository(db),
+		lanqiaoDetailRepo:        reposystem.NewLanqiaoUserDetailRepository(db),
+		luoguUserQuestionRepo:    reposystem.NewLuoguUserQuestionRepository(db),
+		leetcodeUserQuestionRepo: reposystem.NewLeetcodeUserQuestionRepository(db),
+		lanqiaoUserQuestionRepo:  reposystem.NewLanqiaoUserQuestionRepository(db),
+		deadlineNotifier:         notifier,
+	}
+	return svc, db, notifier
+}
+
+// seedOJTaskDeadlineExecution 创建一道力扣 100 的立即任务执行，用户 2、3 都还未完成。
+func seedOJTaskDeadlineExecution(t *testing.T, db *gorm.DB, deadline time.Time) *entity.OJTaskExecution {
+	t.Helper()
+
+	task := &entity.OJTask{
+		VersionNo:  1,
+		Title:      "截止题单",
+		Mode:       string(consts.OJTaskModeImmediate),
+		Status:     string(consts.OJTaskStatusSucceeded),
+		DeadlineAt: &deadline,
+		CreatedBy:  1,
+		UpdatedBy:  1,
+	}
+	mustCreate(t, db, task)
+	taskItem := &entity.OJTaskItem{
+		TaskID:             task.ID,
+		SortNo:             1,
+		Platform:           consts.OJPlatformLeetcode,
+		InputTitle:         "Two Sum",
+		ResolvedQuestionID: 100,
+		ResolutionStatus:   string(consts.OJTaskItemResolutionStatusResolved),
+	}
+	mustCreate(t, db, taskItem)
+	execution := &entity.OJTaskExecution{
+		TaskID:           task.ID,
+		TriggerType:      string(consts.OJTaskExecutionTriggerCreateImmediate),
+		PlannedAt:        deadline.Add(-48 * time.Hour),
+		RequestedBy:      1,
+		Status:           string(consts.OJTaskExecutionStatusSucceeded),
+		TotalUserCount:   2,
+		PendingUserCount: 2,
+		TotalItemCount:   2,
+		PendingItemCount: 2,
+		DeadlineAt:       &deadline,
+	}
+	mustCreate(t, db, execution)
+
+	for _, userID := range []uint{2, 3} {
+		mustCreate(t, db, &entity.User{
+			MODEL:    entity.MODEL{ID: userID},
+			UUID:     uuid.Must(uuid.NewV4()),
+			Username: fmt.Sprintf("user%d", userID),
+			Phone:    fmt.Sprintf("1380000000%d", userID),
+			Email:    fmt.Sprintf("user%d@example.com", userID),
+			Status:   consts.UserStatusActive,
+		})
+		mustCreate(t, db, &entity.LeetcodeUserDetail{UserSlug: fmt.Sprintf("u%d", userID), UserID: userID})
+		executionUser := &entity.OJTaskExecutionUser{
+			ExecutionID:      execution.ID,
+			UserID:           userID,
+			UsernameSnapshot: fmt.Sprintf("user%d", userID),
+			PendingItemCount: 1,
+		}
+		mustCreate(t, db, executionUser)
+		mustCreate(t, db, &entity.OJTaskExecutionUserItem{
+			ExecutionID:     execution.ID,
+			UserID:          userID,
+			ExecutionUserID: executionUser.ID,
+			TaskItemID:      taskItem.ID,
+			ResultStatus:    string(consts.OJTaskExecutionUserItemResultPending),
+			Reason:          string(consts.OJTaskExecutionUserItemReasonUnsolved),
+		})
+	}
+	return execution
+}
+
+func ojTaskDeadlineDetailID(t *testing.T, db *gorm.DB, userID uint) uint {
+	t.Helper()
+	var detail entity.LeetcodeUserDetail
+	if err := db.Where("user_id = ?", userID).First(&detail).Error; err != nil {
+		t.Fatalf("load leetcode detail: %v", err)
+	}
+	return detail.ID
+}
@@ -0,0 +1,364 @@
+package system
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	dtoresp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	readmodel "personal_assistant/internal/model/readmodel"
+	bizerrors "personal_assistant/pkg/errors"
+)
+
+const (
+	// ojTaskExportBatchSize 是导出时每批读取的执行用户数，控制大组织导出时的内存占用。
+	ojTaskExportBatchSize = 200
+
+	ojTaskExportCSVContentType  = "text/csv; charset=utf-8"
+	ojTaskExportXLSXContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// ExportTaskExecution 导出一次执行的“用户 × 题目”结果矩阵。
+// 可见性与筛选条件和执行用户列表一致；权限与参数校验在这里同步完成，
+// 返回的 Write 再按批读取用户、组织快照与题目结果并边读边写，不把整次执行加载进内存。
+func (s *OJTaskService) ExportTaskExecution(
+	ctx context.Context,
+	userID, taskID, executionID uint,
+	req *request.OJTaskExecutionExportReq,
+) (*dtoresp.OJTaskExecutionExportFile, error) {
+	if req == nil {
+		req = &request.OJTaskExecutionExportReq{}
+	}
+	format := consts.OJTaskExportFormat(strings.ToLower(strings.TrimSpace(req.Format)))
+	if format == "" {
+		format = consts.OJTaskExportFormatCSV
+	}
+	contentType := ojTaskExportCSVContentType
+	switch format {
+	case consts.OJTaskExportFormatCSV:
+	case consts.OJTaskExportFormatXLSX:
+		contentType = ojTaskExportXLSXContentType
+	default:
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "format 仅支持 csv 或 xlsx")
+	}
+
+	if _, _, err := s.getVisibleExecutionOrError(ctx, userID, taskID, executionID); err != nil {
+		return nil, err
+	}
+	isSuperAdmin, err := s.isSuperAdmin(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	taskItems, err := s.taskRepo.ListItemsByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	filter := &request.OJTaskExecutionUserListReq{
+		AllCompleted: req.AllCompleted,
+		Username:     req.Username,
+	}
+	return &dtoresp.OJTaskExecutionExportFile{
+		FileName:    fmt.Sprintf("oj-task-%d-execution-%d.%s", taskID, executionID, format),
+		ContentType: contentType,
+		Write: func(w io.Writer) error {
+			sheet, err := newOJTaskExportSheetWriter(w, format)
+			if err != nil {
+				return err
+			}
+			if err := s.writeExecutionExport(ctx, sheet, userID, isSuperAdmin, taskID, executionID, filter, taskItems); err != nil {
+				return err
+			}
+			return sheet.Close()
+		},
+	}, nil
+}
+
+// writeExecutionExport 写出表头后按执行用户快照 ID 游标分批写出数据行。
+func (s *OJTaskService) writeExecutionExport(
+	ctx context.Context,
+	sheet ojTaskExportSheetWriter,
+	userID uint,
+	isSuperAdmin bool,
+	taskID, executionID uint,
+	filter *request.OJTaskExecutionUserListReq,
+	taskItems []*entity.OJTaskItem,
+) error {
+	if err := sheet.WriteRow(buildOJTaskExportHeader(taskItems)); err != nil {
+		return err
+	}
+
+	var afterID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rows, err := s.executionRepo.ListVisibleExecutionUsersAfter(
+			ctx,
+			userID,
+			isSuperAdmin,
+			taskID,
+			executionID,
+			filter,
+			afterID,
+			ojTaskExportBatchSize,
+		)
+		if err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		orgMap, err := s.executionUserOrgMap(ctx, rows)
+		if err != nil {
+			return err
+		}
+		executionUserIDs := make([]uint, 0, len(rows))
+		for _, row := range rows {
+			executionUserIDs = append(executionUserIDs, row.ExecutionUserID)
+		}
+		items, err := s.executionRepo.ListExecutionUserItemsByExecutionUserIDs(ctx, executionID, executionUserIDs)
+		if err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		itemMap := make(map[uint]map[uint]*readmodel.OJTaskExecutionUserItemDetail, len(rows))
+		for _, item := range items {
+			if item == nil {
+				continue
+			}
+			if itemMap[item.ExecutionUserID] == nil {
+				itemMap[item.ExecutionUserID] = make(map[uint]*readmodel.OJTaskExecutionUserItemDetail)
+			}
+			itemMap[item.ExecutionUserID][item.TaskItemID] = item
+		}
+
+		for _, row := range rows {
+			cells := buildOJTaskExportRow(row, orgMap[row.ExecutionUserID], itemMap[row.ExecutionUserID], taskItems)
+			if err := sheet.WriteRow(cells); err != nil {
+				return err
+			}
+		}
+		if len(rows) < ojTaskExportBatchSize {
+			return nil
+		}
+		afterID = rows[len(rows)-1].ExecutionUserID
+	}
+}
+
+// buildOJTaskExportHeader 生成表头：用户汇总列之后，每道题目占“状态 / 原因”两列。
+func buildOJTaskExportHeader(taskItems []*entity.OJTaskItem) []any {
+	header := []any{"用户ID", "用户名", "所属组织", "按时完成题数", "逾期完成题数", "未完成题数", "全部按时完成"}
+	for _, item := range taskItems {
+		label := fmt.Sprintf("%d. %s", item.SortNo, ojTaskExportItemTitle(item))
+		header = append(header, label+" 状态", label+" 原因")
+	}
+	return header
+}
+
+// buildOJTaskExportRow 生成单个用户的数据行；题目结果缺失时两列留空。
+func buildOJTaskExportRow(
+	row *readmodel.OJTaskExecutionUserListItem,
+	orgs []*dtoresp.OJTaskExecutionUserOrgResp,
+	items map[uint]*readmodel.OJTaskExecutionUserItemDetail,
+	taskItems []*entity.OJTaskItem,
+) []any {
+	orgNames := make([]string, 0, len(orgs))
+	for _, org := range orgs {
+		orgNames = append(orgNames, org.OrgNameSnapshot)
+	}
+	allCompleted := "否"
+	if row.AllCompleted {
+		allCompleted = "是"
+	}
+	cells := []any{
+		int(row.UserID),
+		row.UsernameSnapshot,
+		strings.Join(orgNames, "、"),
+		row.CompletedItemCount,
+		row.LateItemCount,
+		row.PendingItemCount,
+		allCompleted,
+	}
+	for _, taskItem := range taskItems {
+		item := items[taskItem.ID]
+		if item == nil {
+			cells = append(cells, "", "")
+			continue
+		}
+		cells = append(cells, item.ResultStatus, item.Reason)
+	}
+	return cells
+}
+
+func ojTaskExportItemTitle(item *entity.OJTaskItem) string {
+	if title := strings.TrimSpace(item.ResolvedTitleSnapshot); title != "" {
+		return title
+	}
+	return item.InputTitle
+}
+
+// ojTaskExportSheetWriter 是单工作表的逐行写出器，单元格取值为 string 或 int。
+type ojTaskExportSheetWriter interface {
+	WriteRow(cells []any) error
+	Close() error
+}
+
+func newOJTaskExportSheetWriter(w io.Writer, format consts.OJTaskExportFormat) (ojTaskExportSheetWriter, error) {
+	if format == consts.OJTaskExportFormatXLSX {
+		return newOJTaskXLSXSheetWriter(w)
+	}
+	return newOJTaskCSVSheetWriter(w)
+}
+
+// ojTaskCSVSheetWriter 写出带 UTF-8 BOM 的 CSV。
+type ojTaskCSVSheetWriter struct {
+	writer *csv.Writer
+}
+
+func newOJTaskCSVSheetWriter(w io.Writer) (*ojTaskCSVSheetWriter, error) {
+	// BOM 让 Excel 按 UTF-8 识别中文列名与用户名。
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	return &ojTaskCSVSheetWriter{writer: csv.NewWriter(w)}, nil
+}
+
+func (w *ojTaskCSVSheetWriter) WriteRow(cells []any) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		switch value := cell.(type) {
+		case int:
+			record[i] = strconv.Itoa(value)
+		default:
+			record[i] = escapeOJTaskCSVFormula(fmt.Sprint(value))
+		}
+	}
+	return w.writer.Write(record)
+}
+
+func (w *ojTaskCSVSheetWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// escapeOJTaskCSVFormula 给以公式字符开头的文本加单引号前缀，避免用户名等自由文本在表格软件中被当作公式执行。
+func escapeOJTaskCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// ojTaskXLSXSheetWriter 以最小 OOXML 包结构流式写出单工作表 XLSX。
+// 固定部件先写入压缩包，工作表部件按行追加，文本一律使用内联字符串，无需维护共享字符串表。
+type ojTaskXLSXSheetWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+}
+
+var ojTaskXLSXStaticParts = []struct {
+	name    string
+	content string
+}{
+	{
+		name: "[Content_Types].xml",
+		content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`,
+	},
+	{
+		name: "_rels/.rels",
+		content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		name: "xl/workbook.xml",
+		content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="执行结果" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`,
+	},
+	{
+		name: "xl/_rels/workbook.xml.rels",
+		content: `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`,
+	},
+}
+
+func newOJTaskXLSXSheetWriter(w io.Writer) (*ojTaskXLSXSheetWriter, error) {
+	archive := zip.NewWriter(w)
+	for _, part := range ojTaskXLSXStaticParts {
+		entry, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(entry, part.content); err != nil {
+			return nil, err
+		}
+	}
+	entry, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(entry)
+	if _, err := sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+	return &ojTaskXLSXSheetWriter{archive: archive, sheet: sheet}, nil
+}
+
+func (w *ojTaskXLSXSheetWriter) WriteRow(cells []any) error {
+	if _, err := w.sheet.WriteString("<row>"); err != nil {
+		return err
+	}
+	for _, cell := range cells {
+		switch value := cell.(type) {
+		case int:
+			if _, err := fmt.Fprintf(w.sheet, "<c><v>%d</v></c>", value); err != nil {
+				return err
+			}
+		default:
+			if _, err := w.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`); err != nil {
+				return err
+			}
+			// EscapeText 同时把 XML 不允许的控制字符替换为 U+FFFD，保证工作表可被解析。
+			if err := xml.EscapeText(w.sheet, []byte(fmt.Sprint(value))); err != nil {
+				return err
+			}
+			if _, err := w.sheet.WriteString("</t></is></c>"); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := w.sheet.WriteString("</row>")
+	return err
+}
+
+func (w *ojTaskXLSXSheetWriter) Close() error {
+	if _, err := w.sheet.WriteString("</sheetData></worksheet>"); err != nil {
+		return err
+	}
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.archive.Close()
+}
@@ -0,0 +1,152 @@
+package system
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/model/entity"
+	bizerrors "personal_assistant/pkg/errors"
+)
+
+func TestExportTaskExecutionCSV(t *testing.T) {
+	svc, execution := newOJTaskExportTestService(t)
+
+	file, err := svc.ExportTaskExecution(context.Background(), 1, execution.TaskID, execution.ID, &request.OJTaskExecutionExportReq{})
+	if err != nil {
+		t.Fatalf("ExportTaskExecution() error = %v", err)
+	}
+	if !strings.HasSuffix(file.FileName, ".csv") || !strings.HasPrefix(file.ContentType, "text/csv") {
+		t.Fatalf("unexpected csv file meta: %+v", file)
+	}
+	var buffer bytes.Buffer
+	if err := file.Write(&buffer); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	content := strings.TrimPrefix(buffer.String(), "\ufeff")
+	records, err := csv.NewReader(strings.NewReader(content)).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header and 2 users, got %d rows", len(records))
+	}
+	if got := records[0][len(records[0])-2]; got != "1. Two Sum 状态" {
+		t.Fatalf("unexpected item header: %q", got)
+	}
+	// 用户 3 的用户名以公式字符开头，导出时需加前缀避免被表格软件执行。
+	want := []string{"3", "'=user3", "一班、二班", "0", "0", "1", "否", "pending", "unsolved"}
+	if strings.Join(records[2], "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected user 3 row: %v", records[2])
+	}
+}
+
+func TestExportTaskExecutionXLSX(t *testing.T) {
+	svc, execution := newOJTaskExportTestService(t)
+	allCompleted := false
+
+	file, err := svc.ExportTaskExecution(context.Background(), 1, execution.TaskID, execution.ID, &request.OJTaskExecutionExportReq{
+		Format:       "xlsx",
+		AllCompleted: &allCompleted,
+		Username:     "user3",
+	})
+	if err != nil {
+		t.Fatalf("ExportTaskExecution() error = %v", err)
+	}
+	var buffer bytes.Buffer
+	if err := file.Write(&buffer); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	if err != nil {
+		t.Fatalf("open xlsx: %v", err)
+	}
+	var sheet []byte
+	for _, entry := range archive.File {
+		if entry.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		reader, err := entry.Open()
+		if err != nil {
+			t.Fatalf("open sheet: %v", err)
+		}
+		sheet, _ = io.ReadAll(reader)
+		_ = reader.Close()
+	}
+	if len(archive.File) != 5 || sheet == nil {
+		t.Fatalf("unexpected xlsx parts: %d", len(archive.File))
+	}
+
+	var parsed struct {
+		Rows []struct {
+			Cells []struct {
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal(sheet, &parsed); err != nil {
+		t.Fatalf("parse sheet: %v", err)
+	}
+	if len(parsed.Rows) != 2 {
+		t.Fatalf("expected header and filtered user, got %d rows", len(parsed.Rows))
+	}
+	row := parsed.Rows[1].Cells
+	// XLSX 以内联字符串写入文本，不会被当作公式，用户名保持原样。
+	if row[0].Value != "3" || row[1].Inline != "=user3" || row[5].Value != "1" || row[7].Inline != "pending" {
+		t.Fatalf("unexpected user row: %+v", row)
+	}
+}
+
+func TestExportTaskExecutionRejectsUnknownFormat(t *testing.T) {
+	svc, execution := newOJTaskExportTestService(t)
+
+	_, err := svc.ExportTaskExecution(context.Background(), 1, execution.TaskID, execution.ID, &request.OJTaskExecutionExportReq{Format: "pdf"})
+	if !hasBizCode(err, bizerrors.CodeInvalidParams) {
+		t.Fatalf("expected invalid params, got %v", err)
+	}
+}
+
+// newOJTaskExportTestService 复用截止跟踪的执行种子：用户 2 已完成，用户 3 未完成且命中两个组织。
+func newOJTaskExportTestService(t *testing.T) (*OJTaskService, *entity.OJTaskExecution) {
+	t.Helper()
+
+	svc, db, _ := newOJTaskDeadlineTestService(t)
+	svc.authorizationService = &fakeAIMemoryManageAuthorization{superAdmins: map[uint]bool{1: true}}
+	if err := db.AutoMigrate(&entity.OJTaskOrg{}, &entity.OJTaskExecutionUserOrg{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	execution := seedOJTaskDeadlineExecution(t, db, time.Now().UTC().Add(24*time.Hour))
+
+	var users []*entity.OJTaskExecutionUser
+	if err := db.Where("execution_id = ?", execution.ID).Order("user_id ASC").Find(&users).Error; err != nil {
+		t.Fatalf("load execution users: %v", err)
+	}
+	if err := db.Model(users[0]).Updates(map[string]any{
+		"completed_item_count": 1,
+		"pending_item_count":   0,
+		"all_completed":        true,
+	}).Error; err != nil {
+		t.Fatalf("complete user 2: %v", err)
+	}
+	if err := db.Model(&entity.OJTaskExecutionUserItem{}).
+		Where("execution_user_id = ?", users[0].ID).
+		Updates(map[string]any{"result_status": "completed", "reason": ""}).Error; err != nil {
+		t.Fatalf("complete user 2 item: %v", err)
+	}
+	if err := db.Model(users[1]).Update("username_snapshot", "=user3").Error; err != nil {
+		t.Fatalf("rename user 3: %v", err)
+	}
+	mustCreate(t, db, &entity.OJTaskExecutionUserOrg{ExecutionUserID: users[1].ID, OrgID: 10, OrgNameSnapshot: "一班"})
+	mustCreate(t, db, &entity.OJTaskExecutionUserOrg{ExecutionUserID: users[1].ID, OrgID: 11, OrgNameSnapshot: "二班"})
+	return svc, execution
+}
@@ -0,0 +1,325 @@
+package system
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	dtoresp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	readmodel "personal_assistant/internal/model/readmodel"
+	bizerrors "personal_assistant/pkg/errors"
+)
+
+const (
+	// ojQuestionIntakeRecallLimit 是模糊召回时从题库取回的最大行数，相似度排序在内存中完成。
+	ojQuestionIntakeRecallLimit = 200
+	// ojQuestionIntakeRecallTerms 限制召回关键词数量，避免长标题拆出过多 LIKE 条件。
+	ojQuestionIntakeRecallTerms    = 10
+	ojQuestionIntakeCandidateLimit = 10
+	// ojQuestionIntakeMinScore 低于该相似度的候选不返回。
+	ojQuestionIntakeMinScore = 0.3
+
+	ojQuestionIntakeNoteConfirmed = "manual_curation_confirmed"
+	ojQuestionIntakeNoteCreated   = "manual_curation_created"
+)
+
+// ListPendingIntakes 跨任务列出待解析题目，按“平台 + 输入标题”聚合，仅超级管理员可查看。
+func (s *OJTaskService) ListPendingIntakes(
+	ctx context.Context,
+	operatorID uint,
+	req *request.OJQuestionIntakeListReq,
+) ([]*dtoresp.OJQuestionIntakeGroupResp, int64, error) {
+	if err := s.requireIntakeCurator(ctx, operatorID); err != nil {
+		return nil, 0, err
+	}
+	if req == nil {
+		req = &request.OJQuestionIntakeListReq{}
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	rows, total, err := s.taskRepo.ListPendingIntakeGroups(
+		ctx,
+		strings.TrimSpace(req.Platform),
+		(req.Page-1)*req.PageSize,
+		req.PageSize,
+	)
+	if err != nil {
+		return nil, 0, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	list := make([]*dtoresp.OJQuestionIntakeGroupResp, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, &dtoresp.OJQuestionIntakeGroupResp{
+			Platform:    row.Platform,
+			InputTitle:  row.InputTitle,
+			IntakeCount: row.IntakeCount,
+			TaskCount:   row.TaskCount,
+		})
+	}
+	return list, total, nil
+}
+
+// SuggestIntakeCandidates 按标题相似度从本地已验证题库中推荐候选题目。
+// 先用标题拆出的关键词做宽松召回，再按编辑距离相似度排序，兼顾错别字、多余空格与标点差异。
+func (s *OJTaskService) SuggestIntakeCandidates(
+	ctx context.Context,
+	operatorID uint,
+	req *request.OJQuestionIntakeCandidateReq,
+) ([]*dtoresp.OJQuestionIntakeCandidateResp, error) {
+	if err := s.requireIntakeCurator(ctx, operatorID); err != nil {
+		return nil, err
+	}
+	platform := strings.TrimSpace(req.Platform)
+	title := normalizeOJTaskTitle(req.InputTitle)
+	if title == "" {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "input_title 不能为空")
+	}
+
+	verified := int8(consts.OJQuestionSourceStatusVerified)
+	rows, _, err := s.questionBankRepo.Search(ctx, &readmodel.OJQuestionBankQuery{
+		Platforms:    []string{platform},
+		Terms:        buildOJQuestionIntakeRecallTerms(title),
+		MatchAnyTerm: true,
+		SourceStatus: &verified,
+		Limit:        ojQuestionIntakeRecallLimit,
+	})
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	list := make([]*dtoresp.OJQuestionIntakeCandidateResp, 0, len(rows))
+	for _, row := range rows {
+		score := max(ojQuestionTitleSimilarity(title, row.Title), ojQuestionTitleSimilarity(title, row.QuestionCode))
+		if score < ojQuestionIntakeMinScore {
+			continue
+		}
+		list = append(list, &dtoresp.OJQuestionIntakeCandidateResp{
+			Platform:     row.Platform,
+			QuestionID:   row.QuestionID,
+			QuestionCode: row.QuestionCode,
+			Title:        row.Title,
+			Score:        float64(int(score*1000+0.5)) / 1000,
+		})
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+		return list[i].QuestionID < list[j].QuestionID
+	})
+	if len(list) > ojQuestionIntakeCandidateLimit {
+		list = list[:ojQuestionIntakeCandidateLimit]
+	}
+	return list, nil
+}
+
+// ConfirmIntake 把待解析标题确认为题库中的已验证题目，并回填所有任务版本中该标题的待解析题目。
+func (s *OJTaskService) ConfirmIntake(
+	ctx context.Context,
+	operatorID uint,
+	req *request.ConfirmOJQuestionIntakeReq,
+) (*dtoresp.OJQuestionIntakeResolveResp, error) {
+	if err := s.requireIntakeCurator(ctx, operatorID); err != nil {
+		return nil, err
+	}
+	platform := strings.TrimSpace(req.Platform)
+	title := normalizeOJTaskTitle(req.InputTitle)
+	if title == "" {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "input_title 不能为空")
+	}
+
+	var out *dtoresp.OJQuestionIntakeResolveResp
+	err := s.txRunner.InTx(ctx, func(tx any) error {
+		candidate, err := s.getVerifiedCandidateByID(ctx, tx, platform, req.QuestionID)
+		if err != nil {
+			return err
+		}
+		items, err := s.backfillPendingIntakesTx(ctx, tx, platform, title, candidate, ojQuestionIntakeNoteConfirmed)
+		if err != nil {
+			return err
+		}
+		out = buildOJQuestionIntakeResolveResp(title, candidate, items)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateManualQuestionForIntake 为题库缺失的题目手工录入一条 manual 来源的已验证题目，
+// 随后回填该输入标题下的全部待解析题目；若录入标题与输入标题不同，同标题的其他待解析项也一并回填。
+func (s *OJTaskService) CreateManualQuestionForIntake(
+	ctx context.Context,
+	operatorID uint,
+	req *request.CreateManualOJQuestionReq,
+) (*dtoresp.OJQuestionIntakeResolveResp, error) {
+	if err := s.requireIntakeCurator(ctx, operatorID); err != nil {
+		return nil, err
+	}
+	platform := strings.TrimSpace(req.Platform)
+	inputTitle := normalizeOJTaskTitle(req.InputTitle)
+	code := strings.TrimSpace(req.QuestionCode)
+	if inputTitle == "" || code == "" {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "input_title/question_code 不能为空")
+	}
+	title := normalizeOJTaskTitle(req.Title)
+	if title == "" {
+		title = inputTitle
+	}
+
+	var out *dtoresp.OJQuestionIntakeResolveResp
+	err := s.txRunner.InTx(ctx, func(tx any) error {
+		candidate, err := s.createManualQuestionTx(ctx, tx, platform, code, title, strings.TrimSpace(req.Difficulty))
+		if err != nil {
+			return err
+		}
+		items, err := s.backfillPendingIntakesTx(ctx, tx, platform, inputTitle, candidate, ojQuestionIntakeNoteCreated)
+		if err != nil {
+			return err
+		}
+		if title != inputTitle {
+			if err := s.resolvePendingIntakesByTitleTx(ctx, tx, platform, title); err != nil {
+				return err
+			}
+		}
+		out = buildOJQuestionIntakeResolveResp(inputTitle, candidate, items)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// createManualQuestionTx 在对应平台题库中写入一条 manual 来源的题目；题目编号已存在时拒绝，由调用方改走确认匹配。
+func (s *OJTaskService) createManualQuestionTx(
+	ctx context.Context,
+	tx any,
+	platform, code, title, difficulty string,
+) (ojTaskAnalyzeCandidate, error) {
+	store, ok := s.platformStores().lookup(platform)
+	if !ok {
+		return ojTaskAnalyzeCandidate{}, bizerrors.New(bizerrors.CodeOJPlatformInvalid)
+	}
+	return store.CreateManualQuestion(ctx, tx, code, title, difficulty)
+}
+
+func (s *OJTaskService) requireIntakeCurator(ctx context.Context, userID uint) error {
+	ok, err := s.isSuperAdmin(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return bizerrors.New(bizerrors.CodePermissionDenied)
+	}
+	return nil
+}
+
+func buildOJQuestionIntakeResolveResp(
+	inputTitle string,
+	candidate ojTaskAnalyzeCandidate,
+	items []*entity.OJTaskItem,
+) *dtoresp.OJQuestionIntakeResolveResp {
+	taskIDs := make(map[uint]struct{}, len(items))
+	for _, item := range items {
+		taskIDs[item.TaskID] = struct{}{}
+	}
+	return &dtoresp.OJQuestionIntakeResolveResp{
+		Platform:          candidate.Platform,
+		InputTitle:        inputTitle,
+		QuestionID:        candidate.QuestionID,
+		QuestionCode:      candidate.QuestionCode,
+		Title:             candidate.Title,
+		ResolvedItemCount: len(items),
+		ResolvedTaskCount: len(taskIDs),
+	}
+}
+
+// buildOJQuestionIntakeRecallTerms 从输入标题拆出召回关键词：按空白切词，
+// 中文等无空格的片段再拆成相邻双字，保证错一个字时仍能召回。
+func buildOJQuestionIntakeRecallTerms(title string) []string {
+	terms := make([]string, 0, ojQuestionIntakeRecallTerms)
+	add := func(term string) {
+		if len(terms) >= ojQuestionIntakeRecallTerms || len([]rune(term)) < 2 {
+			return
+		}
+		for _, existing := range terms {
+			if existing == term {
+				return
+			}
+		}
+		terms = append(terms, term)
+	}
+	for _, field := range strings.Fields(title) {
+		add(field)
+		runes := []rune(field)
+		if len(runes) <= 2 || isASCIIOJTitle(field) {
+			continue
+		}
+		for i := 0; i+1 < len(runes); i++ {
+			add(string(runes[i : i+2]))
+		}
+	}
+	if len(terms) == 0 {
+		terms = append(terms, title)
+	}
+	return terms
+}
+
+// ojQuestionTitleSimilarity 返回两个标题在忽略大小写、空白与标点后的编辑距离相似度，取值 0~1。
+func ojQuestionTitleSimilarity(a, b string) float64 {
+	left := normalizeOJQuestionTitleRunes(a)
+	right := normalizeOJQuestionTitleRunes(b)
+	longest := max(len(left), len(right))
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshteinRunes(left, right))/float64(longest)
+}
+
+func normalizeOJQuestionTitleRunes(title string) []rune {
+	runes := make([]rune, 0, len(title))
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
+
+func levenshteinRunes(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func isASCIIOJTitle(value string) bool {
+	for _, r := range value {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,214 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/model/entity"
+	reposystem "personal_assistant/internal/repository/system"
+	bizerrors "personal_assistant/pkg/errors"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestConfirmIntakeBackfillsAllTaskVersions(t *testing.T) {
+	svc, db := newOJTaskIntakeCurationTestService(t)
+	ctx := context.Background()
+
+	if _, _, err := svc.ListPendingIntakes(ctx, 2, &request.OJQuestionIntakeListReq{}); !hasBizCode(err, bizerrors.CodePermissionDenied) {
+		t.Fatalf("expected permission denied, got %v", err)
+	}
+	groups, total, err := svc.ListPendingIntakes(ctx, 1, &request.OJQuestionIntakeListReq{})
+	if err != nil {
+		t.Fatalf("ListPendingIntakes() error = %v", err)
+	}
+	if total != 3 || groups[0].InputTitle != "Tow Sum" || groups[0].IntakeCount != 2 || groups[0].TaskCount != 2 {
+		t.Fatalf("unexpected pending groups: total=%d first=%+v", total, groups[0])
+	}
+
+	candidates, err := svc.SuggestIntakeCandidates(ctx, 1, &request.OJQuestionIntakeCandidateReq{
+		Platform: consts.OJPlatformLeetcode, InputTitle: "Tow Sum",
+	})
+	if err != nil {
+		t.Fatalf("SuggestIntakeCandidates() error = %v", err)
+	}
+	if len(candidates) == 0 || candidates[0].Title != "Two Sum" {
+		t.Fatalf("expected Two Sum as best candidate, got %+v", candidates)
+	}
+
+	out, err := svc.ConfirmIntake(ctx, 1, &request.ConfirmOJQuestionIntakeReq{
+		Platform: consts.OJPlatformLeetcode, InputTitle: "Tow Sum", QuestionID: candidates[0].QuestionID,
+	})
+	if err != nil {
+		t.Fatalf("ConfirmIntake() error = %v", err)
+	}
+	if out.ResolvedItemCount != 2 || out.ResolvedTaskCount != 2 || out.QuestionCode != "two-sum" {
+		t.Fatalf("unexpected confirm result: %+v", out)
+	}
+	var items []*entity.OJTaskItem
+	if err := db.Where("input_title = ?", "Tow Sum").Find(&items).Error; err != nil {
+		t.Fatalf("load items: %v", err)
+	}
+	for _, item := range items {
+		if item.ResolvedQuestionID != candidates[0].QuestionID ||
+			item.ResolutionStatus != string(consts.OJTaskItemResolutionStatusResolved) ||
+			item.ResolvedTitleSnapshot != "Two Sum" {
+			t.Fatalf("task item not backfilled: %+v", item)
+		}
+	}
+	if _, total, _ := svc.ListPendingIntakes(ctx, 1, &request.OJQuestionIntakeListReq{}); total != 2 {
+		t.Fatalf("expected 2 pending groups after confirm, got %d", total)
+	}
+}
+
+func TestSuggestIntakeCandidatesMatchesChineseTypo(t *testing.T) {
+	svc, _ := newOJTaskIntakeCurationTestService(t)
+
+	candidates, err := svc.SuggestIntakeCandidates(context.Background(), 1, &request.OJQuestionIntakeCandidateReq{
+		Platform: consts.OJPlatformLanqiao, InputTitle: "数字三角型",
+	})
+	if err != nil {
+		t.Fatalf("SuggestIntakeCandidates() error = %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].Title != "数字三角形" || candidates[0].Score != 0.8 {
+		t.Fatalf("unexpected lanqiao candidates: %+v", candidates)
+	}
+}
+
+func TestCreateManualQuestionForIntake(t *testing.T) {
+	svc, db := newOJTaskIntakeCurationTestService(t)
+	ctx := context.Background()
+
+	out, err := svc.CreateManualQuestionForIntake(ctx, 1, &request.CreateManualOJQuestionReq{
+		Platform:     consts.OJPlatformLuogu,
+		InputTitle:   "校内模拟赛 T1",
+		QuestionCode: "P9999",
+		Difficulty:   "普及/提高-",
+	})
+	if err != nil {
+		t.Fatalf("CreateManualQuestionForIntake() error = %v", err)
+	}
+	if out.ResolvedItemCount != 1 || out.Title != "校内模拟赛 T1" {
+		t.Fatalf("unexpected manual result: %+v", out)
+	}
+	var question entity.LuoguQuestionBank
+	if err := db.Where("pid = ?", "P9999").First(&question).Error; err != nil {
+		t.Fatalf("load manual question: %v", err)
+	}
+	if question.SourceType != string(consts.OJQuestionSourceTypeManual) ||
+		question.SourceStatus != int8(consts.OJQuestionSourceStatusVerified) ||
+		question.ID != out.QuestionID {
+		t.Fatalf("unexpected manual question: %+v", question)
+	}
+
+	// 题号已存在时应改走确认匹配。
+	if _, err := svc.CreateManualQuestionForIntake(ctx, 1, &request.CreateManualOJQuestionReq{
+		Platform: consts.OJPlatformLuogu, InputTitle: "另一道题", QuestionCode: "P9999",
+	}); !hasBizCode(err, bizerrors.CodeOJQuestionDuplicate) {
+		t.Fatalf("expected duplicate question, got %v", err)
+	}
+}
+
+func TestOJQuestionTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"Two Sum", "two-sum", 1},
+		{"Tow Sum", "Two Sum", 2.0 / 3},
+		{"", "Two Sum", 0},
+	}
+	for _, tt := range tests {
+		got := ojQuestionTitleSimilarity(tt.a, tt.b)
+		if fmt.Sprintf("%.4f", got) != fmt.Sprintf("%.4f", tt.want) {
+			t.Fatalf("similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// newOJTaskIntakeCurationTestService 预置三组待解析题目：
+// 力扣 “Tow Sum” 出现在同一版本链的 v1、v2；蓝桥 “数字三角型” 与洛谷 “校内模拟赛 T1” 各一条。
+func newOJTaskIntakeCurationTestService(t *testing.T) (*OJTaskService, *gorm.DB) {
+	t.Helper()
+
+	oldConfig := global.Config
+	oldLog := global.Log
+	global.Config = &config.Config{}
+	global.Log = zap.NewNop()
+	t.Cleanup(func() {
+		global.Config = oldConfig
+		global.Log = oldLog
+	})
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&entity.OJTask{},
+		&entity.OJTaskItem{},
+		&entity.OJQuestionIntake{},
+		&entity.LuoguQuestionBank{},
+		&entity.LeetcodeQuestionBank{},
+		&entity.LanqiaoQuestionBank{},
+		&entity.OJProviderQuestionBank{},
+	); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+
+	verified := int8(consts.OJQuestionSourceStatusVerified)
+	mustCreate(t, db, &entity.LeetcodeQuestionBank{TitleSlug: "two-sum", Title: "Two Sum", SourceStatus: verified, SourceType: "sync"})
+	mustCreate(t, db, &entity.LeetcodeQuestionBank{TitleSlug: "add-two-numbers", Title: "Add Two Numbers", SourceStatus: verified, SourceType: "sync"})
+	mustCreate(t, db, &entity.LanqiaoQuestionBank{ProblemID: 1002, Title: "数字三角形", SourceStatus: verified, SourceType: "sync"})
+	mustCreate(t, db, &entity.LanqiaoQuestionBank{ProblemID: 1003, Title: "跳跃", SourceStatus: verified, SourceType: "sync"})
+
+	seed := func(taskID uint, platform, title string) {
+		mustCreate(t, db, &entity.OJTask{
+			MODEL:     entity.MODEL{ID: taskID},
+			VersionNo: int(taskID),
+			Title:     fmt.Sprintf("task-%d", taskID),
+			Mode:      "immediate",
+			Status:    "succeeded",
+			CreatedBy: 1,
+			UpdatedBy: 1,
+		})
+		item := &entity.OJTaskItem{
+			TaskID:           taskID,
+			SortNo:           1,
+			Platform:         platform,
+			InputTitle:       title,
+			ResolutionStatus: string(consts.OJTaskItemResolutionStatusPendingResolution),
+		}
+		mustCreate(t, db, item)
+		mustCreate(t, db, &entity.OJQuestionIntake{
+			TaskID:     taskID,
+			TaskItemID: item.ID,
+			Platform:   platform,
+			InputTitle: title,
+			Status:     string(consts.OJTaskItemResolutionStatusPendingResolution),
+		})
+	}
+	seed(1, consts.OJPlatformLeetcode, "Tow Sum")
+	seed(2, consts.OJPlatformLeetcode, "Tow Sum")
+	seed(3, consts.OJPlatformLanqiao, "数字三角型")
+	seed(4, consts.OJPlatformLuogu, "校内模拟赛 T1")
+
+	svc := &OJTaskService{
+		txRunner:             &stubTxRunner{},
+		taskRepo:             reposystem.NewOJTaskRepository(db),
+		luoguQuestionRepo:    reposystem.NewLuoguQuestionBankRepository(db),
+		leetcodeQuestionRepo: reposystem.NewLeetcodeQuestionBankRepository(db),
+		lanqiaoQuestionRepo:  reposystem.NewLanqiaoQuestionBankRepository(db),
+		providerQuestionRepo: reposystem.NewOJProviderQuestionBankRepository(db),
+		questionBankRepo:     reposystem.NewOJQuestionBankRepository(db),
+		authorizationService: &fakeAIMemoryManageAuthorization{superAdmins: map[uint]bool{1: true}},
+	}
+	return svc, db
+}
@@ -16,51 +16,86 @@ func (s *OJTaskService) HandleQuestionUpserted(ctx context.Context, event *event
 		return bizerrors.NewWithMsg(bizerrors.CodeInvalidParams, "question upsert event invalid")
 	}
 
-	title := normalizeOJTaskTitle(event.Title)
+	inputTitles := []string{normalizeOJTaskTitle(event.Title)}
+	// 扩展平台允许题单直接填写题号，题号形式的 pending 项也需要回填。
+	if _, ok := lookupOJPlatformProvider(event.Platform); ok {
+		if code := normalizeOJTaskTitle(event.QuestionCode); code != "" && code != inputTitles[0] {
+			inputTitles = append(inputTitles, code)
+		}
+	}
 	return s.txRunner.InTx(ctx, func(tx any) error {
-		txTaskRepo := s.taskRepo.WithTx(tx)
+		for _, title := range inputTitles {
+			if err := s.resolvePendingIntakesByTitleTx(ctx, tx, event.Platform, title); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// resolvePendingIntakesByTitleTx 在事务内把指定输入标题的 pending 任务项回填为唯一命中的候选题目。
+func (s *OJTaskService) resolvePendingIntakesByTitleTx(
+	ctx context.Context,
+	tx any,
+	platform string,
+	title string,
+) error {
+	candidates, err := s.findVerifiedTitleCandidates(ctx, tx, platform, title)
+	if err != nil {
+		return err
+	}
+	if len(candidates) != 1 {
+		return nil
+	}
+	_, err = s.backfillPendingIntakesTx(ctx, tx, platform, title, candidates[0], "auto_resolved_from_question_upsert")
+	return err
+}
+
+// backfillPendingIntakesTx 把指定输入标题下所有 pending 任务项（跨任务、跨版本）回填为给定题目，返回实际回填的任务项。
+func (s *OJTaskService) backfillPendingIntakesTx(
+	ctx context.Context,
+	tx any,
+	platform string,
+	title string,
+	candidate ojTaskAnalyzeCandidate,
+	note string,
+) ([]*entity.OJTaskItem, error) {
+	txTaskRepo := s.taskRepo.WithTx(tx)
 
-		candidates, err := s.findVerifiedTitleCandidates(ctx, tx, event.Platform, title)
+	intakes, err := txTaskRepo.ListPendingIntakesByTitle(ctx, platform, title)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	resolved := make([]*entity.OJTaskItem, 0, len(intakes))
+	for _, intake := range intakes {
+		if intake == nil || intake.TaskItemID == 0 {
+			continue
+		}
+		item, err := txTaskRepo.GetItemByID(ctx, intake.TaskItemID)
 		if err != nil {
-			return err
+			return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
 		}
-		if len(candidates) != 1 {
-			return nil
+		if item == nil || item.ID == 0 || item.ResolutionStatus != string(consts.OJTaskItemResolutionStatusPendingResolution) {
+			continue
 		}
-
-		intakes, err := txTaskRepo.ListPendingIntakesByTitle(ctx, event.Platform, title)
-		if err != nil {
-			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		if normalizeOJTaskTitle(item.InputTitle) != title {
+			continue
 		}
-		for _, intake := range intakes {
-			if intake == nil || intake.TaskItemID == 0 {
-				continue
-			}
-			item, err := txTaskRepo.GetItemByID(ctx, intake.TaskItemID)
-			if err != nil {
-				return bizerrors.Wrap(bizerrors.CodeDBError, err)
-			}
-			if item == nil || item.ID == 0 || item.ResolutionStatus != string(consts.OJTaskItemResolutionStatusPendingResolution) {
-				continue
-			}
-			if normalizeOJTaskTitle(item.InputTitle) != title {
-				continue
-			}
 
-			if applyResolvedCandidateToTaskItem(item, candidates[0], "auto_resolved_from_question_upsert") {
-				if err := txTaskRepo.UpdateItem(ctx, item); err != nil {
-					return bizerrors.Wrap(bizerrors.CodeDBError, err)
-				}
-			}
-			intake.Status = string(consts.OJTaskItemResolutionStatusResolved)
-			intake.ResolvedQuestionID = candidates[0].QuestionID
-			intake.ResolutionNote = ""
-			if err := txTaskRepo.UpdateIntake(ctx, intake); err != nil {
-				return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		if applyResolvedCandidateToTaskItem(item, candidate, note) {
+			if err := txTaskRepo.UpdateItem(ctx, item); err != nil {
+				return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
 			}
 		}
-		return nil
-	})
+		intake.Status = string(consts.OJTaskItemResolutionStatusResolved)
+		intake.ResolvedQuestionID = candidate.QuestionID
+		intake.ResolutionNote = ""
+		if err := txTaskRepo.UpdateIntake(ctx, intake); err != nil {
+			return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		resolved = append(resolved, item)
+	}
+	return resolved, nil
 }
 
 func applyResolvedCandidateToTaskItem(
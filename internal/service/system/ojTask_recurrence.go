@@ -0,0 +1,223 @@
+package system
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"time"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/entity"
+	bizerrors "personal_assistant/pkg/errors"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// ojTaskRecurrenceMinInterval 是相邻两个周期的最小间隔，避免一条规则在短时间内堆出大量执行快照。
+	ojTaskRecurrenceMinInterval = time.Hour
+	// ojTaskRecurrenceMaxSpan 是周期任务从创建到截止的最长跨度。
+	ojTaskRecurrenceMaxSpan = 366 * 24 * time.Hour
+	// ojTaskRecurrenceIntervalProbe 是校验最小间隔时向后推演的周期个数。
+	ojTaskRecurrenceIntervalProbe = 8
+	// ojTaskRecurrenceMaterializeBatchSize 是单轮调度最多物化的周期任务数。
+	ojTaskRecurrenceMaterializeBatchSize = 50
+	// defaultOJTaskOccurrenceLimit 是跨周期对比默认返回的最近周期数。
+	defaultOJTaskOccurrenceLimit = 12
+)
+
+// ojTaskRecurrencePlan 是周期规则校验后的推演结果。
+type ojTaskRecurrencePlan struct {
+	Rule             string
+	EndAt            time.Time
+	FirstOccurrence  time.Time
+	NextOccurrenceAt *time.Time
+}
+
+// parseOJTaskRecurrenceRule 解析标准 5 段 cron 表达式（分 时 日 月 周），也支持 @weekly 等描述符。
+func parseOJTaskRecurrenceRule(rule string) (cron.Schedule, error) {
+	rule = strings.TrimSpace(rule)
+	if rule == "" {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeOJTaskRecurrenceInvalid, "周期任务必须传 recurrence_rule")
+	}
+	if strings.HasPrefix(rule, "TZ=") || strings.HasPrefix(rule, "CRON_TZ=") {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeOJTaskRecurrenceInvalid, "recurrence_rule 固定按北京时间解释，不支持指定时区")
+	}
+	schedule, err := cron.ParseStandard(rule)
+	if err != nil {
+		return nil, bizerrors.NewWithMsg(bizerrors.CodeOJTaskRecurrenceInvalid, "recurrence_rule 不是合法的 cron 表达式")
+	}
+	return schedule, nil
+}
+
+// nextOJTaskOccurrence 返回 after 之后的下一个周期时间（UTC）；超过 endAt 时返回 nil。
+// cron 按北京时间解释，与刷题曲线的自然日口径保持一致。
+func nextOJTaskOccurrence(schedule cron.Schedule, after, endAt time.Time) *time.Time {
+	next := schedule.Next(after.In(ojDailyStatsLocation()))
+	if next.IsZero() || next.After(endAt) {
+		return nil
+	}
+	value := next.UTC()
+	return &value
+}
+
+// planOJTaskRecurrence 校验周期规则与截止时间，并推演首个周期与其后一个周期。
+func planOJTaskRecurrence(rule string, endAt *time.Time, now time.Time) (ojTaskRecurrencePlan, error) {
+	schedule, err := parseOJTaskRecurrenceRule(rule)
+	if err != nil {
+		return ojTaskRecurrencePlan{}, err
+	}
+	if endAt == nil || endAt.IsZero() {
+		return ojTaskRecurrencePlan{}, bizerrors.NewWithMsg(bizerrors.CodeOJTaskRecurrenceInvalid, "周期任务必须传 recurrence_end_at")
+	}
+	end := endAt.UTC()
+	if !end.After(now) {
+		return ojTaskRecurrencePlan{}, bizerrors.NewWithMsg(bizerrors.CodeOJTaskRecurrenceInvalid, "recurrence_end_at 必须是未来时间")
+	}
+	if end.Sub(now) > ojTaskRecurrenceMaxSpan {
+		return ojTaskRecurrencePlan{}, bizerrors.NewWithMsg(bizerrors.CodeOJTaskRecurrenceInvalid, "周期任务跨度不能超过一年")
+	}
+
+	first := nextOJTaskOccurrence(schedule, now, end)
+	if first == nil {
+		return ojTaskRecurrencePlan{}, bizerrors.NewWithMsg(bizerrors.CodeOJTaskRecurrenceInvalid, "截止时间前没有任何周期")
+	}
+	// cron 的间隔并不均匀（如“周一、周二”），向后推演若干个周期逐一检查最小间隔。
+	prev := *first
+	for i := 0; i < ojTaskRecurrenceIntervalProbe; i++ {
+		next := schedule.Next(prev.In(ojDailyStatsLocation()))
+		if next.IsZero() {
+			break
+		}
+		if next.Sub(prev) < ojTaskRecurrenceMinInterval {
+			return ojTaskRecurrencePlan{}, bizerrors.NewWithMsg(bizerrors.CodeOJTaskRecurrenceInvalid, "相邻周期间隔不能小于 1 小时")
+		}
+		prev = next.UTC()
+	}
+
+	return ojTaskRecurrencePlan{
+		Rule:             strings.TrimSpace(rule),
+		EndAt:            end,
+		FirstOccurrence:  *first,
+		NextOccurrenceAt: nextOJTaskOccurrence(schedule, *first, end),
+	}, nil
+}
+
+// isPendingRecurringOJTask 判断周期任务是否还有未物化的周期。
+func isPendingRecurringOJTask(task *entity.OJTask) bool {
+	return task != nil &&
+		task.Mode == string(consts.OJTaskModeRecurring) &&
+		task.NextOccurrenceAt != nil
+}
+
+// resolveOJTaskFinishedStatus 返回某次执行收口后任务版本应处的状态：
+// 周期任务仍有后续周期时回到 scheduled，等待下一次物化；否则按本次执行结果收口。
+func resolveOJTaskFinishedStatus(task *entity.OJTask, succeeded bool) string {
+	if isPendingRecurringOJTask(task) {
+		return string(consts.OJTaskStatusScheduled)
+	}
+	if succeeded {
+		return string(consts.OJTaskStatusSucceeded)
+	}
+	return string(consts.OJTaskStatusFailed)
+}
+
+// materializeRecurringExecutions 为到点的周期任务物化本周期的执行记录。
+// 每个任务先以 next_occurrence_at 做条件推进，保证多实例下同一周期只物化一次；
+// 错过的周期（如服务停机）不补跑，只物化最近到点的一个，并直接跳到当前时间之后的下一个周期。
+func (s *OJTaskService) materializeRecurringExecutions(ctx context.Context, now time.Time) error {
+	tasks, err := s.taskRepo.ListDueRecurringTasks(ctx, now, ojTaskRecurrenceMaterializeBatchSize)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	var joined error
+	for _, task := range tasks {
+		if ctx.Err() != nil {
+			return stderrors.Join(joined, ctx.Err())
+		}
+		if err := s.materializeRecurringOccurrence(ctx, task, now); err != nil {
+			joined = stderrors.Join(joined, err)
+		}
+	}
+	return joined
+}
+
+func (s *OJTaskService) materializeRecurringOccurrence(ctx context.Context, task *entity.OJTask, now time.Time) error {
+	if !isPendingRecurringOJTask(task) || task.RecurrenceEndAt == nil {
+		return nil
+	}
+	occurrenceAt := *task.NextOccurrenceAt
+
+	var next *time.Time
+	schedule, parseErr := parseOJTaskRecurrenceRule(task.RecurrenceRule)
+	if parseErr == nil {
+		after := occurrenceAt
+		if now.After(after) {
+			after = now
+		}
+		next = nextOJTaskOccurrence(schedule, after, *task.RecurrenceEndAt)
+	}
+
+	return s.txRunner.InTx(ctx, func(tx any) error {
+		txTaskRepo := s.taskRepo.WithTx(tx)
+		txExecutionRepo := s.executionRepo.WithTx(tx)
+
+		latest, err := txExecutionRepo.GetByTaskIDForUpdate(ctx, task.ID)
+		if err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		// 上一周期尚未收口时跳过本周期，避免同一任务的多个周期并发生成快照。
+		skip := parseErr != nil || (latest != nil && !isFinishedOJTaskExecutionStatus(latest.Status))
+		status := ""
+		if !skip {
+			status = string(consts.OJTaskStatusQueued)
+		}
+		claimed, err := txTaskRepo.AdvanceRecurrence(ctx, task.ID, occurrenceAt, next, status)
+		if err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		if !claimed {
+			return nil
+		}
+		if skip {
+			global.Log.Warn(
+				"跳过 OJTask 周期",
+				zap.Uint("task_id", task.ID),
+				zap.Time("occurrence_at", occurrenceAt),
+				zap.Error(parseErr),
+			)
+			return nil
+		}
+
+		occurrenceNo := 1
+		if latest != nil {
+			occurrenceNo = latest.OccurrenceNo + 1
+		}
+		execution := &entity.OJTaskExecution{
+			TaskID:       task.ID,
+			OccurrenceNo: occurrenceNo,
+			TriggerType:  string(consts.OJTaskExecutionTriggerRecurrenceDue),
+			PlannedAt:    occurrenceAt,
+			RequestedBy:  task.UpdatedBy,
+			Status:       string(consts.OJTaskExecutionStatusQueued),
+		}
+		if err := txExecutionRepo.Create(ctx, execution); err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		return s.publishQueuedExecutionTriggerInTx(ctx, tx, execution.ID, task.ID)
+	})
+}
+
+// isFinishedOJTaskExecutionStatus 判断执行记录是否已收口到终态。
+func isFinishedOJTaskExecutionStatus(status string) bool {
+	switch status {
+	case string(consts.OJTaskExecutionStatusSucceeded),
+		string(consts.OJTaskExecutionStatusFailed),
+		string(consts.OJTaskExecutionStatusCancelled):
+		return true
+	default:
+		return false
+	}
+}
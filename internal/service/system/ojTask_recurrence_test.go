@@ -0,0 +1,333 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/consts"
+	eventdto "personal_assistant/internal/model/dto/event"
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/model/entity"
+	reposystem "personal_assistant/internal/repository/system"
+	bizerrors "personal_assistant/pkg/errors"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type stubOJTaskTriggerPublisher struct {
+	events []*eventdto.OJTaskExecutionTriggerEvent
+}
+
+func (p *stubOJTaskTriggerPublisher) PublishInTx(
+	_ context.Context,
+	_ any,
+	event *eventdto.OJTaskExecutionTriggerEvent,
+) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestPlanOJTaskRecurrence(t *testing.T) {
+	// 2026-03-04 是周三，北京时间 10:00。
+	now := time.Date(2026, 3, 4, 2, 0, 0, 0, time.UTC)
+	endAt := now.Add(30 * 24 * time.Hour)
+
+	plan, err := planOJTaskRecurrence(" 0 8 * * 1 ", &endAt, now)
+	if err != nil {
+		t.Fatalf("planOJTaskRecurrence() error = %v", err)
+	}
+	// 每周一北京时间 08:00，即 UTC 周一 00:00。
+	wantFirst := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	if plan.Rule != "0 8 * * 1" || !plan.FirstOccurrence.Equal(wantFirst) {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if plan.NextOccurrenceAt == nil || !plan.NextOccurrenceAt.Equal(wantFirst.Add(7*24*time.Hour)) {
+		t.Fatalf("unexpected next occurrence: %v", plan.NextOccurrenceAt)
+	}
+
+	shortEnd := wantFirst.Add(time.Hour)
+	plan, err = planOJTaskRecurrence("0 8 * * 1", &shortEnd, now)
+	if err != nil {
+		t.Fatalf("planOJTaskRecurrence() short end error = %v", err)
+	}
+	if plan.NextOccurrenceAt != nil {
+		t.Fatalf("expected single occurrence before end, got next %v", plan.NextOccurrenceAt)
+	}
+
+	pastEnd := now.Add(-time.Hour)
+	farEnd := now.Add(400 * 24 * time.Hour)
+	beforeFirst := now.Add(time.Hour)
+	cases := []struct {
+		name  string
+		rule  string
+		endAt *time.Time
+	}{
+		{name: "empty rule", rule: "", endAt: &endAt},
+		{name: "invalid rule", rule: "every monday", endAt: &endAt},
+		{name: "timezone prefix", rule: "CRON_TZ=UTC 0 8 * * 1", endAt: &endAt},
+		{name: "too frequent", rule: "*/30 * * * *", endAt: &endAt},
+		{name: "missing end", rule: "0 8 * * 1", endAt: nil},
+		{name: "past end", rule: "0 8 * * 1", endAt: &pastEnd},
+		{name: "span too long", rule: "0 8 * * 1", endAt: &farEnd},
+		{name: "no occurrence before end", rule: "0 8 * * 1", endAt: &beforeFirst},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := planOJTaskRecurrence(tc.rule, tc.endAt, now)
+			if !hasBizCode(err, bizerrors.CodeOJTaskRecurrenceInvalid) {
+				t.Fatalf("expected recurrence invalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMaterializeRecurringExecutionsCreatesNextOccurrence(t *testing.T) {
+	svc, db, publisher := newOJTaskRecurrenceTestService(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	due := now.Add(-2 * time.Hour)
+	task := seedRecurringOJTask(t, db, due, now.Add(30*24*time.Hour), string(consts.OJTaskExecutionStatusSucceeded))
+
+	if err := svc.materializeRecurringExecutions(ctx, now); err != nil {
+		t.Fatalf("materializeRecurringExecutions() error = %v", err)
+	}
+
+	var executions []*entity.OJTaskExecution
+	if err := db.Where("task_id = ?", task.ID).Order("occurrence_no ASC").Find(&executions).Error; err != nil {
+		t.Fatalf("list executions: %v", err)
+	}
+	if len(executions) != 2 {
+		t.Fatalf("expected 2 executions, got %d", len(executions))
+	}
+	created := executions[1]
+	if created.OccurrenceNo != 2 ||
+		created.Status != string(consts.OJTaskExecutionStatusQueued) ||
+		created.TriggerType != string(consts.OJTaskExecutionTriggerRecurrenceDue) ||
+		!created.PlannedAt.Equal(due) {
+		t.Fatalf("unexpected materialized execution: %+v", created)
+	}
+	if len(publisher.events) != 1 || publisher.events[0].ExecutionID != created.ID {
+		t.Fatalf("expected one trigger for execution %d, got %+v", created.ID, publisher.events)
+	}
+
+	var reloaded entity.OJTask
+	if err := db.First(&reloaded, task.ID).Error; err != nil {
+		t.Fatalf("reload task: %v", err)
+	}
+	if reloaded.Status != string(consts.OJTaskStatusQueued) {
+		t.Fatalf("expected task queued, got %s", reloaded.Status)
+	}
+	// 错过的周期不补跑，下一周期直接推进到当前时间之后。
+	if reloaded.NextOccurrenceAt == nil || !reloaded.NextOccurrenceAt.After(now) {
+		t.Fatalf("expected next occurrence after now, got %v", reloaded.NextOccurrenceAt)
+	}
+
+	// 同一时刻重复调度不会再次物化。
+	if err := svc.materializeRecurringExecutions(ctx, now); err != nil {
+		t.Fatalf("second materializeRecurringExecutions() error = %v", err)
+	}
+	assertUnscopedCount(t, db, &entity.OJTaskExecution{}, "task_id = ?", task.ID, 2)
+}
+
+func TestMaterializeRecurringExecutionsSkipsWhilePreviousRunning(t *testing.T) {
+	svc, db, publisher := newOJTaskRecurrenceTestService(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	task := seedRecurringOJTask(t, db, now.Add(-time.Minute), now.Add(30*24*time.Hour), string(consts.OJTaskExecutionStatusExecuting))
+	if err := db.Model(&entity.OJTask{}).Where("id = ?", task.ID).
+		Update("status", string(consts.OJTaskStatusExecuting)).Error; err != nil {
+		t.Fatalf("mark task executing: %v", err)
+	}
+
+	if err := svc.materializeRecurringExecutions(ctx, now); err != nil {
+		t.Fatalf("materializeRecurringExecutions() error = %v", err)
+	}
+	assertUnscopedCount(t, db, &entity.OJTaskExecution{}, "task_id = ?", task.ID, 1)
+	if len(publisher.events) != 0 {
+		t.Fatalf("expected no trigger, got %d", len(publisher.events))
+	}
+
+	var reloaded entity.OJTask
+	if err := db.First(&reloaded, task.ID).Error; err != nil {
+		t.Fatalf("reload task: %v", err)
+	}
+	if reloaded.Status != string(consts.OJTaskStatusExecuting) {
+		t.Fatalf("expected status untouched, got %s", reloaded.Status)
+	}
+	if reloaded.NextOccurrenceAt == nil || !reloaded.NextOccurrenceAt.After(now) {
+		t.Fatalf("expected skipped occurrence to advance, got %v", reloaded.NextOccurrenceAt)
+	}
+	// 执行收口时仍有后续周期，任务版本回到 scheduled。
+	if got := resolveOJTaskFinishedStatus(&reloaded, true); got != string(consts.OJTaskStatusScheduled) {
+		t.Fatalf("expected scheduled after finish, got %s", got)
+	}
+}
+
+func TestMaterializeRecurringExecutionsStopsAtEnd(t *testing.T) {
+	svc, db, _ := newOJTaskRecurrenceTestService(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	task := seedRecurringOJTask(t, db, now.Add(-time.Minute), now.Add(time.Minute), string(consts.OJTaskExecutionStatusFailed))
+
+	if err := svc.materializeRecurringExecutions(ctx, now); err != nil {
+		t.Fatalf("materializeRecurringExecutions() error = %v", err)
+	}
+	var reloaded entity.OJTask
+	if err := db.First(&reloaded, task.ID).Error; err != nil {
+		t.Fatalf("reload task: %v", err)
+	}
+	if reloaded.NextOccurrenceAt != nil {
+		t.Fatalf("expected recurrence finished, got next %v", reloaded.NextOccurrenceAt)
+	}
+	if got := resolveOJTaskFinishedStatus(&reloaded, true); got != string(consts.OJTaskStatusSucceeded) {
+		t.Fatalf("expected succeeded after last occurrence, got %s", got)
+	}
+}
+
+func TestGetTaskOccurrencesComparesUsersAcrossOccurrences(t *testing.T) {
+	svc, db, _ := newOJTaskRecurrenceTestService(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	task := seedRecurringOJTask(t, db, now.Add(24*time.Hour), now.Add(30*24*time.Hour), string(consts.OJTaskExecutionStatusSucceeded))
+
+	var first entity.OJTaskExecution
+	if err := db.Where("task_id = ? AND occurrence_no = 1", task.ID).First(&first).Error; err != nil {
+		t.Fatalf("load first execution: %v", err)
+	}
+	second := &entity.OJTaskExecution{
+		TaskID:             task.ID,
+		OccurrenceNo:       2,
+		TriggerType:        string(consts.OJTaskExecutionTriggerRecurrenceDue),
+		PlannedAt:          now.Add(-time.Hour),
+		RequestedBy:        1,
+		Status:             string(consts.OJTaskExecutionStatusSucceeded),
+		TotalUserCount:     2,
+		CompletedUserCount: 2,
+	}
+	mustCreate(t, db, second)
+	// 用户 2 第一周期未完成、第二周期完成；用户 3 只出现在第一周期。
+	for _, row := range []*entity.OJTaskExecutionUser{
+		{ExecutionID: first.ID, UserID: 2, UsernameSnapshot: "old-name", PendingItemCount: 1},
+		{ExecutionID: first.ID, UserID: 3, UsernameSnapshot: "user3", CompletedItemCount: 1, AllCompleted: true},
+		{ExecutionID: second.ID, UserID: 2, UsernameSnapshot: "user2", CompletedItemCount: 1, AllCompleted: true},
+	} {
+		mustCreate(t, db, row)
+	}
+
+	out, err := svc.GetTaskOccurrences(ctx, 1, task.ID, &request.OJTaskOccurrenceListReq{})
+	if err != nil {
+		t.Fatalf("GetTaskOccurrences() error = %v", err)
+	}
+	if len(out.Occurrences) != 2 || out.Occurrences[0].OccurrenceNo != 1 || out.Occurrences[1].OccurrenceNo != 2 {
+		t.Fatalf("unexpected occurrences: %+v", out.Occurrences)
+	}
+	if out.Occurrences[1].CompletionRate != 1 {
+		t.Fatalf("expected second occurrence fully completed, got %v", out.Occurrences[1].CompletionRate)
+	}
+	if len(out.Users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(out.Users))
+	}
+	user2 := out.Users[0]
+	if user2.UserID != 2 || user2.UsernameSnapshot != "user2" || user2.CompletedOccurrenceCount != 1 || len(user2.Results) != 2 {
+		t.Fatalf("unexpected user 2 comparison: %+v", user2)
+	}
+	if user2.Results[0].AllCompleted || !user2.Results[1].AllCompleted {
+		t.Fatalf("unexpected user 2 results: %+v %+v", user2.Results[0], user2.Results[1])
+	}
+
+	limited, err := svc.GetTaskOccurrences(ctx, 1, task.ID, &request.OJTaskOccurrenceListReq{Limit: 1})
+	if err != nil {
+		t.Fatalf("GetTaskOccurrences(limit=1) error = %v", err)
+	}
+	if len(limited.Occurrences) != 1 || limited.Occurrences[0].OccurrenceNo != 2 || len(limited.Users) != 1 {
+		t.Fatalf("expected latest occurrence only, got %+v users=%d", limited.Occurrences, len(limited.Users))
+	}
+
+	if _, err := svc.GetTaskOccurrences(ctx, 9, task.ID, nil); !hasBizCode(err, bizerrors.CodeOJTaskVisibleDenied) {
+		t.Fatalf("expected visible denied for outsider, got %v", err)
+	}
+}
+
+func newOJTaskRecurrenceTestService(t *testing.T) (*OJTaskService, *gorm.DB, *stubOJTaskTriggerPublisher) {
+	t.Helper()
+
+	oldConfig := global.Config
+	oldLog := global.Log
+	global.Config = &config.Config{}
+	global.Log = zap.NewNop()
+	t.Cleanup(func() {
+		global.Config = oldConfig
+		global.Log = oldLog
+	})
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&entity.Org{},
+		&entity.OrgMember{},
+		&entity.OJTask{},
+		&entity.OJTaskOrg{},
+		&entity.OJTaskItem{},
+		&entity.OJTaskExecution{},
+		&entity.OJTaskExecutionUser{},
+	); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+
+	publisher := &stubOJTaskTriggerPublisher{}
+	svc := &OJTaskService{
+		txRunner:         &stubTxRunner{},
+		taskRepo:         reposystem.NewOJTaskRepository(db),
+		executionRepo:    reposystem.NewOJTaskExecutionRepository(db),
+		triggerPublisher: publisher,
+		authorizationService: &fakeAIMemoryManageAuthorization{
+			superAdmins: map[uint]bool{1: true},
+		},
+	}
+	return svc, db, publisher
+}
+
+// seedRecurringOJTask 创建一个每周一 08:00 执行的周期任务，首个周期执行记录处于 firstStatus。
+func seedRecurringOJTask(
+	t *testing.T,
+	db *gorm.DB,
+	nextOccurrenceAt, endAt time.Time,
+	firstStatus string,
+) *entity.OJTask {
+	t.Helper()
+
+	firstAt := nextOccurrenceAt.Add(-7 * 24 * time.Hour)
+	task := &entity.OJTask{
+		VersionNo:        1,
+		Title:            "每周一题单",
+		Mode:             string(consts.OJTaskModeRecurring),
+		Status:           string(consts.OJTaskStatusScheduled),
+		ExecuteAt:        &firstAt,
+		RecurrenceRule:   "0 8 * * 1",
+		RecurrenceEndAt:  &endAt,
+		NextOccurrenceAt: &nextOccurrenceAt,
+		CreatedBy:        1,
+		UpdatedBy:        1,
+	}
+	mustCreate(t, db, task)
+	mustCreate(t, db, &entity.OJTaskOrg{TaskID: task.ID, OrgID: 10})
+	mustCreate(t, db, &entity.OJTaskExecution{
+		TaskID:             task.ID,
+		OccurrenceNo:       1,
+		TriggerType:        string(consts.OJTaskExecutionTriggerRecurrenceDue),
+		PlannedAt:          firstAt,
+		RequestedBy:        1,
+		Status:             firstStatus,
+		TotalUserCount:     2,
+		CompletedUserCount: 1,
+	})
+	return task
+}
@@ -23,12 +23,17 @@ type normalizedOJTaskItem struct {
 
 // normalizedOJTaskDraft 表示完成入参校验后的任务草稿。
 type normalizedOJTaskDraft struct {
-	Title       string
-	Description string
-	Mode        string
-	ExecuteAt   *time.Time
-	OrgIDs      []uint
-	Items       []normalizedOJTaskItem
+	Title            string
+	Description      string
+	Mode             string
+	ExecuteAt        *time.Time
+	RecurrenceRule   string
+	RecurrenceEndAt  *time.Time
+	NextOccurrenceAt *time.Time
+	DeadlineAt       *time.Time
+	DeadlineMinutes  int
+	OrgIDs           []uint
+	Items            []normalizedOJTaskItem
 }
 
 // ojTaskAnalyzeCandidate 统一承载单平台精确命中的候选题目。
@@ -89,6 +94,10 @@ func (s *OJTaskService) validateDraft(
 	ctx context.Context,
 	title, description, mode string,
 	executeAt *time.Time,
+	recurrenceRule string,
+	recurrenceEndAt *time.Time,
+	deadlineAt *time.Time,
+	deadlineMinutes int,
 	orgIDs []uint,
 	items []request.OJTaskItemReq,
 ) (normalizedOJTaskDraft, error) {
@@ -103,7 +112,17 @@ func (s *OJTaskService) validateDraft(
 	}
 
 	now := time.Now()
-	var normalizedExecuteAt *time.Time
+	if normalizedMode != string(consts.OJTaskModeRecurring) &&
+		(strings.TrimSpace(recurrenceRule) != "" || recurrenceEndAt != nil) {
+		return normalizedOJTaskDraft{}, bizerrors.NewWithMsg(
+			bizerrors.CodeOJTaskRecurrenceInvalid,
+			"非周期任务不允许传 recurrence_rule / recurrence_end_at",
+		)
+	}
+	var (
+		normalizedExecuteAt *time.Time
+		recurrence          ojTaskRecurrencePlan
+	)
 	switch normalizedMode {
 	case string(consts.OJTaskModeImmediate):
 		if executeAt != nil {
@@ -127,6 +146,23 @@ func (s *OJTaskService) validateDraft(
 		}
 		value := executeAt.UTC()
 		normalizedExecuteAt = &value
+	case string(consts.OJTaskModeRecurring):
+		if executeAt != nil {
+			return normalizedOJTaskDraft{}, bizerrors.NewWithMsg(
+				bizerrors.CodeOJTaskExecuteAtInvalid,
+				"周期任务由 recurrence_rule 推算执行时间，不允许传 execute_at",
+			)
+		}
+		plan, err := planOJTaskRecurrence(recurrenceRule, recurrenceEndAt, now)
+		if err != nil {
+			return normalizedOJTaskDraft{}, err
+		}
+		recurrence = plan
+		normalizedExecuteAt = &recurrence.FirstOccurrence
+	}
+	normalizedDeadlineAt, err := validateOJTaskDeadline(normalizedMode, normalizedExecuteAt, deadlineAt, deadlineMinutes, now)
+	if err != nil {
+		return normalizedOJTaskDraft{}, err
 	}
 
 	validatedOrgIDs, err := s.validateOrgIDs(ctx, orgIDs)
@@ -138,14 +174,23 @@ func (s *OJTaskService) validateDraft(
 		return normalizedOJTaskDraft{}, err
 	}
 
-	return normalizedOJTaskDraft{
-		Title:       trimmedTitle,
-		Description: strings.TrimSpace(description),
-		Mode:        normalizedMode,
-		ExecuteAt:   normalizedExecuteAt,
-		OrgIDs:      validatedOrgIDs,
-		Items:       normalizedItems,
-	}, nil
+	draft := normalizedOJTaskDraft{
+		Title:           trimmedTitle,
+		Description:     strings.TrimSpace(description),
+		Mode:            normalizedMode,
+		ExecuteAt:       normalizedExecuteAt,
+		DeadlineAt:      normalizedDeadlineAt,
+		DeadlineMinutes: deadlineMinutes,
+		OrgIDs:          validatedOrgIDs,
+		Items:           normalizedItems,
+	}
+	if normalizedMode == string(consts.OJTaskModeRecurring) {
+		endAt := recurrence.EndAt
+		draft.RecurrenceRule = recurrence.Rule
+		draft.RecurrenceEndAt = &endAt
+		draft.NextOccurrenceAt = recurrence.NextOccurrenceAt
+	}
+	return draft, nil
 }
 
 func (s *OJTaskService) validateItems(items []request.OJTaskItemReq) ([]normalizedOJTaskItem, error) {
@@ -180,12 +225,17 @@ func (s *OJTaskService) materializeDraftTx(
 		return validatedOJTaskDraft{}, err
 	}
 	return validatedOJTaskDraft{
-		Title:       draft.Title,
-		Description: draft.Description,
-		Mode:        draft.Mode,
-		ExecuteAt:   draft.ExecuteAt,
-		OrgIDs:      draft.OrgIDs,
-		Items:       items,
+		Title:            draft.Title,
+		Description:      draft.Description,
+		Mode:             draft.Mode,
+		ExecuteAt:        draft.ExecuteAt,
+		RecurrenceRule:   draft.RecurrenceRule,
+		RecurrenceEndAt:  draft.RecurrenceEndAt,
+		NextOccurrenceAt: draft.NextOccurrenceAt,
+		DeadlineAt:       draft.DeadlineAt,
+		DeadlineMinutes:  draft.DeadlineMinutes,
+		OrgIDs:           draft.OrgIDs,
+		Items:            items,
 	}, nil
 }
 
@@ -317,8 +367,40 @@ func (s *OJTaskService) findExactTitleCandidates(
 		}
 		return mapLanqiaoCandidates(rows), nil
 	default:
+		return s.findProviderTitleCandidates(ctx, tx, platform, title)
+	}
+}
+
+// findProviderTitleCandidates 在扩展平台题库中查找候选题目。
+// 除了按标题精确匹配，题单里直接写平台题号（如 "1791C"、"abc300_a"）时也按题号命中。
+func (s *OJTaskService) findProviderTitleCandidates(
+	ctx context.Context,
+	tx any,
+	platform string,
+	title string,
+) ([]ojTaskAnalyzeCandidate, error) {
+	provider, ok := lookupOJPlatformProvider(platform)
+	if !ok {
 		return nil, bizerrors.New(bizerrors.CodeInvalidParams)
 	}
+	repo := s.providerQuestionRepo
+	if tx != nil {
+		repo = repo.WithTx(tx)
+	}
+	rows, err := repo.ListByExactTitle(ctx, provider.Platform(), title)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if code, ok := provider.ParseQuestionCode(title); ok {
+		row, err := repo.GetByCode(ctx, provider.Platform(), code)
+		if err != nil {
+			return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		if row != nil {
+			rows = append(rows, row)
+		}
+	}
+	return mapProviderCandidates(rows), nil
 }
 
 func mapLuoguCandidates(rows []*entity.LuoguQuestionBank) []ojTaskAnalyzeCandidate {
@@ -369,6 +451,22 @@ func mapLanqiaoCandidates(rows []*entity.LanqiaoQuestionBank) []ojTaskAnalyzeCan
 	return candidates
 }
 
+func mapProviderCandidates(rows []*entity.OJProviderQuestionBank) []ojTaskAnalyzeCandidate {
+	candidates := make([]ojTaskAnalyzeCandidate, 0, len(rows))
+	for _, row := range rows {
+		if row == nil || row.ID == 0 || row.SourceStatus != int8(consts.OJQuestionSourceStatusVerified) {
+			continue
+		}
+		candidates = append(candidates, ojTaskAnalyzeCandidate{
+			Platform:     row.Platform,
+			QuestionID:   row.ID,
+			QuestionCode: row.ProblemCode,
+			Title:        row.Title,
+		})
+	}
+	return candidates
+}
+
 func (s *OJTaskService) getVerifiedCandidateByID(
 	ctx context.Context,
 	tx any,
@@ -434,7 +532,27 @@ func (s *OJTaskService) getVerifiedCandidateByID(
 			Title:        row.Title,
 		}, nil
 	default:
-		return ojTaskAnalyzeCandidate{}, bizerrors.New(bizerrors.CodeInvalidParams)
+		if _, ok := lookupOJPlatformProvider(platform); !ok {
+			return ojTaskAnalyzeCandidate{}, bizerrors.New(bizerrors.CodeInvalidParams)
+		}
+		repo := s.providerQuestionRepo
+		if tx != nil {
+			repo = repo.WithTx(tx)
+		}
+		row, err := repo.GetByID(ctx, questionID)
+		if err != nil {
+			return ojTaskAnalyzeCandidate{}, bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		// 扩展平台共用一张题库表，必须校验题目归属平台，防止跨平台串用 question_id。
+		if row == nil || row.ID == 0 || row.Platform != platform || row.SourceStatus != int8(consts.OJQuestionSourceStatusVerified) {
+			return ojTaskAnalyzeCandidate{}, bizerrors.New(bizerrors.CodeOJTaskQuestionNotFound)
+		}
+		return ojTaskAnalyzeCandidate{
+			Platform:     platform,
+			QuestionID:   row.ID,
+			QuestionCode: row.ProblemCode,
+			Title:        row.Title,
+		}, nil
 	}
 }
 
@@ -0,0 +1,168 @@
+package system
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"time"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	resp "personal_assistant/internal/model/dto/response"
+	bizerrors "personal_assistant/pkg/errors"
+	"personal_assistant/pkg/redislock"
+
+	"go.uber.org/zap"
+)
+
+const ojUnbindLockTTL = 10 * time.Second
+
+// UnbindOJAccount 解绑指定平台的 OJ 账号。
+// 账号详情与过题关系会被物理删除，排行榜与每日统计通过既有投影事件回源重建；
+// 任务执行快照（OJTaskExecutionUserItem）只引用用户与任务项，不受解绑影响。
+func (s *OJService) UnbindOJAccount(
+	ctx context.Context,
+	userID uint,
+	req *request.UnbindOJAccountReq,
+) (*resp.UnbindOJAccountResp, error) {
+	if req == nil {
+		return nil, bizerrors.New(bizerrors.CodeInvalidParams)
+	}
+	if userID == 0 {
+		return nil, bizerrors.New(bizerrors.CodeLoginRequired)
+	}
+
+	platform := strings.ToLower(strings.TrimSpace(req.Platform))
+	var (
+		identifier string
+		err        error
+	)
+	if unbind, ok := ojBuiltinUnbinders[platform]; ok {
+		identifier, err = unbind(s, ctx, userID)
+	} else {
+		provider, ok := lookupOJPlatformProvider(platform)
+		if !ok {
+			return nil, bizerrors.New(bizerrors.CodeOJPlatformInvalid)
+		}
+		identifier, err = s.unbindProviderAccount(ctx, userID, provider.Platform())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 数据已经删除，投影事件发布失败只记录日志，由定时修复任务兜底收敛。
+	if err := s.publishOJProfileProjectionEvent(ctx, userID, platform); err != nil {
+		global.Log.Error("failed to publish oj profile projection event after unbind",
+			zap.Uint("user_id", userID),
+			zap.String("platform", platform),
+			zap.Error(err))
+	}
+	if err := s.publishOJDailyStatsProjectionEvent(ctx, userID, platform, true); err != nil {
+		global.Log.Error("failed to publish oj daily stats projection event after unbind",
+			zap.Uint("user_id", userID),
+			zap.String("platform", platform),
+			zap.Error(err))
+	}
+
+	return &resp.UnbindOJAccountResp{
+		Platform:   platform,
+		Identifier: identifier,
+	}, nil
+}
+
+// ojBuiltinUnbinders 是内置平台的解绑实现；扩展平台统一走 unbindProviderAccount。
+var ojBuiltinUnbinders = map[string]func(s *OJService, ctx context.Context, userID uint) (string, error){
+	consts.OJPlatformLeetcode: (*OJService).unbindLeetcode,
+	consts.OJPlatformLuogu:    (*OJService).unbindLuogu,
+	consts.OJPlatformLanqiao:  (*OJService).unbindLanqiao,
+}
+
+func (s *OJService) unbindLeetcode(ctx context.Context, userID uint) (string, error) {
+	existing, err := s.leetcodeRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return "", bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if existing == nil {
+		return "", bizerrors.New(bizerrors.CodeOJAccountNotBound)
+	}
+	lockKey := redislock.LockKeyLeetcodeSyncSingleUser(existing.UserSlug)
+	if err := purgeOJAccountLocked(ctx, lockKey, func() error {
+		return s.leetcodeRepo.PurgeByUserID(ctx, userID)
+	}); err != nil {
+		return "", err
+	}
+	return existing.UserSlug, nil
+}
+
+func (s *OJService) unbindLuogu(ctx context.Context, userID uint) (string, error) {
+	existing, err := s.luoguRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return "", bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if existing == nil {
+		return "", bizerrors.New(bizerrors.CodeOJAccountNotBound)
+	}
+	lockKey := redislock.LockKeyLuoguSyncSingleUser(existing.Identification)
+	if err := purgeOJAccountLocked(ctx, lockKey, func() error {
+		return s.luoguRepo.PurgeByUserID(ctx, userID)
+	}); err != nil {
+		return "", err
+	}
+	return existing.Identification, nil
+}
+
+func (s *OJService) unbindLanqiao(ctx context.Context, userID uint) (string, error) {
+	existing, err := s.lanqiaoRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return "", bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if existing == nil {
+		return "", bizerrors.New(bizerrors.CodeOJAccountNotBound)
+	}
+	lockKey := redislock.LockKeyLanqiaoSyncSingleUser(userID)
+	if err := purgeOJAccountLocked(ctx, lockKey, func() error {
+		return s.lanqiaoRepo.PurgeByUserID(ctx, userID)
+	}); err != nil {
+		return "", err
+	}
+	// 失败计数与自动停同步标记只对旧账号有意义，解绑后一并清理，避免重新绑定时误报告警。
+	if err := s.clearLanqiaoRuntimeState(ctx, userID); err != nil {
+		global.Log.Warn("failed to clear lanqiao runtime state", zap.Uint("user_id", userID), zap.Error(err))
+	}
+	return existing.MaskedPhone, nil
+}
+
+func (s *OJService) unbindProviderAccount(ctx context.Context, userID uint, platform string) (string, error) {
+	existing, err := s.providerDetailRepo.GetByUserPlatform(ctx, userID, platform)
+	if err != nil {
+		return "", bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if existing == nil {
+		return "", bizerrors.New(bizerrors.CodeOJAccountNotBound)
+	}
+	lockKey := redislock.LockKeyOJProviderSyncSingleUser(platform, userID)
+	if err := purgeOJAccountLocked(ctx, lockKey, func() error {
+		return s.providerDetailRepo.DeleteByUserPlatform(ctx, userID, platform)
+	}); err != nil {
+		return "", err
+	}
+	return existing.Identifier, nil
+}
+
+// purgeOJAccountLocked 在单用户同步锁内执行删除，避免与正在进行的同步或绑定交错写入。
+func purgeOJAccountLocked(ctx context.Context, lockKey string, purge func() error) error {
+	err := redislock.WithLock(ctx, lockKey, ojUnbindLockTTL, func() error {
+		if err := purge(); err != nil {
+			return bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		return nil
+	})
+	if err == nil || bizerrors.FromError(err) != nil {
+		return err
+	}
+	if stderrors.Is(err, redislock.ErrLockFailed) {
+		return bizerrors.NewWithMsg(bizerrors.CodeTooManyRequests, "账号正在同步中，请稍后再试")
+	}
+	return bizerrors.Wrap(bizerrors.CodeInternalError, err)
+}
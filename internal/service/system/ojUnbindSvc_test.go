@@ -0,0 +1,237 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/consts"
+	eventdto "personal_assistant/internal/model/dto/event"
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/model/entity"
+	reposystem "personal_assistant/internal/repository/system"
+	svccontract "personal_assistant/internal/service/contract"
+	bizerrors "personal_assistant/pkg/errors"
+	"personal_assistant/pkg/rediskey"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestUnbindOJAccountPurgesLeetcodeFactsAndKeepsTaskSnapshots(t *testing.T) {
+	svc, db, cachePublisher, dailyStatsSvc := newOJUnbindTestService(t)
+	ctx := context.Background()
+
+	question := &entity.LeetcodeQuestionBank{TitleSlug: "two-sum", Title: "Two Sum"}
+	mustCreate(t, db, question)
+	// 历史换绑遗留的软删除详情也应被一并清理。
+	stale := &entity.LeetcodeUserDetail{UserSlug: "old-slug", UserID: 1}
+	mustCreate(t, db, stale)
+	mustCreate(t, db, &entity.LeetcodeUserQuestion{LeetcodeUserDetailID: stale.ID, LeetcodeQuestionID: question.ID})
+	if err := db.Delete(stale).Error; err != nil {
+		t.Fatalf("soft delete stale detail: %v", err)
+	}
+	detail := &entity.LeetcodeUserDetail{UserSlug: "typo-slug", UserID: 1, TotalNumber: 1}
+	mustCreate(t, db, detail)
+	mustCreate(t, db, &entity.LeetcodeUserQuestion{LeetcodeUserDetailID: detail.ID, LeetcodeQuestionID: question.ID})
+	snapshot := &entity.OJTaskExecutionUserItem{
+		ExecutionID:     1,
+		UserID:          1,
+		ExecutionUserID: 1,
+		TaskItemID:      1,
+		ResultStatus:    "completed",
+	}
+	mustCreate(t, db, snapshot)
+
+	out, err := svc.UnbindOJAccount(ctx, 1, &request.UnbindOJAccountReq{Platform: " LeetCode "})
+	if err != nil {
+		t.Fatalf("UnbindOJAccount() error = %v", err)
+	}
+	if out.Platform != "leetcode" || out.Identifier != "typo-slug" {
+		t.Fatalf("UnbindOJAccount() = %+v", out)
+	}
+
+	assertUnscopedCount(t, db, &entity.LeetcodeUserDetail{}, "user_id = ?", 1, 0)
+	assertUnscopedCount(t, db, &entity.LeetcodeUserQuestion{}, "1 = 1", nil, 0)
+	assertUnscopedCount(t, db, &entity.LeetcodeQuestionBank{}, "1 = 1", nil, 1)
+	assertUnscopedCount(t, db, &entity.OJTaskExecutionUserItem{}, "user_id = ? AND result_status = 'completed'", 1, 1)
+
+	if len(cachePublisher.events) != 1 || cachePublisher.events[0].Kind != eventdto.CacheProjectionKindOJProfileChanged {
+		t.Fatalf("cache projection events = %+v, want one oj_profile_changed", cachePublisher.events)
+	}
+	if len(dailyStatsSvc.events) != 1 ||
+		dailyStatsSvc.events[0].Kind != eventdto.OJDailyStatsProjectionKindResetAndRebuildRecentWindow ||
+		dailyStatsSvc.events[0].Platform != "leetcode" {
+		t.Fatalf("daily stats events = %+v, want one reset event for leetcode", dailyStatsSvc.events)
+	}
+
+	_, err = svc.UnbindOJAccount(ctx, 1, &request.UnbindOJAccountReq{Platform: "leetcode"})
+	if !hasBizCode(err, bizerrors.CodeOJAccountNotBound) {
+		t.Fatalf("second unbind error = %v, want CodeOJAccountNotBound", err)
+	}
+}
+
+func TestUnbindOJAccountReleasesLanqiaoCredentialAndRuntimeState(t *testing.T) {
+	svc, db, _, _ := newOJUnbindTestService(t)
+	ctx := context.Background()
+
+	detail := &entity.LanqiaoUserDetail{CredentialHash: "hash-1", MaskedPhone: "138****0000", UserID: 2}
+	mustCreate(t, db, detail)
+	question := &entity.LanqiaoQuestionBank{ProblemID: 1001, Title: "门牌制作"}
+	mustCreate(t, db, question)
+	mustCreate(t, db, &entity.LanqiaoUserQuestion{
+		LanqiaoUserDetailID: detail.ID,
+		LanqiaoQuestionID:   question.ID,
+		SolvedAt:            time.Now(),
+	})
+	if err := global.Redis.Set(ctx, rediskey.LanqiaoSyncDisableKey(2), "credential_invalid", 0).Err(); err != nil {
+		t.Fatalf("seed disable key: %v", err)
+	}
+
+	out, err := svc.UnbindOJAccount(ctx, 2, &request.UnbindOJAccountReq{Platform: "lanqiao"})
+	if err != nil {
+		t.Fatalf("UnbindOJAccount() error = %v", err)
+	}
+	if out.Identifier != "138****0000" {
+		t.Fatalf("identifier = %q, want masked phone", out.Identifier)
+	}
+	assertUnscopedCount(t, db, &entity.LanqiaoUserQuestion{}, "1 = 1", nil, 0)
+	if exists, err := global.Redis.Exists(ctx, rediskey.LanqiaoSyncDisableKey(2)).Result(); err != nil || exists != 0 {
+		t.Fatalf("disable key exists = %d, %v; want cleared", exists, err)
+	}
+
+	// 同一蓝桥账号重新绑定时，credential_hash 唯一索引不应再被旧记录占用。
+	if err := db.Create(&entity.LanqiaoUserDetail{CredentialHash: "hash-1", UserID: 2}).Error; err != nil {
+		t.Fatalf("recreate detail with same credential hash: %v", err)
+	}
+}
+
+func TestUnbindOJAccountRemovesProviderAccount(t *testing.T) {
+	svc, db, _, dailyStatsSvc := newOJUnbindTestService(t)
+	provider := &fakeOJPlatformProvider{
+		platform: consts.OJPlatformCodeforces,
+		profile:  &ojProviderProfile{Identifier: "tourist"},
+		batch: &ojProviderSolvedBatch{
+			Cursor:   100,
+			Problems: []ojProviderSolvedProblem{{Code: "1A", Title: "Theatre Square", SolvedAt: time.Unix(100, 0)}},
+		},
+	}
+	if _, err := svc.bindProviderAccount(context.Background(), 3, provider, "tourist"); err != nil {
+		t.Fatalf("bindProviderAccount() error = %v", err)
+	}
+	dailyStatsSvc.events = nil
+
+	out, err := svc.UnbindOJAccount(context.Background(), 3, &request.UnbindOJAccountReq{Platform: consts.OJPlatformCodeforces})
+	if err != nil {
+		t.Fatalf("UnbindOJAccount() error = %v", err)
+	}
+	if out.Identifier != "tourist" {
+		t.Fatalf("identifier = %q, want tourist", out.Identifier)
+	}
+	assertUnscopedCount(t, db, &entity.OJProviderUserDetail{}, "user_id = ?", 3, 0)
+	assertUnscopedCount(t, db, &entity.OJProviderUserQuestion{}, "1 = 1", nil, 0)
+	if len(dailyStatsSvc.events) != 1 || dailyStatsSvc.events[0].Platform != consts.OJPlatformCodeforces {
+		t.Fatalf("daily stats events = %+v, want one codeforces reset event", dailyStatsSvc.events)
+	}
+}
+
+func TestUnbindOJAccountRejectsUnknownPlatform(t *testing.T) {
+	svc, _, _, _ := newOJUnbindTestService(t)
+
+	_, err := svc.UnbindOJAccount(context.Background(), 1, &request.UnbindOJAccountReq{Platform: "hdu"})
+	if !hasBizCode(err, bizerrors.CodeOJPlatformInvalid) {
+		t.Fatalf("UnbindOJAccount() error = %v, want CodeOJPlatformInvalid", err)
+	}
+}
+
+func newOJUnbindTestService(
+	t *testing.T,
+) (*OJService, *gorm.DB, *stubCacheProjectionPublisher, *stubOJDailyStatsProjectionSvc) {
+	t.Helper()
+	setupRankingRedis(t)
+
+	oldConfig := global.Config
+	oldLog := global.Log
+	global.Config = &config.Config{System: config.System{BindCoolDownHours: 24}}
+	global.Log = zap.NewNop()
+	t.Cleanup(func() {
+		global.Config = oldConfig
+		global.Log = oldLog
+	})
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&entity.LeetcodeUserDetail{},
+		&entity.LeetcodeQuestionBank{},
+		&entity.LeetcodeUserQuestion{},
+		&entity.LanqiaoUserDetail{},
+		&entity.LanqiaoQuestionBank{},
+		&entity.LanqiaoUserQuestion{},
+		&entity.OJProviderUserDetail{},
+		&entity.OJProviderQuestionBank{},
+		&entity.OJProviderUserQuestion{},
+		&entity.OJTaskExecutionUserItem{},
+	); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+
+	cachePublisher := &stubCacheProjectionPublisher{}
+	dailyStatsSvc := &stubOJDailyStatsProjectionSvc{}
+	svc := &OJService{
+		txRunner:                  &stubTxRunner{},
+		leetcodeRepo:              reposystem.NewLeetcodeUserDetailRepository(db),
+		luoguRepo:                 reposystem.NewLuoguUserDetailRepository(db),
+		lanqiaoRepo:               reposystem.NewLanqiaoUserDetailRepository(db),
+		providerDetailRepo:        reposystem.NewOJProviderUserDetailRepository(db),
+		providerQuestionBankRepo:  reposystem.NewOJProviderQuestionBankRepository(db),
+		providerUserQuestionRepo:  reposystem.NewOJProviderUserQuestionRepository(db),
+		cacheProjectionPublisher:  cachePublisher,
+		questionUpsertPublisher:   &stubQuestionUpsertPublisher{},
+		ojDailyStatsProjectionSvc: dailyStatsSvc,
+	}
+	return svc, db, cachePublisher, dailyStatsSvc
+}
+
+type stubOJDailyStatsProjectionSvc struct {
+	svccontract.OJDailyStatsProjectionServiceContract
+	events []*eventdto.OJDailyStatsProjectionEvent
+}
+
+func (s *stubOJDailyStatsProjectionSvc) PublishOJDailyStatsProjectionEvent(
+	_ context.Context,
+	event *eventdto.OJDailyStatsProjectionEvent,
+) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func mustCreate(t *testing.T, db *gorm.DB, value any) {
+	t.Helper()
+	if err := db.Create(value).Error; err != nil {
+		t.Fatalf("create %T: %v", value, err)
+	}
+}
+
+func assertUnscopedCount(t *testing.T, db *gorm.DB, model any, query string, arg any, want int64) {
+	t.Helper()
+	tx := db.Unscoped().Model(model)
+	if arg != nil {
+		tx = tx.Where(query, arg)
+	} else {
+		tx = tx.Where(query)
+	}
+	var count int64
+	if err := tx.Count(&count).Error; err != nil {
+		t.Fatalf("count %T: %v", model, err)
+	}
+	if count != want {
+		t.Fatalf("count %T = %d, want %d", model, count, want)
+	}
+}
@@ -29,6 +29,7 @@ type OrgService struct {
 	userRepo                 interfaces.UserRepository
 	roleRepo                 interfaces.RoleRepository
 	imageRepo                interfaces.ImageRepository
+	orgInvitationRepo        interfaces.OrgInvitationRepository
 	authorizationService     svccontract.AuthorizationServiceContract
 	permissionProjectionSvc  svccontract.PermissionProjectionServiceContract
 	cacheProjectionPublisher cacheProjectionEventPublisher
@@ -47,6 +48,7 @@ func NewOrgService(
 		userRepo:                repositoryGroup.SystemRepositorySupplier.GetUserRepository(),
 		roleRepo:                repositoryGroup.SystemRepositorySupplier.GetRoleRepository(),
 		imageRepo:               repositoryGroup.SystemRepositorySupplier.GetImageRepository(),
+		orgInvitationRepo:       repositoryGroup.SystemRepositorySupplier.GetOrgInvitationRepository(),
 		authorizationService:    authorizationService,
 		permissionProjectionSvc: permissionProjectionSvc,
 		cacheProjectionPublisher: newCacheProjectionOutboxPublisher(
@@ -629,22 +631,35 @@ func (s *OrgService) JoinOrgByInviteCode(ctx context.Context, userID uint, invit
 	if inviteCode == "" {
 		return errors.New(errors.CodeInvalidParams)
 	}
-	user, err := s.userRepo.GetByID(ctx, userID)
+	org, err := s.orgRepo.GetByCode(ctx, inviteCode)
 	if err != nil {
 		return errors.Wrap(errors.CodeDBError, err)
 	}
-	if user == nil {
-		return errors.New(errors.CodeUserNotFound)
+	if org == nil {
+		return errors.New(errors.CodeInviteCodeInvalid)
 	}
-	oldCurrentOrgID := cloneUintPtr(user.CurrentOrgID)
+	return s.joinOrg(ctx, userID, org, string(consts.OrgMemberJoinSourceInvite), true, nil)
+}
 
-	org, err := s.orgRepo.GetByCode(ctx, inviteCode)
+// joinOrg 把用户加入组织：首次加入或 left/removed 恢复为 active 时仅授予默认角色，已是活跃成员时幂等。
+// beforeJoin 在同一事务内先执行（如扣减邀请次数、写入审批结果），返回错误时整体回滚；
+// switchCurrentOrg 为 true 时同时切换用户的当前组织，管理员审批通过等非本人操作不切换。
+func (s *OrgService) joinOrg(
+	ctx context.Context,
+	userID uint,
+	org *entity.Org,
+	joinSource string,
+	switchCurrentOrg bool,
+	beforeJoin func(tx any) error,
+) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return errors.Wrap(errors.CodeDBError, err)
 	}
-	if org == nil {
-		return errors.New(errors.CodeInviteCodeInvalid)
+	if user == nil {
+		return errors.New(errors.CodeUserNotFound)
 	}
+	oldCurrentOrgID := cloneUintPtr(user.CurrentOrgID)
 
 	shouldSyncSubject := false
 	if err := s.txRunner.InTx(ctx, func(tx any) error {
@@ -653,6 +668,12 @@ func (s *OrgService) JoinOrgByInviteCode(ctx context.Context, userID uint, invit
 		txUserRepo := s.userRepo.WithTx(tx)
 		shouldResetDefaultRole := false
 
+		if beforeJoin != nil {
+			if err := beforeJoin(tx); err != nil {
+				return err
+			}
+		}
+
 		member, err := txOrgMemberRepo.GetByOrgAndUserForUpdate(ctx, org.ID, userID)
 		if err != nil {
 			return errors.Wrap(errors.CodeDBError, err)
@@ -665,7 +686,7 @@ func (s *OrgService) JoinOrgByInviteCode(ctx context.Context, userID uint, invit
 				UserID:       userID,
 				MemberStatus: consts.OrgMemberStatusActive,
 				JoinedAt:     time.Now(),
-				JoinSource:   string(consts.OrgMemberJoinSourceInvite),
+				JoinSource:   joinSource,
 			}); err != nil {
 				return errors.Wrap(errors.CodeDBError, err)
 			}
@@ -680,7 +701,7 @@ func (s *OrgService) JoinOrgByInviteCode(ctx context.Context, userID uint, invit
 				consts.OrgMemberStatusActive,
 				nil,
 				"",
-				string(consts.OrgMemberJoinSourceInvite),
+				joinSource,
 			); err != nil {
 				return errors.Wrap(errors.CodeDBError, err)
 			}
@@ -705,6 +726,19 @@ func (s *OrgService) JoinOrgByInviteCode(ctx context.Context, userID uint, invit
 			}
 		}
 
+		if !switchCurrentOrg {
+			if !shouldResetDefaultRole {
+				return nil
+			}
+			if err := s.publishCacheProjectionInTx(
+				ctx,
+				tx,
+				newUserSnapshotProjectionEvent(userID, []uint{org.ID}),
+			); err != nil {
+				return errors.Wrap(errors.CodeDBError, err)
+			}
+			return nil
+		}
 		if err := txUserRepo.UpdateCurrentOrgID(ctx, userID, &org.ID); err != nil {
 			return errors.Wrap(errors.CodeDBError, err)
 		}
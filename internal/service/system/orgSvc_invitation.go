@@ -0,0 +1,436 @@
+package system
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/pkg/errors"
+)
+
+const (
+	orgInvitationTokenBytes   = 32
+	orgInvitationTokenHintLen = 6
+
+	orgInvitationStatusActive    = "active"
+	orgInvitationStatusExpired   = "expired"
+	orgInvitationStatusExhausted = "exhausted"
+	orgInvitationStatusRevoked   = "revoked"
+
+	orgInvitationAcceptJoined  = "joined"
+	orgInvitationAcceptPending = "pending"
+)
+
+// CreateOrgInvitation 创建组织邀请，令牌明文只在本次返回，库中仅保存摘要。
+func (s *OrgService) CreateOrgInvitation(
+	ctx context.Context,
+	operatorID, orgID uint,
+	req *request.CreateOrgInvitationReq,
+) (*response.OrgInvitationCreatedResp, error) {
+	if req == nil {
+		return nil, errors.New(errors.CodeInvalidParams)
+	}
+	org, err := s.getInvitableOrg(ctx, operatorID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	maxUses := req.MaxUses
+	if maxUses <= 0 {
+		maxUses = 1
+	}
+	ttlHours := req.ExpiresInHours
+	if ttlHours <= 0 {
+		ttlHours = consts.OrgInvitationDefaultTTLHours
+	}
+	if maxUses > consts.OrgInvitationMaxUses || ttlHours > consts.OrgInvitationMaxTTLHours {
+		return nil, errors.New(errors.CodeInvalidParams)
+	}
+
+	targetEmail := strings.ToLower(strings.TrimSpace(req.TargetEmail))
+	if targetEmail != "" {
+		// 邮箱不是注册必填项，手机号注册的账号需先绑定邮箱，否则定向邀请永远无法命中。
+		exists, err := s.userRepo.ExistsByEmail(ctx, targetEmail)
+		if err != nil {
+			return nil, errors.Wrap(errors.CodeDBError, err)
+		}
+		if !exists {
+			return nil, errors.New(errors.CodeOrgInvitationEmailUnbound)
+		}
+	}
+
+	token, err := newOrgInvitationToken()
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeInternalError, err)
+	}
+	invitation := &entity.OrgInvitation{
+		OrgID:           org.ID,
+		CreatedBy:       operatorID,
+		TokenHash:       hashOrgInvitationToken(token),
+		TokenHint:       token[:orgInvitationTokenHintLen],
+		MaxUses:         maxUses,
+		ExpiresAt:       time.Now().Add(time.Duration(ttlHours) * time.Hour),
+		TargetEmail:     targetEmail,
+		TargetPhone:     strings.TrimSpace(req.TargetPhone),
+		RequireApproval: req.RequireApproval,
+		Note:            strings.TrimSpace(req.Note),
+	}
+	if err := s.orgInvitationRepo.Create(ctx, invitation); err != nil {
+		return nil, errors.Wrap(errors.CodeDBError, err)
+	}
+	return &response.OrgInvitationCreatedResp{
+		OrgInvitationItem: toOrgInvitationItem(invitation, time.Now()),
+		Token:             token,
+	}, nil
+}
+
+// ListOrgInvitations 列出组织的全部邀请（含已失效），不返回令牌明文。
+func (s *OrgService) ListOrgInvitations(
+	ctx context.Context,
+	operatorID, orgID uint,
+) ([]*response.OrgInvitationItem, error) {
+	if _, err := s.getInvitableOrg(ctx, operatorID, orgID); err != nil {
+		return nil, err
+	}
+	invitations, err := s.orgInvitationRepo.ListByOrg(ctx, orgID)
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeDBError, err)
+	}
+	now := time.Now()
+	items := make([]*response.OrgInvitationItem, 0, len(invitations))
+	for _, invitation := range invitations {
+		item := toOrgInvitationItem(invitation, now)
+		items = append(items, &item)
+	}
+	return items, nil
+}
+
+// RevokeOrgInvitation 撤销邀请；已撤销时幂等，已提交的待审批申请不受影响。
+func (s *OrgService) RevokeOrgInvitation(ctx context.Context, operatorID, orgID, invitationID uint) error {
+	if _, err := s.getInvitableOrg(ctx, operatorID, orgID); err != nil {
+		return err
+	}
+	invitation, err := s.orgInvitationRepo.GetByID(ctx, invitationID)
+	if err != nil {
+		return errors.Wrap(errors.CodeDBError, err)
+	}
+	if invitation == nil || invitation.OrgID != orgID {
+		return errors.New(errors.CodeOrgInvitationInvalid)
+	}
+	if _, err := s.orgInvitationRepo.Revoke(ctx, invitationID, operatorID, time.Now()); err != nil {
+		return errors.Wrap(errors.CodeDBError, err)
+	}
+	return nil
+}
+
+// AcceptOrgInvitation 用户接受邀请。
+// 已是活跃成员时幂等且不占用次数；需要审批的邀请只生成待审批申请，审批通过后才加入。
+func (s *OrgService) AcceptOrgInvitation(
+	ctx context.Context,
+	userID uint,
+	token string,
+) (*response.OrgInvitationAcceptResp, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, errors.New(errors.CodeInvalidParams)
+	}
+	invitation, err := s.orgInvitationRepo.GetByTokenHash(ctx, hashOrgInvitationToken(token))
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeDBError, err)
+	}
+	if invitation == nil {
+		return nil, errors.New(errors.CodeOrgInvitationInvalid)
+	}
+	org, err := s.orgRepo.GetByID(ctx, invitation.OrgID)
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeDBError, err)
+	}
+	if org == nil {
+		return nil, errors.New(errors.CodeOrgInvitationInvalid)
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeDBError, err)
+	}
+	if user == nil {
+		return nil, errors.New(errors.CodeUserNotFound)
+	}
+
+	result := &response.OrgInvitationAcceptResp{
+		OrgID:   org.ID,
+		OrgName: org.Name,
+		Status:  orgInvitationAcceptJoined,
+	}
+	// 已是活跃成员时直接返回，重复打开已用完的单次链接不报错
+	active, err := s.orgMemberRepo.IsUserActiveInOrg(ctx, userID, org.ID)
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeDBError, err)
+	}
+	if active {
+		return result, nil
+	}
+	now := time.Now()
+	if orgInvitationStatus(invitation, now) != orgInvitationStatusActive {
+		return nil, errors.New(errors.CodeOrgInvitationInvalid)
+	}
+	if !orgInvitationMatchesUser(invitation, user) {
+		return nil, errors.New(errors.CodeOrgInvitationNotForYou)
+	}
+
+	if !invitation.RequireApproval {
+		err := s.joinOrg(ctx, userID, org, consts.OrgInvitationJoinSource(invitation.ID), true, func(tx any) error {
+			return s.consumeOrgInvitation(ctx, tx, invitation.ID, now)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	result.Status = orgInvitationAcceptPending
+	if err := s.txRunner.InTx(ctx, func(tx any) error {
+		txInvitationRepo := s.orgInvitationRepo.WithTx(tx)
+		pending, err := txInvitationRepo.GetPendingJoinRequest(ctx, org.ID, userID)
+		if err != nil {
+			return errors.Wrap(errors.CodeDBError, err)
+		}
+		if pending != nil {
+			result.JoinRequestID = pending.ID
+			return nil
+		}
+		if err := s.consumeOrgInvitation(ctx, tx, invitation.ID, now); err != nil {
+			return err
+		}
+		joinRequest := &entity.OrgJoinRequest{
+			OrgID:        org.ID,
+			UserID:       userID,
+			InvitationID: invitation.ID,
+			Status:       consts.OrgJoinRequestStatusPending,
+		}
+		if err := txInvitationRepo.CreateJoinRequest(ctx, joinRequest); err != nil {
+			return errors.Wrap(errors.CodeDBError, err)
+		}
+		result.JoinRequestID = joinRequest.ID
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListOrgJoinRequests 列出组织的加入申请，status 为空时返回全部。
+func (s *OrgService) ListOrgJoinRequests(
+	ctx context.Context,
+	operatorID, orgID uint,
+	status string,
+) ([]*response.OrgJoinRequestItem, error) {
+	if _, err := s.getInvitableOrg(ctx, operatorID, orgID); err != nil {
+		return nil, err
+	}
+	joinRequests, err := s.orgInvitationRepo.ListJoinRequests(ctx, orgID, consts.OrgJoinRequestStatus(status))
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeDBError, err)
+	}
+	userIDs := make([]uint, 0, len(joinRequests))
+	for _, joinRequest := range joinRequests {
+		userIDs = append(userIDs, joinRequest.UserID)
+	}
+	usernames := make(map[uint]string, len(userIDs))
+	if len(userIDs) > 0 {
+		users, err := s.userRepo.GetByIDs(ctx, userIDs)
+		if err != nil {
+			return nil, errors.Wrap(errors.CodeDBError, err)
+		}
+		for _, user := range users {
+			usernames[user.ID] = user.Username
+		}
+	}
+
+	items := make([]*response.OrgJoinRequestItem, 0, len(joinRequests))
+	for _, joinRequest := range joinRequests {
+		item := &response.OrgJoinRequestItem{
+			ID:           joinRequest.ID,
+			OrgID:        joinRequest.OrgID,
+			UserID:       joinRequest.UserID,
+			Username:     usernames[joinRequest.UserID],
+			InvitationID: joinRequest.InvitationID,
+			Status:       string(joinRequest.Status),
+			ReviewedBy:   joinRequest.ReviewedBy,
+			ReviewNote:   joinRequest.ReviewNote,
+			CreatedAt:    joinRequest.CreatedAt.Format(time.DateTime),
+		}
+		if joinRequest.ReviewedAt != nil {
+			item.ReviewedAt = joinRequest.ReviewedAt.Format(time.DateTime)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// ApproveOrgJoinRequest 审批通过加入申请，申请人以默认角色加入，不切换其当前组织。
+func (s *OrgService) ApproveOrgJoinRequest(ctx context.Context, operatorID, orgID, requestID uint, note string) error {
+	org, err := s.getInvitableOrg(ctx, operatorID, orgID)
+	if err != nil {
+		return err
+	}
+	joinRequest, err := s.getPendingOrgJoinRequest(ctx, orgID, requestID)
+	if err != nil {
+		return err
+	}
+	return s.joinOrg(
+		ctx,
+		joinRequest.UserID,
+		org,
+		consts.OrgInvitationJoinSource(joinRequest.InvitationID),
+		false,
+		func(tx any) error {
+			return s.reviewOrgJoinRequest(ctx, tx, requestID, consts.OrgJoinRequestStatusApproved, operatorID, note)
+		},
+	)
+}
+
+// RejectOrgJoinRequest 拒绝加入申请，已占用的邀请次数不退回。
+func (s *OrgService) RejectOrgJoinRequest(ctx context.Context, operatorID, orgID, requestID uint, note string) error {
+	if _, err := s.getInvitableOrg(ctx, operatorID, orgID); err != nil {
+		return err
+	}
+	if _, err := s.getPendingOrgJoinRequest(ctx, orgID, requestID); err != nil {
+		return err
+	}
+	return s.reviewOrgJoinRequest(ctx, nil, requestID, consts.OrgJoinRequestStatusRejected, operatorID, note)
+}
+
+// getInvitableOrg 获取组织并校验操作者具备邀请成员 capability；全员组织不允许发邀请。
+func (s *OrgService) getInvitableOrg(ctx context.Context, operatorID, orgID uint) (*entity.Org, error) {
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeOrgNotFound, err)
+	}
+	if org == nil {
+		return nil, errors.New(errors.CodeOrgNotFound)
+	}
+	if err := s.authorizeOrgMemberAction(ctx, operatorID, orgID, consts.OrgMemberActionInvite); err != nil {
+		return nil, err
+	}
+	if isAllMembersBuiltinOrg(org) {
+		return nil, errors.New(errors.CodeOrgBuiltinProtected)
+	}
+	return org, nil
+}
+
+func (s *OrgService) getPendingOrgJoinRequest(ctx context.Context, orgID, requestID uint) (*entity.OrgJoinRequest, error) {
+	joinRequest, err := s.orgInvitationRepo.GetJoinRequestByID(ctx, requestID)
+	if err != nil {
+		return nil, errors.Wrap(errors.CodeDBError, err)
+	}
+	if joinRequest == nil || joinRequest.OrgID != orgID || joinRequest.Status != consts.OrgJoinRequestStatusPending {
+		return nil, errors.New(errors.CodeOrgJoinRequestNotFound)
+	}
+	return joinRequest, nil
+}
+
+// consumeOrgInvitation 在事务内占用一次邀请次数，并发下次数已用尽或邀请已失效时返回邀请无效。
+func (s *OrgService) consumeOrgInvitation(ctx context.Context, tx any, invitationID uint, now time.Time) error {
+	consumed, err := s.orgInvitationRepo.WithTx(tx).ConsumeUse(ctx, invitationID, now)
+	if err != nil {
+		return errors.Wrap(errors.CodeDBError, err)
+	}
+	if !consumed {
+		return errors.New(errors.CodeOrgInvitationInvalid)
+	}
+	return nil
+}
+
+// reviewOrgJoinRequest 写入审批结果，申请已被他人处理时返回申请不存在。
+func (s *OrgService) reviewOrgJoinRequest(
+	ctx context.Context,
+	tx any,
+	requestID uint,
+	status consts.OrgJoinRequestStatus,
+	reviewerID uint,
+	note string,
+) error {
+	reviewed, err := s.orgInvitationRepo.WithTx(tx).ReviewJoinRequest(
+		ctx,
+		requestID,
+		status,
+		reviewerID,
+		strings.TrimSpace(note),
+		time.Now(),
+	)
+	if err != nil {
+		return errors.Wrap(errors.CodeDBError, err)
+	}
+	if !reviewed {
+		return errors.New(errors.CodeOrgJoinRequestNotFound)
+	}
+	return nil
+}
+
+// orgInvitationMatchesUser 校验定向邀请：同时限定邮箱与手机号时命中任意一个即可。
+func orgInvitationMatchesUser(invitation *entity.OrgInvitation, user *entity.User) bool {
+	if invitation.TargetEmail == "" && invitation.TargetPhone == "" {
+		return true
+	}
+	if invitation.TargetEmail != "" && strings.EqualFold(strings.TrimSpace(user.Email), invitation.TargetEmail) {
+		return true
+	}
+	return invitation.TargetPhone != "" && user.Phone == invitation.TargetPhone
+}
+
+func orgInvitationStatus(invitation *entity.OrgInvitation, now time.Time) string {
+	switch {
+	case invitation.RevokedAt != nil:
+		return orgInvitationStatusRevoked
+	case !now.Before(invitation.ExpiresAt):
+		return orgInvitationStatusExpired
+	case invitation.UsedCount >= invitation.MaxUses:
+		return orgInvitationStatusExhausted
+	default:
+		return orgInvitationStatusActive
+	}
+}
+
+func toOrgInvitationItem(invitation *entity.OrgInvitation, now time.Time) response.OrgInvitationItem {
+	item := response.OrgInvitationItem{
+		ID:              invitation.ID,
+		OrgID:           invitation.OrgID,
+		TokenHint:       invitation.TokenHint,
+		MaxUses:         invitation.MaxUses,
+		UsedCount:       invitation.UsedCount,
+		ExpiresAt:       invitation.ExpiresAt.Format(time.DateTime),
+		TargetEmail:     invitation.TargetEmail,
+		TargetPhone:     invitation.TargetPhone,
+		RequireApproval: invitation.RequireApproval,
+		Note:            invitation.Note,
+		Status:          orgInvitationStatus(invitation, now),
+		CreatedBy:       invitation.CreatedBy,
+		CreatedAt:       invitation.CreatedAt.Format(time.DateTime),
+	}
+	if invitation.RevokedAt != nil {
+		item.RevokedAt = invitation.RevokedAt.Format(time.DateTime)
+	}
+	return item
+}
+
+// newOrgInvitationToken 生成 32 字节随机数的 base64url 编码作为邀请令牌。
+func newOrgInvitationToken() (string, error) {
+	raw := make([]byte, orgInvitationTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func hashOrgInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,234 @@
+package system
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/model/entity"
+	bizerrors "personal_assistant/pkg/errors"
+)
+
+func TestOrgInvitationSingleUseJoinsAndRecordsSource(t *testing.T) {
+	ctx := context.Background()
+	env := newAuthorizationTestEnv(t)
+	createRole(t, env, consts.RoleCodeMember)
+	org := createOrg(t, env, 81)
+	alice := createUser(t, env, "3001")
+	bob := createUser(t, env, "3002")
+
+	created, err := env.orgService.CreateOrgInvitation(ctx, org.OwnerID, org.ID, &request.CreateOrgInvitationReq{})
+	if err != nil {
+		t.Fatalf("CreateOrgInvitation() error = %v", err)
+	}
+	if created.Token == "" || created.MaxUses != 1 || created.Status != "active" {
+		t.Fatalf("unexpected invitation: %+v", created)
+	}
+
+	result, err := env.orgService.AcceptOrgInvitation(ctx, alice.ID, created.Token)
+	if err != nil {
+		t.Fatalf("AcceptOrgInvitation() error = %v", err)
+	}
+	if result.Status != "joined" || result.OrgID != org.ID {
+		t.Fatalf("unexpected accept result: %+v", result)
+	}
+	var member entity.OrgMember
+	if err := env.db.Where("org_id = ? AND user_id = ?", org.ID, alice.ID).First(&member).Error; err != nil {
+		t.Fatalf("load member: %v", err)
+	}
+	if member.MemberStatus != consts.OrgMemberStatusActive || member.JoinSource != consts.OrgInvitationJoinSource(created.ID) {
+		t.Fatalf("unexpected member: status=%v source=%s", member.MemberStatus, member.JoinSource)
+	}
+	var refreshed entity.User
+	if err := env.db.First(&refreshed, alice.ID).Error; err != nil {
+		t.Fatalf("reload user: %v", err)
+	}
+	if refreshed.CurrentOrgID == nil || *refreshed.CurrentOrgID != org.ID {
+		t.Fatalf("current_org_id = %v, want %d", refreshed.CurrentOrgID, org.ID)
+	}
+
+	// 已是成员时重复接受幂等；次数用尽后其他人不可再用
+	if _, err := env.orgService.AcceptOrgInvitation(ctx, alice.ID, created.Token); err != nil {
+		t.Fatalf("expected accept by existing member to be idempotent, got %v", err)
+	}
+	_, err = env.orgService.AcceptOrgInvitation(ctx, bob.ID, created.Token)
+	assertBizCode(t, err, bizerrors.CodeOrgInvitationInvalid)
+
+	items, err := env.orgService.ListOrgInvitations(ctx, org.OwnerID, org.ID)
+	if err != nil {
+		t.Fatalf("ListOrgInvitations() error = %v", err)
+	}
+	if len(items) != 1 || items[0].UsedCount != 1 || items[0].Status != "exhausted" {
+		t.Fatalf("unexpected invitation list: %+v", items)
+	}
+}
+
+func TestOrgInvitationRejectsRevokedExpiredAndUntargetedUsers(t *testing.T) {
+	ctx := context.Background()
+	env := newAuthorizationTestEnv(t)
+	createRole(t, env, consts.RoleCodeMember)
+	org := createOrg(t, env, 82)
+	alice := createUser(t, env, "3011")
+	bob := createUser(t, env, "3012")
+
+	revoked, err := env.orgService.CreateOrgInvitation(ctx, org.OwnerID, org.ID, &request.CreateOrgInvitationReq{MaxUses: 5})
+	if err != nil {
+		t.Fatalf("CreateOrgInvitation() error = %v", err)
+	}
+	if err := env.orgService.RevokeOrgInvitation(ctx, org.OwnerID, org.ID, revoked.ID); err != nil {
+		t.Fatalf("RevokeOrgInvitation() error = %v", err)
+	}
+	_, err = env.orgService.AcceptOrgInvitation(ctx, alice.ID, revoked.Token)
+	assertBizCode(t, err, bizerrors.CodeOrgInvitationInvalid)
+
+	expired, err := env.orgService.CreateOrgInvitation(ctx, org.OwnerID, org.ID, &request.CreateOrgInvitationReq{MaxUses: 5})
+	if err != nil {
+		t.Fatalf("CreateOrgInvitation() error = %v", err)
+	}
+	if err := env.db.Model(&entity.OrgInvitation{}).Where("id = ?", expired.ID).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatalf("expire invitation: %v", err)
+	}
+	_, err = env.orgService.AcceptOrgInvitation(ctx, alice.ID, expired.Token)
+	assertBizCode(t, err, bizerrors.CodeOrgInvitationInvalid)
+
+	targeted, err := env.orgService.CreateOrgInvitation(ctx, org.OwnerID, org.ID, &request.CreateOrgInvitationReq{
+		MaxUses:     5,
+		TargetPhone: bob.Phone,
+	})
+	if err != nil {
+		t.Fatalf("CreateOrgInvitation() error = %v", err)
+	}
+	_, err = env.orgService.AcceptOrgInvitation(ctx, alice.ID, targeted.Token)
+	assertBizCode(t, err, bizerrors.CodeOrgInvitationNotForYou)
+	if _, err := env.orgService.AcceptOrgInvitation(ctx, bob.ID, targeted.Token); err != nil {
+		t.Fatalf("expected targeted user to join, got %v", err)
+	}
+
+	var count int64
+	env.db.Model(&entity.OrgMember{}).Where("org_id = ? AND user_id = ?", org.ID, alice.ID).Count(&count)
+	if count != 0 {
+		t.Fatalf("expected alice not to join, got %d members", count)
+	}
+}
+
+func TestOrgInvitationEmailTargetRequiresBoundEmail(t *testing.T) {
+	ctx := context.Background()
+	env := newAuthorizationTestEnv(t)
+	createRole(t, env, consts.RoleCodeMember)
+	org := createOrg(t, env, 84)
+	alice := createUser(t, env, "3031")
+
+	// 手机号注册的账号未绑定邮箱时，定向邮箱邀请直接在创建阶段拒绝
+	_, err := env.orgService.CreateOrgInvitation(ctx, org.OwnerID, org.ID, &request.CreateOrgInvitationReq{
+		TargetEmail: "alice@example.com",
+	})
+	assertBizCode(t, err, bizerrors.CodeOrgInvitationEmailUnbound)
+
+	if err := env.db.Model(&entity.User{}).Where("id = ?", alice.ID).
+		Update("email", "alice@example.com").Error; err != nil {
+		t.Fatalf("bind email: %v", err)
+	}
+	created, err := env.orgService.CreateOrgInvitation(ctx, org.OwnerID, org.ID, &request.CreateOrgInvitationReq{
+		TargetEmail: " Alice@Example.com ",
+	})
+	if err != nil {
+		t.Fatalf("CreateOrgInvitation() error = %v", err)
+	}
+	if _, err := env.orgService.AcceptOrgInvitation(ctx, alice.ID, created.Token); err != nil {
+		t.Fatalf("expected email-targeted user to join, got %v", err)
+	}
+}
+
+func TestOrgInvitationApprovalFlow(t *testing.T) {
+	ctx := context.Background()
+	env := newAuthorizationTestEnv(t)
+	createRole(t, env, consts.RoleCodeMember)
+	org := createOrg(t, env, 83)
+	admin := createUser(t, env, "3021")
+	alice := createUser(t, env, "3022")
+	bob := createUser(t, env, "3023")
+	seedOrgMember(t, env, org.ID, admin.ID, consts.OrgMemberStatusActive)
+	grantOrgCapability(t, env, admin.ID, org.ID, "inviter", consts.CapabilityCodeOrgMemberInvite)
+
+	// 无邀请权限的成员不能管理邀请
+	_, err := env.orgService.CreateOrgInvitation(ctx, alice.ID, org.ID, &request.CreateOrgInvitationReq{})
+	assertBizCode(t, err, bizerrors.CodePermissionDenied)
+
+	created, err := env.orgService.CreateOrgInvitation(ctx, admin.ID, org.ID, &request.CreateOrgInvitationReq{
+		MaxUses:         3,
+		RequireApproval: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateOrgInvitation() error = %v", err)
+	}
+
+	pending, err := env.orgService.AcceptOrgInvitation(ctx, alice.ID, created.Token)
+	if err != nil {
+		t.Fatalf("AcceptOrgInvitation() error = %v", err)
+	}
+	if pending.Status != "pending" || pending.JoinRequestID == 0 {
+		t.Fatalf("unexpected accept result: %+v", pending)
+	}
+	again, err := env.orgService.AcceptOrgInvitation(ctx, alice.ID, created.Token)
+	if err != nil || again.JoinRequestID != pending.JoinRequestID {
+		t.Fatalf("expected existing pending request, got %+v, %v", again, err)
+	}
+	active, err := env.repoGroup.SystemRepositorySupplier.GetOrgMemberRepository().IsUserActiveInOrg(ctx, alice.ID, org.ID)
+	if err != nil || active {
+		t.Fatalf("expected alice to wait for approval, active=%v err=%v", active, err)
+	}
+
+	_, err = env.orgService.ListOrgJoinRequests(ctx, alice.ID, org.ID, "")
+	assertBizCode(t, err, bizerrors.CodePermissionDenied)
+	requests, err := env.orgService.ListOrgJoinRequests(ctx, admin.ID, org.ID, string(consts.OrgJoinRequestStatusPending))
+	if err != nil {
+		t.Fatalf("ListOrgJoinRequests() error = %v", err)
+	}
+	if len(requests) != 1 || requests[0].UserID != alice.ID || requests[0].Username != alice.Username {
+		t.Fatalf("unexpected join requests: %+v", requests)
+	}
+
+	if err := env.orgService.ApproveOrgJoinRequest(ctx, admin.ID, org.ID, pending.JoinRequestID, "welcome"); err != nil {
+		t.Fatalf("ApproveOrgJoinRequest() error = %v", err)
+	}
+	var member entity.OrgMember
+	if err := env.db.Where("org_id = ? AND user_id = ?", org.ID, alice.ID).First(&member).Error; err != nil {
+		t.Fatalf("load member: %v", err)
+	}
+	if member.MemberStatus != consts.OrgMemberStatusActive || member.JoinSource != consts.OrgInvitationJoinSource(created.ID) {
+		t.Fatalf("unexpected member: status=%v source=%s", member.MemberStatus, member.JoinSource)
+	}
+	var refreshed entity.User
+	if err := env.db.First(&refreshed, alice.ID).Error; err != nil {
+		t.Fatalf("reload user: %v", err)
+	}
+	if refreshed.CurrentOrgID != nil {
+		t.Fatalf("expected approval not to switch current org, got %d", *refreshed.CurrentOrgID)
+	}
+	err = env.orgService.ApproveOrgJoinRequest(ctx, admin.ID, org.ID, pending.JoinRequestID, "")
+	assertBizCode(t, err, bizerrors.CodeOrgJoinRequestNotFound)
+
+	rejected, err := env.orgService.AcceptOrgInvitation(ctx, bob.ID, created.Token)
+	if err != nil {
+		t.Fatalf("AcceptOrgInvitation() error = %v", err)
+	}
+	if err := env.orgService.RejectOrgJoinRequest(ctx, admin.ID, org.ID, rejected.JoinRequestID, "unknown"); err != nil {
+		t.Fatalf("RejectOrgJoinRequest() error = %v", err)
+	}
+	var count int64
+	env.db.Model(&entity.OrgMember{}).Where("org_id = ? AND user_id = ?", org.ID, bob.ID).Count(&count)
+	if count != 0 {
+		t.Fatalf("expected rejected user not to join, got %d members", count)
+	}
+}
+
+func TestOrgInvitationRejectsBuiltinAllMembersOrg(t *testing.T) {
+	env := newAuthorizationTestEnv(t)
+	org := createBuiltinAllMembersOrg(t, env, 84)
+
+	_, err := env.orgService.CreateOrgInvitation(context.Background(), org.OwnerID, org.ID, &request.CreateOrgInvitationReq{})
+	assertBizCode(t, err, bizerrors.CodeOrgBuiltinProtected)
+}
@@ -37,10 +37,12 @@ func SetUp(repositoryGroup *repository.Group) contract.Supplier {
 	rawHealth := NewHealthService(repositoryGroup)
 	rawCacheProjection := NewCacheProjectionService(repositoryGroup)
 	rawOJDailyStatsProjection := NewOJDailyStatsProjectionService(repositoryGroup)
-	rawUser := NewUserService(repositoryGroup, rawAuthorization, rawPermissionProjection)
+	rawUser := NewUserService(repositoryGroup, rawAuthorization, rawPermissionProjection, rawJWT)
 	rawOrg := NewOrgService(repositoryGroup, rawAuthorization, rawPermissionProjection)
 	rawOJ := NewOJService(repositoryGroup, rawCacheProjection, rawOJDailyStatsProjection)
 	rawOJTask := NewOJTaskService(repositoryGroup, rawAuthorization)
+	rawOJContest := NewOJContestService(repositoryGroup, rawAuthorization, rawOJTask, rawOJ)
+	rawOJQuestionBank := NewOJQuestionBankService(repositoryGroup, rawAuthorization)
 	rawAPI := NewApiService(repositoryGroup, rawPermissionProjection)
 	rawMenu := NewMenuService(repositoryGroup, rawPermissionProjection)
 	rawRole := NewRoleService(repositoryGroup, rawPermissionProjection)
@@ -62,6 +64,8 @@ func SetUp(repositoryGroup *repository.Group) contract.Supplier {
 	orgSvc := contract.OrgServiceContract(rawOrg)
 	ojSvc := contract.OJServiceContract(rawOJ)
 	ojTaskSvc := contract.OJTaskServiceContract(rawOJTask)
+	ojContestSvc := contract.OJContestServiceContract(rawOJContest)
+	ojQuestionBankSvc := contract.OJQuestionBankServiceContract(rawOJQuestionBank)
 	apiSvc := contract.ApiServiceContract(rawAPI)
 	menuSvc := contract.MenuServiceContract(rawMenu)
 	// 第二阶段：进入当前函数的主体逻辑，逐步组装中间结果或推进状态。
@@ -125,6 +129,8 @@ func SetUp(repositoryGroup *repository.Group) contract.Supplier {
 	})
 	// 对外仍只暴露统一的 AIService 契约，不把具体 tool 依赖细节泄露到上层。
 	aiSvc := contract.AIServiceContract(rawAI)
+	aiMemorySvc := contract.AIMemoryServiceContract(NewAIMemoryManageService(rawAIMemory, authorizationSvc))
+	aiKnowledgeSvc := contract.AIKnowledgeServiceContract(NewAIKnowledgeService(rawAIMemory, repositoryGroup, authorizationSvc))
 
 	ss.jwtService = jwtSvc
 	ss.authorizationService = authorizationSvc
@@ -135,11 +141,16 @@ func SetUp(repositoryGroup *repository.Group) contract.Supplier {
 	ss.orgService = orgSvc
 	ss.ojService = ojSvc
 	ss.ojTaskService = ojTaskSvc
+	ss.ojContestService = ojContestSvc
+	ss.ojQuestionBankService = ojQuestionBankSvc
 	ss.apiService = apiSvc
 	ss.menuService = menuSvc
 	ss.roleService = roleSvc
 	ss.imageService = imageSvc
 	ss.aiService = aiSvc
+	ss.aiMemoryService = aiMemorySvc
+	ss.aiMemoryIndexService = rawAIMemory
+	ss.aiKnowledgeService = aiKnowledgeSvc
 	ss.observabilityService = observabilitySvc
 	ss.cacheProjectionService = cacheProjectionSvc
 	ss.ojDailyStatsProjectionService = ojDailyStatsProjectionSvc
@@ -13,6 +13,8 @@ type serviceSupplier struct {
 	orgService                    contract.OrgServiceContract
 	ojService                     contract.OJServiceContract
 	ojTaskService                 contract.OJTaskServiceContract
+	ojContestService              contract.OJContestServiceContract
+	ojQuestionBankService         contract.OJQuestionBankServiceContract
 	apiService                    contract.ApiServiceContract
 	menuService                   contract.MenuServiceContract
 	roleService                   contract.RoleServiceContract
@@ -21,6 +23,9 @@ type serviceSupplier struct {
 	cacheProjectionService        contract.CacheProjectionServiceContract
 	ojDailyStatsProjectionService contract.OJDailyStatsProjectionServiceContract
 	aiService                     contract.AIServiceContract
+	aiMemoryService               contract.AIMemoryServiceContract
+	aiMemoryIndexService          contract.AIMemoryIndexServiceContract
+	aiKnowledgeService            contract.AIKnowledgeServiceContract
 }
 
 // GetJWTSvc 用于获取当前场景需要的对象或数据。
@@ -176,6 +181,16 @@ func (s *serviceSupplier) GetOJTaskSvc() contract.OJTaskServiceContract {
 	return s.ojTaskService
 }
 
+// GetOJContestSvc 返回限时 OJ 比赛服务。
+func (s *serviceSupplier) GetOJContestSvc() contract.OJContestServiceContract {
+	return s.ojContestService
+}
+
+// GetOJQuestionBankSvc 返回跨平台题库目录与标签服务。
+func (s *serviceSupplier) GetOJQuestionBankSvc() contract.OJQuestionBankServiceContract {
+	return s.ojQuestionBankService
+}
+
 // GetApiSvc 用于获取当前场景需要的对象或数据。
 // 参数：
 //   - 无。
@@ -311,3 +326,24 @@ func (s *serviceSupplier) GetOJDailyStatsProjectionSvc() contract.OJDailyStatsPr
 func (s *serviceSupplier) GetAISvc() contract.AIServiceContract {
 	return s.aiService
 }
+
+// GetAIMemorySvc 用于获取用户侧记忆管理服务。
+// 返回值：
+//   - contract.AIMemoryServiceContract：记忆管理服务契约。
+func (s *serviceSupplier) GetAIMemorySvc() contract.AIMemoryServiceContract {
+	return s.aiMemoryService
+}
+
+// GetAIMemoryIndexSvc 用于获取长期记忆向量索引补偿服务。
+// 返回值：
+//   - contract.AIMemoryIndexServiceContract：索引补偿服务契约。
+func (s *serviceSupplier) GetAIMemoryIndexSvc() contract.AIMemoryIndexServiceContract {
+	return s.aiMemoryIndexService
+}
+
+// GetAIKnowledgeSvc 用于获取组织知识库服务。
+// 返回值：
+//   - contract.AIKnowledgeServiceContract：知识库服务契约。
+func (s *serviceSupplier) GetAIKnowledgeSvc() contract.AIKnowledgeServiceContract {
+	return s.aiKnowledgeService
+}
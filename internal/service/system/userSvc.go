@@ -34,15 +34,21 @@ type UserService struct {
 	orgRepo                  interfaces.OrgRepository
 	orgMemberRepo            interfaces.OrgMemberRepository
 	imageRepo                interfaces.ImageRepository // 图片仓储
+	passwordResetRepo        interfaces.PasswordResetRepository
+	twoFactorRepo            interfaces.TwoFactorRepository
+	oauthStateRepo           interfaces.OAuthStateRepository
 	authorizationService     svccontract.AuthorizationServiceContract
 	permissionProjectionSvc  svccontract.PermissionProjectionServiceContract
+	jwtService               svccontract.JWTServiceContract // 找回密码、禁用账号后吊销会话
 	cacheProjectionPublisher cacheProjectionEventPublisher
+	sendMail                 func(to, subject, body string) error
 }
 
 func NewUserService(
 	repositoryGroup *repository.Group,
 	authorizationService svccontract.AuthorizationServiceContract,
 	permissionProjectionSvc svccontract.PermissionProjectionServiceContract,
+	jwtService svccontract.JWTServiceContract,
 ) *UserService {
 	return &UserService{
 		txRunner:                repositoryGroup,
@@ -51,8 +57,13 @@ func NewUserService(
 		orgRepo:                 repositoryGroup.SystemRepositorySupplier.GetOrgRepository(),
 		orgMemberRepo:           repositoryGroup.SystemRepositorySupplier.GetOrgMemberRepository(),
 		imageRepo:               repositoryGroup.SystemRepositorySupplier.GetImageRepository(),
+		passwordResetRepo:       repositoryGroup.SystemRepositorySupplier.GetPasswordResetRepository(),
+		twoFactorRepo:           repositoryGroup.SystemRepositorySupplier.GetTwoFactorRepository(),
+		oauthStateRepo:          repositoryGroup.SystemRepositorySupplier.GetOAuthStateRepository(),
 		authorizationService:    authorizationService,
 		permissionProjectionSvc: permissionProjectionSvc,
+		jwtService:              jwtService,
+		sendMail:                util.Email,
 		cacheProjectionPublisher: newCacheProjectionOutboxPublisher(
 			repositoryGroup.SystemRepositorySupplier.GetOutboxRepository(),
 		),
@@ -124,6 +135,27 @@ func (u *UserService) Register(
 		return nil, bizerrors.New(bizerrors.CodeInviteCodeInvalid)
 	}
 
+	// 3. 创建用户实例（不直接设置RoleID，通过权限服务分配）
+	user := &entity.User{
+		Username: req.Username,
+		Password: util.BcryptHash(req.Password),
+		Phone:    req.Phone,
+		UUID:     uuid.Must(uuid.NewV4()),
+		Avatar:   "", // 默认头像为空
+		Register: consts.Email,
+		Status:   consts.UserStatusActive,
+		// 不直接设置 RoleID，将通过权限服务分配角色
+	}
+	return u.createUserWithDefaultOrgs(ctx, user, targetOrg)
+}
+
+// createUserWithDefaultOrgs 创建新用户并完成默认组织分配：加入 targetOrg 与全体成员组织并授予默认角色。
+// targetOrg 为空时只加入全体成员组织；手机号注册与第三方登录注册共用该流程。
+func (u *UserService) createUserWithDefaultOrgs(
+	ctx context.Context,
+	user *entity.User,
+	targetOrg *entity.Org,
+) (*entity.User, error) {
 	// 获取系统内置的全体成员组织，确保用户注册后能自动加入（如果邀请码组织不是全体成员组织）。
 	allMembersOrg, err := u.orgRepo.GetByBuiltinKey(ctx, consts.OrgBuiltinKeyAllMembers)
 	if err != nil {
@@ -139,16 +171,8 @@ func (u *UserService) Register(
 		return nil, err
 	}
 
-	// 3. 创建用户实例（不直接设置RoleID，通过权限服务分配）
-	user := &entity.User{
-		Username: req.Username,
-		Password: util.BcryptHash(req.Password),
-		Phone:    req.Phone,
-		UUID:     uuid.Must(uuid.NewV4()),
-		Avatar:   "", // 默认头像为空
-		Register: consts.Email,
-		Status:   consts.UserStatusActive,
-		// 不直接设置 RoleID，将通过权限服务分配角色
+	if targetOrg == nil {
+		targetOrg = allMembersOrg
 	}
 	targetOrgID := targetOrg.ID
 	user.CurrentOrgID = &targetOrgID
@@ -480,6 +504,37 @@ func (u *UserService) ChangePhone(
 	return user, nil
 }
 
+// BindEmail 绑定或换绑邮箱。调用方需先通过邮箱验证码确认邮箱归属，邮箱统一转小写存储。
+func (u *UserService) BindEmail(ctx context.Context, userID uint, email string) (*entity.User, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return nil, bizerrors.New(bizerrors.CodeInvalidParams)
+	}
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if user == nil {
+		return nil, bizerrors.New(bizerrors.CodeUserNotFound)
+	}
+	owner, err := u.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if owner != nil && owner.ID != userID {
+		return nil, bizerrors.New(bizerrors.CodeEmailAlreadyUsed)
+	}
+
+	user.Email = email
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if err := u.populateUserSuperAdminFlag(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
 // ChangePassword 修改密码
 func (u *UserService) ChangePassword(
 	ctx context.Context,
@@ -1036,10 +1091,10 @@ func (u *UserService) applyUserStatus(
 		return err
 	}
 
-	// 禁用后立即移除 refresh 会话（access 由 ActiveUserMW 即时拦截）
-	if global.Redis != nil && target.UUID != uuid.Nil {
-		if err := global.Redis.Del(ctx, target.UUID.String()).Err(); err != nil {
-			return bizerrors.Wrap(bizerrors.CodeRedisError, err)
+	// 禁用后立即吊销全部会话，刷新令牌与未过期的访问令牌一并失效
+	if status == consts.UserStatusDisabled {
+		if err := u.jwtService.RevokeUserRefreshTokens(ctx, *target, consts.UserSessionRevokeReasonDisabled); err != nil {
+			return bizerrors.Wrap(bizerrors.CodeInternalError, err)
 		}
 	}
 	return nil
@@ -24,11 +24,13 @@ func TestUpdateUserStatusPublishesInactiveProjectionEvent(t *testing.T) {
 		user: &entity.User{MODEL: entity.MODEL{ID: 7}, Status: consts.UserStatusActive},
 	}
 	publisher := &stubCacheProjectionPublisher{}
+	jwtService := &fakePasswordResetJWTService{}
 	svc := &UserService{
 		txRunner:                 &stubTxRunner{},
 		userRepo:                 userRepo,
 		orgMemberRepo:            &stubOrgMemberRepository{orgIDs: []uint{}},
 		cacheProjectionPublisher: publisher,
+		jwtService:               jwtService,
 	}
 
 	err := svc.UpdateUserStatus(context.Background(), 99, 7, &request.AdminUpdateUserStatusReq{
@@ -50,6 +52,10 @@ func TestUpdateUserStatusPublishesInactiveProjectionEvent(t *testing.T) {
 	if publisher.events[0].UserID != 7 {
 		t.Fatalf("event user id = %d, want 7", publisher.events[0].UserID)
 	}
+	if len(jwtService.revoked) != 1 || jwtService.revoked[0] != 7 ||
+		jwtService.reasons[0] != consts.UserSessionRevokeReasonDisabled {
+		t.Fatalf("revoked = %v reasons = %v, want user 7 revoked as disabled", jwtService.revoked, jwtService.reasons)
+	}
 }
 
 func TestUpdateUserStatusPublishesActiveProjectionEvent(t *testing.T) {
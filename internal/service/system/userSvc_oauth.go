@@ -0,0 +1,437 @@
+package system
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	resp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	bizerrors "personal_assistant/pkg/errors"
+	"personal_assistant/pkg/security/oidc"
+	"personal_assistant/pkg/util"
+
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	oauthOpenidMaxLen   = 100 // 与 users.openid 列宽一致
+	oauthUsernameMaxLen = 50  // 与 users.username 列宽一致
+	oauthAvatarMaxLen   = 255 // 与 users.avatar 列宽一致
+)
+
+// ListOAuthProviders 返回已启用的第三方登录方式，供登录页渲染按钮。
+func (u *UserService) ListOAuthProviders(ctx context.Context) ([]resp.OAuthProviderItem, error) {
+	items := make([]resp.OAuthProviderItem, 0)
+	if global.Config == nil {
+		return items, nil
+	}
+	for _, provider := range global.Config.OAuth.Providers {
+		if !provider.Enabled {
+			continue
+		}
+		displayName := strings.TrimSpace(provider.DisplayName)
+		if displayName == "" {
+			displayName = provider.Name
+		}
+		items = append(items, resp.OAuthProviderItem{Name: provider.Name, DisplayName: displayName})
+	}
+	return items, nil
+}
+
+// BeginOAuthLogin 发起第三方登录，返回携带 state 与 PKCE code_challenge 的授权地址。
+func (u *UserService) BeginOAuthLogin(ctx context.Context, provider string) (*resp.OAuthAuthorizeResp, error) {
+	return u.beginOAuthAuthorize(ctx, provider, consts.OAuthStatePurposeLogin, 0)
+}
+
+// BeginOAuthLink 已登录用户发起第三方账号绑定，每个账号最多绑定一个第三方身份。
+func (u *UserService) BeginOAuthLink(ctx context.Context, userID uint, provider string) (*resp.OAuthAuthorizeResp, error) {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if user == nil {
+		return nil, bizerrors.New(bizerrors.CodeUserNotFound)
+	}
+	if userOpenid(user) != "" {
+		return nil, bizerrors.New(bizerrors.CodeOAuthAlreadyLinked)
+	}
+	return u.beginOAuthAuthorize(ctx, provider, consts.OAuthStatePurposeLink, userID)
+}
+
+// CompleteOAuthLogin 处理第三方登录回调，返回可签发令牌的用户。
+// 依次按已绑定身份、已验证邮箱（需提供方开启 link_by_email）匹配已有账号，都未命中时注册新用户，
+// 新用户与手机号注册走同一套默认组织分配。
+func (u *UserService) CompleteOAuthLogin(
+	ctx context.Context,
+	provider string,
+	req *request.OAuthCallbackReq,
+) (*entity.User, error) {
+	providerCfg, identity, err := u.consumeOAuthCallback(ctx, provider, consts.OAuthStatePurposeLogin, 0, req)
+	if err != nil {
+		return nil, err
+	}
+	openid := oauthOpenid(providerCfg.Name, identity.Subject)
+
+	user, err := u.userRepo.GetByOpenid(ctx, openid)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if user == nil && providerCfg.LinkByEmail && identity.EmailVerified && identity.Email != "" {
+		user, err = u.linkOAuthByEmail(ctx, identity.Email, openid)
+		if err != nil {
+			return nil, oauthIdentityConflictError(err)
+		}
+	}
+	if user == nil {
+		user, err = u.registerOAuthUser(ctx, providerCfg, identity, openid)
+		if err != nil {
+			return nil, oauthIdentityConflictError(err)
+		}
+	}
+
+	if user.Freeze || user.Status != consts.UserStatusActive {
+		return nil, bizerrors.New(bizerrors.CodeUserDisabled)
+	}
+	if err := u.populateUserSuperAdminFlag(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// CompleteOAuthLink 处理绑定回调，把第三方身份写入发起绑定的账号。
+func (u *UserService) CompleteOAuthLink(
+	ctx context.Context,
+	userID uint,
+	provider string,
+	req *request.OAuthCallbackReq,
+) (*resp.OAuthBindingResp, error) {
+	providerCfg, identity, err := u.consumeOAuthCallback(ctx, provider, consts.OAuthStatePurposeLink, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	openid := oauthOpenid(providerCfg.Name, identity.Subject)
+
+	owner, err := u.userRepo.GetByOpenid(ctx, openid)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if owner != nil && owner.ID != userID {
+		return nil, bizerrors.New(bizerrors.CodeOAuthIdentityLinked)
+	}
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if user == nil {
+		return nil, bizerrors.New(bizerrors.CodeUserNotFound)
+	}
+	if current := userOpenid(user); current != "" && current != openid {
+		return nil, bizerrors.New(bizerrors.CodeOAuthAlreadyLinked)
+	}
+
+	user.Openid = &openid
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, oauthIdentityConflictError(bizerrors.Wrap(bizerrors.CodeDBError, err))
+	}
+	return &resp.OAuthBindingResp{Linked: true, Provider: providerCfg.Name}, nil
+}
+
+// GetOAuthBinding 查询当前账号的第三方账号绑定状态。
+func (u *UserService) GetOAuthBinding(ctx context.Context, userID uint) (*resp.OAuthBindingResp, error) {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if user == nil {
+		return nil, bizerrors.New(bizerrors.CodeUserNotFound)
+	}
+	openid := userOpenid(user)
+	if openid == "" {
+		return &resp.OAuthBindingResp{}, nil
+	}
+	provider, _, _ := strings.Cut(openid, ":")
+	return &resp.OAuthBindingResp{Linked: true, Provider: provider}, nil
+}
+
+// UnlinkOAuth 解绑第三方账号。第三方注册且仍使用占位手机号的账号解绑后将无法登录，需先绑定手机号。
+func (u *UserService) UnlinkOAuth(ctx context.Context, userID uint) error {
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if user == nil {
+		return bizerrors.New(bizerrors.CodeUserNotFound)
+	}
+	if userOpenid(user) == "" {
+		return nil
+	}
+	if isOAuthPlaceholderPhone(user.Phone) {
+		return bizerrors.New(bizerrors.CodeOAuthUnlinkDenied)
+	}
+	user.Openid = nil
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return nil
+}
+
+func (u *UserService) beginOAuthAuthorize(
+	ctx context.Context,
+	provider string,
+	purpose string,
+	userID uint,
+) (*resp.OAuthAuthorizeResp, error) {
+	providerCfg, client, err := oauthProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+	state, err := oidc.RandomToken()
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+	verifier, err := oidc.RandomToken()
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+	// nonce 只对 OIDC 有意义，纯 OAuth2 提供方不会回传
+	nonce := ""
+	if client.Issuer != "" {
+		if nonce, err = oidc.RandomToken(); err != nil {
+			return nil, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+		}
+	}
+
+	record := &entity.OAuthLoginState{
+		Provider:     providerCfg.Name,
+		StateHash:    hashOAuthState(state),
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		Purpose:      purpose,
+		UserID:       userID,
+		ExpiresAt:    time.Now().Add(consts.OAuthStateTTLMinutes * time.Minute),
+	}
+	if err := u.oauthStateRepo.Create(ctx, record); err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return &resp.OAuthAuthorizeResp{
+		AuthorizeURL: client.AuthCodeURL(state, oidc.S256Challenge(verifier), nonce),
+		ExpiresAt:    record.ExpiresAt.UnixMilli(),
+	}, nil
+}
+
+// consumeOAuthCallback 校验并作废 state，再用授权码与 code_verifier 换取第三方身份。
+// state 不存在、已使用、已过期或与提供方、用途、发起用户不匹配时统一视为失效，不区分具体原因。
+func (u *UserService) consumeOAuthCallback(
+	ctx context.Context,
+	provider string,
+	purpose string,
+	userID uint,
+	req *request.OAuthCallbackReq,
+) (*config.OAuthProvider, *oidc.Identity, error) {
+	if req == nil {
+		return nil, nil, bizerrors.New(bizerrors.CodeInvalidParams)
+	}
+	providerCfg, client, err := oauthProvider(provider)
+	if err != nil {
+		return nil, nil, err
+	}
+	record, err := u.oauthStateRepo.GetByStateHash(ctx, hashOAuthState(strings.TrimSpace(req.State)))
+	if err != nil {
+		return nil, nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	now := time.Now()
+	if record == nil || record.UsedAt != nil || !now.Before(record.ExpiresAt) ||
+		record.Provider != providerCfg.Name || record.Purpose != purpose || record.UserID != userID {
+		return nil, nil, bizerrors.New(bizerrors.CodeOAuthStateInvalid)
+	}
+	marked, err := u.oauthStateRepo.MarkUsed(ctx, record.ID, now)
+	if err != nil {
+		return nil, nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if !marked {
+		return nil, nil, bizerrors.New(bizerrors.CodeOAuthStateInvalid)
+	}
+
+	token, err := client.Exchange(ctx, strings.TrimSpace(req.Code), record.CodeVerifier)
+	if err != nil {
+		global.Log.Warn("第三方授权码兑换失败", zap.String("provider", providerCfg.Name), zap.Error(err))
+		return nil, nil, bizerrors.Wrap(bizerrors.CodeOAuthLoginFailed, err)
+	}
+	identity, err := client.FetchIdentity(ctx, token, record.Nonce)
+	if err != nil {
+		global.Log.Warn("第三方身份校验失败", zap.String("provider", providerCfg.Name), zap.Error(err))
+		return nil, nil, bizerrors.Wrap(bizerrors.CodeOAuthLoginFailed, err)
+	}
+	return providerCfg, identity, nil
+}
+
+// linkOAuthByEmail 首次第三方登录时按已验证邮箱关联已有账号；该账号已绑定其他第三方身份时拒绝。
+func (u *UserService) linkOAuthByEmail(ctx context.Context, email, openid string) (*entity.User, error) {
+	user, err := u.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if user == nil {
+		return nil, nil
+	}
+	if userOpenid(user) != "" {
+		return nil, bizerrors.New(bizerrors.CodeEmailAlreadyUsed)
+	}
+	user.Openid = &openid
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	return user, nil
+}
+
+// registerOAuthUser 为首次登录的第三方身份创建账号。
+// 手机号列唯一且非空，先写入占位值，登录密码为不可用的随机值；邮箱已被其他账号使用时不写入。
+func (u *UserService) registerOAuthUser(
+	ctx context.Context,
+	providerCfg *config.OAuthProvider,
+	identity *oidc.Identity,
+	openid string,
+) (*entity.User, error) {
+	var targetOrg *entity.Org
+	if inviteCode := strings.TrimSpace(providerCfg.InviteCode); inviteCode != "" {
+		org, err := u.orgRepo.GetByCode(ctx, inviteCode)
+		if err != nil {
+			return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		if org == nil {
+			return nil, bizerrors.New(bizerrors.CodeInviteCodeInvalid)
+		}
+		targetOrg = org
+	}
+
+	phone, err := newOAuthPlaceholderPhone()
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+	password, err := oidc.RandomToken()
+	if err != nil {
+		return nil, bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+	email := ""
+	if identity.Email != "" && identity.EmailVerified {
+		exists, err := u.userRepo.ExistsByEmail(ctx, identity.Email)
+		if err != nil {
+			return nil, bizerrors.Wrap(bizerrors.CodeDBError, err)
+		}
+		if !exists {
+			email = identity.Email
+		}
+	}
+	avatar := ""
+	if len(identity.Picture) <= oauthAvatarMaxLen {
+		avatar = identity.Picture
+	}
+
+	user := &entity.User{
+		Username: oauthUsername(providerCfg, identity),
+		Password: util.BcryptHash(password),
+		Phone:    phone,
+		Email:    email,
+		Openid:   &openid,
+		UUID:     uuid.Must(uuid.NewV4()),
+		Avatar:   avatar,
+		Register: consts.OIDC,
+		Status:   consts.UserStatusActive,
+	}
+	return u.createUserWithDefaultOrgs(ctx, user, targetOrg)
+}
+
+func oauthProvider(name string) (*config.OAuthProvider, *oidc.Config, error) {
+	name = strings.TrimSpace(name)
+	if global.Config == nil || name == "" {
+		return nil, nil, bizerrors.New(bizerrors.CodeOAuthProviderNotFound)
+	}
+	for i := range global.Config.OAuth.Providers {
+		provider := &global.Config.OAuth.Providers[i]
+		if provider.Name != name || !provider.Enabled {
+			continue
+		}
+		return provider, &oidc.Config{
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			AuthURL:      provider.AuthURL,
+			TokenURL:     provider.TokenURL,
+			UserInfoURL:  provider.UserInfoURL,
+			Issuer:       provider.Issuer,
+			RedirectURL:  provider.RedirectURL,
+			Scopes:       provider.Scopes,
+		}, nil
+	}
+	return nil, nil, bizerrors.New(bizerrors.CodeOAuthProviderNotFound)
+}
+
+// oauthOpenid 以"提供方:subject"写入 users.openid，避免不同提供方的 subject 冲突；超出列宽时改存 subject 摘要。
+// 提供方名称在配置加载时限制为 config.OAuthProviderNameMaxLen，保证摘要形式不会超出列宽。
+func oauthOpenid(provider, subject string) string {
+	openid := provider + ":" + subject
+	if len(openid) <= oauthOpenidMaxLen {
+		return openid
+	}
+	sum := sha256.Sum256([]byte(subject))
+	return provider + ":" + hex.EncodeToString(sum[:])
+}
+
+// userOpenid 返回用户已绑定的第三方身份，未绑定时为空串。
+func userOpenid(user *entity.User) string {
+	if user == nil || user.Openid == nil {
+		return ""
+	}
+	return *user.Openid
+}
+
+// oauthIdentityConflictError 并发登录或绑定同一第三方身份时，由 users.openid 唯一索引兜底拒绝。
+func oauthIdentityConflictError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return bizerrors.New(bizerrors.CodeOAuthIdentityLinked)
+	}
+	return err
+}
+
+// hashOAuthState state 本身是 256 位随机数，直接取 SHA-256 摘要入库即可。
+func hashOAuthState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
+
+func oauthUsername(providerCfg *config.OAuthProvider, identity *oidc.Identity) string {
+	name := strings.TrimSpace(identity.Name)
+	if name == "" && identity.Email != "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+	if name == "" {
+		name = providerCfg.Name + "用户"
+	}
+	if utf8.RuneCountInString(name) > oauthUsernameMaxLen {
+		name = string([]rune(name)[:oauthUsernameMaxLen])
+	}
+	return name
+}
+
+func newOAuthPlaceholderPhone() (string, error) {
+	raw := make([]byte, 7)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return consts.OAuthPlaceholderPhonePrefix + hex.EncodeToString(raw), nil
+}
+
+func isOAuthPlaceholderPhone(phone string) bool {
+	return strings.HasPrefix(phone, consts.OAuthPlaceholderPhonePrefix)
+}
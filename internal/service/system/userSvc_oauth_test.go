@@ -0,0 +1,321 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/model/entity"
+	"personal_assistant/internal/repository/interfaces"
+	reposystem "personal_assistant/internal/repository/system"
+	bizerrors "personal_assistant/pkg/errors"
+	"personal_assistant/pkg/security/oidc/oidctest"
+
+	"github.com/glebarez/sqlite"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestOAuthLoginRegistersNewUserIntoDefaultOrgs(t *testing.T) {
+	env := newOAuthTestEnv(t, func(provider *config.OAuthProvider) {
+		provider.InviteCode = "TEAM01"
+	})
+	ctx := context.Background()
+	team := &entity.Org{Name: "团队", Code: "TEAM01"}
+	mustCreate(t, env.db, team)
+	env.idp.SetUser(oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true, Name: "Alice"})
+
+	callback := env.authorize(t, "mock")
+	user, err := env.svc.CompleteOAuthLogin(ctx, "mock", callback)
+	if err != nil {
+		t.Fatalf("CompleteOAuthLogin() error = %v", err)
+	}
+	if user.Register != consts.OIDC || userOpenid(user) != "mock:sub-1" || user.Username != "Alice" || user.Email != "alice@example.com" {
+		t.Fatalf("unexpected registered user: %+v", user)
+	}
+	if !isOAuthPlaceholderPhone(user.Phone) || user.CurrentOrgID == nil || *user.CurrentOrgID != team.ID {
+		t.Fatalf("unexpected phone or current org: %q, %v", user.Phone, user.CurrentOrgID)
+	}
+	for _, orgID := range []uint{team.ID, env.allMembers.ID} {
+		var members, roles int64
+		env.db.Model(&entity.OrgMember{}).Where("org_id = ? AND user_id = ?", orgID, user.ID).Count(&members)
+		env.db.Model(&entity.UserOrgRole{}).Where("org_id = ? AND user_id = ? AND role_id = ?", orgID, user.ID, env.memberRole.ID).Count(&roles)
+		if members != 1 || roles != 1 {
+			t.Fatalf("org %d: expected membership and default role, got %d members, %d roles", orgID, members, roles)
+		}
+	}
+
+	// 同一 state 不能重复回调
+	if _, err := env.svc.CompleteOAuthLogin(ctx, "mock", callback); !hasBizCode(err, bizerrors.CodeOAuthStateInvalid) {
+		t.Fatalf("expected replayed state to be rejected, got %v", err)
+	}
+	again, err := env.svc.CompleteOAuthLogin(ctx, "mock", env.authorize(t, "mock"))
+	if err != nil || again.ID != user.ID {
+		t.Fatalf("expected second login to reuse account, got %+v, %v", again, err)
+	}
+	var count int64
+	env.db.Model(&entity.User{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("expected exactly one user, got %d", count)
+	}
+}
+
+func TestOAuthLoginLinksExistingUserByVerifiedEmail(t *testing.T) {
+	env := newOAuthTestEnv(t, func(provider *config.OAuthProvider) {
+		provider.LinkByEmail = true
+	})
+	ctx := context.Background()
+	existing := env.createUser(t, "bob", "bob@example.com")
+
+	// 邮箱未验证时不关联，按新用户注册且不占用该邮箱
+	env.idp.SetUser(oidctest.User{Subject: "sub-unverified", Email: "bob@example.com"})
+	created, err := env.svc.CompleteOAuthLogin(ctx, "mock", env.authorize(t, "mock"))
+	if err != nil {
+		t.Fatalf("CompleteOAuthLogin() error = %v", err)
+	}
+	if created.ID == existing.ID || created.Email != "" || created.Username != "bob" {
+		t.Fatalf("expected a separate account without email, got %+v", created)
+	}
+
+	env.idp.SetUser(oidctest.User{Subject: "sub-verified", Email: "bob@example.com", EmailVerified: true})
+	linked, err := env.svc.CompleteOAuthLogin(ctx, "mock", env.authorize(t, "mock"))
+	if err != nil {
+		t.Fatalf("CompleteOAuthLogin() error = %v", err)
+	}
+	if linked.ID != existing.ID || userOpenid(linked) != "mock:sub-verified" {
+		t.Fatalf("expected existing user to be linked, got %+v", linked)
+	}
+}
+
+func TestOAuthLinkAndUnlink(t *testing.T) {
+	env := newOAuthTestEnv(t, nil)
+	ctx := context.Background()
+	alice := env.createUser(t, "alice", "")
+	bob := env.createUser(t, "bob", "")
+	env.idp.SetUser(oidctest.User{Subject: "sub-1"})
+
+	start, err := env.svc.BeginOAuthLink(ctx, alice.ID, "mock")
+	if err != nil {
+		t.Fatalf("BeginOAuthLink() error = %v", err)
+	}
+	code, state, err := env.idp.Authorize(start.AuthorizeURL)
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	// 绑定用的 state 不能被其他用户或登录流程使用
+	if _, err := env.svc.CompleteOAuthLink(ctx, bob.ID, "mock", &request.OAuthCallbackReq{Code: code, State: state}); !hasBizCode(err, bizerrors.CodeOAuthStateInvalid) {
+		t.Fatalf("expected state of another user to be rejected, got %v", err)
+	}
+	if _, err := env.svc.CompleteOAuthLogin(ctx, "mock", &request.OAuthCallbackReq{Code: code, State: state}); !hasBizCode(err, bizerrors.CodeOAuthStateInvalid) {
+		t.Fatalf("expected link state to be rejected by login, got %v", err)
+	}
+	binding, err := env.svc.CompleteOAuthLink(ctx, alice.ID, "mock", &request.OAuthCallbackReq{Code: code, State: state})
+	if err != nil || !binding.Linked || binding.Provider != "mock" {
+		t.Fatalf("CompleteOAuthLink() = %+v, %v", binding, err)
+	}
+	if _, err := env.svc.BeginOAuthLink(ctx, alice.ID, "mock"); !hasBizCode(err, bizerrors.CodeOAuthAlreadyLinked) {
+		t.Fatalf("expected linked account to be rejected, got %v", err)
+	}
+
+	// 同一第三方身份不能再绑定到其他账号
+	start, err = env.svc.BeginOAuthLink(ctx, bob.ID, "mock")
+	if err != nil {
+		t.Fatalf("BeginOAuthLink() error = %v", err)
+	}
+	code, state, _ = env.idp.Authorize(start.AuthorizeURL)
+	if _, err := env.svc.CompleteOAuthLink(ctx, bob.ID, "mock", &request.OAuthCallbackReq{Code: code, State: state}); !hasBizCode(err, bizerrors.CodeOAuthIdentityLinked) {
+		t.Fatalf("expected identity conflict, got %v", err)
+	}
+
+	loggedIn, err := env.svc.CompleteOAuthLogin(ctx, "mock", env.authorize(t, "mock"))
+	if err != nil || loggedIn.ID != alice.ID {
+		t.Fatalf("expected login as linked user, got %+v, %v", loggedIn, err)
+	}
+	if err := env.svc.UnlinkOAuth(ctx, alice.ID); err != nil {
+		t.Fatalf("UnlinkOAuth() error = %v", err)
+	}
+	if binding, err := env.svc.GetOAuthBinding(ctx, alice.ID); err != nil || binding.Linked {
+		t.Fatalf("expected binding removed, got %+v, %v", binding, err)
+	}
+
+	// 第三方注册且未绑定手机号的账号不允许解绑
+	env.idp.SetUser(oidctest.User{Subject: "sub-2"})
+	ssoUser, err := env.svc.CompleteOAuthLogin(ctx, "mock", env.authorize(t, "mock"))
+	if err != nil {
+		t.Fatalf("CompleteOAuthLogin() error = %v", err)
+	}
+	if err := env.svc.UnlinkOAuth(ctx, ssoUser.ID); !hasBizCode(err, bizerrors.CodeOAuthUnlinkDenied) {
+		t.Fatalf("expected unlink to be denied, got %v", err)
+	}
+}
+
+func TestOAuthIdentityUniqueIndexRejectsConcurrentClaims(t *testing.T) {
+	env := newOAuthTestEnv(t, nil)
+	ctx := context.Background()
+	alice := env.createUser(t, "alice", "")
+	bob := env.createUser(t, "bob", "")
+	env.idp.SetUser(oidctest.User{Subject: "sub-1"})
+
+	start, err := env.svc.BeginOAuthLink(ctx, alice.ID, "mock")
+	if err != nil {
+		t.Fatalf("BeginOAuthLink() error = %v", err)
+	}
+	code, state, _ := env.idp.Authorize(start.AuthorizeURL)
+	if _, err := env.svc.CompleteOAuthLink(ctx, alice.ID, "mock", &request.OAuthCallbackReq{Code: code, State: state}); err != nil {
+		t.Fatalf("CompleteOAuthLink() error = %v", err)
+	}
+
+	// 模拟并发请求：查重时身份尚未写入，由唯一索引兜底
+	env.svc.userRepo = staleOpenidUserRepository{UserRepository: env.svc.userRepo}
+	start, err = env.svc.BeginOAuthLink(ctx, bob.ID, "mock")
+	if err != nil {
+		t.Fatalf("BeginOAuthLink() error = %v", err)
+	}
+	code, state, _ = env.idp.Authorize(start.AuthorizeURL)
+	if _, err := env.svc.CompleteOAuthLink(ctx, bob.ID, "mock", &request.OAuthCallbackReq{Code: code, State: state}); !hasBizCode(err, bizerrors.CodeOAuthIdentityLinked) {
+		t.Fatalf("expected link conflict, got %v", err)
+	}
+	if _, err := env.svc.CompleteOAuthLogin(ctx, "mock", env.authorize(t, "mock")); !hasBizCode(err, bizerrors.CodeOAuthIdentityLinked) {
+		t.Fatalf("expected register conflict, got %v", err)
+	}
+
+	var count int64
+	env.db.Model(&entity.User{}).Where("openid = ?", "mock:sub-1").Count(&count)
+	if count != 1 {
+		t.Fatalf("expected a single owner of the identity, got %d", count)
+	}
+}
+
+func TestOAuthOpenidFitsColumnForLongestProviderName(t *testing.T) {
+	provider := strings.Repeat("p", config.OAuthProviderNameMaxLen)
+	openid := oauthOpenid(provider, strings.Repeat("s", 200))
+	if len(openid) > oauthOpenidMaxLen || !strings.HasPrefix(openid, provider+":") {
+		t.Fatalf("openid = %q (len %d), want prefixed and at most %d", openid, len(openid), oauthOpenidMaxLen)
+	}
+	if err := (config.OAuth{Providers: []config.OAuthProvider{{Name: provider + "x"}}}).Validate(); err == nil {
+		t.Fatal("expected overlong provider name to be rejected")
+	}
+}
+
+// staleOpenidUserRepository 按第三方身份查询总是未命中，模拟查重与写入之间的并发窗口
+type staleOpenidUserRepository struct {
+	interfaces.UserRepository
+}
+
+func (staleOpenidUserRepository) GetByOpenid(context.Context, string) (*entity.User, error) {
+	return nil, nil
+}
+
+type oauthTestEnv struct {
+	db         *gorm.DB
+	svc        *UserService
+	idp        *oidctest.Server
+	allMembers *entity.Org
+	memberRole *entity.Role
+}
+
+func newOAuthTestEnv(t *testing.T, configure func(provider *config.OAuthProvider)) *oauthTestEnv {
+	t.Helper()
+
+	idp := oidctest.NewServer("personal-assistant", "test-secret")
+	t.Cleanup(idp.Close)
+	client := idp.Config("http://localhost/oauth/callback/mock")
+	provider := config.OAuthProvider{
+		Name:         "mock",
+		Enabled:      true,
+		ClientID:     client.ClientID,
+		ClientSecret: client.ClientSecret,
+		AuthURL:      client.AuthURL,
+		TokenURL:     client.TokenURL,
+		UserInfoURL:  client.UserInfoURL,
+		Issuer:       client.Issuer,
+		RedirectURL:  client.RedirectURL,
+		Scopes:       client.Scopes,
+	}
+	if configure != nil {
+		configure(&provider)
+	}
+
+	oldConfig := global.Config
+	oldLog := global.Log
+	global.Config = &config.Config{OAuth: config.OAuth{Providers: []config.OAuthProvider{provider}}}
+	global.Log = zap.NewNop()
+	t.Cleanup(func() {
+		global.Config = oldConfig
+		global.Log = oldLog
+	})
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&entity.Org{},
+		&entity.User{},
+		&entity.OrgMember{},
+		&entity.Role{},
+		&entity.UserOrgRole{},
+		&entity.OAuthLoginState{},
+	); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+
+	builtinKey := consts.OrgBuiltinKeyAllMembers
+	allMembers := &entity.Org{Name: "全体成员", IsBuiltin: true, BuiltinKey: &builtinKey}
+	mustCreate(t, db, allMembers)
+	memberRole := &entity.Role{Name: "成员", Code: consts.RoleCodeMember, Status: 1}
+	mustCreate(t, db, memberRole)
+
+	return &oauthTestEnv{
+		db:         db,
+		idp:        idp,
+		allMembers: allMembers,
+		memberRole: memberRole,
+		svc: &UserService{
+			txRunner:             &stubTxRunner{},
+			userRepo:             reposystem.NewUserRepository(db),
+			orgRepo:              reposystem.NewOrgRepository(db),
+			orgMemberRepo:        reposystem.NewOrgMemberRepository(db),
+			roleRepo:             reposystem.NewRoleRepository(db),
+			oauthStateRepo:       reposystem.NewOAuthStateRepository(db),
+			authorizationService: &fakeAIMemoryManageAuthorization{superAdmins: map[uint]bool{}},
+		},
+	}
+}
+
+// authorize 发起一次第三方登录并模拟浏览器完成授权，返回回调页提交的参数
+func (e *oauthTestEnv) authorize(t *testing.T, provider string) *request.OAuthCallbackReq {
+	t.Helper()
+	start, err := e.svc.BeginOAuthLogin(context.Background(), provider)
+	if err != nil {
+		t.Fatalf("BeginOAuthLogin() error = %v", err)
+	}
+	code, state, err := e.idp.Authorize(start.AuthorizeURL)
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	return &request.OAuthCallbackReq{Code: code, State: state}
+}
+
+func (e *oauthTestEnv) createUser(t *testing.T, username, email string) *entity.User {
+	t.Helper()
+	var count int64
+	if err := e.db.Model(&entity.User{}).Count(&count).Error; err != nil {
+		t.Fatalf("count users: %v", err)
+	}
+	user := &entity.User{
+		UUID:     uuid.Must(uuid.NewV4()),
+		Username: username,
+		Phone:    fmt.Sprintf("139%08d", count+1),
+		Email:    email,
+		Status:   consts.UserStatusActive,
+	}
+	mustCreate(t, e.db, user)
+	return user
+}
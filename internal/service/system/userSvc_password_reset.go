@@ -0,0 +1,194 @@
+package system
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/model/entity"
+	bizerrors "personal_assistant/pkg/errors"
+	"personal_assistant/pkg/util"
+
+	"github.com/mojocn/base64Captcha"
+	"go.uber.org/zap"
+)
+
+// SendPasswordResetCode 找回密码第一步：校验图形验证码后向账号绑定邮箱发送重置验证码。
+// 账号不存在或不可用时同样返回成功，避免接口被用来探测注册信息；
+// 账号未绑定邮箱时明确返回 CodeEmailNotBound，提示用户先登录绑定邮箱，而不是假装已发送。
+func (u *UserService) SendPasswordResetCode(
+	ctx context.Context,
+	store base64Captcha.Store,
+	clientIP string,
+	req *request.SendPasswordResetCodeReq,
+) error {
+	if req == nil {
+		return bizerrors.New(bizerrors.CodeInvalidParams)
+	}
+	if store == nil || !store.Verify(req.CaptchaID, req.Captcha, true) {
+		return bizerrors.New(bizerrors.CodeCaptchaError)
+	}
+
+	account := normalizePasswordResetAccount(req.Account)
+	user, err := u.findUserByPasswordResetAccount(ctx, account)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if user == nil || user.Freeze || user.Status != consts.UserStatusActive {
+		global.Log.Info("找回密码账号不可用，跳过发送", zap.String("account", account))
+		return nil
+	}
+	if strings.TrimSpace(user.Email) == "" {
+		return bizerrors.New(bizerrors.CodeEmailNotBound)
+	}
+
+	code, err := generatePasswordResetCode()
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+	now := time.Now()
+	record := &entity.PasswordResetCode{
+		UserID:    user.ID,
+		Account:   account,
+		Email:     user.Email,
+		CodeHash:  hashPasswordResetCode(user.ID, code),
+		ExpiresAt: now.Add(consts.PasswordResetCodeTTLMinutes * time.Minute),
+		RequestIP: clientIP,
+	}
+	// 新验证码生效时作废旧验证码，保证同一时刻只有一个可用
+	err = u.txRunner.InTx(ctx, func(tx any) error {
+		txResetRepo := u.passwordResetRepo.WithTx(tx)
+		if err := txResetRepo.InvalidateByUserID(ctx, user.ID, now); err != nil {
+			return err
+		}
+		return txResetRepo.Create(ctx, record)
+	})
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	if err := u.sendMail(user.Email, "找回密码验证码", buildPasswordResetMailBody(user.Username, code)); err != nil {
+		return bizerrors.Wrap(bizerrors.CodeEmailSendFailed, err)
+	}
+	return nil
+}
+
+// ResetPassword 找回密码第二步：校验重置验证码并设置新密码，成功后吊销该用户全部刷新令牌。
+// 验证码连续错误达到上限即作废，需重新申请。
+func (u *UserService) ResetPassword(ctx context.Context, req *request.ResetPasswordReq) error {
+	if req == nil {
+		return bizerrors.New(bizerrors.CodeInvalidParams)
+	}
+
+	user, err := u.findUserByPasswordResetAccount(ctx, normalizePasswordResetAccount(req.Account))
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if user == nil {
+		return bizerrors.New(bizerrors.CodeCaptchaError)
+	}
+
+	now := time.Now()
+	record, err := u.passwordResetRepo.GetLatestActiveByUserID(ctx, user.ID, now)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if record == nil {
+		return bizerrors.New(bizerrors.CodeCaptchaExpired)
+	}
+	// 先原子占用一次校验机会再比对，并发猜测也无法超过失败上限
+	claimed, err := u.passwordResetRepo.ClaimAttempt(ctx, record.ID, consts.PasswordResetCodeMaxAttempts, now)
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+	if !claimed {
+		return bizerrors.New(bizerrors.CodeCaptchaExpired)
+	}
+	expected := hashPasswordResetCode(user.ID, strings.TrimSpace(req.Code))
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(record.CodeHash)) != 1 {
+		return bizerrors.New(bizerrors.CodeCaptchaError)
+	}
+	if user.Freeze || user.Status != consts.UserStatusActive {
+		return bizerrors.New(bizerrors.CodeUserDisabled)
+	}
+
+	err = u.txRunner.InTx(ctx, func(tx any) error {
+		user.Password = util.BcryptHash(req.NewPassword)
+		if err := u.userRepo.WithTx(tx).Update(ctx, user); err != nil {
+			return err
+		}
+		return u.passwordResetRepo.WithTx(tx).InvalidateByUserID(ctx, user.ID, now)
+	})
+	if err != nil {
+		return bizerrors.Wrap(bizerrors.CodeDBError, err)
+	}
+
+	// 密码已变更，旧设备上的刷新令牌全部失效，需要重新登录
+	if err := u.jwtService.RevokeUserRefreshTokens(ctx, *user, consts.UserSessionRevokeReasonPasswordReset); err != nil {
+		return bizerrors.Wrap(bizerrors.CodeInternalError, err)
+	}
+	return nil
+}
+
+// findUserByPasswordResetAccount 按账号形态选择邮箱或手机号查询用户。
+func (u *UserService) findUserByPasswordResetAccount(ctx context.Context, account string) (*entity.User, error) {
+	if account == "" {
+		return nil, nil
+	}
+	if strings.Contains(account, "@") {
+		return u.userRepo.GetByEmail(ctx, account)
+	}
+	return u.userRepo.GetByPhone(ctx, account)
+}
+
+// normalizePasswordResetAccount 去除首尾空白，邮箱统一转小写。
+func normalizePasswordResetAccount(account string) string {
+	account = strings.TrimSpace(account)
+	if strings.Contains(account, "@") {
+		return strings.ToLower(account)
+	}
+	return account
+}
+
+// generatePasswordResetCode 使用 crypto/rand 生成数字验证码，重置验证码等同临时凭据，不能使用可预测的随机源。
+func generatePasswordResetCode() (string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < consts.PasswordResetCodeLength; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", consts.PasswordResetCodeLength, n.Int64()), nil
+}
+
+// hashPasswordResetCode 以用户 ID 作为盐计算验证码摘要。
+func hashPasswordResetCode(userID uint, code string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", userID, code)))
+	return hex.EncodeToString(sum[:])
+}
+
+func buildPasswordResetMailBody(username, code string) string {
+	return `亲爱的用户[` + username + `]，<br/>
+<br/>
+我们收到了您在` + global.Config.Website.Name + `找回密码的请求，请使用以下验证码重置密码：<br/>
+<br/>
+验证码：[<font color="blue"><u>` + code + `</u></font>]<br/>
+该验证码在 ` + fmt.Sprint(consts.PasswordResetCodeTTLMinutes) + ` 分钟内有效。重置成功后，所有设备上的登录状态都会失效。<br/>
+<br/>
+如果这不是您本人的操作，请忽略此邮件，您的密码不会被修改。<br/>
+<br/>
+祝好，<br/>` +
+		global.Config.Website.Title + `<br/>
+<br/>`
+}
@@ -0,0 +1,329 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	resp "personal_assistant/internal/model/dto/response"
+	"personal_assistant/internal/model/entity"
+	reposystem "personal_assistant/internal/repository/system"
+	bizerrors "personal_assistant/pkg/errors"
+	"personal_assistant/pkg/util"
+
+	"github.com/glebarez/sqlite"
+	"github.com/gofrs/uuid"
+	"github.com/mojocn/base64Captcha"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestResetPasswordByEmailCodeRevokesRefreshTokens(t *testing.T) {
+	env := newPasswordResetTestEnv(t)
+	ctx := context.Background()
+
+	// 用手机号申请，验证码投递到绑定邮箱
+	if err := env.svc.SendPasswordResetCode(ctx, env.captcha("c1"), "127.0.0.1", &request.SendPasswordResetCodeReq{
+		Account: "13800000000", CaptchaID: "c1", Captcha: "123456",
+	}); err != nil {
+		t.Fatalf("SendPasswordResetCode() error = %v", err)
+	}
+	if len(env.mails) != 1 || env.mails[0].to != "alice@example.com" {
+		t.Fatalf("unexpected mails: %+v", env.mails)
+	}
+	code := env.lastCode(t)
+
+	// 错误验证码计入失败次数
+	if err := env.svc.ResetPassword(ctx, &request.ResetPasswordReq{
+		Account: "Alice@Example.com", Code: wrongPasswordResetCode(code), NewPassword: "new-password",
+	}); !hasBizCode(err, bizerrors.CodeCaptchaError) {
+		t.Fatalf("expected captcha error, got %v", err)
+	}
+
+	if err := env.svc.ResetPassword(ctx, &request.ResetPasswordReq{
+		Account: "Alice@Example.com", Code: code, NewPassword: "new-password",
+	}); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	var user entity.User
+	if err := env.db.First(&user, env.userID).Error; err != nil {
+		t.Fatalf("load user: %v", err)
+	}
+	if !util.BcryptCheck("new-password", user.Password) {
+		t.Fatal("password was not updated")
+	}
+	if len(env.jwt.revoked) != 1 || env.jwt.revoked[0] != env.userID {
+		t.Fatalf("expected refresh tokens revoked for user %d, got %v", env.userID, env.jwt.revoked)
+	}
+
+	// 验证码一次性使用
+	if err := env.svc.ResetPassword(ctx, &request.ResetPasswordReq{
+		Account: "13800000000", Code: code, NewPassword: "another-password",
+	}); !hasBizCode(err, bizerrors.CodeCaptchaExpired) {
+		t.Fatalf("expected used code to be rejected, got %v", err)
+	}
+}
+
+func TestSendPasswordResetCodeGuards(t *testing.T) {
+	env := newPasswordResetTestEnv(t)
+	ctx := context.Background()
+
+	if err := env.svc.SendPasswordResetCode(ctx, env.captcha("c1"), "", &request.SendPasswordResetCodeReq{
+		Account: "13800000000", CaptchaID: "c1", Captcha: "654321",
+	}); !hasBizCode(err, bizerrors.CodeCaptchaError) {
+		t.Fatalf("expected captcha error, got %v", err)
+	}
+	// 未注册账号同样返回成功，但不发邮件
+	if err := env.svc.SendPasswordResetCode(ctx, env.captcha("c2"), "", &request.SendPasswordResetCodeReq{
+		Account: "nobody@example.com", CaptchaID: "c2", Captcha: "123456",
+	}); err != nil {
+		t.Fatalf("unknown account should not be revealed, got %v", err)
+	}
+	if len(env.mails) != 0 {
+		t.Fatalf("unexpected mails for unknown account: %+v", env.mails)
+	}
+}
+
+func TestSendPasswordResetCodeRequiresBoundEmail(t *testing.T) {
+	env := newPasswordResetTestEnv(t)
+	ctx := context.Background()
+	bob := &entity.User{
+		UUID:     uuid.Must(uuid.NewV4()),
+		Username: "bob",
+		Phone:    "13800000001",
+		Password: util.BcryptHash("old-password"),
+		Status:   consts.UserStatusActive,
+	}
+	mustCreate(t, env.db, bob)
+
+	// 手机号注册且未绑定邮箱的账号不能假装已发送
+	if err := env.svc.SendPasswordResetCode(ctx, env.captcha("c1"), "", &request.SendPasswordResetCodeReq{
+		Account: bob.Phone, CaptchaID: "c1", Captcha: "123456",
+	}); !hasBizCode(err, bizerrors.CodeEmailNotBound) {
+		t.Fatalf("expected email not bound, got %v", err)
+	}
+	if len(env.mails) != 0 {
+		t.Fatalf("unexpected mails: %+v", env.mails)
+	}
+
+	if _, err := env.svc.BindEmail(ctx, bob.ID, "alice@example.com"); !hasBizCode(err, bizerrors.CodeEmailAlreadyUsed) {
+		t.Fatalf("expected email already used, got %v", err)
+	}
+	bound, err := env.svc.BindEmail(ctx, bob.ID, " Bob@Example.com ")
+	if err != nil {
+		t.Fatalf("BindEmail() error = %v", err)
+	}
+	if bound.Email != "bob@example.com" {
+		t.Fatalf("email = %q, want normalized bob@example.com", bound.Email)
+	}
+
+	if err := env.svc.SendPasswordResetCode(ctx, env.captcha("c2"), "", &request.SendPasswordResetCodeReq{
+		Account: bob.Phone, CaptchaID: "c2", Captcha: "123456",
+	}); err != nil {
+		t.Fatalf("SendPasswordResetCode() error = %v", err)
+	}
+	if len(env.mails) != 1 || env.mails[0].to != "bob@example.com" {
+		t.Fatalf("expected reset mail to bound email, got %+v", env.mails)
+	}
+}
+
+func TestResetPasswordCodeLocksAfterMaxAttempts(t *testing.T) {
+	env := newPasswordResetTestEnv(t)
+	ctx := context.Background()
+
+	if err := env.svc.SendPasswordResetCode(ctx, env.captcha("c1"), "", &request.SendPasswordResetCodeReq{
+		Account: "alice@example.com", CaptchaID: "c1", Captcha: "123456",
+	}); err != nil {
+		t.Fatalf("SendPasswordResetCode() error = %v", err)
+	}
+	code := env.lastCode(t)
+	for i := 0; i < consts.PasswordResetCodeMaxAttempts; i++ {
+		if err := env.svc.ResetPassword(ctx, &request.ResetPasswordReq{
+			Account: "alice@example.com", Code: wrongPasswordResetCode(code), NewPassword: "new-password",
+		}); !hasBizCode(err, bizerrors.CodeCaptchaError) {
+			t.Fatalf("attempt %d: expected captcha error, got %v", i+1, err)
+		}
+	}
+	if err := env.svc.ResetPassword(ctx, &request.ResetPasswordReq{
+		Account: "alice@example.com", Code: code, NewPassword: "new-password",
+	}); !hasBizCode(err, bizerrors.CodeCaptchaExpired) {
+		t.Fatalf("expected locked code to be rejected, got %v", err)
+	}
+	if len(env.jwt.revoked) != 0 {
+		t.Fatalf("tokens should not be revoked, got %v", env.jwt.revoked)
+	}
+}
+
+func TestResetPasswordAttemptsAreBoundedUnderConcurrency(t *testing.T) {
+	env := newPasswordResetTestEnv(t)
+	ctx := context.Background()
+	sqlDB, err := env.db.DB()
+	if err != nil {
+		t.Fatalf("sql db: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := env.svc.SendPasswordResetCode(ctx, env.captcha("c1"), "", &request.SendPasswordResetCodeReq{
+		Account: "alice@example.com", CaptchaID: "c1", Captcha: "123456",
+	}); err != nil {
+		t.Fatalf("SendPasswordResetCode() error = %v", err)
+	}
+	wrong := wrongPasswordResetCode(env.lastCode(t))
+
+	const workers = 4 * consts.PasswordResetCodeMaxAttempts
+	results := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- env.svc.ResetPassword(ctx, &request.ResetPasswordReq{
+				Account: "alice@example.com", Code: wrong, NewPassword: "new-password",
+			})
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	// 只有前 MaxAttempts 次真正比对了验证码，其余请求都因次数耗尽被拒绝
+	verified := 0
+	for err := range results {
+		switch {
+		case hasBizCode(err, bizerrors.CodeCaptchaError):
+			verified++
+		case hasBizCode(err, bizerrors.CodeCaptchaExpired):
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if verified != consts.PasswordResetCodeMaxAttempts {
+		t.Fatalf("verified attempts = %d, want %d", verified, consts.PasswordResetCodeMaxAttempts)
+	}
+}
+
+type passwordResetTestMail struct {
+	to, body string
+}
+
+type passwordResetTestEnv struct {
+	db     *gorm.DB
+	svc    *UserService
+	jwt    *fakePasswordResetJWTService
+	mails  []passwordResetTestMail
+	userID uint
+}
+
+func newPasswordResetTestEnv(t *testing.T) *passwordResetTestEnv {
+	t.Helper()
+
+	oldConfig := global.Config
+	oldLog := global.Log
+	global.Config = &config.Config{}
+	global.Log = zap.NewNop()
+	t.Cleanup(func() {
+		global.Config = oldConfig
+		global.Log = oldLog
+	})
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&entity.Org{}, &entity.User{}, &entity.PasswordResetCode{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	user := &entity.User{
+		UUID:     uuid.Must(uuid.NewV4()),
+		Username: "alice",
+		Phone:    "13800000000",
+		Email:    "alice@example.com",
+		Password: util.BcryptHash("old-password"),
+		Status:   consts.UserStatusActive,
+	}
+	mustCreate(t, db, user)
+
+	env := &passwordResetTestEnv{db: db, jwt: &fakePasswordResetJWTService{}, userID: user.ID}
+	env.svc = &UserService{
+		txRunner:             &stubTxRunner{},
+		userRepo:             reposystem.NewUserRepository(db),
+		passwordResetRepo:    reposystem.NewPasswordResetRepository(db),
+		jwtService:           env.jwt,
+		authorizationService: &fakeAIMemoryManageAuthorization{superAdmins: map[uint]bool{}},
+		sendMail: func(to, _ string, body string) error {
+			env.mails = append(env.mails, passwordResetTestMail{to: to, body: body})
+			return nil
+		},
+	}
+	return env
+}
+
+// captcha 返回一个预置了图形验证码 123456 的存储。
+func (e *passwordResetTestEnv) captcha(id string) base64Captcha.Store {
+	store := base64Captcha.NewMemoryStore(10, 0)
+	_ = store.Set(id, "123456")
+	return store
+}
+
+var passwordResetMailCodePattern = regexp.MustCompile(`<u>(\d{6})</u>`)
+
+func (e *passwordResetTestEnv) lastCode(t *testing.T) string {
+	t.Helper()
+	if len(e.mails) == 0 {
+		t.Fatal("no reset mail sent")
+	}
+	match := passwordResetMailCodePattern.FindStringSubmatch(e.mails[len(e.mails)-1].body)
+	if len(match) != 2 {
+		t.Fatalf("reset code not found in mail: %s", e.mails[len(e.mails)-1].body)
+	}
+	return match[1]
+}
+
+func wrongPasswordResetCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+type fakePasswordResetJWTService struct {
+	revoked []uint
+	reasons []string
+}
+
+func (f *fakePasswordResetJWTService) IssueLoginTokens(context.Context, entity.User, request.LoginDevice) (*resp.LoginResponse, string, int64, *bizerrors.JWTError) {
+	return nil, "", 0, nil
+}
+
+func (f *fakePasswordResetJWTService) IsInBlacklist(string) bool { return false }
+
+func (f *fakePasswordResetJWTService) RefreshTokens(context.Context, string, request.LoginDevice) (*resp.RefreshTokenResponse, int64, *bizerrors.JWTError) {
+	return nil, 0, nil
+}
+
+func (f *fakePasswordResetJWTService) JoinInBlacklist(context.Context, entity.JwtBlacklist) error {
+	return nil
+}
+
+func (f *fakePasswordResetJWTService) RevokeUserRefreshTokens(_ context.Context, user entity.User, reason string) error {
+	f.revoked = append(f.revoked, user.ID)
+	f.reasons = append(f.reasons, reason)
+	return nil
+}
+
+func (f *fakePasswordResetJWTService) ListUserSessions(context.Context, uint, string) ([]*resp.UserSessionItem, error) {
+	return nil, nil
+}
+
+func (f *fakePasswordResetJWTService) RevokeUserSession(context.Context, uint, string, string) error {
+	return nil
+}
+
+func (f *fakePasswordResetJWTService) RevokeOtherUserSessions(context.Context, uint, string) (int, error) {
+	return 0, nil
+}
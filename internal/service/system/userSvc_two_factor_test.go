@@ -0,0 +1,308 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"personal_assistant/global"
+	"personal_assistant/internal/model/config"
+	"personal_assistant/internal/model/consts"
+	"personal_assistant/internal/model/dto/request"
+	"personal_assistant/internal/model/entity"
+	reposystem "personal_assistant/internal/repository/system"
+	bizerrors "personal_assistant/pkg/errors"
+	sensitivedata "personal_assistant/pkg/security/sensitivedata"
+	"personal_assistant/pkg/security/totp"
+
+	"github.com/glebarez/sqlite"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestTwoFactorLoginAcceptsTOTPAndRecoveryCodeOnce(t *testing.T) {
+	env := newTwoFactorTestEnv(t, false)
+	ctx := context.Background()
+	user := env.createUser(t, "alice")
+
+	if challenge, err := env.svc.BeginTwoFactorLogin(ctx, user); err != nil || challenge != nil {
+		t.Fatalf("expected plain login before enrollment, got %+v, %v", challenge, err)
+	}
+
+	setup, err := env.svc.SetupTwoFactor(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("SetupTwoFactor() error = %v", err)
+	}
+	var stored entity.UserTwoFactor
+	if err := env.db.Where("user_id = ?", user.ID).First(&stored).Error; err != nil {
+		t.Fatalf("load two factor setting: %v", err)
+	}
+	if stored.SecretCipher == setup.Secret || !global.SensitiveDataCodec.IsEncrypted(stored.SecretCipher) {
+		t.Fatal("expected secret to be stored encrypted")
+	}
+
+	code := currentTOTPCode(t, setup.Secret)
+	codes, err := env.svc.EnableTwoFactor(ctx, user.ID, &request.TwoFactorCodeReq{Code: code})
+	if err != nil {
+		t.Fatalf("EnableTwoFactor() error = %v", err)
+	}
+	if len(codes.RecoveryCodes) != consts.TwoFactorRecoveryCodeCount {
+		t.Fatalf("expected %d recovery codes, got %d", consts.TwoFactorRecoveryCodeCount, len(codes.RecoveryCodes))
+	}
+
+	challenge, err := env.svc.BeginTwoFactorLogin(ctx, user)
+	if err != nil || challenge == nil || challenge.Purpose != consts.TwoFactorChallengePurposeVerify {
+		t.Fatalf("expected verify challenge, got %+v, %v", challenge, err)
+	}
+	// 启用时用过的动态码不能再次用于登录
+	if _, _, err := env.svc.CompleteTwoFactorLogin(ctx, &request.TwoFactorLoginReq{
+		ChallengeToken: challenge.ChallengeToken, Code: code,
+	}); !hasBizCode(err, bizerrors.CodeTwoFactorInvalid) {
+		t.Fatalf("expected replayed totp to be rejected, got %v", err)
+	}
+	loggedIn, recovery, err := env.svc.CompleteTwoFactorLogin(ctx, &request.TwoFactorLoginReq{
+		ChallengeToken: challenge.ChallengeToken, Code: codes.RecoveryCodes[0],
+	})
+	if err != nil || loggedIn == nil || loggedIn.ID != user.ID || recovery != nil {
+		t.Fatalf("CompleteTwoFactorLogin() = %+v, %v, %v", loggedIn, recovery, err)
+	}
+
+	// 挑战令牌与恢复码都只能使用一次
+	if _, _, err := env.svc.CompleteTwoFactorLogin(ctx, &request.TwoFactorLoginReq{
+		ChallengeToken: challenge.ChallengeToken, Code: codes.RecoveryCodes[1],
+	}); !hasBizCode(err, bizerrors.CodeTwoFactorExpired) {
+		t.Fatalf("expected used challenge to be rejected, got %v", err)
+	}
+	next, err := env.svc.BeginTwoFactorLogin(ctx, user)
+	if err != nil {
+		t.Fatalf("BeginTwoFactorLogin() error = %v", err)
+	}
+	if _, _, err := env.svc.CompleteTwoFactorLogin(ctx, &request.TwoFactorLoginReq{
+		ChallengeToken: next.ChallengeToken, Code: codes.RecoveryCodes[0],
+	}); !hasBizCode(err, bizerrors.CodeTwoFactorInvalid) {
+		t.Fatalf("expected used recovery code to be rejected, got %v", err)
+	}
+
+	status, err := env.svc.GetTwoFactorStatus(ctx, user.ID)
+	if err != nil || !status.Enabled || status.RecoveryCodesRemaining != int64(consts.TwoFactorRecoveryCodeCount-1) {
+		t.Fatalf("unexpected status: %+v, %v", status, err)
+	}
+}
+
+func TestTwoFactorChallengeLocksAfterMaxAttempts(t *testing.T) {
+	env := newTwoFactorTestEnv(t, false)
+	ctx := context.Background()
+	user := env.createUser(t, "alice")
+	setup, err := env.svc.SetupTwoFactor(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("SetupTwoFactor() error = %v", err)
+	}
+	codes, err := env.svc.EnableTwoFactor(ctx, user.ID, &request.TwoFactorCodeReq{Code: currentTOTPCode(t, setup.Secret)})
+	if err != nil {
+		t.Fatalf("EnableTwoFactor() error = %v", err)
+	}
+
+	challenge, err := env.svc.BeginTwoFactorLogin(ctx, user)
+	if err != nil {
+		t.Fatalf("BeginTwoFactorLogin() error = %v", err)
+	}
+	for i := 0; i < consts.TwoFactorChallengeMaxAttempts; i++ {
+		if _, _, err := env.svc.CompleteTwoFactorLogin(ctx, &request.TwoFactorLoginReq{
+			ChallengeToken: challenge.ChallengeToken, Code: "wrong-code",
+		}); !hasBizCode(err, bizerrors.CodeTwoFactorInvalid) {
+			t.Fatalf("attempt %d: expected invalid code, got %v", i+1, err)
+		}
+	}
+	if _, _, err := env.svc.CompleteTwoFactorLogin(ctx, &request.TwoFactorLoginReq{
+		ChallengeToken: challenge.ChallengeToken, Code: codes.RecoveryCodes[0],
+	}); !hasBizCode(err, bizerrors.CodeTwoFactorExpired) {
+		t.Fatalf("expected locked challenge to be rejected, got %v", err)
+	}
+}
+
+func TestTwoFactorChallengeAttemptsAreBoundedUnderConcurrency(t *testing.T) {
+	env := newTwoFactorTestEnv(t, false)
+	ctx := context.Background()
+	sqlDB, err := env.db.DB()
+	if err != nil {
+		t.Fatalf("sql db: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	user := env.createUser(t, "alice")
+	setup, err := env.svc.SetupTwoFactor(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("SetupTwoFactor() error = %v", err)
+	}
+	if _, err := env.svc.EnableTwoFactor(ctx, user.ID, &request.TwoFactorCodeReq{Code: currentTOTPCode(t, setup.Secret)}); err != nil {
+		t.Fatalf("EnableTwoFactor() error = %v", err)
+	}
+	challenge, err := env.svc.BeginTwoFactorLogin(ctx, user)
+	if err != nil {
+		t.Fatalf("BeginTwoFactorLogin() error = %v", err)
+	}
+
+	const workers = 4 * consts.TwoFactorChallengeMaxAttempts
+	results := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := env.svc.CompleteTwoFactorLogin(ctx, &request.TwoFactorLoginReq{
+				ChallengeToken: challenge.ChallengeToken, Code: "wrong-code",
+			})
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	// 只有前 MaxAttempts 次真正校验了动态码，其余请求都因挑战作废被拒绝
+	verified := 0
+	for err := range results {
+		switch {
+		case hasBizCode(err, bizerrors.CodeTwoFactorInvalid):
+			verified++
+		case hasBizCode(err, bizerrors.CodeTwoFactorExpired):
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if verified != consts.TwoFactorChallengeMaxAttempts {
+		t.Fatalf("verified attempts = %d, want %d", verified, consts.TwoFactorChallengeMaxAttempts)
+	}
+}
+
+func TestTwoFactorPolicyForcesAdminEnrollment(t *testing.T) {
+	env := newTwoFactorTestEnv(t, true)
+	ctx := context.Background()
+	orgAdmin := env.createUser(t, "org-admin")
+	superAdmin := env.createUser(t, "root")
+	member := env.createUser(t, "member")
+	env.authz.superAdmins[superAdmin.ID] = true
+
+	role := &entity.Role{Name: "组织管理员", Code: consts.RoleCodeOrgAdmin, Status: 1}
+	mustCreate(t, env.db, role)
+	mustCreate(t, env.db, &entity.UserOrgRole{UserID: orgAdmin.ID, OrgID: 1, RoleID: role.ID})
+
+	if challenge, err := env.svc.BeginTwoFactorLogin(ctx, member); err != nil || challenge != nil {
+		t.Fatalf("member should log in without 2FA, got %+v, %v", challenge, err)
+	}
+	if challenge, err := env.svc.BeginTwoFactorLogin(ctx, superAdmin); err != nil || challenge == nil ||
+		challenge.Purpose != consts.TwoFactorChallengePurposeEnroll {
+		t.Fatalf("expected enroll challenge for super admin, got %+v, %v", challenge, err)
+	}
+
+	challenge, err := env.svc.BeginTwoFactorLogin(ctx, orgAdmin)
+	if err != nil || challenge == nil || challenge.Purpose != consts.TwoFactorChallengePurposeEnroll {
+		t.Fatalf("expected enroll challenge for org admin, got %+v, %v", challenge, err)
+	}
+	setup, err := env.svc.SetupTwoFactorByChallenge(ctx, &request.TwoFactorChallengeReq{ChallengeToken: challenge.ChallengeToken})
+	if err != nil {
+		t.Fatalf("SetupTwoFactorByChallenge() error = %v", err)
+	}
+	loggedIn, recovery, err := env.svc.CompleteTwoFactorLogin(ctx, &request.TwoFactorLoginReq{
+		ChallengeToken: challenge.ChallengeToken, Code: currentTOTPCode(t, setup.Secret),
+	})
+	if err != nil || loggedIn == nil || len(recovery) != consts.TwoFactorRecoveryCodeCount {
+		t.Fatalf("CompleteTwoFactorLogin() = %+v, %d codes, %v", loggedIn, len(recovery), err)
+	}
+
+	status, err := env.svc.GetTwoFactorStatus(ctx, orgAdmin.ID)
+	if err != nil || !status.Enabled || !status.Required {
+		t.Fatalf("unexpected status: %+v, %v", status, err)
+	}
+	if err := env.svc.DisableTwoFactor(ctx, orgAdmin.ID, &request.TwoFactorCodeReq{Code: recovery[0]}); !hasBizCode(err, bizerrors.CodeTwoFactorRequired) {
+		t.Fatalf("expected admin to be unable to disable 2FA, got %v", err)
+	}
+}
+
+type twoFactorTestEnv struct {
+	db    *gorm.DB
+	svc   *UserService
+	authz *fakeAIMemoryManageAuthorization
+}
+
+func newTwoFactorTestEnv(t *testing.T, enforceAdmin bool) *twoFactorTestEnv {
+	t.Helper()
+
+	codec, err := sensitivedata.New(sensitivedata.Options{
+		AESKeyBase64:  "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+		HashKeyBase64: "ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA=",
+	})
+	if err != nil {
+		t.Fatalf("new codec: %v", err)
+	}
+	oldConfig := global.Config
+	oldLog := global.Log
+	oldCodec := global.SensitiveDataCodec
+	global.Config = &config.Config{Security: config.Security{TwoFactor: config.TwoFactor{
+		Issuer:       "test",
+		EnforceAdmin: enforceAdmin,
+	}}}
+	global.Log = zap.NewNop()
+	global.SensitiveDataCodec = codec
+	t.Cleanup(func() {
+		global.Config = oldConfig
+		global.Log = oldLog
+		global.SensitiveDataCodec = oldCodec
+	})
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&entity.Org{},
+		&entity.User{},
+		&entity.Role{},
+		&entity.UserOrgRole{},
+		&entity.UserTwoFactor{},
+		&entity.UserRecoveryCode{},
+		&entity.TwoFactorChallenge{},
+	); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+
+	authz := &fakeAIMemoryManageAuthorization{superAdmins: map[uint]bool{}}
+	return &twoFactorTestEnv{
+		db:    db,
+		authz: authz,
+		svc: &UserService{
+			txRunner:             &stubTxRunner{},
+			userRepo:             reposystem.NewUserRepository(db),
+			roleRepo:             reposystem.NewRoleRepository(db),
+			twoFactorRepo:        reposystem.NewTwoFactorRepository(db),
+			authorizationService: authz,
+		},
+	}
+}
+
+func (e *twoFactorTestEnv) createUser(t *testing.T, username string) *entity.User {
+	t.Helper()
+	var count int64
+	if err := e.db.Model(&entity.User{}).Count(&count).Error; err != nil {
+		t.Fatalf("count users: %v", err)
+	}
+	user := &entity.User{
+		UUID:     uuid.Must(uuid.NewV4()),
+		Username: username,
+		Phone:    fmt.Sprintf("138%08d", count+1),
+		Status:   consts.UserStatusActive,
+	}
+	mustCreate(t, e.db, user)
+	return user
+}
+
+func currentTOTPCode(t *testing.T, secret string) string {
+	t.Helper()
+	code, err := totp.CodeAt(secret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatalf("totp code: %v", err)
+	}
+	return code
+}
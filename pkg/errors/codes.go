@@ -41,39 +41,56 @@ const (
 
 	// ==================== 用户模块 2xxxx ====================
 
-	CodeUserNotFound       BizCode = 20001 // 用户不存在
-	CodeUserAlreadyExists  BizCode = 20002 // 用户已存在
-	CodePasswordError      BizCode = 20003 // 密码错误
-	CodeUserFrozen         BizCode = 20004 // 用户已被冻结
-	CodeUserDisabled       BizCode = 20005 // 用户已被禁用
-	CodePhoneAlreadyUsed   BizCode = 20006 // 手机号已被使用
-	CodeEmailAlreadyUsed   BizCode = 20007 // 邮箱已被使用
-	CodeCaptchaError       BizCode = 20008 // 验证码错误
-	CodeCaptchaExpired     BizCode = 20009 // 验证码已过期
-	CodeEmailSendFailed    BizCode = 20010 // 邮件发送失败
-	CodeUserStatusConflict BizCode = 20011 // 用户状态冲突
+	CodeUserNotFound          BizCode = 20001 // 用户不存在
+	CodeUserAlreadyExists     BizCode = 20002 // 用户已存在
+	CodePasswordError         BizCode = 20003 // 密码错误
+	CodeUserFrozen            BizCode = 20004 // 用户已被冻结
+	CodeUserDisabled          BizCode = 20005 // 用户已被禁用
+	CodePhoneAlreadyUsed      BizCode = 20006 // 手机号已被使用
+	CodeEmailAlreadyUsed      BizCode = 20007 // 邮箱已被使用
+	CodeCaptchaError          BizCode = 20008 // 验证码错误
+	CodeCaptchaExpired        BizCode = 20009 // 验证码已过期
+	CodeEmailSendFailed       BizCode = 20010 // 邮件发送失败
+	CodeUserStatusConflict    BizCode = 20011 // 用户状态冲突
+	CodeSessionNotFound       BizCode = 20012 // 登录会话不存在
+	CodeTwoFactorInvalid      BizCode = 20013 // 二次验证码错误
+	CodeTwoFactorExpired      BizCode = 20014 // 二次验证挑战已失效
+	CodeTwoFactorDisabled     BizCode = 20015 // 未开启二次验证
+	CodeTwoFactorEnabled      BizCode = 20016 // 已开启二次验证
+	CodeTwoFactorRequired     BizCode = 20017 // 账号必须开启二次验证
+	CodeOAuthProviderNotFound BizCode = 20018 // 第三方登录方式不存在或未启用
+	CodeOAuthStateInvalid     BizCode = 20019 // 第三方登录状态已失效
+	CodeOAuthLoginFailed      BizCode = 20020 // 第三方身份校验失败
+	CodeOAuthIdentityLinked   BizCode = 20021 // 第三方账号已绑定其他用户
+	CodeOAuthAlreadyLinked    BizCode = 20022 // 当前账号已绑定第三方账号
+	CodeOAuthUnlinkDenied     BizCode = 20023 // 解绑后账号将无法登录
+	CodeEmailNotBound         BizCode = 20024 // 账号未绑定邮箱
 
 	// ==================== 组织与权限模块 3xxxx ====================
 
-	CodeOrgNotFound              BizCode = 30001 // 组织不存在
-	CodeOrgAlreadyExists         BizCode = 30002 // 组织已存在
-	CodeOrgNameDuplicate         BizCode = 30003 // 组织名称重复
-	CodeNotOrgMember             BizCode = 30004 // 非组织成员
-	CodeOrgHasMembers            BizCode = 30005 // 组织下有成员，无法删除
-	CodeOrgOwnerOnly             BizCode = 30006 // 仅组织所有者可操作
-	CodeInviteCodeInvalid        BizCode = 30007 // 邀请码无效
-	CodeOrgBuiltinProtected      BizCode = 30008 // 系统内置组织受保护
-	CodeOrgMemberStatusConflict  BizCode = 30009 // 成员状态冲突
-	CodeOrgMemberRemoved         BizCode = 30010 // 成员已被移除
-	CodeOrgOwnerTransferRequired BizCode = 30011 // 组织所有者需先移交
-	CodeOrgCannotLeaveBuiltin    BizCode = 30012 // 内置组织不可退出
-	CodeRoleNotFound             BizCode = 30101 // 角色不存在
-	CodeRoleAlreadyExists        BizCode = 30102 // 角色已存在
-	CodeMenuNotFound             BizCode = 30201 // 菜单不存在
-	CodeMenuCodeDuplicate        BizCode = 30202 // 菜单code重复
-	CodeMenuHasChildren          BizCode = 30203 // 菜单存在子菜单，无法删除
-	CodeAPINotFound              BizCode = 30301 // API不存在
-	CodeAPIAlreadyExists         BizCode = 30302 // API已存在（path+method重复）
+	CodeOrgNotFound               BizCode = 30001 // 组织不存在
+	CodeOrgAlreadyExists          BizCode = 30002 // 组织已存在
+	CodeOrgNameDuplicate          BizCode = 30003 // 组织名称重复
+	CodeNotOrgMember              BizCode = 30004 // 非组织成员
+	CodeOrgHasMembers             BizCode = 30005 // 组织下有成员，无法删除
+	CodeOrgOwnerOnly              BizCode = 30006 // 仅组织所有者可操作
+	CodeInviteCodeInvalid         BizCode = 30007 // 邀请码无效
+	CodeOrgBuiltinProtected       BizCode = 30008 // 系统内置组织受保护
+	CodeOrgMemberStatusConflict   BizCode = 30009 // 成员状态冲突
+	CodeOrgMemberRemoved          BizCode = 30010 // 成员已被移除
+	CodeOrgOwnerTransferRequired  BizCode = 30011 // 组织所有者需先移交
+	CodeOrgCannotLeaveBuiltin     BizCode = 30012 // 内置组织不可退出
+	CodeOrgInvitationInvalid      BizCode = 30013 // 组织邀请无效或已失效
+	CodeOrgInvitationNotForYou    BizCode = 30014 // 组织邀请不属于当前账号
+	CodeOrgJoinRequestNotFound    BizCode = 30015 // 加入申请不存在或已处理
+	CodeOrgInvitationEmailUnbound BizCode = 30016 // 定向邀请邮箱未被任何账号绑定
+	CodeRoleNotFound              BizCode = 30101 // 角色不存在
+	CodeRoleAlreadyExists         BizCode = 30102 // 角色已存在
+	CodeMenuNotFound              BizCode = 30201 // 菜单不存在
+	CodeMenuCodeDuplicate         BizCode = 30202 // 菜单code重复
+	CodeMenuHasChildren           BizCode = 30203 // 菜单存在子菜单，无法删除
+	CodeAPINotFound               BizCode = 30301 // API不存在
+	CodeAPIAlreadyExists          BizCode = 30302 // API已存在（path+method重复）
 
 	// ==================== OJ模块 4xxxx ====================
 
@@ -94,6 +111,16 @@ const (
 	CodeOJTaskExecutionNotFound   BizCode = 40107 // OJ任务执行记录不存在
 	CodeOJTaskPendingConfirmation BizCode = 40108 // OJ任务存在未确认的新题
 	CodeOJTaskQuestionAmbiguous   BizCode = 40109 // OJ任务题目存在多个候选
+	CodeOJTaskRecurrenceInvalid   BizCode = 40110 // OJ任务周期规则非法
+	CodeOJTaskDeadlineInvalid     BizCode = 40111 // OJ任务截止时间非法
+	CodeOJContestNotFound         BizCode = 40201 // OJ比赛不存在
+	CodeOJContestNotEditable      BizCode = 40202 // OJ比赛不可修改
+	CodeOJContestTimeInvalid      BizCode = 40203 // OJ比赛时间非法
+	CodeOJContestVisibleDenied    BizCode = 40204 // OJ比赛无可见权限
+	CodeOJQuestionNotFound        BizCode = 40301 // 题库题目不存在
+	CodeOJQuestionTagNotFound     BizCode = 40302 // 题库标签不存在
+	CodeOJQuestionTagDuplicate    BizCode = 40303 // 题库标签名称重复
+	CodeOJQuestionDuplicate       BizCode = 40304 // 题库题目编号重复
 
 	// ==================== AI模块 5xxxx ====================
 
@@ -105,6 +132,8 @@ const (
 	CodeAIStreamingUnsupported BizCode = 50006 // AI流式输出不可用
 	CodeAIRequestRejected      BizCode = 50007 // AI请求被拒绝
 	CodeAIMessageNotFound      BizCode = 50008 // AI消息不存在
+	CodeAIMemoryNotFound       BizCode = 50009 // AI记忆不存在
+	CodeAIShareNotFound        BizCode = 50010 // AI分享链接不存在或已失效
 )
 
 // codeMessages 错误码与默认消息的映射
@@ -132,38 +161,55 @@ var codeMessages = map[BizCode]string{
 	CodePermissionDenied: "权限不足",
 
 	// 用户模块
-	CodeUserNotFound:       "用户不存在",
-	CodeUserAlreadyExists:  "用户已存在",
-	CodePasswordError:      "用户名或密码错误",
-	CodeUserFrozen:         "用户已被冻结",
-	CodeUserDisabled:       "用户已被禁用",
-	CodePhoneAlreadyUsed:   "手机号已被使用",
-	CodeEmailAlreadyUsed:   "邮箱已被使用",
-	CodeCaptchaError:       "验证码错误",
-	CodeCaptchaExpired:     "验证码已过期",
-	CodeEmailSendFailed:    "邮件发送失败",
-	CodeUserStatusConflict: "账号状态不允许该操作",
+	CodeUserNotFound:          "用户不存在",
+	CodeUserAlreadyExists:     "用户已存在",
+	CodePasswordError:         "用户名或密码错误",
+	CodeUserFrozen:            "用户已被冻结",
+	CodeUserDisabled:          "用户已被禁用",
+	CodePhoneAlreadyUsed:      "手机号已被使用",
+	CodeEmailAlreadyUsed:      "邮箱已被使用",
+	CodeCaptchaError:          "验证码错误",
+	CodeCaptchaExpired:        "验证码已过期",
+	CodeEmailSendFailed:       "邮件发送失败",
+	CodeUserStatusConflict:    "账号状态不允许该操作",
+	CodeSessionNotFound:       "登录会话不存在或已下线",
+	CodeTwoFactorInvalid:      "二次验证码错误",
+	CodeTwoFactorExpired:      "二次验证已失效，请重新登录",
+	CodeTwoFactorDisabled:     "未开启二次验证",
+	CodeTwoFactorEnabled:      "已开启二次验证",
+	CodeTwoFactorRequired:     "管理员账号必须开启二次验证",
+	CodeOAuthProviderNotFound: "第三方登录方式不存在或未启用",
+	CodeOAuthStateInvalid:     "第三方登录已失效，请重新发起",
+	CodeOAuthLoginFailed:      "第三方身份校验失败",
+	CodeOAuthIdentityLinked:   "该第三方账号已绑定其他用户",
+	CodeOAuthAlreadyLinked:    "当前账号已绑定第三方账号，请先解绑",
+	CodeOAuthUnlinkDenied:     "请先绑定手机号后再解绑第三方账号",
+	CodeEmailNotBound:         "账号未绑定邮箱，请登录后先绑定邮箱",
 
 	// 组织与权限
-	CodeOrgNotFound:              "组织不存在",
-	CodeOrgAlreadyExists:         "组织已存在",
-	CodeOrgNameDuplicate:         "组织名称已存在",
-	CodeNotOrgMember:             "您不是该组织成员",
-	CodeOrgHasMembers:            "组织下还有成员，无法删除",
-	CodeOrgOwnerOnly:             "仅组织所有者可操作",
-	CodeInviteCodeInvalid:        "邀请码无效",
-	CodeOrgBuiltinProtected:      "系统内置组织不允许该操作",
-	CodeOrgMemberStatusConflict:  "成员状态冲突",
-	CodeOrgMemberRemoved:         "成员已被移除，需管理员恢复",
-	CodeOrgOwnerTransferRequired: "组织所有者请先移交后再操作",
-	CodeOrgCannotLeaveBuiltin:    "系统内置组织不可退出",
-	CodeRoleNotFound:             "角色不存在",
-	CodeRoleAlreadyExists:        "角色已存在",
-	CodeMenuNotFound:             "菜单不存在",
-	CodeMenuCodeDuplicate:        "菜单权限标识已存在",
-	CodeMenuHasChildren:          "该菜单下存在子菜单，无法删除",
-	CodeAPINotFound:              "API不存在",
-	CodeAPIAlreadyExists:         "API已存在（路径与方法组合重复）",
+	CodeOrgNotFound:               "组织不存在",
+	CodeOrgAlreadyExists:          "组织已存在",
+	CodeOrgNameDuplicate:          "组织名称已存在",
+	CodeNotOrgMember:              "您不是该组织成员",
+	CodeOrgHasMembers:             "组织下还有成员，无法删除",
+	CodeOrgOwnerOnly:              "仅组织所有者可操作",
+	CodeInviteCodeInvalid:         "邀请码无效",
+	CodeOrgBuiltinProtected:       "系统内置组织不允许该操作",
+	CodeOrgMemberStatusConflict:   "成员状态冲突",
+	CodeOrgMemberRemoved:          "成员已被移除，需管理员恢复",
+	CodeOrgOwnerTransferRequired:  "组织所有者请先移交后再操作",
+	CodeOrgCannotLeaveBuiltin:     "系统内置组织不可退出",
+	CodeOrgInvitationInvalid:      "邀请链接无效或已失效",
+	CodeOrgInvitationNotForYou:    "该邀请仅限指定的邮箱或手机号使用",
+	CodeOrgJoinRequestNotFound:    "加入申请不存在或已处理",
+	CodeOrgInvitationEmailUnbound: "该邮箱尚未被任何账号绑定，请受邀人先在个人设置中绑定邮箱",
+	CodeRoleNotFound:              "角色不存在",
+	CodeRoleAlreadyExists:         "角色已存在",
+	CodeMenuNotFound:              "菜单不存在",
+	CodeMenuCodeDuplicate:         "菜单权限标识已存在",
+	CodeMenuHasChildren:           "该菜单下存在子菜单，无法删除",
+	CodeAPINotFound:               "API不存在",
+	CodeAPIAlreadyExists:          "API已存在（路径与方法组合重复）",
 
 	// OJ模块
 	CodeOJAccountNotBound:         "OJ账号未绑定",
@@ -183,6 +229,16 @@ var codeMessages = map[BizCode]string{
 	CodeOJTaskExecutionNotFound:   "OJ任务执行记录不存在",
 	CodeOJTaskPendingConfirmation: "存在未确认的新题目，请确认后再创建任务",
 	CodeOJTaskQuestionAmbiguous:   "任务题目存在多个候选，请先确认具体题目",
+	CodeOJTaskRecurrenceInvalid:   "周期规则不合法",
+	CodeOJTaskDeadlineInvalid:     "截止时间不合法",
+	CodeOJContestNotFound:         "OJ比赛不存在",
+	CodeOJContestNotEditable:      "比赛已开始或已结束，不可修改",
+	CodeOJContestTimeInvalid:      "比赛时间不合法",
+	CodeOJContestVisibleDenied:    "无权查看该 OJ 比赛",
+	CodeOJQuestionNotFound:        "题库中不存在该题目",
+	CodeOJQuestionTagNotFound:     "题库标签不存在",
+	CodeOJQuestionTagDuplicate:    "题库标签名称已存在",
+	CodeOJQuestionDuplicate:       "题库中已存在该题目编号",
 
 	// AI模块
 	CodeAIConversationNotFound: "AI会话不存在",
@@ -193,6 +249,8 @@ var codeMessages = map[BizCode]string{
 	CodeAIStreamingUnsupported: "当前环境不支持流式输出",
 	CodeAIRequestRejected:      "当前请求不符合 AI 流式约束",
 	CodeAIMessageNotFound:      "AI消息不存在",
+	CodeAIMemoryNotFound:       "AI记忆不存在",
+	CodeAIShareNotFound:        "分享链接不存在或已失效",
 }
 
 // Message 获取错误码对应的默认消息
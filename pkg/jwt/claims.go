@@ -60,6 +60,19 @@ func GetUserID(c *gin.Context) uint {
 	}
 }
 
+// GetAuthenticatedUserID 只读取认证中间件写入Context的claims，不回退解析请求中的令牌。
+// 用于公共路由上的可选登录接口：未登录或令牌无效时返回0。
+func GetAuthenticatedUserID(c *gin.Context) uint {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return 0
+	}
+	if waitUse, ok := claims.(*request.JwtCustomClaims); ok && waitUse != nil {
+		return waitUse.UserID
+	}
+	return 0
+}
+
 // GetUUID 从Gin的Context中获取JWT解析出来的用户UUID
 func GetUUID(c *gin.Context) uuid.UUID {
 	// 首先尝试从Context中获取"claims"
@@ -79,6 +92,17 @@ func GetUUID(c *gin.Context) uuid.UUID {
 	}
 }
 
+// GetSessionID 从Gin的Context中获取当前访问令牌所属的登录会话标识
+func GetSessionID(c *gin.Context) string {
+	if claims, exists := c.Get("claims"); exists {
+		return claims.(*request.JwtCustomClaims).SessionID
+	}
+	if cl, err := GetClaims(c); err == nil {
+		return cl.SessionID
+	}
+	return ""
+}
+
 // 注意：GetRoleID函数已移除，因为JWT中不再包含RoleID字段
 // 现在应该通过权限服务动态获取用户角色信息
 
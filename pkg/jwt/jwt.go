@@ -55,7 +55,8 @@ func (j *JWT) CreateAccessToken(claims request.JwtCustomClaims) (string, error)
 func (j *JWT) CreateRefreshClaims(baseClaims request.BaseClaims) request.JwtCustomRefreshClaims {
 	ep, _ := util.ParseDuration(global.Config.JWT.RefreshTokenExpiryTime) // 获取过期时间
 	claims := request.JwtCustomRefreshClaims{
-		UserID: baseClaims.UserID, // 用户 ID
+		UserID:    baseClaims.UserID,    // 用户 ID
+		SessionID: baseClaims.SessionID, // 会话 ID
 		RegisteredClaims: jwt.RegisteredClaims{
 			Audience:  jwt.ClaimStrings{"TAP"},                // 受众
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ep)), // 过期时间
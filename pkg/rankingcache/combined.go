@@ -0,0 +1,87 @@
+package rankingcache
+
+import (
+	"math"
+
+	"personal_assistant/global"
+)
+
+// PlatformAll 表示跨平台综合排行榜，zset key 与单平台榜共用 all_members / org 前缀。
+const PlatformAll = "all"
+
+// CombinedPlatforms 是综合排行榜合并的平台，各平台过题数不去重，只按权重折算到统一刻度。
+var CombinedPlatforms = []string{PlatformLeetcode, PlatformLuogu, PlatformLanqiao}
+
+// RankedPlatforms 是需要维护排行榜 zset 的全部维度：单平台榜加上综合榜。
+var RankedPlatforms = append(append([]string{}, Platforms...), PlatformAll)
+
+// CombinedProfile 是用户跨平台的统一 OJ 画像。
+type CombinedProfile struct {
+	Score          int // 按平台权重折算后的综合分，用于综合排行榜排序。
+	SolvedTotal    int // 各平台过题数直接相加，仅用于展示。
+	BoundPlatforms int // 已绑定的平台数量，为 0 时不进入综合排行榜。
+}
+
+// CombinedWeight 返回平台在综合排行榜中的单题折算分，未配置或配置非法时按 1 处理。
+func CombinedWeight(platform string) float64 {
+	weight := 0.0
+	if global.Config != nil {
+		switch platform {
+		case PlatformLeetcode:
+			weight = global.Config.System.RankingWeightLeetcode
+		case PlatformLuogu:
+			weight = global.Config.System.RankingWeightLuogu
+		case PlatformLanqiao:
+			weight = global.Config.System.RankingWeightLanqiao
+		}
+	}
+	if weight <= 0 {
+		return 1
+	}
+	return weight
+}
+
+// CombinedScore 按当前权重把各平台题数折算成综合分，供综合榜与时间窗口综合榜复用。
+func CombinedScore(solvedByPlatform map[string]int) int {
+	total := 0.0
+	for _, platform := range CombinedPlatforms {
+		total += float64(solvedByPlatform[platform]) * CombinedWeight(platform)
+	}
+	return int(math.Round(total))
+}
+
+// refreshCombined 根据各平台资料重新计算统一画像，只统计已绑定的平台。
+func (p *UserProjection) refreshCombined() {
+	solved := make(map[string]int, len(CombinedPlatforms))
+	combined := CombinedProfile{}
+	for _, platform := range CombinedPlatforms {
+		profile := p.Platform(platform)
+		if profile.Identifier == "" {
+			continue
+		}
+		solved[platform] = profile.Score
+		combined.SolvedTotal += profile.Score
+		combined.BoundPlatforms++
+	}
+	combined.Score = CombinedScore(solved)
+	p.Combined = combined
+}
+
+// combinedPlatformProfile 把统一画像包装成 PlatformProfile，使综合榜可以复用单平台榜的维护与展示逻辑。
+// 标识与头像取第一个已绑定平台，仅用于判断是否上榜及兜底展示。
+func (p *UserProjection) combinedPlatformProfile() PlatformProfile {
+	out := PlatformProfile{Score: p.Combined.Score}
+	for _, platform := range CombinedPlatforms {
+		profile := p.Platform(platform)
+		if profile.Identifier == "" {
+			continue
+		}
+		if out.Identifier == "" {
+			out.Identifier = profile.Identifier
+		}
+		if out.Avatar == "" {
+			out.Avatar = profile.Avatar
+		}
+	}
+	return out
+}
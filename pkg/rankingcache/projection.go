@@ -4,32 +4,45 @@ import (
 	"strconv"
 	"strings"
 
+	"personal_assistant/internal/model/consts"
 	readmodel "personal_assistant/internal/model/readmodel"
 )
 
 const (
-	PlatformLuogu    = "luogu"
-	PlatformLeetcode = "leetcode"
-	PlatformLanqiao  = "lanqiao"
+	PlatformLuogu    = consts.OJPlatformLuogu
+	PlatformLeetcode = consts.OJPlatformLeetcode
+	PlatformLanqiao  = consts.OJPlatformLanqiao
 )
 
+// Platforms 是排行榜投影覆盖的全部平台，维护 zset 时按该顺序逐个处理；扩展平台来自 consts.OJProviderPlatforms。
+var Platforms = consts.SupportedOJPlatforms()
+
+// WeightedPlatforms 是额外维护难度加权 zset 的平台。
+var WeightedPlatforms = []string{PlatformLuogu, PlatformLeetcode}
+
 const (
-	hashFieldUsername        = "username"
-	hashFieldAvatar          = "avatar"
-	hashFieldCurrentOrgID    = "current_org_id"
-	hashFieldCurrentOrgName  = "current_org_name"
-	hashFieldActive          = "active"
-	hashFieldLuoguIdentifier = "luogu_identifier"
-	hashFieldLuoguAvatar     = "luogu_avatar"
-	hashFieldLuoguScore      = "luogu_score"
-	hashFieldLeetcodeSlug    = "leetcode_identifier"
-	hashFieldLeetcodeAvatar  = "leetcode_avatar"
-	hashFieldLeetcodeScore   = "leetcode_score"
-	hashFieldLanqiaoID       = "lanqiao_identifier"
-	hashFieldLanqiaoAvatar   = "lanqiao_avatar"
-	hashFieldLanqiaoScore    = "lanqiao_score"
-	hashActiveValueTrue      = "1"
-	hashActiveValueFalse     = "0"
+	hashFieldUsername         = "username"
+	hashFieldAvatar           = "avatar"
+	hashFieldCurrentOrgID     = "current_org_id"
+	hashFieldCurrentOrgName   = "current_org_name"
+	hashFieldActive           = "active"
+	hashFieldLuoguIdentifier  = "luogu_identifier"
+	hashFieldLuoguAvatar      = "luogu_avatar"
+	hashFieldLuoguScore       = "luogu_score"
+	hashFieldLuoguWeighted    = "luogu_weighted_score"
+	hashFieldLeetcodeSlug     = "leetcode_identifier"
+	hashFieldLeetcodeAvatar   = "leetcode_avatar"
+	hashFieldLeetcodeScore    = "leetcode_score"
+	hashFieldLeetcodeWeighted = "leetcode_weighted_score"
+	hashFieldLanqiaoID        = "lanqiao_identifier"
+	hashFieldLanqiaoAvatar    = "lanqiao_avatar"
+	hashFieldLanqiaoScore     = "lanqiao_score"
+	// 扩展平台的 hash 字段按 <platform> + 后缀拼接，与内置平台的命名保持一致。
+	hashFieldProviderIDSuffix     = "_identifier"
+	hashFieldProviderAvatarSuffix = "_avatar"
+	hashFieldProviderScoreSuffix  = "_score"
+	hashActiveValueTrue           = "1"
+	hashActiveValueFalse          = "0"
 )
 
 // UserProjection 是从数据库读模型转换而来的用户数据投影结构，包含排行榜相关的所有字段。
@@ -37,6 +50,8 @@ type PlatformProfile struct {
 	Identifier string // 在平台上的唯一标识，例如用户名或 slug。
 	Avatar     string // 平台头像 URL，可能与用户主头像不同。
 	Score      int    //	在平台上的分数或排名指标，用于排行榜排序。
+	// WeightedScore 是按题目难度加权后的分数，只有 WeightedPlatforms 中的平台有值。
+	WeightedScore int
 }
 
 type UserProjection struct {
@@ -49,6 +64,10 @@ type UserProjection struct {
 	Luogu          PlatformProfile
 	Leetcode       PlatformProfile
 	Lanqiao        PlatformProfile
+	// Providers 是扩展平台的资料，键为平台标识，未绑定的平台不出现。
+	Providers map[string]PlatformProfile
+	// Combined 是由上面各平台资料派生的跨平台统一画像，不单独写入 hash。
+	Combined CombinedProfile
 }
 
 // FromReadModel 从数据库读模型构建 UserProjection 对象，确保字段映射的正确性和完整性。
@@ -56,7 +75,7 @@ func FromReadModel(item *readmodel.Ranking) *UserProjection {
 	if item == nil {
 		return nil
 	}
-	return &UserProjection{
+	out := &UserProjection{
 		UserID:         item.UserID,
 		Username:       item.Username,
 		Avatar:         item.Avatar,
@@ -67,11 +86,15 @@ func FromReadModel(item *readmodel.Ranking) *UserProjection {
 			Identifier: item.LuoguIdentifier,
 			Avatar:     item.LuoguAvatar,
 			Score:      item.LuoguScore,
+
+			WeightedScore: item.LuoguWeightedScore,
 		},
 		Leetcode: PlatformProfile{
 			Identifier: item.LeetcodeIdentifier,
 			Avatar:     item.LeetcodeAvatar,
 			Score:      item.LeetcodeScore,
+
+			WeightedScore: item.LeetcodeWeightedScore,
 		},
 		Lanqiao: PlatformProfile{
 			Identifier: item.LanqiaoIdentifier,
@@ -79,6 +102,15 @@ func FromReadModel(item *readmodel.Ranking) *UserProjection {
 			Score:      item.LanqiaoScore,
 		},
 	}
+	for platform, profile := range item.Providers {
+		out.setProvider(platform, PlatformProfile{
+			Identifier: profile.Identifier,
+			Avatar:     profile.Avatar,
+			Score:      profile.Score,
+		})
+	}
+	out.refreshCombined()
+	return out
 }
 
 // HashValues 将 UserProjection 转换为适合 Redis hash 存储的字段-值映射，确保数据类型和格式的正确处理。
@@ -88,18 +120,27 @@ func (p *UserProjection) HashValues() map[string]interface{} {
 	}
 
 	values := map[string]interface{}{
-		hashFieldUsername:        p.Username,
-		hashFieldAvatar:          p.Avatar,
-		hashFieldCurrentOrgName:  p.CurrentOrgName,
-		hashFieldLuoguIdentifier: p.Luogu.Identifier,
-		hashFieldLuoguAvatar:     p.Luogu.Avatar,
-		hashFieldLuoguScore:      p.Luogu.Score,
-		hashFieldLeetcodeSlug:    p.Leetcode.Identifier,
-		hashFieldLeetcodeAvatar:  p.Leetcode.Avatar,
-		hashFieldLeetcodeScore:   p.Leetcode.Score,
-		hashFieldLanqiaoID:       p.Lanqiao.Identifier,
-		hashFieldLanqiaoAvatar:   p.Lanqiao.Avatar,
-		hashFieldLanqiaoScore:    p.Lanqiao.Score,
+		hashFieldUsername:         p.Username,
+		hashFieldAvatar:           p.Avatar,
+		hashFieldCurrentOrgName:   p.CurrentOrgName,
+		hashFieldLuoguIdentifier:  p.Luogu.Identifier,
+		hashFieldLuoguAvatar:      p.Luogu.Avatar,
+		hashFieldLuoguScore:       p.Luogu.Score,
+		hashFieldLuoguWeighted:    p.Luogu.WeightedScore,
+		hashFieldLeetcodeSlug:     p.Leetcode.Identifier,
+		hashFieldLeetcodeAvatar:   p.Leetcode.Avatar,
+		hashFieldLeetcodeScore:    p.Leetcode.Score,
+		hashFieldLeetcodeWeighted: p.Leetcode.WeightedScore,
+		hashFieldLanqiaoID:        p.Lanqiao.Identifier,
+		hashFieldLanqiaoAvatar:    p.Lanqiao.Avatar,
+		hashFieldLanqiaoScore:     p.Lanqiao.Score,
+	}
+	// 未绑定的扩展平台也写入空值，覆盖解绑前残留的旧字段。
+	for _, platform := range consts.OJProviderPlatforms {
+		profile := p.Providers[platform]
+		values[platform+hashFieldProviderIDSuffix] = profile.Identifier
+		values[platform+hashFieldProviderAvatarSuffix] = profile.Avatar
+		values[platform+hashFieldProviderScoreSuffix] = profile.Score
 	}
 	if p.Active {
 		values[hashFieldActive] = hashActiveValueTrue
@@ -135,11 +176,15 @@ func ProjectionFromHash(userID uint, values map[string]string) (*UserProjection,
 			Identifier: strings.TrimSpace(values[hashFieldLuoguIdentifier]),
 			Avatar:     strings.TrimSpace(values[hashFieldLuoguAvatar]),
 			Score:      parseInt(values[hashFieldLuoguScore]),
+
+			WeightedScore: parseInt(values[hashFieldLuoguWeighted]),
 		},
 		Leetcode: PlatformProfile{
 			Identifier: strings.TrimSpace(values[hashFieldLeetcodeSlug]),
 			Avatar:     strings.TrimSpace(values[hashFieldLeetcodeAvatar]),
 			Score:      parseInt(values[hashFieldLeetcodeScore]),
+
+			WeightedScore: parseInt(values[hashFieldLeetcodeWeighted]),
 		},
 		Lanqiao: PlatformProfile{
 			Identifier: strings.TrimSpace(values[hashFieldLanqiaoID]),
@@ -147,6 +192,13 @@ func ProjectionFromHash(userID uint, values map[string]string) (*UserProjection,
 			Score:      parseInt(values[hashFieldLanqiaoScore]),
 		},
 	}
+	for _, platform := range consts.OJProviderPlatforms {
+		out.setProvider(platform, PlatformProfile{
+			Identifier: strings.TrimSpace(values[platform+hashFieldProviderIDSuffix]),
+			Avatar:     strings.TrimSpace(values[platform+hashFieldProviderAvatarSuffix]),
+			Score:      parseInt(values[platform+hashFieldProviderScoreSuffix]),
+		})
+	}
 
 	// 解析 current_org_id 字段，确保正确处理空值和无效值。
 	if rawOrgID := strings.TrimSpace(values[hashFieldCurrentOrgID]); rawOrgID != "" {
@@ -155,6 +207,7 @@ func ProjectionFromHash(userID uint, values map[string]string) (*UserProjection,
 			out.CurrentOrgID = &orgID
 		}
 	}
+	out.refreshCombined()
 	return out, true
 }
 
@@ -167,28 +220,51 @@ func hasProjectionOrgFields(values map[string]string) bool {
 	return hasOrgID && hasOrgName
 }
 
-// Platform 返回指定平台的用户资料，默认为洛谷。
+// Platform 返回指定平台的用户资料，默认为洛谷；PlatformAll 返回综合画像。
 func (p *UserProjection) Platform(platform string) PlatformProfile {
-	switch NormalizePlatform(platform) {
+	switch platform = NormalizePlatform(platform); platform {
+	case PlatformAll:
+		return p.combinedPlatformProfile()
 	case PlatformLeetcode:
 		return p.Leetcode
 	case PlatformLanqiao:
 		return p.Lanqiao
-	default:
+	case PlatformLuogu:
 		return p.Luogu
+	default:
+		return p.Providers[platform]
+	}
+}
+
+// setProvider 写入扩展平台资料，未绑定（标识为空）的平台不占用 map 项。
+func (p *UserProjection) setProvider(platform string, profile PlatformProfile) {
+	if profile.Identifier == "" && profile.Score == 0 {
+		return
+	}
+	if p.Providers == nil {
+		p.Providers = make(map[string]PlatformProfile, len(consts.OJProviderPlatforms))
+	}
+	p.Providers[platform] = profile
+}
+
+// SupportsWeighted 判断平台是否维护难度加权排行榜。
+func SupportsWeighted(platform string) bool {
+	platform = strings.ToLower(strings.TrimSpace(platform))
+	for _, item := range WeightedPlatforms {
+		if item == platform {
+			return true
+		}
 	}
+	return false
 }
 
-// NormalizePlatform 将输入的平台标识规范化为预定义的常量值，默认为洛谷。
+// NormalizePlatform 将输入的平台标识规范化为受支持的平台或 PlatformAll，无法识别时默认为洛谷。
 func NormalizePlatform(platform string) string {
-	switch strings.ToLower(strings.TrimSpace(platform)) {
-	case PlatformLeetcode:
-		return PlatformLeetcode
-	case PlatformLanqiao:
-		return PlatformLanqiao
-	default:
-		return PlatformLuogu
+	platform = strings.ToLower(strings.TrimSpace(platform))
+	if platform == PlatformAll || consts.IsSupportedOJPlatform(platform) {
+		return platform
 	}
+	return PlatformLuogu
 }
 
 // parseInt 是一个辅助函数，用于将字符串解析为整数，解析失败时返回 0。
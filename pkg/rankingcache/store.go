@@ -62,7 +62,7 @@ func SyncProjectionRanks(
 	removeOrgIDs := dedupeOrgIDs(orgIDsToRemove, projection.CurrentOrgID)
 	pipe := client.Pipeline()
 	// 先从全站排行榜和相关组织排行榜中移除用户，确保旧排名被清除。
-	for _, platform := range []string{PlatformLuogu, PlatformLeetcode, PlatformLanqiao} {
+	for _, platform := range RankedPlatforms {
 		profile := projection.Platform(platform)
 		pipe.ZRem(ctx, rediskey.RankingAllMembersZSetKey(platform), member)
 		for _, orgID := range removeOrgIDs {
@@ -84,6 +84,29 @@ func SyncProjectionRanks(
 			})
 		}
 	}
+	// 加权榜与累计榜成员集合一致，只是分数换成加权分。
+	for _, platform := range WeightedPlatforms {
+		profile := projection.Platform(platform)
+		pipe.ZRem(ctx, rediskey.RankingAllMembersWeightedZSetKey(platform), member)
+		for _, orgID := range removeOrgIDs {
+			pipe.ZRem(ctx, rediskey.RankingOrgWeightedZSetKey(orgID, platform), member)
+		}
+		if !projection.Active || profile.Identifier == "" {
+			continue
+		}
+
+		score := float64(profile.WeightedScore)
+		pipe.ZAdd(ctx, rediskey.RankingAllMembersWeightedZSetKey(platform), &redis.Z{
+			Score:  score,
+			Member: member,
+		})
+		if projection.CurrentOrgID != nil && *projection.CurrentOrgID > 0 {
+			pipe.ZAdd(ctx, rediskey.RankingOrgWeightedZSetKey(*projection.CurrentOrgID, platform), &redis.Z{
+				Score:  score,
+				Member: member,
+			})
+		}
+	}
 
 	_, err := pipe.Exec(ctx)
 	return err
@@ -105,12 +128,18 @@ func DeleteProjection(
 	// 删除用户详情 hash，确保用户的详细信息不再可用。
 	pipe.Del(ctx, rediskey.RankingUserHashKey(userID))
 	// 从全站排行榜和相关组织排行榜中移除用户，确保用户不再出现在任何排行榜中。
-	for _, platform := range []string{PlatformLuogu, PlatformLeetcode, PlatformLanqiao} {
+	for _, platform := range RankedPlatforms {
 		pipe.ZRem(ctx, rediskey.RankingAllMembersZSetKey(platform), member)
 		for _, orgID := range dedupeOrgIDs(orgIDs, nil) {
 			pipe.ZRem(ctx, rediskey.RankingOrgZSetKey(orgID, platform), member)
 		}
 	}
+	for _, platform := range WeightedPlatforms {
+		pipe.ZRem(ctx, rediskey.RankingAllMembersWeightedZSetKey(platform), member)
+		for _, orgID := range dedupeOrgIDs(orgIDs, nil) {
+			pipe.ZRem(ctx, rediskey.RankingOrgWeightedZSetKey(orgID, platform), member)
+		}
+	}
 	_, err := pipe.Exec(ctx)
 	return err
 }
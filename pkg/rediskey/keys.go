@@ -10,6 +10,11 @@ const (
 	rankingAllMembersZSetKeyFmt = "ranking:all_members:%s"
 	rankingOrgZSetKeyFmt        = "ranking:org:%d:%s"
 	rankingUserHashKeyFmt       = "ranking:user:%d"
+	// 加权排行榜与累计榜共用 all_members / org 前缀，全量重建时按同一批 pattern 清理。
+	rankingAllMembersWeightedZSetKeyFmt = "ranking:all_members:weighted:%s"
+	rankingOrgWeightedZSetKeyFmt        = "ranking:org:%d:weighted:%s"
+	// 时间窗口排行榜：起止日期 + 对应累计榜 key，带 TTL 的按需投影。
+	rankingWindowZSetKeyFmt = "ranking:window:%s:%s:%s"
 	// 用户活跃态缓存 key。
 	userActiveStateKeyFmt = "user:active_state:%d"
 	lanqiaoSyncFailKeyFmt = "lanqiao:sync:fail:%d"
@@ -32,6 +37,21 @@ func RankingOrgZSetKey(orgID uint, platform string) string {
 	return fmt.Sprintf(rankingOrgZSetKeyFmt, orgID, platform)
 }
 
+// RankingAllMembersWeightedZSetKey 生成全站加权排行榜 zset key。
+func RankingAllMembersWeightedZSetKey(platform string) string {
+	return fmt.Sprintf(rankingAllMembersWeightedZSetKeyFmt, platform)
+}
+
+// RankingOrgWeightedZSetKey 生成组织加权排行榜 zset key。
+func RankingOrgWeightedZSetKey(orgID uint, platform string) string {
+	return fmt.Sprintf(rankingOrgWeightedZSetKeyFmt, orgID, platform)
+}
+
+// RankingWindowZSetKey 生成时间窗口排行榜 zset key，baseKey 为同范围的累计榜 key，日期格式为 20060102。
+func RankingWindowZSetKey(startDate, endDate, baseKey string) string {
+	return fmt.Sprintf(rankingWindowZSetKeyFmt, startDate, endDate, baseKey)
+}
+
 // RankingUserHashKey 生成用户详情 hash key。
 func RankingUserHashKey(userID uint) string {
 	return fmt.Sprintf(rankingUserHashKeyFmt, userID)
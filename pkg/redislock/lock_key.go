@@ -3,17 +3,19 @@ package redislock
 import "fmt"
 
 const (
-	LockKeyLuoguSyncAllUsers         = "luogu:sync:all_users"         // 洛谷全量同步锁
-	LockKeyLeetcodeSyncAllUsers      = "leetcode:sync:all_users"      // 力扣全量同步锁
-	LockKeyLanqiaoSyncAllUsers       = "lanqiao:sync:all_users"       // 蓝桥全量同步锁
-	lockKeyLuoguBindUserFmt          = "luogu:bind:user:%d"           // 洛谷按用户绑定锁
-	lockKeyLuoguBindIdentifierFmt    = "luogu:bind:identifier:%s"     // 洛谷按标识绑定锁
-	lockKeyLuoguUserSyncSingleFmt    = "luogu:sync:user:%s"           // 洛谷单用户同步锁
-	lockKeyLeetcodeUserSyncSingleFmt = "leetcode:sync:user:%s"        // 力扣单用户同步锁
-	lockKeyLanqiaoUserSyncSingleFmt  = "lanqiao:sync:user:%d"         // 蓝桥单用户同步锁
-	LockKeyLuoguProblemBankSyncKey   = "luogu:sync:problem_bank"      // 洛谷题库同步锁
-	LockKeyLuoguProblemBankWarmup    = "luogu:warmup:problem_bank"    // 洛谷题库预热锁
-	LockKeyLeetcodeProblemBankWarmup = "leetcode:warmup:problem_bank" // 力扣题库预热锁
+	LockKeyLuoguSyncAllUsers         = "luogu:sync:all_users"          // 洛谷全量同步锁
+	LockKeyLeetcodeSyncAllUsers      = "leetcode:sync:all_users"       // 力扣全量同步锁
+	LockKeyLanqiaoSyncAllUsers       = "lanqiao:sync:all_users"        // 蓝桥全量同步锁
+	lockKeyLuoguBindUserFmt          = "luogu:bind:user:%d"            // 洛谷按用户绑定锁
+	lockKeyLuoguBindIdentifierFmt    = "luogu:bind:identifier:%s"      // 洛谷按标识绑定锁
+	lockKeyLuoguUserSyncSingleFmt    = "luogu:sync:user:%s"            // 洛谷单用户同步锁
+	lockKeyLeetcodeUserSyncSingleFmt = "leetcode:sync:user:%s"         // 力扣单用户同步锁
+	lockKeyLanqiaoUserSyncSingleFmt  = "lanqiao:sync:user:%d"          // 蓝桥单用户同步锁
+	lockKeyOJProviderSyncAllFmt      = "oj_provider:%s:sync:all_users" // 扩展平台全量同步锁
+	lockKeyOJProviderSyncSingleFmt   = "oj_provider:%s:sync:user:%d"   // 扩展平台单用户同步锁
+	LockKeyLuoguProblemBankSyncKey   = "luogu:sync:problem_bank"       // 洛谷题库同步锁
+	LockKeyLuoguProblemBankWarmup    = "luogu:warmup:problem_bank"     // 洛谷题库预热锁
+	LockKeyLeetcodeProblemBankWarmup = "leetcode:warmup:problem_bank"  // 力扣题库预热锁
 
 	// Outbox Relay 调度锁，确保任何时候，只有一个outbox实例在运行
 	LockKeyOutboxRelayProcess = "outbox:relay:process"
@@ -42,6 +44,16 @@ func LockKeyLanqiaoSyncSingleUser(userID uint) string {
 	return fmt.Sprintf(lockKeyLanqiaoUserSyncSingleFmt, userID)
 }
 
+// LockKeyOJProviderSyncAllUsers 生成扩展 OJ 平台（Codeforces、AtCoder 等）全量同步锁 Key。
+func LockKeyOJProviderSyncAllUsers(platform string) string {
+	return fmt.Sprintf(lockKeyOJProviderSyncAllFmt, platform)
+}
+
+// LockKeyOJProviderSyncSingleUser 生成扩展 OJ 平台单用户同步锁 Key，绑定与定时同步共用。
+func LockKeyOJProviderSyncSingleUser(platform string, userID uint) string {
+	return fmt.Sprintf(lockKeyOJProviderSyncSingleFmt, platform, userID)
+}
+
 // LockKeyRolePermissionAssign 生成角色权限分配锁 Key。
 // 菜单权限与角色直绑 API 权限都会触发 Casbin 全量刷新，因此按 role 维度串行化。
 func LockKeyRolePermissionAssign(roleID uint) string {
@@ -0,0 +1,252 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	defaultHTTPTimeout = 10 * time.Second
+	maxResponseBytes   = 1 << 20
+)
+
+// Config 描述一个 OAuth2 / OIDC 授权服务器及本系统在其上注册的客户端。
+// Issuer 非空时按 OIDC 校验 id_token；UserInfoURL 非空时额外拉取用户信息补齐身份字段。
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+	Issuer       string
+	RedirectURL  string
+	Scopes       []string
+	HTTPClient   *http.Client
+}
+
+// Token 令牌端点的响应。
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+}
+
+// Identity 第三方返回的用户身份，Subject 在同一提供方内唯一且稳定。
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+}
+
+// RandomToken 生成 32 字节随机数的 base64url 编码，用作 state、nonce 与 PKCE code_verifier。
+func RandomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate oauth random token failed: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// S256Challenge 按 RFC 7636 计算 code_verifier 对应的 S256 code_challenge。
+func S256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL 拼装授权端点地址，固定使用授权码模式 + PKCE(S256)。
+func (c *Config) AuthCodeURL(state, codeChallenge, nonce string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", c.ClientID)
+	query.Set("redirect_uri", c.RedirectURL)
+	query.Set("state", state)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	if len(c.Scopes) > 0 {
+		query.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	if nonce != "" {
+		query.Set("nonce", nonce)
+	}
+	separator := "?"
+	if strings.Contains(c.AuthURL, "?") {
+		separator = "&"
+	}
+	return c.AuthURL + separator + query.Encode()
+}
+
+// Exchange 用授权码与 code_verifier 换取令牌。
+func (c *Config) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	form.Set("client_id", c.ClientID)
+	if c.ClientSecret != "" {
+		form.Set("client_secret", c.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("build token request failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token Token
+	if err := c.doJSON(req, &token); err != nil {
+		return nil, fmt.Errorf("exchange authorization code failed: %w", err)
+	}
+	if token.AccessToken == "" && token.IDToken == "" {
+		return nil, fmt.Errorf("token response has no access_token or id_token")
+	}
+	return &token, nil
+}
+
+// FetchIdentity 解析令牌得到用户身份。
+// id_token 由令牌端点经 TLS 直接返回，按 OIDC Core 3.1.3.7 以 TLS 代替签名校验，只校验 iss、aud、exp 与 nonce。
+func (c *Config) FetchIdentity(ctx context.Context, token *Token, nonce string) (*Identity, error) {
+	if token == nil {
+		return nil, fmt.Errorf("token is nil")
+	}
+	identity := &Identity{}
+	if token.IDToken != "" {
+		claims, err := c.verifyIDToken(token.IDToken, nonce, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		mergeClaims(identity, claims)
+	} else if c.Issuer != "" {
+		return nil, fmt.Errorf("token response has no id_token")
+	}
+
+	if c.UserInfoURL != "" && token.AccessToken != "" {
+		claims, err := c.fetchUserInfo(ctx, token.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+		subject := claimString(claims, "sub", "id")
+		if identity.Subject != "" && subject != "" && subject != identity.Subject {
+			return nil, fmt.Errorf("userinfo subject does not match id_token")
+		}
+		mergeClaims(identity, claims)
+	}
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("identity has no subject")
+	}
+	return identity, nil
+}
+
+func (c *Config) verifyIDToken(raw, nonce string, now time.Time) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(raw, claims); err != nil {
+		return nil, fmt.Errorf("parse id_token failed: %w", err)
+	}
+	if c.Issuer != "" && !claims.VerifyIssuer(c.Issuer, true) {
+		return nil, fmt.Errorf("id_token issuer mismatch")
+	}
+	if !claims.VerifyAudience(c.ClientID, true) {
+		return nil, fmt.Errorf("id_token audience mismatch")
+	}
+	if !claims.VerifyExpiresAt(now.Unix(), true) {
+		return nil, fmt.Errorf("id_token expired")
+	}
+	if nonce != "" && claimString(claims, "nonce") != nonce {
+		return nil, fmt.Errorf("id_token nonce mismatch")
+	}
+	return claims, nil
+}
+
+func (c *Config) fetchUserInfo(ctx context.Context, accessToken string) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.UserInfoURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build userinfo request failed: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	claims := map[string]any{}
+	if err := c.doJSON(req, &claims); err != nil {
+		return nil, fmt.Errorf("fetch userinfo failed: %w", err)
+	}
+	return claims, nil
+}
+
+func (c *Config) doJSON(req *http.Request, out any) error {
+	client := c.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseBytes))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, out)
+}
+
+// mergeClaims 只补齐尚未取得的字段，兼容 OIDC 标准声明与 GitHub 等纯 OAuth2 提供方的常见字段名。
+func mergeClaims(identity *Identity, claims map[string]any) {
+	if identity.Subject == "" {
+		identity.Subject = claimString(claims, "sub", "id")
+	}
+	if identity.Email == "" {
+		identity.Email = strings.TrimSpace(claimString(claims, "email"))
+		identity.EmailVerified = claimBool(claims, "email_verified")
+	}
+	if identity.Name == "" {
+		identity.Name = claimString(claims, "name", "preferred_username", "nickname", "login")
+	}
+	if identity.Picture == "" {
+		identity.Picture = claimString(claims, "picture", "avatar_url")
+	}
+}
+
+func claimString(claims map[string]any, keys ...string) string {
+	for _, key := range keys {
+		switch value := claims[key].(type) {
+		case string:
+			if value != "" {
+				return value
+			}
+		case float64:
+			return strconv.FormatFloat(value, 'f', -1, 64)
+		case json.Number:
+			return value.String()
+		}
+	}
+	return ""
+}
+
+func claimBool(claims map[string]any, key string) bool {
+	switch value := claims[key].(type) {
+	case bool:
+		return value
+	case string:
+		parsed, _ := strconv.ParseBool(value)
+		return parsed
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package oidc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"personal_assistant/pkg/security/oidc"
+	"personal_assistant/pkg/security/oidc/oidctest"
+)
+
+func TestAuthorizationCodeFlowWithPKCE(t *testing.T) {
+	server := oidctest.NewServer("client", "secret")
+	defer server.Close()
+	server.SetUser(oidctest.User{Subject: "u-1", Email: "alice@example.com", EmailVerified: true, Name: "Alice"})
+	cfg := server.Config("http://localhost/callback")
+	ctx := context.Background()
+
+	verifier, err := oidc.RandomToken()
+	if err != nil {
+		t.Fatalf("RandomToken() error = %v", err)
+	}
+	authURL := cfg.AuthCodeURL("state-1", oidc.S256Challenge(verifier), "nonce-1")
+	if !strings.Contains(authURL, "code_challenge_method=S256") || !strings.Contains(authURL, "scope=openid+email+profile") {
+		t.Fatalf("unexpected authorize url: %s", authURL)
+	}
+	code, state, err := server.Authorize(authURL)
+	if err != nil || state != "state-1" {
+		t.Fatalf("Authorize() = %q, %q, %v", code, state, err)
+	}
+
+	token, err := cfg.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	identity, err := cfg.FetchIdentity(ctx, token, "nonce-1")
+	if err != nil {
+		t.Fatalf("FetchIdentity() error = %v", err)
+	}
+	if identity.Subject != "u-1" || identity.Email != "alice@example.com" || !identity.EmailVerified || identity.Name != "Alice" {
+		t.Fatalf("unexpected identity: %+v", identity)
+	}
+	if _, err := cfg.FetchIdentity(ctx, token, "other-nonce"); err == nil {
+		t.Fatal("expected nonce mismatch to be rejected")
+	}
+	// 授权码只能兑换一次
+	if _, err := cfg.Exchange(ctx, code, verifier); err == nil {
+		t.Fatal("expected used code to be rejected")
+	}
+}
+
+func TestExchangeRejectsWrongCodeVerifier(t *testing.T) {
+	server := oidctest.NewServer("client", "secret")
+	defer server.Close()
+	server.SetUser(oidctest.User{Subject: "u-1"})
+	cfg := server.Config("http://localhost/callback")
+
+	verifier, _ := oidc.RandomToken()
+	code, _, err := server.Authorize(cfg.AuthCodeURL("state", oidc.S256Challenge(verifier), ""))
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	if _, err := cfg.Exchange(context.Background(), code, "wrong-verifier"); err == nil {
+		t.Fatal("expected wrong code_verifier to be rejected")
+	}
+}
@@ -0,0 +1,69 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录 B 的 SHA1 测试密钥 "12345678901234567890"。
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCodeAtMatchesRFC6238Vectors(t *testing.T) {
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+	for _, tc := range cases {
+		got, err := CodeAt(rfcSecret, Step(time.Unix(tc.unix, 0)))
+		if err != nil {
+			t.Fatalf("CodeAt(%d) error = %v", tc.unix, err)
+		}
+		if got != tc.want {
+			t.Fatalf("CodeAt(%d) = %s, want %s", tc.unix, got, tc.want)
+		}
+	}
+}
+
+func TestValidateAllowsSkewAndReturnsStep(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	previous, err := CodeAt(rfcSecret, Step(now)-1)
+	if err != nil {
+		t.Fatalf("CodeAt() error = %v", err)
+	}
+
+	step, ok := Validate(rfcSecret, previous, now, 1)
+	if !ok || step != Step(now)-1 {
+		t.Fatalf("Validate() = %d, %v; want previous step", step, ok)
+	}
+	if _, ok := Validate(rfcSecret, previous, now, 0); ok {
+		t.Fatal("expected previous step to be rejected without skew")
+	}
+	if _, ok := Validate(rfcSecret, "12345", now, 1); ok {
+		t.Fatal("expected short code to be rejected")
+	}
+}
+
+func TestGenerateSecretAndProvisioningURI(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+	if _, err := CodeAt(secret, 1); err != nil {
+		t.Fatalf("generated secret is not usable: %v", err)
+	}
+
+	uri := ProvisioningURI("Personal Assistant", "alice@example.com", secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/Personal%20Assistant:alice@example.com?") {
+		t.Fatalf("unexpected uri: %s", uri)
+	}
+	if !strings.Contains(uri, "secret="+secret) || !strings.Contains(uri, "issuer=Personal+Assistant") {
+		t.Fatalf("uri missing secret or issuer: %s", uri)
+	}
+}
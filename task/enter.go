@@ -110,6 +110,13 @@ func LanqiaoSyncTask() {
 	})
 }
 
+// OJProviderSyncTask Codeforces、AtCoder 等扩展平台用户数据定时增量同步。
+func OJProviderSyncTask() {
+	runServiceTask("OJProviderSyncTask", func(ctx context.Context) error {
+		return service.GroupApp.SystemServiceSupplier.GetOJSvc().SyncAllProviderUsers(ctx)
+	})
+}
+
 // LanqiaoStatsRefreshTask 蓝桥提交成功/失败次数低频刷新。
 func LanqiaoStatsRefreshTask() {
 	runServiceTask("LanqiaoStatsRefreshTask", func(ctx context.Context) error {
@@ -142,6 +149,20 @@ func OJTaskDispatchTask() {
 	})
 }
 
+// OJContestPollTask OJ 比赛轮询：推进比赛状态、同步参赛者并推送实时榜单，结束后冻结最终榜单。
+func OJContestPollTask() {
+	runServiceTask("OJContestPollTask", func(ctx context.Context) error {
+		return service.GroupApp.SystemServiceSupplier.GetOJContestSvc().PollContests(ctx)
+	})
+}
+
+// OJTaskDeadlineTask OJ 任务截止跟踪：截止前复评未完成题目并提醒，截止后冻结按时结果并标记逾期完成。
+func OJTaskDeadlineTask() {
+	runServiceTask("OJTaskDeadlineTask", func(ctx context.Context) error {
+		return service.GroupApp.SystemServiceSupplier.GetOJTaskSvc().TrackExecutionDeadlines(ctx)
+	})
+}
+
 // ImageOrphanCleanupTask 孤儿图片定时清理。
 // 查找已软删除且无活跃引用的存储 key，删除对应物理文件后清除 DB 记录。
 func ImageOrphanCleanupTask() {
@@ -158,6 +179,14 @@ func DisabledUserCleanupTask() {
 	})
 }
 
+// AIMemoryReindexTask 长期记忆向量索引补偿。
+// 每轮只处理一批未索引、已过期或 embedding 空间不一致的 documents，切换 provider 后逐步完成重建。
+func AIMemoryReindexTask() {
+	runServiceTask("AIMemoryReindexTask", func(ctx context.Context) error {
+		return service.GroupApp.SystemServiceSupplier.GetAIMemoryIndexSvc().IndexPendingDocuments(ctx, 0)
+	})
+}
+
 // RegisterScheduledTasks 注册所有定时任务到 cron 调度器。
 func RegisterScheduledTasks(c *cron.Cron) error {
 	// Outbox 清理 — 每天一次
@@ -187,6 +216,14 @@ func RegisterScheduledTasks(c *cron.Cron) error {
 		return fmt.Errorf("注册 LanqiaoSyncTask 失败: %w", err)
 	}
 
+	providerInterval := global.Config.Task.OJProviderSyncIntervalSeconds
+	if providerInterval <= 0 {
+		providerInterval = 3600
+	}
+	if _, err := c.AddFunc(fmt.Sprintf("@every %ds", providerInterval), OJProviderSyncTask); err != nil {
+		return fmt.Errorf("注册 OJProviderSyncTask 失败: %w", err)
+	}
+
 	lanqiaoStatsCron := strings.TrimSpace(global.Config.Task.LanqiaoStatsRefreshCron)
 	if lanqiaoStatsCron == "" {
 		lanqiaoStatsCron = "@daily"
@@ -222,6 +259,26 @@ func RegisterScheduledTasks(c *cron.Cron) error {
 		}
 	}
 
+	if global.Config.Task.OJTaskDeadlineEnabled {
+		deadlineInterval := global.Config.Task.OJTaskDeadlineIntervalSeconds
+		if deadlineInterval <= 0 {
+			deadlineInterval = 600
+		}
+		if _, err := c.AddFunc(fmt.Sprintf("@every %ds", deadlineInterval), OJTaskDeadlineTask); err != nil {
+			return fmt.Errorf("注册 OJTaskDeadlineTask 失败: %w", err)
+		}
+	}
+
+	if global.Config.Task.OJContestPollEnabled {
+		pollInterval := global.Config.Task.OJContestPollIntervalSeconds
+		if pollInterval <= 0 {
+			pollInterval = 60
+		}
+		if _, err := c.AddFunc(fmt.Sprintf("@every %ds", pollInterval), OJContestPollTask); err != nil {
+			return fmt.Errorf("注册 OJContestPollTask 失败: %w", err)
+		}
+	}
+
 	// 孤儿图片清理 — cron 表达式由配置驱动
 	orphanCron := global.Config.Task.ImageOrphanCleanupCron
 	if orphanCron == "" {
@@ -241,6 +298,15 @@ func RegisterScheduledTasks(c *cron.Cron) error {
 		}
 	}
 
+	// AI 记忆向量补偿/重建 — 间隔由配置驱动；记忆模块关闭时 IndexPendingDocuments 直接返回
+	reindexInterval := global.Config.Task.AIMemoryReindexIntervalSeconds
+	if reindexInterval <= 0 {
+		reindexInterval = 60
+	}
+	if _, err := c.AddFunc(fmt.Sprintf("@every %ds", reindexInterval), AIMemoryReindexTask); err != nil {
+		return fmt.Errorf("注册 AIMemoryReindexTask 失败: %w", err)
+	}
+
 	rollupCron := global.Config.Observability.Metrics.RollupCron
 	if rollupCron == "" {
 		rollupCron = "10 2 * * *"